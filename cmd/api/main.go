@@ -2,32 +2,63 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+	// Embedded so digest time zones work on hosts without a zoneinfo database
+	_ "time/tzdata"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/gorilla/mux"
 	_ "github.com/lib/pq"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 
+	"sample/task-management-system/internal/setup"
 	"sample/task-management-system/pkg/api"
+	"sample/task-management-system/pkg/audit"
 	"sample/task-management-system/pkg/auth"
 	"sample/task-management-system/pkg/middleware"
-	"sample/task-management-system/pkg/repository/postgres"
+	"sample/task-management-system/pkg/repository/memory"
+	"sample/task-management-system/pkg/repository/sqlite"
 	"sample/task-management-system/pkg/service"
 	"sample/task-management-system/pkg/cache"
+	"sample/task-management-system/pkg/challenge"
+	"sample/task-management-system/pkg/doctor"
+	"sample/task-management-system/pkg/events"
 	"sample/task-management-system/pkg/health"
 	"sample/task-management-system/pkg/metrics"
+	"sample/task-management-system/pkg/models"
 	"sample/task-management-system/pkg/monitoring"
+	"sample/task-management-system/pkg/notify"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/routing"
+	"sample/task-management-system/pkg/security"
+	"sample/task-management-system/pkg/share"
+	"sample/task-management-system/pkg/storage"
 )
 
 func main() {
+	selfCheck := flag.Bool("self-check", false, "Validate configuration and connectivity, print a report and exit")
+	devMode := flag.Bool("dev", false, "Serve the API from a local SQLite database with no external services, for local development")
+	flag.Parse()
+
 	// Enable verbose logging
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
+
+	if *selfCheck {
+		os.Exit(runSelfCheck())
+	}
 	
 	// Initialize metrics if enabled
 	if err := metrics.Initialize(); err != nil {
@@ -35,12 +66,25 @@ func main() {
 	}
 	
 	// Load configuration from environment variables
-	dbHost := getEnv("DB_HOST", "localhost")
-	dbPort := getEnv("DB_PORT", "5432")
-	dbUser := getEnv("DB_USER", "postgres")
-	dbPass := getEnv("DB_PASSWORD", "postgres")
-	dbName := getEnv("DB_NAME", "taskdb")
 	serverPort := getEnv("SERVER_PORT", "8080")
+
+	// Sandbox mode serves fixture tasks from memory and needs none of the configuration below
+	sandboxMode, err := strconv.ParseBool(getEnv("SANDBOX_MODE", "false"))
+	if err != nil {
+		log.Fatalf("Invalid SANDBOX_MODE: %v", err)
+	}
+	if sandboxMode {
+		runSandbox(serverPort)
+		return
+	}
+	// Dev mode serves the API from a local SQLite database with Redis in process, so it
+	// needs no external services
+	var devDB *sql.DB
+	if *devMode {
+		var stopRedis func()
+		devDB, stopRedis = startDev()
+		defer stopRedis()
+	}
 	
 	// Auth configuration
 	authSecret := []byte(getEnv("AUTH_SECRET", ""))
@@ -50,6 +94,58 @@ func main() {
 		log.Fatal("AUTH_SECRET and AUTH_ISSUER must be set")
 	}
 
+	// Token validation: issuer and audience are always required
+	jwtLeeway, err := time.ParseDuration(getEnv("JWT_LEEWAY", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid JWT_LEEWAY: %v", err)
+	}
+	tokenValidation := auth.ValidationOptions{
+		Issuer:            authIssuer,
+		Audience:          getEnv("AUTH_AUDIENCE", "task-management-api"),
+		AllowedAlgorithms: strings.Split(getEnv("JWT_ALLOWED_ALGORITHMS", "HS256"), ","),
+		Leeway:            jwtLeeway,
+	}
+
+	// Tokens may be verified with public keys, e.g. an identity provider's, and issued with a
+	// private key instead of AUTH_SECRET
+	jwtPublicKeys, err := auth.LoadPublicKeys(os.Getenv("JWT_PUBLIC_KEYS"))
+	if err != nil {
+		log.Fatalf("Invalid JWT_PUBLIC_KEYS: %v", err)
+	}
+	tokenOptions := []auth.TokenManagerOption{
+		auth.WithAudience(tokenValidation.Audience),
+		auth.WithLeeway(tokenValidation.Leeway),
+		auth.WithAllowedAlgorithms(tokenValidation.AllowedAlgorithms...),
+	}
+	if path := os.Getenv("JWT_SIGNING_KEY_FILE"); path != "" {
+		signingKey, err := loadSigningKey(path, getEnv("JWT_SIGNING_KEY_ID", ""), tokenValidation.AllowedAlgorithms)
+		if err != nil {
+			log.Fatalf("Invalid JWT_SIGNING_KEY_FILE: %v", err)
+		}
+		jwtPublicKeys[signingKey.ID] = signingKey.Key.Public()
+		tokenOptions = append(tokenOptions, auth.WithSigningKey(signingKey))
+	}
+	if *devMode {
+		// The printed dev token lasts a working day
+		tokenOptions = append(tokenOptions, auth.WithAccessExpiry(24*time.Hour))
+	}
+
+	// Destructive operations require authentication within this window
+	stepUpMaxAge, err := time.ParseDuration(getEnv("STEP_UP_MAX_AGE", "10m"))
+	if err != nil {
+		log.Fatalf("Invalid STEP_UP_MAX_AGE: %v", err)
+	}
+
+	// Maintenance mode answers every request but health checks with 503
+	maintenanceMode, err := strconv.ParseBool(getEnv("MAINTENANCE_MODE", "false"))
+	if err != nil {
+		log.Fatalf("Invalid MAINTENANCE_MODE: %v", err)
+	}
+	maintenanceRetryAfter, err := time.ParseDuration(getEnv("MAINTENANCE_RETRY_AFTER", "5m"))
+	if err != nil {
+		log.Fatalf("Invalid MAINTENANCE_RETRY_AFTER: %v", err)
+	}
+
 	// Initialize AWS CloudWatch client and alarm service if monitoring is enabled
 	var serviceMonitor *monitoring.ServiceMonitor
 	if os.Getenv("ENABLE_METRICS") == "true" {
@@ -83,29 +179,96 @@ func main() {
 		}
 	}
 
-	log.Printf("Connecting to database: host=%s port=%s user=%s dbname=%s", dbHost, dbPort, dbUser, dbName)
+	db := devDB
+	var repos *setup.Repositories
+	if *devMode {
+		repos = setup.SQLiteRepositories(db)
+	} else {
+		db, err = setup.Database()
+		if err != nil {
+			log.Fatal(err)
+		}
+		repos = setup.PostgresRepositories(db)
+	}
+	defer db.Close()
 
-	// Create database URL
-	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		dbUser, dbPass, dbHost, dbPort, dbName)
+	// Initialize Redis cache
+	redisCache, err := setup.Redis()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Task writes are published on the event bus, e.g. to keep badge counts current
+	eventBus := events.NewBus(1024)
+	taskRepo, taskSearcher := setup.TaskRepository(repos, eventBus)
 
-	// Connect to the database
-	db, err := sql.Open("postgres", dbURL)
+	moderationRepo := repos.Moderation
+	moderator, err := setup.Moderator(moderationRepo)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		log.Fatalf("Failed to configure content moderation: %v", err)
 	}
-	defer db.Close()
 
-	// Test the database connection
-	if err := db.Ping(); err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
+	dependencyRepo := repos.Dependencies
+	starRepo := repos.Stars
+	relationRepo := repos.Relations
+	approvalRepo := repos.Approvals
+	taskService := service.NewStarredTaskService(
+		service.NewSLATaskService(service.NewRelatedTaskService(
+			service.NewBlockedTaskService(
+				service.NewApprovalTaskService(
+					service.NewModeratedTaskService(service.NewTaskService(taskRepo), moderator),
+					approvalRepo,
+				),
+				dependencyRepo,
+			),
+			relationRepo,
+		)),
+		starRepo,
+	)
+	moderationHandler := api.NewModerationHandler(service.NewModerationService(moderationRepo, taskRepo))
+
+	// Logged-out tokens are revoked in Redis until they expire, so every replica rejects them.
+	// Refreshed and impersonation tokens carry the user's stored roles, and every user
+	// token is scoped to the organization the user joined last.
+	revocations := auth.NewRevocationList(redisCache)
+	userRepo := repos.Users
+	invitationRepo := repos.Invitations
+	tokenManager := auth.NewTokenManager(authSecret, authIssuer, append(tokenOptions,
+		auth.WithRevocationList(revocations), auth.WithUserRoles(userRepo.GetRoles),
+		auth.WithUserOrgs(service.MembershipLookup(invitationRepo)))...)
+	var devToken string
+	if *devMode {
+		tokens, err := tokenManager.CreateTokenPair(context.Background(), devAdminID, []string{"admin"})
+		if err != nil {
+			log.Fatalf("Failed to create the dev token: %v", err)
+		}
+		devToken = tokens.AccessToken
+	}
+	auditRepo := repos.Audit
+	securityDetector, err := newSecurityDetector(audit.NewLogger(auditRepo), cache.NewSecurityStore(redisCache))
+	if err != nil {
+		log.Fatalf("Failed to configure security detector: %v", err)
 	}
-	log.Println("Successfully connected to database")
+	auditLogger := audit.Logger(securityDetector)
+	securityHandler := api.NewSecurityHandler(service.NewSecurityService(auditRepo))
+	impersonationHandler := api.NewImpersonationHandler(tokenManager, auditLogger)
+	oauthHandler := api.NewOAuthHandler(service.NewOAuthService(repos.OAuthClients, tokenManager))
+	searchHandler := api.NewSearchHandler(service.NewSearchService(taskRepo, taskSearcher))
+	ipAllowlistService := service.NewIPAllowlistService(repos.IPAllowlists)
+	ipAllowlistHandler := api.NewIPAllowlistHandler(ipAllowlistService)
+	clientIPResolver, err := middleware.NewClientIPResolver(strings.Split(os.Getenv("TRUSTED_PROXIES"), ","))
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+	shareHandler := api.NewShareHandler(
+		service.NewShareService(repos.Shares, taskRepo, share.NewSigner(authSecret, authIssuer)),
+		getEnv("SHARE_BASE_URL", "http://localhost:"+serverPort),
+	)
 
-	// Initialize dependencies
-	taskRepo := postgres.NewTaskRepository(db)
-	taskService := service.NewTaskService(taskRepo)
-	taskHandler := api.NewTaskHandler(taskService)
+	attachmentHandler, err := newAttachmentHandler(repos.Attachments, taskRepo)
+	if err != nil {
+		log.Fatalf("Failed to configure attachments: %v", err)
+	}
 
 	// Set up the router
 	router := mux.NewRouter()
@@ -113,58 +276,570 @@ func main() {
 	// Configure auth middleware
 	authConfig := auth.AuthConfig{
 		JWTSecret:    authSecret,
+		PublicKeys:   jwtPublicKeys,
+		Validation:   tokenValidation,
 		AllowedRoles: auth.DefaultRoles,
-		PublicPaths:  []string{"/health"},
+		PublicRoutes: []auth.PublicRoute{
+			{Methods: []string{http.MethodGet}, Pattern: "/health"},
+			{Methods: []string{http.MethodGet}, Pattern: "/api/v1/challenge"},
+			{Pattern: "/api/v1/account/**"},
+			{Methods: []string{http.MethodPost}, Pattern: "/oauth/token"},
+			{Methods: []string{http.MethodPost}, Pattern: "/api/v1/auth/refresh"},
+			{Methods: []string{http.MethodGet}, Pattern: "/api/v1/auth/oidc/**"},
+			{Methods: []string{http.MethodGet}, Pattern: "/api/v1/shared/{token}"},
+			{Methods: []string{http.MethodGet}, Pattern: "/api/v1/announcements"},
+			{Methods: []string{http.MethodGet, http.MethodPost}, Pattern: "/api/v1/users/preferences/unsubscribe"},
+		},
+	}
+	// Tokens of an identity provider are verified with the keys it publishes at JWKS_URL
+	if jwksURL := os.Getenv("JWKS_URL"); jwksURL != "" {
+		jwksRefresh, err := time.ParseDuration(getEnv("JWKS_REFRESH_INTERVAL", "1h"))
+		if err != nil {
+			log.Fatalf("Invalid JWKS_REFRESH_INTERVAL: %v", err)
+		}
+		jwks, err := auth.NewJWKS(context.Background(), jwksURL, &http.Client{Timeout: 10 * time.Second})
+		if err != nil {
+			log.Fatalf("Failed to fetch JWKS from %s: %v", jwksURL, err)
+		}
+		go jwks.Run(context.Background(), jwksRefresh)
+		authConfig.JWKS = jwks
+	}
+	// Roles are the built-in ones replaced or extended by those edited with the admin roles
+	// API, reloaded every ROLE_RELOAD_INTERVAL so every instance picks up changes
+	roleReload, err := time.ParseDuration(getEnv("ROLE_RELOAD_INTERVAL", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid ROLE_RELOAD_INTERVAL: %v", err)
+	}
+	roleRepo := repos.Roles
+	roleCache, err := auth.NewRoleCache(context.Background(), service.NewRoleSource(roleRepo, auth.DefaultRoles), roleReload)
+	if err != nil {
+		log.Fatalf("Failed to load roles: %v", err)
+	}
+	go roleCache.Run(context.Background())
+	authConfig.RoleCache = roleCache
+	tokenCacheSize, err := strconv.Atoi(getEnv("TOKEN_CACHE_SIZE", "10000"))
+	if err != nil {
+		log.Fatalf("Invalid TOKEN_CACHE_SIZE: %v", err)
+	}
+	if tokenCacheSize > 0 {
+		authConfig.TokenCache = auth.NewMemoryTokenCache(tokenCacheSize)
+	}
+	authConfig.Revocations = revocations
+	authConfig.OnAuthEvent = security.AuthEventHook(auditLogger, securityDetector, clientIPResolver.ClientIP, os.Getenv("GEO_COUNTRY_HEADER"))
+	guestLinks, err := auth.ParseGuestLinks(os.Getenv("GUEST_LINKS"))
+	if err != nil {
+		log.Fatalf("Invalid GUEST_LINKS: %v", err)
+	}
+	if len(guestLinks) > 0 {
+		authConfig.GuestAccess = &auth.GuestAccess{
+			Links: guestLinks,
+			Routes: []auth.PublicRoute{
+				{Pattern: "/api/v1/tasks"},
+				{Pattern: "/api/v1/tasks/search"},
+				{Pattern: "/api/v1/tasks/{id}"},
+				{Pattern: "/api/v1/tasks/{id}/related"},
+				{Pattern: "/api/v1/tasks/{id}/subtasks"},
+			},
+		}
+		log.Printf("Guest link access enabled for %d link(s)", len(guestLinks))
+	}
+	if probeToken := os.Getenv("HEALTH_PROBE_TOKEN"); probeToken != "" {
+		authConfig.ProbeAccess = &auth.ProbeAccess{
+			Token:  probeToken,
+			Routes: []auth.PublicRoute{{Pattern: "/health/details"}},
+		}
 	}
 
-	// Add global middleware
-	router.Use(middleware.LoggingMiddleware)
-	router.Use(middleware.NewSafetyLimiter().Limit)
-	router.Use(auth.AuthMiddleware(authConfig))
-	
-	// Initialize Redis cache
-	log.Printf("Connecting to Redis at %s", os.Getenv("REDIS_ADDR"))
-	redisCache, err := cache.NewRedisCache(
-		os.Getenv("REDIS_ADDR"),
-		os.Getenv("REDIS_PASSWORD"),
-		0,
+	// Add global middleware; stages run in their fixed order whatever order they are added in
+	chain := &routing.Chain{}
+	chain.Use(routing.Observe, middleware.GoldenSignalsMiddleware, middleware.LoggingMiddleware)
+	if maintenanceMode {
+		log.Printf("Maintenance mode enabled; requests are answered with 503")
+		chain.Use(routing.Guard, middleware.MaintenanceMiddleware(maintenanceRetryAfter))
+	}
+	chain.Use(routing.Guard, middleware.NewSafetyLimiter().Limit)
+	// API keys are verified first; AuthMiddleware then checks their roles like a token's
+	apiKeyService := service.NewAPIKeyService(repos.APIKeys)
+	chain.Use(routing.Auth, auth.APIKeyMiddleware(apiKeyService), auth.AuthMiddleware(authConfig))
+	// Runs after authentication, so callers are limited per user and see their remaining
+	// requests on every response
+	rateLimiter, err := setup.RateLimiter()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if rateLimiter != nil {
+		chain.Use(routing.RateLimit, rateLimiter.RateLimit)
+	}
+	chain.Use(routing.Policy,
+		middleware.IPAllowlistMiddleware(ipAllowlistService, clientIPResolver, auditLogger),
+		middleware.AuditMiddleware(auditLogger),
 	)
+	router.Use(chain.Middleware())
+
+	// Badge counts are maintained in Redis from task events instead of COUNT queries
+	badgeCounter := cache.NewBadgeCounter(redisCache)
+	badgeService := service.NewBadgeService(badgeCounter, taskRepo)
+	if err := badgeService.EnsureBuilt(context.Background()); err != nil {
+		log.Printf("Warning: failed to build task badge counts: %v", err)
+	}
+	eventBus.Subscribe("badges", badgeCounter.Apply)
+	// Task status history for burnup and burndown charts
+	progressService := service.NewProgressService(repos.Progress, redisCache)
+	eventBus.Subscribe("progress", progressService.Record)
+	// Task events are posted to webhook subscribers, signed with each subscription's secret
+	webhookSender, err := setup.WebhookSender()
+	if err != nil {
+		log.Fatal(err)
+	}
+	webhookService := service.NewWebhookService(repos.Webhooks, webhookSender)
+	// Every task event is archived, so time ranges can be replayed to rebuild webhook receivers
+	eventArchive, err := setup.EventArchive(repos.EventArchive, webhookService)
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventBus.Subscribe("archive", eventArchive.Record)
+	eventBus.Subscribe("webhooks", webhookService.Dispatch)
+	go eventBus.Run(context.Background())
+
+	// Recently viewed tasks are recorded in Redis and flushed to Postgres in the background
+	recentTaskService := service.NewRecentTaskService(
+		cache.NewRecentViews(redisCache, service.MaxRecentTasks),
+		repos.Views,
+		taskRepo,
+	)
+	taskService = service.NewViewRecordingTaskService(taskService, recentTaskService)
+
+	// Usage analytics events are buffered in memory and stored in the background
+	telemetrySampleRate, err := metrics.ParseSampleRate(getEnv("TELEMETRY_SAMPLE_RATE", "1.0"))
+	if err != nil {
+		log.Fatalf("Invalid TELEMETRY_SAMPLE_RATE: %v", err)
+	}
+	telemetryService := service.NewTelemetryService(repos.Telemetry, telemetrySampleRate, 10000)
+	go telemetryService.Run(context.Background(), 10*time.Second)
+	telemetryHandler := api.NewTelemetryHandler(telemetryService)
+
+	// Deadline digests are emailed at each user's chosen local hour
+	mailSender, err := setup.MailSender()
+	if err != nil {
+		log.Fatalf("Failed to configure email: %v", err)
+	}
+	preferencesRepo := repos.Preferences
+	unsubscribeSigner := notify.NewUnsubscribeSigner(authSecret, authIssuer)
+	preferencesService := service.NewPreferencesService(preferencesRepo, unsubscribeSigner)
+	preferencesHandler := api.NewPreferencesHandler(preferencesService)
+	digestService := service.NewDigestService(preferencesRepo, repos.Digests, mailSender, unsubscribeSigner,
+		getEnv("PUBLIC_BASE_URL", "http://localhost:"+serverPort))
+
+	// Organization admins invite users by email; accepting an invitation makes the user a member
+	invitationHandler := api.NewInvitationHandler(service.NewInvitationService(invitationRepo, mailSender,
+		getEnv("PUBLIC_BASE_URL", "http://localhost:"+serverPort)))
+
+	// Open tasks without updates are flagged as stale after a per-project number of days
+	staleTaskDays, err := strconv.Atoi(getEnv("STALE_TASK_DAYS", "14"))
+	if err != nil {
+		log.Fatalf("Invalid STALE_TASK_DAYS: %v", err)
+	}
+	staleTaskService := service.NewStaleTaskService(repos.StaleTasks, preferencesRepo, eventBus, mailSender, staleTaskDays)
+
+	// Requests to deprecated endpoints, parameters and versions are counted per client, and
+	// their users told DEPRECATION_NOTICE_DAYS before the sunset date
+	deprecations, err := setup.Deprecations()
+	if err != nil {
+		log.Fatal(err)
+	}
+	deprecationNoticeDays, err := strconv.Atoi(getEnv("DEPRECATION_NOTICE_DAYS", "30"))
+	if err != nil || deprecationNoticeDays < 0 {
+		log.Fatalf("Invalid DEPRECATION_NOTICE_DAYS: %q", os.Getenv("DEPRECATION_NOTICE_DAYS"))
+	}
+	deprecationService := service.NewDeprecationService(repos.Deprecations, deprecations,
+		time.Duration(deprecationNoticeDays)*24*time.Hour, preferencesRepo, mailSender, webhookService)
+	go deprecationService.Run(context.Background(), time.Minute)
+	chain.Use(routing.Policy, middleware.DeprecationMiddleware(deprecations, deprecationService))
+
+	// Open tasks past their due date are flagged and counted for dashboards and alarms
+	overdueTaskService := service.NewOverdueTaskService(repos.OverdueTasks, taskService, eventBus)
+
+	// Tasks with an SLA policy are checked for missed response and resolution targets
+	slaService := service.NewSLAService(repos.SLAs)
+
+	// Task lists hint at their first tasks' detail URLs, which the cache warms in the background
+	prefetchHints, err := strconv.Atoi(getEnv("PREFETCH_HINTS", "5"))
+	if err != nil || prefetchHints < 0 {
+		log.Fatalf("Invalid PREFETCH_HINTS: must be a non-negative number")
+	}
+	// Streamed exports give up their database cursor after EXPORT_MAX_DURATION, or once the
+	// client kept them waiting EXPORT_MAX_STALL in total, and hand back a cursor to resume from
+	exportMaxDuration, err := time.ParseDuration(getEnv("EXPORT_MAX_DURATION", "2m"))
+	if err != nil {
+		log.Fatalf("Invalid EXPORT_MAX_DURATION: %v", err)
+	}
+	exportMaxStall, err := time.ParseDuration(getEnv("EXPORT_MAX_STALL", "30s"))
 	if err != nil {
-		log.Fatalf("Failed to initialize Redis cache: %v", err)
+		log.Fatalf("Invalid EXPORT_MAX_STALL: %v", err)
 	}
-	log.Println("Successfully connected to Redis")
+	exportPacing := models.ExportPacing{MaxDuration: exportMaxDuration, MaxStall: exportMaxStall}
+	taskHandler := api.NewTaskHandler(taskService).WithPrefetchHints(prefetchHints).WithExportPacing(exportPacing).
+		WithPreferences(preferencesService)
 
-	// Create middleware instances
-	cacheMiddleware := middleware.NewCacheMiddleware(redisCache, 5*time.Minute)
+	// Create middleware instances. The cache runs inside the router, after authentication,
+	// so a cached response is only served to callers that may read it; batched sub-requests
+	// are cached one by one, so the batch itself passes through.
+	cacheMiddleware := middleware.NewCacheMiddleware(redisCache, 5*time.Minute).WithPrefetchHandler(router)
+	chain.Use(routing.Cache, cacheMiddleware.CacheHandler)
+	chain.Override(models.BatchPath, routing.Cache)
+	// Single task reads are also kept in process for L1_CACHE_TTL, invalidated across
+	// replicas over Redis pub/sub; 0 turns the local cache off
+	l1CacheTTL, err := time.ParseDuration(getEnv("L1_CACHE_TTL", "5s"))
+	if err != nil {
+		log.Fatalf("Invalid L1_CACHE_TTL: %v", err)
+	}
+	l1CacheSize, err := strconv.Atoi(getEnv("L1_CACHE_SIZE", "1000"))
+	if err != nil || l1CacheSize < 1 {
+		log.Fatalf("Invalid L1_CACHE_SIZE: must be a positive number")
+	}
+	if l1CacheTTL > 0 {
+		localCache := cache.NewLocalCache(redisCache, l1CacheTTL, l1CacheSize)
+		go localCache.Listen(context.Background())
+		cacheMiddleware.WithLocalCache(localCache)
+	}
 
 	// API v1 routes
 	v1Router := router.PathPrefix("/api/v1").Subrouter()
 	
+	// Tasks of other organizations are not found on any task route, whatever the caller's role
+	taskTenant := auth.TenantResourceMiddleware(service.TaskInTenant(taskRepo))
+
+	// Stars are personal, so any reader may star a task and unstarring needs no step-up.
+	// Registered before the tasks router so its ownership and step-up checks don't apply.
+	starRouter := v1Router.PathPrefix("/tasks/{id}/star").Subrouter()
+	starRouter.Use(taskTenant)
+	starRouter.Use(auth.RequireReadWriteScopes(auth.ScopeTasksRead, auth.ScopeTasksWrite))
+	api.NewStarHandler(service.NewStarService(starRepo, taskRepo)).RegisterRoutes(starRouter)
+
+	// Any reader may copy a task into a task of their own, so cloning skips the ownership check
+	cloneRouter := v1Router.PathPrefix("/tasks/{id}/clone").Subrouter()
+	cloneRouter.Use(taskTenant)
+	cloneRouter.Use(auth.RequireReadWriteScopes(auth.ScopeTasksRead, auth.ScopeTasksWrite))
+	api.NewCloneHandler(service.NewCloneService(taskService, repos.Checklists)).RegisterRoutes(cloneRouter)
+
+	// Teams; members of a task's team may change it like its owner and assignee
+	teamRepo := repos.Teams
+	teamHandler := api.NewTeamHandler(service.NewTeamService(teamRepo))
+
+	// Approvers needn't own the task they approve, so the approval service checks access itself
+	approvalRouter := v1Router.PathPrefix("/tasks/{id}/approval").Subrouter()
+	approvalRouter.Use(taskTenant)
+	approvalRouter.Use(auth.RequireReadWriteScopes(auth.ScopeTasksRead, auth.ScopeTasksWrite))
+	api.NewApprovalHandler(service.NewApprovalService(approvalRepo, taskRepo, teamRepo, auditLogger)).RegisterRoutes(approvalRouter)
+
 	// Tasks routes for v1
 	tasksRouter := v1Router.PathPrefix("/tasks").Subrouter()
-	tasksRouter.Use(auth.ResourceOwnershipMiddleware("task"))
+	tasksRouter.Use(taskTenant)
+	tasksRouter.Use(auth.ResourceOwnershipMiddleware(service.TaskOwnership(taskRepo, teamRepo)))
+	tasksRouter.Use(auth.RequireReadWriteScopes(auth.ScopeTasksRead, auth.ScopeTasksWrite))
+	tasksRouter.Use(auth.RequireStepUp(stepUpMaxAge, http.MethodDelete))
 	
 	// Configure router to handle trailing slashes
 	tasksRouter.StrictSlash(true)
 	
+	// Search, badge, import and draft routes must be registered before "/{id}"
+	searchHandler.RegisterRoutes(tasksRouter)
+	api.NewBadgeHandler(badgeService).RegisterRoutes(tasksRouter)
+	api.NewOverdueHandler(overdueTaskService).RegisterRoutes(tasksRouter)
+	api.NewTaskImportHandler(service.NewTaskImportService(taskRepo, moderator)).RegisterRoutes(tasksRouter)
+	api.NewDraftHandler(taskService).RegisterRoutes(tasksRouter)
 	taskHandler.RegisterRoutes(tasksRouter)
+	shareHandler.RegisterRoutes(tasksRouter)
+	api.NewDependencyHandler(service.NewDependencyService(dependencyRepo, taskRepo)).RegisterRoutes(tasksRouter)
+	api.NewRelationHandler(service.NewRelationService(relationRepo, taskRepo)).RegisterRoutes(tasksRouter)
+	api.NewMergeHandler(service.NewMergeService(repos.Merges, taskRepo, teamRepo, eventBus)).RegisterRoutes(tasksRouter)
+	api.NewSnoozeHandler(service.NewSnoozeService(repos.Snoozes)).RegisterRoutes(tasksRouter)
+	teamHandler.RegisterTaskRoutes(tasksRouter)
+	api.NewSLAHandler(slaService).RegisterRoutes(tasksRouter)
+	api.NewChecklistHandler(service.NewChecklistService(repos.Checklists, taskRepo)).RegisterRoutes(tasksRouter)
+	api.NewBoardHandler(service.NewBoardService(repos.Boards, taskService)).RegisterRoutes(tasksRouter)
+	api.NewDescriptionHandler(service.NewDescriptionService(repos.Descriptions, taskRepo, taskService)).RegisterRoutes(tasksRouter)
+	api.NewTimeHandler(service.NewTimeTrackingService(repos.TimeEntries, taskRepo)).RegisterRoutes(tasksRouter)
+	if attachmentHandler != nil {
+		attachmentHandler.RegisterRoutes(tasksRouter)
+	}
+
+	// Task templates; anyone may use a template, only its owner may change it
+	templateRepo := repos.Templates
+	templatesRouter := v1Router.PathPrefix("/templates").Subrouter()
+	templatesRouter.Use(auth.RequireTenant(), auth.TenantResourceMiddleware(service.TemplateInTenant(templateRepo)))
+	templatesRouter.Use(auth.ResourceOwnershipMiddleware(service.TemplateOwnership(templateRepo)))
+	templatesRouter.Use(auth.RequireReadWriteScopes(auth.ScopeTasksRead, auth.ScopeTasksWrite))
+	templatesRouter.StrictSlash(true)
+	api.NewTemplateHandler(service.NewTemplateService(templateRepo, taskService)).RegisterRoutes(templatesRouter)
+
+	// Per-project summaries across tasks
+	projectsRouter := v1Router.PathPrefix("/projects").Subrouter()
+	projectsRouter.Use(auth.RequireTenant())
+	projectsRouter.Use(auth.RequireReadWriteScopes(auth.ScopeTasksRead, auth.ScopeTasksWrite))
+	api.NewWorkloadHandler(service.NewWorkloadService(repos.Workload)).RegisterRoutes(projectsRouter)
+	api.NewProjectSettingsHandler(staleTaskService).RegisterRoutes(projectsRouter)
+	api.NewProgressHandler(progressService).RegisterRoutes(projectsRouter)
+
+	// Tag listing across all tasks
+	tagsRouter := v1Router.PathPrefix("/tags").Subrouter()
+	tagsRouter.Use(auth.RequireTenant())
+	tagsRouter.Use(auth.RequireReadWriteScopes(auth.ScopeTasksRead, auth.ScopeTasksWrite))
+	taskHandler.RegisterTagRoutes(tagsRouter)
+
+	// Incremental task sync and offline edits for offline clients
+	syncRouter := v1Router.PathPrefix("/sync").Subrouter()
+	syncRouter.Use(auth.RequireTenant())
+	syncRouter.Use(auth.RequireReadWriteScopes(auth.ScopeTasksRead, auth.ScopeTasksWrite))
+	conflictPolicy, err := setup.SyncConflictPolicy()
+	if err != nil {
+		log.Fatal(err)
+	}
+	syncService := service.NewSyncService(repos.Sync, taskService, service.TaskOwnership(taskRepo, teamRepo), conflictPolicy)
+	api.NewSyncHandler(syncService).RegisterRoutes(syncRouter)
+
+	// Account-less access through share URLs; the share token is validated per request
+	shareHandler.RegisterSharedRoutes(v1Router.PathPrefix("/shared").Subrouter())
+
+	// Bot protection for account endpoints (sign-up, password sign-in and reset).
+	// Routes registered on accountRouter require a CAPTCHA or proof-of-work response;
+	// solved proof-of-work challenges are recorded in Redis so no replica accepts one twice.
+	challengeVerifier, pow, err := newChallengeVerifier(authSecret, redisCache)
+	if err != nil {
+		log.Fatalf("Failed to configure challenge verification: %v", err)
+	}
+	if pow != nil {
+		api.NewChallengeHandler(pow).RegisterRoutes(v1Router)
+	}
+	accountRouter := v1Router.PathPrefix("/account").Subrouter()
+	accountRouter.Use(challenge.Middleware(challengeVerifier))
+	accountService := service.NewAccountService(userRepo, tokenManager, mailSender,
+		getEnv("PUBLIC_BASE_URL", "http://localhost:"+serverPort))
+	api.NewAccountHandler(accountService).RegisterRoutes(accountRouter)
+
+	// Admin moderation review queue
+	moderationRouter := v1Router.PathPrefix("/admin/moderation").Subrouter()
+	moderationRouter.Use(auth.RequireRoles("admin"))
+	moderationHandler.RegisterRoutes(moderationRouter)
+
+	// Request latency percentiles and cache effectiveness for admins
+	statsRouter := v1Router.PathPrefix("/admin/stats").Subrouter()
+	statsRouter.Use(auth.RequireRoles("admin"))
+	api.NewStatsHandler(authConfig.TokenCache).RegisterRoutes(statsRouter)
+
+	// Cache inspection and invalidation for admins, instead of FLUSHALL during incidents
+	cacheAdminRouter := v1Router.PathPrefix("/admin/cache").Subrouter()
+	cacheAdminRouter.Use(auth.RequireRoles("admin"))
+	api.NewCacheHandler(service.NewCacheAdminService(redisCache)).RegisterRoutes(cacheAdminRouter)
+
+	// Which clients still use deprecations, for maintainers planning sunsets
+	deprecationsRouter := v1Router.PathPrefix("/admin/deprecations").Subrouter()
+	deprecationsRouter.Use(auth.RequireRoles("admin"))
+	api.NewDeprecationHandler(deprecationService).RegisterRoutes(deprecationsRouter)
+
+	// Role permissions, edited by admins without restarting the server
+	rolesRouter := v1Router.PathPrefix("/admin/roles").Subrouter()
+	rolesRouter.Use(auth.RequireRoles("admin"))
+	rolesRouter.Use(auth.RequireStepUp(stepUpMaxAge, http.MethodPut, http.MethodDelete))
+	api.NewRoleHandler(service.NewRoleService(roleRepo, auth.DefaultRoles, roleCache)).RegisterRoutes(rolesRouter)
+
+	// Org-wide announcements, managed by admins and readable without signing in
+	announcementService := service.NewAnnouncementService(repos.Announcements)
+	announcementHandler := api.NewAnnouncementHandler(announcementService)
+	announcementHandler.RegisterRoutes(v1Router.PathPrefix("/announcements").Subrouter())
+	announcementAdminRouter := v1Router.PathPrefix("/admin/announcements").Subrouter()
+	announcementAdminRouter.Use(auth.RequireRoles("admin"))
+	announcementHandler.RegisterAdminRoutes(announcementAdminRouter)
+
+	// Teams are listed to every reader and managed by admins
+	teamsRouter := v1Router.PathPrefix("/teams").Subrouter()
+	teamsRouter.Use(auth.RequireReadWriteScopes(auth.ScopeTasksRead, auth.ScopeTasksWrite))
+	teamHandler.RegisterRoutes(teamsRouter)
+	teamAdminRouter := v1Router.PathPrefix("/admin/teams").Subrouter()
+	teamAdminRouter.Use(auth.RequireRoles("admin"))
+	teamHandler.RegisterAdminRoutes(teamAdminRouter)
+
+	// Saved reports over tasks, defined and run by admins
+	reportTimeout, err := time.ParseDuration(getEnv("REPORT_TIMEOUT", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid REPORT_TIMEOUT: %v", err)
+	}
+	reportRouter := v1Router.PathPrefix("/admin/reports").Subrouter()
+	reportRouter.Use(auth.RequireRoles("admin"))
+	reportService := service.NewReportService(repos.Reports, reportTimeout)
+	api.NewReportHandler(reportService).RegisterRoutes(reportRouter)
+
+	// Large exports are queued in Postgres and built by background workers
+	exportStore, err := setup.ExportStore()
+	if err != nil {
+		log.Fatalf("Failed to configure exports: %v", err)
+	}
+	exportQueue, importQueue, err := setup.JobQueues(redisCache)
+	if err != nil {
+		log.Fatalf("Failed to configure job queue: %v", err)
+	}
+	retryPolicies, err := setup.RetryPolicies()
+	if err != nil {
+		log.Fatal(err)
+	}
+	exportRepo := repos.Exports
+	exportService := service.NewExportService(exportRepo, taskRepo, reportService, preferencesRepo, exportStore, exportQueue, retryPolicies[models.JobExport])
+	exportsRouter := v1Router.PathPrefix("/exports").Subrouter()
+	exportsRouter.Use(auth.RequireReadWriteScopes(auth.ScopeTasksRead, auth.ScopeTasksWrite))
+	api.NewExportHandler(exportService).RegisterRoutes(exportsRouter)
+
+	// CSV and Jira imports run as resumable background jobs
+	importRepo := repos.Imports
+	importService := service.NewImportService(importRepo, taskService, redisCache, importQueue, retryPolicies[models.JobImport])
+	importsRouter := v1Router.PathPrefix("/imports").Subrouter()
+	importsRouter.Use(auth.RequireReadWriteScopes(auth.ScopeTasksRead, auth.ScopeTasksWrite))
+	api.NewImportHandler(importService).RegisterRoutes(importsRouter)
+
+	// Export and import jobs that ran out of retries, inspected and requeued by admins
+	deadLettersRouter := v1Router.PathPrefix("/admin/jobs/dead-letters").Subrouter()
+	deadLettersRouter.Use(auth.RequireRoles("admin"))
+	deadLetterService := service.NewDeadLetterService(repos.DeadLetters, exportRepo, importRepo)
+	api.NewDeadLetterHandler(deadLetterService).RegisterRoutes(deadLettersRouter)
+
+	// Replays of archived task events for admins
+	eventsAdminRouter := v1Router.PathPrefix("/admin/events").Subrouter()
+	eventsAdminRouter.Use(auth.RequireRoles("admin"))
+	api.NewEventArchiveHandler(eventArchive).RegisterRoutes(eventsAdminRouter)
+
+	// Webhook subscriptions, managed with the webhooks:manage scope
+	webhooksRouter := v1Router.PathPrefix("/webhooks").Subrouter()
+	webhooksRouter.Use(auth.RequireScopes(auth.ScopeWebhooksManage))
+	// Creating a webhook issues its signing secret
+	webhooksRouter.Use(auth.RequireStepUpOn(stepUpMaxAge, auth.PublicRoute{Methods: []string{http.MethodPost}, Pattern: "/api/v1/webhooks"}))
+	api.NewWebhookHandler(webhookService).RegisterRoutes(webhooksRouter)
+
+	// Background jobs and schedulers run here unless they are left to cmd/worker
+	runWorkers, err := strconv.ParseBool(getEnv("RUN_WORKERS", "true"))
+	if err != nil {
+		log.Fatalf("Invalid RUN_WORKERS: %v", err)
+	}
+	if runWorkers {
+		go setup.Worker(setup.Jobs{
+			RecentTasks:  recentTaskService,
+			Digests:      digestService,
+			StaleTasks:   staleTaskService,
+			OverdueTasks: overdueTaskService,
+			SLAs:         slaService,
+			KPIs:         service.NewKPIService(repos.KPIs),
+			Exports:      exportService,
+			Imports:      importService,
+			Webhooks:     webhookService,
+			EventArchive: eventArchive,
+			ExportRepo:   exportRepo,
+			ImportRepo:   importRepo,
+		}).Run(context.Background())
+	} else {
+		log.Println("Background jobs are left to the worker process")
+	}
+
+	// OAuth2 client_credentials token endpoint and admin client management
+	oauthHandler.RegisterRoutes(router.PathPrefix("/oauth").Subrouter())
+	oauthAdminRouter := v1Router.PathPrefix("/admin/oauth").Subrouter()
+	oauthAdminRouter.Use(auth.RequireRoles("admin"))
+	oauthAdminRouter.Use(auth.RequireStepUp(stepUpMaxAge, http.MethodDelete))
+	oauthHandler.RegisterAdminRoutes(oauthAdminRouter)
 
-	// Apply cache middleware
-	handler := cacheMiddleware.CacheHandler(router)
+	// Sign-in with OpenID Connect providers such as Google or Azure AD
+	publicBaseURL := getEnv("PUBLIC_BASE_URL", "http://localhost:"+serverPort)
+	oidcProviders, err := newOIDCProviders(publicBaseURL)
+	if err != nil {
+		log.Fatalf("Failed to configure OIDC sign-in: %v", err)
+	}
+	if len(oidcProviders) > 0 {
+		oidcService := service.NewOIDCService(oidcProviders, redisCache, repos.OIDCIdentities, tokenManager)
+		api.NewOIDCHandler(oidcService, strings.HasPrefix(publicBaseURL, "https://")).RegisterRoutes(v1Router.PathPrefix("/auth/oidc").Subrouter())
+		log.Printf("OIDC sign-in enabled for %s", strings.Join(oidcService.Providers(), ", "))
+	}
+
+	// Per-user account routes
+	usersRouter := v1Router.PathPrefix("/users").Subrouter()
+	securityHandler.RegisterRoutes(usersRouter)
+	api.NewRecentTaskHandler(recentTaskService).RegisterRoutes(usersRouter)
+	preferencesHandler.RegisterRoutes(usersRouter)
+
+	// Organization IP allowlists, telemetry settings and invitations, managed by platform admins or the org's own admins
+	orgRouter := v1Router.PathPrefix("/orgs").Subrouter()
+	orgRouter.Use(auth.RequireRoles("admin", "org_admin"))
+	ipAllowlistHandler.RegisterRoutes(orgRouter)
+	telemetryHandler.RegisterOrgRoutes(orgRouter)
+	invitationHandler.RegisterOrgRoutes(orgRouter)
+
+	// Invitations are accepted by the invitee, whatever their roles
+	invitationHandler.RegisterRoutes(v1Router.PathPrefix("/invitations").Subrouter())
+
+	// Refresh rotates the caller's refresh token; logout revokes the caller's tokens
+	api.NewSessionHandler(tokenManager).RegisterRoutes(v1Router.PathPrefix("/auth").Subrouter())
+
+	// API keys for integrations, managed by each user for themselves
+	apiKeysRouter := v1Router.PathPrefix("/apikeys").Subrouter()
+	apiKeysRouter.Use(auth.RequireStepUp(stepUpMaxAge, http.MethodPost))
+	api.NewAPIKeyHandler(apiKeyService).RegisterRoutes(apiKeysRouter)
+
+	// Usage events reported by first-party clients
+	telemetryHandler.RegisterRoutes(v1Router.PathPrefix("/telemetry").Subrouter())
 
-	// Initialize health check handler with service monitor
+	// Impersonation requires both the admin and impersonator roles
+	impersonationRouter := v1Router.PathPrefix("/admin").Subrouter()
+	impersonationRouter.Use(auth.RequireRoles("admin"))
+	impersonationRouter.Use(auth.RequireRoles("impersonator"))
+	impersonationRouter.Use(auth.RequireStepUp(stepUpMaxAge))
+	impersonationHandler.RegisterRoutes(impersonationRouter)
+
+	// JSON timestamps are written as RFC3339Nano unless TIME_FORMAT or the Accept header's
+	// time-format parameter asks for another format
+	timeFormat, err := models.ParseTimeFormat(os.Getenv("TIME_FORMAT"))
+	if err != nil {
+		log.Fatalf("Invalid TIME_FORMAT: %v", err)
+	}
+
+	// The request ID wraps the router so cached responses carry one too, and timestamps are
+	// reformatted outside it so one cached entry serves every format
+	handler := middleware.RequestIDMiddleware(middleware.TimeFormatMiddleware(timeFormat)(router))
+
+	// Optionally send the most severe active announcement on every response, cached ones included
+	announcementHeader, err := strconv.ParseBool(getEnv("ANNOUNCEMENT_HEADER", "false"))
+	if err != nil {
+		log.Fatalf("Invalid ANNOUNCEMENT_HEADER: %v", err)
+	}
+	if announcementHeader {
+		handler = middleware.AnnouncementMiddleware(announcementService)(handler)
+	}
+
+	// Batched sub-requests run through the complete handler, as if sent one by one
+	batchMaxRequests, err := strconv.Atoi(getEnv("BATCH_MAX_REQUESTS", "20"))
+	if err != nil || batchMaxRequests < 1 {
+		log.Fatalf("Invalid BATCH_MAX_REQUESTS: %q", os.Getenv("BATCH_MAX_REQUESTS"))
+	}
+	api.NewBatchHandler(handler, batchMaxRequests).RegisterRoutes(v1Router)
+
+	// Initialize health check handler with service monitor. Without ENABLE_METRICS there is
+	// none, and a nil *ServiceMonitor must not reach the handler as a non-nil interface.
+	var healthMonitor interface{ UpdateServiceState(state monitoring.ServiceState) error }
+	if serviceMonitor != nil {
+		healthMonitor = serviceMonitor
+	}
 	healthHandler := health.NewHandler(
 		"1.0", // API version
 		db,      // database connection
 		redisCache, // Redis client
-		serviceMonitor, // Service monitor
-	)
+		healthMonitor, // Service monitor
+	).WithTelemetry(metrics.Telemetry).WithSignals(metrics.GoldenSignals)
 
-	// Add global health check route
+	// Add global health check routes; only the summary is public
 	router.Handle("/health", healthHandler).Methods(http.MethodGet)
+	router.HandleFunc("/health/details", healthHandler.Details).Methods(http.MethodGet)
 
 	// Start the server
 	log.Printf("Server starting on port %s", serverPort)
+	if *devMode {
+		log.Printf("Dev mode: authenticate as %q, an admin, with the token below", devAdminID)
+		fmt.Printf("\nexport TOKEN=%s\n\n", devToken)
+	}
 	if err := http.ListenAndServe(":"+serverPort, handler); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
@@ -177,6 +852,80 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// loadSigningKey reads the PEM private key tokens are signed with. Its algorithm must be
+// allowed, or the API would reject the tokens it issues.
+func loadSigningKey(path, id string, allowed []string) (*auth.SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := auth.ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, err
+	}
+	signingKey, err := auth.NewSigningKey(id, key)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(allowed, signingKey.Method.Alg()) {
+		return nil, fmt.Errorf("JWT_ALLOWED_ALGORITHMS must include %s", signingKey.Method.Alg())
+	}
+	return signingKey, nil
+}
+
+// newAttachmentHandler builds the attachment handler from environment configuration.
+// Attachments are disabled, and nil is returned, when no bucket is configured.
+func newAttachmentHandler(attachments repository.AttachmentRepository, tasks repository.TaskRepository) (*api.AttachmentHandler, error) {
+	bucket := os.Getenv("ATTACHMENTS_BUCKET")
+	if bucket == "" {
+		log.Println("Task attachments are disabled")
+		return nil, nil
+	}
+
+	maxSize, err := strconv.ParseInt(getEnv("ATTACHMENT_MAX_BYTES", "26214400"), 10, 64)
+	if err != nil || maxSize <= 0 {
+		return nil, fmt.Errorf("invalid ATTACHMENT_MAX_BYTES: %q", os.Getenv("ATTACHMENT_MAX_BYTES"))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(os.Getenv("AWS_REGION")),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AWS config: %v", err)
+	}
+
+	store := storage.NewS3Store(cfg, bucket, os.Getenv("ATTACHMENTS_S3_ENDPOINT"))
+	log.Printf("Task attachments stored in S3 bucket %s", bucket)
+	return api.NewAttachmentHandler(service.NewAttachmentService(attachments, tasks, store, maxSize), maxSize), nil
+}
+
+// newChallengeVerifier builds the bot challenge verifier from environment configuration.
+// The proof-of-work issuer is returned separately so its challenge endpoint can be exposed.
+func newChallengeVerifier(authSecret []byte, used challenge.ReplayStore) (challenge.Verifier, *challenge.ProofOfWork, error) {
+	provider := getEnv("CHALLENGE_PROVIDER", "none")
+	switch provider {
+	case "none":
+		return nil, nil, nil
+	case "pow":
+		difficulty, err := strconv.Atoi(getEnv("POW_DIFFICULTY", "20"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid POW_DIFFICULTY: %v", err)
+		}
+		pow := challenge.NewProofOfWork(authSecret, difficulty, 5*time.Minute, used)
+		return pow, pow, nil
+	default:
+		secret := os.Getenv("CAPTCHA_SECRET")
+		if secret == "" {
+			return nil, nil, fmt.Errorf("CAPTCHA_SECRET must be set for provider %s", provider)
+		}
+		verifier, err := challenge.NewCaptchaVerifier(provider, secret)
+		if err != nil {
+			return nil, nil, err
+		}
+		return verifier, nil, nil
+	}
+}
+
 // setupDefaultAlarms creates the default set of alarms
 func setupDefaultAlarms(ctx context.Context, monitor *monitoring.ServiceMonitor) error {
 	alarms := []struct {
@@ -215,4 +964,180 @@ func setupDefaultAlarms(ctx context.Context, monitor *monitoring.ServiceMonitor)
 	}
 
 	return nil
-} 
\ No newline at end of file
+} 
+
+// newOIDCProviders discovers the OpenID Connect providers named in OIDC_PROVIDERS, each
+// configured by OIDC_<NAME>_* settings, and keeps their signing keys refreshed
+func newOIDCProviders(baseURL string) ([]service.OIDCIdentityProvider, error) {
+	refresh, err := time.ParseDuration(getEnv("JWKS_REFRESH_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWKS_REFRESH_INTERVAL: %v", err)
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	var providers []service.OIDCIdentityProvider
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		roleMap, err := auth.ParseOIDCRoleMap(os.Getenv(prefix + "ROLE_MAP"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %sROLE_MAP: %v", prefix, err)
+		}
+		config := auth.OIDCConfig{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  strings.TrimSuffix(baseURL, "/") + "/api/v1/auth/oidc/" + name + "/callback",
+			RoleClaim:    os.Getenv(prefix + "ROLE_CLAIM"),
+			RoleMap:      roleMap,
+			DefaultRoles: strings.Fields(strings.ReplaceAll(getEnv(prefix+"DEFAULT_ROLES", "user"), ",", " ")),
+		}
+		if config.Issuer == "" || config.ClientID == "" {
+			return nil, fmt.Errorf("%sISSUER and %sCLIENT_ID must be set", prefix, prefix)
+		}
+		provider, err := auth.NewOIDCProvider(context.Background(), config, client)
+		if err != nil {
+			return nil, err
+		}
+		go provider.Keys().Run(context.Background(), refresh)
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// runSelfCheck runs the same checks as `taskctl doctor` and returns the process exit code
+func runSelfCheck() int {
+	checks, cleanup := doctor.FromEnv(os.Getenv)
+	defer cleanup()
+
+	report := doctor.Run(context.Background(), checks)
+	report.WriteText(os.Stdout)
+	if report.Failed() {
+		return 1
+	}
+	return 0
+}
+
+// newSecurityDetector configures suspicious-activity detection on top of the audit log
+func newSecurityDetector(next audit.Logger, store security.Store) (*security.Detector, error) {
+	config := security.DefaultConfig()
+	var err error
+	if value := os.Getenv("SECURITY_WINDOW"); value != "" {
+		if config.Window, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid SECURITY_WINDOW: %w", err)
+		}
+	}
+	if value := os.Getenv("SECURITY_UNAUTHORIZED_THRESHOLD"); value != "" {
+		if config.UnauthorizedThreshold, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("invalid SECURITY_UNAUTHORIZED_THRESHOLD: %w", err)
+		}
+	}
+	if value := os.Getenv("SECURITY_FORBIDDEN_THRESHOLD"); value != "" {
+		if config.ForbiddenThreshold, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("invalid SECURITY_FORBIDDEN_THRESHOLD: %w", err)
+		}
+	}
+
+	notifier := security.MultiNotifier{security.LogNotifier{}}
+	if webhookURL := os.Getenv("SECURITY_WEBHOOK_URL"); webhookURL != "" {
+		notifier = append(notifier, security.NewWebhookNotifier(webhookURL))
+	}
+
+	return security.NewDetector(next, notifier, config, store), nil
+}
+
+// runSandbox serves the task API over fixture tasks kept in memory, without a database,
+// Redis or signing keys, so frontends and SDK tests can develop against a stable
+// instance. Writes are answered but never kept, and the demo token authenticates as
+// auth.SandboxUserID, who owns the fixtures.
+func runSandbox(serverPort string) {
+	token := getEnv("SANDBOX_TOKEN", "sandbox-demo-token")
+	if token == "" {
+		log.Fatal("SANDBOX_TOKEN must not be empty")
+	}
+	timeFormat, err := models.ParseTimeFormat(os.Getenv("TIME_FORMAT"))
+	if err != nil {
+		log.Fatalf("Invalid TIME_FORMAT: %v", err)
+	}
+
+	taskService := service.NewTaskService(memory.NewTaskRepository(memory.TaskFixtures(auth.SandboxUserID)))
+	taskHandler := api.NewTaskHandler(taskService)
+
+	router := mux.NewRouter()
+	router.Use(middleware.LoggingMiddleware)
+	router.Use(auth.AuthMiddleware(auth.AuthConfig{
+		AllowedRoles: auth.DefaultRoles,
+		PublicRoutes: []auth.PublicRoute{
+			{Methods: []string{http.MethodGet}, Pattern: "/health"},
+		},
+		SandboxAccess: &auth.SandboxAccess{Token: token, Roles: []string{"user"}},
+	}))
+
+	v1Router := router.PathPrefix("/api/v1").Subrouter()
+	tasksRouter := v1Router.PathPrefix("/tasks").Subrouter()
+	tasksRouter.StrictSlash(true)
+	// Draft routes must be registered before "/{id}"
+	api.NewDraftHandler(taskService).RegisterRoutes(tasksRouter)
+	taskHandler.RegisterRoutes(tasksRouter)
+	taskHandler.RegisterTagRoutes(v1Router.PathPrefix("/tags").Subrouter())
+
+	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "healthy", "mode": "sandbox"})
+	}).Methods(http.MethodGet)
+
+	handler := middleware.RequestIDMiddleware(middleware.TimeFormatMiddleware(timeFormat)(router))
+
+	log.Printf("Sandbox mode: serving fixture tasks on port %s; authenticate with the SANDBOX_TOKEN bearer token", serverPort)
+	if err := http.ListenAndServe(":"+serverPort, handler); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+}
+
+// devAdminID is the seeded admin dev mode prints a token for
+const devAdminID = "dev-admin"
+
+// startDev prepares dev mode, which serves the API for local development with no external
+// services. Data is kept in the SQLite database at DEV_DATABASE, migrated on startup and
+// seeded with the fixture tasks when empty, and the dev admin is added to it. Redis runs in
+// process, and unless AUTH_SECRET is set tokens are signed with a secret generated at
+// startup. It returns the database and a function stopping Redis.
+func startDev() (*sql.DB, func()) {
+	databasePath := getEnv("DEV_DATABASE", "dev.db")
+	db, err := sqlite.Open(context.Background(), databasePath)
+	if err != nil {
+		log.Fatalf("Failed to open the dev database: %v", err)
+	}
+	if err := sqlite.Seed(context.Background(), db, memory.TaskFixtures(devAdminID)); err != nil {
+		log.Fatalf("Failed to seed the dev database: %v", err)
+	}
+	admin := &models.Account{ID: devAdminID, Email: devAdminID + "@localhost", Roles: []string{"admin"}}
+	if err := sqlite.SeedUser(context.Background(), db, admin); err != nil {
+		log.Fatalf("Failed to seed the dev admin: %v", err)
+	}
+
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		log.Fatalf("Failed to start in-process Redis: %v", err)
+	}
+	os.Setenv("REDIS_ADDR", redisServer.Addr())
+	os.Setenv("REDIS_PASSWORD", "")
+
+	if os.Getenv("AUTH_SECRET") == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatalf("Failed to generate a dev signing secret: %v", err)
+		}
+		os.Setenv("AUTH_SECRET", hex.EncodeToString(secret))
+	}
+	if os.Getenv("AUTH_ISSUER") == "" {
+		os.Setenv("AUTH_ISSUER", "task-management-dev")
+	}
+
+	log.Printf("Dev mode: serving the API from %s", databasePath)
+	return db, redisServer.Close
+}
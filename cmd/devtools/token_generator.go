@@ -18,8 +18,11 @@ func main() {
 		"iat":   time.Now().Unix(),
 		"nbf":   time.Now().Unix(),
 		"iss":   "dev-auth",
+		"aud":   "task-management-api",
 		"roles": []string{"admin"},
 		"uid":   "test-user",
+		// An unscoped token grants nothing unless it is a first-party session
+		"first_party": true,
 	}
 
 	// Create token
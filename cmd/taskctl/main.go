@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"sample/task-management-system/pkg/doctor"
+)
+
+const usage = `Usage: taskctl <command> [flags]
+
+Commands:
+  doctor    Validate configuration and connectivity, and print a report
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "doctor":
+		os.Exit(runDoctor(os.Args[2:]))
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+// runDoctor runs the startup self-checks and returns the process exit code
+func runDoctor(args []string) int {
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Print the report as JSON")
+	flags.Parse(args)
+
+	checks, cleanup := doctor.FromEnv(os.Getenv)
+	defer cleanup()
+
+	report := doctor.Run(context.Background(), checks)
+	if *asJSON {
+		if err := report.WriteJSON(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		report.WriteText(os.Stdout)
+	}
+
+	if report.Failed() {
+		return 1
+	}
+	return 0
+}
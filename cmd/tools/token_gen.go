@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,7 +17,10 @@ func main() {
 	userId := flag.String("user", "test-user", "User ID to include in token")
 	secret := flag.String("secret", "your-development-secret", "JWT secret key")
 	issuer := flag.String("issuer", "dev-auth", "Token issuer")
+	audience := flag.String("audience", "task-management-api", "Token audience (must match AUTH_AUDIENCE)")
 	duration := flag.Duration("duration", 1*time.Hour, "Token duration")
+	scope := flag.String("scope", "", "Space-separated scopes (e.g. \"tasks:read tasks:write\"); empty means limited by role only")
+	org := flag.String("org", "", "Organization ID to include in token (optional)")
 	flag.Parse()
 
 	// Create claims
@@ -25,10 +29,21 @@ func main() {
 		"uid":   *userId,
 		"roles": []string{*role},
 		"iss":   *issuer,
+		"aud":   *audience,
 		"exp":   now.Add(*duration).Unix(),
 		"iat":   now.Unix(),
 		"nbf":   now.Unix(),
 	}
+	scopeClaim := strings.Join(strings.Fields(*scope), " ")
+	if scopeClaim != "" {
+		claims["scope"] = scopeClaim
+	} else {
+		// An unscoped token grants nothing unless it is a first-party session
+		claims["first_party"] = true
+	}
+	if *org != "" {
+		claims["org_id"] = *org
+	}
 
 	// Create token
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -45,11 +60,15 @@ func main() {
 		ExpiresAt time.Time `json:"expires_at"`
 		UserID    string    `json:"user_id"`
 		Roles     []string  `json:"roles"`
+		Scope     string    `json:"scope,omitempty"`
+		OrgID     string    `json:"org_id,omitempty"`
 	}{
 		Token:     tokenString,
 		ExpiresAt: time.Unix(claims["exp"].(int64), 0),
 		UserID:    claims["uid"].(string),
 		Roles:     []string{*role},
+		Scope:     scopeClaim,
+		OrgID:     *org,
 	}
 
 	// Print as JSON
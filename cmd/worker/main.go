@@ -0,0 +1,152 @@
+// Command worker runs the background jobs and schedulers of the task management system,
+// such as exports, imports, digests and stale task checks, apart from the API so each can
+// be scaled on its own. Run the API with RUN_WORKERS=false alongside it.
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+	// Embedded so digest time zones work on hosts without a zoneinfo database
+	_ "time/tzdata"
+
+	"sample/task-management-system/internal/setup"
+	"sample/task-management-system/pkg/cache"
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/metrics"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/notify"
+	"sample/task-management-system/pkg/service"
+)
+
+func main() {
+	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
+
+	if err := metrics.Initialize(); err != nil {
+		log.Printf("Warning: Failed to initialize metrics: %v", err)
+	}
+
+	authSecret := []byte(os.Getenv("AUTH_SECRET"))
+	authIssuer := os.Getenv("AUTH_ISSUER")
+	if len(authSecret) == 0 || authIssuer == "" {
+		log.Fatal("AUTH_SECRET and AUTH_ISSUER must be set")
+	}
+
+	// Jobs are handed back or left to expire when the worker is stopped
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := setup.Database()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	repos := setup.PostgresRepositories(db)
+
+	redisCache, err := setup.Redis()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Tasks written here, e.g. by imports, update badge counts and burnup charts, are
+	// archived and reach webhooks like tasks written through the API
+	eventBus := events.NewBus(1024)
+	taskRepo, _ := setup.TaskRepository(repos, eventBus)
+	eventBus.Subscribe("badges", cache.NewBadgeCounter(redisCache).Apply)
+	eventBus.Subscribe("progress", service.NewProgressService(repos.Progress, redisCache).Record)
+	webhookSender, err := setup.WebhookSender()
+	if err != nil {
+		log.Fatal(err)
+	}
+	webhookService := service.NewWebhookService(repos.Webhooks, webhookSender)
+	eventArchive, err := setup.EventArchive(repos.EventArchive, webhookService)
+	if err != nil {
+		log.Fatal(err)
+	}
+	eventBus.Subscribe("archive", eventArchive.Record)
+	eventBus.Subscribe("webhooks", webhookService.Dispatch)
+	go eventBus.Run(ctx)
+
+	moderator, err := setup.Moderator(repos.Moderation)
+	if err != nil {
+		log.Fatalf("Failed to configure content moderation: %v", err)
+	}
+	taskService := service.NewBlockedTaskService(
+		service.NewModeratedTaskService(service.NewTaskService(taskRepo), moderator),
+		repos.Dependencies,
+	)
+
+	mailSender, err := setup.MailSender()
+	if err != nil {
+		log.Fatalf("Failed to configure email: %v", err)
+	}
+	preferencesRepo := repos.Preferences
+	staleTaskDays, err := strconv.Atoi(setup.Getenv("STALE_TASK_DAYS", "14"))
+	if err != nil {
+		log.Fatalf("Invalid STALE_TASK_DAYS: %v", err)
+	}
+	reportTimeout, err := time.ParseDuration(setup.Getenv("REPORT_TIMEOUT", "30s"))
+	if err != nil {
+		log.Fatalf("Invalid REPORT_TIMEOUT: %v", err)
+	}
+	exportStore, err := setup.ExportStore()
+	if err != nil {
+		log.Fatalf("Failed to configure exports: %v", err)
+	}
+	exportQueue, importQueue, err := setup.JobQueues(redisCache)
+	if err != nil {
+		log.Fatalf("Failed to configure job queue: %v", err)
+	}
+	retryPolicies, err := setup.RetryPolicies()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	exportRepo := repos.Exports
+	importRepo := repos.Imports
+	workers := setup.Worker(setup.Jobs{
+		RecentTasks: service.NewRecentTaskService(
+			cache.NewRecentViews(redisCache, service.MaxRecentTasks),
+			repos.Views,
+			taskRepo,
+		),
+		Digests: service.NewDigestService(preferencesRepo, repos.Digests, mailSender,
+			notify.NewUnsubscribeSigner(authSecret, authIssuer),
+			setup.Getenv("PUBLIC_BASE_URL", "http://localhost:"+setup.Getenv("SERVER_PORT", "8080"))),
+		StaleTasks:   service.NewStaleTaskService(repos.StaleTasks, preferencesRepo, eventBus, mailSender, staleTaskDays),
+		OverdueTasks: service.NewOverdueTaskService(repos.OverdueTasks, taskService, eventBus),
+		SLAs:         service.NewSLAService(repos.SLAs),
+		KPIs:         service.NewKPIService(repos.KPIs),
+		Exports: service.NewExportService(exportRepo, taskRepo,
+			service.NewReportService(repos.Reports, reportTimeout), preferencesRepo, exportStore, exportQueue, retryPolicies[models.JobExport]),
+		Imports:      service.NewImportService(importRepo, taskService, redisCache, importQueue, retryPolicies[models.JobImport]),
+		Webhooks:     webhookService,
+		EventArchive: eventArchive,
+		ExportRepo:   exportRepo,
+		ImportRepo:   importRepo,
+	})
+
+	// The health check reports every loop and the depth of each job queue
+	router := http.NewServeMux()
+	router.Handle("/health", workers)
+	server := &http.Server{Addr: ":" + setup.Getenv("WORKER_PORT", "8081"), Handler: router}
+	go func() {
+		log.Printf("Worker health check listening on %s", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to start health check server: %v", err)
+		}
+	}()
+
+	log.Println("Worker started")
+	workers.Run(ctx)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server.Shutdown(shutdownCtx)
+	log.Println("Worker stopped")
+}
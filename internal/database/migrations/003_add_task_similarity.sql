@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Trigram similarity for related-task suggestions
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_tasks_title_trgm ON tasks USING GIN (title gin_trgm_ops);
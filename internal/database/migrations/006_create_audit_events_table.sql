@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS audit_events (
+    id VARCHAR(36) PRIMARY KEY,
+    action VARCHAR(100) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL,
+    subject_id VARCHAR(255) NOT NULL,
+    impersonated BOOLEAN NOT NULL DEFAULT FALSE,
+    method VARCHAR(10),
+    path TEXT,
+    status_code INTEGER,
+    remote_addr VARCHAR(255),
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_subject ON audit_events(subject_id, created_at DESC);
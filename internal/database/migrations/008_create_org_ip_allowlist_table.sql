@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS org_ip_allowlist (
+    id VARCHAR(36) PRIMARY KEY,
+    org_id VARCHAR(255) NOT NULL,
+    cidr CIDR NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (org_id, cidr)
+);
@@ -0,0 +1,6 @@
+-- +migrate Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS owner_id VARCHAR(255);
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assignee_id VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_owner_id ON tasks(owner_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_assignee_id ON tasks(assignee_id);
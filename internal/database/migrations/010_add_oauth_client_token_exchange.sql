@@ -0,0 +1,2 @@
+-- +migrate Up
+ALTER TABLE oauth_clients ADD COLUMN IF NOT EXISTS allow_token_exchange BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,5 @@
+-- +migrate Up
+-- Deleting a parent promotes its subtasks to top-level tasks
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id VARCHAR(36) REFERENCES tasks(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);
@@ -0,0 +1,4 @@
+-- +migrate Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS priority VARCHAR(20) NOT NULL DEFAULT 'medium';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_priority ON tasks(priority);
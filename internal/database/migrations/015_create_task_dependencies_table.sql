@@ -0,0 +1,11 @@
+-- +migrate Up
+-- task_id is blocked by blocked_by_id; deleting either task removes the dependency
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocked_by_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, blocked_by_id),
+    CHECK (task_id <> blocked_by_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_by_id ON task_dependencies(blocked_by_id);
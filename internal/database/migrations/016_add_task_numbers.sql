@@ -0,0 +1,26 @@
+-- +migrate Up
+-- Tasks are numbered per project (PROJ-123); the counter holds the last number handed out
+CREATE TABLE IF NOT EXISTS project_task_counters (
+    project_key VARCHAR(10) PRIMARY KEY,
+    last_number BIGINT NOT NULL
+);
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_key VARCHAR(10) NOT NULL DEFAULT 'TASK';
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS number BIGINT;
+
+-- Number existing tasks in creation order
+UPDATE tasks
+SET number = numbered.n
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY project_key ORDER BY created_at, id) AS n
+    FROM tasks
+) numbered
+WHERE tasks.id = numbered.id AND tasks.number IS NULL;
+
+INSERT INTO project_task_counters (project_key, last_number)
+SELECT project_key, MAX(number) FROM tasks GROUP BY project_key
+ON CONFLICT (project_key) DO NOTHING;
+
+ALTER TABLE tasks ALTER COLUMN number SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_project_number ON tasks(project_key, number);
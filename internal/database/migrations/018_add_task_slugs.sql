@@ -0,0 +1,39 @@
+-- +migrate Up
+-- URL-safe slugs derived from titles, unique per project
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS slug VARCHAR(100);
+
+UPDATE tasks
+SET slug = COALESCE(NULLIF(trim(both '-' from left(
+    trim(both '-' from regexp_replace(lower(title), '[^a-z0-9]+', '-', 'g')), 80)), ''), 'task')
+WHERE slug IS NULL;
+
+-- Tasks sharing a title get their number appended, and the rare remaining clash part of their ID
+UPDATE tasks
+SET slug = tasks.slug || '-' || tasks.number
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY project_key, slug ORDER BY number) AS n
+    FROM tasks
+) duplicates
+WHERE tasks.id = duplicates.id AND duplicates.n > 1;
+
+UPDATE tasks
+SET slug = tasks.slug || '-' || left(tasks.id, 8)
+WHERE EXISTS (
+    SELECT 1 FROM tasks other
+    WHERE other.project_key = tasks.project_key AND other.slug = tasks.slug AND other.id < tasks.id
+);
+
+ALTER TABLE tasks ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_project_slug ON tasks(project_key, slug);
+
+-- Previous slugs of renamed tasks, so old links keep resolving
+CREATE TABLE IF NOT EXISTS task_slug_history (
+    project_key VARCHAR(10) NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (project_key, slug)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_slug_history_task_id ON task_slug_history(task_id);
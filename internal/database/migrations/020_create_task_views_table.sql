@@ -0,0 +1,10 @@
+-- +migrate Up
+-- Each user's most recently viewed tasks, flushed from Redis
+CREATE TABLE IF NOT EXISTS task_views (
+    user_id VARCHAR(255) NOT NULL,
+    task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    viewed_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, task_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_views_user_viewed_at ON task_views(user_id, viewed_at DESC);
@@ -0,0 +1,3 @@
+-- +migrate Up
+-- Custom fields set by clients, e.g. {"customer": "acme"}; filtered with metadata ->> key
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Drafts are saved while being written, before they are complete, and only their owner
+-- sees them until they are published
+ALTER TYPE task_status ADD VALUE IF NOT EXISTS 'draft';
+
+ALTER TABLE tasks ALTER COLUMN due_date DROP NOT NULL;
@@ -0,0 +1,10 @@
+-- +migrate Up
+-- Each edit of a task's description is kept as a gzip-compressed snapshot
+CREATE TABLE IF NOT EXISTS task_description_versions (
+    task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    content BYTEA NOT NULL,
+    editor_id VARCHAR(36),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, version)
+);
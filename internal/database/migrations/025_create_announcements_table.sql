@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS announcements (
+    id VARCHAR(36) PRIMARY KEY,
+    message VARCHAR(500) NOT NULL,
+    severity VARCHAR(20) NOT NULL DEFAULT 'info',
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_announcements_window ON announcements(starts_at, ends_at);
@@ -0,0 +1,21 @@
+-- +migrate Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimated_minutes INTEGER;
+-- Total of the task's finished time entries, kept on the task so every read can report it
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS logged_minutes INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS task_time_entries (
+    id VARCHAR(36) PRIMARY KEY,
+    task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    minutes INTEGER NOT NULL DEFAULT 0,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_time_entries_task_id ON task_time_entries(task_id, started_at);
+
+-- A user runs at most one timer per task
+CREATE UNIQUE INDEX IF NOT EXISTS idx_task_time_entries_running
+    ON task_time_entries(task_id, user_id) WHERE ended_at IS NULL;
@@ -0,0 +1,23 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS telemetry_events (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(64) NOT NULL,
+    client VARCHAR(20) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    org_id VARCHAR(255),
+    properties JSONB NOT NULL DEFAULT '{}',
+    -- Fraction of events kept when this one was recorded; weight counts by 1 / sample_rate
+    sample_rate DOUBLE PRECISION NOT NULL,
+    occurred_at TIMESTAMP NOT NULL,
+    received_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_telemetry_events_name ON telemetry_events(name, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_telemetry_events_org ON telemetry_events(org_id, occurred_at);
+
+CREATE TABLE IF NOT EXISTS org_telemetry_settings (
+    org_id VARCHAR(255) PRIMARY KEY,
+    opted_out BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
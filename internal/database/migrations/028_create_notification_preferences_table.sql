@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS user_notification_preferences (
+    user_id VARCHAR(255) PRIMARY KEY,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    digest VARCHAR(10) NOT NULL DEFAULT 'off',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    digest_hour INTEGER NOT NULL DEFAULT 8,
+    last_digest_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_notification_preferences_digest
+    ON user_notification_preferences(digest) WHERE digest <> 'off';
+
+-- When the current assignee got the task, for the "recently assigned" part of digests
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP;
+UPDATE tasks SET assigned_at = created_at WHERE assignee_id IS NOT NULL AND assigned_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_assigned_at ON tasks(assignee_id, assigned_at);
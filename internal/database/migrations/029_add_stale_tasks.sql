@@ -0,0 +1,14 @@
+-- +migrate Up
+-- When the stale task job last flagged the task; it is stale while no update came after
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS stale_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_tasks_stale_at ON tasks(stale_at) WHERE stale_at IS NOT NULL;
+
+-- Per-project settings; projects without a row use the defaults
+CREATE TABLE IF NOT EXISTS project_settings (
+    project_key VARCHAR(10) PRIMARY KEY,
+    stale_after_days INTEGER, -- NULL uses STALE_TASK_DAYS, 0 disables stale detection
+    updated_by VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE user_notification_preferences ADD COLUMN IF NOT EXISTS stale_alerts BOOLEAN NOT NULL DEFAULT TRUE;
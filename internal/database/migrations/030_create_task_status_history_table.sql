@@ -0,0 +1,28 @@
+-- +migrate Up
+-- Status changes of published tasks, recorded from task events, for burnup and burndown charts
+CREATE TABLE IF NOT EXISTS task_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    task_id VARCHAR(36) NOT NULL,
+    project_key VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL, -- the task's new status, or 'deleted'
+    changed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_status_history_project ON task_status_history(project_key, changed_at);
+CREATE INDEX IF NOT EXISTS idx_task_status_history_task ON task_status_history(task_id, changed_at DESC);
+
+-- Existing tasks have no history: they count as open from their creation, and finished
+-- tasks as finished since their last update
+INSERT INTO task_status_history (task_id, project_key, status, changed_at)
+SELECT id, project_key,
+    CASE WHEN status IN ('completed', 'cancelled') THEN 'pending' ELSE status::text END,
+    created_at
+FROM tasks
+WHERE status <> 'draft'
+    AND NOT EXISTS (SELECT 1 FROM task_status_history h WHERE h.task_id = tasks.id);
+
+INSERT INTO task_status_history (task_id, project_key, status, changed_at)
+SELECT id, project_key, status::text, updated_at
+FROM tasks
+WHERE status IN ('completed', 'cancelled')
+    AND NOT EXISTS (SELECT 1 FROM task_status_history h WHERE h.task_id = tasks.id AND h.status = tasks.status::text);
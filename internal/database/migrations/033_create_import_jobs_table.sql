@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    file_name VARCHAR(255) NOT NULL,
+    options JSONB NOT NULL,
+    requested_by VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    total_rows INTEGER NOT NULL,
+    -- Checkpoint: rows before it are done, so a resumed job starts there
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    created_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    -- The uploaded file, dropped once the job finishes
+    content BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    heartbeat_at TIMESTAMP,
+    completed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_jobs_queue ON import_jobs(status, created_at);
+
+-- Outcome of every processed row, so rows past the checkpoint are not imported twice on resume
+CREATE TABLE IF NOT EXISTS import_job_rows (
+    job_id VARCHAR(36) NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+    row_number INTEGER NOT NULL,
+    task_id VARCHAR(36),
+    error TEXT,
+    PRIMARY KEY (job_id, row_number)
+);
@@ -0,0 +1,4 @@
+-- +migrate Up
+-- When the overdue task job flagged the task; the flag applies while it is not before the due date
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS overdue_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_tasks_open_due_date ON tasks(due_date) WHERE status IN ('pending', 'in_progress');
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- Manual order of tasks within their board column (project and status), ascending.
+-- Existing tasks keep their newest-first order.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS position DOUBLE PRECISION;
+UPDATE tasks
+SET position = ranked.rank * 1024
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY project_key, status ORDER BY created_at DESC) AS rank
+    FROM tasks
+) ranked
+WHERE tasks.id = ranked.id AND tasks.position IS NULL;
+ALTER TABLE tasks ALTER COLUMN position SET DEFAULT 0;
+ALTER TABLE tasks ALTER COLUMN position SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_board_position ON tasks(project_key, status, position);
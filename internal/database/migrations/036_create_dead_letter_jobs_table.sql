@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Failed jobs wait until run_after before they are retried
+ALTER TABLE export_jobs ADD COLUMN IF NOT EXISTS run_after TIMESTAMP;
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS run_after TIMESTAMP;
+
+-- Jobs given up on after their retry policy ran out; a job dead-lettered again replaces its row
+CREATE TABLE IF NOT EXISTS dead_letter_jobs (
+    job_type VARCHAR(20) NOT NULL,
+    job_id VARCHAR(36) NOT NULL,
+    attempts INTEGER NOT NULL,
+    error TEXT,
+    dead_at TIMESTAMP NOT NULL,
+    requeued_at TIMESTAMP,
+    PRIMARY KEY (job_type, job_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letter_jobs_dead_at ON dead_letter_jobs(dead_at DESC) WHERE requeued_at IS NULL;
@@ -0,0 +1,19 @@
+-- +migrate Up
+-- Delivery attempts of webhook events, kept for 30 days for integrators to debug and redeliver
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id VARCHAR(36) PRIMARY KEY,
+    webhook_id VARCHAR(36) NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id VARCHAR(36) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    success BOOLEAN NOT NULL,
+    status_code INTEGER, -- NULL when the receiver could not be reached
+    duration_ms BIGINT NOT NULL,
+    response TEXT,
+    error TEXT,
+    redelivery BOOLEAN NOT NULL DEFAULT FALSE,
+    event JSONB NOT NULL,
+    delivered_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, delivered_at DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_delivered_at ON webhook_deliveries(delivered_at);
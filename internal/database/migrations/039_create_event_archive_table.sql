@@ -0,0 +1,13 @@
+-- +migrate Up
+-- Every task event, kept for EVENT_ARCHIVE_RETENTION so downstream systems can be rebuilt by
+-- replaying a time range
+CREATE TABLE IF NOT EXISTS event_archive (
+    id BIGSERIAL PRIMARY KEY,
+    event_id VARCHAR(36) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    task_id VARCHAR(36) NOT NULL,
+    task JSONB, -- NULL for task.deleted
+    occurred_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_archive_occurred_at ON event_archive(occurred_at);
@@ -0,0 +1,14 @@
+-- +migrate Up
+-- task_id relates to related_id as type, e.g. task_id is a duplicate_of related_id. relates_to
+-- has no direction and is stored once, with the smaller ID as task_id. Deleting either task
+-- removes the relation.
+CREATE TABLE IF NOT EXISTS task_relations (
+    task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    related_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, related_id, type),
+    CHECK (task_id <> related_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_relations_related_id ON task_relations(related_id);
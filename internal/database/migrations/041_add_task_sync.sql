@@ -0,0 +1,44 @@
+-- +migrate Up
+-- change_seq orders task changes for incremental sync: the ID of the transaction that last
+-- changed the task. Transaction IDs only grow, and every transaction below the oldest one
+-- still running has finished, so syncs up to that point can't miss a change committed later.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS change_seq BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_tasks_change_seq ON tasks(change_seq, id);
+
+-- Deleted tasks, so syncing clients learn to drop them; drafts are only synced to their owner
+CREATE TABLE IF NOT EXISTS task_tombstones (
+    task_id VARCHAR(36) PRIMARY KEY,
+    owner_id VARCHAR(255),
+    draft BOOLEAN NOT NULL DEFAULT FALSE,
+    change_seq BIGINT NOT NULL,
+    deleted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_task_tombstones_change_seq ON task_tombstones(change_seq, task_id);
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION set_task_change_seq() RETURNS trigger AS $$
+BEGIN
+    NEW.change_seq := pg_current_xact_id()::text::bigint;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION record_task_tombstone() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO task_tombstones (task_id, owner_id, draft, change_seq)
+    VALUES (OLD.id, OLD.owner_id, OLD.status = 'draft', pg_current_xact_id()::text::bigint)
+    ON CONFLICT (task_id) DO UPDATE SET change_seq = EXCLUDED.change_seq, deleted_at = EXCLUDED.deleted_at;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+DROP TRIGGER IF EXISTS tasks_change_seq ON tasks;
+CREATE TRIGGER tasks_change_seq BEFORE INSERT OR UPDATE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION set_task_change_seq();
+
+DROP TRIGGER IF EXISTS tasks_tombstone ON tasks;
+CREATE TRIGGER tasks_tombstone AFTER DELETE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION record_task_tombstone();
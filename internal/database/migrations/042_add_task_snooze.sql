@@ -0,0 +1,4 @@
+-- +migrate Up
+-- A snoozed task is left out of default task lists until snoozed_until
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS snoozed_until TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_tasks_snoozed_until ON tasks(snoozed_until) WHERE snoozed_until IS NOT NULL;
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- Tasks with a row here can only be completed once approver_id approved them. Decisions
+-- are kept with who made them and when; requesting approval again starts over.
+CREATE TABLE IF NOT EXISTS task_approvals (
+    task_id VARCHAR(36) PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    approver_id VARCHAR(255) NOT NULL,
+    requested_by VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    comment TEXT,
+    requested_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    decided_by VARCHAR(255),
+    decided_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_approvals_approver_id ON task_approvals(approver_id) WHERE status = 'pending';
@@ -0,0 +1,41 @@
+-- +migrate Up
+-- A task's SLA policy: it must be responded to (leave pending) within sla_respond_within
+-- seconds and resolved (completed or cancelled) within sla_resolve_within seconds of its
+-- creation. responded_at and resolved_at are kept by a trigger, so every way of changing a
+-- task's status counts; sla_*_breached_at flag breaches once so each is reported once.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sla_respond_within INTEGER;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sla_resolve_within INTEGER;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS responded_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS resolved_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sla_respond_breached_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sla_resolve_breached_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_sla_respond ON tasks(created_at)
+    WHERE sla_respond_within IS NOT NULL AND responded_at IS NULL AND sla_respond_breached_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_sla_resolve ON tasks(created_at)
+    WHERE sla_resolve_within IS NOT NULL AND resolved_at IS NULL AND sla_resolve_breached_at IS NULL;
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION set_task_sla_times() RETURNS trigger AS $$
+BEGIN
+    IF NEW.status NOT IN ('pending', 'draft') AND NEW.responded_at IS NULL THEN
+        NEW.responded_at := CURRENT_TIMESTAMP;
+    END IF;
+    -- Reopening a task undoes its resolution
+    IF NEW.status IN ('completed', 'cancelled') THEN
+        NEW.resolved_at := COALESCE(NEW.resolved_at, CURRENT_TIMESTAMP);
+    ELSE
+        NEW.resolved_at := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+DROP TRIGGER IF EXISTS tasks_sla_times ON tasks;
+CREATE TRIGGER tasks_sla_times BEFORE INSERT OR UPDATE OF status ON tasks
+    FOR EACH ROW EXECUTE FUNCTION set_task_sla_times();
+
+-- Tasks closed before this migration count as responded to and resolved when they were last updated
+UPDATE tasks SET responded_at = updated_at WHERE status NOT IN ('pending', 'draft') AND responded_at IS NULL;
+UPDATE tasks SET resolved_at = updated_at WHERE status IN ('completed', 'cancelled') AND resolved_at IS NULL;
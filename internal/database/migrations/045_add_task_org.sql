@@ -0,0 +1,6 @@
+-- +migrate Up
+-- The organization of the task's creator, from their token, so business metrics can be
+-- reported per organization. Tasks created without one, e.g. by background imports, have none.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS org_id VARCHAR(255);
+CREATE INDEX IF NOT EXISTS idx_tasks_org ON tasks(org_id);
+CREATE INDEX IF NOT EXISTS idx_task_status_history_changed ON task_status_history(changed_at);
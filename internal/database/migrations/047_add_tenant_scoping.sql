@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Tasks belong to the tenant (organization) in their creator's token, and every query is
+-- confined to the caller's tenant; tasks without an organization form the default tenant.
+-- Tombstones keep the tenant so syncs only drop the tenant's own tasks, and export and
+-- import jobs keep the tenant they were requested in, since they run in the background.
+ALTER TABLE task_tombstones ADD COLUMN IF NOT EXISTS org_id VARCHAR(255);
+ALTER TABLE export_jobs ADD COLUMN IF NOT EXISTS org_id VARCHAR(255);
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS org_id VARCHAR(255);
+
+-- Task keys and slugs are looked up within the tenant
+CREATE INDEX IF NOT EXISTS idx_tasks_org_project ON tasks(org_id, project_key, number);
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION record_task_tombstone() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO task_tombstones (task_id, owner_id, draft, change_seq, org_id)
+    VALUES (OLD.id, OLD.owner_id, OLD.status = 'draft', pg_current_xact_id()::text::bigint, OLD.org_id)
+    ON CONFLICT (task_id) DO UPDATE SET change_seq = EXCLUDED.change_seq, deleted_at = EXCLUDED.deleted_at;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
@@ -0,0 +1,28 @@
+-- +migrate Up
+-- Invitations to join an organization. Only a hash of the emailed token is stored.
+CREATE TABLE IF NOT EXISTS org_invitations (
+    id VARCHAR(36) PRIMARY KEY,
+    org_id VARCHAR(255) NOT NULL,
+    email VARCHAR(320) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_by VARCHAR(255),
+    accepted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_invitations_org_id ON org_invitations(org_id, created_at);
+
+-- A user's membership of an organization, with the role they were invited with
+CREATE TABLE IF NOT EXISTS org_memberships (
+    org_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    invitation_id VARCHAR(36) REFERENCES org_invitations(id) ON DELETE SET NULL,
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (org_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_org_memberships_user_id ON org_memberships(user_id);
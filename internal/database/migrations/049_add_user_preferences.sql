@@ -0,0 +1,5 @@
+-- +migrate Up
+-- Notification preferences grow into general user preferences
+ALTER TABLE user_notification_preferences
+    ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT 'en',
+    ADD COLUMN IF NOT EXISTS page_size INTEGER NOT NULL DEFAULT 10;
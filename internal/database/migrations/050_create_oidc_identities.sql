@@ -0,0 +1,12 @@
+-- +migrate Up
+-- Users signing in with an OpenID Connect provider, by their subject at the provider. The
+-- first sign-in creates the local user ID every later one maps to.
+CREATE TABLE IF NOT EXISTS oidc_identities (
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    user_id VARCHAR(36) NOT NULL UNIQUE,
+    email VARCHAR(320),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, subject)
+);
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- Requests to deprecated endpoints, parameters and versions, counted per client (API key or
+-- user agent) and user so integrators can be told before the sunset date.
+CREATE TABLE IF NOT EXISTS deprecation_usage (
+    deprecation_id VARCHAR(100) NOT NULL,
+    client_kind VARCHAR(20) NOT NULL,
+    client VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL DEFAULT '',
+    org_id VARCHAR(255),
+    requests BIGINT NOT NULL,
+    first_seen_at TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL,
+    notified_at TIMESTAMP,
+    PRIMARY KEY (deprecation_id, client_kind, client, user_id)
+);
@@ -0,0 +1,35 @@
+-- +migrate Up
+-- The text search configuration each project's tasks are indexed and matched with
+ALTER TABLE project_settings ADD COLUMN IF NOT EXISTS search_language VARCHAR(20) NOT NULL DEFAULT 'english';
+
+-- Tasks carry their project's search language so the search vector can be generated with it.
+-- Existing tasks keep the english configuration they were indexed with.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_language regconfig NOT NULL DEFAULT 'english';
+
+DROP INDEX IF EXISTS idx_tasks_search_vector;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE tasks
+    ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector(search_language, coalesce(title, '')), 'A') ||
+        setweight(to_tsvector(search_language, coalesce(description, '')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector);
+
+-- New tasks, and tasks moved to another project, take the project's search language
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION set_task_search_language() RETURNS trigger AS $$
+BEGIN
+    NEW.search_language := COALESCE(
+        (SELECT search_language FROM project_settings WHERE project_key = NEW.project_key),
+        'english'
+    )::regconfig;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+DROP TRIGGER IF EXISTS tasks_search_language ON tasks;
+CREATE TRIGGER tasks_search_language BEFORE INSERT OR UPDATE OF project_key ON tasks
+    FOR EACH ROW EXECUTE FUNCTION set_task_search_language();
+
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- Role definitions edited through the admin API. A stored role replaces the built-in role
+-- of the same name; deleting it restores the built-in permissions.
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(50) PRIMARY KEY,
+    permissions JSONB NOT NULL, -- route pattern -> allowed methods
+    updated_by VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,4 @@
+-- +migrate Up
+-- The roles the provider granted at the last sign-in, so refreshed tokens and impersonation
+-- use them instead of guessing. Identities signed in before this sign in again to get roles.
+ALTER TABLE oidc_identities ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,52 @@
+-- +migrate Up
+-- Templates, webhooks, status history, slugs and project settings belong to a tenant too,
+-- so they are confined to it like tasks are (see 047). Rows that outlive their task keep
+-- the tenant themselves; other task data is confined through its task.
+
+-- Existing templates and webhooks stay with the default tenant
+ALTER TABLE task_templates ADD COLUMN IF NOT EXISTS org_id VARCHAR(255);
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS org_id VARCHAR(255);
+CREATE INDEX IF NOT EXISTS idx_task_templates_org ON task_templates(org_id);
+CREATE INDEX IF NOT EXISTS idx_webhooks_org ON webhooks(org_id);
+
+-- Status history outlives deleted tasks, so it keeps their tenant
+ALTER TABLE task_status_history ADD COLUMN IF NOT EXISTS org_id VARCHAR(255);
+
+UPDATE task_status_history h
+SET org_id = t.org_id
+FROM tasks t
+WHERE t.id = h.task_id AND t.org_id IS NOT NULL AND h.org_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_task_status_history_org_project ON task_status_history(org_id, project_key, changed_at);
+
+-- Slugs are unique per project within a tenant, so one tenant's slugs never reveal another's
+ALTER TABLE task_slug_history ADD COLUMN IF NOT EXISTS org_id VARCHAR(255);
+
+UPDATE task_slug_history h
+SET org_id = t.org_id
+FROM tasks t
+WHERE t.id = h.task_id AND t.org_id IS NOT NULL AND h.org_id IS NULL;
+
+ALTER TABLE task_slug_history DROP CONSTRAINT IF EXISTS task_slug_history_pkey;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_task_slug_history_org_slug ON task_slug_history((COALESCE(org_id, '')), project_key, slug);
+
+DROP INDEX IF EXISTS idx_tasks_project_slug;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_org_project_slug ON tasks((COALESCE(org_id, '')), project_key, slug);
+
+-- Each tenant configures its projects separately; existing settings stay with the default tenant
+ALTER TABLE project_settings ADD COLUMN IF NOT EXISTS org_id VARCHAR(255);
+ALTER TABLE project_settings DROP CONSTRAINT IF EXISTS project_settings_pkey;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_settings_org_project ON project_settings((COALESCE(org_id, '')), project_key);
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION set_task_search_language() RETURNS trigger AS $$
+BEGIN
+    NEW.search_language := COALESCE(
+        (SELECT search_language FROM project_settings
+        WHERE project_key = NEW.project_key AND COALESCE(org_id, '') = COALESCE(NEW.org_id, '')),
+        'english'
+    )::regconfig;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
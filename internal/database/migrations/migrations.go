@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL schema migrations so tools can inspect them
+// without access to the source tree.
+package migrations
+
+import "embed"
+
+// FS holds the migration files, applied in file name order
+//
+//go:embed *.sql
+var FS embed.FS
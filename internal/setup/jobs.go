@@ -0,0 +1,46 @@
+package setup
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/service"
+	"sample/task-management-system/pkg/worker"
+)
+
+// Jobs is the background work of the service: schedulers, job workers and flushers
+type Jobs struct {
+	RecentTasks  service.RecentTaskService
+	Digests      service.DigestService
+	StaleTasks   service.StaleTaskService
+	OverdueTasks service.OverdueTaskService
+	SLAs         service.SLAService
+	KPIs         service.KPIService
+	Exports      service.ExportService
+	Imports      service.ImportService
+	Webhooks     service.WebhookService
+	EventArchive service.EventArchiveService
+	// ExportRepo and ImportRepo count the jobs waiting in each queue
+	ExportRepo repository.ExportRepository
+	ImportRepo repository.ImportRepository
+}
+
+// Worker registers every background loop and job queue on a worker sampling queue
+// depths every minute
+func Worker(jobs Jobs) *worker.Worker {
+	w := worker.New(time.Minute)
+	w.Add("recent-views", func(ctx context.Context) { jobs.RecentTasks.Run(ctx, 30*time.Second) })
+	w.Add("digests", func(ctx context.Context) { jobs.Digests.Run(ctx, 5*time.Minute) })
+	w.Add("stale-tasks", func(ctx context.Context) { jobs.StaleTasks.Run(ctx, time.Hour) })
+	w.Add("overdue-tasks", func(ctx context.Context) { jobs.OverdueTasks.Run(ctx, 5*time.Minute) })
+	w.Add("sla-breaches", func(ctx context.Context) { jobs.SLAs.Run(ctx, time.Minute) })
+	w.Add("business-kpis", func(ctx context.Context) { jobs.KPIs.Run(ctx, 5*time.Minute) })
+	w.Add("exports", func(ctx context.Context) { jobs.Exports.Run(ctx, 5*time.Second) })
+	w.Add("imports", func(ctx context.Context) { jobs.Imports.Run(ctx, 5*time.Second) })
+	w.Add("webhook-deliveries", func(ctx context.Context) { jobs.Webhooks.Run(ctx, time.Hour) })
+	w.Add("event-archive", func(ctx context.Context) { jobs.EventArchive.Run(ctx, time.Hour) })
+	w.AddQueue("exports", jobs.ExportRepo.CountPending)
+	w.AddQueue("imports", jobs.ImportRepo.CountPending)
+	return w
+}
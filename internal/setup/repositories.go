@@ -0,0 +1,159 @@
+package setup
+
+import (
+	"database/sql"
+
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/repository/postgres"
+	"sample/task-management-system/pkg/repository/sqlite"
+)
+
+// Repositories are the stores the API and worker binaries build their services on, all
+// backed by one database
+type Repositories struct {
+	APIKeys        repository.APIKeyRepository
+	Announcements  repository.AnnouncementRepository
+	Approvals      repository.ApprovalRepository
+	Attachments    repository.AttachmentRepository
+	Audit          repository.AuditRepository
+	Boards         repository.BoardRepository
+	Checklists     repository.ChecklistRepository
+	DeadLetters    repository.DeadLetterRepository
+	Dependencies   repository.DependencyRepository
+	Deprecations   repository.DeprecationRepository
+	Descriptions   repository.DescriptionRepository
+	Digests        repository.DigestRepository
+	EventArchive   repository.EventArchiveRepository
+	Exports        repository.ExportRepository
+	IPAllowlists   repository.IPAllowlistRepository
+	Imports        repository.ImportRepository
+	Invitations    repository.InvitationRepository
+	KPIs           repository.KPIRepository
+	Merges         repository.MergeRepository
+	Moderation     repository.ModerationRepository
+	OAuthClients   repository.OAuthClientRepository
+	OIDCIdentities repository.OIDCIdentityRepository
+	OverdueTasks   repository.OverdueTaskRepository
+	Preferences    repository.PreferencesRepository
+	Progress       repository.ProgressRepository
+	Relations      repository.RelationRepository
+	Reports        repository.ReportRepository
+	Roles          repository.RoleRepository
+	SLAs           repository.SLARepository
+	Shares         repository.ShareRepository
+	Snoozes        repository.SnoozeRepository
+	StaleTasks     repository.StaleTaskRepository
+	Stars          repository.StarRepository
+	Sync           repository.SyncRepository
+	Tasks          repository.TaskRepository
+	TaskSearcher   repository.TaskSearcher
+	Teams          repository.TeamRepository
+	Telemetry      repository.TelemetryRepository
+	Templates      repository.TemplateRepository
+	TimeEntries    repository.TimeEntryRepository
+	Users          repository.UserRepository
+	Views          repository.ViewRepository
+	Webhooks       repository.WebhookRepository
+	Workload       repository.WorkloadRepository
+}
+
+// PostgresRepositories builds the repositories over the Postgres database of Database
+func PostgresRepositories(db *sql.DB) *Repositories {
+	return &Repositories{
+		APIKeys:        postgres.NewAPIKeyRepository(db),
+		Announcements:  postgres.NewAnnouncementRepository(db),
+		Approvals:      postgres.NewApprovalRepository(db),
+		Attachments:    postgres.NewAttachmentRepository(db),
+		Audit:          postgres.NewAuditRepository(db),
+		Boards:         postgres.NewBoardRepository(db),
+		Checklists:     postgres.NewChecklistRepository(db),
+		DeadLetters:    postgres.NewDeadLetterRepository(db),
+		Dependencies:   postgres.NewDependencyRepository(db),
+		Deprecations:   postgres.NewDeprecationRepository(db),
+		Descriptions:   postgres.NewDescriptionRepository(db),
+		Digests:        postgres.NewDigestRepository(db),
+		EventArchive:   postgres.NewEventArchiveRepository(db),
+		Exports:        postgres.NewExportRepository(db),
+		IPAllowlists:   postgres.NewIPAllowlistRepository(db),
+		Imports:        postgres.NewImportRepository(db),
+		Invitations:    postgres.NewInvitationRepository(db),
+		KPIs:           postgres.NewKPIRepository(db),
+		Merges:         postgres.NewMergeRepository(db),
+		Moderation:     postgres.NewModerationRepository(db),
+		OAuthClients:   postgres.NewOAuthClientRepository(db),
+		OIDCIdentities: postgres.NewOIDCIdentityRepository(db),
+		OverdueTasks:   postgres.NewOverdueTaskRepository(db),
+		Preferences:    postgres.NewPreferencesRepository(db),
+		Progress:       postgres.NewProgressRepository(db),
+		Relations:      postgres.NewRelationRepository(db),
+		Reports:        postgres.NewReportRepository(db),
+		Roles:          postgres.NewRoleRepository(db),
+		SLAs:           postgres.NewSLARepository(db),
+		Shares:         postgres.NewShareRepository(db),
+		Snoozes:        postgres.NewSnoozeRepository(db),
+		StaleTasks:     postgres.NewStaleTaskRepository(db),
+		Stars:          postgres.NewStarRepository(db),
+		Sync:           postgres.NewSyncRepository(db),
+		Tasks:          postgres.NewTaskRepository(db),
+		TaskSearcher:   postgres.NewTaskSearcher(db),
+		Teams:          postgres.NewTeamRepository(db),
+		Telemetry:      postgres.NewTelemetryRepository(db),
+		Templates:      postgres.NewTemplateRepository(db),
+		TimeEntries:    postgres.NewTimeEntryRepository(db),
+		Users:          postgres.NewUserRepository(db),
+		Views:          postgres.NewViewRepository(db),
+		Webhooks:       postgres.NewWebhookRepository(db),
+		Workload:       postgres.NewWorkloadRepository(db),
+	}
+}
+
+// SQLiteRepositories builds the repositories over a SQLite database opened with
+// sqlite.Open, for dev mode
+func SQLiteRepositories(db *sql.DB) *Repositories {
+	return &Repositories{
+		APIKeys:        sqlite.NewAPIKeyRepository(db),
+		Announcements:  sqlite.NewAnnouncementRepository(db),
+		Approvals:      sqlite.NewApprovalRepository(db),
+		Attachments:    sqlite.NewAttachmentRepository(db),
+		Audit:          sqlite.NewAuditRepository(db),
+		Boards:         sqlite.NewBoardRepository(db),
+		Checklists:     sqlite.NewChecklistRepository(db),
+		DeadLetters:    sqlite.NewDeadLetterRepository(db),
+		Dependencies:   sqlite.NewDependencyRepository(db),
+		Deprecations:   sqlite.NewDeprecationRepository(db),
+		Descriptions:   sqlite.NewDescriptionRepository(db),
+		Digests:        sqlite.NewDigestRepository(db),
+		EventArchive:   sqlite.NewEventArchiveRepository(db),
+		Exports:        sqlite.NewExportRepository(db),
+		IPAllowlists:   sqlite.NewIPAllowlistRepository(db),
+		Imports:        sqlite.NewImportRepository(db),
+		Invitations:    sqlite.NewInvitationRepository(db),
+		KPIs:           sqlite.NewKPIRepository(db),
+		Merges:         sqlite.NewMergeRepository(db),
+		Moderation:     sqlite.NewModerationRepository(db),
+		OAuthClients:   sqlite.NewOAuthClientRepository(db),
+		OIDCIdentities: sqlite.NewOIDCIdentityRepository(db),
+		OverdueTasks:   sqlite.NewOverdueTaskRepository(db),
+		Preferences:    sqlite.NewPreferencesRepository(db),
+		Progress:       sqlite.NewProgressRepository(db),
+		Relations:      sqlite.NewRelationRepository(db),
+		Reports:        sqlite.NewReportRepository(db),
+		Roles:          sqlite.NewRoleRepository(db),
+		SLAs:           sqlite.NewSLARepository(db),
+		Shares:         sqlite.NewShareRepository(db),
+		Snoozes:        sqlite.NewSnoozeRepository(db),
+		StaleTasks:     sqlite.NewStaleTaskRepository(db),
+		Stars:          sqlite.NewStarRepository(db),
+		Sync:           sqlite.NewSyncRepository(db),
+		Tasks:          sqlite.NewTaskRepository(db),
+		TaskSearcher:   sqlite.NewTaskSearcher(db),
+		Teams:          sqlite.NewTeamRepository(db),
+		Telemetry:      sqlite.NewTelemetryRepository(db),
+		Templates:      sqlite.NewTemplateRepository(db),
+		TimeEntries:    sqlite.NewTimeEntryRepository(db),
+		Users:          sqlite.NewUserRepository(db),
+		Views:          sqlite.NewViewRepository(db),
+		Webhooks:       sqlite.NewWebhookRepository(db),
+		Workload:       sqlite.NewWorkloadRepository(db),
+	}
+}
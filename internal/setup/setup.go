@@ -0,0 +1,344 @@
+// Package setup builds the dependencies shared by the API and worker binaries from
+// environment configuration.
+package setup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	_ "github.com/lib/pq"
+
+	"sample/task-management-system/pkg/cache"
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/middleware"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/moderation"
+	"sample/task-management-system/pkg/notify"
+	"sample/task-management-system/pkg/queue"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/search"
+	"sample/task-management-system/pkg/service"
+	"sample/task-management-system/pkg/storage"
+	"sample/task-management-system/pkg/webhook"
+)
+
+// Getenv returns the environment variable key, or fallback when it is not set
+func Getenv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
+
+// Database connects to Postgres with the DB_* settings
+func Database() (*sql.DB, error) {
+	host := Getenv("DB_HOST", "localhost")
+	port := Getenv("DB_PORT", "5432")
+	user := Getenv("DB_USER", "postgres")
+	name := Getenv("DB_NAME", "taskdb")
+	log.Printf("Connecting to database: host=%s port=%s user=%s dbname=%s", host, port, user, name)
+
+	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		user, Getenv("DB_PASSWORD", "postgres"), host, port, name)
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %v", err)
+	}
+	log.Println("Successfully connected to database")
+	return db, nil
+}
+
+// Redis connects to Redis with the REDIS_* settings
+func Redis() (*cache.RedisCache, error) {
+	log.Printf("Connecting to Redis at %s", os.Getenv("REDIS_ADDR"))
+	redisCache, err := cache.NewRedisCache(os.Getenv("REDIS_ADDR"), os.Getenv("REDIS_PASSWORD"), 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Redis cache: %v", err)
+	}
+	log.Println("Successfully connected to Redis")
+	return redisCache, nil
+}
+
+// RateLimiter builds the per-caller rate limiter from the RATE_LIMIT_* settings, or returns
+// nil when RATE_LIMIT_REQUESTS is unset or 0. It counts in the Redis of the REDIS_* settings.
+func RateLimiter() (*middleware.RateLimiter, error) {
+	maxRequests, err := strconv.Atoi(Getenv("RATE_LIMIT_REQUESTS", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_REQUESTS: %v", err)
+	}
+	if maxRequests <= 0 {
+		return nil, nil
+	}
+	window, err := time.ParseDuration(Getenv("RATE_LIMIT_WINDOW", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMIT_WINDOW: %v", err)
+	}
+
+	redisURL := url.URL{Scheme: "redis", Host: os.Getenv("REDIS_ADDR")}
+	if password := os.Getenv("REDIS_PASSWORD"); password != "" {
+		redisURL.User = url.UserPassword("", password)
+	}
+	return middleware.NewRateLimiter(redisURL.String(), maxRequests, window)
+}
+
+// TaskRepository builds the task repository and searcher over repos. With
+// SEARCH_BACKEND=opensearch writes are mirrored to OpenSearch and searches fall back to
+// the database's search. Every write is published to publisher.
+func TaskRepository(repos *Repositories, publisher events.Publisher) (repository.TaskRepository, repository.TaskSearcher) {
+	taskRepo := repos.Tasks
+	taskSearcher := repos.TaskSearcher
+
+	searchBackend := Getenv("SEARCH_BACKEND", "postgres")
+	switch searchBackend {
+	case "postgres":
+	case "opensearch":
+		openSearch := search.NewOpenSearchClient(
+			Getenv("OPENSEARCH_URL", "http://localhost:9200"),
+			Getenv("OPENSEARCH_INDEX", "tasks"),
+			os.Getenv("OPENSEARCH_USERNAME"),
+			os.Getenv("OPENSEARCH_PASSWORD"),
+			taskRepo,
+		)
+		taskRepo = search.NewIndexingRepository(taskRepo, openSearch)
+		taskSearcher = search.NewFallbackSearcher(openSearch, taskSearcher)
+		log.Printf("Using OpenSearch search backend with database fallback")
+	default:
+		log.Printf("Warning: Unknown search backend %s, defaulting to postgres", searchBackend)
+	}
+
+	return events.NewPublishingRepository(taskRepo, publisher), taskSearcher
+}
+
+// Moderator builds the content moderator
+func Moderator(flags repository.ModerationRepository) (*moderation.Moderator, error) {
+	mode, err := moderation.ParseMode(Getenv("MODERATION_MODE", "off"))
+	if err != nil {
+		return nil, err
+	}
+
+	maxURLs, err := strconv.Atoi(Getenv("MODERATION_MAX_URLS", "2"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MODERATION_MAX_URLS: %v", err)
+	}
+
+	// Copied, so appending never writes into the package-level list
+	blocklist := append([]string(nil), moderation.DefaultBlocklist...)
+	if extra := os.Getenv("MODERATION_BLOCKLIST"); extra != "" {
+		blocklist = append(blocklist, strings.Split(extra, ",")...)
+	}
+
+	checkers := []moderation.Checker{moderation.NewHeuristicChecker(blocklist, maxURLs)}
+	if apiURL := os.Getenv("MODERATION_API_URL"); apiURL != "" {
+		checkers = append(checkers, moderation.NewExternalChecker(apiURL, os.Getenv("MODERATION_API_KEY")))
+	}
+
+	log.Printf("Content moderation mode: %s", mode)
+	return moderation.NewModerator(moderation.NewMultiChecker(checkers...), mode, flags), nil
+}
+
+// MailSender builds the email sender. Without SMTP_ADDR email is only logged.
+func MailSender() (notify.Sender, error) {
+	addr := os.Getenv("SMTP_ADDR")
+	if addr == "" {
+		log.Println("SMTP_ADDR is not set; emails are logged instead of sent")
+		return notify.LogSender{}, nil
+	}
+
+	return notify.NewSMTPSender(addr, Getenv("SMTP_FROM", "Task Manager <noreply@localhost>"),
+		os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"))
+}
+
+// ExportStore builds the object store for finished exports. Without EXPORTS_BUCKET it
+// returns nil and exports are kept in Postgres.
+func ExportStore() (storage.ObjectStore, error) {
+	bucket := os.Getenv("EXPORTS_BUCKET")
+	if bucket == "" {
+		log.Println("Exports are stored in the database")
+		return nil, nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(os.Getenv("AWS_REGION")),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize AWS config: %v", err)
+	}
+
+	log.Printf("Exports stored in S3 bucket %s", bucket)
+	return storage.NewS3Store(cfg, bucket, os.Getenv("EXPORTS_S3_ENDPOINT")), nil
+}
+
+// WebhookSender builds the sender posting webhook deliveries, giving up on receivers after
+// WEBHOOK_TIMEOUT
+func WebhookSender() (webhook.Sender, error) {
+	timeout, err := time.ParseDuration(Getenv("WEBHOOK_TIMEOUT", "10s"))
+	if err != nil || timeout <= 0 {
+		return nil, fmt.Errorf("invalid WEBHOOK_TIMEOUT: %q", os.Getenv("WEBHOOK_TIMEOUT"))
+	}
+	return webhook.NewHTTPSender(timeout), nil
+}
+
+// EventArchive builds the archive of task events, keeping them for
+// EVENT_ARCHIVE_RETENTION and replaying them to webhooks
+func EventArchive(archive repository.EventArchiveRepository, webhooks service.WebhookService) (service.EventArchiveService, error) {
+	retention, err := time.ParseDuration(Getenv("EVENT_ARCHIVE_RETENTION", "2160h"))
+	if err != nil || retention <= 0 {
+		return nil, fmt.Errorf("invalid EVENT_ARCHIVE_RETENTION: %q", os.Getenv("EVENT_ARCHIVE_RETENTION"))
+	}
+	return service.NewEventArchiveService(archive, webhooks, retention), nil
+}
+
+// Deprecations reads the deprecated endpoints, parameters and API versions from the JSON
+// list in DEPRECATIONS_FILE. Without it nothing is deprecated.
+func Deprecations() ([]models.Deprecation, error) {
+	path := os.Getenv("DEPRECATIONS_FILE")
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DEPRECATIONS_FILE: %v", err)
+	}
+	deprecations, err := models.ParseDeprecations(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DEPRECATIONS_FILE: %v", err)
+	}
+	log.Printf("Tracking %d deprecation(s)", len(deprecations))
+	return deprecations, nil
+}
+
+// SyncConflictPolicy reads how offline edits conflicting with server changes are resolved
+// from SYNC_CONFLICT_POLICY
+func SyncConflictPolicy() (models.ConflictPolicy, error) {
+	policy, err := models.ParseConflictPolicy(os.Getenv("SYNC_CONFLICT_POLICY"))
+	if err != nil {
+		return "", fmt.Errorf("invalid SYNC_CONFLICT_POLICY: %v", err)
+	}
+	return policy, nil
+}
+
+// RetryPolicies reads how often each job type is retried, and how long it waits between
+// attempts, from JOB_RETRY_POLICIES
+func RetryPolicies() (map[models.JobType]models.RetryPolicy, error) {
+	policies, err := models.ParseRetryPolicies(os.Getenv("JOB_RETRY_POLICIES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_RETRY_POLICIES: %v", err)
+	}
+	return policies, nil
+}
+
+// JobQueues builds the queues handing export and import jobs to workers, each run in a
+// lane limited by JOB_LIMITS. With JOB_QUEUE=postgres, the default, it returns nil and
+// workers poll the database instead.
+func JobQueues(redisCache *cache.RedisCache) (service.JobQueue, service.JobQueue, error) {
+	backend := Getenv("JOB_QUEUE", "postgres")
+	switch backend {
+	case "postgres":
+		return nil, nil, nil
+	case "redis", "sqs":
+	default:
+		return nil, nil, fmt.Errorf("unknown JOB_QUEUE %q", backend)
+	}
+
+	claimIdle, err := time.ParseDuration(Getenv("JOB_QUEUE_CLAIM_IDLE", "5m"))
+	if err != nil || claimIdle < time.Second {
+		return nil, nil, fmt.Errorf("invalid JOB_QUEUE_CLAIM_IDLE: %q", os.Getenv("JOB_QUEUE_CLAIM_IDLE"))
+	}
+	maxDeliveries, err := strconv.ParseInt(Getenv("JOB_QUEUE_MAX_DELIVERIES", "5"), 10, 64)
+	if err != nil || maxDeliveries < 1 {
+		return nil, nil, fmt.Errorf("invalid JOB_QUEUE_MAX_DELIVERIES: %q", os.Getenv("JOB_QUEUE_MAX_DELIVERIES"))
+	}
+
+	var exports, imports queue.JobQueue
+	if backend == "sqs" {
+		exports, imports, err = sqsJobQueues(claimIdle, maxDeliveries)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		// Each instance reads the streams as its own consumer
+		consumer, err := os.Hostname()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		options := cache.JobStreamOptions{ClaimIdle: claimIdle, MaxDeliveries: maxDeliveries, Block: 5 * time.Second}
+		log.Printf("Export and import jobs queued on Redis streams as consumer %s", consumer)
+		exports = cache.NewJobStream(redisCache, "jobs:exports", consumer, options)
+		imports = cache.NewJobStream(redisCache, "jobs:imports", consumer, options)
+	}
+
+	// Job types share the instance's job slots in priority lanes
+	slots, err := strconv.Atoi(Getenv("JOB_SLOTS", "2"))
+	if err != nil || slots < 1 {
+		return nil, nil, fmt.Errorf("invalid JOB_SLOTS: %q", os.Getenv("JOB_SLOTS"))
+	}
+	limits, err := queue.ParseLimits(Getenv("JOB_LIMITS", defaultJobLimits))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid JOB_LIMITS: %v", err)
+	}
+	scheduler := queue.NewScheduler(slots)
+	return scheduler.Lane("exports", exports, jobLimits(limits, "exports")),
+		scheduler.Lane("imports", imports, jobLimits(limits, "imports")), nil
+}
+
+// defaultJobLimits runs exports, which users wait on, ahead of imports
+const defaultJobLimits = "exports=high/2,imports=normal/1"
+
+// jobLimits returns a job type's limits, defaulting to one job at a time at normal priority
+func jobLimits(limits map[string]queue.Limits, jobType string) queue.Limits {
+	if limit, ok := limits[jobType]; ok {
+		return limit
+	}
+	return queue.Limits{Priority: queue.PriorityNormal, Concurrency: 1}
+}
+
+// sqsJobQueues builds the SQS queues for export and import jobs. When
+// JOB_QUEUE_SQS_DLQ_URL is set both queues get a redrive policy moving jobs received
+// maxDeliveries times to it.
+func sqsJobQueues(visibilityTimeout time.Duration, maxDeliveries int64) (queue.JobQueue, queue.JobQueue, error) {
+	exportsURL := os.Getenv("JOB_QUEUE_SQS_EXPORTS_URL")
+	importsURL := os.Getenv("JOB_QUEUE_SQS_IMPORTS_URL")
+	if exportsURL == "" || importsURL == "" {
+		return nil, nil, errors.New("JOB_QUEUE_SQS_EXPORTS_URL and JOB_QUEUE_SQS_IMPORTS_URL are required")
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(),
+		config.WithRegion(os.Getenv("AWS_REGION")),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize AWS config: %v", err)
+	}
+
+	options := queue.SQSOptions{VisibilityTimeout: visibilityTimeout, WaitTime: 20 * time.Second}
+	endpoint := os.Getenv("JOB_QUEUE_SQS_ENDPOINT")
+	exports := queue.NewSQSQueue(cfg, endpoint, exportsURL, options)
+	imports := queue.NewSQSQueue(cfg, endpoint, importsURL, options)
+
+	if deadLetterURL := os.Getenv("JOB_QUEUE_SQS_DLQ_URL"); deadLetterURL != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		for _, q := range []*queue.SQSQueue{exports, imports} {
+			if err := q.SetDeadLetter(ctx, deadLetterURL, maxDeliveries); err != nil {
+				return nil, nil, fmt.Errorf("failed to set dead-letter queue: %v", err)
+			}
+		}
+	}
+
+	log.Printf("Export and import jobs queued on SQS")
+	return exports, imports, nil
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type AccountHandler struct {
+	service service.AccountService
+}
+
+func NewAccountHandler(service service.AccountService) *AccountHandler {
+	return &AccountHandler{service: service}
+}
+
+// RegisterRoutes registers sign-up, password sign-in and password reset on the account
+// router, which requires a bot challenge response
+func (h *AccountHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/signup", h.SignUp).Methods(http.MethodPost)
+	router.HandleFunc("/login", h.Login).Methods(http.MethodPost)
+	router.HandleFunc("/password-reset", h.RequestPasswordReset).Methods(http.MethodPost)
+	router.HandleFunc("/password-reset/confirm", h.ResetPassword).Methods(http.MethodPost)
+}
+
+// SignUp creates an account and responds with tokens for it
+func (h *AccountHandler) SignUp(w http.ResponseWriter, r *http.Request) {
+	var input models.AccountSignup
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.service.SignUp(r.Context(), &input)
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, tokens)
+}
+
+func (h *AccountHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var input models.AccountLogin
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.service.Login(r.Context(), &input)
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+// RequestPasswordReset emails a reset link; the response is the same whether or not the
+// address has an account
+func (h *AccountHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var input models.PasswordResetRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RequestPasswordReset(r.Context(), &input); err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword sets a new password with a reset token and responds with tokens
+func (h *AccountHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var input models.PasswordReset
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := input.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.service.ResetPassword(r.Context(), &input)
+	if err != nil {
+		respondAccountError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+// respondAccountError maps account errors to their status; anything else is a server error
+func respondAccountError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, models.ErrEmailTaken):
+		status = http.StatusConflict
+	case errors.Is(err, models.ErrInvalidCredentials):
+		status = http.StatusUnauthorized
+	case errors.Is(err, models.ErrPasswordResetInvalid):
+		status = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type AnnouncementHandler struct {
+	service service.AnnouncementService
+}
+
+func NewAnnouncementHandler(service service.AnnouncementService) *AnnouncementHandler {
+	return &AnnouncementHandler{service: service}
+}
+
+// RegisterRoutes registers the listing of active announcements
+func (h *AnnouncementHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.ListActive).Methods(http.MethodGet)
+}
+
+// RegisterAdminRoutes registers announcement management on the admin router
+func (h *AnnouncementHandler) RegisterAdminRoutes(router *mux.Router) {
+	router.HandleFunc("", h.CreateAnnouncement).Methods(http.MethodPost)
+	router.HandleFunc("", h.ListAnnouncements).Methods(http.MethodGet)
+	router.HandleFunc("/{id}", h.UpdateAnnouncement).Methods(http.MethodPut)
+	router.HandleFunc("/{id}", h.DeleteAnnouncement).Methods(http.MethodDelete)
+}
+
+// ListActive lists the announcements shown now, most severe first
+func (h *AnnouncementHandler) ListActive(w http.ResponseWriter, r *http.Request) {
+	announcements, err := h.service.ActiveAnnouncements(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"announcements": announcements,
+	})
+}
+
+func (h *AnnouncementHandler) CreateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var input models.AnnouncementInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	announcement, err := h.service.CreateAnnouncement(r.Context(), user.ID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, announcement)
+}
+
+// ListAnnouncements lists every announcement, including past and scheduled ones
+func (h *AnnouncementHandler) ListAnnouncements(w http.ResponseWriter, r *http.Request) {
+	announcements, err := h.service.ListAnnouncements(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"announcements": announcements,
+	})
+}
+
+func (h *AnnouncementHandler) UpdateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	var input models.AnnouncementInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	announcement, err := h.service.UpdateAnnouncement(r.Context(), mux.Vars(r)["id"], &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, announcement)
+}
+
+func (h *AnnouncementHandler) DeleteAnnouncement(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteAnnouncement(r.Context(), mux.Vars(r)["id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type APIKeyHandler struct {
+	service service.APIKeyService
+}
+
+func NewAPIKeyHandler(service service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+// RegisterRoutes registers API key management, open to any signed-in user for their own keys
+func (h *APIKeyHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.CreateKey).Methods(http.MethodPost)
+	router.HandleFunc("", h.ListKeys).Methods(http.MethodGet)
+	router.HandleFunc("/{id}", h.RevokeKey).Methods(http.MethodDelete)
+}
+
+// keyOwner returns the claims of a caller allowed to manage API keys. Keys are managed by
+// users signed in themselves: a key can't mint more keys, and an impersonator can't leave
+// a key behind acting as the user they impersonated.
+func keyOwner(w http.ResponseWriter, r *http.Request) (*auth.Claims, bool) {
+	claims, ok := r.Context().Value("claims").(*auth.Claims)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	if auth.ViaAPIKey(r.Context()) || claims.ActorID() != claims.UserID {
+		http.Error(w, "API keys can only be managed when signed in as yourself", http.StatusForbidden)
+		return nil, false
+	}
+	return claims, true
+}
+
+// CreateKey creates a key; the key itself is only ever returned in this response
+func (h *APIKeyHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	claims, ok := keyOwner(w, r)
+	if !ok {
+		return
+	}
+
+	var input models.APIKeyCreate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	apiKey, key, err := h.service.CreateKey(r.Context(), claims, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"api_key": apiKey,
+		"key":     key,
+	})
+}
+
+func (h *APIKeyHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	claims, ok := keyOwner(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.service.ListKeys(r.Context(), claims.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"api_keys": keys,
+	})
+}
+
+func (h *APIKeyHandler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	claims, ok := keyOwner(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.service.RevokeKey(r.Context(), claims.UserID, mux.Vars(r)["id"])
+	if errors.Is(err, models.ErrAPIKeyNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type ApprovalHandler struct {
+	service service.ApprovalService
+}
+
+func NewApprovalHandler(service service.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{service: service}
+}
+
+// RegisterRoutes registers the approval routes on a router for "/tasks/{id}/approval".
+// Approvers needn't own the task, so the service checks access instead of the tasks router.
+func (h *ApprovalHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.GetApproval).Methods(http.MethodGet)
+	router.HandleFunc("", h.RequestApproval).Methods(http.MethodPut)
+	router.HandleFunc("", h.RemoveApproval).Methods(http.MethodDelete)
+	router.HandleFunc("/approve", h.Approve).Methods(http.MethodPost)
+	router.HandleFunc("/reject", h.Reject).Methods(http.MethodPost)
+}
+
+func (h *ApprovalHandler) GetApproval(w http.ResponseWriter, r *http.Request) {
+	approval, err := h.service.GetApproval(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respondApprovalError(w, err, http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, approval)
+}
+
+// RequestApproval makes the task need approval, e.g. {"approver_id": "..."}
+func (h *ApprovalHandler) RequestApproval(w http.ResponseWriter, r *http.Request) {
+	var input models.ApprovalRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	approval, err := h.service.RequestApproval(r.Context(), mux.Vars(r)["id"], &input)
+	if err != nil {
+		respondApprovalError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, approval)
+}
+
+func (h *ApprovalHandler) RemoveApproval(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RemoveApproval(r.Context(), mux.Vars(r)["id"]); err != nil {
+		respondApprovalError(w, err, http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ApprovalHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.Approve)
+}
+
+func (h *ApprovalHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, h.service.Reject)
+}
+
+// decide reads an optional {"comment": "..."} and records the decision
+func (h *ApprovalHandler) decide(w http.ResponseWriter, r *http.Request, decide func(ctx context.Context, taskID string, decision *models.ApprovalDecision) (*models.TaskApproval, error)) {
+	var input models.ApprovalDecision
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	approval, err := decide(r.Context(), mux.Vars(r)["id"], &input)
+	if err != nil {
+		respondApprovalError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, approval)
+}
+
+// respondApprovalError maps approval errors to their status, using fallback for the rest
+func respondApprovalError(w http.ResponseWriter, err error, fallback int) {
+	status := fallback
+	switch {
+	case errors.Is(err, models.ErrApprovalNotFound), errors.Is(err, models.ErrTaskNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrApprovalNotPending):
+		status = http.StatusConflict
+	case errors.Is(err, service.ErrNotApprover), errors.Is(err, auth.ErrResourceNotOwned):
+		status = http.StatusForbidden
+	}
+	http.Error(w, err.Error(), status)
+}
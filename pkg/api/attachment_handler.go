@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/service"
+)
+
+// attachmentFormMemory is how much of a multipart upload is held in memory before spilling to disk
+const attachmentFormMemory = 8 << 20
+
+type AttachmentHandler struct {
+	service service.AttachmentService
+	maxSize int64
+}
+
+// NewAttachmentHandler creates an attachment handler accepting uploads up to maxSize bytes
+func NewAttachmentHandler(service service.AttachmentService, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{service: service, maxSize: maxSize}
+}
+
+// RegisterRoutes registers attachment routes on the tasks router
+func (h *AttachmentHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/attachments", h.UploadAttachment).Methods(http.MethodPost)
+	router.HandleFunc("/{id}/attachments", h.ListAttachments).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/attachments/{attachment_id}/download", h.DownloadAttachment).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/attachments/{attachment_id}", h.DeleteAttachment).Methods(http.MethodDelete)
+}
+
+// UploadAttachment accepts a multipart/form-data upload with the content in the "file" field
+func (h *AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID := vars["id"]
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// Leave room for the multipart framing around the file
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxSize+1<<20)
+	if err := r.ParseMultipartForm(attachmentFormMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, service.ErrAttachmentTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing file field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	created, err := h.service.Upload(r.Context(), taskID, user.ActorID, header.Filename, header.Header.Get("Content-Type"), header.Size, file)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrAttachmentTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, created)
+}
+
+func (h *AttachmentHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	attachments, err := h.service.ListAttachments(r.Context(), vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"attachments": attachments,
+	})
+}
+
+// DownloadAttachment returns a short-lived presigned URL for the attachment content
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	url, expiresAt, err := h.service.DownloadURL(r.Context(), vars["id"], vars["attachment_id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	// Presigned URLs expire, so the response must not be cached
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"url":        url,
+		"expires_at": expiresAt,
+	})
+}
+
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := h.service.DeleteAttachment(r.Context(), vars["id"], vars["attachment_id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
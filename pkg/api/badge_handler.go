@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/service"
+)
+
+type BadgeHandler struct {
+	service service.BadgeService
+}
+
+func NewBadgeHandler(service service.BadgeService) *BadgeHandler {
+	return &BadgeHandler{service: service}
+}
+
+// RegisterRoutes registers the badge route; must be called before the task routes so
+// "/badges" is not captured by "/{id}"
+func (h *BadgeHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/badges", h.GetBadges).Methods(http.MethodGet)
+}
+
+// GetBadges returns the task counts the UI polls. "Today" is in the time zone given by
+// ?tz= (an IANA name such as Europe/Berlin), UTC by default.
+func (h *BadgeHandler) GetBadges(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	loc := time.UTC
+	if tz := r.URL.Query().Get("tz"); tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			http.Error(w, "invalid tz", http.StatusBadRequest)
+			return
+		}
+	}
+
+	badges, err := h.service.GetBadges(r.Context(), user.ID, loc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Counts include "assigned to me", so they must never be served from the shared cache
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, badges)
+}
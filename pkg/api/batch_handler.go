@@ -0,0 +1,142 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// credentialHeaders identify the caller; sub-requests always carry the batch's own
+var credentialHeaders = []string{"Authorization", "Cookie", auth.APIKeyHeader, auth.GuestLinkHeader}
+
+type BatchHandler struct {
+	api         http.Handler
+	maxRequests int
+}
+
+// NewBatchHandler creates a batch handler running sub-requests through api, the full
+// API handler, so each passes the same authentication, limits and caching as on its own
+func NewBatchHandler(api http.Handler, maxRequests int) *BatchHandler {
+	return &BatchHandler{api: api, maxRequests: maxRequests}
+}
+
+// RegisterRoutes registers the batch route on the v1 router
+func (h *BatchHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc(strings.TrimPrefix(models.BatchPath, "/api/v1"), h.Batch).Methods(http.MethodPost)
+}
+
+// Batch runs sub-requests in order and returns their responses in the same order
+func (h *BatchHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, models.MaxBatchBytes)
+	var batch models.Batch
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := batch.Validate(h.maxRequests); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	responses := make([]*models.BatchResponse, 0, len(batch.Requests))
+	for _, request := range batch.Requests {
+		if r.Context().Err() != nil {
+			return
+		}
+		responses = append(responses, h.run(r, request))
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"responses": responses,
+	})
+}
+
+// run serves one sub-request with the batch request's headers and client address
+func (h *BatchHandler) run(r *http.Request, request *models.BatchRequest) *models.BatchResponse {
+	sub, err := http.NewRequestWithContext(r.Context(), request.Method, request.Path, bytes.NewReader(request.Body))
+	if err != nil {
+		return &models.BatchResponse{Status: http.StatusBadRequest, Body: batchBody("", []byte(err.Error()))}
+	}
+	sub.Header = r.Header.Clone()
+	sub.Header.Del("Content-Length")
+	sub.Header.Del("Content-Type")
+	if len(request.Body) > 0 {
+		sub.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range request.Headers {
+		if !isCredentialHeader(name) {
+			sub.Header.Set(name, value)
+		}
+	}
+	sub.RemoteAddr = r.RemoteAddr
+	sub.Host = r.Host
+	sub.TLS = r.TLS
+
+	recorder := &batchRecorder{header: make(http.Header)}
+	h.api.ServeHTTP(recorder, sub)
+
+	response := &models.BatchResponse{
+		Status:  recorder.status,
+		Headers: make(map[string]string, len(recorder.header)),
+		Body:    batchBody(recorder.header.Get("Content-Type"), recorder.body.Bytes()),
+	}
+	if response.Status == 0 {
+		response.Status = http.StatusOK
+	}
+	for name := range recorder.header {
+		response.Headers[name] = recorder.header.Get(name)
+	}
+	return response
+}
+
+func isCredentialHeader(name string) bool {
+	for _, header := range credentialHeaders {
+		if textproto.CanonicalMIMEHeaderKey(name) == textproto.CanonicalMIMEHeaderKey(header) {
+			return true
+		}
+	}
+	return false
+}
+
+// batchBody embeds a JSON response as is and any other response as a JSON string
+func batchBody(contentType string, body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if strings.HasPrefix(contentType, "application/json") && json.Valid(body) {
+		return body
+	}
+	encoded, _ := json.Marshal(string(body))
+	return encoded
+}
+
+// batchRecorder buffers a sub-request's response
+type batchRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func (r *batchRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *batchRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *batchRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
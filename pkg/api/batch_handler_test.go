@@ -0,0 +1,189 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/middleware"
+	"sample/task-management-system/pkg/models"
+)
+
+// echoAPI answers every request with its method, path and headers
+func echoAPI(calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"method":  r.Method,
+			"path":    r.URL.Path,
+			"headers": r.Header,
+		})
+	})
+}
+
+// serveBatch posts requests to the batch handler with the headers
+func serveBatch(t *testing.T, handler *BatchHandler, headers map[string]string, requests ...*models.BatchRequest) *httptest.ResponseRecorder {
+	t.Helper()
+	body, err := json.Marshal(models.Batch{Requests: requests})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, models.BatchPath, bytes.NewReader(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	handler.Batch(rec, req)
+	return rec
+}
+
+// batchResponses decodes the sub-responses of a batch
+func batchResponses(t *testing.T, rec *httptest.ResponseRecorder) []*models.BatchResponse {
+	t.Helper()
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body struct {
+		Responses []*models.BatchResponse `json:"responses"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	return body.Responses
+}
+
+func TestBatch_KeepsBatchCredentials(t *testing.T) {
+	calls := 0
+	handler := NewBatchHandler(echoAPI(&calls), 5)
+
+	rec := serveBatch(t, handler, map[string]string{"Authorization": "Bearer batch-token"}, &models.BatchRequest{
+		Method: http.MethodGet,
+		Path:   "/api/v1/tasks",
+		Headers: map[string]string{
+			"authorization":      "Bearer other-token",
+			"Cookie":             "session=other",
+			auth.APIKeyHeader:    "other-key",
+			auth.GuestLinkHeader: "other-link",
+			"X-Request-Label":    "kept",
+		},
+	})
+
+	responses := batchResponses(t, rec)
+	require.Len(t, responses, 1)
+	var echoed struct {
+		Headers http.Header `json:"headers"`
+	}
+	require.NoError(t, json.Unmarshal(responses[0].Body, &echoed))
+	assert.Equal(t, "Bearer batch-token", echoed.Headers.Get("Authorization"))
+	assert.Empty(t, echoed.Headers.Get("Cookie"))
+	assert.Empty(t, echoed.Headers.Get(auth.APIKeyHeader))
+	assert.Empty(t, echoed.Headers.Get(auth.GuestLinkHeader))
+	assert.Equal(t, "kept", echoed.Headers.Get("X-Request-Label"))
+}
+
+func TestBatch_RejectsNestedBatches(t *testing.T) {
+	for _, path := range []string{
+		"/api/v1/batch",
+		"/api/v1/batch/",
+		"/api/v1/%62atch",
+		"/api/v1/%62%61%74%63%68?x=1",
+		"//api/v1/batch",
+		"/api//v1//batch",
+		"/api/v1/tasks/../batch",
+		"/api/v1/./batch",
+	} {
+		t.Run(path, func(t *testing.T) {
+			calls := 0
+			rec := serveBatch(t, NewBatchHandler(echoAPI(&calls), 5), nil,
+				&models.BatchRequest{Method: http.MethodGet, Path: "/api/v1/tasks"},
+				&models.BatchRequest{Method: http.MethodPost, Path: path})
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), "batches can't be nested")
+			assert.Zero(t, calls, "nothing runs")
+		})
+	}
+}
+
+func TestBatch_RespondsInRequestOrder(t *testing.T) {
+	calls := 0
+	rec := serveBatch(t, NewBatchHandler(echoAPI(&calls), 5), nil,
+		&models.BatchRequest{Method: http.MethodGet, Path: "/api/v1/tasks/3"},
+		&models.BatchRequest{Method: "delete", Path: "/api/v1/tasks/1"},
+		&models.BatchRequest{Method: http.MethodPut, Path: "/api/v1/tasks/2", Body: json.RawMessage(`{"title":"x"}`)})
+
+	responses := batchResponses(t, rec)
+	require.Len(t, responses, 3)
+	for i, want := range []string{"GET /api/v1/tasks/3", "DELETE /api/v1/tasks/1", "PUT /api/v1/tasks/2"} {
+		var echoed struct {
+			Method string `json:"method"`
+			Path   string `json:"path"`
+		}
+		require.NoError(t, json.Unmarshal(responses[i].Body, &echoed))
+		assert.Equal(t, want, echoed.Method+" "+echoed.Path)
+		assert.Equal(t, http.StatusOK, responses[i].Status)
+	}
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+}
+
+func TestBatch_MaxRequests(t *testing.T) {
+	calls := 0
+	handler := NewBatchHandler(echoAPI(&calls), 2)
+	request := &models.BatchRequest{Method: http.MethodGet, Path: "/api/v1/tasks"}
+
+	rec := serveBatch(t, handler, nil, request, request, request)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "at most 2 requests")
+	assert.Zero(t, calls)
+
+	assert.Len(t, batchResponses(t, serveBatch(t, handler, nil, request, request)), 2)
+	assert.Equal(t, 2, calls)
+
+	rec = serveBatch(t, handler, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestBatch_AuthenticatesAndLimitsEachRequest(t *testing.T) {
+	tm := auth.NewTokenManager([]byte("test-secret"), "test-issuer")
+	viewer, err := tm.CreateTokenPair(context.Background(), "user-1", []string{"viewer"})
+	require.NoError(t, err)
+	admin, err := tm.CreateTokenPair(context.Background(), "admin-1", []string{"admin"})
+	require.NoError(t, err)
+
+	calls := 0
+	authenticated := auth.AuthMiddleware(auth.AuthConfig{
+		JWTSecret:    []byte("test-secret"),
+		Validation:   tm.ValidationOptions(),
+		AllowedRoles: auth.DefaultRoles,
+	})(echoAPI(&calls))
+	// Three requests are allowed, then the limiter runs dry
+	limited := middleware.NewLocalRateLimiter(rate.Limit(0), 3).RateLimit(authenticated)
+	handler := NewBatchHandler(limited, 5)
+
+	rec := serveBatch(t, handler, map[string]string{"Authorization": "Bearer " + viewer.AccessToken},
+		&models.BatchRequest{Method: http.MethodGet, Path: "/api/v1/tasks"},
+		&models.BatchRequest{Method: http.MethodPost, Path: "/api/v1/tasks", Body: json.RawMessage(`{}`)},
+		&models.BatchRequest{Method: http.MethodPost, Path: "/api/v1/tasks", Body: json.RawMessage(`{}`),
+			Headers: map[string]string{"Authorization": "Bearer " + admin.AccessToken}},
+		&models.BatchRequest{Method: http.MethodGet, Path: "/api/v1/tasks"})
+
+	responses := batchResponses(t, rec)
+	require.Len(t, responses, 4)
+	assert.Equal(t, http.StatusOK, responses[0].Status)
+	assert.Equal(t, http.StatusForbidden, responses[1].Status, "viewers can't write")
+	assert.Equal(t, http.StatusForbidden, responses[2].Status, "a sub-request can't bring its own token")
+	assert.Equal(t, http.StatusTooManyRequests, responses[3].Status)
+	assert.Equal(t, 1, calls)
+
+	// Without credentials every sub-request is refused on its own
+	limited = middleware.NewLocalRateLimiter(rate.Limit(0), 3).RateLimit(authenticated)
+	rec = serveBatch(t, NewBatchHandler(limited, 5), nil,
+		&models.BatchRequest{Method: http.MethodGet, Path: "/api/v1/tasks"},
+		&models.BatchRequest{Method: http.MethodGet, Path: "/api/v1/tasks",
+			Headers: map[string]string{"Authorization": "Bearer " + admin.AccessToken}})
+	for _, response := range batchResponses(t, rec) {
+		assert.Equal(t, http.StatusUnauthorized, response.Status)
+	}
+	assert.Equal(t, 1, calls)
+}
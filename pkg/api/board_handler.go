@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type BoardHandler struct {
+	service service.BoardService
+}
+
+func NewBoardHandler(service service.BoardService) *BoardHandler {
+	return &BoardHandler{service: service}
+}
+
+// RegisterRoutes registers the board routes on the tasks router
+func (h *BoardHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/position", h.MoveTask).Methods(http.MethodPatch)
+}
+
+// MoveTask places the task in a board column, e.g.
+// {"status": "in_progress", "after_id": "...", "before_id": "..."}
+func (h *BoardHandler) MoveTask(w http.ResponseWriter, r *http.Request) {
+	var move models.TaskMove
+	if err := json.NewDecoder(r.Body).Decode(&move); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.service.MoveTask(r.Context(), mux.Vars(r)["id"], &move)
+	if errors.Is(err, service.ErrTaskBlocked) || errors.Is(err, service.ErrApprovalRequired) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type CacheHandler struct {
+	service service.CacheAdminService
+}
+
+func NewCacheHandler(service service.CacheAdminService) *CacheHandler {
+	return &CacheHandler{service: service}
+}
+
+// RegisterRoutes registers the cache routes on the admin cache router
+func (h *CacheHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/invalidate", h.Invalidate).Methods(http.MethodPost)
+	router.HandleFunc("/stats", h.GetStats).Methods(http.MethodGet)
+}
+
+// Invalidate deletes the cache keys matching a pattern or tag
+func (h *CacheHandler) Invalidate(w http.ResponseWriter, r *http.Request) {
+	var input models.CacheInvalidation
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := input.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Invalidate(r.Context(), &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+func (h *CacheHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.service.Stats(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/challenge"
+)
+
+type ChallengeHandler struct {
+	pow *challenge.ProofOfWork
+}
+
+func NewChallengeHandler(pow *challenge.ProofOfWork) *ChallengeHandler {
+	return &ChallengeHandler{pow: pow}
+}
+
+// RegisterRoutes registers the proof-of-work challenge route
+func (h *ChallengeHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/challenge", h.IssueChallenge).Methods(http.MethodGet)
+}
+
+func (h *ChallengeHandler) IssueChallenge(w http.ResponseWriter, r *http.Request) {
+	result, err := h.pow.Issue()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
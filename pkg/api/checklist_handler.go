@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type ChecklistHandler struct {
+	service service.ChecklistService
+}
+
+func NewChecklistHandler(service service.ChecklistService) *ChecklistHandler {
+	return &ChecklistHandler{service: service}
+}
+
+// RegisterRoutes registers the checklist routes on the tasks router
+func (h *ChecklistHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/checklist", h.ListItems).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/checklist", h.AddItem).Methods(http.MethodPost)
+	// Registered before "/{item_id}" so "order" isn't taken for an item ID
+	router.HandleFunc("/{id}/checklist/order", h.ReorderItems).Methods(http.MethodPut)
+	router.HandleFunc("/{id}/checklist/toggle", h.ToggleItems).Methods(http.MethodPost)
+	router.HandleFunc("/{id}/checklist/{item_id}", h.UpdateItem).Methods(http.MethodPut)
+	router.HandleFunc("/{id}/checklist/{item_id}", h.DeleteItem).Methods(http.MethodDelete)
+}
+
+// ListItems lists a task's checklist in order along with its progress
+func (h *ChecklistHandler) ListItems(w http.ResponseWriter, r *http.Request) {
+	items, err := h.service.ListItems(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondChecklist(w, items)
+}
+
+func (h *ChecklistHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	var input models.ChecklistItemInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.AddItem(r.Context(), mux.Vars(r)["id"], &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, item)
+}
+
+func (h *ChecklistHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var update models.ChecklistItemUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.UpdateItem(r.Context(), vars["id"], vars["item_id"], &update)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, item)
+}
+
+func (h *ChecklistHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := h.service.DeleteItem(r.Context(), vars["id"], vars["item_id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReorderItems puts the checklist in the order of "item_ids"
+func (h *ChecklistHandler) ReorderItems(w http.ResponseWriter, r *http.Request) {
+	var order models.ChecklistOrder
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.service.ReorderItems(r.Context(), mux.Vars(r)["id"], &order)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondChecklist(w, items)
+}
+
+// ToggleItems checks or unchecks the items in "item_ids", or every item with "all"
+func (h *ChecklistHandler) ToggleItems(w http.ResponseWriter, r *http.Request) {
+	var toggle models.ChecklistToggle
+	if err := json.NewDecoder(r.Body).Decode(&toggle); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.service.ToggleItems(r.Context(), mux.Vars(r)["id"], &toggle)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondChecklist(w, items)
+}
+
+// respondChecklist writes a whole checklist with its progress
+func respondChecklist(w http.ResponseWriter, items []*models.ChecklistItem) {
+	done := 0
+	for _, item := range items {
+		if item.Done {
+			done++
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":    items,
+		"progress": models.NewChecklistProgress(len(items), done),
+	})
+}
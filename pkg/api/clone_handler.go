@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type CloneHandler struct {
+	service service.CloneService
+}
+
+func NewCloneHandler(service service.CloneService) *CloneHandler {
+	return &CloneHandler{service: service}
+}
+
+// RegisterRoutes registers the clone route on a "/tasks/{id}/clone" router
+func (h *CloneHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.CloneTask).Methods(http.MethodPost)
+}
+
+// CloneTask copies the task into a new pending task owned by the caller. The body is
+// optional, e.g. {"subtasks": true, "checklist": true}.
+func (h *CloneHandler) CloneTask(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var input models.TaskClone
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	task, err := h.service.CloneTask(r.Context(), mux.Vars(r)["id"], user.ID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, task)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type DeadLetterHandler struct {
+	service service.DeadLetterService
+}
+
+func NewDeadLetterHandler(service service.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{service: service}
+}
+
+// RegisterRoutes registers the admin dead letter routes
+func (h *DeadLetterHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.ListDeadLetters).Methods(http.MethodGet)
+	router.HandleFunc("/{type}/{id}", h.GetDeadLetter).Methods(http.MethodGet)
+	router.HandleFunc("/{type}/{id}/requeue", h.Requeue).Methods(http.MethodPost)
+}
+
+// ListDeadLetters lists the jobs given up on, optionally of one ?type
+func (h *DeadLetterHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, _ := strconv.Atoi(query.Get("page"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	letters, total, err := h.service.ListDeadLetters(r.Context(), models.JobType(query.Get("type")), page, limit)
+	if errors.Is(err, service.ErrUnknownJobType) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"dead_letters": letters,
+		"total":        total,
+		"page":         page,
+		"limit":        limit,
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// GetDeadLetter shows a dead letter along with its job
+func (h *DeadLetterHandler) GetDeadLetter(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	letter, err := h.service.GetDeadLetter(r.Context(), models.JobType(vars["type"]), vars["id"])
+	if errors.Is(err, service.ErrUnknownJobType) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, letter)
+}
+
+// Requeue queues a dead-lettered job again
+func (h *DeadLetterHandler) Requeue(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	letter, err := h.service.Requeue(r.Context(), models.JobType(vars["type"]), vars["id"])
+	switch {
+	case errors.Is(err, service.ErrUnknownJobType):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, models.ErrJobNotRequeueable):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, letter)
+}
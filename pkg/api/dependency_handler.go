@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/service"
+)
+
+type DependencyHandler struct {
+	service service.DependencyService
+}
+
+func NewDependencyHandler(service service.DependencyService) *DependencyHandler {
+	return &DependencyHandler{service: service}
+}
+
+// RegisterRoutes registers the blocked-by routes on the tasks router
+func (h *DependencyHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/blockers", h.ListBlockers).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/blockers", h.AddBlocker).Methods(http.MethodPost)
+	router.HandleFunc("/{id}/blockers/{blocker_id}", h.RemoveBlocker).Methods(http.MethodDelete)
+	router.HandleFunc("/{id}/blocked", h.ListBlocked).Methods(http.MethodGet)
+}
+
+func (h *DependencyHandler) ListBlockers(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	tasks, err := h.service.ListBlockers(r.Context(), vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	// Blocker status changes without this task changing, so don't serve it from cache
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"blockers": tasks,
+	})
+}
+
+func (h *DependencyHandler) AddBlocker(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var input struct {
+		BlockerID string `json:"blocker_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.AddBlocker(r.Context(), vars["id"], input.BlockerID)
+	if errors.Is(err, service.ErrDependencyCycle) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *DependencyHandler) RemoveBlocker(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := h.service.RemoveBlocker(r.Context(), vars["id"], vars["blocker_id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *DependencyHandler) ListBlocked(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	tasks, err := h.service.ListBlocked(r.Context(), vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"blocked": tasks,
+	})
+}
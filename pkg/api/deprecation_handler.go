@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/service"
+)
+
+type DeprecationHandler struct {
+	service service.DeprecationService
+}
+
+func NewDeprecationHandler(service service.DeprecationService) *DeprecationHandler {
+	return &DeprecationHandler{service: service}
+}
+
+// RegisterRoutes registers the deprecation report route on the admin deprecations router
+func (h *DeprecationHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.GetReport).Methods(http.MethodGet)
+}
+
+// GetReport lists the declared deprecations, soonest sunset first, with the clients
+// still using them
+func (h *DeprecationHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.service.Report(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"deprecations": reports})
+}
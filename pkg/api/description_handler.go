@@ -0,0 +1,66 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/service"
+)
+
+type DescriptionHandler struct {
+	service service.DescriptionService
+}
+
+func NewDescriptionHandler(service service.DescriptionService) *DescriptionHandler {
+	return &DescriptionHandler{service: service}
+}
+
+// RegisterRoutes registers the description history routes on the tasks router
+func (h *DescriptionHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/description/versions", h.ListVersions).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/description/versions/{version}/restore", h.RestoreVersion).Methods(http.MethodPost)
+}
+
+// ListVersions lists a task's description versions, newest first, with line diffs
+func (h *DescriptionHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
+	versions, err := h.service.ListVersions(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"versions": versions,
+	})
+}
+
+// RestoreVersion makes an earlier version the task's description
+func (h *DescriptionHandler) RestoreVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	version, err := strconv.Atoi(vars["version"])
+	if err != nil {
+		http.Error(w, "version must be a number", http.StatusBadRequest)
+		return
+	}
+
+	var editorID string
+	if user, err := auth.GetUserFromContext(r.Context()); err == nil {
+		editorID = user.ID
+	}
+
+	task, err := h.service.RestoreVersion(r.Context(), vars["id"], version, editorID)
+	if errors.Is(err, service.ErrCurrentVersion) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
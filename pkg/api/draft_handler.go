@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type DraftHandler struct {
+	service service.TaskService
+}
+
+func NewDraftHandler(service service.TaskService) *DraftHandler {
+	return &DraftHandler{service: service}
+}
+
+// RegisterRoutes registers draft routes on the tasks router; they must come before "/{id}".
+// The route variable is not "id" because a new draft has no task for the ownership
+// check to find; the service checks that the caller owns the draft instead.
+func (h *DraftHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/drafts/{draft_id}", h.SaveDraft).Methods(http.MethodPut)
+	router.HandleFunc("/drafts/{draft_id}/publish", h.PublishDraft).Methods(http.MethodPost)
+}
+
+// SaveDraft creates or replaces a draft; repeating the same request is safe, so clients
+// can autosave with it
+func (h *DraftHandler) SaveDraft(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var draft models.TaskDraft
+	if err := json.NewDecoder(r.Body).Decode(&draft); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	draft.OwnerID = user.ID
+	draft.OrgID = user.OrgID
+
+	task, err := h.service.SaveDraft(r.Context(), mux.Vars(r)["draft_id"], &draft)
+	if errors.Is(err, service.ErrNotDraft) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
+
+// PublishDraft validates a draft as a new task and makes it visible to everyone
+func (h *DraftHandler) PublishDraft(w http.ResponseWriter, r *http.Request) {
+	task, err := h.service.PublishDraft(r.Context(), mux.Vars(r)["draft_id"])
+	if errors.Is(err, service.ErrNotDraft) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type EventArchiveHandler struct {
+	service service.EventArchiveService
+}
+
+func NewEventArchiveHandler(service service.EventArchiveService) *EventArchiveHandler {
+	return &EventArchiveHandler{service: service}
+}
+
+// RegisterRoutes registers the admin event archive routes
+func (h *EventArchiveHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/replay", h.Replay).Methods(http.MethodPost)
+}
+
+// Replay sends a time range of archived events to a sink again
+func (h *EventArchiveHandler) Replay(w http.ResponseWriter, r *http.Request) {
+	var request models.ReplayRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Replay(r.Context(), &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
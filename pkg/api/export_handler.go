@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type ExportHandler struct {
+	service service.ExportService
+}
+
+func NewExportHandler(service service.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// RegisterRoutes registers the export routes on the exports router
+func (h *ExportHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.RequestExport).Methods(http.MethodPost)
+	router.HandleFunc("/{id}", h.GetExport).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/download", h.DownloadExport).Methods(http.MethodGet)
+}
+
+// RequestExport queues an export and returns its job, to be polled until it completes
+func (h *ExportHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if user.IsGuest() {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var input models.ExportRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if input.Kind == models.ExportReport && !auth.HasRole(user, "admin") {
+		http.Error(w, "only admins can export reports", http.StatusForbidden)
+		return
+	}
+
+	job, err := h.service.RequestExport(r.Context(), user.ID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/exports/"+job.ID)
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// GetExport reports an export's progress and, once it completes, where to download it
+func (h *ExportHandler) GetExport(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	job, err := h.service.GetExport(r.Context(), mux.Vars(r)["id"], user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, job)
+}
+
+// DownloadExport serves a completed export, redirecting to S3 when the file is kept there
+func (h *ExportHandler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	job, content, err := h.service.Download(r.Context(), mux.Vars(r)["id"], user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if content == nil && job.StorageKey != "" {
+		http.Redirect(w, r, job.DownloadURL, http.StatusFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", job.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.FileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/audit"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// impersonationTTL keeps support sessions short
+const impersonationTTL = 15 * time.Minute
+
+type ImpersonationHandler struct {
+	tokens *auth.TokenManager
+	audit  audit.Logger
+}
+
+func NewImpersonationHandler(tokens *auth.TokenManager, audit audit.Logger) *ImpersonationHandler {
+	return &ImpersonationHandler{tokens: tokens, audit: audit}
+}
+
+// RegisterRoutes registers the impersonation route
+func (h *ImpersonationHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/impersonate/{user_id}", h.Impersonate).Methods(http.MethodPost)
+}
+
+func (h *ImpersonationHandler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	subjectID := vars["user_id"]
+
+	var request struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Reason == "" {
+		http.Error(w, "reason is required", http.StatusBadRequest)
+		return
+	}
+
+	actor, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Impersonation tokens can't be used to start another impersonation
+	if actor.IsImpersonated() {
+		http.Error(w, "cannot impersonate while impersonating", http.StatusForbidden)
+		return
+	}
+	if subjectID == actor.ID {
+		http.Error(w, "cannot impersonate yourself", http.StatusBadRequest)
+		return
+	}
+
+	token, err := h.tokens.CreateImpersonationToken(r.Context(), actor.ID, subjectID, impersonationTTL)
+	if errors.Is(err, models.ErrUserNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.audit.Record(r.Context(), &models.AuditEvent{
+		Action:     "impersonation.start",
+		ActorID:    actor.ID,
+		SubjectID:  subjectID,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		StatusCode: http.StatusCreated,
+		RemoteAddr: r.RemoteAddr,
+		Metadata:   map[string]string{"reason": request.Reason},
+	})
+
+	respondJSON(w, http.StatusCreated, token)
+}
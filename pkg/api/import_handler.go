@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type ImportHandler struct {
+	service service.ImportService
+}
+
+func NewImportHandler(service service.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// RegisterRoutes registers the import routes on the imports router
+func (h *ImportHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.StartImport).Methods(http.MethodPost)
+	router.HandleFunc("/{id}", h.GetImport).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/failures", h.ListFailures).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/cancel", h.CancelImport).Methods(http.MethodPost)
+}
+
+// StartImport accepts a multipart/form-data upload with the file in the "file" field and
+// the "source", "project_key", "default_due_date" and "columns" options, and queues the import
+func (h *ImportHandler) StartImport(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if user.IsGuest() {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	// Leave room for the multipart framing around the file
+	r.Body = http.MaxBytesReader(w, r.Body, models.MaxImportBytes+1<<20)
+	if err := r.ParseMultipartForm(attachmentFormMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "file is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing file field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, models.MaxImportBytes+1))
+	if err != nil {
+		http.Error(w, "Invalid file", http.StatusBadRequest)
+		return
+	}
+	if len(content) > models.MaxImportBytes {
+		http.Error(w, "file is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	options := models.ImportOptions{
+		Source:     models.ImportSource(r.FormValue("source")),
+		ProjectKey: r.FormValue("project_key"),
+	}
+	if value := r.FormValue("columns"); value != "" {
+		if err := json.Unmarshal([]byte(value), &options.Columns); err != nil {
+			http.Error(w, "columns must be a JSON object of header names to task fields", http.StatusBadRequest)
+			return
+		}
+	}
+	if value := r.FormValue("default_due_date"); value != "" {
+		dueDate, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "default_due_date must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		options.DefaultDueDate = &dueDate
+	}
+
+	job, err := h.service.StartImport(r.Context(), user.ID, header.Filename, &options, content)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/imports/"+job.ID)
+	respondJSON(w, http.StatusAccepted, job)
+}
+
+// GetImport reports an import's progress and how many rows were imported or failed
+func (h *ImportHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	job, err := h.service.GetImport(r.Context(), mux.Vars(r)["id"], user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, job)
+}
+
+// ListFailures lists the rows that could not be imported and why
+func (h *ImportHandler) ListFailures(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	failures, total, err := h.service.ListFailures(r.Context(), mux.Vars(r)["id"], user.ID, page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"failures": failures,
+		"total":    total,
+	})
+}
+
+// CancelImport stops an import; the tasks it already created are kept
+func (h *ImportHandler) CancelImport(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	job, err := h.service.CancelImport(r.Context(), mux.Vars(r)["id"], user.ID)
+	if errors.Is(err, service.ErrImportFinished) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type InvitationHandler struct {
+	service service.InvitationService
+}
+
+func NewInvitationHandler(service service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{service: service}
+}
+
+// RegisterOrgRoutes registers invitation and member management on the organizations router
+func (h *InvitationHandler) RegisterOrgRoutes(router *mux.Router) {
+	router.HandleFunc("/{org_id}/invitations", h.ListInvitations).Methods(http.MethodGet)
+	router.HandleFunc("/{org_id}/invitations", h.Invite).Methods(http.MethodPost)
+	router.HandleFunc("/{org_id}/invitations/{id}", h.RevokeInvitation).Methods(http.MethodDelete)
+	router.HandleFunc("/{org_id}/members", h.ListMembers).Methods(http.MethodGet)
+}
+
+// RegisterRoutes registers invitation acceptance, open to any signed-in user
+func (h *InvitationHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/accept", h.AcceptInvitation).Methods(http.MethodPost)
+}
+
+func (h *InvitationHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["org_id"]
+	if _, ok := authorizeOrg(w, r, orgID); !ok {
+		return
+	}
+
+	invitations, err := h.service.ListInvitations(r.Context(), orgID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"invitations": invitations,
+	})
+}
+
+// Invite emails an invitation to join the organization; the token is only in the email
+func (h *InvitationHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["org_id"]
+	user, ok := authorizeOrg(w, r, orgID)
+	if !ok {
+		return
+	}
+
+	var input models.InvitationCreate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	invitation, err := h.service.Invite(r.Context(), orgID, user.ActorID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, invitation)
+}
+
+func (h *InvitationHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orgID := vars["org_id"]
+	if _, ok := authorizeOrg(w, r, orgID); !ok {
+		return
+	}
+
+	if err := h.service.RevokeInvitation(r.Context(), orgID, vars["id"]); err != nil {
+		respondInvitationError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *InvitationHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["org_id"]
+	if _, ok := authorizeOrg(w, r, orgID); !ok {
+		return
+	}
+
+	members, err := h.service.ListMembers(r.Context(), orgID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"members": members,
+	})
+}
+
+// AcceptInvitation makes the caller a member of the organization the token invites to
+func (h *InvitationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var input models.InvitationAccept
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	membership, err := h.service.AcceptInvitation(r.Context(), input.Token, user.ID)
+	if err != nil {
+		respondInvitationError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, membership)
+}
+
+// respondInvitationError maps invitation errors to their status, using fallback for the rest
+func respondInvitationError(w http.ResponseWriter, err error, fallback int) {
+	status := fallback
+	switch {
+	case errors.Is(err, models.ErrInvitationNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrInvitationUnavailable):
+		status = http.StatusGone
+	}
+	http.Error(w, err.Error(), status)
+}
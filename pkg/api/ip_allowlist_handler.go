@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type IPAllowlistHandler struct {
+	service service.IPAllowlistService
+}
+
+func NewIPAllowlistHandler(service service.IPAllowlistService) *IPAllowlistHandler {
+	return &IPAllowlistHandler{service: service}
+}
+
+// RegisterRoutes registers organization allowlist routes
+func (h *IPAllowlistHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{org_id}/ip-allowlist", h.ListEntries).Methods(http.MethodGet)
+	router.HandleFunc("/{org_id}/ip-allowlist", h.AddEntry).Methods(http.MethodPost)
+	router.HandleFunc("/{org_id}/ip-allowlist/{id}", h.RemoveEntry).Methods(http.MethodDelete)
+}
+
+// authorizeOrg checks that the user may manage the organization's allowlist.
+// Platform admins manage any organization; org admins only their own.
+func authorizeOrg(w http.ResponseWriter, r *http.Request, orgID string) (auth.User, bool) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return auth.User{}, false
+	}
+
+	if !auth.HasRole(user, "admin") && (!auth.HasRole(user, "org_admin") || user.OrgID != orgID) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return auth.User{}, false
+	}
+	return user, true
+}
+
+func (h *IPAllowlistHandler) ListEntries(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["org_id"]
+	if _, ok := authorizeOrg(w, r, orgID); !ok {
+		return
+	}
+
+	entries, err := h.service.ListEntries(r.Context(), orgID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"entries": entries,
+	})
+}
+
+func (h *IPAllowlistHandler) AddEntry(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["org_id"]
+	user, ok := authorizeOrg(w, r, orgID)
+	if !ok {
+		return
+	}
+
+	var input models.IPAllowlistEntryCreate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.service.AddEntry(r.Context(), orgID, user.ActorID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, entry)
+}
+
+func (h *IPAllowlistHandler) RemoveEntry(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	orgID := vars["org_id"]
+	if _, ok := authorizeOrg(w, r, orgID); !ok {
+		return
+	}
+
+	if err := h.service.RemoveEntry(r.Context(), orgID, vars["id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type MergeHandler struct {
+	service service.MergeService
+}
+
+func NewMergeHandler(service service.MergeService) *MergeHandler {
+	return &MergeHandler{service: service}
+}
+
+// RegisterRoutes registers the merge route on the tasks router
+func (h *MergeHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/merge", h.MergeTask).Methods(http.MethodPost)
+}
+
+// MergeTask folds a duplicate into the task, e.g. {"source_id": "..."}
+func (h *MergeHandler) MergeTask(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var input models.TaskMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	merge, err := h.service.MergeTask(r.Context(), vars["id"], &input)
+	if errors.Is(err, auth.ErrResourceNotOwned) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, merge)
+}
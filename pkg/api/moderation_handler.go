@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type ModerationHandler struct {
+	service service.ModerationService
+}
+
+func NewModerationHandler(service service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{service: service}
+}
+
+// RegisterRoutes registers the admin review queue routes
+func (h *ModerationHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/flags", h.ListFlags).Methods(http.MethodGet)
+	router.HandleFunc("/flags/{id}", h.ReviewFlag).Methods(http.MethodPut)
+}
+
+func (h *ModerationHandler) ListFlags(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, _ := strconv.Atoi(query.Get("page"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	status := models.FlagStatus(query.Get("status"))
+
+	flags, total, err := h.service.ListFlags(r.Context(), status, page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Echo the pagination the service applied
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	response := map[string]interface{}{
+		"flags": flags,
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+func (h *ModerationHandler) ReviewFlag(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var review models.FlagReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.service.ReviewFlag(r.Context(), id, user.ID, &review)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
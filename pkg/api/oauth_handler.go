@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type OAuthHandler struct {
+	service service.OAuthService
+}
+
+func NewOAuthHandler(service service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{service: service}
+}
+
+// RegisterRoutes registers the public OAuth2 token route
+func (h *OAuthHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/token", h.Token).Methods(http.MethodPost)
+}
+
+// RegisterAdminRoutes registers client management routes
+func (h *OAuthHandler) RegisterAdminRoutes(router *mux.Router) {
+	router.HandleFunc("/clients", h.CreateClient).Methods(http.MethodPost)
+	router.HandleFunc("/clients", h.ListClients).Methods(http.MethodGet)
+	router.HandleFunc("/clients/{id}", h.RevokeClient).Methods(http.MethodDelete)
+}
+
+// Token implements the client_credentials grant (RFC 6749 section 4.4) and token exchange (RFC 8693).
+// Clients authenticate with HTTP Basic auth or client_id/client_secret form fields.
+func (h *OAuthHandler) Token(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	if err := r.ParseForm(); err != nil {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_request", "malformed form body")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID = r.PostForm.Get("client_id")
+		clientSecret = r.PostForm.Get("client_secret")
+	}
+
+	var token *auth.ClientToken
+	var err error
+	switch r.PostForm.Get("grant_type") {
+	case "client_credentials":
+		token, err = h.service.IssueClientToken(r.Context(), clientID, clientSecret, r.PostForm.Get("scope"))
+	case auth.GrantTypeTokenExchange:
+		token, err = h.service.ExchangeToken(r.Context(), clientID, clientSecret,
+			r.PostForm.Get("subject_token"), r.PostForm.Get("subject_token_type"), r.PostForm.Get("scope"))
+	default:
+		respondOAuthError(w, http.StatusBadRequest, "unsupported_grant_type", "only client_credentials and token-exchange are supported")
+		return
+	}
+
+	switch {
+	case errors.Is(err, auth.ErrInvalidClient):
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+		respondOAuthError(w, http.StatusUnauthorized, "invalid_client", err.Error())
+		return
+	case errors.Is(err, auth.ErrUnauthorizedClient):
+		respondOAuthError(w, http.StatusBadRequest, "unauthorized_client", err.Error())
+		return
+	case errors.Is(err, auth.ErrUnsupportedTokenType):
+		respondOAuthError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	case errors.Is(err, auth.ErrInvalidGrant):
+		respondOAuthError(w, http.StatusBadRequest, "invalid_grant", err.Error())
+		return
+	case errors.Is(err, auth.ErrInvalidScope):
+		respondOAuthError(w, http.StatusBadRequest, "invalid_scope", err.Error())
+		return
+	case err != nil:
+		// The endpoint is public, so internal errors are only logged
+		log.Printf("OAuth token request failed: %v", err)
+		respondOAuthError(w, http.StatusInternalServerError, "server_error", "token could not be issued")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, token)
+}
+
+func (h *OAuthHandler) CreateClient(w http.ResponseWriter, r *http.Request) {
+	var input models.OAuthClientCreate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	client, secret, err := h.service.CreateClient(r.Context(), &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The secret is only ever shown in this response
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"client":        client,
+		"client_secret": secret,
+	})
+}
+
+func (h *OAuthHandler) ListClients(w http.ResponseWriter, r *http.Request) {
+	clients, err := h.service.ListClients(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"clients": clients,
+	})
+}
+
+func (h *OAuthHandler) RevokeClient(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	if err := h.service.RevokeClient(r.Context(), id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func respondOAuthError(w http.ResponseWriter, status int, code, description string) {
+	respondJSON(w, status, map[string]string{
+		"error":             code,
+		"error_description": description,
+	})
+}
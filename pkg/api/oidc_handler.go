@@ -0,0 +1,102 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"path"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+// oidcStateCookie binds a sign-in's state to the browser that started it
+const oidcStateCookie = "oidc_state"
+
+type OIDCHandler struct {
+	service service.OIDCService
+	// secureCookies marks the state cookie Secure, for APIs served over HTTPS
+	secureCookies bool
+}
+
+func NewOIDCHandler(service service.OIDCService, secureCookies bool) *OIDCHandler {
+	return &OIDCHandler{service: service, secureCookies: secureCookies}
+}
+
+// RegisterRoutes registers the public OpenID Connect sign-in routes
+func (h *OIDCHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.ListProviders).Methods(http.MethodGet)
+	router.HandleFunc("/{provider}/login", h.Login).Methods(http.MethodGet)
+	router.HandleFunc("/{provider}/callback", h.Callback).Methods(http.MethodGet)
+}
+
+func (h *OIDCHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"providers": h.service.Providers(),
+	})
+}
+
+// Login redirects the user to the provider to sign in, with the sign-in's state in a
+// cookie only the provider's callback receives
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	authURL, state, err := h.service.BeginLogin(r.Context(), mux.Vars(r)["provider"])
+	if errors.Is(err, models.ErrOIDCProviderNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, h.stateCookie(r, state, int(models.OIDCLoginTTL.Seconds())))
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback completes the sign-in the provider redirected back from and responds with a
+// token pair for the user
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	// The state cookie is used once, whatever the outcome
+	var boundState string
+	if cookie, err := r.Cookie(oidcStateCookie); err == nil {
+		boundState = cookie.Value
+	}
+	http.SetCookie(w, h.stateCookie(r, "", -1))
+	query := r.URL.Query()
+	// The user declined, or the provider refused the sign-in
+	if reason := query.Get("error"); reason != "" {
+		http.Error(w, "sign-in was not completed: "+reason, http.StatusUnauthorized)
+		return
+	}
+
+	tokens, err := h.service.CompleteLogin(r.Context(), mux.Vars(r)["provider"], query.Get("state"), boundState, query.Get("code"))
+	if errors.Is(err, auth.ErrOIDCLogin) {
+		http.Error(w, auth.ErrOIDCLogin.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		log.Printf("OIDC sign-in with %s failed: %v", mux.Vars(r)["provider"], err)
+		http.Error(w, "sign-in failed", http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+// stateCookie returns the state cookie for the provider of the login or callback route r
+// is for. It is Lax rather than Strict so the provider's redirect back carries it.
+func (h *OIDCHandler) stateCookie(r *http.Request, state string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     path.Dir(r.URL.Path),
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   h.secureCookies,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
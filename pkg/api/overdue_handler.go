@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/service"
+)
+
+type OverdueHandler struct {
+	service service.OverdueTaskService
+}
+
+func NewOverdueHandler(service service.OverdueTaskService) *OverdueHandler {
+	return &OverdueHandler{service: service}
+}
+
+// RegisterRoutes registers the overdue route; must be called before the task routes so
+// "/overdue" is not captured by "/{id}"
+func (h *OverdueHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/overdue", h.ListOverdue).Methods(http.MethodGet)
+}
+
+// ListOverdue lists open tasks past their due date, filtered like the task list by
+// ?assignee=, ?priority= and ?tags=
+func (h *OverdueHandler) ListOverdue(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, _ := strconv.Atoi(query.Get("page"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	view, err := taskView(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter := repository.TaskFilter{
+		Priority:   models.TaskPriority(query.Get("priority")),
+		AssigneeID: query.Get("assignee"),
+		Sort:       query.Get("sort"),
+		Order:      query.Get("order"),
+		Page:       page,
+		Limit:      limit,
+	}
+	if value := query.Get("tags"); value != "" {
+		tags, err := models.NormalizeTags(strings.Split(value, ","))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.Tags = tags
+	}
+
+	// "me" resolves to the authenticated user
+	if filter.AssigneeID == "me" {
+		user, err := auth.GetUserFromContext(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		filter.AssigneeID = user.ID
+	}
+
+	tasks, total, err := h.service.ListOverdue(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Overdue depends on the current time, so responses must not be cached
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"tasks": representTasks(tasks, view),
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/notify"
+	"sample/task-management-system/pkg/service"
+)
+
+// unsubscribePage asks to confirm an unsubscribe link, and confirms it was applied when Done
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="robots" content="noindex"><title>Unsubscribe</title></head>
+<body>
+{{if .Done}}<p>You will no longer receive the deadlines digest. You can turn it back on in your preferences.</p>
+{{else}}<form method="post">
+<p>Stop receiving the deadlines digest?</p>
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit" name="confirm" value="1">Unsubscribe</button>
+</form>
+{{end}}</body>
+</html>
+`))
+
+type PreferencesHandler struct {
+	service service.PreferencesService
+}
+
+func NewPreferencesHandler(service service.PreferencesService) *PreferencesHandler {
+	return &PreferencesHandler{service: service}
+}
+
+// RegisterRoutes registers the preferences routes on the users router
+func (h *PreferencesHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/me/preferences", h.GetPreferences).Methods(http.MethodGet)
+	router.HandleFunc("/me/preferences", h.UpdatePreferences).Methods(http.MethodPut)
+	// Linked from digest emails, so it works without signing in. GET only asks for
+	// confirmation, since mail scanners and link prefetchers follow links; the POST is
+	// the confirmation or the one-click List-Unsubscribe request (RFC 8058) mail clients send.
+	router.HandleFunc("/preferences/unsubscribe", h.ConfirmUnsubscribe).Methods(http.MethodGet)
+	router.HandleFunc("/preferences/unsubscribe", h.Unsubscribe).Methods(http.MethodPost)
+}
+
+func (h *PreferencesHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	preferences, err := h.service.GetPreferences(r.Context(), user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, preferences)
+}
+
+func (h *PreferencesHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var input models.PreferencesUpdate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	preferences, err := h.service.UpdatePreferences(r.Context(), user.ID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preferences)
+}
+
+// ConfirmUnsubscribe renders a page asking to confirm the unsubscribe link; it changes nothing
+func (h *PreferencesHandler) ConfirmUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	// The token is a credential; keep it out of shared caches and referrers
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		http.Error(w, notify.ErrInvalidUnsubscribeToken.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	unsubscribePage.Execute(w, map[string]interface{}{"Token": token})
+}
+
+// Unsubscribe turns off the digest of the user named by the signed token, which is in the
+// query of one-click requests and in the form of the confirmation page
+func (h *PreferencesHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	preferences, err := h.service.Unsubscribe(r.Context(), r.FormValue("token"))
+	if errors.Is(err, notify.ErrInvalidUnsubscribeToken) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.PostFormValue("confirm") != "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		unsubscribePage.Execute(w, map[string]interface{}{"Done": true})
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"digest": preferences.Digest,
+	})
+}
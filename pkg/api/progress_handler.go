@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+// defaultChartDays is the date range of charts requested without one
+const defaultChartDays = 30
+
+type ProgressHandler struct {
+	service service.ProgressService
+}
+
+func NewProgressHandler(service service.ProgressService) *ProgressHandler {
+	return &ProgressHandler{service: service}
+}
+
+// RegisterRoutes registers the chart routes on the projects router
+func (h *ProgressHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/burnup", h.Burnup).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/burndown", h.Burndown).Methods(http.MethodGet)
+}
+
+// Burnup returns the project's completed tasks and scope per day
+func (h *ProgressHandler) Burnup(w http.ResponseWriter, r *http.Request) {
+	projectKey, from, to, ok := chartRequest(w, r)
+	if !ok {
+		return
+	}
+
+	chart, err := h.service.Burnup(r.Context(), projectKey, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, chart)
+}
+
+// Burndown returns the project's open tasks per day
+func (h *ProgressHandler) Burndown(w http.ResponseWriter, r *http.Request) {
+	projectKey, from, to, ok := chartRequest(w, r)
+	if !ok {
+		return
+	}
+
+	chart, err := h.service.Burndown(r.Context(), projectKey, from, to)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, chart)
+}
+
+// chartRequest parses the project and the ?from= and ?to= dates of a chart, which
+// default to the last 30 days up to today (UTC)
+func chartRequest(w http.ResponseWriter, r *http.Request) (string, time.Time, time.Time, bool) {
+	projectKey, ok := projectFromRequest(w, r)
+	if !ok {
+		return "", time.Time{}, time.Time{}, false
+	}
+
+	query := r.URL.Query()
+	to := time.Now().UTC()
+	if value := query.Get("to"); value != "" {
+		parsed, err := time.Parse(models.ChartDateLayout, value)
+		if err != nil {
+			http.Error(w, "to must be a date, e.g. 2024-03-04", http.StatusBadRequest)
+			return "", time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, 1-defaultChartDays)
+	if value := query.Get("from"); value != "" {
+		parsed, err := time.Parse(models.ChartDateLayout, value)
+		if err != nil {
+			http.Error(w, "from must be a date, e.g. 2024-03-04", http.StatusBadRequest)
+			return "", time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days < 1 || days > models.MaxChartDays {
+		http.Error(w, fmt.Sprintf("from must not be after to, and the range may span at most %d days", models.MaxChartDays), http.StatusBadRequest)
+		return "", time.Time{}, time.Time{}, false
+	}
+	return projectKey, from, to, true
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type ProjectSettingsHandler struct {
+	service service.StaleTaskService
+}
+
+func NewProjectSettingsHandler(service service.StaleTaskService) *ProjectSettingsHandler {
+	return &ProjectSettingsHandler{service: service}
+}
+
+// RegisterRoutes registers the project settings routes on the projects router
+func (h *ProjectSettingsHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/settings", h.GetSettings).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/settings", h.UpdateSettings).Methods(http.MethodPut)
+}
+
+func (h *ProjectSettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	projectKey, err := models.NormalizeProjectKey(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.service.GetProjectSettings(r.Context(), projectKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// UpdateSettings replaces the project's settings, e.g. {"stale_after_days": 7}
+func (h *ProjectSettingsHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var input models.ProjectSettingsUpdate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.service.UpdateProjectSettings(r.Context(), mux.Vars(r)["id"], user.ActorID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
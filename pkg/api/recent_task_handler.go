@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/service"
+)
+
+// defaultRecentTasks is the number of recent tasks returned when no limit is given
+const defaultRecentTasks = 10
+
+type RecentTaskHandler struct {
+	service service.RecentTaskService
+}
+
+func NewRecentTaskHandler(service service.RecentTaskService) *RecentTaskHandler {
+	return &RecentTaskHandler{service: service}
+}
+
+// RegisterRoutes registers recent task routes on the users router
+func (h *RecentTaskHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/me/recent-tasks", h.ListRecentTasks).Methods(http.MethodGet)
+}
+
+// ListRecentTasks lists the tasks the caller viewed most recently
+func (h *RecentTaskHandler) ListRecentTasks(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	limit := defaultRecentTasks
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil || limit < 1 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	view, err := taskView(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tasks, err := h.service.RecentTasks(r.Context(), user.ID, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"tasks": representRecentTasks(tasks, view),
+	})
+}
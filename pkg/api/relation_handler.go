@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type RelationHandler struct {
+	service service.RelationService
+}
+
+func NewRelationHandler(service service.RelationService) *RelationHandler {
+	return &RelationHandler{service: service}
+}
+
+// RegisterRoutes registers the task relation routes on the tasks router
+func (h *RelationHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/relations", h.ListRelations).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/relations", h.AddRelation).Methods(http.MethodPost)
+	router.HandleFunc("/{id}/relations/{type}/{related_id}", h.RemoveRelation).Methods(http.MethodDelete)
+}
+
+func (h *RelationHandler) ListRelations(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	relations, err := h.service.ListRelations(r.Context(), vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	// Related tasks change without this task changing, so don't serve it from cache
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"relations": relations,
+	})
+}
+
+func (h *RelationHandler) AddRelation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var input models.TaskRelationCreate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.AddRelation(r.Context(), vars["id"], &input)
+	if errors.Is(err, service.ErrRelationConflict) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RelationHandler) RemoveRelation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	err := h.service.RemoveRelation(r.Context(), vars["id"], models.RelationType(vars["type"]), vars["related_id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
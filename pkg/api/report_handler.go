@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type ReportHandler struct {
+	service service.ReportService
+}
+
+func NewReportHandler(service service.ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// RegisterRoutes registers the saved report routes on the admin reports router
+func (h *ReportHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.CreateReport).Methods(http.MethodPost)
+	router.HandleFunc("", h.ListReports).Methods(http.MethodGet)
+	router.HandleFunc("/{id}", h.GetReport).Methods(http.MethodGet)
+	router.HandleFunc("/{id}", h.UpdateReport).Methods(http.MethodPut)
+	router.HandleFunc("/{id}", h.DeleteReport).Methods(http.MethodDelete)
+	router.HandleFunc("/{id}/run", h.RunReport).Methods(http.MethodGet)
+}
+
+func (h *ReportHandler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var input models.ReportInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.CreateReport(r.Context(), user.ActorID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, report)
+}
+
+func (h *ReportHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.service.ListReports(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"reports": reports,
+	})
+}
+
+func (h *ReportHandler) GetReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.service.GetReport(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+func (h *ReportHandler) UpdateReport(w http.ResponseWriter, r *http.Request) {
+	var input models.ReportInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.UpdateReport(r.Context(), mux.Vars(r)["id"], &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+func (h *ReportHandler) DeleteReport(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteReport(r.Context(), mux.Vars(r)["id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunReport runs a saved report and returns its rows as JSON, or as a CSV download
+// with ?format=csv
+func (h *ReportHandler) RunReport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		http.Error(w, "format must be json or csv", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RunReport(r.Context(), mux.Vars(r)["id"])
+	if errors.Is(err, service.ErrReportTimeout) {
+		http.Error(w, err.Error(), http.StatusGatewayTimeout)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if format == "json" {
+		respondJSON(w, http.StatusOK, result)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"report-%s.csv\"", result.ReportID))
+	w.Header().Set("X-Report-Truncated", strconv.FormatBool(result.Truncated))
+	w.WriteHeader(http.StatusOK)
+
+	if err := service.WriteReportCSV(w, result); err != nil {
+		log.Printf("Failed to write report %s: %v", result.ReportID, err)
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type RoleHandler struct {
+	service service.RoleService
+}
+
+func NewRoleHandler(service service.RoleService) *RoleHandler {
+	return &RoleHandler{service: service}
+}
+
+// RegisterRoutes registers role management on the admin roles router
+func (h *RoleHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.ListRoles).Methods(http.MethodGet)
+	router.HandleFunc("/{name}", h.GetRole).Methods(http.MethodGet)
+	router.HandleFunc("/{name}", h.SaveRole).Methods(http.MethodPut)
+	router.HandleFunc("/{name}", h.DeleteRole).Methods(http.MethodDelete)
+}
+
+// ListRoles lists the roles in effect, built-in or stored
+func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.service.ListRoles(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"roles": roles})
+}
+
+func (h *RoleHandler) GetRole(w http.ResponseWriter, r *http.Request) {
+	role, err := h.service.GetRole(r.Context(), mux.Vars(r)["name"])
+	if err != nil {
+		respondRoleError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, role)
+}
+
+// SaveRole creates a role or replaces its permissions
+func (h *RoleHandler) SaveRole(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var input models.RoleInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.service.SaveRole(r.Context(), mux.Vars(r)["name"], user.ActorID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, role)
+}
+
+// DeleteRole deletes a stored role, restoring the built-in one of the same name
+func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteRole(r.Context(), mux.Vars(r)["name"]); err != nil {
+		respondRoleError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondRoleError maps role errors to their status, using fallback for the rest
+func respondRoleError(w http.ResponseWriter, err error, fallback int) {
+	status := fallback
+	if errors.Is(err, models.ErrRoleNotFound) {
+		status = http.StatusNotFound
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type SearchHandler struct {
+	service service.SearchService
+}
+
+func NewSearchHandler(service service.SearchService) *SearchHandler {
+	return &SearchHandler{service: service}
+}
+
+// RegisterRoutes registers search routes; must be called before the task
+// routes so "/search" is not captured by "/{id}"
+func (h *SearchHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/search", h.SearchTasks).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/related", h.RelatedTasks).Methods(http.MethodGet)
+}
+
+func (h *SearchHandler) SearchTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	status := models.TaskStatus(r.URL.Query().Get("status"))
+	language := models.SearchLanguage(r.URL.Query().Get("language"))
+	view, err := taskView(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.SearchTasks(r.Context(), query, status, language, page, limit)
+	if err != nil {
+		// Only a bad request is the client's fault; backend outages are not
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrSearchQueryRequired) || errors.Is(err, service.ErrInvalidSearchLanguage) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > service.MaxSearchResults {
+		limit = service.MaxSearchResults
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"tasks":  representTasks(result.Tasks, view),
+		"total":  result.Total,
+		"facets": result.Facets,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+func (h *SearchHandler) RelatedTasks(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	view, err := taskView(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tasks, err := h.service.RelatedTasks(r.Context(), id, limit)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrTaskNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"tasks": representTasks(tasks, view),
+	})
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/service"
+)
+
+type SecurityHandler struct {
+	service service.SecurityService
+}
+
+func NewSecurityHandler(service service.SecurityService) *SecurityHandler {
+	return &SecurityHandler{service: service}
+}
+
+// RegisterRoutes registers security routes on the users router
+func (h *SecurityHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/me/security-events", h.ListSecurityEvents).Methods(http.MethodGet)
+}
+
+// ListSecurityEvents lists suspicious activity detected on the caller's account
+func (h *SecurityHandler) ListSecurityEvents(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	events, total, err := h.service.ListSecurityEvents(r.Context(), user.ID, page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"events": events,
+		"total":  total,
+	})
+}
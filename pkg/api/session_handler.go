@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+type SessionHandler struct {
+	tokens *auth.TokenManager
+}
+
+func NewSessionHandler(tokens *auth.TokenManager) *SessionHandler {
+	return &SessionHandler{tokens: tokens}
+}
+
+// RegisterRoutes registers the session routes on the auth router
+func (h *SessionHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/refresh", h.Refresh).Methods(http.MethodPost)
+	router.HandleFunc("/logout", h.Logout).Methods(http.MethodPost)
+}
+
+// Refresh exchanges the refresh token in the body for a new token pair. The route is
+// public, since the access token has usually expired by the time a client refreshes.
+func (h *SessionHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.RefreshToken == "" {
+		http.Error(w, "refresh_token is required", http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.tokens.RefreshTokens(r.Context(), request.RefreshToken)
+	switch {
+	case errors.Is(err, auth.ErrTokenReused), errors.Is(err, auth.ErrTokenRevoked):
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrExpiredToken),
+		errors.Is(err, auth.ErrInvalidSignature), errors.Is(err, auth.ErrInvalidIssuer),
+		errors.Is(err, auth.ErrInvalidAudience), errors.Is(err, models.ErrUserNotFound):
+		http.Error(w, "invalid refresh token", http.StatusUnauthorized)
+		return
+	case err != nil:
+		log.Printf("Failed to refresh tokens: %v", err)
+		http.Error(w, "tokens could not be refreshed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+// Logout revokes the access token the request was made with and the refresh token in the
+// body, if any. The body is optional, but without the refresh token the session can be
+// refreshed until the refresh token expires.
+func (h *SessionHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.Claims)
+	if !ok {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	// Only our own tokens carry an ID to revoke; API keys are revoked under /apikeys
+	if auth.ViaAPIKey(r.Context()) || claims.ID == "" {
+		http.Error(w, "only sessions signed in with a token can log out", http.StatusBadRequest)
+		return
+	}
+
+	var request struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err := h.tokens.Logout(r.Context(), claims, request.RefreshToken)
+	switch {
+	case errors.Is(err, auth.ErrRevocationDisabled):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+		return
+	case errors.Is(err, auth.ErrInvalidToken):
+		http.Error(w, "invalid refresh token", http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
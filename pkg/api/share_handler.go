@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+	"sample/task-management-system/pkg/share"
+)
+
+type ShareHandler struct {
+	service service.ShareService
+	baseURL string
+}
+
+// NewShareHandler creates a share handler; baseURL is the public origin used in share URLs
+func NewShareHandler(service service.ShareService, baseURL string) *ShareHandler {
+	return &ShareHandler{service: service, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// RegisterRoutes registers share management routes on the tasks router
+func (h *ShareHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/share", h.CreateShare).Methods(http.MethodPost)
+	router.HandleFunc("/{id}/shares", h.ListShares).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/shares/{share_id}", h.RevokeShare).Methods(http.MethodDelete)
+}
+
+// RegisterSharedRoutes registers the account-less routes reached through share URLs
+func (h *ShareHandler) RegisterSharedRoutes(router *mux.Router) {
+	router.Use(share.Middleware(h.service, models.SharePermissionRead))
+	router.HandleFunc("/{token}", h.GetSharedTask).Methods(http.MethodGet)
+}
+
+func (h *ShareHandler) CreateShare(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID := vars["id"]
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var input models.TaskShareCreate
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	created, token, err := h.service.CreateShare(r.Context(), taskID, user.ActorID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The token is only ever shown in this response
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"share": created,
+		"token": token,
+		"url":   h.baseURL + "/api/v1/shared/" + token,
+	})
+}
+
+func (h *ShareHandler) ListShares(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	taskID := vars["id"]
+
+	shares, err := h.service.ListShares(r.Context(), taskID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"shares": shares,
+	})
+}
+
+func (h *ShareHandler) RevokeShare(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := h.service.RevokeShare(r.Context(), vars["id"], vars["share_id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ShareHandler) GetSharedTask(w http.ResponseWriter, r *http.Request) {
+	sh, ok := share.FromContext(r.Context())
+	if !ok {
+		http.Error(w, share.ErrInvalidShareToken.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	task, err := h.service.GetSharedTask(r.Context(), sh)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"task":       task,
+		"permission": sh.Permission,
+		"expires_at": sh.ExpiresAt,
+	})
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type SLAHandler struct {
+	service service.SLAService
+}
+
+func NewSLAHandler(service service.SLAService) *SLAHandler {
+	return &SLAHandler{service: service}
+}
+
+// RegisterRoutes registers the SLA policy routes on the tasks router
+func (h *SLAHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/sla", h.SetPolicy).Methods(http.MethodPut)
+	router.HandleFunc("/{id}/sla", h.RemovePolicy).Methods(http.MethodDelete)
+}
+
+// SetPolicy replaces the task's SLA policy, e.g. {"respond_within": "4h", "resolve_within": "72h"}
+func (h *SLAHandler) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var input models.SLAPolicy
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.service.SetPolicy(r.Context(), vars["id"], &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
+
+func (h *SLAHandler) RemovePolicy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	task, err := h.service.RemovePolicy(r.Context(), vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
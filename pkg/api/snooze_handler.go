@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type SnoozeHandler struct {
+	service service.SnoozeService
+}
+
+func NewSnoozeHandler(service service.SnoozeService) *SnoozeHandler {
+	return &SnoozeHandler{service: service}
+}
+
+// RegisterRoutes registers the snooze routes on the tasks router
+func (h *SnoozeHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/snooze", h.Snooze).Methods(http.MethodPost)
+	router.HandleFunc("/{id}/snooze", h.Wake).Methods(http.MethodDelete)
+}
+
+func (h *SnoozeHandler) Snooze(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var input models.TaskSnooze
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.service.Snooze(r.Context(), vars["id"], &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
+
+func (h *SnoozeHandler) Wake(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	task, err := h.service.Wake(r.Context(), vars["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
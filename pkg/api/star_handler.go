@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/service"
+)
+
+type StarHandler struct {
+	service service.StarService
+}
+
+func NewStarHandler(service service.StarService) *StarHandler {
+	return &StarHandler{service: service}
+}
+
+// RegisterRoutes registers the star routes on a "/tasks/{id}/star" router
+func (h *StarHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.StarTask).Methods(http.MethodPost)
+	router.HandleFunc("", h.UnstarTask).Methods(http.MethodDelete)
+}
+
+func (h *StarHandler) StarTask(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.StarTask(r.Context(), mux.Vars(r)["id"], user.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *StarHandler) UnstarTask(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.UnstarTask(r.Context(), mux.Vars(r)["id"], user.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
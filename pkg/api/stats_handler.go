@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/metrics"
+)
+
+type StatsHandler struct {
+	tokenCache auth.TokenCache
+}
+
+// NewStatsHandler creates a stats handler; tokenCache may be nil when token caching is disabled
+func NewStatsHandler(tokenCache auth.TokenCache) *StatsHandler {
+	return &StatsHandler{tokenCache: tokenCache}
+}
+
+// RegisterRoutes registers the stats route on the admin stats router
+func (h *StatsHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.GetStats).Methods(http.MethodGet)
+}
+
+// GetStats reports in-process request latency percentiles per route, telemetry health,
+// and response and token cache effectiveness
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	response := map[string]interface{}{
+		"latency": map[string]interface{}{
+			"window": metrics.LatencyWindow.String(),
+			"routes": metrics.LatencyStats(),
+		},
+		"telemetry":      metrics.Telemetry(),
+		"response_cache": metrics.CacheLookups(),
+	}
+	if h.tokenCache != nil {
+		response["token_cache"] = h.tokenCache.Stats()
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type SyncHandler struct {
+	service service.SyncService
+}
+
+func NewSyncHandler(service service.SyncService) *SyncHandler {
+	return &SyncHandler{service: service}
+}
+
+// RegisterRoutes registers the sync routes on the sync router
+func (h *SyncHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.Sync).Methods(http.MethodGet)
+	router.HandleFunc("", h.Push).Methods(http.MethodPost)
+}
+
+// Sync returns task changes since the cursor in the since parameter
+func (h *SyncHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	page, err := h.service.Sync(r.Context(), query.Get("since"), limit)
+	if errors.Is(err, models.ErrInvalidSyncCursor) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, page)
+}
+
+// Push applies edits made offline and reports what became of each
+func (h *SyncHandler) Push(w http.ResponseWriter, r *http.Request) {
+	var push models.SyncPush
+	if err := json.NewDecoder(r.Body).Decode(&push); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.service.Push(r.Context(), &push)
+	if errors.Is(err, auth.ErrUserNotFound) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"results": results,
+	})
+}
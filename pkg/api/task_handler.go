@@ -2,29 +2,74 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
 	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
 	"sample/task-management-system/pkg/service"
 )
 
 type TaskHandler struct {
 	service service.TaskService
+	// prefetchHints is how many of a list's first tasks are hinted with a Link header
+	prefetchHints int
+	// exportPacing bounds how long a streamed export may hold its database cursor
+	exportPacing models.ExportPacing
+	// preferences supplies the caller's page size for lists without a limit; nil uses
+	// the default
+	preferences service.PreferencesService
 }
 
 func NewTaskHandler(service service.TaskService) *TaskHandler {
 	return &TaskHandler{service: service}
 }
 
+// WithPrefetchHints makes task lists hint at the detail URLs of their first n tasks, so
+// clients can fetch them early and the cache middleware warms them; 0 turns hints off
+func (h *TaskHandler) WithPrefetchHints(n int) *TaskHandler {
+	h.prefetchHints = n
+	return h
+}
+
+// WithExportPacing cuts streamed exports short, with a cursor to resume from, once they
+// reach the pacing limits. A single write blocking for longer than MaxStall fails, which
+// disconnects a client that stopped reading.
+func (h *TaskHandler) WithExportPacing(pacing models.ExportPacing) *TaskHandler {
+	h.exportPacing = pacing
+	return h
+}
+
+// WithPreferences makes task lists without a limit return the caller's preferred page size
+func (h *TaskHandler) WithPreferences(preferences service.PreferencesService) *TaskHandler {
+	h.preferences = preferences
+	return h
+}
+
 // RegisterRoutes registers all task-related routes
 func (h *TaskHandler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("", h.CreateTask).Methods(http.MethodPost)
 	router.HandleFunc("", h.ListTasks).Methods(http.MethodGet)
+	router.HandleFunc("/export", h.ExportTasks).Methods(http.MethodGet)
+	router.HandleFunc("/by-key/{key}", h.GetTaskByKey).Methods(http.MethodGet)
+	router.HandleFunc("/by-slug/{project}/{slug}", h.GetTaskBySlug).Methods(http.MethodGet)
 	router.HandleFunc("/{id}", h.GetTask).Methods(http.MethodGet)
 	router.HandleFunc("/{id}", h.UpdateTask).Methods(http.MethodPut)
 	router.HandleFunc("/{id}", h.DeleteTask).Methods(http.MethodDelete)
+	router.HandleFunc("/{id}/subtasks", h.ListSubtasks).Methods(http.MethodGet)
+}
+
+// RegisterTagRoutes registers the tag listing on the tags router
+func (h *TaskHandler) RegisterTagRoutes(router *mux.Router) {
+	router.HandleFunc("", h.ListTags).Methods(http.MethodGet)
 }
 
 func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
@@ -34,6 +79,12 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The creator owns the task, which belongs to their organization
+	if user, err := auth.GetUserFromContext(r.Context()); err == nil {
+		task.OwnerID = user.ID
+		task.OrgID = user.OrgID
+	}
+
 	result, err := h.service.CreateTask(r.Context(), &task)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -56,6 +107,33 @@ func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, task)
 }
 
+// GetTaskByKey resolves a human-friendly key such as PROJ-123
+func (h *TaskHandler) GetTaskByKey(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	task, err := h.service.GetTaskByKey(r.Context(), vars["key"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
+
+// GetTaskBySlug resolves a pretty URL such as /by-slug/PROJ/fix-login, including
+// slugs the task had before it was renamed
+func (h *TaskHandler) GetTaskBySlug(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	task, err := h.service.GetTaskBySlug(r.Context(), vars["project"], vars["slug"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
+
 func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -66,7 +144,16 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The editor is recorded with the description version the update saves
+	if user, err := auth.GetUserFromContext(r.Context()); err == nil {
+		task.EditorID = user.ID
+	}
+
 	result, err := h.service.UpdateTask(r.Context(), id, &task)
+	if errors.Is(err, service.ErrTaskBlocked) || errors.Is(err, service.ErrApprovalRequired) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -87,31 +174,322 @@ func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// statusFilter parses a comma-separated list of statuses, e.g. "pending,in_progress"
+func statusFilter(value string) []models.TaskStatus {
+	var statuses []models.TaskStatus
+	for _, status := range strings.Split(value, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			statuses = append(statuses, models.TaskStatus(status))
+		}
+	}
+	return statuses
+}
+
 func (h *TaskHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
 	
 	page, _ := strconv.Atoi(query.Get("page"))
 	limit, _ := strconv.Atoi(query.Get("limit"))
-	status := models.TaskStatus(query.Get("status"))
+	view, err := taskView(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter, status, err := taskFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if limit < 1 {
+		limit = h.pageSize(r)
+	}
+	filter.Page = page
+	filter.Limit = limit
 
-	tasks, total, err := h.service.ListTasks(r.Context(), status, page, limit)
+	tasks, total, err := h.service.ListTasks(r.Context(), filter)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	response := map[string]interface{}{
-		"tasks": tasks,
+		"tasks": representTasks(tasks, view),
 		"total": total,
 		"page":  page,
 		"limit": limit,
 	}
 
+	h.preloadHints(w, r, tasks)
 	respondJSON(w, http.StatusOK, response)
 }
 
+// pageSize returns the caller's preferred page size, or the default when it can't be read
+func (h *TaskHandler) pageSize(r *http.Request) int {
+	if h.preferences == nil {
+		return models.DefaultPageSize
+	}
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		return models.DefaultPageSize
+	}
+	preferences, err := h.preferences.GetPreferences(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("Failed to load preferences of user %s: %v", user.ID, err)
+		return models.DefaultPageSize
+	}
+	return preferences.PageSize
+}
+
+// preloadHints adds a `Link: </api/v1/tasks/{id}>; rel=preload; as=fetch` header for
+// each of the first prefetchHints tasks
+func (h *TaskHandler) preloadHints(w http.ResponseWriter, r *http.Request, tasks []*models.Task) {
+	base := strings.TrimSuffix(r.URL.Path, "/")
+	// Guest link holders authenticate with the query string, which the hints must keep
+	var query string
+	if link := r.URL.Query().Get(auth.GuestLinkParam); link != "" {
+		query = "?" + url.Values{auth.GuestLinkParam: {link}}.Encode()
+	}
+
+	for i, task := range tasks {
+		if i == h.prefetchHints {
+			break
+		}
+		w.Header().Add("Link", fmt.Sprintf("<%s/%s%s>; rel=preload; as=fetch", base, url.PathEscape(task.ID), query))
+	}
+}
+
+// taskFilter reads the list filter from the query string, returning the status to
+// answer with when it is invalid
+func taskFilter(r *http.Request) (repository.TaskFilter, int, error) {
+	query := r.URL.Query()
+	
+	filter := repository.TaskFilter{
+		Status:     statusFilter(query.Get("status")),
+		Priority:   models.TaskPriority(query.Get("priority")),
+		AssigneeID: query.Get("assignee"),
+		TeamID:     query.Get("team"),
+		Metadata:   metadataFilter(query),
+		Sort:       query.Get("sort"),
+		Order:      query.Get("order"),
+	}
+	dueRange := []struct {
+		param  string
+		target **time.Time
+	}{
+		{"due_after", &filter.DueAfter},
+		{"due_before", &filter.DueBefore},
+	}
+	for _, bound := range dueRange {
+		if value := query.Get(bound.param); value != "" {
+			due, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, http.StatusBadRequest, fmt.Errorf("%s must be an RFC3339 time, e.g. 2024-03-04T00:00:00Z", bound.param)
+			}
+			due = due.UTC()
+			*bound.target = &due
+		}
+	}
+	if err := filter.Validate(); err != nil {
+		return filter, http.StatusBadRequest, err
+	}
+
+	if value := query.Get("tags"); value != "" {
+		tags, err := models.NormalizeTags(strings.Split(value, ","))
+		if err != nil {
+			return filter, http.StatusBadRequest, err
+		}
+		filter.Tags = tags
+	}
+
+	// "me" resolves to the authenticated user
+	if filter.AssigneeID == "me" {
+		user, err := auth.GetUserFromContext(r.Context())
+		if err != nil {
+			return filter, http.StatusUnauthorized, err
+		}
+		filter.AssigneeID = user.ID
+	}
+
+	if value := query.Get("starred"); value != "" {
+		starred, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, http.StatusBadRequest, errors.New("starred must be true or false")
+		}
+		if starred {
+			user, err := auth.GetUserFromContext(r.Context())
+			if err != nil {
+				return filter, http.StatusUnauthorized, err
+			}
+			filter.StarredBy = user.ID
+		}
+	}
+
+	if value := query.Get("stale"); value != "" {
+		stale, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, http.StatusBadRequest, errors.New("stale must be true or false")
+		}
+		filter.Stale = stale
+	}
+
+	// Snoozed tasks stay out of lists until their snooze runs out
+	switch query.Get("snoozed") {
+	case "":
+		filter.Snoozed = repository.SnoozedHidden
+	case "include":
+	case "only":
+		filter.Snoozed = repository.SnoozedOnly
+	default:
+		return filter, http.StatusBadRequest, errors.New("snoozed must be include or only")
+	}
+
+
+	return filter, http.StatusOK, nil
+}
+
+// exportCursorTrailer carries the cursor of a streamed export that was cut short
+const exportCursorTrailer = "Export-Cursor"
+
+// ExportTasks streams every task matching the list filter as CSV, JSON or NDJSON, chosen
+// with ?format=csv|json|ndjson (default csv). An export cut short by pacing ends with a
+// next_cursor, also sent in the Export-Cursor trailer, that ?cursor= resumes it from.
+func (h *TaskHandler) ExportTasks(w http.ResponseWriter, r *http.Request) {
+	filter, status, err := taskFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	cursor, err := models.ParseExportCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.Offset = cursor.Offset
+
+	format := models.ExportFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		format = models.ExportCSV
+	}
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case models.ExportCSV:
+	case models.ExportJSON:
+		contentType = "application/json"
+	case models.ExportNDJSON:
+		contentType = "application/x-ndjson"
+	default:
+		http.Error(w, "format must be csv, json or ndjson", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format))
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Trailer", exportCursorTrailer)
+	out := &exportWriter{w: w, controller: http.NewResponseController(w), timeout: h.exportPacing.MaxStall}
+	defer out.finish()
+	next, err := h.service.ExportTasks(r.Context(), filter, format, out, h.exportPacing)
+	if err != nil {
+		if !out.started {
+			w.Header().Del("Content-Disposition")
+			w.Header().Del("Trailer")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// The status is already sent, so a failure can only cut the file short
+		log.Printf("Task export failed: %v", err)
+		return
+	}
+	if next != nil {
+		w.Header().Set(exportCursorTrailer, next.String())
+	}
+}
+
+// exportWriter notes whether any of a streamed response was written, and gives every
+// write timeout to complete
+type exportWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	timeout    time.Duration
+	started    bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	e.started = true
+	if e.timeout > 0 {
+		// Writers that can't set deadlines, e.g. batch sub-requests, never block
+		if err := e.controller.SetWriteDeadline(time.Now().Add(e.timeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return 0, err
+		}
+	}
+	return e.w.Write(p)
+}
+
+// finish sends what is left of the response within the write timeout, then lifts the
+// deadline so it doesn't outlive the request on a kept-alive connection
+func (e *exportWriter) finish() {
+	if e.timeout <= 0 || !e.started {
+		return
+	}
+	e.controller.SetWriteDeadline(time.Now().Add(e.timeout))
+	e.controller.Flush()
+	e.controller.SetWriteDeadline(time.Time{})
+}
+
+// metadataParamPrefix starts list parameters filtering on a metadata key
+const metadataParamPrefix = "metadata."
+
+// metadataFilter collects ?metadata.<key>=<value> parameters
+func metadataFilter(query url.Values) map[string]string {
+	var filter map[string]string
+	for param := range query {
+		key, ok := strings.CutPrefix(param, metadataParamPrefix)
+		if !ok {
+			continue
+		}
+		if filter == nil {
+			filter = make(map[string]string)
+		}
+		filter[key] = query.Get(param)
+	}
+	return filter
+}
+
+func (h *TaskHandler) ListSubtasks(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	recursive, _ := strconv.ParseBool(r.URL.Query().Get("recursive"))
+	view, err := taskView(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tasks, err := h.service.ListSubtasks(r.Context(), id, recursive)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"tasks": representTasks(tasks, view),
+	})
+}
+
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
-} 
\ No newline at end of file
+}
+
+func (h *TaskHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.service.ListTags(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"tags": tags,
+	})
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type TaskImportHandler struct {
+	service service.TaskImportService
+}
+
+func NewTaskImportHandler(service service.TaskImportService) *TaskImportHandler {
+	return &TaskImportHandler{service: service}
+}
+
+// RegisterRoutes registers the import route on the tasks router; it must be registered
+// before "/{id}"
+func (h *TaskImportHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/import", h.ImportTasks).Methods(http.MethodPost)
+}
+
+// ImportTasks accepts a multipart/form-data upload with a CSV file in the "file" field
+// and creates its tasks right away. The optional "columns" field maps header names to
+// task fields as a JSON object, e.g. {"Name": "title", "Deadline": "due_date"}; the
+// "project_key" and "default_due_date" fields apply to rows without their own.
+func (h *TaskImportHandler) ImportTasks(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if user.IsGuest() {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	// Leave room for the multipart framing around the file
+	r.Body = http.MaxBytesReader(w, r.Body, models.MaxTaskImportBytes+1<<20)
+	if err := r.ParseMultipartForm(attachmentFormMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "file is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing file field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, models.MaxTaskImportBytes+1))
+	if err != nil {
+		http.Error(w, "Invalid file", http.StatusBadRequest)
+		return
+	}
+	if len(content) > models.MaxTaskImportBytes {
+		http.Error(w, "file is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	options := models.ImportOptions{
+		Source:     models.ImportCSV,
+		ProjectKey: r.FormValue("project_key"),
+	}
+	if value := r.FormValue("columns"); value != "" {
+		if err := json.Unmarshal([]byte(value), &options.Columns); err != nil {
+			http.Error(w, "columns must be a JSON object of header names to task fields", http.StatusBadRequest)
+			return
+		}
+	}
+	if value := r.FormValue("default_due_date"); value != "" {
+		dueDate, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			http.Error(w, "default_due_date must be an RFC 3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		options.DefaultDueDate = &dueDate
+	}
+
+	result, err := h.service.ImportTasks(r.Context(), user.ID, &options, content)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
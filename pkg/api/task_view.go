@@ -0,0 +1,78 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// TaskView is how list endpoints represent tasks, chosen with ?view=
+type TaskView string
+
+const (
+	// TaskViewFull represents tasks with every field; the default
+	TaskViewFull TaskView = "full"
+	// TaskViewLite represents tasks as liteTask, for clients on slow or metered connections
+	TaskViewLite TaskView = "lite"
+)
+
+// taskView reads ?view=, defaulting to TaskViewFull
+func taskView(r *http.Request) (TaskView, error) {
+	switch view := TaskView(r.URL.Query().Get("view")); view {
+	case "", TaskViewFull:
+		return TaskViewFull, nil
+	case TaskViewLite:
+		return view, nil
+	default:
+		return "", fmt.Errorf("invalid view %q, expected lite or full", view)
+	}
+}
+
+// liteTask is the compact representation of a task
+type liteTask struct {
+	ID      string            `json:"id"`
+	Title   string            `json:"title"`
+	Status  models.TaskStatus `json:"status"`
+	DueDate *time.Time        `json:"due_date,omitempty"` // drafts may not have one
+}
+
+func newLiteTask(task *models.Task) liteTask {
+	lite := liteTask{ID: task.ID, Title: task.Title, Status: task.Status}
+	if !task.DueDate.IsZero() {
+		due := task.DueDate
+		lite.DueDate = &due
+	}
+	return lite
+}
+
+// representTasks returns tasks as view represents them
+func representTasks(tasks []*models.Task, view TaskView) interface{} {
+	if view != TaskViewLite {
+		return tasks
+	}
+	lite := make([]liteTask, len(tasks))
+	for i, task := range tasks {
+		lite[i] = newLiteTask(task)
+	}
+	return lite
+}
+
+// liteRecentTask is the compact representation of a recently viewed task
+type liteRecentTask struct {
+	Task     liteTask  `json:"task"`
+	ViewedAt time.Time `json:"viewed_at"`
+}
+
+// representRecentTasks returns recently viewed tasks as view represents them
+func representRecentTasks(recent []*models.RecentTask, view TaskView) interface{} {
+	if view != TaskViewLite {
+		return recent
+	}
+	lite := make([]liteRecentTask, len(recent))
+	for i, viewed := range recent {
+		lite[i] = liteRecentTask{Task: newLiteTask(viewed.Task), ViewedAt: viewed.ViewedAt}
+	}
+	return lite
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+func TestTaskView(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    TaskView
+		wantErr bool
+	}{
+		{"", TaskViewFull, false},
+		{"?view=full", TaskViewFull, false},
+		{"?view=lite", TaskViewLite, false},
+		{"?view=LITE", "", true},
+		{"?view=compact", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			view, err := taskView(httptest.NewRequest(http.MethodGet, "/api/v1/tasks"+tt.query, nil))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, view)
+		})
+	}
+}
+
+func TestTaskView_InvalidViewIsBadRequest(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewSearchHandler(nil).RelatedTasks(rec, httptest.NewRequest(http.MethodGet, "/api/v1/tasks/1/related?view=compact", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `invalid view "compact"`)
+}
+
+func TestRepresentTasks(t *testing.T) {
+	due := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tasks := []*models.Task{
+		{ID: "1", Title: "Ship it", Description: "Soon", Status: models.StatusPending, Priority: models.PriorityHigh, DueDate: due},
+		{ID: "2", Title: "Draft", Status: models.StatusDraft},
+	}
+
+	// The full view is the tasks themselves
+	assert.Equal(t, tasks, representTasks(tasks, TaskViewFull))
+
+	encoded, err := json.Marshal(representTasks(tasks, TaskViewLite))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"id": "1", "title": "Ship it", "status": "pending", "due_date": "2024-06-01T12:00:00Z"},
+		{"id": "2", "title": "Draft", "status": "draft"}
+	]`, string(encoded))
+
+	encoded, err = json.Marshal(representTasks([]*models.Task{}, TaskViewLite))
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(encoded))
+}
+
+func TestRepresentRecentTasks(t *testing.T) {
+	viewedAt := time.Date(2024, 6, 2, 8, 30, 0, 0, time.UTC)
+	recent := []*models.RecentTask{
+		{Task: &models.Task{ID: "1", Title: "Ship it", Status: models.StatusInProgress}, ViewedAt: viewedAt},
+	}
+
+	assert.Equal(t, recent, representRecentTasks(recent, TaskViewFull))
+
+	encoded, err := json.Marshal(representRecentTasks(recent, TaskViewLite))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"task": {"id": "1", "title": "Ship it", "status": "in_progress"}, "viewed_at": "2024-06-02T08:30:00Z"}
+	]`, string(encoded))
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type TeamHandler struct {
+	service service.TeamService
+}
+
+func NewTeamHandler(service service.TeamService) *TeamHandler {
+	return &TeamHandler{service: service}
+}
+
+// RegisterRoutes registers the team listing and lookup
+func (h *TeamHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.ListTeams).Methods(http.MethodGet)
+	router.HandleFunc("/{id}", h.GetTeam).Methods(http.MethodGet)
+}
+
+// RegisterAdminRoutes registers team and membership management on the admin router
+func (h *TeamHandler) RegisterAdminRoutes(router *mux.Router) {
+	router.HandleFunc("", h.CreateTeam).Methods(http.MethodPost)
+	router.HandleFunc("/{id}", h.UpdateTeam).Methods(http.MethodPut)
+	router.HandleFunc("/{id}", h.DeleteTeam).Methods(http.MethodDelete)
+	router.HandleFunc("/{id}/members/{user_id}", h.AddMember).Methods(http.MethodPut)
+	router.HandleFunc("/{id}/members/{user_id}", h.RemoveMember).Methods(http.MethodDelete)
+}
+
+// RegisterTaskRoutes registers team assignment on the tasks router
+func (h *TeamHandler) RegisterTaskRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/team", h.AssignTask).Methods(http.MethodPut)
+	router.HandleFunc("/{id}/team", h.UnassignTask).Methods(http.MethodDelete)
+}
+
+func (h *TeamHandler) ListTeams(w http.ResponseWriter, r *http.Request) {
+	teams, err := h.service.ListTeams(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"teams": teams,
+	})
+}
+
+// GetTeam returns a team with its members
+func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
+	team, err := h.service.GetTeam(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		respondTeamError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, team)
+}
+
+func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var input models.TeamInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.service.CreateTeam(r.Context(), user.ID, &input)
+	if err != nil {
+		respondTeamError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, team)
+}
+
+func (h *TeamHandler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
+	var input models.TeamInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	team, err := h.service.UpdateTeam(r.Context(), mux.Vars(r)["id"], &input)
+	if err != nil {
+		respondTeamError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, team)
+}
+
+func (h *TeamHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteTeam(r.Context(), mux.Vars(r)["id"]); err != nil {
+		respondTeamError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddMember adds a user to a team, returning the team with its members
+func (h *TeamHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	team, err := h.service.AddMember(r.Context(), vars["id"], vars["user_id"], user.ID)
+	if err != nil {
+		respondTeamError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, team)
+}
+
+// RemoveMember removes a user from a team, returning the team with its remaining members
+func (h *TeamHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	team, err := h.service.RemoveMember(r.Context(), vars["id"], vars["user_id"])
+	if err != nil {
+		respondTeamError(w, err, http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, team)
+}
+
+func (h *TeamHandler) AssignTask(w http.ResponseWriter, r *http.Request) {
+	var input models.TaskTeamAssignment
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	task, err := h.service.AssignTask(r.Context(), mux.Vars(r)["id"], &input)
+	if err != nil {
+		respondTeamError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
+
+func (h *TeamHandler) UnassignTask(w http.ResponseWriter, r *http.Request) {
+	task, err := h.service.UnassignTask(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, task)
+}
+
+// respondTeamError maps team errors to their status, using fallback for the rest
+func respondTeamError(w http.ResponseWriter, err error, fallback int) {
+	status := fallback
+	switch {
+	case errors.Is(err, models.ErrTeamNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, models.ErrTeamNameTaken):
+		status = http.StatusConflict
+	}
+	http.Error(w, err.Error(), status)
+}
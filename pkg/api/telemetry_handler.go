@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+// maxTelemetryBodyBytes bounds the size of a batch of events
+const maxTelemetryBodyBytes = 256 << 10
+
+type TelemetryHandler struct {
+	service service.TelemetryService
+}
+
+func NewTelemetryHandler(service service.TelemetryService) *TelemetryHandler {
+	return &TelemetryHandler{service: service}
+}
+
+// RegisterRoutes registers the event ingestion route
+func (h *TelemetryHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/events", h.IngestEvents).Methods(http.MethodPost)
+}
+
+// RegisterOrgRoutes registers the organization telemetry settings routes
+func (h *TelemetryHandler) RegisterOrgRoutes(router *mux.Router) {
+	router.HandleFunc("/{org_id}/telemetry", h.GetSettings).Methods(http.MethodGet)
+	router.HandleFunc("/{org_id}/telemetry", h.UpdateSettings).Methods(http.MethodPut)
+}
+
+// IngestEvents accepts a batch of usage events. Events are stored in the background,
+// so the response only reports how many were accepted.
+func (h *TelemetryHandler) IngestEvents(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if user.IsGuest() {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var batch models.TelemetryBatch
+	r.Body = http.MaxBytesReader(w, r.Body, maxTelemetryBodyBytes)
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Support staff acting as the user would skew the user's analytics
+	if user.IsImpersonated() {
+		respondJSON(w, http.StatusAccepted, map[string]interface{}{"accepted": 0})
+		return
+	}
+
+	accepted, err := h.service.Ingest(r.Context(), user.ID, user.OrgID, &batch)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, map[string]interface{}{"accepted": accepted})
+}
+
+func (h *TelemetryHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["org_id"]
+	if _, ok := authorizeOrg(w, r, orgID); !ok {
+		return
+	}
+
+	settings, err := h.service.GetSettings(r.Context(), orgID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, settings)
+}
+
+func (h *TelemetryHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	orgID := mux.Vars(r)["org_id"]
+	user, ok := authorizeOrg(w, r, orgID)
+	if !ok {
+		return
+	}
+
+	var input models.TelemetrySettingsUpdate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(r.Context(), orgID, user.ActorID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type TimeHandler struct {
+	service service.TimeTrackingService
+}
+
+func NewTimeHandler(service service.TimeTrackingService) *TimeHandler {
+	return &TimeHandler{service: service}
+}
+
+// RegisterRoutes registers the time tracking routes on the tasks router
+func (h *TimeHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/time", h.GetTime).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/time", h.LogTime).Methods(http.MethodPost)
+	// Registered before "/{entry_id}" so "start" and "stop" aren't taken for entry IDs
+	router.HandleFunc("/{id}/time/start", h.StartTimer).Methods(http.MethodPost)
+	router.HandleFunc("/{id}/time/stop", h.StopTimer).Methods(http.MethodPost)
+	router.HandleFunc("/{id}/time/{entry_id}", h.DeleteEntry).Methods(http.MethodDelete)
+}
+
+// GetTime returns the task's estimate, logged and remaining time and its time entries
+func (h *TimeHandler) GetTime(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.service.GetTime(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	// Running timers change without the task changing, so don't serve this from cache
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, summary)
+}
+
+// LogTime records time spent without a timer
+func (h *TimeHandler) LogTime(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var entry models.TimeEntryLog
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	entry.UserID = user.ID
+
+	result, err := h.service.LogTime(r.Context(), mux.Vars(r)["id"], &entry)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, result)
+}
+
+// StartTimer starts the caller's timer on the task; the body with a "note" is optional
+func (h *TimeHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var timer models.TimerStart
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&timer); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+	timer.UserID = user.ID
+
+	entry, err := h.service.StartTimer(r.Context(), mux.Vars(r)["id"], &timer)
+	if errors.Is(err, service.ErrTimerRunning) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, entry)
+}
+
+// StopTimer stops the caller's running timer on the task
+func (h *TimeHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	entry, err := h.service.StopTimer(r.Context(), mux.Vars(r)["id"], user.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, entry)
+}
+
+func (h *TimeHandler) DeleteEntry(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := h.service.DeleteEntry(r.Context(), vars["id"], vars["entry_id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
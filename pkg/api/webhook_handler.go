@@ -0,0 +1,135 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type WebhookHandler struct {
+	service service.WebhookService
+}
+
+func NewWebhookHandler(service service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// RegisterRoutes registers the webhook subscription routes
+func (h *WebhookHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("", h.CreateWebhook).Methods(http.MethodPost)
+	router.HandleFunc("", h.ListWebhooks).Methods(http.MethodGet)
+	router.HandleFunc("/{id}", h.GetWebhook).Methods(http.MethodGet)
+	router.HandleFunc("/{id}", h.DeleteWebhook).Methods(http.MethodDelete)
+	router.HandleFunc("/{id}/test", h.SendTest).Methods(http.MethodPost)
+	router.HandleFunc("/{id}/deliveries", h.ListDeliveries).Methods(http.MethodGet)
+	router.HandleFunc("/{id}/deliveries/{delivery_id}/redeliver", h.Redeliver).Methods(http.MethodPost)
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var input models.WebhookCreate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	hook, secret, err := h.service.CreateWebhook(r.Context(), user.ID, &input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The secret is only ever shown in this response
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"webhook": hook,
+		"secret":  secret,
+	})
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := h.service.ListWebhooks(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"webhooks": hooks,
+	})
+}
+
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	hook, err := h.service.GetWebhook(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, hook)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteWebhook(r.Context(), mux.Vars(r)["id"]); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SendTest sends a signed webhook.test event, returning the delivery log with how the
+// receiver answered
+func (h *WebhookHandler) SendTest(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.service.SendTest(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, delivery)
+}
+
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, _ := strconv.Atoi(query.Get("page"))
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	deliveries, total, err := h.service.ListDeliveries(r.Context(), mux.Vars(r)["id"], page, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	response := map[string]interface{}{
+		"deliveries": deliveries,
+		"total":      total,
+		"page":       page,
+		"limit":      limit,
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// Redeliver sends the event of a logged delivery again, returning the new delivery log
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	delivery, err := h.service.Redeliver(r.Context(), vars["id"], vars["delivery_id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, delivery)
+}
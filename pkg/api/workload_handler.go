@@ -0,0 +1,77 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/service"
+)
+
+type WorkloadHandler struct {
+	service service.WorkloadService
+}
+
+func NewWorkloadHandler(service service.WorkloadService) *WorkloadHandler {
+	return &WorkloadHandler{service: service}
+}
+
+// RegisterRoutes registers the workload routes on the projects router
+func (h *WorkloadHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/{id}/workload", h.GetWorkload).Methods(http.MethodGet)
+}
+
+// GetWorkload summarizes a project's open tasks per assignee. With ?suggest=true it also
+// proposes reassignments; ?candidates=a,b adds assignees without open tasks to balance onto.
+func (h *WorkloadHandler) GetWorkload(w http.ResponseWriter, r *http.Request) {
+	projectKey, ok := projectFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	suggest := false
+	if value := query.Get("suggest"); value != "" {
+		var err error
+		suggest, err = strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "suggest must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+	var candidates []string
+	if value := query.Get("candidates"); value != "" {
+		candidates = strings.Split(value, ",")
+		if len(candidates) > models.MaxWorkloadCandidates {
+			http.Error(w, fmt.Sprintf("at most %d candidates may be given", models.MaxWorkloadCandidates), http.StatusBadRequest)
+			return
+		}
+	}
+
+	workload, err := h.service.GetWorkload(r.Context(), projectKey, suggest, candidates)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, workload)
+}
+
+// projectFromRequest returns the normalized project key of the route, writing an error
+// response if it is invalid or the caller is a guest limited to another project
+func projectFromRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	projectKey, err := models.NormalizeProjectKey(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	if user, err := auth.GetUserFromContext(r.Context()); err == nil && user.ProjectID != "" && !strings.EqualFold(user.ProjectID, projectKey) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return "", false
+	}
+	return projectKey, true
+}
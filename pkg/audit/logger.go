@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// Logger records audit events
+type Logger interface {
+	// Record stores an event. Failures are logged, never returned, so auditing
+	// can't break the request being audited.
+	Record(ctx context.Context, event *models.AuditEvent)
+}
+
+type logger struct {
+	repo repository.AuditRepository
+}
+
+// NewLogger creates an audit logger that persists events to repo
+func NewLogger(repo repository.AuditRepository) Logger {
+	return &logger{repo: repo}
+}
+
+// Record implements Logger. Actor and subject default to the authenticated user in ctx.
+func (l *logger) Record(ctx context.Context, event *models.AuditEvent) {
+	if user, err := auth.GetUserFromContext(ctx); err == nil {
+		if event.SubjectID == "" {
+			event.SubjectID = user.ID
+		}
+		if event.ActorID == "" {
+			event.ActorID = user.ActorID
+		}
+		// Keep the full chain when a delegated token was delegated again
+		if len(user.ActorChain) > 1 {
+			if event.Metadata == nil {
+				event.Metadata = make(map[string]string)
+			}
+			event.Metadata["actor_chain"] = strings.Join(user.ActorChain, ",")
+		}
+	}
+	if event.ActorID == "" {
+		event.ActorID = event.SubjectID
+	}
+	event.Impersonated = event.ActorID != event.SubjectID
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	if err := l.repo.Create(ctx, event); err != nil {
+		log.Printf("Failed to record audit event %s (actor: %s, subject: %s): %v",
+			event.Action, event.ActorID, event.SubjectID, err)
+	}
+}
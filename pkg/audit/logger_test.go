@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// MockAuditRepository is a mock implementation of AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, filter repository.AuditFilter) ([]*models.AuditEvent, int, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.AuditEvent), args.Int(1), args.Error(2)
+}
+
+func TestLogger_Record(t *testing.T) {
+	tests := []struct {
+		name             string
+		claims           *auth.Claims
+		event            *models.AuditEvent
+		wantActor        string
+		wantSubject      string
+		wantImpersonated bool
+	}{
+		{
+			name:        "regular user",
+			claims:      &auth.Claims{UserID: "user-1"},
+			event:       &models.AuditEvent{Action: "http.request"},
+			wantActor:   "user-1",
+			wantSubject: "user-1",
+		},
+		{
+			name:             "impersonated user",
+			claims:           &auth.Claims{UserID: "user-1", Act: &auth.Actor{Subject: "admin-1"}},
+			event:            &models.AuditEvent{Action: "http.request"},
+			wantActor:        "admin-1",
+			wantSubject:      "user-1",
+			wantImpersonated: true,
+		},
+		{
+			name:             "explicit actor and subject",
+			claims:           &auth.Claims{UserID: "admin-1"},
+			event:            &models.AuditEvent{Action: "impersonation.start", ActorID: "admin-1", SubjectID: "user-2"},
+			wantActor:        "admin-1",
+			wantSubject:      "user-2",
+			wantImpersonated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockAuditRepository)
+			repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+			ctx := context.WithValue(context.Background(), "claims", tt.claims)
+
+			NewLogger(repo).Record(ctx, tt.event)
+
+			assert.Equal(t, tt.wantActor, tt.event.ActorID)
+			assert.Equal(t, tt.wantSubject, tt.event.SubjectID)
+			assert.Equal(t, tt.wantImpersonated, tt.event.Impersonated)
+			assert.False(t, tt.event.CreatedAt.IsZero())
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLogger_RecordFailureIsNotFatal(t *testing.T) {
+	repo := new(MockAuditRepository)
+	repo.On("Create", mock.Anything, mock.Anything).Return(errors.New("database unavailable"))
+
+	assert.NotPanics(t, func() {
+		NewLogger(repo).Record(context.Background(), &models.AuditEvent{Action: "http.request", SubjectID: "user-1"})
+	})
+}
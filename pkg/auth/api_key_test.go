@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sample/task-management-system/pkg/tenant"
+)
+
+// staticAPIKeys verifies the keys in its map
+type staticAPIKeys map[string]*Claims
+
+func (k staticAPIKeys) VerifyAPIKey(ctx context.Context, key string) (*Claims, error) {
+	claims, ok := k[key]
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	return claims, nil
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, err := GenerateAPIKey()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "tms_"))
+	assert.True(t, strings.HasPrefix(key, prefix))
+	assert.Len(t, prefix, len("tms_")+8)
+
+	other, _, err := GenerateAPIKey()
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	keys := staticAPIKeys{
+		"tms_reader": {UserID: "user-1", Roles: []string{"user"}, Scope: ScopeTasksRead, OrgID: "acme"},
+	}
+	config := AuthConfig{JWTSecret: []byte("test-secret"), AllowedRoles: DefaultRoles}
+
+	var user User
+	var orgID string
+	handler := APIKeyMiddleware(keys)(AuthMiddleware(config)(RequireScopes(ScopeTasksRead)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, _ = GetUserFromContext(r.Context())
+			orgID, _ = tenant.FromContext(r.Context())
+			w.WriteHeader(http.StatusOK)
+		}))))
+
+	tests := []struct {
+		name   string
+		method string
+		key    string
+		bearer string
+		want   int
+	}{
+		{"valid key", http.MethodGet, "tms_reader", "", http.StatusOK},
+		{"unknown key", http.MethodGet, "tms_unknown", "", http.StatusUnauthorized},
+		{"key and token", http.MethodGet, "tms_reader", "Bearer token", http.StatusBadRequest},
+		{"roles still apply", http.MethodDelete, "tms_reader", "", http.StatusForbidden},
+		{"no credentials", http.MethodGet, "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, orgID = User{}, ""
+			req := httptest.NewRequest(tt.method, "/api/v1/tasks", nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			if tt.bearer != "" {
+				req.Header.Set("Authorization", tt.bearer)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.want == http.StatusOK {
+				assert.Equal(t, "user-1", user.ID)
+				assert.Equal(t, "acme", orgID)
+			}
+		})
+	}
+}
+
+func TestClaims_GrantedScopes(t *testing.T) {
+	assert.Equal(t, []string{ScopeTasksRead}, (&Claims{Roles: []string{"user"}, Scope: ScopeTasksRead}).GrantedScopes())
+	assert.Equal(t, []string{ScopeTasksRead, ScopeTasksWrite}, (&Claims{Roles: []string{"user"}, FirstParty: true}).GrantedScopes())
+	assert.Empty(t, (&Claims{Roles: []string{"user"}}).GrantedScopes())
+}
@@ -7,12 +7,24 @@ var (
 	ErrNoAuthHeader       = errors.New("no authorization header")
 	ErrInvalidAuthType    = errors.New("invalid authorization type")
 	ErrInvalidToken       = errors.New("invalid token")
+	ErrTokenRevoked       = errors.New("token has been revoked")
+	ErrTokenReused        = errors.New("refresh token was already used; the session has been revoked")
 	ErrInsufficientRole   = errors.New("insufficient role")
 	ErrInvalidSignature   = errors.New("invalid token signature")
 	ErrExpiredToken       = errors.New("token has expired")
 	ErrInvalidIssuer      = errors.New("invalid token issuer")
+	ErrInvalidAudience    = errors.New("invalid token audience")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUserNotFound       = errors.New("user not found in context")
 	ErrUnauthorizedRole   = errors.New("user role not authorized for this action")
 	ErrResourceNotOwned   = errors.New("user does not own this resource")
+	ErrInvalidClient      = errors.New("invalid client credentials")
+	ErrInvalidScope       = errors.New("requested scope is not allowed")
+	ErrInsufficientScope  = errors.New("token is missing required scope")
+	ErrStepUpRequired     = errors.New("recent authentication required for this operation")
+	ErrInvalidGrant       = errors.New("subject token is invalid, expired or revoked")
+	ErrUnauthorizedClient = errors.New("client is not allowed to use this grant type")
+	ErrUnsupportedTokenType = errors.New("unsupported subject token type")
+	ErrRevocationDisabled = errors.New("token revocation is not configured")
+	ErrUserRolesUnavailable = errors.New("user role lookup is not configured")
 ) 
\ No newline at end of file
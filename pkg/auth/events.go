@@ -0,0 +1,27 @@
+package auth
+
+import "net/http"
+
+// AuthEventType classifies the outcome of authenticating a request
+type AuthEventType string
+
+const (
+	AuthEventSuccess      AuthEventType = "success"
+	AuthEventUnauthorized AuthEventType = "unauthorized"
+	AuthEventForbidden    AuthEventType = "forbidden"
+	AuthEventRevokedToken AuthEventType = "revoked_token"
+)
+
+// AuthEvent describes how AuthMiddleware handled a request
+type AuthEvent struct {
+	Type   AuthEventType
+	UserID string // empty when the token couldn't be validated
+	Err    error  // reason for failures
+}
+
+// emitAuthEvent calls the configured hook, if any
+func (c AuthConfig) emitAuthEvent(r *http.Request, event AuthEvent) {
+	if c.OnAuthEvent != nil {
+		c.OnAuthEvent(r, event)
+	}
+}
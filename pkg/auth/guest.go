@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GuestUserID is the user ID of the synthetic principal used for guest access
+const GuestUserID = "guest"
+
+// GuestLinkParam is the query parameter carrying a guest link token;
+// API clients may send the token in the GuestLinkHeader instead
+const (
+	GuestLinkParam  = "link"
+	GuestLinkHeader = "X-Guest-Link"
+)
+
+// GuestLink grants read-only access to a public project of an organization to anyone
+// holding the token. The empty OrgID is the default tenant.
+type GuestLink struct {
+	Token     string
+	OrgID     string
+	ProjectID string
+}
+
+// GuestAccess configures unauthenticated read-only access via link tokens
+type GuestAccess struct {
+	Links  []GuestLink
+	Routes []PublicRoute // routes guests may read; only GET requests are ever allowed
+}
+
+// ParseGuestLinks parses a comma-separated list of "token:org_id:project_id" entries; the
+// org ID is left empty for the default tenant, e.g. "abc::ROADMAP"
+func ParseGuestLinks(value string) ([]GuestLink, error) {
+	var links []GuestLink
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid guest link %q, expected token:org_id:project_id", entry)
+		}
+		links = append(links, GuestLink{Token: parts[0], OrgID: parts[1], ProjectID: parts[2]})
+	}
+	return links, nil
+}
+
+// guestClaims returns claims for a guest principal if the request carries a valid link token
+// for a guest-readable route
+func (g *GuestAccess) guestClaims(r *http.Request) (*Claims, bool) {
+	if g == nil || r.Method != http.MethodGet || !isPublicRoute(g.Routes, r) {
+		return nil, false
+	}
+
+	token := r.URL.Query().Get(GuestLinkParam)
+	if token == "" {
+		token = r.Header.Get(GuestLinkHeader)
+	}
+	if token == "" {
+		return nil, false
+	}
+
+	for _, link := range g.Links {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(link.Token)) == 1 {
+			return &Claims{
+				UserID:    GuestUserID,
+				Roles:     []string{"viewer"},
+				Scope:     ScopeTasksRead,
+				OrgID:     link.OrgID,
+				ProjectID: link.ProjectID,
+			}, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// jwksMinRefresh is how long after a fetch a token with an unknown "kid" may trigger
+// another one, so tokens with made-up key IDs can't hammer the identity provider
+const jwksMinRefresh = time.Minute
+
+// JWKS verifies tokens with the keys an identity provider publishes as a JSON Web Key
+// Set (RFC 7517), e.g. Auth0, Cognito or Keycloak. Keys are cached, refreshed by Run and
+// fetched early when a token names a key not seen yet, as after a key rotation.
+type JWKS struct {
+	url    string
+	client *http.Client
+	now    func() time.Time
+
+	mu        sync.RWMutex
+	keys      PublicKeys
+	fetchedAt time.Time
+	// fetching serializes fetches, so a burst of tokens with a new key fetches once
+	fetching sync.Mutex
+}
+
+// NewJWKS fetches the key set at url, failing if it can't be fetched
+func NewJWKS(ctx context.Context, url string, client *http.Client) (*JWKS, error) {
+	j := &JWKS{url: url, client: client, now: time.Now}
+	if err := j.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// PublicKey returns the key with the ID, fetching the key set again if the key is unknown
+// and the last fetch is older than jwksMinRefresh
+func (j *JWKS) PublicKey(kid string) (crypto.PublicKey, bool) {
+	if j == nil {
+		return nil, false
+	}
+	if key, ok := j.lookup(kid); ok {
+		return key, true
+	}
+
+	j.fetching.Lock()
+	defer j.fetching.Unlock()
+	// Another request may have fetched the key meanwhile
+	if key, ok := j.lookup(kid); ok {
+		return key, true
+	}
+	j.mu.RLock()
+	recent := j.now().Sub(j.fetchedAt) < jwksMinRefresh
+	j.mu.RUnlock()
+	if recent {
+		return nil, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := j.fetch(ctx); err != nil {
+		log.Printf("Failed to fetch JWKS for key %q: %v", kid, err)
+		return nil, false
+	}
+	return j.lookup(kid)
+}
+
+// Refresh fetches the key set now
+func (j *JWKS) Refresh(ctx context.Context) error {
+	j.fetching.Lock()
+	defer j.fetching.Unlock()
+	return j.fetch(ctx)
+}
+
+// Run refreshes the key set every interval until ctx is done. A failed refresh keeps the
+// keys fetched before.
+func (j *JWKS) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.Refresh(ctx); err != nil {
+				log.Printf("Failed to refresh JWKS, keeping the previous keys: %v", err)
+			}
+		}
+	}
+}
+
+func (j *JWKS) lookup(kid string) (crypto.PublicKey, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	key, ok := j.keys[kid]
+	return key, ok
+}
+
+// fetch downloads and parses the key set; callers hold fetching
+func (j *JWKS) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("JWKS request returned %s", resp.Status)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&set); err != nil {
+		return fmt.Errorf("invalid JWKS: %v", err)
+	}
+	keys := PublicKeys{}
+	for _, jwk := range set.Keys {
+		// Encryption keys and key types we can't verify with are skipped
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			log.Printf("Skipping JWKS key %q: %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.fetchedAt = j.now()
+	j.mu.Unlock()
+	return nil
+}
+
+// jsonWebKey is an RSA or EC public key in JWK form (RFC 7518 section 6)
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBase64URLInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URLInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBase64URLInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URLInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBase64URLInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
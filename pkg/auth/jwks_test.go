@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jwksServer publishes a key set that tests can change
+type jwksServer struct {
+	mu       sync.Mutex
+	keys     []map[string]string
+	requests int
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	json.NewEncoder(w).Encode(map[string]interface{}{"keys": s.keys})
+}
+
+func (s *jwksServer) publish(keys ...map[string]string) {
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+}
+
+func b64(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := &jwksServer{}
+	server.publish(map[string]string{
+		"kty": "RSA", "kid": "rsa-1", "use": "sig",
+		"n": b64(rsaKey.N), "e": b64(big.NewInt(int64(rsaKey.E))),
+	}, map[string]string{"kty": "oct", "kid": "secret", "k": "c2VjcmV0"})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	jwks, err := NewJWKS(context.Background(), ts.URL, ts.Client())
+	require.NoError(t, err)
+	now := time.Now()
+	jwks.now = func() time.Time { return now }
+
+	handler := AuthMiddleware(AuthConfig{
+		JWKS:         jwks,
+		Validation:   ValidationOptions{AllowedAlgorithms: []string{"RS256", "ES256"}},
+		AllowedRoles: DefaultRoles,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method jwt.SigningMethod, kid string, key interface{}) int {
+		token := jwt.NewWithClaims(method, &Claims{
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+			UserID:           "user-1",
+			Roles:            []string{"user"},
+		})
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+		r.Header.Set("Authorization", "Bearer "+signed)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(jwt.SigningMethodRS256, "rsa-1", rsaKey))
+	_, ok := jwks.PublicKey("secret")
+	assert.False(t, ok, "symmetric keys are never used")
+
+	// The provider rotates to a new key; tokens naming it fetch the set again, at most
+	// once per jwksMinRefresh
+	server.publish(map[string]string{
+		"kty": "EC", "kid": "ec-2", "crv": "P-256", "x": b64(ecKey.X), "y": b64(ecKey.Y),
+	})
+	assert.Equal(t, http.StatusUnauthorized, serve(jwt.SigningMethodES256, "ec-2", ecKey))
+	assert.Equal(t, 1, server.requests)
+
+	now = now.Add(jwksMinRefresh)
+	assert.Equal(t, http.StatusOK, serve(jwt.SigningMethodES256, "ec-2", ecKey))
+	assert.Equal(t, http.StatusUnauthorized, serve(jwt.SigningMethodRS256, "rsa-1", rsaKey))
+	assert.Equal(t, 2, server.requests)
+}
+
+func TestNewJWKS_FailsWithoutKeySet(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	defer ts.Close()
+
+	_, err := NewJWKS(context.Background(), ts.URL, ts.Client())
+	assert.EqualError(t, err, "JWKS request returned 404 Not Found")
+}
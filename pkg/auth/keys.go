@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// PublicKeys verify asymmetrically signed tokens (RS*, PS* and ES*), keyed by the "kid"
+// header they are selected with. The key under "" verifies tokens without a "kid".
+type PublicKeys map[string]crypto.PublicKey
+
+// PublicKey returns the key with the ID
+func (k PublicKeys) PublicKey(kid string) (crypto.PublicKey, bool) {
+	key, ok := k[kid]
+	return key, ok
+}
+
+// keySource looks up public keys by "kid", e.g. PublicKeys or JWKS
+type keySource interface {
+	PublicKey(kid string) (crypto.PublicKey, bool)
+}
+
+// SigningKey signs tokens with a private key instead of the shared secret
+type SigningKey struct {
+	ID     string // sent as the "kid" header
+	Method jwt.SigningMethod
+	Key    crypto.Signer
+}
+
+// NewSigningKey pairs a private key with its signing method: RS256 for RSA keys, and
+// ES256, ES384 or ES512 for ECDSA keys on P-256, P-384 and P-521
+func NewSigningKey(id string, key crypto.Signer) (*SigningKey, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, Key: key}, nil
+	case *ecdsa.PrivateKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return &SigningKey{ID: id, Method: jwt.SigningMethodES256, Key: key}, nil
+		case elliptic.P384():
+			return &SigningKey{ID: id, Method: jwt.SigningMethodES384, Key: key}, nil
+		case elliptic.P521():
+			return &SigningKey{ID: id, Method: jwt.SigningMethodES512, Key: key}, nil
+		}
+	}
+	return nil, errors.New("signing keys must be RSA or ECDSA on P-256, P-384 or P-521")
+}
+
+// ParsePrivateKeyPEM parses an RSA or ECDSA private key in PEM form
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPrivateKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("not a PEM encoded RSA or ECDSA private key")
+}
+
+// ParsePublicKeyPEM parses an RSA or ECDSA public key, or a certificate, in PEM form
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("not a PEM encoded RSA or ECDSA public key")
+}
+
+// LoadPublicKeys reads PEM files from a comma-separated list of paths, each optionally
+// prefixed with its key ID, e.g. "idp-2024=/etc/keys/idp.pem,/etc/keys/legacy.pem"
+func LoadPublicKeys(spec string) (PublicKeys, error) {
+	keys := PublicKeys{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, path, found := strings.Cut(entry, "=")
+		if !found {
+			id, path = "", entry
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("public key %q is configured twice", id)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// verificationKey returns a jwt.Keyfunc checking HMAC tokens against secret and the
+// others against the public key named by their "kid" in the first source that has it
+func verificationKey(secret []byte, sources ...keySource) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			if len(secret) == 0 {
+				return nil, ErrInvalidToken
+			}
+			return secret, nil
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		var key crypto.PublicKey
+		for _, source := range sources {
+			if k, ok := source.PublicKey(kid); ok {
+				key = k
+				break
+			}
+		}
+		if key == nil {
+			return nil, ErrInvalidToken
+		}
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			if key, ok := key.(*rsa.PublicKey); ok {
+				return key, nil
+			}
+		case *jwt.SigningMethodECDSA:
+			if key, ok := key.(*ecdsa.PublicKey); ok {
+				return key, nil
+			}
+		}
+		// The key doesn't match the algorithm, e.g. an RSA key named by an ES256 token
+		return nil, ErrInvalidToken
+	}
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenManager_SigningKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	signingKey, err := NewSigningKey("key-1", key)
+	require.NoError(t, err)
+	tm := NewTokenManager([]byte("test-secret"), "test-issuer", WithSigningKey(signingKey), WithAllowedAlgorithms("RS256", "HS256"),
+		testUserRoles(map[string][]string{"user-1": {"user"}}))
+
+	pair, err := tm.CreateTokenPair(context.Background(), "user-1", []string{"user"})
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(pair.AccessToken, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", parsed.Method.Alg())
+	assert.Equal(t, "key-1", parsed.Header["kid"])
+
+	claims, err := tm.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+
+	_, err = tm.RefreshTokens(context.Background(), pair.RefreshToken)
+	assert.NoError(t, err)
+}
+
+func TestAuthMiddleware_PublicKeys(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	handler := AuthMiddleware(AuthConfig{
+		JWTSecret:    []byte("test-secret"),
+		PublicKeys:   PublicKeys{"idp": &key.PublicKey},
+		Validation:   ValidationOptions{AllowedAlgorithms: []string{"ES256", "HS256"}},
+		AllowedRoles: DefaultRoles,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	sign := func(method jwt.SigningMethod, kid string, key interface{}) int {
+		claims := &Claims{
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+			UserID:           "user-1",
+			Roles:            []string{"user"},
+		}
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+		r.Header.Set("Authorization", "Bearer "+signed)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, sign(jwt.SigningMethodES256, "idp", key))
+	assert.Equal(t, http.StatusOK, sign(jwt.SigningMethodHS256, "", []byte("test-secret")))
+	assert.Equal(t, http.StatusUnauthorized, sign(jwt.SigningMethodES256, "idp", other))
+	assert.Equal(t, http.StatusUnauthorized, sign(jwt.SigningMethodES256, "unknown", key))
+	// The public key is not an HMAC secret, whatever the token's header claims
+	assert.Equal(t, http.StatusUnauthorized, sign(jwt.SigningMethodHS256, "idp", publicDER))
+}
+
+func TestLoadPublicKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "idp.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	keys, err := LoadPublicKeys("idp=" + path + ", " + path)
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, &key.PublicKey, keys["idp"])
+
+	_, err = LoadPublicKeys(path + "," + path)
+	assert.EqualError(t, err, `public key "" is configured twice`)
+
+	keys, err = LoadPublicKeys("")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
@@ -7,6 +7,10 @@ import (
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+
+	"sample/task-management-system/pkg/metrics"
+	"sample/task-management-system/pkg/tenant"
 )
 
 // Claims represents our custom JWT claims
@@ -14,6 +18,75 @@ type Claims struct {
 	jwt.RegisteredClaims
 	UserID string   `json:"uid"`
 	Roles  []string `json:"roles"`
+	Scope  string   `json:"scope,omitempty"` // space-delimited OAuth2 scopes
+	Act    *Actor   `json:"act,omitempty"`   // set when acting on behalf of UserID (RFC 8693)
+
+	// OrgID is the organization the user belongs to, if any
+	OrgID string `json:"org_id,omitempty"`
+	// ProjectID restricts the principal to a single project (guest link access)
+	ProjectID string `json:"project_id,omitempty"`
+
+	// AuthTime is when the user last actively authenticated; it survives token refreshes
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	// AMR lists the authentication methods used (e.g. "pwd", "mfa")
+	AMR []string `json:"amr,omitempty"`
+	// FirstParty is set on sessions of users who signed in themselves. Without a scope
+	// claim they are limited by their roles; every other token without one grants nothing.
+	FirstParty bool `json:"first_party,omitempty"`
+	// Family identifies the sign-in the token was issued for; it is kept when tokens are
+	// refreshed, so revoking it revokes every token refreshed since
+	Family string `json:"fam,omitempty"`
+	// TokenUse is TokenUseRefresh on refresh tokens, which never authenticate requests
+	TokenUse string `json:"token_use,omitempty"`
+
+	// APIKeyID is the key that authenticated the request; API keys aren't tokens, so it is
+	// never part of one
+	APIKeyID string `json:"-"`
+	// probe is set only on the claims ProbeAccess grants for the shared probe token; no
+	// token or API key can set it
+	probe bool
+}
+
+// Actor identifies the party acting on behalf of the token subject.
+// Act is set when the actor was itself acting for someone else (RFC 8693 section 4.1).
+type Actor struct {
+	Subject string `json:"sub"`
+	Act     *Actor `json:"act,omitempty"`
+}
+
+// maxActorChainDepth bounds how many times a token can be delegated onwards
+const maxActorChainDepth = 3
+
+// ActorID returns who is actually performing requests with this token
+func (c *Claims) ActorID() string {
+	if c.Act != nil && c.Act.Subject != "" {
+		return c.Act.Subject
+	}
+	return c.UserID
+}
+
+// ActorChain returns the delegation chain, starting with the current actor and
+// ending with the earliest one. It is empty when the subject acts for themselves.
+func (c *Claims) ActorChain() []string {
+	var chain []string
+	for act := c.Act; act != nil; act = act.Act {
+		chain = append(chain, act.Subject)
+	}
+	return chain
+}
+
+// validateActor rejects act claims that are malformed or delegated too many times
+func (c *Claims) validateActor() error {
+	chain := c.ActorChain()
+	if len(chain) > maxActorChainDepth {
+		return ErrInvalidToken
+	}
+	for _, actor := range chain {
+		if actor == "" {
+			return ErrInvalidToken
+		}
+	}
+	return nil
 }
 
 // Role represents a user role and its permissions
@@ -22,17 +95,98 @@ type Role struct {
 	Permissions map[string][]string // endpoint -> allowed methods
 }
 
+// Allows reports whether the role's permissions let it make a request, as the
+// middleware checks them
+func (r Role) Allows(method, path string) bool {
+	for pattern, methods := range r.Permissions {
+		if matchPath(pattern, path) && containsMethod(methods, method) {
+			return true
+		}
+	}
+	return false
+}
+
 // AuthConfig holds the middleware configuration
 type AuthConfig struct {
 	JWTSecret     []byte
+	PublicKeys    PublicKeys        // optional keys verifying RS*, PS* and ES* tokens
+	JWKS          *JWKS             // optional identity provider key set, consulted after PublicKeys
+	Validation    ValidationOptions // issuer, audience, algorithm and leeway checks
 	AllowedRoles  map[string]Role
-	PublicPaths   []string // paths that don't require authentication
+	RoleCache     *RoleCache    // optional; replaces AllowedRoles with roles loaded from a source
+	PublicRoutes  []PublicRoute // routes that don't require authentication
+	TokenCache    TokenCache    // optional cache of validated tokens
+	Revocations   *RevocationList // optional list of revoked tokens, checked on every request
+	GuestAccess   *GuestAccess  // optional read-only access via link tokens
+	ProbeAccess   *ProbeAccess  // optional monitoring access via a shared probe token
+	SandboxAccess *SandboxAccess // optional demo token accepted on sandbox instances
+
+	// OnAuthEvent, if set, is called with the outcome of every non-public request
+	OnAuthEvent func(r *http.Request, event AuthEvent)
+}
+
+// PublicRoute describes a route that doesn't require authentication.
+// Pattern segments match exactly, except "*" or "{name}" which match any single
+// segment, and a final "**" which matches any remaining segments (including none).
+type PublicRoute struct {
+	Methods []string // empty means any method
+	Pattern string
+}
+
+// Matches checks if the route matches the request method and path
+func (p PublicRoute) Matches(method, path string) bool {
+	if len(p.Methods) > 0 && !containsMethod(p.Methods, method) {
+		return false
+	}
+	return matchSegments(p.Pattern, path)
+}
+
+// matchSegments matches a path against a segment pattern without prefix matching
+func matchSegments(pattern, path string) bool {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+
+	// Trailing slashes are significant; "/health/" is not "/health"
+	if strings.HasSuffix(path, "/") != strings.HasSuffix(pattern, "/") && !strings.HasSuffix(pattern, "**") {
+		return false
+	}
+
+	for i, part := range patternParts {
+		if part == "**" && i == len(patternParts)-1 {
+			return true
+		}
+		if i >= len(pathParts) {
+			return false
+		}
+		isWildcard := part == "*" || (strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}"))
+		if isWildcard {
+			if pathParts[i] == "" {
+				return false
+			}
+			continue
+		}
+		if part != pathParts[i] {
+			return false
+		}
+	}
+
+	return len(patternParts) == len(pathParts)
+}
+
+// isPublicRoute checks if a request matches any public route
+func isPublicRoute(routes []PublicRoute, r *http.Request) bool {
+	for _, route := range routes {
+		if route.Matches(r.Method, r.URL.Path) {
+			return true
+		}
+	}
+	return false
 }
 
 // matchPath checks if a request path matches a pattern
 func matchPath(pattern, path string) bool {
 	// Convert pattern to regex
-	regexPattern := strings.ReplaceAll(pattern, "{id}", "[a-zA-Z0-9-]+")
+	regexPattern := strings.ReplaceAll(pattern, "{id}", "[a-zA-Z0-9_-]+")
 	regexPattern = "^" + regexPattern + "$"
 	
 	match, err := regexp.MatchString(regexPattern, path)
@@ -44,82 +198,139 @@ func matchPath(pattern, path string) bool {
 
 // AuthMiddleware handles JWT validation and role-based access control
 func AuthMiddleware(config AuthConfig) func(http.Handler) http.Handler {
+	parserOptions := config.Validation.parserOptions()
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Check if path is public
-			for _, path := range config.PublicPaths {
-				if strings.HasPrefix(r.URL.Path, path) {
-					next.ServeHTTP(w, r)
-					return
-				}
+			// Check if route is public
+			if isPublicRoute(config.PublicRoutes, r) {
+				next.ServeHTTP(w, r)
+				return
 			}
 
 			// Get token from header
 			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				http.Error(w, ErrNoAuthHeader.Error(), http.StatusUnauthorized)
-				return
-			}
-
-			// Check bearer format
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, ErrInvalidAuthType.Error(), http.StatusUnauthorized)
-				return
-			}
+			claims, viaAPIKey := apiKeyClaims(r)
+			switch {
+			case viaAPIKey:
+				// Authenticated by APIKeyMiddleware; roles are checked below like a token's
+			case authHeader == "":
+				// Unauthenticated reads may use a guest link or probe token instead
+				linkClaims, ok := config.GuestAccess.guestClaims(r)
+				if !ok {
+					linkClaims, ok = config.ProbeAccess.probeClaims(r)
+				}
+				if !ok {
+					config.emitAuthEvent(r, AuthEvent{Type: AuthEventUnauthorized, Err: ErrNoAuthHeader})
+					http.Error(w, ErrNoAuthHeader.Error(), http.StatusUnauthorized)
+					return
+				}
+				claims = linkClaims
+			default:
+				// Check bearer format
+				parts := strings.Split(authHeader, " ")
+				if len(parts) != 2 || parts[0] != "Bearer" {
+					config.emitAuthEvent(r, AuthEvent{Type: AuthEventUnauthorized, Err: ErrInvalidAuthType})
+					http.Error(w, ErrInvalidAuthType.Error(), http.StatusUnauthorized)
+					return
+				}
 
-			// Parse and validate token
-			claims := &Claims{}
-			token, err := jwt.ParseWithClaims(parts[1], claims, func(token *jwt.Token) (interface{}, error) {
-				return config.JWTSecret, nil
-			})
+				// Parse and validate token, unless it was recently validated or is the sandbox demo token
+				var err error
+				sandboxClaims, ok := config.SandboxAccess.sandboxClaims(parts[1])
+				if ok {
+					claims = sandboxClaims
+				} else {
+					claims, err = validateBearerToken(config, parts[1], parserOptions)
+				}
+				if err != nil {
+					config.emitAuthEvent(r, AuthEvent{Type: AuthEventUnauthorized, Err: ErrInvalidToken})
+					http.Error(w, ErrInvalidToken.Error(), http.StatusUnauthorized)
+					return
+				}
 
-			if err != nil || !token.Valid {
-				http.Error(w, ErrInvalidToken.Error(), http.StatusUnauthorized)
-				return
+				revoked, err := config.Revocations.IsRevoked(r.Context(), claims.ID, claims.Family)
+				if err != nil {
+					http.Error(w, "token revocation could not be checked", http.StatusInternalServerError)
+					return
+				}
+				if revoked {
+					config.emitAuthEvent(r, AuthEvent{Type: AuthEventRevokedToken, UserID: claims.UserID, Err: ErrTokenRevoked})
+					http.Error(w, ErrTokenRevoked.Error(), http.StatusUnauthorized)
+					return
+				}
 			}
 
 			// Check role permissions
+			allowedRoles := config.AllowedRoles
+			if config.RoleCache != nil {
+				allowedRoles = config.RoleCache.Roles()
+			}
 			hasPermission := false
 			for _, userRole := range claims.Roles {
-				if role, exists := config.AllowedRoles[userRole]; exists {
-					for pattern, methods := range role.Permissions {
-						if matchPath(pattern, r.URL.Path) {
-							for _, method := range methods {
-								if method == r.Method {
-									hasPermission = true
-									break
-								}
-							}
-							if hasPermission {
-								break
-							}
-						}
-					}
-				}
-				if hasPermission {
+				if role, exists := allowedRoles[userRole]; exists && role.Allows(r.Method, r.URL.Path) {
+					hasPermission = true
 					break
 				}
 			}
 
 			if !hasPermission {
+				config.emitAuthEvent(r, AuthEvent{Type: AuthEventForbidden, UserID: claims.UserID, Err: ErrInsufficientRole})
 				http.Error(w, ErrInsufficientRole.Error(), http.StatusForbidden)
 				return
 			}
 
+			config.emitAuthEvent(r, AuthEvent{Type: AuthEventSuccess, UserID: claims.UserID})
+
 			// Add claims to context
 			ctx := context.WithValue(r.Context(), "claims", claims)
+			// Everyone but probes only ever sees their organization's data; guests see the
+			// organization their link belongs to. Tokens claiming the probe's user ID are
+			// scoped like any other.
+			if !claims.probe {
+				ctx = tenant.WithID(ctx, claims.OrgID)
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
-// ResourceOwnershipMiddleware checks if the user owns the resource or has admin rights
-func ResourceOwnershipMiddleware(resourceType string) func(http.Handler) http.Handler {
+// validateBearerToken returns the claims of a valid token, consulting the token cache first
+func validateBearerToken(config AuthConfig, tokenString string, parserOptions []jwt.ParserOption) (*Claims, error) {
+	if config.TokenCache != nil {
+		claims, hit := config.TokenCache.Get(tokenString)
+		metrics.RecordTokenCacheLookup(hit)
+		if hit {
+			return claims, nil
+		}
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, verificationKey(config.JWTSecret, config.PublicKeys, config.JWKS), parserOptions...)
+	if err != nil || !token.Valid || claims.TokenUse == TokenUseRefresh {
+		return nil, ErrInvalidToken
+	}
+	if err := claims.validateActor(); err != nil {
+		return nil, err
+	}
+
+	if config.TokenCache != nil {
+		config.TokenCache.Set(tokenString, claims)
+	}
+	return claims, nil
+}
+
+// OwnershipChecker reports whether a user may modify a resource, e.g. because they own it
+type OwnershipChecker func(ctx context.Context, userID, resourceID string) (bool, error)
+
+// ResourceOwnershipMiddleware checks if the user owns the resource or has admin rights.
+// The resource is identified by the "id" route variable; reads are governed by roles alone.
+func ResourceOwnershipMiddleware(checker OwnershipChecker) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Skip for non-resource specific endpoints
-			if r.Method == http.MethodGet && !strings.Contains(r.URL.Path, "/") {
+			// Only writes to a specific resource need an ownership check
+			resourceID := mux.Vars(r)["id"]
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || resourceID == "" {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -138,14 +349,7 @@ func ResourceOwnershipMiddleware(resourceType string) func(http.Handler) http.Ha
 				}
 			}
 
-			// Check resource ownership
-			resourceID := extractResourceID(r.URL.Path)
-			if resourceID == "" {
-				next.ServeHTTP(w, r)
-				return
-			}
-
-			if !hasResourceAccess(claims.UserID, resourceType, resourceID) {
+			if allowed, err := checker(r.Context(), claims.UserID, resourceID); err != nil || !allowed {
 				http.Error(w, "forbidden", http.StatusForbidden)
 				return
 			}
@@ -155,22 +359,6 @@ func ResourceOwnershipMiddleware(resourceType string) func(http.Handler) http.Ha
 	}
 }
 
-// Helper function to extract resource ID from URL path
-func extractResourceID(path string) string {
-	parts := strings.Split(path, "/")
-	if len(parts) < 3 {
-		return ""
-	}
-	return parts[len(parts)-1]
-}
-
-// hasResourceAccess checks if a user has access to a specific resource
-func hasResourceAccess(userID, resourceType, resourceID string) bool {
-	// TODO: Implement resource ownership check based on your storage
-	// This is where you would query your database to check ownership
-	return true
-}
-
 // Example usage of role definitions
 var DefaultRoles = map[string]Role{
 	"admin": {
@@ -178,10 +366,135 @@ var DefaultRoles = map[string]Role{
 		Permissions: map[string][]string{
 			"/api/v1/tasks":          {"GET", "POST", "PUT", "DELETE"},
 			"/api/v1/tasks/{id}":     {"GET", "PUT", "DELETE"},
+			"/api/v1/tasks/by-key/{id}": {"GET"},
+			"/api/v1/tasks/import": {"POST"},
+			"/api/v1/tasks/by-slug/{id}/{id}": {"GET"},
+			"/api/v1/tasks/drafts/{id}": {"PUT"},
+			"/api/v1/tasks/drafts/{id}/publish": {"POST"},
+			"/api/v1/tasks/{id}/related": {"GET"},
+			"/api/v1/tasks/{id}/subtasks": {"GET"},
+			"/api/v1/tasks/{id}/share":   {"POST"},
+			"/api/v1/tasks/{id}/shares":  {"GET"},
+			"/api/v1/tasks/{id}/shares/{id}": {"DELETE"},
+			"/api/v1/tasks/{id}/blockers": {"GET", "POST"},
+			"/api/v1/tasks/{id}/blockers/{id}": {"DELETE"},
+			"/api/v1/tasks/{id}/blocked": {"GET"},
+			"/api/v1/tasks/{id}/relations": {"GET", "POST"},
+			"/api/v1/tasks/{id}/relations/{id}/{id}": {"DELETE"},
+			"/api/v1/tasks/{id}/merge": {"POST"},
+			"/api/v1/tasks/{id}/snooze": {"POST", "DELETE"},
+			"/api/v1/tasks/{id}/team": {"PUT", "DELETE"},
+			"/api/v1/tasks/{id}/sla": {"PUT", "DELETE"},
+			"/api/v1/tasks/{id}/approval": {"GET", "PUT", "DELETE"},
+			"/api/v1/tasks/{id}/approval/approve": {"POST"},
+			"/api/v1/tasks/{id}/approval/reject": {"POST"},
+			"/api/v1/tasks/{id}/checklist": {"GET", "POST"},
+			"/api/v1/tasks/{id}/checklist/order": {"PUT"},
+			"/api/v1/tasks/{id}/checklist/toggle": {"POST"},
+			"/api/v1/tasks/{id}/checklist/{id}": {"PUT", "DELETE"},
+			"/api/v1/tasks/{id}/position": {"PATCH"},
+			"/api/v1/tasks/{id}/description/versions": {"GET"},
+			"/api/v1/tasks/{id}/description/versions/{id}/restore": {"POST"},
+			"/api/v1/tasks/{id}/time": {"GET", "POST"},
+			"/api/v1/tasks/{id}/time/start": {"POST"},
+			"/api/v1/tasks/{id}/time/stop": {"POST"},
+			"/api/v1/tasks/{id}/time/{id}": {"DELETE"},
+			"/api/v1/tasks/{id}/star":   {"POST", "DELETE"},
+			"/api/v1/tasks/{id}/clone": {"POST"},
+			"/api/v1/tasks/{id}/attachments": {"GET", "POST"},
+			"/api/v1/tasks/{id}/attachments/{id}": {"DELETE"},
+			"/api/v1/tasks/{id}/attachments/{id}/download": {"GET"},
+			"/api/v1/projects/{id}/workload": {"GET"},
+			"/api/v1/projects/{id}/burnup": {"GET"},
+			"/api/v1/projects/{id}/burndown": {"GET"},
+			"/api/v1/projects/{id}/settings": {"GET", "PUT"},
+			"/api/v1/tags":           {"GET"},
+			"/api/v1/teams":          {"GET"},
+			"/api/v1/teams/{id}":     {"GET"},
+			"/api/v1/sync":           {"GET", "POST"},
+			"/api/v1/batch":          {"POST"},
+			"/api/v1/templates":      {"GET", "POST"},
+			"/api/v1/templates/{id}": {"GET", "PUT", "DELETE"},
+			"/api/v1/templates/{id}/instantiate": {"POST"},
 			"/api/v1/users":          {"GET", "POST", "PUT", "DELETE"},
 			"/api/v1/users/{id}":     {"GET", "PUT", "DELETE"},
+			"/api/v1/users/me/security-events": {"GET"},
+			"/api/v1/users/me/recent-tasks":    {"GET"},
+			"/api/v1/users/me/preferences":     {"GET", "PUT"},
+			"/api/v1/exports":                  {"POST"},
+			"/api/v1/exports/{id}":             {"GET"},
+			"/api/v1/exports/{id}/download":    {"GET"},
+			"/api/v1/imports":                  {"POST"},
+			"/api/v1/imports/{id}":             {"GET"},
+			"/api/v1/imports/{id}/failures":    {"GET"},
+			"/api/v1/imports/{id}/cancel":      {"POST"},
+			"/api/v1/telemetry/events":         {"POST"},
+			"/api/v1/invitations/accept":       {"POST"},
+			"/api/v1/apikeys":                  {"GET", "POST"},
+			"/api/v1/apikeys/{id}":             {"DELETE"},
+			"/api/v1/auth/logout":              {"POST"},
 			"/api/v1/metrics":        {"GET"},
 			"/api/v1/settings":       {"GET", "PUT"},
+			"/api/v1/admin/moderation/flags":      {"GET"},
+			"/api/v1/admin/moderation/flags/{id}": {"PUT"},
+			"/api/v1/admin/stats":                 {"GET"},
+			"/api/v1/admin/cache/invalidate":      {"POST"},
+			"/api/v1/admin/cache/stats":           {"GET"},
+			"/api/v1/admin/deprecations":          {"GET"},
+			"/api/v1/admin/roles":                 {"GET"},
+			"/api/v1/admin/roles/{id}":            {"GET", "PUT", "DELETE"},
+			"/api/v1/admin/announcements":         {"GET", "POST"},
+			"/api/v1/admin/announcements/{id}":    {"PUT", "DELETE"},
+			"/api/v1/admin/teams":                 {"POST"},
+			"/api/v1/admin/teams/{id}":            {"PUT", "DELETE"},
+			"/api/v1/admin/teams/{id}/members/{id}": {"PUT", "DELETE"},
+			"/api/v1/admin/reports":               {"GET", "POST"},
+			"/api/v1/admin/reports/{id}":          {"GET", "PUT", "DELETE"},
+			"/api/v1/admin/reports/{id}/run":      {"GET"},
+			"/api/v1/admin/oauth/clients":         {"GET", "POST"},
+			"/api/v1/admin/oauth/clients/{id}":    {"DELETE"},
+			"/api/v1/admin/jobs/dead-letters":     {"GET"},
+			"/api/v1/admin/jobs/dead-letters/{id}/{id}":         {"GET"},
+			"/api/v1/admin/jobs/dead-letters/{id}/{id}/requeue": {"POST"},
+			"/api/v1/admin/events/replay":         {"POST"},
+			"/api/v1/webhooks":                    {"GET", "POST"},
+			"/api/v1/webhooks/{id}":               {"GET", "DELETE"},
+			"/api/v1/webhooks/{id}/test":          {"POST"},
+			"/api/v1/webhooks/{id}/deliveries":    {"GET"},
+			"/api/v1/webhooks/{id}/deliveries/{id}/redeliver": {"POST"},
+			"/api/v1/orgs/{id}/ip-allowlist":      {"GET", "POST"},
+			"/api/v1/orgs/{id}/ip-allowlist/{id}": {"DELETE"},
+			"/api/v1/orgs/{id}/telemetry":         {"GET", "PUT"},
+			"/api/v1/orgs/{id}/invitations":       {"GET", "POST"},
+			"/api/v1/orgs/{id}/invitations/{id}":  {"DELETE"},
+			"/api/v1/orgs/{id}/members":           {"GET"},
+			"/health/details":                     {"GET"},
+		},
+	},
+	// probe is the role of the synthetic principal authenticated by a probe token
+	"probe": {
+		Name: "probe",
+		Permissions: map[string][]string{
+			"/health/details": {"GET"},
+		},
+	},
+	// org_admin is granted alongside user to organization administrators
+	"org_admin": {
+		Name: "org_admin",
+		Permissions: map[string][]string{
+			"/api/v1/orgs/{id}/ip-allowlist":      {"GET", "POST"},
+			"/api/v1/orgs/{id}/ip-allowlist/{id}": {"DELETE"},
+			"/api/v1/orgs/{id}/telemetry":         {"GET", "PUT"},
+			"/api/v1/orgs/{id}/invitations":       {"GET", "POST"},
+			"/api/v1/orgs/{id}/invitations/{id}":  {"DELETE"},
+			"/api/v1/orgs/{id}/members":           {"GET"},
+		},
+	},
+	// impersonator is granted alongside admin to support staff who may impersonate users
+	"impersonator": {
+		Name: "impersonator",
+		Permissions: map[string][]string{
+			"/api/v1/admin/impersonate/{id}": {"POST"},
 		},
 	},
 	"user": {
@@ -189,7 +502,72 @@ var DefaultRoles = map[string]Role{
 		Permissions: map[string][]string{
 			"/api/v1/tasks":          {"GET", "POST"},
 			"/api/v1/tasks/{id}":     {"GET", "PUT", "DELETE"},
+			"/api/v1/tasks/by-key/{id}": {"GET"},
+			"/api/v1/tasks/import": {"POST"},
+			"/api/v1/tasks/by-slug/{id}/{id}": {"GET"},
+			"/api/v1/tasks/drafts/{id}": {"PUT"},
+			"/api/v1/tasks/drafts/{id}/publish": {"POST"},
+			"/api/v1/tasks/{id}/related": {"GET"},
+			"/api/v1/tasks/{id}/subtasks": {"GET"},
+			"/api/v1/tasks/{id}/share":   {"POST"},
+			"/api/v1/tasks/{id}/shares":  {"GET"},
+			"/api/v1/tasks/{id}/shares/{id}": {"DELETE"},
+			"/api/v1/tasks/{id}/blockers": {"GET", "POST"},
+			"/api/v1/tasks/{id}/blockers/{id}": {"DELETE"},
+			"/api/v1/tasks/{id}/blocked": {"GET"},
+			"/api/v1/tasks/{id}/relations": {"GET", "POST"},
+			"/api/v1/tasks/{id}/relations/{id}/{id}": {"DELETE"},
+			"/api/v1/tasks/{id}/merge": {"POST"},
+			"/api/v1/tasks/{id}/snooze": {"POST", "DELETE"},
+			"/api/v1/tasks/{id}/team": {"PUT", "DELETE"},
+			"/api/v1/tasks/{id}/sla": {"PUT", "DELETE"},
+			"/api/v1/tasks/{id}/approval": {"GET", "PUT", "DELETE"},
+			"/api/v1/tasks/{id}/approval/approve": {"POST"},
+			"/api/v1/tasks/{id}/approval/reject": {"POST"},
+			"/api/v1/tasks/{id}/checklist": {"GET", "POST"},
+			"/api/v1/tasks/{id}/checklist/order": {"PUT"},
+			"/api/v1/tasks/{id}/checklist/toggle": {"POST"},
+			"/api/v1/tasks/{id}/checklist/{id}": {"PUT", "DELETE"},
+			"/api/v1/tasks/{id}/position": {"PATCH"},
+			"/api/v1/tasks/{id}/description/versions": {"GET"},
+			"/api/v1/tasks/{id}/description/versions/{id}/restore": {"POST"},
+			"/api/v1/tasks/{id}/time": {"GET", "POST"},
+			"/api/v1/tasks/{id}/time/start": {"POST"},
+			"/api/v1/tasks/{id}/time/stop": {"POST"},
+			"/api/v1/tasks/{id}/time/{id}": {"DELETE"},
+			"/api/v1/tasks/{id}/star":   {"POST", "DELETE"},
+			"/api/v1/tasks/{id}/clone": {"POST"},
+			"/api/v1/tasks/{id}/attachments": {"GET", "POST"},
+			"/api/v1/tasks/{id}/attachments/{id}": {"DELETE"},
+			"/api/v1/tasks/{id}/attachments/{id}/download": {"GET"},
+			"/api/v1/projects/{id}/workload": {"GET"},
+			"/api/v1/projects/{id}/burnup": {"GET"},
+			"/api/v1/projects/{id}/burndown": {"GET"},
+			"/api/v1/projects/{id}/settings": {"GET"},
+			"/api/v1/tags":           {"GET"},
+			"/api/v1/teams":          {"GET"},
+			"/api/v1/teams/{id}":     {"GET"},
+			"/api/v1/sync":           {"GET", "POST"},
+			"/api/v1/batch":          {"POST"},
+			"/api/v1/templates":      {"GET", "POST"},
+			"/api/v1/templates/{id}": {"GET", "PUT", "DELETE"},
+			"/api/v1/templates/{id}/instantiate": {"POST"},
 			"/api/v1/users/me":       {"GET", "PUT"},
+			"/api/v1/users/me/security-events": {"GET"},
+			"/api/v1/users/me/recent-tasks":    {"GET"},
+			"/api/v1/users/me/preferences":     {"GET", "PUT"},
+			"/api/v1/exports":                  {"POST"},
+			"/api/v1/exports/{id}":             {"GET"},
+			"/api/v1/exports/{id}/download":    {"GET"},
+			"/api/v1/imports":                  {"POST"},
+			"/api/v1/imports/{id}":             {"GET"},
+			"/api/v1/imports/{id}/failures":    {"GET"},
+			"/api/v1/imports/{id}/cancel":      {"POST"},
+			"/api/v1/telemetry/events":         {"POST"},
+			"/api/v1/invitations/accept":       {"POST"},
+			"/api/v1/apikeys":                  {"GET", "POST"},
+			"/api/v1/apikeys/{id}":             {"DELETE"},
+			"/api/v1/auth/logout":              {"POST"},
 		},
 	},
 	"viewer": {
@@ -197,6 +575,38 @@ var DefaultRoles = map[string]Role{
 		Permissions: map[string][]string{
 			"/api/v1/tasks":          {"GET"},
 			"/api/v1/tasks/{id}":     {"GET"},
+			"/api/v1/tasks/by-key/{id}": {"GET"},
+			"/api/v1/tasks/by-slug/{id}/{id}": {"GET"},
+			"/api/v1/tasks/{id}/related": {"GET"},
+			"/api/v1/tasks/{id}/subtasks": {"GET"},
+			"/api/v1/tasks/{id}/blockers": {"GET"},
+			"/api/v1/tasks/{id}/blocked": {"GET"},
+			"/api/v1/tasks/{id}/relations": {"GET"},
+			"/api/v1/tasks/{id}/approval": {"GET"},
+			"/api/v1/tasks/{id}/checklist": {"GET"},
+			"/api/v1/tasks/{id}/description/versions": {"GET"},
+			"/api/v1/tasks/{id}/time": {"GET"},
+			"/api/v1/tasks/{id}/attachments": {"GET"},
+			"/api/v1/tasks/{id}/attachments/{id}/download": {"GET"},
+			"/api/v1/projects/{id}/workload": {"GET"},
+			"/api/v1/projects/{id}/burnup": {"GET"},
+			"/api/v1/projects/{id}/burndown": {"GET"},
+			"/api/v1/projects/{id}/settings": {"GET"},
+			"/api/v1/tags":           {"GET"},
+			"/api/v1/teams":          {"GET"},
+			"/api/v1/teams/{id}":     {"GET"},
+			"/api/v1/sync":           {"GET"},
+			"/api/v1/batch":          {"POST"},
+			"/api/v1/templates":      {"GET"},
+			"/api/v1/templates/{id}": {"GET"},
+			"/api/v1/users/me/security-events": {"GET"},
+			"/api/v1/users/me/recent-tasks":    {"GET"},
+			"/api/v1/users/me/preferences":     {"GET", "PUT"},
+			"/api/v1/telemetry/events":         {"POST"},
+			"/api/v1/invitations/accept":       {"POST"},
+			"/api/v1/apikeys":                  {"GET", "POST"},
+			"/api/v1/apikeys/{id}":             {"DELETE"},
+			"/api/v1/auth/logout":              {"POST"},
 		},
 	},
 }
@@ -0,0 +1,517 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/tenant"
+)
+
+func TestPublicRoute_Matches(t *testing.T) {
+	tests := []struct {
+		name   string
+		route  PublicRoute
+		method string
+		path   string
+		want   bool
+	}{
+		{"exact match", PublicRoute{Pattern: "/health"}, http.MethodGet, "/health", true},
+		{"no prefix match", PublicRoute{Pattern: "/health"}, http.MethodGet, "/healthz-admin", false},
+		{"no sub-path match", PublicRoute{Pattern: "/health"}, http.MethodGet, "/health/history", false},
+		{"trailing slash is significant", PublicRoute{Pattern: "/health"}, http.MethodGet, "/health/", false},
+		{"method allowed", PublicRoute{Methods: []string{http.MethodPost}, Pattern: "/oauth/token"}, http.MethodPost, "/oauth/token", true},
+		{"method not allowed", PublicRoute{Methods: []string{http.MethodPost}, Pattern: "/oauth/token"}, http.MethodGet, "/oauth/token", false},
+		{"single segment wildcard", PublicRoute{Pattern: "/api/v1/shared/*"}, http.MethodGet, "/api/v1/shared/abc-123", true},
+		{"named segment wildcard", PublicRoute{Pattern: "/api/v1/shared/{id}"}, http.MethodGet, "/api/v1/shared/abc-123", true},
+		{"single wildcard is one segment", PublicRoute{Pattern: "/api/v1/shared/*"}, http.MethodGet, "/api/v1/shared/abc/edit", false},
+		{"wildcard needs a segment", PublicRoute{Pattern: "/api/v1/shared/*"}, http.MethodGet, "/api/v1/shared/", false},
+		{"double wildcard matches suffix", PublicRoute{Pattern: "/api/v1/account/**"}, http.MethodPost, "/api/v1/account/password/reset", true},
+		{"double wildcard matches base", PublicRoute{Pattern: "/api/v1/account/**"}, http.MethodPost, "/api/v1/account", true},
+		{"double wildcard respects segment boundary", PublicRoute{Pattern: "/api/v1/account/**"}, http.MethodPost, "/api/v1/accounts", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.route.Matches(tt.method, tt.path))
+		})
+	}
+}
+
+func TestAuthMiddleware_PublicRoutes(t *testing.T) {
+	config := AuthConfig{
+		JWTSecret:    []byte("test-secret"),
+		AllowedRoles: DefaultRoles,
+		PublicRoutes: []PublicRoute{{Methods: []string{http.MethodGet}, Pattern: "/health"}},
+	}
+	handler := AuthMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodGet, "/health", http.StatusOK},
+		{http.MethodGet, "/healthz-admin", http.StatusUnauthorized},
+		{http.MethodPost, "/health", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_TokenValidation(t *testing.T) {
+	secret := []byte("test-secret")
+	config := AuthConfig{
+		JWTSecret: secret,
+		Validation: ValidationOptions{
+			Issuer:   "test-issuer",
+			Audience: "test-api",
+			Leeway:   30 * time.Second,
+		},
+		AllowedRoles: DefaultRoles,
+	}
+	handler := AuthMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	sign := func(method jwt.SigningMethod, mutate func(*Claims)) string {
+		now := time.Now()
+		claims := &Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    "test-issuer",
+				Audience:  jwt.ClaimStrings{"test-api"},
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			},
+			UserID: "user-1",
+			Roles:  []string{"viewer"},
+		}
+		if mutate != nil {
+			mutate(claims)
+		}
+		token, err := jwt.NewWithClaims(method, claims).SignedString(secret)
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"valid token", sign(jwt.SigningMethodHS256, nil), http.StatusOK},
+		{"disallowed algorithm", sign(jwt.SigningMethodHS512, nil), http.StatusUnauthorized},
+		{"wrong issuer", sign(jwt.SigningMethodHS256, func(c *Claims) { c.Issuer = "other" }), http.StatusUnauthorized},
+		{"wrong audience", sign(jwt.SigningMethodHS256, func(c *Claims) { c.Audience = jwt.ClaimStrings{"other-api"} }), http.StatusUnauthorized},
+		{"missing expiry", sign(jwt.SigningMethodHS256, func(c *Claims) { c.ExpiresAt = nil }), http.StatusUnauthorized},
+		{"refresh token", sign(jwt.SigningMethodHS256, func(c *Claims) { c.TokenUse = TokenUseRefresh }), http.StatusUnauthorized},
+		{"expired within leeway", sign(jwt.SigningMethodHS256, func(c *Claims) {
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-10 * time.Second))
+		}), http.StatusOK},
+		{"expired beyond leeway", sign(jwt.SigningMethodHS256, func(c *Claims) {
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+		}), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_GuestAccess(t *testing.T) {
+	config := AuthConfig{
+		JWTSecret:    []byte("test-secret"),
+		AllowedRoles: DefaultRoles,
+		GuestAccess: &GuestAccess{
+			Links:  []GuestLink{{Token: "roadmap-link", OrgID: "org-1", ProjectID: "roadmap"}},
+			Routes: []PublicRoute{{Pattern: "/api/v1/tasks"}, {Pattern: "/api/v1/tasks/{id}"}},
+		},
+	}
+
+	var user User
+	var orgID string
+	handler := AuthMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ = GetUserFromContext(r.Context())
+		orgID, _ = tenant.FromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		header string
+		want   int
+	}{
+		{"link in query", http.MethodGet, "/api/v1/tasks?link=roadmap-link", "", http.StatusOK},
+		{"link in header", http.MethodGet, "/api/v1/tasks/abc-123", "roadmap-link", http.StatusOK},
+		{"unknown link", http.MethodGet, "/api/v1/tasks?link=other", "", http.StatusUnauthorized},
+		{"no link", http.MethodGet, "/api/v1/tasks", "", http.StatusUnauthorized},
+		{"writes are never allowed", http.MethodPost, "/api/v1/tasks?link=roadmap-link", "", http.StatusUnauthorized},
+		{"route not guest readable", http.MethodGet, "/api/v1/users?link=roadmap-link", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user = User{}
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(GuestLinkHeader, tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.want == http.StatusOK {
+				assert.True(t, user.IsGuest())
+				assert.Equal(t, "roadmap", user.ProjectID)
+				assert.Equal(t, []string{"viewer"}, user.Roles)
+				assert.Equal(t, "org-1", orgID)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_ProbeAccess(t *testing.T) {
+	config := AuthConfig{
+		JWTSecret:    []byte("test-secret"),
+		AllowedRoles: DefaultRoles,
+		ProbeAccess: &ProbeAccess{
+			Token:  "probe-secret",
+			Routes: []PublicRoute{{Pattern: "/health/details"}},
+		},
+	}
+
+	var scoped bool
+	handler := AuthMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, scoped = tenant.FromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		token  string
+		want   int
+	}{
+		{"valid probe token", http.MethodGet, "/health/details", "probe-secret", http.StatusOK},
+		{"wrong probe token", http.MethodGet, "/health/details", "guess", http.StatusUnauthorized},
+		{"no probe token", http.MethodGet, "/health/details", "", http.StatusUnauthorized},
+		{"route not probe readable", http.MethodGet, "/api/v1/tasks", "probe-secret", http.StatusUnauthorized},
+		{"writes are never allowed", http.MethodPost, "/health/details", "probe-secret", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.token != "" {
+				req.Header.Set(ProbeTokenHeader, tt.token)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.want == http.StatusOK {
+				assert.False(t, scoped)
+			}
+		})
+	}
+
+	// Tokens naming the probe as their user are scoped to their organization like any other
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+		UserID:           ProbeUserID,
+		Roles:            []string{"viewer"},
+		OrgID:            "org-1",
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(config.JWTSecret)
+	require.NoError(t, err)
+	var orgID string
+	scopedHandler := AuthMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgID, scoped = tenant.FromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	scopedHandler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, scoped)
+	assert.Equal(t, "org-1", orgID)
+}
+
+func TestAuthMiddleware_SandboxAccess(t *testing.T) {
+	config := AuthConfig{
+		JWTSecret:     []byte("test-secret"),
+		AllowedRoles:  DefaultRoles,
+		SandboxAccess: &SandboxAccess{Token: "demo-token", Roles: []string{"user"}},
+	}
+
+	var user User
+	handler := AuthMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ = GetUserFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		token  string
+		want   int
+	}{
+		{"demo token", http.MethodPost, "/api/v1/tasks", "demo-token", http.StatusOK},
+		{"wrong demo token", http.MethodGet, "/api/v1/tasks", "guess", http.StatusUnauthorized},
+		{"demo roles still apply", http.MethodGet, "/api/v1/users", "demo-token", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.want == http.StatusOK {
+				assert.Equal(t, SandboxUserID, user.ID)
+			}
+		})
+	}
+}
+
+func TestParseGuestLinks(t *testing.T) {
+	links, err := ParseGuestLinks("abc:org-1:roadmap, def::public-docs")
+	require.NoError(t, err)
+	assert.Equal(t, []GuestLink{{Token: "abc", OrgID: "org-1", ProjectID: "roadmap"}, {Token: "def", ProjectID: "public-docs"}}, links)
+
+	links, err = ParseGuestLinks("")
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	_, err = ParseGuestLinks("missing-project")
+	assert.Error(t, err)
+	_, err = ParseGuestLinks("abc:roadmap")
+	assert.Error(t, err)
+}
+
+func TestAuthMiddleware_AuthEvents(t *testing.T) {
+	secret := []byte("test-secret")
+	var events []AuthEvent
+	config := AuthConfig{
+		JWTSecret:    secret,
+		AllowedRoles: DefaultRoles,
+		OnAuthEvent: func(r *http.Request, event AuthEvent) {
+			events = append(events, event)
+		},
+	}
+	handler := AuthMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+		UserID:           "user-1",
+		Roles:            []string{"viewer"},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		method string
+		header string
+		want   AuthEvent
+	}{
+		{"success", http.MethodGet, "Bearer " + token, AuthEvent{Type: AuthEventSuccess, UserID: "user-1"}},
+		{"forbidden", http.MethodPost, "Bearer " + token, AuthEvent{Type: AuthEventForbidden, UserID: "user-1", Err: ErrInsufficientRole}},
+		{"invalid token", http.MethodGet, "Bearer garbage", AuthEvent{Type: AuthEventUnauthorized, Err: ErrInvalidToken}},
+		{"missing header", http.MethodGet, "", AuthEvent{Type: AuthEventUnauthorized, Err: ErrNoAuthHeader}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events = nil
+			req := httptest.NewRequest(tt.method, "/api/v1/tasks", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			require.Len(t, events, 1)
+			assert.Equal(t, tt.want, events[0])
+		})
+	}
+}
+
+func TestResourceOwnershipMiddleware(t *testing.T) {
+	checker := func(ctx context.Context, userID, resourceID string) (bool, error) {
+		switch resourceID {
+		case "owned":
+			return userID == "user-1", nil
+		case "broken":
+			return false, errors.New("lookup failed")
+		}
+		return false, nil
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		roles      []string
+		wantStatus int
+	}{
+		{"owner can update", http.MethodPut, "/tasks/owned", []string{"user"}, http.StatusOK},
+		{"non-owner cannot update", http.MethodPut, "/tasks/other", []string{"user"}, http.StatusForbidden},
+		{"non-owner cannot delete", http.MethodDelete, "/tasks/other", []string{"user"}, http.StatusForbidden},
+		{"reads are not checked", http.MethodGet, "/tasks/other", []string{"user"}, http.StatusOK},
+		{"admin bypasses ownership", http.MethodDelete, "/tasks/other", []string{"admin"}, http.StatusOK},
+		{"checker error denies", http.MethodPut, "/tasks/broken", []string{"user"}, http.StatusForbidden},
+		{"collection writes are not checked", http.MethodPost, "/tasks", []string{"user"}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					claims := &Claims{UserID: "user-1", Roles: tt.roles}
+					next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), "claims", claims)))
+				})
+			})
+			router.Use(ResourceOwnershipMiddleware(checker))
+			ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+			router.HandleFunc("/tasks", ok)
+			router.HandleFunc("/tasks/{id}", ok)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestTenantResourceMiddleware(t *testing.T) {
+	secret := []byte("test-secret")
+	// Tasks and the organization they belong to
+	orgs := map[string]string{"task-a": "org-a", "task-b": "org-b", "task-legacy": ""}
+	lookup := func(ctx context.Context, resourceID string) error {
+		id, scoped := tenant.FromContext(ctx)
+		if org, ok := orgs[resourceID]; ok && (!scoped || org == id) {
+			return nil
+		}
+		return errors.New("task not found")
+	}
+
+	router := mux.NewRouter()
+	router.Use(AuthMiddleware(AuthConfig{JWTSecret: secret, AllowedRoles: DefaultRoles}))
+	router.Use(TenantResourceMiddleware(lookup))
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.HandleFunc("/api/v1/tasks", ok)
+	router.HandleFunc("/api/v1/tasks/{id}", ok)
+
+	sign := func(orgID string, roles ...string) string {
+		claims := &Claims{
+			RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+			UserID:           "user-1",
+			Roles:            roles,
+			OrgID:            orgID,
+		}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		wantStatus int
+	}{
+		{"own tenant's task", http.MethodGet, "/api/v1/tasks/task-a", sign("org-a", "user"), http.StatusOK},
+		{"other tenant's task is hidden", http.MethodGet, "/api/v1/tasks/task-b", sign("org-a", "user"), http.StatusNotFound},
+		{"admins are confined too", http.MethodDelete, "/api/v1/tasks/task-b", sign("org-a", "admin"), http.StatusNotFound},
+		{"users without an organization see the default tenant", http.MethodGet, "/api/v1/tasks/task-legacy", sign("", "user"), http.StatusOK},
+		{"default tenant is a tenant of its own", http.MethodGet, "/api/v1/tasks/task-a", sign("", "user"), http.StatusNotFound},
+		{"collections are not checked", http.MethodGet, "/api/v1/tasks", sign("org-a", "user"), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+		})
+	}
+}
+
+func TestRequireTenant(t *testing.T) {
+	handler := RequireTenant()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Requests of the default tenant are scoped too; only unscoped requests are refused
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tags", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req.WithContext(tenant.WithID(req.Context(), "")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestClaims_ValidateActor(t *testing.T) {
+	tests := []struct {
+		name    string
+		act     *Actor
+		wantErr bool
+	}{
+		{"no actor", nil, false},
+		{"single actor", &Actor{Subject: "slack"}, false},
+		{"nested actors", &Actor{Subject: "slack", Act: &Actor{Subject: "gateway"}}, false},
+		{"empty actor subject", &Actor{Subject: "slack", Act: &Actor{}}, true},
+		{"chain too deep", &Actor{Subject: "a", Act: &Actor{Subject: "b", Act: &Actor{Subject: "c", Act: &Actor{Subject: "d"}}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &Claims{UserID: "user-1", Act: tt.act}
+			err := claims.validateActor()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidToken)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
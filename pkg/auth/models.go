@@ -4,6 +4,25 @@ package auth
 type User struct {
 	ID    string
 	Roles []string
+	// ActorID is the real user behind the request; it differs from ID when impersonating
+	// or when a service acts on the user's behalf
+	ActorID string
+	// ActorChain lists every delegated actor, most recent first
+	ActorChain []string
+	// OrgID is the organization the user belongs to, if any
+	OrgID string
+	// ProjectID is set when the user is limited to a single project, e.g. guests
+	ProjectID string
+}
+
+// IsGuest reports whether the request was made with a guest link rather than a token
+func (u User) IsGuest() bool {
+	return u.ID == GuestUserID
+}
+
+// IsImpersonated reports whether the request is made by someone acting as this user
+func (u User) IsImpersonated() bool {
+	return u.ActorID != "" && u.ActorID != u.ID
 }
 
 // HasRole checks if the user has any of the specified roles
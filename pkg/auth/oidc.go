@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrOIDCLogin is returned when an OpenID Connect sign-in can't be completed, e.g. for an
+// unknown or reused state, a rejected code or an ID token that doesn't verify
+var ErrOIDCLogin = errors.New("sign-in could not be completed")
+
+// oidcAlgorithms are the ID token algorithms accepted; HMAC would mean trusting a token
+// signed with our own client secret
+var oidcAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// OIDCConfig configures sign-in with an OpenID Connect identity provider, e.g. Google or
+// Azure AD
+type OIDCConfig struct {
+	Name         string // used in login URLs, e.g. "google"
+	Issuer       string // e.g. "https://accounts.google.com"
+	ClientID     string
+	ClientSecret string // optional for public clients, which rely on PKCE alone
+	RedirectURL  string
+	// RoleClaim names the ID token claim listing the user's groups or app roles at the
+	// provider, e.g. "groups" or "roles"; RoleMap maps its values to our roles
+	RoleClaim string
+	RoleMap   map[string]string
+	// DefaultRoles are granted to everyone who signs in
+	DefaultRoles []string
+}
+
+// OIDCClaims are the ID token claims used to sign a user in
+type OIDCClaims struct {
+	jwt.RegisteredClaims
+	Nonce string `json:"nonce"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+
+	// claims keeps every claim, so the role claim can be any of them
+	claims map[string]json.RawMessage
+}
+
+// UnmarshalJSON decodes the standard claims and keeps the rest for role mapping
+func (c *OIDCClaims) UnmarshalJSON(data []byte) error {
+	type plain OIDCClaims
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &c.claims)
+}
+
+// OIDCLogin is the per-sign-in state kept between redirecting the user to the provider and
+// the provider redirecting back: the state identifying the sign-in, the nonce the ID token
+// must carry and the PKCE code verifier (RFC 7636)
+type OIDCLogin struct {
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// NewOIDCLogin starts a sign-in with the provider, with random state, nonce and verifier
+func NewOIDCLogin(provider string) (*OIDCLogin, error) {
+	login := &OIDCLogin{Provider: provider}
+	for _, value := range []*string{&login.State, &login.Nonce, &login.Verifier} {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		*value = base64.RawURLEncoding.EncodeToString(raw)
+	}
+	return login, nil
+}
+
+// OIDCProvider signs users in with an OpenID Connect provider using the authorization code
+// flow with PKCE. Endpoints are read from the provider's discovery document and ID tokens
+// are verified with the keys it publishes.
+type OIDCProvider struct {
+	config                OIDCConfig
+	client                *http.Client
+	authorizationEndpoint string
+	tokenEndpoint         string
+	keys                  *JWKS
+}
+
+// NewOIDCProvider reads the provider's discovery document and fetches its keys, failing
+// if either can't be fetched
+func NewOIDCProvider(ctx context.Context, config OIDCConfig, client *http.Client) (*OIDCProvider, error) {
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+	var discovery struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+	if err := getJSON(ctx, client, config.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("OIDC discovery for %s: %v", config.Name, err)
+	}
+	// Tokens are checked against the configured issuer, so it must be the provider's own
+	if strings.TrimSuffix(discovery.Issuer, "/") != config.Issuer {
+		return nil, fmt.Errorf("OIDC discovery for %s: issuer is %q, not %q", config.Name, discovery.Issuer, config.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("OIDC discovery for %s: endpoints missing", config.Name)
+	}
+
+	keys, err := NewJWKS(ctx, discovery.JWKSURI, client)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC keys for %s: %v", config.Name, err)
+	}
+	return &OIDCProvider{
+		config:                config,
+		client:                client,
+		authorizationEndpoint: discovery.AuthorizationEndpoint,
+		tokenEndpoint:         discovery.TokenEndpoint,
+		keys:                  keys,
+	}, nil
+}
+
+// Name returns the provider's name in login URLs
+func (p *OIDCProvider) Name() string {
+	return p.config.Name
+}
+
+// Keys returns the provider's signing keys, for refreshing them in the background
+func (p *OIDCProvider) Keys() *JWKS {
+	return p.keys
+}
+
+// AuthCodeURL returns the provider URL to send the user to for the sign-in
+func (p *OIDCProvider) AuthCodeURL(login *OIDCLogin) string {
+	challenge := sha256.Sum256([]byte(login.Verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {"openid email profile"},
+		"state":                 {login.State},
+		"nonce":                 {login.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(p.authorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return p.authorizationEndpoint + separator + query.Encode()
+}
+
+// Exchange redeems the authorization code of the sign-in and returns the verified ID
+// token's claims
+func (p *OIDCProvider) Exchange(ctx context.Context, login *OIDCLogin, code string) (*OIDCClaims, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"client_id":     {p.config.ClientID},
+		"code_verifier": {login.Verifier},
+	}
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrOIDCLogin
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&tokens); err != nil || tokens.IDToken == "" {
+		return nil, ErrOIDCLogin
+	}
+	return p.verifyIDToken(tokens.IDToken, login.Nonce)
+}
+
+// verifyIDToken checks the ID token's signature, issuer, audience, expiry and nonce
+func (p *OIDCProvider) verifyIDToken(raw, nonce string) (*OIDCClaims, error) {
+	claims := &OIDCClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, verificationKey(nil, p.keys),
+		jwt.WithValidMethods(oidcAlgorithms),
+		jwt.WithIssuer(p.config.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, ErrOIDCLogin
+	}
+	if claims.Subject == "" || subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, ErrOIDCLogin
+	}
+	return claims, nil
+}
+
+// Roles maps the user's groups or app roles at the provider to our roles, on top of the
+// default roles
+func (p *OIDCProvider) Roles(claims *OIDCClaims) []string {
+	roles := append([]string{}, p.config.DefaultRoles...)
+	if p.config.RoleClaim == "" {
+		return roles
+	}
+
+	// The claim is a list at most providers, but a single value at some
+	var values []string
+	if err := json.Unmarshal(claims.claims[p.config.RoleClaim], &values); err != nil {
+		var value string
+		if json.Unmarshal(claims.claims[p.config.RoleClaim], &value) == nil {
+			values = []string{value}
+		}
+	}
+	for _, value := range values {
+		role, ok := p.config.RoleMap[value]
+		if ok && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// getJSON fetches a JSON document of up to 1MB
+func getJSON(ctx context.Context, client *http.Client, url string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request returned %s", resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(dest)
+}
+
+// ParseOIDCRoleMap parses a role map of comma-separated "value=role" pairs, e.g.
+// "task-admins=admin,engineering=user"
+func ParseOIDCRoleMap(spec string) (map[string]string, error) {
+	roles := map[string]string{}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		value, role, ok := strings.Cut(pair, "=")
+		value, role = strings.TrimSpace(value), strings.TrimSpace(role)
+		if !ok || value == "" || role == "" {
+			return nil, fmt.Errorf("invalid role mapping %q: want value=role", pair)
+		}
+		if _, known := DefaultRoles[role]; !known {
+			return nil, fmt.Errorf("invalid role mapping %q: unknown role %q", pair, role)
+		}
+		roles[value] = role
+	}
+	return roles, nil
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIdP is an OpenID Connect provider issuing ID tokens with the claims tests set
+type fakeIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+	// challenge is the PKCE challenge the authorization request carried
+	challenge string
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	idp := &fakeIdP{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "idp-1", "n": b64(key.N), "e": b64(big.NewInt(int64(key.E))),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != "code-1" || base64.RawURLEncoding.EncodeToString(sum[:]) != idp.challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims)
+		token.Header["kid"] = "idp-1"
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func TestOIDCProvider_SignIn(t *testing.T) {
+	idp := newFakeIdP(t)
+	provider, err := NewOIDCProvider(context.Background(), OIDCConfig{
+		Name:         "azure",
+		Issuer:       idp.server.URL,
+		ClientID:     "client-1",
+		RedirectURL:  "https://tasks.example.com/api/v1/auth/oidc/azure/callback",
+		RoleClaim:    "groups",
+		RoleMap:      map[string]string{"task-admins": "admin"},
+		DefaultRoles: []string{"user"},
+	}, idp.server.Client())
+	require.NoError(t, err)
+
+	login, err := NewOIDCLogin("azure")
+	require.NoError(t, err)
+	authURL, err := url.Parse(provider.AuthCodeURL(login))
+	require.NoError(t, err)
+	assert.Equal(t, "/authorize", authURL.Path)
+	assert.Equal(t, login.State, authURL.Query().Get("state"))
+	assert.Equal(t, "S256", authURL.Query().Get("code_challenge_method"))
+	idp.challenge = authURL.Query().Get("code_challenge")
+
+	claims := func(nonce string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": idp.server.URL, "aud": "client-1", "sub": "idp-user-1",
+			"exp": time.Now().Add(time.Minute).Unix(), "nonce": nonce,
+			"email": "ada@example.com", "groups": []string{"task-admins", "other"},
+		}
+	}
+
+	idp.claims = claims(login.Nonce)
+	got, err := provider.Exchange(context.Background(), login, "code-1")
+	require.NoError(t, err)
+	assert.Equal(t, "idp-user-1", got.Subject)
+	assert.Equal(t, "ada@example.com", got.Email)
+	assert.Equal(t, []string{"user", "admin"}, provider.Roles(got))
+
+	// A token issued for another sign-in is rejected
+	idp.claims = claims("other-nonce")
+	_, err = provider.Exchange(context.Background(), login, "code-1")
+	assert.ErrorIs(t, err, ErrOIDCLogin)
+
+	// So is a code redeemed without the sign-in's verifier
+	idp.claims = claims(login.Nonce)
+	_, err = provider.Exchange(context.Background(), &OIDCLogin{Nonce: login.Nonce, Verifier: "guessed"}, "code-1")
+	assert.ErrorIs(t, err, ErrOIDCLogin)
+}
+
+func TestOIDCProvider_Roles(t *testing.T) {
+	provider := &OIDCProvider{config: OIDCConfig{
+		RoleClaim:    "roles",
+		RoleMap:      map[string]string{"Tasks.Admin": "admin", "Tasks.Read": "viewer"},
+		DefaultRoles: []string{"user"},
+	}}
+	decode := func(raw string) *OIDCClaims {
+		claims := &OIDCClaims{}
+		require.NoError(t, json.Unmarshal([]byte(raw), claims))
+		return claims
+	}
+
+	assert.Equal(t, []string{"user", "viewer"}, provider.Roles(decode(`{"roles":["Tasks.Read","Unknown"]}`)))
+	assert.Equal(t, []string{"user", "admin"}, provider.Roles(decode(`{"roles":"Tasks.Admin"}`)))
+	assert.Equal(t, []string{"user"}, provider.Roles(decode(`{"sub":"idp-user-1"}`)))
+}
+
+func TestParseOIDCRoleMap(t *testing.T) {
+	roles, err := ParseOIDCRoleMap("task-admins=admin, engineering = user")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"task-admins": "admin", "engineering": "user"}, roles)
+
+	_, err = ParseOIDCRoleMap("task-admins")
+	assert.EqualError(t, err, `invalid role mapping "task-admins": want value=role`)
+	_, err = ParseOIDCRoleMap("task-admins=root")
+	assert.EqualError(t, err, `invalid role mapping "task-admins=root": unknown role "root"`)
+}
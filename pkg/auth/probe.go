@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// ProbeUserID is the user ID of the synthetic principal used by monitoring probes
+const ProbeUserID = "probe"
+
+// ProbeTokenHeader carries the shared probe token
+const ProbeTokenHeader = "X-Probe-Token"
+
+// ProbeAccess lets monitoring systems without user accounts read selected routes
+// (e.g. detailed health checks) with a shared token
+type ProbeAccess struct {
+	Token  string
+	Routes []PublicRoute // routes probes may read; only GET requests are ever allowed
+}
+
+// probeClaims returns claims for the probe principal if the request carries the probe token
+// for a probe-readable route
+func (p *ProbeAccess) probeClaims(r *http.Request) (*Claims, bool) {
+	if p == nil || p.Token == "" || r.Method != http.MethodGet || !isPublicRoute(p.Routes, r) {
+		return nil, false
+	}
+
+	token := r.Header.Get(ProbeTokenHeader)
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(p.Token)) != 1 {
+		return nil, false
+	}
+
+	return &Claims{
+		UserID: ProbeUserID,
+		Roles:  []string{"probe"},
+		probe:  true,
+	}, true
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// RevocationStore keeps revoked token IDs, e.g. cache.RedisCache
+type RevocationStore interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	// Exists reports whether any of the keys is set
+	Exists(ctx context.Context, keys ...string) (bool, error)
+}
+
+// RevocationList records revoked tokens, by ID (jti) or by family, until they would have
+// expired anyway, so it only ever holds tokens that could still be used. It also tracks
+// which refresh tokens were rotated. A nil list revokes nothing.
+type RevocationList struct {
+	store RevocationStore
+	now   func() time.Time
+}
+
+// NewRevocationList creates a revocation list kept in store, shared by every replica
+func NewRevocationList(store RevocationStore) *RevocationList {
+	return &RevocationList{store: store, now: time.Now}
+}
+
+// revocationKey is the store key marking a token ID as revoked
+func revocationKey(tokenID string) string {
+	return "revoked:jti:" + tokenID
+}
+
+// familyRevocationKey is the store key marking a token family as revoked
+func familyRevocationKey(family string) string {
+	return "revoked:family:" + family
+}
+
+// rotationKey is the store key marking a refresh token as exchanged for a new one
+func rotationKey(tokenID string) string {
+	return "rotated:jti:" + tokenID
+}
+
+// Revoke marks the token ID as revoked until the token expires
+func (l *RevocationList) Revoke(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := expiresAt.Sub(l.now())
+	if tokenID == "" || ttl <= 0 {
+		return nil
+	}
+	return l.store.Set(ctx, revocationKey(tokenID), true, ttl)
+}
+
+// RevokeFamily marks every token of the family as revoked until expiresAt, when the last
+// of them expires
+func (l *RevocationList) RevokeFamily(ctx context.Context, family string, expiresAt time.Time) error {
+	ttl := expiresAt.Sub(l.now())
+	if family == "" || ttl <= 0 {
+		return nil
+	}
+	return l.store.Set(ctx, familyRevocationKey(family), true, ttl)
+}
+
+// IsRevoked reports whether the token ID or the token's family was revoked. Tokens without
+// an ID or family can't be revoked.
+func (l *RevocationList) IsRevoked(ctx context.Context, tokenID, family string) (bool, error) {
+	if l == nil {
+		return false, nil
+	}
+	var keys []string
+	if tokenID != "" {
+		keys = append(keys, revocationKey(tokenID))
+	}
+	if family != "" {
+		keys = append(keys, familyRevocationKey(family))
+	}
+	if len(keys) == 0 {
+		return false, nil
+	}
+	return l.store.Exists(ctx, keys...)
+}
+
+// MarkRotated records that a refresh token was exchanged for a new one, until it expires.
+// It reports false when the token was exchanged before, i.e. it is being reused. Only one
+// of several concurrent calls for the same token succeeds.
+func (l *RevocationList) MarkRotated(ctx context.Context, tokenID string, expiresAt time.Time) (bool, error) {
+	ttl := expiresAt.Sub(l.now())
+	if tokenID == "" || ttl <= 0 {
+		return true, nil
+	}
+	return l.store.SetNX(ctx, rotationKey(tokenID), true, ttl)
+}
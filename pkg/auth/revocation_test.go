@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRevocationStore keeps revoked token IDs in a map, failing every call once err is set
+type memoryRevocationStore struct {
+	keys map[string]time.Duration
+	err  error
+}
+
+func (s *memoryRevocationStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.keys[key] = expiration
+	return nil
+}
+
+func (s *memoryRevocationStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	if _, ok := s.keys[key]; ok {
+		return false, nil
+	}
+	s.keys[key] = expiration
+	return true, nil
+}
+
+func (s *memoryRevocationStore) Exists(ctx context.Context, keys ...string) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	for _, key := range keys {
+		if _, ok := s.keys[key]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestTokenManager_Logout(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryRevocationStore{keys: map[string]time.Duration{}}
+	tm := NewTokenManager([]byte("test-secret"), "test-issuer", WithRevocationList(NewRevocationList(store)),
+		testUserRoles(map[string][]string{"user-1": {"user"}, "user-2": {"user"}}))
+
+	pair, err := tm.CreateTokenPair(context.Background(), "user-1", []string{"user"})
+	require.NoError(t, err)
+	access, err := tm.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+	require.NotEmpty(t, access.ID)
+
+	// Another user's refresh token can't be revoked
+	other, err := tm.CreateTokenPair(context.Background(), "user-2", []string{"user"})
+	require.NoError(t, err)
+	assert.ErrorIs(t, tm.Logout(ctx, access, other.RefreshToken), ErrInvalidToken)
+
+	require.NoError(t, tm.Logout(ctx, access, pair.RefreshToken))
+	_, err = tm.RefreshTokens(ctx, pair.RefreshToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+	revoked, err := NewRevocationList(store).IsRevoked(ctx, access.ID, "")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	// Entries are only kept until the tokens expire
+	for _, ttl := range store.keys {
+		assert.LessOrEqual(t, ttl, 7*24*time.Hour)
+	}
+
+	_, err = tm.RefreshTokens(ctx, other.RefreshToken)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, NewTokenManager([]byte("test-secret"), "test-issuer").Logout(ctx, access, ""), ErrRevocationDisabled)
+}
+
+func TestAuthMiddleware_RevokedToken(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryRevocationStore{keys: map[string]time.Duration{}}
+	tm := NewTokenManager([]byte("test-secret"), "test-issuer")
+	config := AuthConfig{
+		JWTSecret:    []byte("test-secret"),
+		Validation:   tm.ValidationOptions(),
+		AllowedRoles: DefaultRoles,
+		Revocations:  NewRevocationList(store),
+		// Cached tokens are checked against the list too
+		TokenCache: NewMemoryTokenCache(10),
+	}
+	handler := AuthMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	pair, err := tm.CreateTokenPair(context.Background(), "user-1", []string{"user"})
+	require.NoError(t, err)
+	claims, err := tm.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, serve(pair.AccessToken))
+
+	require.NoError(t, config.Revocations.Revoke(ctx, claims.ID, claims.ExpiresAt.Time))
+	assert.Equal(t, http.StatusUnauthorized, serve(pair.AccessToken))
+
+	// Tokens aren't let through when the list can't be checked
+	store.err = errors.New("redis down")
+	assert.Equal(t, http.StatusInternalServerError, serve(pair.AccessToken))
+}
+
+func TestTokenManager_RefreshRotation(t *testing.T) {
+	ctx := context.Background()
+	store := &memoryRevocationStore{keys: map[string]time.Duration{}}
+	tm := NewTokenManager([]byte("test-secret"), "test-issuer", WithRevocationList(NewRevocationList(store)),
+		testUserRoles(map[string][]string{"user-1": {"user"}, "user-2": {"user"}}))
+	config := AuthConfig{
+		JWTSecret:    []byte("test-secret"),
+		Validation:   tm.ValidationOptions(),
+		AllowedRoles: DefaultRoles,
+		Revocations:  NewRevocationList(store),
+	}
+	handler := AuthMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	first, err := tm.CreateTokenPair(context.Background(), "user-1", []string{"user"})
+	require.NoError(t, err)
+	second, err := tm.RefreshTokens(ctx, first.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+	third, err := tm.RefreshTokens(ctx, second.RefreshToken)
+	require.NoError(t, err)
+
+	// Refreshed tokens stay in the sign-in's family
+	firstAccess, err := tm.ValidateToken(first.AccessToken)
+	require.NoError(t, err)
+	thirdAccess, err := tm.ValidateToken(third.AccessToken)
+	require.NoError(t, err)
+	require.NotEmpty(t, firstAccess.Family)
+	assert.Equal(t, firstAccess.Family, thirdAccess.Family)
+
+	// Another sign-in is a family of its own
+	other, err := tm.CreateTokenPair(context.Background(), "user-1", []string{"user"})
+	require.NoError(t, err)
+
+	// A rotated token presented again revokes everything issued since the sign-in
+	_, err = tm.RefreshTokens(ctx, first.RefreshToken)
+	assert.ErrorIs(t, err, ErrTokenReused)
+	_, err = tm.RefreshTokens(ctx, third.RefreshToken)
+	assert.ErrorIs(t, err, ErrTokenRevoked)
+	assert.Equal(t, http.StatusUnauthorized, serve(third.AccessToken))
+
+	assert.Equal(t, http.StatusOK, serve(other.AccessToken))
+	_, err = tm.RefreshTokens(ctx, other.RefreshToken)
+	assert.NoError(t, err)
+}
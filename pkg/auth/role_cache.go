@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RoleSource loads every role definition, e.g. from the database
+type RoleSource func(ctx context.Context) (map[string]Role, error)
+
+// RoleCache serves role definitions from memory so permission checks never query the
+// source on the request path. Roles are reloaded in the background and right after they
+// change, through Invalidate.
+type RoleCache struct {
+	source   RoleSource
+	interval time.Duration
+	roles    atomic.Pointer[map[string]Role]
+	// loading serializes reloads, so a slow reload can't replace a newer one's roles
+	loading sync.Mutex
+}
+
+// NewRoleCache loads the roles from source, failing if they can't be loaded. Run keeps
+// them up to date every interval.
+func NewRoleCache(ctx context.Context, source RoleSource, interval time.Duration) (*RoleCache, error) {
+	c := &RoleCache{source: source, interval: interval}
+	if err := c.load(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Roles returns the roles loaded last. The map is shared and must not be modified.
+func (c *RoleCache) Roles() map[string]Role {
+	return *c.roles.Load()
+}
+
+// Invalidate reloads the roles now; call it after changing them. Other instances pick
+// the change up on their next background reload.
+func (c *RoleCache) Invalidate(ctx context.Context) error {
+	return c.load(ctx)
+}
+
+// Run reloads the roles every interval until ctx is done. A failed reload keeps the
+// roles loaded before, so an unavailable source doesn't lock users out.
+func (c *RoleCache) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.load(ctx); err != nil {
+				log.Printf("Failed to reload roles, keeping the previous ones: %v", err)
+			}
+		}
+	}
+}
+
+func (c *RoleCache) load(ctx context.Context) error {
+	c.loading.Lock()
+	defer c.loading.Unlock()
+
+	roles, err := c.source(ctx)
+	if err != nil {
+		return err
+	}
+	c.roles.Store(&roles)
+	return nil
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleCache(t *testing.T) {
+	ctx := context.Background()
+	loads := 0
+	var loadErr error
+	source := func(ctx context.Context) (map[string]Role, error) {
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		loads++
+		methods := []string{"GET"}
+		if loads > 1 {
+			methods = append(methods, "POST")
+		}
+		return map[string]Role{"user": {Name: "user", Permissions: map[string][]string{"/api/v1/tasks": methods}}}, nil
+	}
+
+	c, err := NewRoleCache(ctx, source, time.Hour)
+	require.NoError(t, err)
+	secret := []byte("test-secret")
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))},
+		UserID:           "user-1",
+		Roles:            []string{"user"},
+	}).SignedString(secret)
+	require.NoError(t, err)
+	handler := AuthMiddleware(AuthConfig{JWTSecret: secret, RoleCache: c})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method string) int {
+		r := httptest.NewRequest(method, "/api/v1/tasks", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	// Requests are checked against the cached roles without loading them again
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet))
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost))
+	assert.Equal(t, 1, loads)
+
+	require.NoError(t, c.Invalidate(ctx))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost))
+
+	// A failed reload keeps the roles loaded before
+	loadErr = errors.New("database unavailable")
+	assert.Error(t, c.Invalidate(ctx))
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost))
+}
+
+func TestNewRoleCache_FailsWithoutRoles(t *testing.T) {
+	_, err := NewRoleCache(context.Background(), func(ctx context.Context) (map[string]Role, error) {
+		return nil, errors.New("database unavailable")
+	}, time.Hour)
+	assert.EqualError(t, err, "database unavailable")
+}
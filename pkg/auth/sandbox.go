@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SandboxUserID is the user ID of the demo principal of a sandbox instance
+const SandboxUserID = "sandbox-user"
+
+// SandboxAccess relaxes authentication on sandbox instances: the fixed demo token is
+// accepted as a bearer token in place of a JWT. It must never be set in production.
+type SandboxAccess struct {
+	Token string
+	Roles []string // roles of the demo principal
+}
+
+// sandboxClaims returns claims for the demo principal if token is the demo token. The
+// principal counts as freshly authenticated, so step-up checks pass.
+func (s *SandboxAccess) sandboxClaims(token string) (*Claims, bool) {
+	if s == nil || s.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+		return nil, false
+	}
+
+	return &Claims{
+		UserID:     SandboxUserID,
+		Roles:      s.Roles,
+		AuthTime:   jwt.NewNumericDate(time.Now()),
+		FirstParty: true,
+	}, true
+}
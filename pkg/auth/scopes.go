@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Known token scopes
+const (
+	ScopeTasksRead      = "tasks:read"
+	ScopeTasksWrite     = "tasks:write"
+	ScopeWebhooksManage = "webhooks:manage"
+)
+
+// KnownScopes lists every scope a token can be granted
+var KnownScopes = []string{ScopeTasksRead, ScopeTasksWrite, ScopeWebhooksManage}
+
+// DefaultRoleScopes maps roles to the scopes issued in their user tokens
+var DefaultRoleScopes = map[string][]string{
+	"admin":  {ScopeTasksRead, ScopeTasksWrite, ScopeWebhooksManage},
+	"user":   {ScopeTasksRead, ScopeTasksWrite},
+	"viewer": {ScopeTasksRead},
+}
+
+// Scopes returns the scopes granted to the token
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// HasScope checks if the token grants all of the given scopes. Tokens without a scope
+// claim grant none, unless they are first-party sessions, which are limited by their roles.
+func (c *Claims) HasScope(scopes ...string) bool {
+	if c.Scope == "" {
+		return c.FirstParty
+	}
+
+	granted := c.Scopes()
+	for _, required := range scopes {
+		found := false
+		for _, scope := range granted {
+			if scope == required {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// scopesForRoles returns the union of scopes for the given roles
+func scopesForRoles(roles []string) []string {
+	seen := make(map[string]bool)
+	var scopes []string
+	for _, role := range roles {
+		for _, scope := range DefaultRoleScopes[role] {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// RequireScopes middleware checks that the token grants all of the given scopes.
+// It runs after AuthMiddleware, layering scope checks on top of role checks.
+func RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := r.Context().Value("claims").(*Claims)
+			if !ok {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			if !claims.HasScope(scopes...) {
+				http.Error(w, ErrInsufficientScope.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireReadWriteScopes requires readScope for safe methods (GET, HEAD, OPTIONS)
+// and writeScope for everything else
+func RequireReadWriteScopes(readScope, writeScope string) func(http.Handler) http.Handler {
+	read := RequireScopes(readScope)
+	write := RequireScopes(writeScope)
+
+	return func(next http.Handler) http.Handler {
+		readHandler := read(next)
+		writeHandler := write(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				readHandler.ServeHTTP(w, r)
+			default:
+				writeHandler.ServeHTTP(w, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaims_HasScope(t *testing.T) {
+	tests := []struct {
+		name       string
+		scope      string
+		firstParty bool
+		required   []string
+		want       bool
+	}{
+		{"unscoped token", "", false, []string{ScopeTasksWrite}, false},
+		{"unscoped first-party session", "", true, []string{ScopeTasksWrite}, true},
+		{"granted scope", "tasks:read tasks:write", false, []string{ScopeTasksWrite}, true},
+		{"missing scope", "tasks:read", false, []string{ScopeTasksWrite}, false},
+		{"scoped first-party session", "tasks:read", true, []string{ScopeTasksWrite}, false},
+		{"all scopes required", "tasks:read", false, []string{ScopeTasksRead, ScopeWebhooksManage}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &Claims{Scope: tt.scope, FirstParty: tt.firstParty}
+			assert.Equal(t, tt.want, claims.HasScope(tt.required...))
+		})
+	}
+}
+
+func TestRequireReadWriteScopes(t *testing.T) {
+	handler := RequireReadWriteScopes(ScopeTasksRead, ScopeTasksWrite)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		claims *Claims
+		want   int
+	}{
+		{"read with read scope", http.MethodGet, &Claims{Scope: ScopeTasksRead}, http.StatusOK},
+		{"write with read scope", http.MethodPost, &Claims{Scope: ScopeTasksRead}, http.StatusForbidden},
+		{"write with write scope", http.MethodDelete, &Claims{Scope: ScopeTasksWrite}, http.StatusOK},
+		{"no claims", http.MethodGet, nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/tasks", nil)
+			if tt.claims != nil {
+				req = req.WithContext(context.WithValue(req.Context(), "claims", tt.claims))
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestCreateTokenPair_RoleScopes(t *testing.T) {
+	tm := NewTokenManager([]byte("test-secret"), "test-issuer")
+
+	pair, err := tm.CreateTokenPair(context.Background(), "user-1", []string{"viewer"})
+	require.NoError(t, err)
+
+	claims, err := tm.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, []string{ScopeTasksRead}, claims.Scopes())
+	assert.True(t, claims.FirstParty)
+
+	client, err := tm.CreateClientToken("client-1", []string{"viewer"}, []string{ScopeTasksRead})
+	require.NoError(t, err)
+	claims, err = tm.ValidateToken(client.AccessToken)
+	require.NoError(t, err)
+	assert.False(t, claims.FirstParty)
+}
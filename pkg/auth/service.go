@@ -12,13 +12,17 @@ func GetUserFromContext(ctx context.Context) (User, error) {
 	}
 
 	return User{
-		ID:    claims.UserID,
-		Roles: claims.Roles,
+		ID:        claims.UserID,
+		Roles:     claims.Roles,
+		ActorID:   claims.ActorID(),
+		ActorChain: claims.ActorChain(),
+		OrgID:     claims.OrgID,
+		ProjectID: claims.ProjectID,
 	}, nil
 }
 
 // CanAccessResource checks if a user can access a specific resource
-func CanAccessResource(ctx context.Context, resourceType, resourceID string) error {
+func CanAccessResource(ctx context.Context, resourceID string, checker OwnershipChecker) error {
 	user, err := GetUserFromContext(ctx)
 	if err != nil {
 		return err
@@ -30,7 +34,11 @@ func CanAccessResource(ctx context.Context, resourceType, resourceID string) err
 	}
 
 	// Check resource ownership
-	if !hasResourceAccess(user.ID, resourceType, resourceID) {
+	allowed, err := checker(ctx, user.ID, resourceID)
+	if err != nil {
+		return err
+	}
+	if !allowed {
 		return ErrResourceNotOwned
 	}
 
@@ -39,15 +47,16 @@ func CanAccessResource(ctx context.Context, resourceType, resourceID string) err
 
 // Example service-level authorization check
 type TaskService struct {
+	ownership OwnershipChecker
 	// ... other fields ...
 }
 
 func (s *TaskService) UpdateTask(ctx context.Context, taskID string, update interface{}) error {
 	// Check if user can access this task
-	if err := CanAccessResource(ctx, "task", taskID); err != nil {
+	if err := CanAccessResource(ctx, taskID, s.ownership); err != nil {
 		return err
 	}
 
 	// Proceed with update
 	return nil
-} 
\ No newline at end of file
+}
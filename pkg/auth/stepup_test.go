@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireStepUp(t *testing.T) {
+	handler := RequireStepUp(10*time.Minute, http.MethodDelete)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	recent := jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	stale := jwt.NewNumericDate(time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name   string
+		method string
+		claims *Claims
+		want   int
+	}{
+		{"unchecked method", http.MethodGet, &Claims{AuthTime: stale}, http.StatusOK},
+		{"recent auth", http.MethodDelete, &Claims{AuthTime: recent}, http.StatusOK},
+		{"stale auth", http.MethodDelete, &Claims{AuthTime: stale}, http.StatusUnauthorized},
+		{"stale auth with second factor", http.MethodDelete, &Claims{AuthTime: stale, AMR: []string{"pwd", "mfa"}}, http.StatusUnauthorized},
+		{"second factor without auth time", http.MethodDelete, &Claims{AMR: []string{"mfa"}}, http.StatusUnauthorized},
+		{"issued at doesn't count", http.MethodDelete, &Claims{RegisteredClaims: jwt.RegisteredClaims{IssuedAt: recent}}, http.StatusUnauthorized},
+		{"no auth time", http.MethodDelete, &Claims{}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/tasks/1", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "claims", tt.claims))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+
+			if tt.want == http.StatusUnauthorized {
+				var body map[string]interface{}
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+				assert.Equal(t, "step_up_required", body["error"])
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "max_age=600")
+			}
+		})
+	}
+}
+
+func TestRequireStepUpOn(t *testing.T) {
+	handler := RequireStepUpOn(10*time.Minute, PublicRoute{Methods: []string{http.MethodPost}, Pattern: "/api/v1/webhooks"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+	stale := &Claims{AuthTime: jwt.NewNumericDate(time.Now().Add(-time.Hour))}
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{http.MethodPost, "/api/v1/webhooks", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/webhooks", http.StatusOK},
+		{http.MethodPost, "/api/v1/webhooks/hook-1/test", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req = req.WithContext(context.WithValue(req.Context(), "claims", stale))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, tt.want, rec.Code, tt.method+" "+tt.path)
+	}
+}
+
+func TestClaims_HasSecondFactor(t *testing.T) {
+	assert.True(t, (&Claims{AMR: []string{"pwd", "otp"}}).HasSecondFactor())
+	assert.False(t, (&Claims{AMR: []string{"pwd"}}).HasSecondFactor())
+	assert.False(t, (&Claims{}).HasSecondFactor())
+}
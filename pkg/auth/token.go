@@ -1,10 +1,13 @@
 package auth
 
 import (
-	"errors"
+	"context"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // TokenManager handles JWT token operations
@@ -13,6 +16,74 @@ type TokenManager struct {
 	issuer        string
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
+	validation    ValidationOptions
+	// signingKey, if set, signs tokens instead of secretKey; publicKeys verify them
+	signingKey *SigningKey
+	publicKeys PublicKeys
+	// revocations, if set, rejects revoked refresh and subject tokens
+	revocations *RevocationList
+	// userRoles looks up the current roles of users for refreshed and impersonation tokens
+	userRoles UserRoleLookup
+	// userOrgs looks up the organization every user token is scoped to
+	userOrgs UserOrgLookup
+}
+
+// UserRoleLookup returns a user's current roles, e.g. from the user repository
+type UserRoleLookup func(ctx context.Context, userID string) ([]string, error)
+
+// UserOrgLookup returns the organization a user's tokens are scoped to and the role the
+// user has in it, or empty strings for users outside any organization
+type UserOrgLookup func(ctx context.Context, userID string) (orgID, role string, err error)
+
+// TokenManagerOption configures a TokenManager
+type TokenManagerOption func(*TokenManager)
+
+// WithAudience sets the "aud" claim on issued tokens and requires it when validating
+func WithAudience(audience string) TokenManagerOption {
+	return func(tm *TokenManager) { tm.validation.Audience = audience }
+}
+
+// WithLeeway sets the clock skew tolerance used when validating tokens
+func WithLeeway(leeway time.Duration) TokenManagerOption {
+	return func(tm *TokenManager) { tm.validation.Leeway = leeway }
+}
+
+// WithAllowedAlgorithms restricts the signing algorithms accepted when validating tokens
+func WithAllowedAlgorithms(algorithms ...string) TokenManagerOption {
+	return func(tm *TokenManager) { tm.validation.AllowedAlgorithms = algorithms }
+}
+
+// WithAccessExpiry sets how long issued access tokens are valid
+func WithAccessExpiry(expiry time.Duration) TokenManagerOption {
+	return func(tm *TokenManager) { tm.accessExpiry = expiry }
+}
+
+// WithRevocationList checks refreshed and exchanged tokens against the revocation list and
+// records tokens revoked by Logout in it
+func WithRevocationList(list *RevocationList) TokenManagerOption {
+	return func(tm *TokenManager) { tm.revocations = list }
+}
+
+// WithUserRoles looks up users' roles when refreshing tokens and impersonating users.
+// Without it both fail, since their roles can't be known.
+func WithUserRoles(lookup UserRoleLookup) TokenManagerOption {
+	return func(tm *TokenManager) { tm.userRoles = lookup }
+}
+
+// WithUserOrgs scopes every token issued to a user, including impersonation tokens, to
+// the user's organization and adds their role in it. Without it user tokens carry no
+// org_id, so their users share the default tenant.
+func WithUserOrgs(lookup UserOrgLookup) TokenManagerOption {
+	return func(tm *TokenManager) { tm.userOrgs = lookup }
+}
+
+// WithSigningKey signs issued tokens with a private key instead of the shared secret.
+// Tokens signed with the secret still validate while its algorithm is allowed.
+func WithSigningKey(key *SigningKey) TokenManagerOption {
+	return func(tm *TokenManager) {
+		tm.signingKey = key
+		tm.publicKeys = PublicKeys{key.ID: key.Key.Public()}
+	}
 }
 
 // TokenPair represents an access and refresh token pair
@@ -22,26 +93,82 @@ type TokenPair struct {
 	ExpiresIn    int64  `json:"expires_in"` // seconds until access token expires
 }
 
+// ClientToken represents an OAuth2 access token issued to a client
+type ClientToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+	// IssuedTokenType is set for token exchange responses (RFC 8693 section 2.2.1)
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+}
+
 // NewTokenManager creates a new token manager
-func NewTokenManager(secretKey []byte, issuer string) *TokenManager {
-	return &TokenManager{
+func NewTokenManager(secretKey []byte, issuer string, opts ...TokenManagerOption) *TokenManager {
+	tm := &TokenManager{
 		secretKey:     secretKey,
 		issuer:        issuer,
 		accessExpiry:  15 * time.Minute,  // Access tokens expire in 15 minutes
 		refreshExpiry: 7 * 24 * time.Hour, // Refresh tokens expire in 7 days
+		validation:    ValidationOptions{Issuer: issuer},
+	}
+	for _, opt := range opts {
+		opt(tm)
 	}
+	return tm
 }
 
-// CreateTokenPair generates a new access and refresh token pair
-func (tm *TokenManager) CreateTokenPair(userID string, roles []string) (*TokenPair, error) {
+// ValidationOptions returns the options tokens issued by this manager are validated with
+func (tm *TokenManager) ValidationOptions() ValidationOptions {
+	return tm.validation
+}
+
+// audience returns the "aud" claim for issued tokens
+func (tm *TokenManager) audience() jwt.ClaimStrings {
+	if tm.validation.Audience == "" {
+		return nil
+	}
+	return jwt.ClaimStrings{tm.validation.Audience}
+}
+
+// TokenUseRefresh is the token_use claim of refresh tokens. They are signed like access
+// tokens, so the claim is what keeps one kind from passing as the other.
+const TokenUseRefresh = "refresh"
+
+// refreshClaims are the claims carried by refresh tokens
+type refreshClaims struct {
+	jwt.RegisteredClaims
+	AuthTime *jwt.NumericDate `json:"auth_time,omitempty"`
+	Family   string           `json:"fam,omitempty"`
+	TokenUse string           `json:"token_use"`
+}
+
+// CreateTokenPair generates a new access and refresh token pair for a user who just authenticated.
+// The access token is scoped to the user's organization and to the union of
+// DefaultRoleScopes for the user's roles.
+func (tm *TokenManager) CreateTokenPair(ctx context.Context, userID string, roles []string) (*TokenPair, error) {
+	return tm.createTokenPair(ctx, userID, roles, time.Now(), "")
+}
+
+// createTokenPair generates a token pair carrying the original authentication time. Both
+// tokens belong to family, or to a new family when it is empty.
+func (tm *TokenManager) createTokenPair(ctx context.Context, userID string, roles []string, authTime time.Time, family string) (*TokenPair, error) {
+	if family == "" {
+		family = uuid.NewString()
+	}
+	orgID, roles, err := tm.lookupUserOrg(ctx, userID, roles)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create access token
-	accessToken, err := tm.createToken(userID, roles, tm.accessExpiry)
+	accessToken, err := tm.createToken(userID, orgID, roles, scopesForRoles(roles), &authTime, family, tm.accessExpiry)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create refresh token
-	refreshToken, err := tm.createRefreshToken(userID)
+	refreshToken, err := tm.createRefreshToken(userID, authTime, family)
 	if err != nil {
 		return nil, err
 	}
@@ -53,8 +180,70 @@ func (tm *TokenManager) CreateTokenPair(userID string, roles []string) (*TokenPa
 	}, nil
 }
 
-// createToken generates a new JWT token
-func (tm *TokenManager) createToken(userID string, roles []string, expiry time.Duration) (string, error) {
+// CreateClientToken generates an access token for an OAuth2 client_credentials grant.
+// Client tokens have no refresh token; clients request a new one when it expires.
+func (tm *TokenManager) CreateClientToken(clientID string, roles, scopes []string) (*ClientToken, error) {
+	accessToken, err := tm.createToken(clientID, "", roles, scopes, nil, "", tm.accessExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(tm.accessExpiry.Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// CreateImpersonationToken generates a short-lived access token that acts as
+// subjectID, in the subject's organization, while recording actorID in the act claim.
+// No refresh token is issued.
+func (tm *TokenManager) CreateImpersonationToken(ctx context.Context, actorID, subjectID string, expiry time.Duration) (*ClientToken, error) {
+	roles, err := tm.lookupUserRoles(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	orgID, roles, err := tm.lookupUserOrg(ctx, subjectID, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	scopes := scopesForRoles(roles)
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    tm.issuer,
+			Audience:  tm.audience(),
+			Subject:   subjectID,
+			ID:        generateTokenID(),
+		},
+		UserID: subjectID,
+		Roles:  roles,
+		Scope:  strings.Join(scopes, " "),
+		Act:    &Actor{Subject: actorID},
+		OrgID:  orgID,
+	}
+
+	token, err := tm.sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientToken{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(expiry.Seconds()),
+		Scope:       claims.Scope,
+	}, nil
+}
+
+// createToken generates a new JWT token; authTime is nil and family empty for
+// non-interactive clients
+func (tm *TokenManager) createToken(userID, orgID string, roles, scopes []string, authTime *time.Time, family string, expiry time.Duration) (string, error) {
 	now := time.Now()
 	claims := &Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -62,99 +251,195 @@ func (tm *TokenManager) createToken(userID string, roles []string, expiry time.D
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    tm.issuer,
+			Audience:  tm.audience(),
 			Subject:   userID,
+			ID:        generateTokenID(), // Unique ID for token revocation
 		},
 		UserID: userID,
 		Roles:  roles,
+		Scope:  strings.Join(scopes, " "),
+		Family: family,
+		OrgID:  orgID,
+	}
+	// Only user sign-ins carry an authentication time
+	if authTime != nil {
+		claims.AuthTime = jwt.NewNumericDate(*authTime)
+		claims.FirstParty = true
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(tm.secretKey)
+	return tm.sign(claims)
 }
 
 // createRefreshToken generates a new refresh token
-func (tm *TokenManager) createRefreshToken(userID string) (string, error) {
+func (tm *TokenManager) createRefreshToken(userID string, authTime time.Time, family string) (string, error) {
 	now := time.Now()
-	claims := jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(now.Add(tm.refreshExpiry)),
-		IssuedAt:  jwt.NewNumericDate(now),
-		NotBefore: jwt.NewNumericDate(now),
-		Issuer:    tm.issuer,
-		Subject:   userID,
-		ID:        generateTokenID(), // Unique ID for token revocation
+	claims := refreshClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(tm.refreshExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    tm.issuer,
+			Audience:  tm.audience(),
+			Subject:   userID,
+			ID:        generateTokenID(), // Unique ID for token revocation
+		},
+		AuthTime: jwt.NewNumericDate(authTime),
+		Family:   family,
+		TokenUse: TokenUseRefresh,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(tm.secretKey)
+	return tm.sign(claims)
 }
 
-// RefreshTokens validates a refresh token and issues new token pair
-func (tm *TokenManager) RefreshTokens(refreshToken string) (*TokenPair, error) {
-	// Parse the refresh token
-	token, err := jwt.ParseWithClaims(refreshToken, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return tm.secretKey, nil
-	})
+// parseRefreshToken validates a refresh token and returns its claims. Access tokens,
+// including impersonation, exchanged and client tokens, are rejected.
+func (tm *TokenManager) parseRefreshToken(refreshToken string) (*refreshClaims, error) {
+	token, err := jwt.ParseWithClaims(refreshToken, &refreshClaims{}, verificationKey(tm.secretKey, tm.publicKeys),
+		tm.validation.parserOptions()...)
 
+	if err != nil {
+		return nil, mapValidationError(err)
+	}
+
+	claims, ok := token.Claims.(*refreshClaims)
+	if !ok || !token.Valid || claims.TokenUse != TokenUseRefresh {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// RefreshTokens validates a refresh token and issues a new token pair in its family. With a
+// revocation list, each refresh token can be exchanged only once: presenting one again means
+// someone else holds a copy, so the whole family is revoked and ErrTokenReused returned.
+func (tm *TokenManager) RefreshTokens(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	claims, err := tm.parseRefreshToken(refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok && token.Valid {
-		// Check if token is revoked (implement token blacklist check here)
-		if isTokenRevoked(claims.ID) {
-			return nil, ErrInvalidToken
-		}
+	// Tokens issued before families existed start one named after themselves
+	family := claims.Family
+	if family == "" {
+		family = claims.ID
+	}
 
-		// Get user roles from your user service/database
-		roles, err := getUserRoles(claims.Subject)
+	revoked, err := tm.revocations.IsRevoked(ctx, claims.ID, family)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	if tm.revocations != nil && claims.ExpiresAt != nil {
+		first, err := tm.revocations.MarkRotated(ctx, claims.ID, claims.ExpiresAt.Time)
 		if err != nil {
 			return nil, err
 		}
+		if !first {
+			// Every token of the family was issued by now, so none outlives refreshExpiry
+			if err := tm.revocations.RevokeFamily(ctx, family, time.Now().Add(tm.refreshExpiry)); err != nil {
+				return nil, err
+			}
+			return nil, ErrTokenReused
+		}
+	}
 
-		// Create new token pair
-		return tm.CreateTokenPair(claims.Subject, roles)
+	// Roles may have changed since the sign-in
+	roles, err := tm.lookupUserRoles(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, ErrInvalidToken
+	// Create new token pair, keeping the original authentication time
+	// so refreshing doesn't count as re-authentication
+	var authTime time.Time
+	switch {
+	case claims.AuthTime != nil:
+		authTime = claims.AuthTime.Time
+	case claims.IssuedAt != nil:
+		authTime = claims.IssuedAt.Time
+	}
+	return tm.createTokenPair(ctx, claims.Subject, roles, authTime, family)
 }
 
-// ValidateToken validates a JWT token and returns its claims
+// Logout revokes the caller's access token and, if given, the refresh token of the same
+// user, so neither can be used again
+func (tm *TokenManager) Logout(ctx context.Context, access *Claims, refreshToken string) error {
+	if tm.revocations == nil {
+		return ErrRevocationDisabled
+	}
+	if access.ID == "" || access.ExpiresAt == nil {
+		return ErrInvalidToken
+	}
+
+	if refreshToken != "" {
+		// A refresh token can only be revoked by its own user
+		refresh, err := tm.parseRefreshToken(refreshToken)
+		if err != nil || refresh.Subject != access.UserID || refresh.ID == "" || refresh.ExpiresAt == nil {
+			return ErrInvalidToken
+		}
+		if err := tm.revocations.Revoke(ctx, refresh.ID, refresh.ExpiresAt.Time); err != nil {
+			return err
+		}
+	}
+	return tm.revocations.Revoke(ctx, access.ID, access.ExpiresAt.Time)
+}
+
+// ValidateToken validates an access token and returns its claims; refresh tokens are rejected
 func (tm *TokenManager) ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return tm.secretKey, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, verificationKey(tm.secretKey, tm.publicKeys),
+		tm.validation.parserOptions()...)
 
 	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, ErrExpiredToken
-		}
-		return nil, err
+		return nil, mapValidationError(err)
 	}
 
-	if !token.Valid {
+	if !token.Valid || claims.TokenUse == TokenUseRefresh {
 		return nil, ErrInvalidToken
 	}
 
-	if claims.Issuer != tm.issuer {
-		return nil, ErrInvalidIssuer
-	}
-
 	return claims, nil
 }
 
-// Helper functions (to be implemented based on your storage solution)
+// sign signs claims with the signing key, or with the shared secret (HS256) without one
+func (tm *TokenManager) sign(claims jwt.Claims) (string, error) {
+	if tm.signingKey == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(tm.secretKey)
+	}
+	token := jwt.NewWithClaims(tm.signingKey.Method, claims)
+	if tm.signingKey.ID != "" {
+		token.Header["kid"] = tm.signingKey.ID
+	}
+	return token.SignedString(tm.signingKey.Key)
+}
+
+// generateTokenID returns a unique token ID (jti), by which tokens are revoked
 func generateTokenID() string {
-	// Implement unique ID generation (e.g., UUID)
-	return "unique-token-id"
+	return uuid.NewString()
 }
 
-func isTokenRevoked(tokenID string) bool {
-	// Implement token revocation check (e.g., Redis blacklist)
-	return false
+// lookupUserOrg returns the organization of the user and roles with the user's role in it
+// added, or no organization and roles unchanged without a lookup
+func (tm *TokenManager) lookupUserOrg(ctx context.Context, userID string, roles []string) (string, []string, error) {
+	if tm.userOrgs == nil {
+		return "", roles, nil
+	}
+	orgID, role, err := tm.userOrgs(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if role != "" && !slices.Contains(roles, role) {
+		roles = append(append([]string(nil), roles...), role)
+	}
+	return orgID, roles, nil
 }
 
-func getUserRoles(userID string) ([]string, error) {
-	// Implement role lookup from your database
-	return []string{"user"}, nil
+// lookupUserRoles returns the user's current roles
+func (tm *TokenManager) lookupUserRoles(ctx context.Context, userID string) ([]string, error) {
+	if tm.userRoles == nil {
+		return nil, ErrUserRolesUnavailable
+	}
+	return tm.userRoles(ctx, userID)
 } 
\ No newline at end of file
@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TokenCache stores claims of already validated tokens so chatty clients don't
+// pay for signature verification on every request. Keys are token hashes, never
+// raw tokens.
+type TokenCache interface {
+	Get(token string) (*Claims, bool)
+	Set(token string, claims *Claims)
+	Stats() TokenCacheStats
+}
+
+// TokenCacheStats reports token cache effectiveness
+type TokenCacheStats struct {
+	Hits    uint64  `json:"hits"`
+	Misses  uint64  `json:"misses"`
+	Size    int     `json:"size"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+type tokenCacheEntry struct {
+	claims    *Claims
+	expiresAt time.Time
+}
+
+type memoryTokenCache struct {
+	mu         sync.RWMutex
+	entries    map[string]tokenCacheEntry
+	maxEntries int
+	now        func() time.Time
+	hits       uint64
+	misses     uint64
+}
+
+// NewMemoryTokenCache creates an in-process token cache holding at most maxEntries tokens.
+// Entries expire at the token's exp claim. Revocation isn't checked here: AuthMiddleware
+// checks the revocation list for cached and freshly validated tokens alike.
+func NewMemoryTokenCache(maxEntries int) TokenCache {
+	return &memoryTokenCache{
+		entries:    make(map[string]tokenCacheEntry),
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// hashToken derives the cache key for a raw token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *memoryTokenCache) Get(token string) (*Claims, bool) {
+	key := hashToken(token)
+
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if ok && !c.now().Before(entry.expiresAt) {
+		c.remove(key)
+		ok = false
+	}
+
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	atomic.AddUint64(&c.hits, 1)
+	return entry.claims, true
+}
+
+func (c *memoryTokenCache) Set(token string, claims *Claims) {
+	// Tokens without an expiry are never cached
+	if claims.ExpiresAt == nil {
+		return
+	}
+	expiresAt := claims.ExpiresAt.Time
+	if !c.now().Before(expiresAt) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.maxEntries {
+		c.evictLocked()
+	}
+	if len(c.entries) >= c.maxEntries {
+		return
+	}
+	c.entries[hashToken(token)] = tokenCacheEntry{claims: claims, expiresAt: expiresAt}
+}
+
+func (c *memoryTokenCache) Stats() TokenCacheStats {
+	c.mu.RLock()
+	size := len(c.entries)
+	c.mu.RUnlock()
+
+	stats := TokenCacheStats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+		Size:   size,
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+func (c *memoryTokenCache) remove(key string) {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+}
+
+// evictLocked drops expired entries, then the entry closest to expiry if the cache is still full
+func (c *memoryTokenCache) evictLocked() {
+	now := c.now()
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTokenCache(maxEntries int, now time.Time) *memoryTokenCache {
+	cache := NewMemoryTokenCache(maxEntries).(*memoryTokenCache)
+	cache.now = func() time.Time { return now }
+	return cache
+}
+
+func claimsExpiringAt(id string, exp time.Time) *Claims {
+	return &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{ID: id, ExpiresAt: jwt.NewNumericDate(exp)},
+		UserID:           "user-1",
+	}
+}
+
+func TestMemoryTokenCache_GetSet(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		claims  *Claims
+		advance time.Duration
+		wantHit bool
+	}{
+		{"cached until expiry", claimsExpiringAt("", now.Add(time.Minute)), 30 * time.Second, true},
+		{"expires at exp", claimsExpiringAt("", now.Add(time.Minute)), time.Minute, false},
+		{"already expired is not cached", claimsExpiringAt("", now.Add(-time.Second)), 0, false},
+		{"no expiry is not cached", &Claims{UserID: "user-1"}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newTestTokenCache(10, now)
+			cache.Set("token", tt.claims)
+
+			cache.now = func() time.Time { return now.Add(tt.advance) }
+			claims, hit := cache.Get("token")
+			assert.Equal(t, tt.wantHit, hit)
+			if tt.wantHit {
+				assert.Same(t, tt.claims, claims)
+			}
+		})
+	}
+}
+
+func TestMemoryTokenCache_KeyedByHash(t *testing.T) {
+	cache := newTestTokenCache(10, time.Now())
+	cache.Set("secret-token", claimsExpiringAt("", time.Now().Add(time.Minute)))
+
+	_, stored := cache.entries["secret-token"]
+	assert.False(t, stored)
+	_, stored = cache.entries[hashToken("secret-token")]
+	assert.True(t, stored)
+}
+
+func TestMemoryTokenCache_Eviction(t *testing.T) {
+	now := time.Now()
+	cache := newTestTokenCache(2, now)
+	cache.Set("soonest", claimsExpiringAt("", now.Add(time.Minute)))
+	cache.Set("later", claimsExpiringAt("", now.Add(time.Hour)))
+	cache.Set("newest", claimsExpiringAt("", now.Add(time.Hour)))
+
+	assert.Equal(t, 2, cache.Stats().Size)
+	_, hit := cache.Get("soonest")
+	assert.False(t, hit)
+	_, hit = cache.Get("newest")
+	assert.True(t, hit)
+}
+
+func TestMemoryTokenCache_Stats(t *testing.T) {
+	cache := newTestTokenCache(10, time.Now())
+	cache.Set("a", claimsExpiringAt("", time.Now().Add(time.Minute)))
+
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("a")
+	cache.Get("missing")
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(3), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Size)
+	assert.InDelta(t, 0.75, stats.HitRate, 0.001)
+}
+
+func TestAuthMiddleware_TokenCache(t *testing.T) {
+	secret := []byte("test-secret")
+	cache := NewMemoryTokenCache(10)
+	config := AuthConfig{
+		JWTSecret:    secret,
+		AllowedRoles: DefaultRoles,
+		TokenCache:   cache,
+	}
+	handler := AuthMiddleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		UserID: "user-1",
+		Roles:  []string{"viewer"},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	// Invalid tokens are never cached
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+	req.Header.Set("Authorization", "Bearer "+token+"tampered")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, 1, stats.Size)
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Token exchange identifiers (RFC 8693)
+const (
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	TokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// ExchangeToken implements RFC 8693 token exchange. It validates a user's access token and
+// issues a token that acts as the user while recording actorID in the act claim; an existing
+// act claim on the subject token is kept as the prior actor.
+//
+// The issued token carries the subject token's scopes that actorScopes also allows, narrowed
+// to requested if non-empty, and never outlives the subject token. It has its own jti, so it
+// can be logged out on its own, and no auth_time or amr: the actor did not authenticate as
+// the user, so delegated tokens never pass step-up checks.
+func (tm *TokenManager) ExchangeToken(ctx context.Context, subjectToken, actorID string, actorScopes, requested []string) (*ClientToken, error) {
+	subject, err := tm.ValidateToken(subjectToken)
+	if err != nil {
+		return nil, ErrInvalidGrant
+	}
+	revoked, err := tm.revocations.IsRevoked(ctx, subject.ID, subject.Family)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrInvalidGrant
+	}
+	if len(subject.ActorChain()) >= maxActorChainDepth {
+		return nil, ErrInvalidGrant
+	}
+
+	subjectScopes := subject.GrantedScopes()
+	var scopes []string
+	for _, scope := range subjectScopes {
+		if containsScope(actorScopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(requested) > 0 {
+		for _, scope := range requested {
+			if !containsScope(scopes, scope) {
+				return nil, ErrInvalidScope
+			}
+		}
+		scopes = requested
+	}
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScope
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(tm.accessExpiry)
+	if subject.ExpiresAt != nil && subject.ExpiresAt.Time.Before(expiresAt) {
+		expiresAt = subject.ExpiresAt.Time
+	}
+
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    tm.issuer,
+			Audience:  tm.audience(),
+			Subject:   subject.UserID,
+			ID:        generateTokenID(),
+		},
+		UserID: subject.UserID,
+		Roles:  subject.Roles,
+		Scope:  strings.Join(scopes, " "),
+		Act:    &Actor{Subject: actorID, Act: subject.Act},
+		OrgID:  subject.OrgID,
+		// Revoking the subject token's family revokes the exchanged token too
+		Family: subject.Family,
+	}
+
+	token, err := tm.sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClientToken{
+		AccessToken:     token,
+		TokenType:       "Bearer",
+		ExpiresIn:       int64(expiresAt.Sub(now).Seconds()),
+		Scope:           claims.Scope,
+		IssuedTokenType: TokenTypeAccessToken,
+	}, nil
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnknownUser = errors.New("unknown user")
+
+// testUserRoles looks up roles in a fixed map
+func testUserRoles(roles map[string][]string) TokenManagerOption {
+	return WithUserRoles(func(ctx context.Context, userID string) ([]string, error) {
+		if userRoles, ok := roles[userID]; ok {
+			return userRoles, nil
+		}
+		return nil, errUnknownUser
+	})
+}
+
+func TestCreateImpersonationToken(t *testing.T) {
+	ctx := context.Background()
+	tm := NewTokenManager([]byte("test-secret"), "test-issuer", testUserRoles(map[string][]string{"user-1": {"viewer"}}))
+
+	token, err := tm.CreateImpersonationToken(ctx, "admin-1", "user-1", 5*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), token.ExpiresIn)
+
+	claims, err := tm.ValidateToken(token.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.Equal(t, "admin-1", claims.ActorID())
+	assert.Equal(t, []string{"viewer"}, claims.Roles)
+	assert.Equal(t, ScopeTasksRead, claims.Scope)
+
+	_, err = tm.CreateImpersonationToken(ctx, "admin-1", "user-2", 5*time.Minute)
+	assert.ErrorIs(t, err, errUnknownUser)
+
+	// Roles can't be guessed without a lookup
+	_, err = NewTokenManager([]byte("test-secret"), "test-issuer").CreateImpersonationToken(ctx, "admin-1", "user-1", 5*time.Minute)
+	assert.ErrorIs(t, err, ErrUserRolesUnavailable)
+}
+
+func TestTokens_CarryUserOrg(t *testing.T) {
+	ctx := context.Background()
+	tm := NewTokenManager([]byte("test-secret"), "test-issuer",
+		testUserRoles(map[string][]string{"user-1": {"viewer"}, "user-2": {"user"}}),
+		WithUserOrgs(func(ctx context.Context, userID string) (string, string, error) {
+			if userID == "user-1" {
+				return "org-1", "org_admin", nil
+			}
+			return "", "", nil
+		}))
+
+	pair, err := tm.CreateTokenPair(ctx, "user-1", []string{"viewer"})
+	require.NoError(t, err)
+	refreshed, err := tm.RefreshTokens(ctx, pair.RefreshToken)
+	require.NoError(t, err)
+	impersonation, err := tm.CreateImpersonationToken(ctx, "admin-1", "user-1", 5*time.Minute)
+	require.NoError(t, err)
+
+	for name, token := range map[string]string{
+		"issued":        pair.AccessToken,
+		"refreshed":     refreshed.AccessToken,
+		"impersonation": impersonation.AccessToken,
+	} {
+		claims, err := tm.ValidateToken(token)
+		require.NoError(t, err, name)
+		assert.Equal(t, "org-1", claims.OrgID, name)
+		assert.Equal(t, []string{"viewer", "org_admin"}, claims.Roles, name)
+	}
+
+	// Users outside any organization get no org_id
+	pair, err = tm.CreateTokenPair(ctx, "user-2", []string{"user"})
+	require.NoError(t, err)
+	claims, err := tm.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Empty(t, claims.OrgID)
+	assert.Equal(t, []string{"user"}, claims.Roles)
+}
+
+func TestRefreshTokens_PreservesAuthTime(t *testing.T) {
+	tm := NewTokenManager([]byte("test-secret"), "test-issuer", testUserRoles(map[string][]string{"user-1": {"user"}}))
+	authTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	pair, err := tm.createTokenPair(context.Background(), "user-1", []string{"user"}, authTime, "")
+	require.NoError(t, err)
+
+	refreshed, err := tm.RefreshTokens(context.Background(), pair.RefreshToken)
+	require.NoError(t, err)
+
+	claims, err := tm.ValidateToken(refreshed.AccessToken)
+	require.NoError(t, err)
+	require.NotNil(t, claims.AuthTime)
+	assert.True(t, authTime.Equal(claims.AuthTime.Time))
+	assert.False(t, claims.HasRecentAuth(10*time.Minute, time.Now()))
+}
+
+func TestRefreshTokens_OnlyAcceptsRefreshTokens(t *testing.T) {
+	ctx := context.Background()
+	tm := NewTokenManager([]byte("test-secret"), "test-issuer", testUserRoles(map[string][]string{"user-1": {"user"}}))
+
+	pair, err := tm.CreateTokenPair(context.Background(), "user-1", []string{"user"})
+	require.NoError(t, err)
+	impersonation, err := tm.CreateImpersonationToken(ctx, "admin-1", "user-1", 5*time.Minute)
+	require.NoError(t, err)
+	exchanged, err := tm.ExchangeToken(ctx, pair.AccessToken, "slack", []string{ScopeTasksRead}, nil)
+	require.NoError(t, err)
+	client, err := tm.CreateClientToken("client-1", []string{"user"}, []string{ScopeTasksRead})
+	require.NoError(t, err)
+
+	for name, token := range map[string]string{
+		"access":        pair.AccessToken,
+		"impersonation": impersonation.AccessToken,
+		"exchanged":     exchanged.AccessToken,
+		"client":        client.AccessToken,
+	} {
+		_, err := tm.RefreshTokens(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+
+	// Nor do refresh tokens pass as access tokens
+	_, err = tm.ValidateToken(pair.RefreshToken)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = tm.RefreshTokens(ctx, pair.RefreshToken)
+	assert.NoError(t, err)
+}
+
+func TestValidateToken_Audience(t *testing.T) {
+	issuer := NewTokenManager([]byte("test-secret"), "test-issuer", WithAudience("api-a"))
+	other := NewTokenManager([]byte("test-secret"), "test-issuer", WithAudience("api-b"))
+
+	pair, err := issuer.CreateTokenPair(context.Background(), "user-1", []string{"user"})
+	require.NoError(t, err)
+
+	_, err = issuer.ValidateToken(pair.AccessToken)
+	assert.NoError(t, err)
+
+	_, err = other.ValidateToken(pair.AccessToken)
+	assert.ErrorIs(t, err, ErrInvalidAudience)
+}
+
+func TestExchangeToken(t *testing.T) {
+	tm := NewTokenManager([]byte("test-secret"), "test-issuer")
+
+	pair, err := tm.CreateTokenPair(context.Background(), "user-1", []string{"user"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		actorScopes []string
+		requested   []string
+		wantScope   string
+		wantErr     error
+	}{
+		{"intersects subject and actor scopes", []string{ScopeTasksRead, ScopeWebhooksManage}, nil, ScopeTasksRead, nil},
+		{"narrows to requested scope", []string{ScopeTasksRead, ScopeTasksWrite}, []string{ScopeTasksWrite}, ScopeTasksWrite, nil},
+		{"rejects scope the subject lacks", []string{ScopeWebhooksManage}, []string{ScopeWebhooksManage}, "", ErrInvalidScope},
+		{"rejects empty intersection", []string{ScopeWebhooksManage}, nil, "", ErrInvalidScope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tm.ExchangeToken(context.Background(), pair.AccessToken, "slack", tt.actorScopes, tt.requested)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, TokenTypeAccessToken, token.IssuedTokenType)
+			assert.LessOrEqual(t, token.ExpiresIn, int64(15*time.Minute/time.Second))
+
+			claims, err := tm.ValidateToken(token.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims.UserID)
+			assert.Equal(t, "slack", claims.ActorID())
+			assert.Equal(t, tt.wantScope, claims.Scope)
+		})
+	}
+
+	t.Run("issues a fresh jti and no authentication time", func(t *testing.T) {
+		subject, err := tm.ValidateToken(pair.AccessToken)
+		require.NoError(t, err)
+		require.NotNil(t, subject.AuthTime)
+
+		token, err := tm.ExchangeToken(context.Background(), pair.AccessToken, "slack", []string{ScopeTasksRead}, nil)
+		require.NoError(t, err)
+		claims, err := tm.ValidateToken(token.AccessToken)
+		require.NoError(t, err)
+		assert.NotEmpty(t, claims.ID)
+		assert.NotEqual(t, subject.ID, claims.ID)
+		assert.Nil(t, claims.AuthTime)
+		assert.Empty(t, claims.AMR)
+		assert.False(t, claims.HasRecentAuth(10*time.Minute, time.Now()))
+	})
+
+	t.Run("rejects invalid subject token", func(t *testing.T) {
+		_, err := tm.ExchangeToken(context.Background(), pair.AccessToken+"tampered", "slack", []string{ScopeTasksRead}, nil)
+		assert.ErrorIs(t, err, ErrInvalidGrant)
+	})
+
+	t.Run("keeps prior actors and bounds the chain", func(t *testing.T) {
+		token := pair.AccessToken
+		for _, actor := range []string{"gateway", "email", "slack"} {
+			exchanged, err := tm.ExchangeToken(context.Background(), token, actor, []string{ScopeTasksRead}, nil)
+			require.NoError(t, err)
+			token = exchanged.AccessToken
+		}
+
+		claims, err := tm.ValidateToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"slack", "email", "gateway"}, claims.ActorChain())
+
+		_, err = tm.ExchangeToken(context.Background(), token, "one-too-many", []string{ScopeTasksRead}, nil)
+		assert.ErrorIs(t, err, ErrInvalidGrant)
+	})
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultAllowedAlgorithms are the signing algorithms accepted when none are configured
+var DefaultAllowedAlgorithms = []string{jwt.SigningMethodHS256.Alg()}
+
+// ValidationOptions configures how incoming JWTs are validated
+type ValidationOptions struct {
+	Issuer            string        // required "iss" value; empty skips the check
+	Audience          string        // required "aud" value; empty skips the check
+	AllowedAlgorithms []string      // accepted "alg" values; defaults to DefaultAllowedAlgorithms
+	Leeway            time.Duration // clock skew tolerance for exp/nbf/iat
+}
+
+// parserOptions converts the validation options into jwt parser options
+func (o ValidationOptions) parserOptions() []jwt.ParserOption {
+	algorithms := o.AllowedAlgorithms
+	if len(algorithms) == 0 {
+		algorithms = DefaultAllowedAlgorithms
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(algorithms),
+		jwt.WithLeeway(o.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if o.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(o.Issuer))
+	}
+	if o.Audience != "" {
+		opts = append(opts, jwt.WithAudience(o.Audience))
+	}
+	return opts
+}
+
+// mapValidationError converts jwt parser errors into package errors
+func mapValidationError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return ErrExpiredToken
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return ErrInvalidIssuer
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return ErrInvalidAudience
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return ErrInvalidSignature
+	default:
+		return ErrInvalidToken
+	}
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"strconv"
+	"strings"
+
+	"sample/task-management-system/pkg/metrics"
+	"sample/task-management-system/pkg/models"
+)
+
+// protectedPrefixes are key prefixes that hold state rather than cached data, e.g. the
+// job queue streams, so invalidation never deletes them
+var protectedPrefixes = []string{"jobs:"}
+
+// adminScanCount is how many keys each SCAN step asks Redis to look at
+const adminScanCount = 1000
+
+// Invalidate deletes the keys matching pattern, leaving protected keys alone, and returns
+// how many were deleted. Keys are found with SCAN, so Redis keeps serving meanwhile.
+// Replicas' local caches are flushed afterwards.
+func (c *RedisCache) Invalidate(ctx context.Context, pattern string) (int, error) {
+	deleted := 0
+	iter := c.client.Scan(ctx, 0, pattern, adminScanCount).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if isProtectedKey(key) {
+			continue
+		}
+		n, err := c.client.Del(ctx, key).Result()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += int(n)
+	}
+	if err := iter.Err(); err != nil {
+		return deleted, err
+	}
+	return deleted, c.client.Publish(ctx, invalidationChannel, flushAll).Err()
+}
+
+// InvalidateTasks deletes every cached task response of the organization, for changes made
+// outside a write request to the tasks API, e.g. by a background import
+func (c *RedisCache) InvalidateTasks(ctx context.Context, orgID string) error {
+	deleted, err := c.Invalidate(ctx, models.TaskCachePattern(orgID))
+	metrics.RecordCacheInvalidation(metrics.InvalidationService, deleted)
+	return err
+}
+
+// KeyCounts counts the keys by prefix, the part of the key before its first ":"
+func (c *RedisCache) KeyCounts(ctx context.Context) (map[string]int, error) {
+	counts := map[string]int{}
+	iter := c.client.Scan(ctx, 0, "*", adminScanCount).Iterator()
+	for iter.Next(ctx) {
+		prefix, _, _ := strings.Cut(iter.Val(), ":")
+		counts[prefix]++
+	}
+	return counts, iter.Err()
+}
+
+// ServerStats reads the memory usage and keyspace hit counters from INFO; the key counts
+// and hit ratio are left unset
+func (c *RedisCache) ServerStats(ctx context.Context) (*models.CacheStats, error) {
+	info, err := c.client.Info(ctx, "memory", "stats").Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseServerStats(info), nil
+}
+
+// parseServerStats reads the memory usage and hit counters from an INFO reply; missing
+// fields are 0
+func parseServerStats(info string) *models.CacheStats {
+	stats := &models.CacheStats{}
+	fields := map[string]*int64{
+		"used_memory":     &stats.UsedMemoryBytes,
+		"keyspace_hits":   &stats.Hits,
+		"keyspace_misses": &stats.Misses,
+	}
+	scanner := bufio.NewScanner(strings.NewReader(info))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if field, known := fields[name]; ok && known {
+			*field, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	return stats
+}
+
+func isProtectedKey(key string) bool {
+	for _, prefix := range protectedPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
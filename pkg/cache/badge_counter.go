@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+)
+
+// Badge keys. Open tasks are kept in sorted sets scored by due date, so overdue and
+// due-today counts are range counts instead of database queries.
+const (
+	badgeOpenKey      = "badges:open"
+	badgeAssigneesKey = "badges:assignees" // task ID -> assignee ID of open tasks
+	badgeAssigneeKey  = "badges:assignee:"
+	badgeBuiltKey     = "badges:built"
+)
+
+// BadgeCounter maintains task badge counts in Redis from task events
+type BadgeCounter struct {
+	client *redis.Client
+}
+
+// NewBadgeCounter creates a badge counter storing its counts in c
+func NewBadgeCounter(c *RedisCache) *BadgeCounter {
+	return &BadgeCounter{client: c.client}
+}
+
+// Apply updates the counts for a task event
+func (b *BadgeCounter) Apply(ctx context.Context, event events.Event) error {
+	var task *models.Task
+	if event.Type != events.TaskDeleted {
+		task = event.Task
+	}
+	return b.sync(ctx, event.TaskID, task)
+}
+
+// sync moves a task into the sets matching its current state; a nil task is removed
+func (b *BadgeCounter) sync(ctx context.Context, id string, task *models.Task) error {
+	previousAssignee, err := b.client.HGet(ctx, badgeAssigneesKey, id).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+
+	_, err = b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, badgeOpenKey, id)
+		pipe.HDel(ctx, badgeAssigneesKey, id)
+		if previousAssignee != "" {
+			pipe.ZRem(ctx, badgeAssigneeKey+previousAssignee, id)
+		}
+
+		if task == nil || !task.IsOpen() {
+			return nil
+		}
+		member := redis.Z{Score: float64(task.DueDate.Unix()), Member: id}
+		pipe.ZAdd(ctx, badgeOpenKey, member)
+		if task.AssigneeID != "" {
+			pipe.HSet(ctx, badgeAssigneesKey, id, task.AssigneeID)
+			pipe.ZAdd(ctx, badgeAssigneeKey+task.AssigneeID, member)
+		}
+		return nil
+	})
+	return err
+}
+
+// Counts returns the badge counts for userID at now, where today is the calendar day
+// of now in now's location
+func (b *BadgeCounter) Counts(ctx context.Context, userID string, now time.Time) (*models.TaskBadges, error) {
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	var open, overdue, dueToday, assigned *redis.IntCmd
+	_, err := b.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		open = pipe.ZCard(ctx, badgeOpenKey)
+		overdue = pipe.ZCount(ctx, badgeOpenKey, "-inf", "("+unixScore(now))
+		dueToday = pipe.ZCount(ctx, badgeOpenKey, unixScore(dayStart), "("+unixScore(dayEnd))
+		assigned = pipe.ZCard(ctx, badgeAssigneeKey+userID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TaskBadges{
+		Open:         open.Val(),
+		Overdue:      overdue.Val(),
+		DueToday:     dueToday.Val(),
+		AssignedToMe: assigned.Val(),
+	}, nil
+}
+
+// Built reports whether the counts have been rebuilt since Redis was last emptied
+func (b *BadgeCounter) Built(ctx context.Context) (bool, error) {
+	n, err := b.client.Exists(ctx, badgeBuiltKey).Result()
+	return n > 0, err
+}
+
+// Rebuild replaces all counts with the given open tasks
+func (b *BadgeCounter) Rebuild(ctx context.Context, open []*models.Task) error {
+	iter := b.client.Scan(ctx, 0, badgeAssigneeKey+"*", 0).Iterator()
+	keys := []string{badgeOpenKey, badgeAssigneesKey}
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if err := b.client.Del(ctx, keys...).Err(); err != nil {
+		return err
+	}
+
+	for _, task := range open {
+		if err := b.sync(ctx, task.ID, task); err != nil {
+			return err
+		}
+	}
+	return b.client.Set(ctx, badgeBuiltKey, time.Now().Unix(), 0).Err()
+}
+
+func unixScore(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+)
+
+func TestBadgeCounter(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	counter := NewBadgeCounter(cache)
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	apply := func(eventType events.Type, task *models.Task) {
+		require.NoError(t, counter.Apply(ctx, events.Event{Type: eventType, TaskID: task.ID, Task: task}))
+	}
+
+	apply(events.TaskCreated, &models.Task{ID: "overdue", Status: models.StatusPending, DueDate: now.Add(-2 * time.Hour), AssigneeID: "alice"})
+	apply(events.TaskCreated, &models.Task{ID: "later-today", Status: models.StatusInProgress, DueDate: now.Add(3 * time.Hour)})
+	apply(events.TaskCreated, &models.Task{ID: "next-week", Status: models.StatusPending, DueDate: now.AddDate(0, 0, 7), AssigneeID: "bob"})
+	apply(events.TaskCreated, &models.Task{ID: "done", Status: models.StatusCompleted, DueDate: now, AssigneeID: "alice"})
+
+	badges, err := counter.Counts(ctx, "alice", now)
+	require.NoError(t, err)
+	assert.Equal(t, &models.TaskBadges{Open: 3, Overdue: 1, DueToday: 2, AssignedToMe: 1}, badges)
+
+	// Reassigning moves the task between assignees; completing it closes it
+	apply(events.TaskUpdated, &models.Task{ID: "next-week", Status: models.StatusPending, DueDate: now.AddDate(0, 0, 7), AssigneeID: "alice"})
+	apply(events.TaskUpdated, &models.Task{ID: "overdue", Status: models.StatusCompleted, DueDate: now.Add(-2 * time.Hour), AssigneeID: "alice"})
+	require.NoError(t, counter.Apply(ctx, events.Event{Type: events.TaskDeleted, TaskID: "later-today"}))
+
+	badges, err = counter.Counts(ctx, "alice", now)
+	require.NoError(t, err)
+	assert.Equal(t, &models.TaskBadges{Open: 1, Overdue: 0, DueToday: 0, AssignedToMe: 1}, badges)
+
+	badges, err = counter.Counts(ctx, "bob", now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), badges.AssignedToMe)
+}
+
+func TestBadgeCounter_Rebuild(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	counter := NewBadgeCounter(cache)
+	now := time.Now()
+
+	built, err := counter.Built(ctx)
+	require.NoError(t, err)
+	assert.False(t, built)
+
+	require.NoError(t, counter.Apply(ctx, events.Event{Type: events.TaskCreated, TaskID: "stale",
+		Task: &models.Task{ID: "stale", Status: models.StatusPending, DueDate: now, AssigneeID: "alice"}}))
+	require.NoError(t, counter.Rebuild(ctx, []*models.Task{
+		{ID: "a", Status: models.StatusPending, DueDate: now.Add(time.Hour), AssigneeID: "bob"},
+	}))
+
+	built, err = counter.Built(ctx)
+	require.NoError(t, err)
+	assert.True(t, built)
+
+	badges, err := counter.Counts(ctx, "alice", now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), badges.Open)
+	assert.Equal(t, int64(0), badges.AssignedToMe)
+}
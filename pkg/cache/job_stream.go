@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Job stream layout. Jobs are entries of a stream read through one consumer group, so
+// each job goes to a single worker across all instances. Jobs given up on are copied to
+// the dead-letter stream, the stream's key with jobStreamDeadSuffix.
+const (
+	jobStreamGroup      = "workers"
+	jobStreamDeadSuffix = ":dead"
+	jobStreamField      = "job"
+	jobStreamBatch      = 10
+)
+
+// JobStreamOptions tunes redelivery of a job stream
+type JobStreamOptions struct {
+	// ClaimIdle is how long a job may go unacknowledged, because its handler failed or
+	// its worker stopped, before a worker takes it over and runs it again
+	ClaimIdle time.Duration
+	// MaxDeliveries is how often a job is run before it is moved to the dead-letter stream
+	MaxDeliveries int64
+	// Block is how long a worker waits for new jobs before checking for abandoned ones
+	Block time.Duration
+}
+
+// JobStream is a job queue on a Redis stream with at-least-once delivery: jobs are
+// acknowledged only once handled, so a job whose handler failed or whose worker stopped
+// stays pending and is claimed again after ClaimIdle.
+type JobStream struct {
+	client   *redis.Client
+	stream   string
+	consumer string
+	options  JobStreamOptions
+}
+
+// NewJobStream creates a job queue on the stream at key stream in c. consumer names
+// this worker within the group and must be unique per instance, e.g. the host name.
+func NewJobStream(c *RedisCache, stream, consumer string, options JobStreamOptions) *JobStream {
+	return &JobStream{client: c.client, stream: stream, consumer: consumer, options: options}
+}
+
+// Publish queues a job
+func (s *JobStream) Publish(ctx context.Context, jobID string) error {
+	return s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream,
+		Values: map[string]interface{}{jobStreamField: jobID},
+	}).Err()
+}
+
+// Consume passes queued jobs to handle until ctx is done. Jobs abandoned by other
+// workers are taken over before new ones are read.
+func (s *JobStream) Consume(ctx context.Context, handle func(ctx context.Context, jobID string) error) {
+	ready := false
+	for ctx.Err() == nil {
+		var err error
+		if !ready {
+			err = s.createGroup(ctx)
+			ready = err == nil
+		}
+		if err == nil {
+			err = s.poll(ctx, handle)
+		}
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to read job stream %s: %v", s.stream, err)
+			// Back off rather than spin while Redis is unreachable
+			select {
+			case <-ctx.Done():
+			case <-time.After(s.options.Block):
+			}
+		}
+	}
+}
+
+// createGroup creates the consumer group, and the stream with it, unless they exist
+func (s *JobStream) createGroup(ctx context.Context) error {
+	err := s.client.XGroupCreateMkStream(ctx, s.stream, jobStreamGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+	return nil
+}
+
+// poll runs the abandoned jobs, then waits up to Block for new ones and runs them
+func (s *JobStream) poll(ctx context.Context, handle func(ctx context.Context, jobID string) error) error {
+	if err := s.reclaim(ctx, handle); err != nil {
+		return err
+	}
+
+	streams, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    jobStreamGroup,
+		Consumer: s.consumer,
+		Streams:  []string{s.stream, ">"},
+		Count:    jobStreamBatch,
+		Block:    s.options.Block,
+	}).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, stream := range streams {
+		for _, message := range stream.Messages {
+			s.run(ctx, message, handle)
+		}
+	}
+	return nil
+}
+
+// reclaim takes over the jobs left unacknowledged for ClaimIdle and runs them again, or
+// moves them to the dead-letter stream once they were delivered MaxDeliveries times
+func (s *JobStream) reclaim(ctx context.Context, handle func(ctx context.Context, jobID string) error) error {
+	pending, err := s.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: s.stream,
+		Group:  jobStreamGroup,
+		Idle:   s.options.ClaimIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  jobStreamBatch,
+	}).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range pending {
+		// Claiming checks the idle time again, so only one worker takes each job over
+		messages, err := s.client.XClaim(ctx, &redis.XClaimArgs{
+			Stream:   s.stream,
+			Group:    jobStreamGroup,
+			Consumer: s.consumer,
+			MinIdle:  s.options.ClaimIdle,
+			Messages: []string{entry.ID},
+		}).Result()
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			if entry.RetryCount >= s.options.MaxDeliveries {
+				if err := s.deadLetter(ctx, message, entry.RetryCount); err != nil {
+					return err
+				}
+				continue
+			}
+			s.run(ctx, message, handle)
+		}
+	}
+	return nil
+}
+
+// run hands a job to handle and acknowledges it when handled; failed jobs stay pending
+func (s *JobStream) run(ctx context.Context, message redis.XMessage, handle func(ctx context.Context, jobID string) error) {
+	jobID, _ := message.Values[jobStreamField].(string)
+	if err := handle(ctx, jobID); err != nil {
+		log.Printf("Job %s from stream %s failed, it will be retried: %v", jobID, s.stream, err)
+		return
+	}
+	if err := s.remove(ctx, message.ID); err != nil {
+		log.Printf("Failed to acknowledge job %s on stream %s: %v", jobID, s.stream, err)
+	}
+}
+
+// deadLetter copies a job to the dead-letter stream with its delivery count and removes
+// it from the queue
+func (s *JobStream) deadLetter(ctx context.Context, message redis.XMessage, deliveries int64) error {
+	jobID, _ := message.Values[jobStreamField].(string)
+	log.Printf("Job %s from stream %s failed %d times, moving it to the dead-letter stream", jobID, s.stream, deliveries)
+	err := s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.stream + jobStreamDeadSuffix,
+		Values: map[string]interface{}{
+			jobStreamField: jobID,
+			"message_id":   message.ID,
+			"deliveries":   deliveries,
+			"failed_at":    time.Now().UTC().Format(time.RFC3339),
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("dead-letter job %s: %w", jobID, err)
+	}
+	return s.remove(ctx, message.ID)
+}
+
+// remove acknowledges a message and deletes it, so the stream only holds unfinished jobs
+func (s *JobStream) remove(ctx context.Context, id string) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAck(ctx, s.stream, jobStreamGroup, id)
+		pipe.XDel(ctx, s.stream, id)
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobStream_RetriesAndDeadLetters(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	mr.SetTime(now)
+	stream := NewJobStream(cache, "jobs:exports", "worker-1", JobStreamOptions{
+		ClaimIdle:     time.Minute,
+		MaxDeliveries: 2,
+		Block:         10 * time.Millisecond,
+	})
+	require.NoError(t, stream.createGroup(ctx))
+	require.NoError(t, stream.createGroup(ctx)) // an existing group is reused
+
+	require.NoError(t, stream.Publish(ctx, "job-1"))
+	require.NoError(t, stream.Publish(ctx, "job-2"))
+
+	var handled []string
+	handle := func(ctx context.Context, jobID string) error {
+		handled = append(handled, jobID)
+		if jobID == "job-2" {
+			return errors.New("storage unavailable")
+		}
+		return nil
+	}
+
+	require.NoError(t, stream.poll(ctx, handle))
+	assert.Equal(t, []string{"job-1", "job-2"}, handled)
+
+	// The failed job is not redelivered until it was idle for ClaimIdle
+	handled = nil
+	require.NoError(t, stream.poll(ctx, handle))
+	assert.Empty(t, handled)
+
+	mr.SetTime(now.Add(time.Minute))
+	require.NoError(t, stream.poll(ctx, handle))
+	assert.Equal(t, []string{"job-2"}, handled)
+
+	// After MaxDeliveries the job is set aside instead of run again
+	handled = nil
+	mr.SetTime(now.Add(2 * time.Minute))
+	require.NoError(t, stream.poll(ctx, handle))
+	assert.Empty(t, handled)
+
+	dead, err := cache.client.XRange(ctx, "jobs:exports:dead", "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, "job-2", dead[0].Values["job"])
+	assert.Equal(t, "2", dead[0].Values["deliveries"])
+
+	length, err := cache.client.XLen(ctx, "jobs:exports").Result()
+	require.NoError(t, err)
+	assert.Zero(t, length)
+}
+
+func TestJobStream_ConsumeStopsWithContext(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	stream := NewJobStream(cache, "jobs:imports", "worker-1", JobStreamOptions{
+		ClaimIdle:     time.Minute,
+		MaxDeliveries: 3,
+		Block:         10 * time.Millisecond,
+	})
+	require.NoError(t, stream.Publish(context.Background(), "job-1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	handled := make(chan string, 1)
+	go func() {
+		stream.Consume(ctx, func(ctx context.Context, jobID string) error {
+			handled <- jobID
+			return nil
+		})
+		close(done)
+	}()
+
+	select {
+	case jobID := <-handled:
+		assert.Equal(t, "job-1", jobID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("job was not consumed")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Consume did not stop")
+	}
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+// invalidationChannel is the Redis pub/sub channel replicas announce invalidated keys on
+const invalidationChannel = "cache:invalidate"
+
+// flushAll is published instead of keys when every replica should drop its whole local
+// cache, e.g. after an invalidation by pattern
+const flushAll = "*"
+
+// LocalCache is a small in-process cache in front of Redis for the hottest keys. Entries
+// live for a short TTL; invalidations are broadcast over Redis pub/sub, so a replica that
+// deletes a key makes every other replica drop its copy too.
+type LocalCache struct {
+	redis      *RedisCache
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]localEntry
+}
+
+type localEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLocalCache creates a local cache holding at most maxEntries values for ttl each,
+// broadcasting invalidations through redis. Call Listen to receive other replicas'.
+func NewLocalCache(redis *RedisCache, ttl time.Duration, maxEntries int) *LocalCache {
+	return &LocalCache{
+		redis:      redis,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]localEntry),
+	}
+}
+
+// Get returns the value stored for key, if it has not expired
+func (c *LocalCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set stores value for key for the cache's TTL
+func (c *LocalCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evictLocked()
+	}
+	if len(c.entries) >= c.maxEntries {
+		return
+	}
+	c.entries[key] = localEntry{value: value, expiresAt: c.now().Add(c.ttl)}
+}
+
+// Invalidate drops keys from this replica and tells the others to drop them too
+func (c *LocalCache) Invalidate(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	c.drop(keys)
+
+	message, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	return c.redis.client.Publish(ctx, invalidationChannel, message).Err()
+}
+
+// Len returns the number of entries, including expired ones not evicted yet
+func (c *LocalCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Listen applies the invalidations other replicas broadcast until ctx is done. Entries
+// cached while the subscription was down may be stale for up to the TTL.
+func (c *LocalCache) Listen(ctx context.Context) {
+	sub := c.redis.client.Subscribe(ctx, invalidationChannel)
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				return
+			}
+			c.apply(msg.Payload)
+		}
+	}
+}
+
+// apply drops the keys named by an invalidation message
+func (c *LocalCache) apply(payload string) {
+	if payload == flushAll {
+		c.mu.Lock()
+		c.entries = make(map[string]localEntry)
+		c.mu.Unlock()
+		return
+	}
+
+	var keys []string
+	if err := json.Unmarshal([]byte(payload), &keys); err != nil {
+		log.Printf("Ignoring malformed cache invalidation %q: %v", payload, err)
+		return
+	}
+	c.drop(keys)
+}
+
+func (c *LocalCache) drop(keys []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+}
+
+// evictLocked drops expired entries, then the entry closest to expiry if the cache is still full
+func (c *LocalCache) evictLocked() {
+	now := c.now()
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalCache_Expiry(t *testing.T) {
+	redis, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	local := NewLocalCache(redis, 5*time.Second, 2)
+	local.now = func() time.Time { return now }
+
+	local.Set("a", []byte("1"))
+	value, ok := local.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	now = now.Add(5 * time.Second)
+	_, ok = local.Get("a")
+	assert.False(t, ok)
+}
+
+func TestLocalCache_EvictsClosestToExpiry(t *testing.T) {
+	redis, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	local := NewLocalCache(redis, 5*time.Second, 2)
+	local.now = func() time.Time { return now }
+
+	local.Set("a", []byte("1"))
+	now = now.Add(time.Second)
+	local.Set("b", []byte("2"))
+	local.Set("c", []byte("3"))
+
+	assert.Equal(t, 2, local.Len())
+	_, ok := local.Get("a")
+	assert.False(t, ok)
+	_, ok = local.Get("c")
+	assert.True(t, ok)
+}
+
+func TestLocalCache_InvalidationReachesOtherReplicas(t *testing.T) {
+	redis, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	replica := NewLocalCache(redis, time.Minute, 10)
+	go replica.Listen(ctx)
+	require.Eventually(t, func() bool { return len(mr.PubSubChannels("")) == 1 }, time.Second, 10*time.Millisecond)
+
+	replica.Set("a", []byte("1"))
+	replica.Set("b", []byte("2"))
+	other := NewLocalCache(redis, time.Minute, 10)
+	require.NoError(t, other.Invalidate(ctx, "a"))
+	assert.Eventually(t, func() bool {
+		_, ok := replica.Get("a")
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+	_, ok := replica.Get("b")
+	assert.True(t, ok)
+
+	// Invalidating by pattern flushes every replica
+	_, err := redis.Invalidate(ctx, "v1:*")
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return replica.Len() == 0 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// Recent view keys. Each user's views are a sorted set of task IDs scored by view time
+// and capped at the configured size, so repeat views move a task to the front.
+const (
+	recentViewsKey      = "recent:user:"
+	recentViewsDirtyKey = "recent:dirty" // users with views not yet flushed to Postgres
+	recentViewsTTL      = 30 * 24 * time.Hour
+)
+
+// RecentViews keeps each user's most recently viewed tasks in Redis
+type RecentViews struct {
+	client *redis.Client
+	size   int
+}
+
+// NewRecentViews creates a store keeping the last size views per user in c
+func NewRecentViews(c *RedisCache, size int) *RecentViews {
+	return &RecentViews{client: c.client, size: size}
+}
+
+// Size returns the number of views kept per user
+func (v *RecentViews) Size() int {
+	return v.size
+}
+
+// Record moves taskID to the front of the user's recent views and marks the user for flushing
+func (v *RecentViews) Record(ctx context.Context, userID, taskID string, at time.Time) error {
+	key := recentViewsKey + userID
+	_, err := v.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(at.UnixMilli()), Member: taskID})
+		pipe.ZRemRangeByRank(ctx, key, 0, int64(-v.size-1))
+		pipe.Expire(ctx, key, recentViewsTTL)
+		pipe.SAdd(ctx, recentViewsDirtyKey, userID)
+		return nil
+	})
+	return err
+}
+
+// List returns up to limit of the user's views, most recent first
+func (v *RecentViews) List(ctx context.Context, userID string, limit int) ([]models.TaskView, error) {
+	members, err := v.client.ZRevRangeWithScores(ctx, recentViewsKey+userID, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]models.TaskView, 0, len(members))
+	for _, member := range members {
+		taskID, _ := member.Member.(string)
+		views = append(views, models.TaskView{
+			TaskID:   taskID,
+			ViewedAt: time.UnixMilli(int64(member.Score)).UTC(),
+		})
+	}
+	return views, nil
+}
+
+// Seed restores a user's views, e.g. from Postgres after Redis lost them. Views
+// recorded since are kept.
+func (v *RecentViews) Seed(ctx context.Context, userID string, views []models.TaskView) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	key := recentViewsKey + userID
+	members := make([]redis.Z, len(views))
+	for i, view := range views {
+		members[i] = redis.Z{Score: float64(view.ViewedAt.UnixMilli()), Member: view.TaskID}
+	}
+	_, err := v.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAddNX(ctx, key, members...)
+		pipe.ZRemRangeByRank(ctx, key, 0, int64(-v.size-1))
+		pipe.Expire(ctx, key, recentViewsTTL)
+		return nil
+	})
+	return err
+}
+
+// PopDirty removes and returns up to count users whose views changed since they were
+// last popped
+func (v *RecentViews) PopDirty(ctx context.Context, count int) ([]string, error) {
+	return v.client.SPopN(ctx, recentViewsDirtyKey, int64(count)).Result()
+}
+
+// MarkDirty marks users for flushing again, e.g. after a failed flush
+func (v *RecentViews) MarkDirty(ctx context.Context, userIDs ...string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	members := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		members[i] = id
+	}
+	return v.client.SAdd(ctx, recentViewsDirtyKey, members...).Err()
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+func TestRecentViews(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	recent := NewRecentViews(cache, 3)
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	for i, taskID := range []string{"task-1", "task-2", "task-3", "task-4", "task-2"} {
+		require.NoError(t, recent.Record(ctx, "alice", taskID, now.Add(time.Duration(i)*time.Minute)))
+	}
+
+	// Repeat views move a task to the front and the oldest views fall off
+	views, err := recent.List(ctx, "alice", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []models.TaskView{
+		{TaskID: "task-2", ViewedAt: now.Add(4 * time.Minute)},
+		{TaskID: "task-4", ViewedAt: now.Add(3 * time.Minute)},
+		{TaskID: "task-3", ViewedAt: now.Add(2 * time.Minute)},
+	}, views)
+
+	views, err = recent.List(ctx, "alice", 1)
+	require.NoError(t, err)
+	assert.Len(t, views, 1)
+
+	dirty, err := recent.PopDirty(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, dirty)
+
+	dirty, err = recent.PopDirty(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, dirty)
+}
+
+func TestRecentViews_Seed(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	recent := NewRecentViews(cache, 3)
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, recent.Record(ctx, "alice", "task-1", now))
+	require.NoError(t, recent.Seed(ctx, "alice", []models.TaskView{
+		{TaskID: "task-1", ViewedAt: now.Add(-time.Hour)},
+		{TaskID: "task-2", ViewedAt: now.Add(-2 * time.Hour)},
+	}))
+
+	// Seeding keeps views recorded since
+	views, err := recent.List(ctx, "alice", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []models.TaskView{
+		{TaskID: "task-1", ViewedAt: now},
+		{TaskID: "task-2", ViewedAt: now.Add(-2 * time.Hour)},
+	}, views)
+}
@@ -43,6 +43,30 @@ func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) erro
 	return json.Unmarshal(data, dest)
 }
 
+// Take gets and deletes the value at key in one step, so only one caller ever gets it
+func (c *RedisCache) Take(ctx context.Context, key string, dest interface{}) error {
+	data, err := c.client.GetDel(ctx, key).Bytes()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// SetNX stores value at key unless a value is stored there already, reporting whether it did
+func (c *RedisCache) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+	return c.client.SetNX(ctx, key, data, expiration).Result()
+}
+
+// Exists reports whether a value is stored at any of the keys
+func (c *RedisCache) Exists(ctx context.Context, keys ...string) (bool, error) {
+	n, err := c.client.Exists(ctx, keys...).Result()
+	return n > 0, err
+}
+
 func (c *RedisCache) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ctx, key).Err()
 }
@@ -107,6 +107,63 @@ func TestRedisCache_Delete(t *testing.T) {
 	assert.False(t, exists)
 }
 
+func TestRedisCache_Take(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, cache.Set(ctx, "test_key", "test_value", time.Minute))
+
+	var value string
+	assert.NoError(t, cache.Take(ctx, "test_key", &value))
+	assert.Equal(t, "test_value", value)
+
+	// Taken values are gone
+	assert.False(t, mr.Exists("test_key"))
+	assert.Error(t, cache.Take(ctx, "test_key", &value))
+}
+
+func TestRedisCache_Exists(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, cache.Set(ctx, "test_key", true, time.Minute))
+
+	exists, err := cache.Exists(ctx, "test_key")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	// Any of the keys will do
+	exists, err = cache.Exists(ctx, "missing_key", "test_key")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	// Expired values are gone
+	mr.FastForward(time.Minute)
+	exists, err = cache.Exists(ctx, "test_key")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRedisCache_SetNX(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	stored, err := cache.SetNX(ctx, "test_key", "first", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, stored)
+
+	// The first value is kept
+	stored, err = cache.SetNX(ctx, "test_key", "second", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, stored)
+	var value string
+	assert.NoError(t, cache.Get(ctx, "test_key", &value))
+	assert.Equal(t, "first", value)
+}
+
 func TestRedisCache_Clear(t *testing.T) {
 	cache, mr := setupTestRedis(t)
 	defer mr.Close()
@@ -125,4 +182,64 @@ func TestRedisCache_Clear(t *testing.T) {
 
 	// Verify all keys were deleted
 	assert.Equal(t, 0, len(mr.Keys()))
-} 
\ No newline at end of file
+} 
+func TestRedisCache_InvalidateSkipsProtectedKeys(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	for _, key := range []string{"v1:tasks:default:a", "v1:tasks:org-acme:b", "progress:p1", "jobs:export"} {
+		assert.NoError(t, mr.Set(key, "x"))
+	}
+
+	deleted, err := cache.Invalidate(ctx, "v1:tasks:*")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	assert.Equal(t, []string{"jobs:export", "progress:p1"}, mr.Keys())
+
+	// Queue streams survive even a catch-all pattern
+	deleted, err = cache.Invalidate(ctx, "*")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.Equal(t, []string{"jobs:export"}, mr.Keys())
+}
+
+func TestRedisCache_InvalidateTasks(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	for _, key := range []string{"v1:tasks:org-acme:c1", "v1:tasks:org-acme:c1:task-1", "v1:tasks:org-other:c1", "v1:tasks:default:c1"} {
+		assert.NoError(t, mr.Set(key, "x"))
+	}
+
+	// Lists and single tasks of the organization alike
+	assert.NoError(t, cache.InvalidateTasks(ctx, "acme"))
+	assert.Equal(t, []string{"v1:tasks:default:c1", "v1:tasks:org-other:c1"}, mr.Keys())
+
+	assert.NoError(t, cache.InvalidateTasks(ctx, ""))
+	assert.Equal(t, []string{"v1:tasks:org-other:c1"}, mr.Keys())
+}
+
+func TestRedisCache_KeyCounts(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	for _, key := range []string{"v1:tasks:default:a", "v1:tasks:default:b", "badges:open", "standalone"} {
+		assert.NoError(t, mr.Set(key, "x"))
+	}
+
+	counts, err := cache.KeyCounts(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"v1": 2, "badges": 1, "standalone": 1}, counts)
+}
+
+func TestParseServerStats(t *testing.T) {
+	info := "# Memory\r\nused_memory:1048576\r\nused_memory_human:1.00M\r\n\r\n" +
+		"# Stats\r\nkeyspace_hits:30\r\nkeyspace_misses:10\r\n"
+
+	stats := parseServerStats(info)
+	assert.Equal(t, int64(1048576), stats.UsedMemoryBytes)
+	assert.Equal(t, int64(30), stats.Hits)
+	assert.Equal(t, int64(10), stats.Misses)
+}
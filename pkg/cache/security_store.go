@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Security detector keys. Failures are kept in sorted sets scored by time, so counting
+// the failures within the window is a range count.
+const (
+	securityFailuresKey  = "security:failures:"
+	securityAlertedKey   = "security:alerted:"
+	securityCountriesKey = "security:countries:"
+	// securityCountriesTTL forgets the countries of users who stop signing in
+	securityCountriesTTL = 90 * 24 * time.Hour
+)
+
+// SecurityStore keeps the security detector's counters in Redis, so every instance
+// counts the failures and sees the alerts of the others. It implements security.Store.
+type SecurityStore struct {
+	client *redis.Client
+}
+
+// NewSecurityStore creates a security store keeping its counters in c
+func NewSecurityStore(c *RedisCache) *SecurityStore {
+	return &SecurityStore{client: c.client}
+}
+
+// AddFailure records a failure for key at now and returns the number of failures for key
+// within window
+func (s *SecurityStore) AddFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	key = securityFailuresKey + key
+	var count *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-window).UnixNano(), 10))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixNano()), Member: uuid.NewString()})
+		count = pipe.ZCard(ctx, key)
+		pipe.PExpire(ctx, key, window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(count.Val()), nil
+}
+
+// MarkAlerted records an alert for key at now, reporting false if one was already
+// recorded within window
+func (s *SecurityStore) MarkAlerted(ctx context.Context, key string, now time.Time, window time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, securityAlertedKey+key, now.Unix(), window).Result()
+}
+
+// AddCountry records an access by the user from country, reporting whether it is the
+// first from country by a user seen in other countries before
+func (s *SecurityStore) AddCountry(ctx context.Context, userID, country string) (bool, error) {
+	key := securityCountriesKey + userID
+	var known, added *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		known = pipe.SCard(ctx, key)
+		added = pipe.SAdd(ctx, key, country)
+		pipe.Expire(ctx, key, securityCountriesTTL)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return known.Val() > 0 && added.Val() == 1, nil
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityStore(t *testing.T) {
+	cache, mr := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	store := NewSecurityStore(cache)
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	// Failures older than the window are no longer counted
+	for i, want := range []int{1, 2, 2} {
+		count, err := store.AddFailure(ctx, "401:198.51.100.1", now.Add(time.Duration(i)*40*time.Second), time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, want, count)
+	}
+
+	first, err := store.MarkAlerted(ctx, "brute_force", now, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, first)
+	first, err = store.MarkAlerted(ctx, "brute_force", now, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, first)
+
+	// Only countries after the first, and only once each, are new
+	for _, step := range []struct {
+		country string
+		isNew   bool
+	}{{"US", false}, {"US", false}, {"BR", true}, {"BR", false}} {
+		isNew, err := store.AddCountry(ctx, "user-1", step.country)
+		require.NoError(t, err)
+		assert.Equal(t, step.isNew, isNew, step.country)
+	}
+}
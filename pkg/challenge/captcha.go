@@ -0,0 +1,88 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CaptchaTokenHeader carries the token produced by the CAPTCHA widget
+const CaptchaTokenHeader = "X-Captcha-Token"
+
+// Providers maps supported CAPTCHA providers to their verification endpoints
+var Providers = map[string]string{
+	"recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+	"hcaptcha":  "https://hcaptcha.com/siteverify",
+	"turnstile": "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+// CaptchaVerifier verifies tokens against a siteverify-compatible CAPTCHA API
+type CaptchaVerifier struct {
+	verifyURL  string
+	secret     string
+	httpClient *http.Client
+}
+
+// NewCaptchaVerifier creates a verifier for one of the known Providers
+func NewCaptchaVerifier(provider, secret string) (*CaptchaVerifier, error) {
+	verifyURL, ok := Providers[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown captcha provider: %s", provider)
+	}
+	return NewCaptchaVerifierWithURL(verifyURL, secret), nil
+}
+
+// NewCaptchaVerifierWithURL creates a verifier for a custom siteverify endpoint
+func NewCaptchaVerifierWithURL(verifyURL, secret string) *CaptchaVerifier {
+	return &CaptchaVerifier{
+		verifyURL:  verifyURL,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Verify implements Verifier
+func (v *CaptchaVerifier) Verify(ctx context.Context, r *http.Request) error {
+	token := r.Header.Get(CaptchaTokenHeader)
+	if token == "" {
+		return ErrChallengeRequired
+	}
+
+	form := url.Values{
+		"secret":   {v.secret},
+		"response": {token},
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		form.Set("remoteip", host)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+
+	if !result.Success {
+		return ErrChallengeFailed
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Common errors
+var (
+	ErrChallengeRequired = errors.New("challenge response required")
+	ErrChallengeFailed   = errors.New("challenge verification failed")
+	ErrChallengeExpired  = errors.New("challenge has expired")
+)
+
+// Verifier checks that a request carries a valid human/bot challenge response
+type Verifier interface {
+	Verify(ctx context.Context, r *http.Request) error
+}
+
+// Middleware rejects requests that fail challenge verification with 403.
+// A nil verifier disables the check.
+func Middleware(verifier Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if verifier == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := verifier.Verify(r.Context(), r); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package challenge
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryReplayStore is an in-memory ReplayStore
+type memoryReplayStore struct {
+	keys map[string]bool
+}
+
+func newMemoryReplayStore() *memoryReplayStore {
+	return &memoryReplayStore{keys: make(map[string]bool)}
+}
+
+func (s *memoryReplayStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	if s.keys[key] {
+		return false, nil
+	}
+	s.keys[key] = true
+	return true, nil
+}
+
+// solve brute-forces a nonce for the challenge
+func solve(t *testing.T, c *PoWChallenge) string {
+	for i := 0; i < 1<<20; i++ {
+		nonce := strconv.Itoa(i)
+		sum := sha256.Sum256([]byte(c.Challenge + ":" + nonce))
+		if leadingZeroBits(sum[:]) >= c.Difficulty {
+			return nonce
+		}
+	}
+	t.Fatal("no nonce found")
+	return ""
+}
+
+func powRequest(challenge, nonce string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/account/register", nil)
+	req.Header.Set(PoWChallengeHeader, challenge)
+	req.Header.Set(PoWNonceHeader, nonce)
+	return req
+}
+
+func TestProofOfWork_Verify(t *testing.T) {
+	pow := NewProofOfWork([]byte("secret"), 8, time.Minute, newMemoryReplayStore())
+	issued, err := pow.Issue()
+	require.NoError(t, err)
+	nonce := solve(t, issued)
+
+	tests := []struct {
+		name      string
+		verifier  *ProofOfWork
+		challenge string
+		nonce     string
+		wantErr   error
+	}{
+		{"missing headers", pow, "", "", ErrChallengeRequired},
+		{"tampered signature", pow, issued.Challenge + "00", nonce, ErrChallengeFailed},
+		{"wrong secret", NewProofOfWork([]byte("other"), 8, time.Minute, newMemoryReplayStore()), issued.Challenge, nonce, ErrChallengeFailed},
+		{"valid solution", pow, issued.Challenge, nonce, nil},
+		{"replayed solution", pow, issued.Challenge, nonce, ErrChallengeFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.verifier.Verify(context.Background(), powRequest(tt.challenge, tt.nonce))
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProofOfWork_ReplayAcrossReplicas(t *testing.T) {
+	used := newMemoryReplayStore()
+	first := NewProofOfWork([]byte("secret"), 8, time.Minute, used)
+	second := NewProofOfWork([]byte("secret"), 8, time.Minute, used)
+	issued, err := first.Issue()
+	require.NoError(t, err)
+	nonce := solve(t, issued)
+
+	require.NoError(t, first.Verify(context.Background(), powRequest(issued.Challenge, nonce)))
+	err = second.Verify(context.Background(), powRequest(issued.Challenge, nonce))
+	assert.ErrorIs(t, err, ErrChallengeFailed)
+}
+
+func TestProofOfWork_Expired(t *testing.T) {
+	pow := NewProofOfWork([]byte("secret"), 0, -time.Minute, newMemoryReplayStore())
+	issued, err := pow.Issue()
+	require.NoError(t, err)
+
+	err = pow.Verify(context.Background(), powRequest(issued.Challenge, "0"))
+	assert.ErrorIs(t, err, ErrChallengeExpired)
+}
+
+func TestCaptchaVerifier_Verify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		if r.PostForm.Get("response") == "good-token" {
+			w.Write([]byte(`{"success": true}`))
+			return
+		}
+		w.Write([]byte(`{"success": false}`))
+	}))
+	defer server.Close()
+
+	verifier := NewCaptchaVerifierWithURL(server.URL, "secret")
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"missing token", "", ErrChallengeRequired},
+		{"rejected token", "bad-token", ErrChallengeFailed},
+		{"accepted token", "good-token", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/account/register", nil)
+			if tt.token != "" {
+				req.Header.Set(CaptchaTokenHeader, tt.token)
+			}
+
+			err := verifier.Verify(context.Background(), req)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// Disabled verification passes through
+	rec := httptest.NewRecorder()
+	Middleware(nil)(next).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Failed verification is rejected
+	rec = httptest.NewRecorder()
+	pow := NewProofOfWork([]byte("secret"), 8, time.Minute, newMemoryReplayStore())
+	Middleware(pow)(next).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestNewCaptchaVerifier_UnknownProvider(t *testing.T) {
+	_, err := NewCaptchaVerifier("unknown", "secret")
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package challenge
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"math/bits"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Proof-of-work request headers
+const (
+	PoWChallengeHeader = "X-PoW-Challenge"
+	PoWNonceHeader     = "X-PoW-Nonce"
+)
+
+// PoWChallenge is issued to clients, who must find a nonce such that
+// sha256(challenge + ":" + nonce) starts with Difficulty zero bits
+type PoWChallenge struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// ReplayStore records solved challenges, e.g. cache.RedisCache
+type ReplayStore interface {
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+}
+
+// ProofOfWork issues and verifies stateless, HMAC-signed proof-of-work challenges
+type ProofOfWork struct {
+	secret     []byte
+	difficulty int
+	ttl        time.Duration
+	used       ReplayStore // solved challenges, kept until expiry to prevent replay
+}
+
+// NewProofOfWork creates a proof-of-work verifier. Solved challenges are recorded in
+// used, shared by every replica, so a solution is accepted once across all of them.
+func NewProofOfWork(secret []byte, difficulty int, ttl time.Duration, used ReplayStore) *ProofOfWork {
+	return &ProofOfWork{
+		secret:     secret,
+		difficulty: difficulty,
+		ttl:        ttl,
+		used:       used,
+	}
+}
+
+// Issue creates a new challenge
+func (p *ProofOfWork) Issue() (*PoWChallenge, error) {
+	payload := make([]byte, 24)
+	expiresAt := time.Now().Add(p.ttl)
+	binary.BigEndian.PutUint64(payload[:8], uint64(expiresAt.Unix()))
+	if _, err := rand.Read(payload[8:]); err != nil {
+		return nil, err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return &PoWChallenge{
+		Challenge:  encoded + "." + p.sign(encoded),
+		Difficulty: p.difficulty,
+		ExpiresAt:  expiresAt.Truncate(time.Second),
+	}, nil
+}
+
+// Verify implements Verifier
+func (p *ProofOfWork) Verify(ctx context.Context, r *http.Request) error {
+	challenge := r.Header.Get(PoWChallengeHeader)
+	nonce := r.Header.Get(PoWNonceHeader)
+	if challenge == "" || nonce == "" {
+		return ErrChallengeRequired
+	}
+
+	encoded, signature, ok := strings.Cut(challenge, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(p.sign(encoded))) {
+		return ErrChallengeFailed
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(payload) < 8 {
+		return ErrChallengeFailed
+	}
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[:8])), 0)
+	if time.Now().After(expiresAt) {
+		return ErrChallengeExpired
+	}
+
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	if leadingZeroBits(sum[:]) < p.difficulty {
+		return ErrChallengeFailed
+	}
+
+	return p.markUsed(ctx, signature, expiresAt)
+}
+
+func (p *ProofOfWork) sign(payload string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// markUsed records a solved challenge by its signature until it expires, failing if it
+// was solved before
+func (p *ProofOfWork) markUsed(ctx context.Context, signature string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	first, err := p.used.SetNX(ctx, "pow:used:"+signature, true, ttl)
+	if err != nil {
+		return err
+	}
+	if !first {
+		return ErrChallengeFailed
+	}
+	return nil
+}
+
+func leadingZeroBits(data []byte) int {
+	count := 0
+	for _, b := range data {
+		if b == 0 {
+			count += 8
+			continue
+		}
+		return count + bits.LeadingZeros8(b)
+	}
+	return count
+}
@@ -0,0 +1,169 @@
+package doctor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+)
+
+// minSecretLength is the minimum HMAC secret length; HS256 needs at least 256 bits
+const minSecretLength = 32
+
+// weakSecrets are placeholder secrets from docs and examples that must never reach production
+var weakSecrets = []string{"your-development-secret", "secret", "changeme", "change-me", "password"}
+
+// JWTSecretCheck verifies the token signing secret is long and not a known placeholder
+func JWTSecretCheck(secret []byte) Check {
+	return Check{Name: "jwt-secret", Run: func(ctx context.Context) (Status, string) {
+		if len(secret) == 0 {
+			return StatusFail, "AUTH_SECRET is not set"
+		}
+		for _, weak := range weakSecrets {
+			if strings.EqualFold(string(secret), weak) {
+				return StatusFail, "AUTH_SECRET is a well-known placeholder value"
+			}
+		}
+		if len(secret) < minSecretLength {
+			return StatusFail, fmt.Sprintf("AUTH_SECRET is %d bytes, need at least %d", len(secret), minSecretLength)
+		}
+
+		distinct := make(map[byte]bool)
+		for _, b := range secret {
+			distinct[b] = true
+		}
+		if len(distinct) < 10 {
+			return StatusWarn, fmt.Sprintf("AUTH_SECRET uses only %d distinct characters", len(distinct))
+		}
+		return StatusOK, fmt.Sprintf("%d bytes", len(secret))
+	}}
+}
+
+// PingCheck verifies a dependency is reachable
+func PingCheck(name string, ping func(ctx context.Context) error) Check {
+	return Check{Name: name, Run: func(ctx context.Context) (Status, string) {
+		if err := ping(ctx); err != nil {
+			return StatusFail, err.Error()
+		}
+		return StatusOK, "reachable"
+	}}
+}
+
+// cloudWatchLister is the subset of the CloudWatch client used to verify access
+type cloudWatchLister interface {
+	ListMetrics(ctx context.Context, params *cloudwatch.ListMetricsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListMetricsOutput, error)
+}
+
+// CloudWatchCheck verifies the configured credentials can read metrics in namespace
+func CloudWatchCheck(client cloudWatchLister, namespace string) Check {
+	return Check{Name: "cloudwatch", Run: func(ctx context.Context) (Status, string) {
+		if _, err := client.ListMetrics(ctx, &cloudwatch.ListMetricsInput{Namespace: &namespace}); err != nil {
+			return StatusFail, err.Error()
+		}
+		return StatusOK, "metrics readable in namespace " + namespace
+	}}
+}
+
+// schemaObject is a table, column, index, type or extension created by a migration
+type schemaObject struct {
+	kind      string // "table", "column", "index", "type" or "extension"
+	table     string // for columns
+	name      string
+	migration string
+}
+
+func (o schemaObject) String() string {
+	if o.kind == "column" {
+		return fmt.Sprintf("column %s.%s (%s)", o.table, o.name, o.migration)
+	}
+	return fmt.Sprintf("%s %s (%s)", o.kind, o.name, o.migration)
+}
+
+var (
+	createTableRe     = regexp.MustCompile(`(?i)CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)`)
+	addColumnRe       = regexp.MustCompile(`(?i)ALTER\s+TABLE\s+(\w+)\s+ADD\s+COLUMN\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)`)
+	createIndexRe     = regexp.MustCompile(`(?i)CREATE\s+(?:UNIQUE\s+)?INDEX\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)`)
+	createTypeRe      = regexp.MustCompile(`(?i)CREATE\s+TYPE\s+(\w+)`)
+	createExtensionRe = regexp.MustCompile(`(?i)CREATE\s+EXTENSION\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)`)
+)
+
+// expectedSchema lists the schema objects the migrations in migrations create
+func expectedSchema(migrations fs.FS) ([]schemaObject, error) {
+	files, err := fs.Glob(migrations, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var objects []schemaObject
+	for _, file := range files {
+		content, err := fs.ReadFile(migrations, file)
+		if err != nil {
+			return nil, err
+		}
+		sql := string(content)
+
+		for _, m := range createTableRe.FindAllStringSubmatch(sql, -1) {
+			objects = append(objects, schemaObject{kind: "table", name: m[1], migration: file})
+		}
+		for _, m := range addColumnRe.FindAllStringSubmatch(sql, -1) {
+			objects = append(objects, schemaObject{kind: "column", table: m[1], name: m[2], migration: file})
+		}
+		for _, m := range createIndexRe.FindAllStringSubmatch(sql, -1) {
+			objects = append(objects, schemaObject{kind: "index", name: m[1], migration: file})
+		}
+		for _, m := range createTypeRe.FindAllStringSubmatch(sql, -1) {
+			objects = append(objects, schemaObject{kind: "type", name: m[1], migration: file})
+		}
+		for _, m := range createExtensionRe.FindAllStringSubmatch(sql, -1) {
+			objects = append(objects, schemaObject{kind: "extension", name: m[1], migration: file})
+		}
+	}
+	return objects, nil
+}
+
+// schemaQueries check whether an object of each kind exists
+var schemaQueries = map[string]string{
+	"table":     `SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1)`,
+	"column":    `SELECT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $2 AND column_name = $1)`,
+	"index":     `SELECT EXISTS (SELECT 1 FROM pg_indexes WHERE schemaname = current_schema() AND indexname = $1)`,
+	"type":      `SELECT EXISTS (SELECT 1 FROM pg_type WHERE typname = $1)`,
+	"extension": `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = $1)`,
+}
+
+// MigrationsCheck verifies every table, column, index, type and extension created by
+// the migrations exists, i.e. that all migrations have been applied
+func MigrationsCheck(db *sql.DB, migrations fs.FS) Check {
+	return Check{Name: "migrations", Run: func(ctx context.Context) (Status, string) {
+		objects, err := expectedSchema(migrations)
+		if err != nil {
+			return StatusFail, "reading migrations: " + err.Error()
+		}
+
+		var missing []string
+		for _, object := range objects {
+			args := []interface{}{object.name}
+			if object.kind == "column" {
+				args = append(args, object.table)
+			}
+
+			var exists bool
+			if err := db.QueryRowContext(ctx, schemaQueries[object.kind], args...).Scan(&exists); err != nil {
+				return StatusFail, err.Error()
+			}
+			if !exists {
+				missing = append(missing, object.String())
+			}
+		}
+
+		if len(missing) > 0 {
+			return StatusFail, "missing " + strings.Join(missing, ", ")
+		}
+		return StatusOK, fmt.Sprintf("%d schema objects present", len(objects))
+	}}
+}
@@ -0,0 +1,92 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Status is the outcome of a single check
+type Status string
+
+const (
+	StatusOK   Status = "OK"
+	StatusWarn Status = "WARN"
+	StatusFail Status = "FAIL"
+	StatusSkip Status = "SKIP"
+)
+
+// checkTimeout bounds how long a single check may take, so an unreachable
+// dependency can't hang the whole report
+const checkTimeout = 10 * time.Second
+
+// Check is a named startup self-check
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) (Status, string)
+}
+
+// Result is the outcome of running a Check
+type Result struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Message  string        `json:"message,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report is the outcome of running a set of checks
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Run runs every check in order, each with its own timeout
+func Run(ctx context.Context, checks []Check) Report {
+	var report Report
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		start := time.Now()
+		status, message := check.Run(checkCtx)
+		cancel()
+
+		report.Results = append(report.Results, Result{
+			Name:     check.Name,
+			Status:   status,
+			Message:  message,
+			Duration: time.Since(start),
+		})
+	}
+	return report
+}
+
+// Failed reports whether any check failed; warnings don't fail the report
+func (r Report) Failed() bool {
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText prints the report as one line per check followed by a summary
+func (r Report) WriteText(w io.Writer) {
+	counts := make(map[Status]int)
+	for _, result := range r.Results {
+		counts[result.Status]++
+		fmt.Fprintf(w, "[%-4s] %-12s %s\n", result.Status, result.Name, result.Message)
+	}
+	fmt.Fprintf(w, "\n%d ok, %d warnings, %d failed, %d skipped\n",
+		counts[StatusOK], counts[StatusWarn], counts[StatusFail], counts[StatusSkip])
+}
+
+// WriteJSON prints the report as JSON, for CI gates
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Report
+		Failed bool `json:"failed"`
+	}{r, r.Failed()})
+}
@@ -0,0 +1,136 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/internal/database/migrations"
+)
+
+func TestJWTSecretCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		want   Status
+	}{
+		{"missing", "", StatusFail},
+		{"placeholder", "your-development-secret", StatusFail},
+		{"too short", "s3cr3t-but-short", StatusFail},
+		{"low variety", strings.Repeat("ab", 20), StatusWarn},
+		{"strong", "q8Z!r2vL#p0Xw7Nc$k4Ty9Hm&e1Bs6Ud", StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _ := JWTSecretCheck([]byte(tt.secret)).Run(context.Background())
+			assert.Equal(t, tt.want, status)
+		})
+	}
+}
+
+func TestConfigCheck(t *testing.T) {
+	env := map[string]string{
+		"AUTH_SECRET": "secret",
+		"AUTH_ISSUER": "issuer",
+		"REDIS_ADDR":  "localhost:6379",
+	}
+	getenv := func(key string) string { return env[key] }
+
+	status, _ := ConfigCheck(getenv).Run(context.Background())
+	assert.Equal(t, StatusOK, status)
+
+	env["JWT_LEEWAY"] = "thirty seconds"
+	env["GUEST_LINKS"] = "missing-project"
+	delete(env, "AUTH_ISSUER")
+	status, message := ConfigCheck(getenv).Run(context.Background())
+	assert.Equal(t, StatusFail, status)
+	assert.Contains(t, message, "AUTH_ISSUER is not set")
+	assert.Contains(t, message, "invalid JWT_LEEWAY")
+	assert.Contains(t, message, "invalid GUEST_LINKS")
+}
+
+func TestExpectedSchema(t *testing.T) {
+	fsys := fstest.MapFS{
+		"001_init.sql": {Data: []byte(`
+			CREATE TYPE task_status AS ENUM ('pending');
+			CREATE TABLE tasks (id VARCHAR(36) PRIMARY KEY);
+			CREATE INDEX idx_tasks_id ON tasks(id);`)},
+		"002_search.sql": {Data: []byte(`
+			CREATE EXTENSION IF NOT EXISTS pg_trgm;
+			ALTER TABLE tasks
+			    ADD COLUMN IF NOT EXISTS search_vector tsvector;
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_search ON tasks(search_vector);`)},
+	}
+
+	objects, err := expectedSchema(fsys)
+	require.NoError(t, err)
+
+	var names []string
+	for _, object := range objects {
+		names = append(names, object.String())
+	}
+	assert.Equal(t, []string{
+		"table tasks (001_init.sql)",
+		"index idx_tasks_id (001_init.sql)",
+		"type task_status (001_init.sql)",
+		"column tasks.search_vector (002_search.sql)",
+		"index idx_tasks_search (002_search.sql)",
+		"extension pg_trgm (002_search.sql)",
+	}, names)
+}
+
+func TestExpectedSchema_EmbeddedMigrations(t *testing.T) {
+	objects, err := expectedSchema(migrations.FS)
+	require.NoError(t, err)
+
+	found := make(map[string]bool)
+	for _, object := range objects {
+		found[object.kind+":"+object.table+"."+object.name] = true
+	}
+	assert.True(t, found["table:.tasks"])
+	assert.True(t, found["column:tasks.assignee_id"])
+	assert.True(t, found["extension:.pg_trgm"])
+}
+
+type fakeCloudWatch struct {
+	err error
+}
+
+func (f *fakeCloudWatch) ListMetrics(ctx context.Context, params *cloudwatch.ListMetricsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.ListMetricsOutput, error) {
+	return &cloudwatch.ListMetricsOutput{}, f.err
+}
+
+func TestRun(t *testing.T) {
+	checks := []Check{
+		PingCheck("postgres", func(ctx context.Context) error { return nil }),
+		PingCheck("redis", func(ctx context.Context) error { return errors.New("connection refused") }),
+		CloudWatchCheck(&fakeCloudWatch{}, "TaskAPI"),
+		{Name: "optional", Run: func(ctx context.Context) (Status, string) { return StatusSkip, "disabled" }},
+	}
+
+	report := Run(context.Background(), checks)
+	require.Len(t, report.Results, 4)
+	assert.Equal(t, StatusOK, report.Results[0].Status)
+	assert.Equal(t, StatusFail, report.Results[1].Status)
+	assert.Equal(t, "connection refused", report.Results[1].Message)
+	assert.Equal(t, StatusOK, report.Results[2].Status)
+	assert.True(t, report.Failed())
+
+	var out bytes.Buffer
+	report.WriteText(&out)
+	assert.Contains(t, out.String(), "[FAIL] redis")
+	assert.Contains(t, out.String(), "2 ok, 0 warnings, 1 failed, 1 skipped")
+}
+
+func TestReport_Failed(t *testing.T) {
+	report := Report{Results: []Result{{Status: StatusOK}, {Status: StatusWarn}, {Status: StatusSkip}}}
+	assert.False(t, report.Failed())
+}
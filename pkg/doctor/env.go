@@ -0,0 +1,219 @@
+package doctor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+
+	"sample/task-management-system/internal/database/migrations"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/metrics"
+	"sample/task-management-system/pkg/middleware"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/queue"
+)
+
+// Getenv looks up a configuration setting, e.g. os.Getenv
+type Getenv func(key string) string
+
+// envParsers validate optional settings that must parse when set
+var envParsers = map[string]func(string) error{
+	"JWT_LEEWAY":                      parseDuration,
+	"JWT_PUBLIC_KEYS":                 parsePublicKeys,
+	"JWKS_REFRESH_INTERVAL":           parseDuration,
+	"STEP_UP_MAX_AGE":                 parseDuration,
+	"SECURITY_WINDOW":                 parseDuration,
+	"MAINTENANCE_RETRY_AFTER":         parseDuration,
+	"MAINTENANCE_MODE":                parseBool,
+	"SANDBOX_MODE":                    parseBool,
+	"TOKEN_CACHE_SIZE":                parseInt,
+	"L1_CACHE_TTL":                    parseDuration,
+	"L1_CACHE_SIZE":                   parseInt,
+	"SECURITY_UNAUTHORIZED_THRESHOLD": parseInt,
+	"SECURITY_FORBIDDEN_THRESHOLD":    parseInt,
+	"ATTACHMENT_MAX_BYTES":            parseInt,
+	"STALE_TASK_DAYS":                 parseInt,
+	"DEPRECATION_NOTICE_DAYS":         parseInt,
+	"ROLE_RELOAD_INTERVAL":            parseDuration,
+	"DEPRECATIONS_FILE":               parseDeprecations,
+	"PREFETCH_HINTS":                  parseInt,
+	"REPORT_TIMEOUT":                  parseDuration,
+	"EXPORT_MAX_DURATION":             parseDuration,
+	"EXPORT_MAX_STALL":                parseDuration,
+	"WEBHOOK_TIMEOUT":                 parseDuration,
+	"EVENT_ARCHIVE_RETENTION":         parseDuration,
+	"RUN_WORKERS":                     parseBool,
+	"JOB_SLOTS":                       parseInt,
+	"BATCH_MAX_REQUESTS":              parseInt,
+	"RATE_LIMIT_REQUESTS":             parseInt,
+	"RATE_LIMIT_WINDOW":               parseDuration,
+	"JOB_LIMITS": func(value string) error {
+		_, err := queue.ParseLimits(value)
+		return err
+	},
+	"JOB_RETRY_POLICIES": func(value string) error {
+		_, err := models.ParseRetryPolicies(value)
+		return err
+	},
+	"TIME_FORMAT": func(value string) error {
+		_, err := models.ParseTimeFormat(value)
+		return err
+	},
+	"SYNC_CONFLICT_POLICY": func(value string) error {
+		_, err := models.ParseConflictPolicy(value)
+		return err
+	},
+	"GUEST_LINKS": func(value string) error {
+		_, err := auth.ParseGuestLinks(value)
+		return err
+	},
+	"METRICS_SAMPLE_RATE": func(value string) error {
+		_, err := metrics.ParseSampleRate(value)
+		return err
+	},
+	"TELEMETRY_SAMPLE_RATE": func(value string) error {
+		_, err := metrics.ParseSampleRate(value)
+		return err
+	},
+	"METRICS_ROUTE_SAMPLING": func(value string) error {
+		_, err := metrics.ParseRouteRules(value)
+		return err
+	},
+	"TRUSTED_PROXIES": func(value string) error {
+		_, err := middleware.NewClientIPResolver(strings.Split(value, ","))
+		return err
+	},
+}
+
+func parseDuration(value string) error {
+	_, err := time.ParseDuration(value)
+	return err
+}
+
+func parseBool(value string) error {
+	_, err := strconv.ParseBool(value)
+	return err
+}
+
+func parseInt(value string) error {
+	_, err := strconv.Atoi(value)
+	return err
+}
+
+func parsePublicKeys(value string) error {
+	_, err := auth.LoadPublicKeys(value)
+	return err
+}
+
+func parseDeprecations(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	_, err = models.ParseDeprecations(data)
+	return err
+}
+
+// ConfigCheck verifies required settings are present and optional ones parse
+func ConfigCheck(getenv Getenv) Check {
+	return Check{Name: "config", Run: func(ctx context.Context) (Status, string) {
+		var problems []string
+		for _, key := range []string{"AUTH_SECRET", "AUTH_ISSUER", "REDIS_ADDR"} {
+			if getenv(key) == "" {
+				problems = append(problems, key+" is not set")
+			}
+		}
+
+		keys := make([]string, 0, len(envParsers))
+		for key := range envParsers {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if value := getenv(key); value != "" {
+				if err := envParsers[key](value); err != nil {
+					problems = append(problems, fmt.Sprintf("invalid %s: %v", key, err))
+				}
+			}
+		}
+
+		if len(problems) > 0 {
+			return StatusFail, strings.Join(problems, "; ")
+		}
+		return StatusOK, "required settings present"
+	}}
+}
+
+// FromEnv builds the full set of checks from the same environment the API server reads.
+// The returned cleanup closes any connections opened for the checks.
+func FromEnv(getenv Getenv) ([]Check, func()) {
+	envOr := func(key, fallback string) string {
+		if value := getenv(key); value != "" {
+			return value
+		}
+		return fallback
+	}
+
+	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		envOr("DB_USER", "postgres"), envOr("DB_PASSWORD", "postgres"),
+		envOr("DB_HOST", "localhost"), envOr("DB_PORT", "5432"), envOr("DB_NAME", "taskdb"))
+	// sql.Open only validates the driver name; connection errors surface on ping
+	db, openErr := sql.Open("postgres", dbURL)
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     getenv("REDIS_ADDR"),
+		Password: getenv("REDIS_PASSWORD"),
+	})
+
+	checks := []Check{
+		ConfigCheck(getenv),
+		JWTSecretCheck([]byte(getenv("AUTH_SECRET"))),
+		PingCheck("postgres", func(ctx context.Context) error {
+			if openErr != nil {
+				return openErr
+			}
+			return db.PingContext(ctx)
+		}),
+		{Name: "migrations", Run: func(ctx context.Context) (Status, string) {
+			if openErr != nil || db.PingContext(ctx) != nil {
+				return StatusSkip, "postgres unreachable"
+			}
+			return MigrationsCheck(db, migrations.FS).Run(ctx)
+		}},
+		PingCheck("redis", func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}),
+		cloudWatchFromEnv(getenv),
+	}
+
+	cleanup := func() {
+		if openErr == nil {
+			db.Close()
+		}
+		redisClient.Close()
+	}
+	return checks, cleanup
+}
+
+// cloudWatchFromEnv checks CloudWatch access when metrics are enabled
+func cloudWatchFromEnv(getenv Getenv) Check {
+	return Check{Name: "cloudwatch", Run: func(ctx context.Context) (Status, string) {
+		if getenv("ENABLE_METRICS") != "true" {
+			return StatusSkip, "ENABLE_METRICS is not true"
+		}
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(getenv("AWS_REGION")))
+		if err != nil {
+			return StatusFail, "loading AWS config: " + err.Error()
+		}
+		return CloudWatchCheck(cloudwatch.NewFromConfig(cfg), "TaskAPI").Run(ctx)
+	}}
+}
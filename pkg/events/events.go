@@ -0,0 +1,107 @@
+// Package events lets parts of the system react to task changes without the write
+// path knowing about them.
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+)
+
+// Type identifies what happened to a task
+type Type string
+
+const (
+	TaskCreated Type = "task.created"
+	TaskUpdated Type = "task.updated"
+	TaskDeleted Type = "task.deleted"
+	// TaskStale is published when a task is flagged for going without updates
+	TaskStale Type = "task.stale"
+	// TaskOverdue is published when an open task is flagged for passing its due date
+	TaskOverdue Type = "task.overdue"
+)
+
+// Event describes a change to a task
+type Event struct {
+	// ID identifies the event wherever it is delivered; assigned when it is published
+	ID         string
+	Type       Type
+	TaskID     string
+	Task       *models.Task // the task after the change; nil for TaskDeleted
+	OrgID      string       // the tenant of the task; empty for the default tenant
+	OccurredAt time.Time
+}
+
+// Handler reacts to an event. Errors are logged; they never fail the original write.
+type Handler func(ctx context.Context, event Event) error
+
+// Publisher publishes events
+type Publisher interface {
+	Publish(event Event)
+}
+
+// Bus delivers events to its subscribers in the background, one at a time and in the
+// order they were published, so handlers can maintain state incrementally
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []subscription
+	queue    chan Event
+}
+
+type subscription struct {
+	name    string
+	handler Handler
+}
+
+// NewBus creates a bus buffering up to bufferSize events. Publishing blocks while the
+// buffer is full rather than losing events.
+func NewBus(bufferSize int) *Bus {
+	return &Bus{queue: make(chan Event, bufferSize)}
+}
+
+// Subscribe registers handler under name, used in logs
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, subscription{name: name, handler: handler})
+}
+
+// Publish queues an event for delivery
+func (b *Bus) Publish(event Event) {
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	b.queue <- event
+}
+
+// Run delivers events until ctx is done
+func (b *Bus) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-b.queue:
+			b.deliver(ctx, event)
+		}
+	}
+}
+
+func (b *Bus) deliver(ctx context.Context, event Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, s := range handlers {
+		if err := s.handler(ctx, event); err != nil {
+			log.Printf("Event handler %s failed on %s for task %s: %v", s.name, event.Type, event.TaskID, err)
+		}
+	}
+}
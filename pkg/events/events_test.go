@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+func TestBus_DeliversInOrder(t *testing.T) {
+	bus := NewBus(10)
+	var failing, received []string
+	bus.Subscribe("failing", func(ctx context.Context, event Event) error {
+		failing = append(failing, event.TaskID)
+		return errors.New("boom")
+	})
+
+	done := make(chan struct{})
+	bus.Subscribe("recorder", func(ctx context.Context, event Event) error {
+		received = append(received, event.TaskID)
+		if len(received) == 3 {
+			close(done)
+		}
+		return nil
+	})
+
+	for _, id := range []string{"1", "2", "3"} {
+		bus.Publish(Event{Type: TaskCreated, TaskID: id})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bus.Run(ctx)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("events were not delivered")
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, failing)
+	assert.Equal(t, []string{"1", "2", "3"}, received)
+}
+
+type recordingPublisher struct {
+	events []Event
+}
+
+func (p *recordingPublisher) Publish(event Event) {
+	p.events = append(p.events, event)
+}
+
+// stubRepository implements the writes used by the tests; other methods panic
+type stubRepository struct {
+	repository.TaskRepository
+	err error
+}
+
+func (r *stubRepository) Create(ctx context.Context, input *models.TaskCreate) (*models.Task, error) {
+	return &models.Task{ID: "new", Title: input.Title}, r.err
+}
+
+func (r *stubRepository) UpdateSubtaskStatus(ctx context.Context, parentID string, status models.TaskStatus) ([]*models.Task, error) {
+	return []*models.Task{{ID: "child-1", Status: status}, {ID: "child-2", Status: status}}, r.err
+}
+
+func (r *stubRepository) Delete(ctx context.Context, id string) error {
+	return r.err
+}
+
+func TestPublishingRepository(t *testing.T) {
+	ctx := context.Background()
+	publisher := &recordingPublisher{}
+	repo := NewPublishingRepository(&stubRepository{}, publisher)
+
+	_, err := repo.Create(ctx, &models.TaskCreate{Title: "Write docs"})
+	require.NoError(t, err)
+	_, err = repo.UpdateSubtaskStatus(ctx, "parent", models.StatusCompleted)
+	require.NoError(t, err)
+	require.NoError(t, repo.Delete(ctx, "old"))
+
+	require.Len(t, publisher.events, 4)
+	assert.Equal(t, TaskCreated, publisher.events[0].Type)
+	assert.Equal(t, "new", publisher.events[0].Task.ID)
+	assert.Equal(t, TaskUpdated, publisher.events[1].Type)
+	assert.Equal(t, "child-2", publisher.events[2].TaskID)
+	assert.Equal(t, Event{Type: TaskDeleted, TaskID: "old"}, publisher.events[3])
+}
+
+func TestPublishingRepository_FailedWritesAreNotPublished(t *testing.T) {
+	publisher := &recordingPublisher{}
+	repo := NewPublishingRepository(&stubRepository{err: errors.New("db down")}, publisher)
+
+	assert.Error(t, repo.Delete(context.Background(), "old"))
+	assert.Empty(t, publisher.events)
+}
@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// publishingRepository wraps a TaskRepository and publishes an event for every
+// successful write, so every path that changes tasks is covered
+type publishingRepository struct {
+	repository.TaskRepository
+	publisher Publisher
+}
+
+// NewPublishingRepository creates a TaskRepository that publishes task writes
+func NewPublishingRepository(repo repository.TaskRepository, publisher Publisher) repository.TaskRepository {
+	return &publishingRepository{
+		TaskRepository: repo,
+		publisher:      publisher,
+	}
+}
+
+func (r *publishingRepository) Create(ctx context.Context, input *models.TaskCreate) (*models.Task, error) {
+	task, err := r.TaskRepository.Create(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	r.publish(TaskCreated, task)
+	return task, nil
+}
+
+func (r *publishingRepository) CreateBatch(ctx context.Context, inputs []*models.TaskCreate) ([]*models.Task, error) {
+	tasks, err := r.TaskRepository.CreateBatch(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		r.publish(TaskCreated, task)
+	}
+	return tasks, nil
+}
+
+func (r *publishingRepository) Update(ctx context.Context, id string, input *models.TaskUpdate) (*models.Task, error) {
+	task, err := r.TaskRepository.Update(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+	r.publish(TaskUpdated, task)
+	return task, nil
+}
+
+// PublishDraft publishes TaskCreated, since the task is new to everyone but its owner.
+// Saving a draft publishes nothing.
+func (r *publishingRepository) PublishDraft(ctx context.Context, id string, status models.TaskStatus) (*models.Task, error) {
+	task, err := r.TaskRepository.PublishDraft(ctx, id, status)
+	if err != nil {
+		return nil, err
+	}
+	r.publish(TaskCreated, task)
+	return task, nil
+}
+
+func (r *publishingRepository) UpdateSubtaskStatus(ctx context.Context, parentID string, status models.TaskStatus) ([]*models.Task, error) {
+	tasks, err := r.TaskRepository.UpdateSubtaskStatus(ctx, parentID, status)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		r.publish(TaskUpdated, task)
+	}
+	return tasks, nil
+}
+
+func (r *publishingRepository) Delete(ctx context.Context, id string) error {
+	if err := r.TaskRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	// A delete in a tenant's context only reaches the tenant's own tasks
+	orgID, _ := tenant.FromContext(ctx)
+	r.publisher.Publish(Event{Type: TaskDeleted, TaskID: id, OrgID: orgID})
+	return nil
+}
+
+func (r *publishingRepository) publish(eventType Type, task *models.Task) {
+	r.publisher.Publish(Event{Type: eventType, TaskID: task.ID, Task: task, OrgID: task.OrgID})
+}
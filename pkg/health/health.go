@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"runtime"
 	"time"
 
+	"sample/task-management-system/pkg/metrics"
 	"sample/task-management-system/pkg/monitoring"
 )
 
@@ -15,8 +17,9 @@ import (
 type Status string
 
 const (
-	StatusUp   Status = "UP"
-	StatusDown Status = "DOWN"
+	StatusUp       Status = "UP"
+	StatusDown     Status = "DOWN"
+	StatusDegraded Status = "DEGRADED"
 )
 
 // HealthResponse represents the health check response
@@ -26,6 +29,15 @@ type HealthResponse struct {
 	Version   string                `json:"version"`
 	Services  map[string]Component  `json:"services"`
 	System    SystemInfo            `json:"system"`
+	Signals   *metrics.Signals      `json:"signals,omitempty"`
+}
+
+// Summary is the minimal health payload served to unauthenticated callers such as
+// load balancers; it carries no component messages or system details
+type Summary struct {
+	Status    Status    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+	Version   string    `json:"version"`
 }
 
 // Component represents a service component's health
@@ -52,6 +64,8 @@ type Handler struct {
 	monitor  interface {
 		UpdateServiceState(state monitoring.ServiceState) error
 	}
+	telemetry func() metrics.Status
+	signals   func() metrics.Signals
 }
 
 // NewHandler creates a new health check handler
@@ -64,17 +78,49 @@ func NewHandler(version string, db *sql.DB, cache interface{ Ping(ctx context.Co
 	}
 }
 
-// ServeHTTP implements the http.Handler interface
+// WithTelemetry reports metrics publishing as a "telemetry" component. Degraded
+// telemetry never takes the service down, since requests are still being served.
+func (h *Handler) WithTelemetry(status func() metrics.Status) *Handler {
+	h.telemetry = status
+	return h
+}
+
+// WithSignals adds the API's latency, traffic, errors and saturation to the detailed
+// health payload. They are informational and never change the reported status.
+func (h *Handler) WithSignals(signals func() metrics.Signals) *Handler {
+	h.signals = signals
+	return h
+}
+
+// ServeHTTP implements the http.Handler interface. It serves only the overall status,
+// since component messages may contain internal connection errors.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	response := h.checkHealth(ctx)
+	response := h.checkHealth(r.Context())
 
+	writeHealth(w, response.Status, Summary{
+		Status:    response.Status,
+		Timestamp: response.Timestamp,
+		Version:   response.Version,
+	})
+}
+
+// Details serves the full health payload, including component messages and system info.
+// It must only be routed behind authentication.
+func (h *Handler) Details(w http.ResponseWriter, r *http.Request) {
+	response := h.checkHealth(r.Context())
+
+	writeHealth(w, response.Status, response)
+}
+
+// writeHealth writes a health payload, reporting 503 when the service is down
+func writeHealth(w http.ResponseWriter, status Status, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
-	if response.Status == StatusDown {
+	w.Header().Set("Cache-Control", "no-store")
+	if status == StatusDown {
 		w.WriteHeader(http.StatusServiceUnavailable)
 	}
 
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(payload)
 }
 
 // checkHealth performs the health check
@@ -120,6 +166,10 @@ func (h *Handler) checkHealth(ctx context.Context) HealthResponse {
 		}
 	}
 
+	if h.telemetry != nil {
+		services["telemetry"] = h.checkTelemetry()
+	}
+
 	// Get system info
 	var mem runtime.MemStats
 	runtime.ReadMemStats(&mem)
@@ -144,13 +194,18 @@ func (h *Handler) checkHealth(ctx context.Context) HealthResponse {
 		})
 	}
 
-	return HealthResponse{
+	response := HealthResponse{
 		Status:    overallStatus,
 		Timestamp: time.Now().UTC(),
 		Version:   h.version,
 		Services:  services,
 		System:    sysInfo,
 	}
+	if h.signals != nil {
+		signals := h.signals()
+		response.Signals = &signals
+	}
+	return response
 }
 
 // checkDatabase verifies database connectivity
@@ -197,4 +252,26 @@ func (h *Handler) checkRedis(ctx context.Context) Component {
 		Status:  StatusUp,
 		Message: "Redis connection successful",
 	}
-} 
\ No newline at end of file
+}
+
+// checkTelemetry reports whether metrics are reaching CloudWatch
+func (h *Handler) checkTelemetry() Component {
+	status := h.telemetry()
+	switch status.State {
+	case metrics.TelemetryDisabled:
+		return Component{
+			Status:  StatusUp,
+			Message: "Telemetry disabled",
+		}
+	case metrics.TelemetryDegraded:
+		return Component{
+			Status:  StatusDegraded,
+			Message: fmt.Sprintf("telemetry degraded: %s (%d buffered, %d dropped)", status.LastError, status.Buffered, status.Dropped),
+		}
+	}
+
+	return Component{
+		Status:  StatusUp,
+		Message: fmt.Sprintf("Telemetry publishing (%d buffered, %d dropped)", status.Buffered, status.Dropped),
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"sample/task-management-system/pkg/metrics"
 	"sample/task-management-system/pkg/monitoring"
 )
 
@@ -151,6 +152,80 @@ func TestHealthHandler_ServeHTTP(t *testing.T) {
 	}
 }
 
+func TestHealthHandler_DetailsTiers(t *testing.T) {
+	mockCache := &MockRedisCache{}
+	mockCache.On("Ping").Return(errors.New("dial tcp 10.0.3.7:6379: connection refused"))
+	mockMonitor := &MockServiceMonitor{}
+	mockMonitor.On("UpdateServiceState", mock.Anything).Return(nil)
+
+	handler := NewHandler("1.0.0", nil, mockCache, mockMonitor)
+
+	// The public summary must not leak component messages or system info
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/health", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "10.0.3.7")
+
+	var summary map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &summary))
+	assert.Equal(t, "DOWN", summary["status"])
+	assert.NotContains(t, summary, "services")
+	assert.NotContains(t, summary, "system")
+
+	// Details include everything
+	rr = httptest.NewRecorder()
+	handler.Details(rr, httptest.NewRequest("GET", "/health/details", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	var details HealthResponse
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &details))
+	assert.Contains(t, details.Services["cache"].Message, "10.0.3.7")
+	assert.NotEmpty(t, details.System.GoVersion)
+}
+
+func TestHealthHandler_Telemetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      metrics.Status
+		wantStatus  Status
+		wantMessage string
+	}{
+		{
+			name:        "disabled",
+			status:      metrics.Status{State: metrics.TelemetryDisabled},
+			wantStatus:  StatusUp,
+			wantMessage: "Telemetry disabled",
+		},
+		{
+			name:        "publishing",
+			status:      metrics.Status{State: metrics.TelemetryOK, Buffered: 3},
+			wantStatus:  StatusUp,
+			wantMessage: "3 buffered",
+		},
+		{
+			name:        "degraded",
+			status:      metrics.Status{State: metrics.TelemetryDegraded, Dropped: 40, LastError: "no route to host"},
+			wantStatus:  StatusDegraded,
+			wantMessage: "telemetry degraded: no route to host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := &MockRedisCache{}
+			mockCache.On("Ping").Return(nil)
+
+			handler := NewHandler("1.0.0", nil, mockCache, nil).WithTelemetry(func() metrics.Status {
+				return tt.status
+			})
+
+			response := handler.checkHealth(context.Background())
+			assert.Equal(t, tt.wantStatus, response.Services["telemetry"].Status)
+			assert.Contains(t, response.Services["telemetry"].Message, tt.wantMessage)
+		})
+	}
+}
+
 func TestHealthHandler_CacheTimeout(t *testing.T) {
 	mockCache := &MockRedisCache{}
 	mockMonitor := &MockServiceMonitor{}
@@ -0,0 +1,285 @@
+// Package importer reads tasks from CSV files: files with a header of task field names,
+// and CSV exports of Jira issues.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// csvColumns maps the header names of ImportCSV files to task fields
+var csvColumns = map[string]string{
+	"title":             "title",
+	"description":       "description",
+	"status":            "status",
+	"priority":          "priority",
+	"due_date":          "due_date",
+	"assignee_id":       "assignee_id",
+	"tags":              "tags", // separated by ";"
+	"estimated_minutes": "estimated_minutes",
+	"project_key":       "project_key",
+}
+
+// jiraColumns maps the header names of Jira CSV exports, lowercased, to task fields
+var jiraColumns = map[string]string{
+	"summary":           "title",
+	"description":       "description",
+	"status":            "status",
+	"priority":          "priority",
+	"due date":          "due_date",
+	"assignee":          "assignee_id",
+	"labels":            "tags", // Jira repeats the column once per label
+	"original estimate": "estimate_seconds",
+	"project key":       "project_key",
+}
+
+// jiraStatuses maps common Jira status names to task statuses; others are imported as pending
+var jiraStatuses = map[string]models.TaskStatus{
+	"to do":       models.StatusPending,
+	"open":        models.StatusPending,
+	"backlog":     models.StatusPending,
+	"reopened":    models.StatusPending,
+	"in progress": models.StatusInProgress,
+	"in review":   models.StatusInProgress,
+	"done":        models.StatusCompleted,
+	"resolved":    models.StatusCompleted,
+	"closed":      models.StatusCompleted,
+	"won't do":    models.StatusCancelled,
+	"cancelled":   models.StatusCancelled,
+	"canceled":    models.StatusCancelled,
+}
+
+// jiraPriorities maps Jira priority names to task priorities; others are imported as medium
+var jiraPriorities = map[string]models.TaskPriority{
+	"highest":  models.PriorityCritical,
+	"blocker":  models.PriorityCritical,
+	"critical": models.PriorityCritical,
+	"high":     models.PriorityHigh,
+	"major":    models.PriorityHigh,
+	"medium":   models.PriorityMedium,
+	"low":      models.PriorityLow,
+	"lowest":   models.PriorityLow,
+	"minor":    models.PriorityLow,
+	"trivial":  models.PriorityLow,
+}
+
+// dateLayouts are the due date formats accepted, Jira's own first
+var dateLayouts = []string{
+	"02/Jan/06 3:04 PM",
+	"02/Jan/06",
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Reader reads tasks from an import file, one row at a time
+type Reader struct {
+	source  models.ImportSource
+	csv     *csv.Reader
+	fields  []string // task field of each column, "" for ignored columns
+	options models.ImportOptions
+	row     int
+}
+
+// NewReader reads the header of an import file. Rows without a project use the options'
+// project and rows without a due date its default due date.
+func NewReader(r io.Reader, options models.ImportOptions) (*Reader, error) {
+	columns := csvColumns
+	if options.Source == models.ImportJira {
+		columns = jiraColumns
+	}
+	mapping, err := columnMapping(options.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+
+	fields := make([]string, len(header))
+	hasTitle := false
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if field, ok := mapping[strings.ToLower(name)]; ok {
+			fields[i] = field
+		} else {
+			if options.Source == models.ImportJira {
+				name = strings.ToLower(name)
+			}
+			fields[i] = columns[name]
+		}
+		if fields[i] == "title" {
+			hasTitle = true
+		}
+	}
+	if !hasTitle {
+		if options.Source == models.ImportJira {
+			return nil, errors.New("the header has no Summary column")
+		}
+		return nil, errors.New("the header has no title column")
+	}
+
+	return &Reader{source: options.Source, csv: reader, fields: fields, options: options}, nil
+}
+
+// columnMapping checks a mapping of header names to task fields, keying it by the
+// lowercased header name
+func columnMapping(columns map[string]string) (map[string]string, error) {
+	mapping := make(map[string]string, len(columns))
+	for name, field := range columns {
+		field = strings.TrimSpace(field)
+		if _, ok := csvColumns[field]; field != "" && !ok {
+			return nil, fmt.Errorf("column %q is mapped to unknown field %q", name, field)
+		}
+		mapping[strings.ToLower(strings.TrimSpace(name))] = field
+	}
+	return mapping, nil
+}
+
+// Next returns the next row as a task to create, with the row's number. It returns
+// io.EOF after the last row, a *RowError for a row that can't be imported, and any
+// other error when the file itself can't be read further.
+func (r *Reader) Next() (*models.TaskCreate, int, error) {
+	record, err := r.csv.Read()
+	if err == io.EOF {
+		return nil, r.row, io.EOF
+	}
+	r.row++
+	if err != nil {
+		return nil, r.row, fmt.Errorf("invalid CSV at row %d: %v", r.row, err)
+	}
+
+	task, err := r.task(record)
+	if err != nil {
+		return nil, r.row, &RowError{Row: r.row, Err: err}
+	}
+	return task, r.row, nil
+}
+
+// Count reads the remaining rows, returning how many there are
+func (r *Reader) Count() (int, error) {
+	for {
+		_, _, err := r.Next()
+		var rowErr *RowError
+		if err == io.EOF {
+			return r.row, nil
+		}
+		if err != nil && !errors.As(err, &rowErr) {
+			return 0, err
+		}
+	}
+}
+
+// RowError reports a row that can't be imported; the rows after it can
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (r *Reader) task(record []string) (*models.TaskCreate, error) {
+	task := &models.TaskCreate{ProjectKey: r.options.ProjectKey}
+	if r.options.DefaultDueDate != nil {
+		task.DueDate = *r.options.DefaultDueDate
+	}
+
+	for i, value := range record {
+		if i >= len(r.fields) {
+			break
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch r.fields[i] {
+		case "title":
+			task.Title = value
+		case "description":
+			task.Description = value
+		case "status":
+			task.Status = r.status(value)
+		case "priority":
+			task.Priority = r.priority(value)
+		case "due_date":
+			dueDate, err := parseDate(value)
+			if err != nil {
+				return nil, err
+			}
+			task.DueDate = dueDate
+		case "assignee_id":
+			task.AssigneeID = value
+		case "tags":
+			if r.source == models.ImportJira {
+				task.Tags = append(task.Tags, value)
+			} else {
+				task.Tags = append(task.Tags, strings.Split(value, ";")...)
+			}
+		case "estimated_minutes":
+			minutes, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid estimated_minutes %q", value)
+			}
+			task.EstimatedMinutes = minutes
+		case "estimate_seconds":
+			seconds, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Original Estimate %q", value)
+			}
+			task.EstimatedMinutes = (seconds + 59) / 60
+		case "project_key":
+			task.ProjectKey = value
+		}
+	}
+
+	if task.DueDate.IsZero() {
+		return nil, errors.New("due date is required; set a default due date for rows without one")
+	}
+	return task, nil
+}
+
+func (r *Reader) status(value string) models.TaskStatus {
+	if r.source != models.ImportJira {
+		return models.TaskStatus(value)
+	}
+	if status, ok := jiraStatuses[strings.ToLower(value)]; ok {
+		return status
+	}
+	return models.StatusPending
+}
+
+func (r *Reader) priority(value string) models.TaskPriority {
+	if r.source != models.ImportJira {
+		return models.TaskPriority(value)
+	}
+	if priority, ok := jiraPriorities[strings.ToLower(value)]; ok {
+		return priority
+	}
+	return models.PriorityMedium
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid due date %q", value)
+}
@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+func TestReader_CSV(t *testing.T) {
+	file := "title,status,priority,due_date,tags,estimated_minutes,notes\n" +
+		"Write docs,in_progress,high,2030-01-15,docs;Writing,90,ignored\n" +
+		"No due date,,,,,,\n" +
+		"Bad estimate,,,2030-01-15,,lots,\n"
+	reader, err := NewReader(strings.NewReader(file), models.ImportOptions{Source: models.ImportCSV, ProjectKey: "OPS"})
+	require.NoError(t, err)
+
+	task, row, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, 1, row)
+	assert.Equal(t, "Write docs", task.Title)
+	assert.Equal(t, models.StatusInProgress, task.Status)
+	assert.Equal(t, models.PriorityHigh, task.Priority)
+	assert.Equal(t, time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC), task.DueDate)
+	assert.Equal(t, []string{"docs", "Writing"}, task.Tags)
+	assert.Equal(t, 90, task.EstimatedMinutes)
+	assert.Equal(t, "OPS", task.ProjectKey)
+
+	var rowErr *RowError
+	_, row, err = reader.Next()
+	require.True(t, errors.As(err, &rowErr))
+	assert.Equal(t, 2, row)
+	assert.Contains(t, err.Error(), "due date is required")
+
+	_, _, err = reader.Next()
+	require.True(t, errors.As(err, &rowErr))
+	assert.Equal(t, 3, rowErr.Row)
+
+	_, _, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReader_Jira(t *testing.T) {
+	dueDate := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	file := "Summary,Issue key,Status,Priority,Due date,Labels,Labels,Original Estimate,Project key\n" +
+		"Fix login,PROJ-1,In Progress,Highest,01/Mar/30 5:00 PM,auth,backend,5400,PROJ\n" +
+		"Tidy up,PROJ-2,Waiting for QA,Unknown,,,,,\n"
+	reader, err := NewReader(strings.NewReader(file), models.ImportOptions{Source: models.ImportJira, ProjectKey: "TASK", DefaultDueDate: &dueDate})
+	require.NoError(t, err)
+
+	task, _, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "Fix login", task.Title)
+	assert.Equal(t, models.StatusInProgress, task.Status)
+	assert.Equal(t, models.PriorityCritical, task.Priority)
+	assert.Equal(t, time.Date(2030, 3, 1, 17, 0, 0, 0, time.UTC), task.DueDate)
+	assert.Equal(t, []string{"auth", "backend"}, task.Tags)
+	assert.Equal(t, 90, task.EstimatedMinutes)
+	assert.Equal(t, "PROJ", task.ProjectKey)
+
+	// Unknown statuses and priorities get defaults; the default due date fills the gap
+	task, _, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, task.Status)
+	assert.Equal(t, models.PriorityMedium, task.Priority)
+	assert.Equal(t, dueDate, task.DueDate)
+	assert.Equal(t, "TASK", task.ProjectKey)
+}
+
+func TestReader_Count(t *testing.T) {
+	reader, err := NewReader(strings.NewReader("title\na\n\nb\nc\n"), models.ImportOptions{Source: models.ImportCSV})
+	require.NoError(t, err)
+	count, err := reader.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestNewReader_RequiresTitle(t *testing.T) {
+	_, err := NewReader(strings.NewReader("name,status\nx,pending\n"), models.ImportOptions{Source: models.ImportCSV})
+	assert.Error(t, err)
+
+	_, err = NewReader(strings.NewReader(""), models.ImportOptions{Source: models.ImportJira})
+	assert.Error(t, err)
+}
+
+func TestReader_ColumnMapping(t *testing.T) {
+	file := "Name,Deadline,Status,Owner\n" +
+		"Ship it,2030-01-15,pending,someone\n"
+	reader, err := NewReader(strings.NewReader(file), models.ImportOptions{
+		Source:  models.ImportCSV,
+		Columns: map[string]string{"name": "title", "Deadline": "due_date", "status": ""},
+	})
+	require.NoError(t, err)
+
+	task, _, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "Ship it", task.Title)
+	assert.Equal(t, time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC), task.DueDate)
+	// Columns mapped to "" are ignored even when named after a field
+	assert.Empty(t, task.Status)
+
+	_, err = NewReader(strings.NewReader(file), models.ImportOptions{
+		Source:  models.ImportCSV,
+		Columns: map[string]string{"Name": "title", "Owner": "owner_id"},
+	})
+	assert.ErrorContains(t, err, "unknown field")
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of calling a dependency that is backing off
+var ErrCircuitOpen = errors.New("circuit open, dependency is backing off")
+
+// Breaker is a circuit breaker with exponential backoff for calls to a flaky dependency.
+// After a failure it rejects calls until the backoff elapses, then lets a single call
+// through; each consecutive failure doubles the backoff up to max. State changes are
+// logged once rather than on every failed call.
+type Breaker struct {
+	name      string
+	base, max time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	backoff   time.Duration
+	openUntil time.Time
+	lastErr   error
+}
+
+// NewBreaker creates a closed breaker backing off from base up to max
+func NewBreaker(name string, base, max time.Duration) *Breaker {
+	return &Breaker{name: name, base: base, max: max, now: time.Now}
+}
+
+// Allow reports whether a call may be made now. Once the backoff has elapsed it
+// lets one trial call through and holds back others until that call is recorded.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures == 0 {
+		return true
+	}
+	now := b.now()
+	if now.Before(b.openUntil) {
+		return false
+	}
+	b.openUntil = now.Add(b.backoff)
+	return true
+}
+
+// Record records the outcome of a call made after Allow
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		if b.failures > 0 {
+			log.Printf("%s recovered after %d failed attempt(s)", b.name, b.failures)
+		}
+		b.failures, b.backoff, b.lastErr = 0, 0, nil
+		return
+	}
+
+	b.failures++
+	b.lastErr = err
+	if b.backoff == 0 {
+		b.backoff = b.base
+		log.Printf("%s degraded, backing off: %v", b.name, err)
+	} else if b.backoff *= 2; b.backoff > b.max {
+		b.backoff = b.max
+	}
+	b.openUntil = b.now().Add(b.backoff)
+}
+
+// Degraded reports whether the last call failed, and with which error
+func (b *Breaker) Degraded() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures > 0, b.lastErr
+}
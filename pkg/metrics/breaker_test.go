@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker_Backoff(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewBreaker("test", time.Second, 4*time.Second)
+	b.now = func() time.Time { return now }
+
+	assert.True(t, b.Allow())
+	b.Record(errors.New("unreachable"))
+
+	degraded, err := b.Degraded()
+	assert.True(t, degraded)
+	assert.EqualError(t, err, "unreachable")
+
+	// Each consecutive failure doubles the backoff up to the max
+	for _, backoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		now = now.Add(backoff - time.Millisecond)
+		assert.False(t, b.Allow(), "allowed before backoff elapsed")
+
+		now = now.Add(time.Millisecond)
+		assert.True(t, b.Allow())
+		assert.False(t, b.Allow(), "only one trial call per backoff")
+		b.Record(errors.New("unreachable"))
+	}
+
+	// A success closes the circuit
+	now = now.Add(4 * time.Second)
+	assert.True(t, b.Allow())
+	b.Record(nil)
+
+	degraded, err = b.Degraded()
+	assert.False(t, degraded)
+	assert.NoError(t, err)
+	assert.True(t, b.Allow())
+	assert.True(t, b.Allow())
+}
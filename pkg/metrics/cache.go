@@ -0,0 +1,203 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// CacheLevel is where a cached response was found
+type CacheLevel string
+
+const (
+	CacheL1   CacheLevel = "L1" // the replica's in-process cache
+	CacheL2   CacheLevel = "L2" // Redis
+	CacheMiss CacheLevel = "Miss"
+)
+
+// cacheLookups count response cache lookups by level since the process started; they are
+// kept even when metrics are disabled
+var cacheLookups = map[CacheLevel]*uint64{
+	CacheL1:   new(uint64),
+	CacheL2:   new(uint64),
+	CacheMiss: new(uint64),
+}
+
+// CacheKind groups cached responses that cost about the same to serve fresh
+type CacheKind string
+
+const (
+	CacheDetail CacheKind = "Detail" // a single task
+	CacheList   CacheKind = "List"   // task lists and other task reads
+)
+
+// CacheInvalidationSource is what invalidated cached responses
+type CacheInvalidationSource string
+
+const (
+	InvalidationWrite   CacheInvalidationSource = "Write"   // a write to the resources
+	InvalidationAdmin   CacheInvalidationSource = "Admin"   // an operator's flush
+	InvalidationService CacheInvalidationSource = "Service" // a change made outside a write request, e.g. by a job
+)
+
+// baselineWeight is the weight of each miss in the moving average of miss latency, so the
+// baseline follows the database's current speed
+const baselineWeight = 0.1
+
+// cacheSavings tracks what the response cache saves since the process started; it is kept
+// even when metrics are disabled
+var cacheSavings = struct {
+	sync.Mutex
+	// baselines are the moving averages of miss latency by kind, which hits are measured against
+	baselines       map[CacheKind]time.Duration
+	saved           time.Duration
+	bytesServed     uint64
+	invalidations   uint64
+	keysInvalidated uint64
+}{baselines: map[CacheKind]time.Duration{}}
+
+// CacheLookupStats reports where response cache lookups were answered and what the hits saved
+type CacheLookupStats struct {
+	L1Hits  uint64  `json:"l1_hits"`
+	L2Hits  uint64  `json:"l2_hits"`
+	Misses  uint64  `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+	// L1Share is the fraction of hits that needed no Redis round trip
+	L1Share float64 `json:"l1_share"`
+
+	// BaselineMs is the moving average latency of misses by kind; TimeSavedMs sums how much
+	// faster each hit was served than its kind's baseline
+	BaselineMs  map[CacheKind]float64 `json:"baseline_ms"`
+	TimeSavedMs float64               `json:"time_saved_ms"`
+	BytesServed uint64                `json:"bytes_served"`
+	// Invalidations counts invalidation passes, KeysInvalidated the entries they deleted
+	Invalidations   uint64 `json:"invalidations"`
+	KeysInvalidated uint64 `json:"keys_invalidated"`
+}
+
+// RecordCacheLookup records where a response cache lookup was answered
+func RecordCacheLookup(level CacheLevel) {
+	counter, ok := cacheLookups[level]
+	if !ok {
+		return
+	}
+	atomic.AddUint64(counter, 1)
+
+	if !IsEnabled() {
+		return
+	}
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("CacheLookups"),
+		Unit:       types.StandardUnitCount,
+		Value:      aws.Float64(1.0),
+		Dimensions: []types.Dimension{
+			{
+				Name:  aws.String("Level"),
+				Value: aws.String(string(level)),
+			},
+		},
+		Timestamp: aws.Time(time.Now()),
+	})
+}
+
+// RecordCacheMiss records how long a response missing from the cache took to serve fresh,
+// updating the baseline hits of its kind are measured against
+func RecordCacheMiss(kind CacheKind, latency time.Duration) {
+	cacheSavings.Lock()
+	defer cacheSavings.Unlock()
+	baseline, ok := cacheSavings.baselines[kind]
+	if !ok {
+		cacheSavings.baselines[kind] = latency
+		return
+	}
+	cacheSavings.baselines[kind] = baseline + time.Duration(baselineWeight*float64(latency-baseline))
+}
+
+// RecordCacheHit records a response served from the cache: its size and the time saved
+// against the baseline of its kind. Hits before the kind's first miss save nothing.
+func RecordCacheHit(kind CacheKind, latency time.Duration, bytes int) {
+	cacheSavings.Lock()
+	saved := cacheSavings.baselines[kind] - latency
+	if saved < 0 {
+		saved = 0
+	}
+	cacheSavings.saved += saved
+	cacheSavings.bytesServed += uint64(bytes)
+	cacheSavings.Unlock()
+
+	if !IsEnabled() {
+		return
+	}
+	dimensions := []types.Dimension{{Name: aws.String("Kind"), Value: aws.String(string(kind))}}
+	now := time.Now()
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("CacheTimeSaved"),
+		Unit:       types.StandardUnitMilliseconds,
+		Value:      aws.Float64(float64(saved) / float64(time.Millisecond)),
+		Dimensions: dimensions,
+		Timestamp:  aws.Time(now),
+	})
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("CacheBytesServed"),
+		Unit:       types.StandardUnitBytes,
+		Value:      aws.Float64(float64(bytes)),
+		Dimensions: dimensions,
+		Timestamp:  aws.Time(now),
+	})
+}
+
+// RecordCacheInvalidation records an invalidation pass and the number of entries it deleted
+func RecordCacheInvalidation(source CacheInvalidationSource, keys int) {
+	cacheSavings.Lock()
+	cacheSavings.invalidations++
+	cacheSavings.keysInvalidated += uint64(keys)
+	cacheSavings.Unlock()
+
+	if !IsEnabled() {
+		return
+	}
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("CacheKeysInvalidated"),
+		Unit:       types.StandardUnitCount,
+		Value:      aws.Float64(float64(keys)),
+		Dimensions: []types.Dimension{
+			{
+				Name:  aws.String("Source"),
+				Value: aws.String(string(source)),
+			},
+		},
+		Timestamp: aws.Time(time.Now()),
+	})
+}
+
+// CacheLookups returns the response cache lookups by level and their savings since the
+// process started
+func CacheLookups() CacheLookupStats {
+	stats := CacheLookupStats{
+		L1Hits: atomic.LoadUint64(cacheLookups[CacheL1]),
+		L2Hits: atomic.LoadUint64(cacheLookups[CacheL2]),
+		Misses: atomic.LoadUint64(cacheLookups[CacheMiss]),
+	}
+	hits := stats.L1Hits + stats.L2Hits
+	if total := hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(hits) / float64(total)
+	}
+	if hits > 0 {
+		stats.L1Share = float64(stats.L1Hits) / float64(hits)
+	}
+
+	cacheSavings.Lock()
+	defer cacheSavings.Unlock()
+	stats.BaselineMs = make(map[CacheKind]float64, len(cacheSavings.baselines))
+	for kind, baseline := range cacheSavings.baselines {
+		stats.BaselineMs[kind] = float64(baseline) / float64(time.Millisecond)
+	}
+	stats.TimeSavedMs = float64(cacheSavings.saved) / float64(time.Millisecond)
+	stats.BytesServed = cacheSavings.bytesServed
+	stats.Invalidations = cacheSavings.invalidations
+	stats.KeysInvalidated = cacheSavings.keysInvalidated
+	return stats
+}
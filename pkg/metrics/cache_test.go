@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheSavings(t *testing.T) {
+	// Counters are process-wide, so the test uses its own kind and compares deltas
+	kind := CacheKind("Test")
+	before := CacheLookups()
+
+	// No baseline yet, so the hit saves nothing
+	RecordCacheHit(kind, time.Millisecond, 100)
+	RecordCacheMiss(kind, 50*time.Millisecond)
+	RecordCacheMiss(kind, 150*time.Millisecond)
+	RecordCacheHit(kind, 10*time.Millisecond, 200)
+	// A hit slower than the baseline doesn't count as a loss
+	RecordCacheHit(kind, time.Second, 300)
+	RecordCacheInvalidation(InvalidationWrite, 3)
+	RecordCacheInvalidation(InvalidationAdmin, 0)
+
+	after := CacheLookups()
+	assert.InDelta(t, 60, after.BaselineMs[kind], 0.001)
+	assert.InDelta(t, 50, after.TimeSavedMs-before.TimeSavedMs, 0.001)
+	assert.Equal(t, uint64(600), after.BytesServed-before.BytesServed)
+	assert.Equal(t, uint64(2), after.Invalidations-before.Invalidations)
+	assert.Equal(t, uint64(3), after.KeysInvalidated-before.KeysInvalidated)
+}
+
+func TestCollectTokenCacheLookups(t *testing.T) {
+	RecordTokenCacheLookup(true)
+	RecordTokenCacheLookup(true)
+	RecordTokenCacheLookup(false)
+
+	values := map[string]float64{}
+	for _, datum := range collectTokenCacheLookups() {
+		values[*datum.Dimensions[0].Value] = *datum.Value
+	}
+	assert.Equal(t, map[string]float64{"Hit": 2, "Miss": 1}, values)
+	// The counters restart after each flush
+	assert.Empty(t, collectTokenCacheLookups())
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -19,8 +21,47 @@ var (
 	cwClient       *cloudwatch.Client
 	once           sync.Once
 	namespace      = "TaskAPI"
+
+	// circuit guards every CloudWatch call made by this process, so metrics and
+	// alarms back off together and degradation is logged once
+	circuit   = NewBreaker("CloudWatch telemetry", time.Second, 5*time.Minute)
+	publisher *Publisher
+
+	// sampler decides which requests are recorded by RecordRequest
+	sampler = NewSampler(1, nil)
+
+	// pendingDurations aggregates request durations until the next flush to CloudWatch;
+	// requestLatencies backs LatencyStats and is kept even when metrics are disabled
+	pendingDurations = newHistogramSet()
+	requestLatencies = newRollingHistograms(LatencyWindow)
+)
+
+// LatencyWindow is the period LatencyStats covers, one to two windows back
+const LatencyWindow = 5 * time.Minute
+
+// maxDatumValues is the most distinct values CloudWatch accepts in one datum
+const maxDatumValues = 150
+
+const (
+	bufferSize    = 1000
+	flushInterval = 10 * time.Second
 )
 
+// TelemetryDisabled, TelemetryOK and TelemetryDegraded are the states reported by Telemetry
+const (
+	TelemetryDisabled = "disabled"
+	TelemetryOK       = "ok"
+	TelemetryDegraded = "degraded"
+)
+
+// Status describes the health of metrics publishing
+type Status struct {
+	State     string `json:"state"`
+	Buffered  int    `json:"buffered"`
+	Dropped   uint64 `json:"dropped"`
+	LastError string `json:"last_error,omitempty"`
+}
+
 // Initialize sets up the metrics client based on environment configuration
 func Initialize() error {
 	var initErr error
@@ -32,6 +73,14 @@ func Initialize() error {
 			return
 		}
 
+		configured, err := samplerFromEnv()
+		if err != nil {
+			metricsEnabled = false
+			initErr = err
+			return
+		}
+		sampler = configured
+
 		// Load AWS configuration
 		cfg, err := config.LoadDefaultConfig(context.Background(),
 			config.WithRegion(os.Getenv("AWS_REGION")),
@@ -43,11 +92,18 @@ func Initialize() error {
 
 		// Initialize CloudWatch client
 		cwClient = cloudwatch.NewFromConfig(cfg)
-		
-		// Test the CloudWatch connection
+		publisher = NewPublisher(cwClient, namespace, bufferSize, flushInterval, circuit)
+		publisher.Collect(collectDurations)
+		publisher.Collect(collectGoldenSignals)
+		publisher.Collect(collectTokenCacheLookups)
+		go publisher.Run(context.Background())
+
+		// Test the CloudWatch connection; on failure metrics stay buffered and the
+		// publisher keeps retrying with backoff
 		_, err = cwClient.ListMetrics(context.Background(), &cloudwatch.ListMetricsInput{
 			Namespace: aws.String(namespace),
 		})
+		circuit.Record(err)
 		if err != nil {
 			initErr = fmt.Errorf("failed to connect to CloudWatch: %v", err)
 			return
@@ -58,42 +114,147 @@ func Initialize() error {
 	return initErr
 }
 
+// samplerFromEnv builds the request sampler from METRICS_SAMPLE_RATE and METRICS_ROUTE_SAMPLING
+func samplerFromEnv() (*Sampler, error) {
+	rate := 1.0
+	if value := os.Getenv("METRICS_SAMPLE_RATE"); value != "" {
+		parsed, err := ParseSampleRate(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid METRICS_SAMPLE_RATE: %v", err)
+		}
+		rate = parsed
+	}
+
+	rules, err := ParseRouteRules(os.Getenv("METRICS_ROUTE_SAMPLING"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid METRICS_ROUTE_SAMPLING: %v", err)
+	}
+
+	return NewSampler(rate, rules), nil
+}
+
 // IsEnabled returns whether metrics collection is enabled
 func IsEnabled() bool {
-	return metricsEnabled && cwClient != nil
+	return metricsEnabled && publisher != nil
 }
 
-// RecordRequestDuration records the duration of an HTTP request
+// Circuit returns the breaker shared by all CloudWatch calls
+func Circuit() *Breaker {
+	return circuit
+}
+
+// Telemetry reports whether metrics are being published or are degraded
+func Telemetry() Status {
+	if !IsEnabled() {
+		return Status{State: TelemetryDisabled}
+	}
+	return publisher.Status()
+}
+
+// RecordRequestDuration records the duration of an HTTP request in seconds. Durations are
+// aggregated per route and published as a histogram on every flush, so CloudWatch can
+// compute percentiles such as p95 and p99.
 func RecordRequestDuration(method, path string, duration float64) {
 	if !IsEnabled() {
 		return
 	}
 
-	_, err := cwClient.PutMetricData(context.Background(), &cloudwatch.PutMetricDataInput{
-		Namespace: aws.String(namespace),
-		MetricData: []types.MetricDatum{
-			{
+	pendingDurations.observe(routeKey{Method: method, Route: path}, duration*1000)
+}
+
+// collectDurations turns the durations recorded since the last flush into datums
+func collectDurations() []types.MetricDatum {
+	var datums []types.MetricDatum
+	now := time.Now()
+	for key, h := range pendingDurations.drain() {
+		values, counts := h.Values(maxDatumValues)
+		for i := range values {
+			// Histograms hold milliseconds; the metric has always been in seconds
+			seconds := make([]float64, len(values[i]))
+			for j, ms := range values[i] {
+				seconds[j] = ms / 1000
+			}
+
+			datums = append(datums, types.MetricDatum{
 				MetricName: aws.String("RequestDuration"),
 				Unit:       types.StandardUnitSeconds,
-				Value:      aws.Float64(duration),
+				Values:     seconds,
+				Counts:     counts[i],
 				Dimensions: []types.Dimension{
 					{
 						Name:  aws.String("Method"),
-						Value: aws.String(method),
+						Value: aws.String(key.Method),
 					},
 					{
 						Name:  aws.String("Path"),
-						Value: aws.String(path),
+						Value: aws.String(key.Route),
 					},
 				},
-				Timestamp: aws.Time(time.Now()),
-			},
-		},
-	})
+				Timestamp: aws.Time(now),
+			})
+		}
+	}
+	return datums
+}
 
-	if err != nil {
-		log.Printf("Error publishing duration metric to CloudWatch: %v", err)
+// RecordRequest records the duration and outcome of an HTTP request. path should be
+// the route template rather than the raw path, so requests for different IDs
+// aggregate together. Latency is always kept for LatencyStats; CloudWatch metrics
+// are subject to the configured sampling.
+func RecordRequest(method, path string, statusCode int, duration float64) {
+	requestLatencies.observe(routeKey{Method: method, Route: path}, duration*1000)
+
+	if !IsEnabled() {
+		return
+	}
+
+	weight, ok := sampler.Sample(path, statusCode)
+	if !ok {
+		return
+	}
+	RecordRequestDuration(method, path, duration)
+	recordAPICall(method, path, statusCode, weight)
+}
+
+// RouteLatency summarizes the latency of a route in milliseconds
+type RouteLatency struct {
+	Method string  `json:"method"`
+	Route  string  `json:"route"`
+	Count  uint64  `json:"count"`
+	MeanMs float64 `json:"mean_ms"`
+	P50Ms  float64 `json:"p50_ms"`
+	P95Ms  float64 `json:"p95_ms"`
+	P99Ms  float64 `json:"p99_ms"`
+	MaxMs  float64 `json:"max_ms"`
+}
+
+// LatencyStats returns request latency percentiles per route over the last
+// LatencyWindow to 2*LatencyWindow, busiest routes first
+func LatencyStats() []RouteLatency {
+	var stats []RouteLatency
+	for key, h := range requestLatencies.snapshot() {
+		stats = append(stats, RouteLatency{
+			Method: key.Method,
+			Route:  key.Route,
+			Count:  h.Count(),
+			MeanMs: h.Mean(),
+			P50Ms:  h.Percentile(50),
+			P95Ms:  h.Percentile(95),
+			P99Ms:  h.Percentile(99),
+			MaxMs:  h.Max(),
+		})
 	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		if stats[i].Route != stats[j].Route {
+			return stats[i].Route < stats[j].Route
+		}
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
 }
 
 // RecordAPICall records API call counts with status codes
@@ -102,35 +263,31 @@ func RecordAPICall(method, path string, statusCode int) {
 		return
 	}
 
-	_, err := cwClient.PutMetricData(context.Background(), &cloudwatch.PutMetricDataInput{
-		Namespace: aws.String(namespace),
-		MetricData: []types.MetricDatum{
+	recordAPICall(method, path, statusCode, 1)
+}
+
+// recordAPICall records a call counted weight times, to make up for sampled-out calls
+func recordAPICall(method, path string, statusCode int, weight float64) {
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("APICallCount"),
+		Unit:       types.StandardUnitCount,
+		Value:      aws.Float64(weight),
+		Dimensions: []types.Dimension{
 			{
-				MetricName: aws.String("APICallCount"),
-				Unit:       types.StandardUnitCount,
-				Value:      aws.Float64(1.0),
-				Dimensions: []types.Dimension{
-					{
-						Name:  aws.String("Method"),
-						Value: aws.String(method),
-					},
-					{
-						Name:  aws.String("Path"),
-						Value: aws.String(path),
-					},
-					{
-						Name:  aws.String("StatusCode"),
-						Value: aws.String(fmt.Sprintf("%d", statusCode)),
-					},
-				},
-				Timestamp: aws.Time(time.Now()),
+				Name:  aws.String("Method"),
+				Value: aws.String(method),
+			},
+			{
+				Name:  aws.String("Path"),
+				Value: aws.String(path),
+			},
+			{
+				Name:  aws.String("StatusCode"),
+				Value: aws.String(fmt.Sprintf("%d", statusCode)),
 			},
 		},
+		Timestamp: aws.Time(time.Now()),
 	})
-
-	if err != nil {
-		log.Printf("Error publishing API call metric to CloudWatch: %v", err)
-	}
 }
 
 // RecordCacheOperation records cache hits and misses
@@ -139,29 +296,211 @@ func RecordCacheOperation(operation string, success bool) {
 		return
 	}
 
-	_, err := cwClient.PutMetricData(context.Background(), &cloudwatch.PutMetricDataInput{
-		Namespace: aws.String(namespace),
-		MetricData: []types.MetricDatum{
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("CacheOperations"),
+		Unit:       types.StandardUnitCount,
+		Value:      aws.Float64(1.0),
+		Dimensions: []types.Dimension{
 			{
-				MetricName: aws.String("CacheOperations"),
-				Unit:       types.StandardUnitCount,
-				Value:      aws.Float64(1.0),
-				Dimensions: []types.Dimension{
-					{
-						Name:  aws.String("Operation"),
-						Value: aws.String(operation),
-					},
-					{
-						Name:  aws.String("Result"),
-						Value: aws.String(map[bool]string{true: "Success", false: "Failure"}[success]),
-					},
+				Name:  aws.String("Operation"),
+				Value: aws.String(operation),
+			},
+			{
+				Name:  aws.String("Result"),
+				Value: aws.String(map[bool]string{true: "Success", false: "Failure"}[success]),
+			},
+		},
+		Timestamp: aws.Time(time.Now()),
+	})
+}
+
+// tokenCacheLookups count validated-token cache hits and misses since the last flush. The
+// lookup runs on every authenticated request, so it is counted in memory and published once
+// per flush interval.
+var tokenCacheLookups = struct {
+	hits, misses uint64
+}{}
+
+// RecordTokenCacheLookup records validated-token cache hits and misses
+func RecordTokenCacheLookup(hit bool) {
+	if hit {
+		atomic.AddUint64(&tokenCacheLookups.hits, 1)
+	} else {
+		atomic.AddUint64(&tokenCacheLookups.misses, 1)
+	}
+}
+
+// collectTokenCacheLookups returns the token cache lookups counted since the last flush
+func collectTokenCacheLookups() []types.MetricDatum {
+	var datums []types.MetricDatum
+	now := time.Now()
+	for _, result := range []struct {
+		name  string
+		count *uint64
+	}{{"Hit", &tokenCacheLookups.hits}, {"Miss", &tokenCacheLookups.misses}} {
+		count := atomic.SwapUint64(result.count, 0)
+		if count == 0 {
+			continue
+		}
+		datums = append(datums, types.MetricDatum{
+			MetricName: aws.String("TokenCacheLookups"),
+			Unit:       types.StandardUnitCount,
+			Value:      aws.Float64(float64(count)),
+			Dimensions: []types.Dimension{
+				{
+					Name:  aws.String("Result"),
+					Value: aws.String(result.name),
 				},
-				Timestamp: aws.Time(time.Now()),
+			},
+			Timestamp: aws.Time(now),
+		})
+	}
+	return datums
+}
+
+// RecordSecurityEvent records a suspicious-activity alert so CloudWatch alarms can fire on it
+func RecordSecurityEvent(kind string) {
+	if !IsEnabled() {
+		return
+	}
+
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("SecurityEvents"),
+		Unit:       types.StandardUnitCount,
+		Value:      aws.Float64(1.0),
+		Dimensions: []types.Dimension{
+			{
+				Name:  aws.String("Kind"),
+				Value: aws.String(kind),
 			},
 		},
+		Timestamp: aws.Time(time.Now()),
 	})
+}
 
-	if err != nil {
-		log.Printf("Error publishing cache metric to CloudWatch: %v", err)
+// RecordOverdueTasks records how many open tasks are past their due date and how many
+// became overdue since the last check, so dashboards and alarms can track delinquency
+func RecordOverdueTasks(overdue, newlyOverdue int) {
+	if !IsEnabled() {
+		return
 	}
-} 
\ No newline at end of file
+
+	now := aws.Time(time.Now())
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("OverdueTasks"),
+		Unit:       types.StandardUnitCount,
+		Value:      aws.Float64(float64(overdue)),
+		Timestamp:  now,
+	})
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("TasksBecameOverdue"),
+		Unit:       types.StandardUnitCount,
+		Value:      aws.Float64(float64(newlyOverdue)),
+		Timestamp:  now,
+	})
+}
+
+// RecordSLABreaches records how many tasks newly missed their kind SLA target ("respond"
+// or "resolve"), so alarms can fire on breaches
+func RecordSLABreaches(kind string, breaches int) {
+	if !IsEnabled() {
+		return
+	}
+
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("SLABreaches"),
+		Unit:       types.StandardUnitCount,
+		Value:      aws.Float64(float64(breaches)),
+		Dimensions: []types.Dimension{
+			{
+				Name:  aws.String("Target"),
+				Value: aws.String(kind),
+			},
+		},
+		Timestamp: aws.Time(time.Now()),
+	})
+}
+
+// RecordOrgKPIs records an organization's business metrics over a reporting window: the
+// tasks created and completed in it, the tasks overdue at its end and, when tasks were
+// completed, their average cycle time. org is "none" for tasks without an organization.
+func RecordOrgKPIs(org string, created, completed, overdue int, avgCycleTime time.Duration) {
+	if !IsEnabled() {
+		return
+	}
+
+	now := aws.Time(time.Now())
+	dimensions := []types.Dimension{
+		{
+			Name:  aws.String("Org"),
+			Value: aws.String(org),
+		},
+	}
+	counts := []struct {
+		name  string
+		value int
+	}{
+		{"TasksCreated", created},
+		{"TasksCompleted", completed},
+		{"OverdueTasks", overdue},
+	}
+	for _, count := range counts {
+		publisher.Publish(types.MetricDatum{
+			MetricName: aws.String(count.name),
+			Unit:       types.StandardUnitCount,
+			Value:      aws.Float64(float64(count.value)),
+			Dimensions: dimensions,
+			Timestamp:  now,
+		})
+	}
+	if completed > 0 {
+		publisher.Publish(types.MetricDatum{
+			MetricName: aws.String("CycleTime"),
+			Unit:       types.StandardUnitSeconds,
+			Value:      aws.Float64(avgCycleTime.Seconds()),
+			Dimensions: dimensions,
+			Timestamp:  now,
+		})
+	}
+}
+
+// RecordQueueDepth records how many jobs are waiting in a background job queue, so
+// workers can be scaled on it
+func RecordQueueDepth(queue string, depth int) {
+	if !IsEnabled() {
+		return
+	}
+
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("QueueDepth"),
+		Unit:       types.StandardUnitCount,
+		Value:      aws.Float64(float64(depth)),
+		Dimensions: []types.Dimension{
+			{
+				Name:  aws.String("Queue"),
+				Value: aws.String(queue),
+			},
+		},
+		Timestamp: aws.Time(time.Now()),
+	})
+}
+
+// RecordWorkerRestart records a background loop that stopped and was restarted
+func RecordWorkerRestart(loop string) {
+	if !IsEnabled() {
+		return
+	}
+
+	publisher.Publish(types.MetricDatum{
+		MetricName: aws.String("WorkerRestarts"),
+		Unit:       types.StandardUnitCount,
+		Value:      aws.Float64(1.0),
+		Dimensions: []types.Dimension{
+			{
+				Name:  aws.String("Loop"),
+				Value: aws.String(loop),
+			},
+		},
+		Timestamp: aws.Time(time.Now()),
+	})
+}
@@ -0,0 +1,336 @@
+package metrics
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+var (
+	// inFlight is the number of requests being served; peakInFlight is the highest it
+	// has been since the last flush
+	inFlight     int64
+	peakInFlight int64
+
+	// pendingOutcomes and pendingQueueWaits aggregate until the next flush to CloudWatch;
+	// requestOutcomes and queueWaits back Signals and are kept even when metrics are disabled
+	pendingOutcomes   = newOutcomeSet()
+	pendingQueueWaits = newHistogramSet()
+	requestOutcomes   = newRollingOutcomes(LatencyWindow)
+	queueWaits        = newRollingHistograms(LatencyWindow)
+)
+
+// maxErrorRoutes is the number of routes listed in Signals, highest error rate first
+const maxErrorRoutes = 5
+
+// Outcomes counts the requests served on a route and how many failed with a server error
+type Outcomes struct {
+	Requests uint64
+	Errors   uint64
+}
+
+// ErrorRate returns the fraction of requests that failed, or 0 without requests
+func (o Outcomes) ErrorRate() float64 {
+	if o.Requests == 0 {
+		return 0
+	}
+	return float64(o.Errors) / float64(o.Requests)
+}
+
+// StartRequest marks a request as in flight. The returned function must be called
+// once the request is done with its route template and status code.
+func StartRequest() func(method, route string, statusCode int) {
+	current := atomic.AddInt64(&inFlight, 1)
+	for {
+		peak := atomic.LoadInt64(&peakInFlight)
+		if current <= peak || atomic.CompareAndSwapInt64(&peakInFlight, peak, current) {
+			break
+		}
+	}
+
+	return func(method, route string, statusCode int) {
+		atomic.AddInt64(&inFlight, -1)
+
+		key := routeKey{Method: method, Route: route}
+		failed := statusCode >= http.StatusInternalServerError
+		requestOutcomes.observe(key, failed)
+		if IsEnabled() {
+			pendingOutcomes.observe(key, failed)
+		}
+	}
+}
+
+// RecordQueueWait records how long a request waited in front of the API, e.g. in a
+// load balancer queue, before it was handled
+func RecordQueueWait(wait time.Duration) {
+	ms := float64(wait) / float64(time.Millisecond)
+	queueWaits.observe(routeKey{}, ms)
+	if IsEnabled() {
+		pendingQueueWaits.observe(routeKey{}, ms)
+	}
+}
+
+// collectGoldenSignals turns the saturation and error signals recorded since the last
+// flush into datums
+func collectGoldenSignals() []types.MetricDatum {
+	now := time.Now()
+
+	// The peak starts over from the requests still in flight
+	peak := atomic.SwapInt64(&peakInFlight, atomic.LoadInt64(&inFlight))
+	datums := []types.MetricDatum{{
+		MetricName: aws.String("RequestsInFlight"),
+		Unit:       types.StandardUnitCount,
+		Value:      aws.Float64(float64(peak)),
+		Timestamp:  aws.Time(now),
+	}}
+
+	for _, h := range pendingQueueWaits.drain() {
+		values, counts := h.Values(maxDatumValues)
+		for i := range values {
+			seconds := make([]float64, len(values[i]))
+			for j, ms := range values[i] {
+				seconds[j] = ms / 1000
+			}
+
+			datums = append(datums, types.MetricDatum{
+				MetricName: aws.String("QueueWaitTime"),
+				Unit:       types.StandardUnitSeconds,
+				Values:     seconds,
+				Counts:     counts[i],
+				Timestamp:  aws.Time(now),
+			})
+		}
+	}
+
+	for key, outcomes := range pendingOutcomes.drain() {
+		// Routes with request metrics turned off don't report errors either
+		if _, ok := sampler.Sample(key.Route, http.StatusInternalServerError); !ok {
+			continue
+		}
+
+		datums = append(datums, types.MetricDatum{
+			MetricName: aws.String("ErrorRate"),
+			Unit:       types.StandardUnitPercent,
+			Value:      aws.Float64(outcomes.ErrorRate() * 100),
+			Dimensions: []types.Dimension{
+				{
+					Name:  aws.String("Method"),
+					Value: aws.String(key.Method),
+				},
+				{
+					Name:  aws.String("Path"),
+					Value: aws.String(key.Route),
+				},
+			},
+			Timestamp: aws.Time(now),
+		})
+	}
+	return datums
+}
+
+// Signals summarizes the four golden signals over the last LatencyWindow to
+// 2*LatencyWindow
+type Signals struct {
+	Window     string           `json:"window"`
+	Latency    LatencySignal    `json:"latency"`
+	Traffic    TrafficSignal    `json:"traffic"`
+	Errors     ErrorSignal      `json:"errors"`
+	Saturation SaturationSignal `json:"saturation"`
+}
+
+// LatencySignal is the request latency across all routes in milliseconds
+type LatencySignal struct {
+	P50Ms float64 `json:"p50_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	P99Ms float64 `json:"p99_ms"`
+}
+
+// TrafficSignal is the number of requests served
+type TrafficSignal struct {
+	Requests          uint64  `json:"requests"`
+	RequestsPerSecond float64 `json:"requests_per_second"`
+}
+
+// ErrorSignal is the fraction of requests failing with a server error, overall and
+// for the routes failing most
+type ErrorSignal struct {
+	Rate   float64      `json:"rate"`
+	Routes []RouteError `json:"routes,omitempty"`
+}
+
+// RouteError is the server error rate of a route
+type RouteError struct {
+	Method   string  `json:"method"`
+	Route    string  `json:"route"`
+	Requests uint64  `json:"requests"`
+	Rate     float64 `json:"rate"`
+}
+
+// SaturationSignal is how loaded the API is: requests being served right now and how
+// long requests waited before reaching it
+type SaturationSignal struct {
+	InFlight       int64   `json:"in_flight"`
+	QueueWaitP50Ms float64 `json:"queue_wait_p50_ms"`
+	QueueWaitP95Ms float64 `json:"queue_wait_p95_ms"`
+}
+
+// GoldenSignals returns the current latency, traffic, errors and saturation of the API
+func GoldenSignals() Signals {
+	signals := Signals{
+		Window:     LatencyWindow.String(),
+		Saturation: SaturationSignal{InFlight: atomic.LoadInt64(&inFlight)},
+	}
+
+	latency := NewHistogram()
+	for _, h := range requestLatencies.snapshot() {
+		latency.Merge(h)
+	}
+	signals.Latency = LatencySignal{
+		P50Ms: latency.Percentile(50),
+		P95Ms: latency.Percentile(95),
+		P99Ms: latency.Percentile(99),
+	}
+
+	outcomes, covered := requestOutcomes.snapshot()
+	var total Outcomes
+	for key, o := range outcomes {
+		total.Requests += o.Requests
+		total.Errors += o.Errors
+		if o.Errors > 0 {
+			signals.Errors.Routes = append(signals.Errors.Routes, RouteError{
+				Method:   key.Method,
+				Route:    key.Route,
+				Requests: o.Requests,
+				Rate:     o.ErrorRate(),
+			})
+		}
+	}
+	signals.Traffic.Requests = total.Requests
+	if covered > 0 {
+		signals.Traffic.RequestsPerSecond = float64(total.Requests) / covered.Seconds()
+	}
+	signals.Errors.Rate = total.ErrorRate()
+
+	routes := signals.Errors.Routes
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Rate != routes[j].Rate {
+			return routes[i].Rate > routes[j].Rate
+		}
+		return routes[i].Requests > routes[j].Requests
+	})
+	if len(routes) > maxErrorRoutes {
+		signals.Errors.Routes = routes[:maxErrorRoutes]
+	}
+
+	if wait, ok := queueWaits.snapshot()[routeKey{}]; ok {
+		signals.Saturation.QueueWaitP50Ms = wait.Percentile(50)
+		signals.Saturation.QueueWaitP95Ms = wait.Percentile(95)
+	}
+	return signals
+}
+
+// outcomeSet holds the outcomes of every route
+type outcomeSet struct {
+	mu       sync.Mutex
+	outcomes map[routeKey]Outcomes
+}
+
+func newOutcomeSet() *outcomeSet {
+	return &outcomeSet{outcomes: make(map[routeKey]Outcomes)}
+}
+
+func (s *outcomeSet) observe(key routeKey, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := s.outcomes[key]
+	o.Requests++
+	if failed {
+		o.Errors++
+	}
+	s.outcomes[key] = o
+}
+
+// drain returns the outcomes and starts over
+func (s *outcomeSet) drain() map[routeKey]Outcomes {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	drained := s.outcomes
+	s.outcomes = make(map[routeKey]Outcomes)
+	return drained
+}
+
+// addTo adds every route's outcomes to dst
+func (s *outcomeSet) addTo(dst map[routeKey]Outcomes) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, o := range s.outcomes {
+		sum := dst[key]
+		sum.Requests += o.Requests
+		sum.Errors += o.Errors
+		dst[key] = sum
+	}
+}
+
+// rollingOutcomes keeps per-route outcomes over the last one to two periods, rotating
+// the same way as rollingHistograms
+type rollingOutcomes struct {
+	mu        sync.Mutex
+	period    time.Duration
+	now       func() time.Time
+	startedAt time.Time // start of the previous period, or the current one when there is none
+	rotatedAt time.Time
+	current   *outcomeSet
+	previous  *outcomeSet
+}
+
+func newRollingOutcomes(period time.Duration) *rollingOutcomes {
+	now := time.Now()
+	return &rollingOutcomes{
+		period:    period,
+		now:       time.Now,
+		startedAt: now,
+		rotatedAt: now,
+		current:   newOutcomeSet(),
+		previous:  newOutcomeSet(),
+	}
+}
+
+func (r *rollingOutcomes) sets() (*outcomeSet, *outcomeSet, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if elapsed := now.Sub(r.rotatedAt); elapsed >= r.period {
+		r.previous = r.current
+		r.startedAt = r.rotatedAt
+		if elapsed >= 2*r.period {
+			r.previous = newOutcomeSet()
+			r.startedAt = now
+		}
+		r.current = newOutcomeSet()
+		r.rotatedAt = now
+	}
+	return r.current, r.previous, now.Sub(r.startedAt)
+}
+
+func (r *rollingOutcomes) observe(key routeKey, failed bool) {
+	current, _, _ := r.sets()
+	current.observe(key, failed)
+}
+
+// snapshot returns the outcomes of every route and the time they were counted over
+func (r *rollingOutcomes) snapshot() (map[routeKey]Outcomes, time.Duration) {
+	current, previous, covered := r.sets()
+	merged := make(map[routeKey]Outcomes)
+	previous.addTo(merged)
+	current.addTo(merged)
+	return merged, covered
+}
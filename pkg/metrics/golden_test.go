@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollingOutcomes(t *testing.T) {
+	now := time.Unix(0, 0)
+	rolling := newRollingOutcomes(time.Minute)
+	rolling.now = func() time.Time { return now }
+	rolling.startedAt = now
+	rolling.rotatedAt = now
+	key := routeKey{Method: "GET", Route: "/api/v1/tasks/{id}"}
+
+	rolling.observe(key, false)
+	rolling.observe(key, true)
+	now = now.Add(90 * time.Second)
+	rolling.observe(key, false)
+	rolling.observe(key, false)
+
+	outcomes, covered := rolling.snapshot()
+	assert.Equal(t, Outcomes{Requests: 4, Errors: 1}, outcomes[key])
+	assert.Equal(t, 90*time.Second, covered)
+	assert.InEpsilon(t, 0.25, outcomes[key].ErrorRate(), 1e-9)
+
+	// Both periods have passed
+	now = now.Add(2 * time.Minute)
+	outcomes, covered = rolling.snapshot()
+	assert.Empty(t, outcomes)
+	assert.Equal(t, time.Duration(0), covered)
+}
+
+func TestOutcomes_ErrorRateWithoutRequests(t *testing.T) {
+	assert.Equal(t, 0.0, Outcomes{}.ErrorRate())
+}
+
+func TestStartRequest(t *testing.T) {
+	before := GoldenSignals().Saturation.InFlight
+
+	done := StartRequest()
+	assert.Equal(t, before+1, GoldenSignals().Saturation.InFlight)
+
+	done("POST", "/api/v1/golden-test", 503)
+	signals := GoldenSignals()
+	assert.Equal(t, before, signals.Saturation.InFlight)
+
+	var found bool
+	for _, route := range signals.Errors.Routes {
+		if route.Route == "/api/v1/golden-test" {
+			found = true
+			assert.Equal(t, uint64(1), route.Requests)
+			assert.Equal(t, 1.0, route.Rate)
+		}
+	}
+	assert.True(t, found)
+}
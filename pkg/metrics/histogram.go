@@ -0,0 +1,239 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// histogramGrowth is the ratio between consecutive bucket bounds, so percentiles are
+// estimated within 5% without keeping every observation
+const histogramGrowth = 1.05
+
+var logHistogramGrowth = math.Log(histogramGrowth)
+
+// minHistogramValue is the smallest distinguishable value; anything lower shares its bucket
+const minHistogramValue = 0.01
+
+// Histogram counts observations in logarithmic buckets
+type Histogram struct {
+	counts map[int]uint64
+	total  uint64
+	sum    float64
+	max    float64
+}
+
+// NewHistogram creates an empty histogram
+func NewHistogram() *Histogram {
+	return &Histogram{counts: make(map[int]uint64)}
+}
+
+func bucketIndex(value float64) int {
+	if value < minHistogramValue {
+		value = minHistogramValue
+	}
+	return int(math.Ceil(math.Log(value) / logHistogramGrowth))
+}
+
+// bucketValue returns the upper bound of a bucket
+func bucketValue(index int) float64 {
+	return math.Pow(histogramGrowth, float64(index))
+}
+
+// Observe adds a value
+func (h *Histogram) Observe(value float64) {
+	h.counts[bucketIndex(value)]++
+	h.total++
+	h.sum += value
+	if value > h.max {
+		h.max = value
+	}
+}
+
+// Merge adds all observations of other
+func (h *Histogram) Merge(other *Histogram) {
+	for index, count := range other.counts {
+		h.counts[index] += count
+	}
+	h.total += other.total
+	h.sum += other.sum
+	if other.max > h.max {
+		h.max = other.max
+	}
+}
+
+// Count returns the number of observations
+func (h *Histogram) Count() uint64 {
+	return h.total
+}
+
+// Mean returns the average observation
+func (h *Histogram) Mean() float64 {
+	if h.total == 0 {
+		return 0
+	}
+	return h.sum / float64(h.total)
+}
+
+// Max returns the largest observation
+func (h *Histogram) Max() float64 {
+	return h.max
+}
+
+// Percentile estimates the value below which p percent of observations fall
+func (h *Histogram) Percentile(p float64) float64 {
+	if h.total == 0 {
+		return 0
+	}
+
+	indexes := h.sortedIndexes()
+	rank := uint64(math.Ceil(p / 100 * float64(h.total)))
+	if rank == 0 {
+		rank = 1
+	}
+
+	var seen uint64
+	for _, index := range indexes {
+		seen += h.counts[index]
+		if seen >= rank {
+			// The top bucket's bound may overshoot the largest value actually seen
+			return math.Min(bucketValue(index), h.max)
+		}
+	}
+	return h.max
+}
+
+// Values returns the histogram as parallel value and count slices, in CloudWatch's
+// Values/Counts form, split into chunks of at most maxValues distinct values
+func (h *Histogram) Values(maxValues int) ([][]float64, [][]float64) {
+	var values, counts [][]float64
+	indexes := h.sortedIndexes()
+	for start := 0; start < len(indexes); start += maxValues {
+		end := start + maxValues
+		if end > len(indexes) {
+			end = len(indexes)
+		}
+
+		chunkValues := make([]float64, 0, end-start)
+		chunkCounts := make([]float64, 0, end-start)
+		for _, index := range indexes[start:end] {
+			chunkValues = append(chunkValues, math.Min(bucketValue(index), h.max))
+			chunkCounts = append(chunkCounts, float64(h.counts[index]))
+		}
+		values = append(values, chunkValues)
+		counts = append(counts, chunkCounts)
+	}
+	return values, counts
+}
+
+func (h *Histogram) sortedIndexes() []int {
+	indexes := make([]int, 0, len(h.counts))
+	for index := range h.counts {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// routeKey identifies the route a latency was observed on
+type routeKey struct {
+	Method string
+	Route  string
+}
+
+// histogramSet holds one histogram per route
+type histogramSet struct {
+	mu         sync.Mutex
+	histograms map[routeKey]*Histogram
+}
+
+func newHistogramSet() *histogramSet {
+	return &histogramSet{histograms: make(map[routeKey]*Histogram)}
+}
+
+func (s *histogramSet) observe(key routeKey, value float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, ok := s.histograms[key]
+	if !ok {
+		h = NewHistogram()
+		s.histograms[key] = h
+	}
+	h.Observe(value)
+}
+
+// drain returns the histograms and starts over
+func (s *histogramSet) drain() map[routeKey]*Histogram {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	drained := s.histograms
+	s.histograms = make(map[routeKey]*Histogram)
+	return drained
+}
+
+// mergeInto adds a copy of every histogram to dst
+func (s *histogramSet) mergeInto(dst map[routeKey]*Histogram) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, h := range s.histograms {
+		merged, ok := dst[key]
+		if !ok {
+			merged = NewHistogram()
+			dst[key] = merged
+		}
+		merged.Merge(h)
+	}
+}
+
+// rollingHistograms keeps per-route histograms over the last one to two periods by
+// rotating between a current and a previous set
+type rollingHistograms struct {
+	mu        sync.Mutex
+	period    time.Duration
+	now       func() time.Time
+	rotatedAt time.Time
+	current   *histogramSet
+	previous  *histogramSet
+}
+
+func newRollingHistograms(period time.Duration) *rollingHistograms {
+	return &rollingHistograms{
+		period:    period,
+		now:       time.Now,
+		rotatedAt: time.Now(),
+		current:   newHistogramSet(),
+		previous:  newHistogramSet(),
+	}
+}
+
+func (r *rollingHistograms) sets() (*histogramSet, *histogramSet) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if elapsed := r.now().Sub(r.rotatedAt); elapsed >= r.period {
+		r.previous = r.current
+		if elapsed >= 2*r.period {
+			r.previous = newHistogramSet()
+		}
+		r.current = newHistogramSet()
+		r.rotatedAt = r.now()
+	}
+	return r.current, r.previous
+}
+
+func (r *rollingHistograms) observe(key routeKey, value float64) {
+	current, _ := r.sets()
+	current.observe(key, value)
+}
+
+func (r *rollingHistograms) snapshot() map[routeKey]*Histogram {
+	current, previous := r.sets()
+	merged := make(map[routeKey]*Histogram)
+	previous.mergeInto(merged)
+	current.mergeInto(merged)
+	return merged
+}
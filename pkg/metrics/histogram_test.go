@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram_Percentile(t *testing.T) {
+	h := NewHistogram()
+	// 1..1000ms, uniformly
+	for i := 1; i <= 1000; i++ {
+		h.Observe(float64(i))
+	}
+
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{50, 500},
+		{95, 950},
+		{99, 990},
+		{100, 1000},
+	}
+
+	for _, tt := range tests {
+		got := h.Percentile(tt.p)
+		assert.InEpsilon(t, tt.want, got, histogramGrowth-1)
+	}
+
+	assert.Equal(t, uint64(1000), h.Count())
+	assert.InDelta(t, 500.5, h.Mean(), 1e-9)
+	assert.Equal(t, 1000.0, h.Max())
+}
+
+func TestHistogram_Empty(t *testing.T) {
+	h := NewHistogram()
+	assert.Equal(t, 0.0, h.Percentile(99))
+	assert.Equal(t, 0.0, h.Mean())
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	fast, slow := NewHistogram(), NewHistogram()
+	for i := 0; i < 90; i++ {
+		fast.Observe(10)
+	}
+	for i := 0; i < 10; i++ {
+		slow.Observe(2000)
+	}
+
+	fast.Merge(slow)
+	assert.Equal(t, uint64(100), fast.Count())
+	assert.InEpsilon(t, 10, fast.Percentile(90), histogramGrowth-1)
+	assert.InEpsilon(t, 2000, fast.Percentile(95), histogramGrowth-1)
+}
+
+func TestHistogram_Values(t *testing.T) {
+	h := NewHistogram()
+	for i := 1; i <= 300; i++ {
+		h.Observe(float64(i * i))
+	}
+
+	values, counts := h.Values(50)
+	var total float64
+	for i := range values {
+		assert.LessOrEqual(t, len(values[i]), 50)
+		assert.Equal(t, len(values[i]), len(counts[i]))
+		for _, c := range counts[i] {
+			total += c
+		}
+	}
+	assert.Greater(t, len(values), 1)
+	assert.Equal(t, 300.0, total)
+}
+
+func TestRollingHistograms(t *testing.T) {
+	now := time.Unix(0, 0)
+	rolling := newRollingHistograms(time.Minute)
+	rolling.now = func() time.Time { return now }
+	rolling.rotatedAt = now
+	key := routeKey{Method: "GET", Route: "/api/v1/tasks/{id}"}
+
+	rolling.observe(key, 10)
+
+	// The previous window is still reported after one rotation
+	now = now.Add(time.Minute)
+	rolling.observe(key, 20)
+	assert.Equal(t, uint64(2), rolling.snapshot()[key].Count())
+
+	// and dropped after the next
+	now = now.Add(time.Minute)
+	rolling.observe(key, 30)
+	assert.Equal(t, uint64(2), rolling.snapshot()[key].Count())
+
+	// Idle for longer than two windows reports nothing
+	now = now.Add(3 * time.Minute)
+	assert.Empty(t, rolling.snapshot())
+}
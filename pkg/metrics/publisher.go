@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// maxBatchSize is the number of datums sent per PutMetricData call
+const maxBatchSize = 20
+
+// putMetricDataAPI is the subset of the CloudWatch client used to publish metrics
+type putMetricDataAPI interface {
+	PutMetricData(ctx context.Context, params *cloudwatch.PutMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.PutMetricDataOutput, error)
+}
+
+// Publisher sends metrics to CloudWatch in the background so recording a metric never
+// blocks a request. Datums are buffered locally; when the buffer is full, new datums are
+// dropped. While CloudWatch is unreachable the breaker holds off sending, so the buffer
+// keeps the most recent backlog instead of every request paying for a failing call.
+type Publisher struct {
+	client        putMetricDataAPI
+	namespace     string
+	breaker       *Breaker
+	queue         chan types.MetricDatum
+	flushInterval time.Duration
+	collectors    []func() []types.MetricDatum
+	dropped       uint64
+}
+
+// NewPublisher creates a publisher buffering up to bufferSize datums
+func NewPublisher(client putMetricDataAPI, namespace string, bufferSize int, flushInterval time.Duration, breaker *Breaker) *Publisher {
+	return &Publisher{
+		client:        client,
+		namespace:     namespace,
+		breaker:       breaker,
+		queue:         make(chan types.MetricDatum, bufferSize),
+		flushInterval: flushInterval,
+	}
+}
+
+// Publish queues a datum without blocking, dropping it if the buffer is full
+func (p *Publisher) Publish(datum types.MetricDatum) {
+	select {
+	case p.queue <- datum:
+	default:
+		atomic.AddUint64(&p.dropped, 1)
+	}
+}
+
+// Collect registers fn to be called before every flush, for metrics that are aggregated
+// locally and published once per interval. It must be called before Run.
+func (p *Publisher) Collect(fn func() []types.MetricDatum) {
+	p.collectors = append(p.collectors, fn)
+}
+
+// Run flushes the buffer every flush interval until ctx is done
+func (p *Publisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, collect := range p.collectors {
+				for _, datum := range collect() {
+					p.Publish(datum)
+				}
+			}
+			p.Flush(ctx)
+		}
+	}
+}
+
+// Flush sends buffered datums in batches, stopping at the first failure
+func (p *Publisher) Flush(ctx context.Context) {
+	for len(p.queue) > 0 {
+		if !p.breaker.Allow() {
+			return
+		}
+
+		batch := p.takeBatch()
+		callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		_, err := p.client.PutMetricData(callCtx, &cloudwatch.PutMetricDataInput{
+			Namespace:  aws.String(p.namespace),
+			MetricData: batch,
+		})
+		cancel()
+
+		p.breaker.Record(err)
+		if err != nil {
+			atomic.AddUint64(&p.dropped, uint64(len(batch)))
+			return
+		}
+	}
+}
+
+func (p *Publisher) takeBatch() []types.MetricDatum {
+	batch := make([]types.MetricDatum, 0, maxBatchSize)
+	for len(batch) < maxBatchSize {
+		select {
+		case datum := <-p.queue:
+			batch = append(batch, datum)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// Status reports the publisher's health
+func (p *Publisher) Status() Status {
+	status := Status{
+		State:    TelemetryOK,
+		Buffered: len(p.queue),
+		Dropped:  atomic.LoadUint64(&p.dropped),
+	}
+	if degraded, err := p.breaker.Degraded(); degraded {
+		status.State = TelemetryDegraded
+		if err != nil {
+			status.LastError = err.Error()
+		}
+	}
+	return status
+}
@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPutMetricDataAPI is a mock implementation of putMetricDataAPI
+type MockPutMetricDataAPI struct {
+	mock.Mock
+}
+
+func (m *MockPutMetricDataAPI) PutMetricData(ctx context.Context, params *cloudwatch.PutMetricDataInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.PutMetricDataOutput, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*cloudwatch.PutMetricDataOutput), args.Error(1)
+}
+
+func datum(name string) types.MetricDatum {
+	return types.MetricDatum{MetricName: aws.String(name), Value: aws.Float64(1)}
+}
+
+func TestPublisher_DropsOnOverflow(t *testing.T) {
+	client := &MockPutMetricDataAPI{}
+	p := NewPublisher(client, "Test", 2, time.Minute, NewBreaker("test", time.Second, time.Minute))
+
+	p.Publish(datum("a"))
+	p.Publish(datum("b"))
+	p.Publish(datum("c"))
+
+	status := p.Status()
+	assert.Equal(t, TelemetryOK, status.State)
+	assert.Equal(t, 2, status.Buffered)
+	assert.Equal(t, uint64(1), status.Dropped)
+	client.AssertNotCalled(t, "PutMetricData", mock.Anything, mock.Anything)
+}
+
+func TestPublisher_FlushBatches(t *testing.T) {
+	client := &MockPutMetricDataAPI{}
+	client.On("PutMetricData", mock.Anything, mock.MatchedBy(func(input *cloudwatch.PutMetricDataInput) bool {
+		return *input.Namespace == "Test" && len(input.MetricData) <= maxBatchSize
+	})).Return(&cloudwatch.PutMetricDataOutput{}, nil).Times(2)
+
+	p := NewPublisher(client, "Test", 100, time.Minute, NewBreaker("test", time.Second, time.Minute))
+	for i := 0; i < maxBatchSize+5; i++ {
+		p.Publish(datum("a"))
+	}
+
+	p.Flush(context.Background())
+
+	assert.Equal(t, 0, p.Status().Buffered)
+	client.AssertExpectations(t)
+}
+
+func TestPublisher_BacksOffWhenUnreachable(t *testing.T) {
+	client := &MockPutMetricDataAPI{}
+	client.On("PutMetricData", mock.Anything, mock.Anything).
+		Return(&cloudwatch.PutMetricDataOutput{}, errors.New("no route to host")).Once()
+
+	p := NewPublisher(client, "Test", 100, time.Minute, NewBreaker("test", time.Hour, time.Hour))
+	for i := 0; i < maxBatchSize+5; i++ {
+		p.Publish(datum("a"))
+	}
+
+	// The failed batch is dropped and the rest stays buffered
+	p.Flush(context.Background())
+	// While the circuit is open nothing is sent
+	p.Flush(context.Background())
+
+	status := p.Status()
+	assert.Equal(t, TelemetryDegraded, status.State)
+	assert.Equal(t, "no route to host", status.LastError)
+	assert.Equal(t, 5, status.Buffered)
+	assert.Equal(t, uint64(maxBatchSize), status.Dropped)
+	client.AssertNumberOfCalls(t, "PutMetricData", 1)
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// RouteRule configures request metrics for paths matching Pattern. In patterns,
+// "{name}" matches a single path segment and a trailing "*" matches any remainder.
+type RouteRule struct {
+	Pattern  string
+	Rate     float64 // fraction of successful requests recorded; errors are always recorded
+	Disabled bool    // record nothing for the route, not even errors
+}
+
+// Matches reports whether the rule applies to path
+func (r RouteRule) Matches(path string) bool {
+	patternParts := strings.Split(strings.Trim(r.Pattern, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, part := range patternParts {
+		if part == "*" && i == len(patternParts)-1 {
+			return true
+		}
+		if i >= len(pathParts) {
+			return false
+		}
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			continue
+		}
+		if part != pathParts[i] {
+			return false
+		}
+	}
+	return len(patternParts) == len(pathParts)
+}
+
+// Sampler decides which requests have their metrics recorded
+type Sampler struct {
+	defaultRate float64
+	rules       []RouteRule
+	random      func() float64
+}
+
+// NewSampler creates a sampler recording defaultRate of successful requests on routes
+// without a rule. The first matching rule wins.
+func NewSampler(defaultRate float64, rules []RouteRule) *Sampler {
+	return &Sampler{defaultRate: defaultRate, rules: rules, random: rand.Float64}
+}
+
+// Sample reports whether to record a request and the weight to count it with, so that
+// sampled counts still add up to an estimate of the real traffic. Server errors are
+// always recorded with weight 1 so error-rate alarms stay accurate.
+func (s *Sampler) Sample(path string, statusCode int) (float64, bool) {
+	rate := s.defaultRate
+	for _, rule := range s.rules {
+		if rule.Matches(path) {
+			if rule.Disabled {
+				return 0, false
+			}
+			rate = rule.Rate
+			break
+		}
+	}
+
+	if statusCode >= 500 || rate >= 1 {
+		return 1, true
+	}
+	if rate <= 0 || s.random() >= rate {
+		return 0, false
+	}
+	return 1 / rate, true
+}
+
+// ParseSampleRate parses a sampling rate between 0 and 1
+func ParseSampleRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("sample rate %v must be between 0 and 1", rate)
+	}
+	return rate, nil
+}
+
+// ParseRouteRules parses a comma-separated list of "pattern=rate" pairs, where rate
+// is a sampling rate or "off" to disable metrics for the route
+func ParseRouteRules(value string) ([]RouteRule, error) {
+	var rules []RouteRule
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], "/") {
+			return nil, fmt.Errorf("invalid route rule %q, expected /path=rate or /path=off", entry)
+		}
+
+		rule := RouteRule{Pattern: strings.TrimSpace(parts[0])}
+		if strings.TrimSpace(parts[1]) == "off" {
+			rule.Disabled = true
+		} else {
+			rate, err := ParseSampleRate(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid route rule %q: %v", entry, err)
+			}
+			rule.Rate = rate
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
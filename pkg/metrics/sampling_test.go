@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteRule_Matches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/api/v1/tasks", "/api/v1/tasks", true},
+		{"/api/v1/tasks", "/api/v1/tasks/", true},
+		{"/api/v1/tasks", "/api/v1/tasks/123", false},
+		{"/api/v1/tasks/{id}", "/api/v1/tasks/123", true},
+		{"/api/v1/tasks/{id}", "/api/v1/tasks/123/related", false},
+		{"/api/v1/tasks/*", "/api/v1/tasks/123/related", true},
+		{"/health*", "/health", false},
+		{"/health", "/health/details", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, RouteRule{Pattern: tt.pattern}.Matches(tt.path))
+		})
+	}
+}
+
+func TestSampler_Sample(t *testing.T) {
+	rules, err := ParseRouteRules("/health=off, /api/v1/tasks=0.1, /api/v1/tasks/*=0")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		path       string
+		status     int
+		random     float64
+		wantRecord bool
+		wantWeight float64
+	}{
+		{name: "default rate records everything", path: "/oauth/token", status: 200, random: 0.99, wantRecord: true, wantWeight: 1},
+		{name: "disabled route", path: "/health", status: 200, wantRecord: false},
+		{name: "disabled route skips errors too", path: "/health", status: 503, wantRecord: false},
+		{name: "sampled in and weighted", path: "/api/v1/tasks", status: 200, random: 0.05, wantRecord: true, wantWeight: 10},
+		{name: "sampled out", path: "/api/v1/tasks", status: 200, random: 0.5, wantRecord: false},
+		{name: "errors always recorded", path: "/api/v1/tasks", status: 500, random: 0.5, wantRecord: true, wantWeight: 1},
+		{name: "zero rate records only errors", path: "/api/v1/tasks/123", status: 200, random: 0, wantRecord: false},
+		{name: "zero rate error", path: "/api/v1/tasks/123", status: 502, wantRecord: true, wantWeight: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSampler(1, rules)
+			s.random = func() float64 { return tt.random }
+
+			weight, ok := s.Sample(tt.path, tt.status)
+			assert.Equal(t, tt.wantRecord, ok)
+			if tt.wantRecord {
+				assert.InDelta(t, tt.wantWeight, weight, 1e-9)
+			}
+		})
+	}
+}
+
+func TestParseRouteRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []RouteRule
+		wantErr bool
+	}{
+		{name: "empty", value: ""},
+		{
+			name:  "rates and off",
+			value: "/api/v1/tasks=0.25,/health=off",
+			want:  []RouteRule{{Pattern: "/api/v1/tasks", Rate: 0.25}, {Pattern: "/health", Disabled: true}},
+		},
+		{name: "missing rate", value: "/api/v1/tasks", wantErr: true},
+		{name: "relative path", value: "tasks=0.5", wantErr: true},
+		{name: "rate out of range", value: "/api/v1/tasks=1.5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParseRouteRules(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rules)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// Response headers carrying the most severe active announcement
+const (
+	AnnouncementHeader         = "X-Announcement"
+	AnnouncementSeverityHeader = "X-Announcement-Severity"
+)
+
+// AnnouncementSource lists the announcements shown now, most severe first
+type AnnouncementSource interface {
+	ActiveAnnouncements(ctx context.Context) ([]*models.Announcement, error)
+}
+
+// AnnouncementMiddleware adds the most severe active announcement to every response,
+// so API clients learn of maintenance without polling. Failing to read announcements
+// never fails the request.
+func AnnouncementMiddleware(source AnnouncementSource) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			announcements, err := source.ActiveAnnouncements(r.Context())
+			if err != nil {
+				log.Printf("Failed to read announcements: %v", err)
+			} else if len(announcements) > 0 {
+				w.Header().Set(AnnouncementHeader, announcements[0].Message)
+				w.Header().Set(AnnouncementSeverityHeader, string(announcements[0].Severity))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+
+	"sample/task-management-system/pkg/audit"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// AuditMiddleware records every authenticated write request in the audit log,
+// including the real actor when a user is being impersonated
+func AuditMiddleware(logger audit.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rw := NewResponseWriter(w)
+			next.ServeHTTP(rw, r)
+
+			if _, err := auth.GetUserFromContext(r.Context()); err != nil {
+				return
+			}
+
+			logger.Record(r.Context(), &models.AuditEvent{
+				Action:     "http.request",
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				StatusCode: rw.StatusCode(),
+				RemoteAddr: r.RemoteAddr,
+			})
+		})
+	}
+}
@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,13 +11,22 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/auth"
 	"sample/task-management-system/pkg/cache"
+	"sample/task-management-system/pkg/metrics"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/tenant"
 )
 
 // CacheMiddleware handles caching of HTTP responses
 type CacheMiddleware struct {
 	cache    *cache.RedisCache
 	duration time.Duration
+	// local keeps the hottest responses in process in front of Redis; nil turns it off
+	local *cache.LocalCache
+	// prefetchVia serves prefetched links; nil serves them with the handler the cache wraps
+	prefetchVia http.Handler
 }
 
 func NewCacheMiddleware(cache *cache.RedisCache, expiration time.Duration) *CacheMiddleware {
@@ -25,6 +36,22 @@ func NewCacheMiddleware(cache *cache.RedisCache, expiration time.Duration) *Cach
 	}
 }
 
+// WithLocalCache keeps responses for single tasks in local as well as Redis, so the
+// hottest reads skip the Redis round trip
+func (m *CacheMiddleware) WithLocalCache(local *cache.LocalCache) *CacheMiddleware {
+	m.local = local
+	return m
+}
+
+// WithPrefetchHandler serves the links a list response hints at through h. It is needed
+// when the cache runs inside a router, where the handler it wraps only serves the list's
+// route; h is then the router, so prefetches pass authentication like the caller's own
+// requests.
+func (m *CacheMiddleware) WithPrefetchHandler(h http.Handler) *CacheMiddleware {
+	m.prefetchVia = h
+	return m
+}
+
 // buildCacheKey generates a consistent and efficient cache key
 func (m *CacheMiddleware) buildCacheKey(r *http.Request) string {
 	// Extract path parts
@@ -58,17 +85,26 @@ func (m *CacheMiddleware) buildCacheKey(r *http.Request) string {
 	keyParts := []string{
 		version,
 		"tasks", // Always use "tasks" as the resource type
+		tenantKey(r),
 	}
 
 	// Add user ID if present
 	if userID := r.Header.Get("X-User-ID"); userID != "" {
 		keyParts = append(keyParts, userID)
 	}
+
+	// Responses carry per-user state such as "starred", so callers never share entries
+	keyParts = append(keyParts, callerKey(r))
 	
 	// Add resource ID if present (for single resource requests)
 	if len(parts) > 3 {
 		keyParts = append(keyParts, parts[3])
 	}
+
+	// Add sub-resource path (e.g. /tasks/{id}/related) so it doesn't share the resource's key
+	if len(parts) > 4 {
+		keyParts = append(keyParts, strings.Join(parts[4:], "/"))
+	}
 	
 	if len(queryParts) > 0 {
 		keyParts = append(keyParts, strings.Join(queryParts, "&"))
@@ -87,22 +123,25 @@ func (m *CacheMiddleware) buildCachePatterns(r *http.Request) []string {
 		version = parts[1]
 	}
 	
-	// Always include the base pattern that matches all task-related keys
+	// Writes only change the writer's tenant, so other tenants' entries are left alone
+	tenant := tenantKey(r)
+
+	// Always include the base pattern that matches all of the tenant's task-related keys
 	patterns := []string{
-		fmt.Sprintf("%s:tasks:*", version),
+		fmt.Sprintf("%s:tasks:%s:*", version, tenant),
 	}
 
 	// Add user-specific pattern if user ID is present
 	if userID := r.Header.Get("X-User-ID"); userID != "" {
-		patterns = append(patterns, fmt.Sprintf("%s:tasks:%s:*", version, userID))
+		patterns = append(patterns, fmt.Sprintf("%s:tasks:%s:%s:*", version, tenant, userID))
 	}
 
 	// For single resource operations, add specific resource pattern
 	if len(parts) > 3 {
 		resourceID := parts[3]
 		patterns = append(patterns, 
-			fmt.Sprintf("%s:tasks:*:%s", version, resourceID),
-			fmt.Sprintf("%s:tasks:*:%s:*", version, resourceID),
+			fmt.Sprintf("%s:tasks:%s:*:%s", version, tenant, resourceID),
+			fmt.Sprintf("%s:tasks:%s:*:%s:*", version, tenant, resourceID),
 		)
 	}
 
@@ -113,6 +152,8 @@ func (m *CacheMiddleware) buildCachePatterns(r *http.Request) []string {
 // invalidateRelatedCaches removes all cached entries related to the modified resource
 func (m *CacheMiddleware) invalidateRelatedCaches(r *http.Request) error {
 	patterns := m.buildCachePatterns(r)
+	deleted := 0
+	defer func() { metrics.RecordCacheInvalidation(metrics.InvalidationWrite, deleted) }()
 	for _, pattern := range patterns {
 		log.Printf("Attempting to invalidate cache pattern: %s", pattern)
 		keys, err := m.cache.Keys(r.Context(), pattern)
@@ -120,18 +161,49 @@ func (m *CacheMiddleware) invalidateRelatedCaches(r *http.Request) error {
 			log.Printf("Failed to get keys for pattern %s: %v", pattern, err)
 			continue
 		}
+		if m.local != nil {
+			if err := m.local.Invalidate(r.Context(), keys...); err != nil {
+				log.Printf("Failed to broadcast invalidation of %d keys: %v", len(keys), err)
+			}
+		}
 		
 		for _, key := range keys {
 			if err := m.cache.Delete(r.Context(), key); err != nil {
 				log.Printf("Failed to delete cache key %s: %v", key, err)
 				continue
 			}
+			deleted++
 			log.Printf("Successfully invalidated cache key: %s", key)
 		}
 	}
 	return nil
 }
 
+// callerKey identifies the caller in cache keys by a hash of the credentials it presents
+func callerKey(r *http.Request) string {
+	credentials := []string{
+		r.Header.Get("Authorization"),
+		r.Header.Get(auth.APIKeyHeader),
+		r.Header.Get(auth.GuestLinkHeader),
+		r.URL.Query().Get(auth.GuestLinkParam),
+	}
+	if strings.Join(credentials, "") == "" {
+		return "anonymous"
+	}
+	sum := sha256.Sum256([]byte(strings.Join(credentials, "\n")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// tenantKey identifies the caller's tenant in cache keys: the tenant authentication scoped
+// the request to, or the token's claim when the cache runs before authentication. Entries
+// are keyed by the credentials too, so a forged claim finds nothing.
+func tenantKey(r *http.Request) string {
+	if orgID, ok := tenant.FromContext(r.Context()); ok {
+		return models.TaskCacheTenant(orgID)
+	}
+	return models.TaskCacheTenant(auth.UnverifiedOrgID(r))
+}
+
 // isCacheableParam determines if a query parameter should be included in the cache key
 func isCacheableParam(param string) bool {
 	cacheableParams := map[string]bool{
@@ -140,12 +212,47 @@ func isCacheableParam(param string) bool {
 		"page":   true,
 		"sort":   true,
 		"order":  true,
+		"q":      true,
+		"assignee": true,
+		"team":     true,
+		"recursive": true,
+		"tags":     true,
+		"priority": true,
+		"starred":  true,
+		"due_before": true,
+		"due_after":  true,
+		"stale":      true,
+		"snoozed":    true,
+		"view":       true,
 	}
-	return cacheableParams[param]
+	// metadata.<key> filters on custom fields
+	return cacheableParams[param] || strings.HasPrefix(param, "metadata.")
+}
+
+// isTaskPath determines if a request path points at a single task, e.g. /api/v1/tasks/{id}
+func isTaskPath(path string) bool {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 || parts[2] != "tasks" {
+		return false
+	}
+	_, err := uuid.Parse(parts[3])
+	return err == nil
+}
+
+// isCacheablePath determines if a GET request path points at task resources
+func isCacheablePath(path string) bool {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	return len(parts) > 2 && parts[0] == "api" && parts[2] == "tasks"
 }
 
 func (m *CacheMiddleware) CacheHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Prefetches are looked up and stored by the prefetch itself
+		if isPrefetch(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		// Handle write operations (POST, PUT, DELETE)
 		if r.Method != http.MethodGet {
 			// Invalidate related caches before processing the request
@@ -157,20 +264,54 @@ func (m *CacheMiddleware) CacheHandler(next http.Handler) http.Handler {
 			return
 		}
 
+		// Only task reads are cached; other resources (e.g. admin endpoints) pass through
+		if !isCacheablePath(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// "assignee=me" depends on the caller, which isn't known before authentication
+		if r.URL.Query().Get("assignee") == "me" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		// Handle read operations (GET)
+		start := time.Now()
 		cacheKey := m.buildCacheKey(r)
+		kind := metrics.CacheList
+		if isTaskPath(r.URL.Path) {
+			kind = metrics.CacheDetail
+		}
+		local := m.local != nil && kind == metrics.CacheDetail
 
-		// Try to get from cache
+		// Try the local cache, then Redis
+		if local {
+			if cachedResponse, ok := m.local.Get(cacheKey); ok {
+				metrics.RecordCacheLookup(metrics.CacheL1)
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("X-Cache", "HIT")
+				w.Write(cachedResponse)
+				metrics.RecordCacheHit(kind, time.Since(start), len(cachedResponse))
+				return
+			}
+		}
 		var cachedResponse []byte
 		err := m.cache.Get(r.Context(), cacheKey, &cachedResponse)
 		if err == nil {
 			log.Printf("Cache HIT for key: %s", cacheKey)
+			metrics.RecordCacheLookup(metrics.CacheL2)
+			if local {
+				m.local.Set(cacheKey, cachedResponse)
+			}
 			w.Header().Set("Content-Type", "application/json")
 			w.Header().Set("X-Cache", "HIT")
 			w.Write(cachedResponse)
+			metrics.RecordCacheHit(kind, time.Since(start), len(cachedResponse))
 			return
 		}
 		log.Printf("Cache MISS for key: %s", cacheKey)
+		metrics.RecordCacheLookup(metrics.CacheMiss)
 
 		// Create a response recorder
 		buf := &bytes.Buffer{}
@@ -181,13 +322,32 @@ func (m *CacheMiddleware) CacheHandler(next http.Handler) http.Handler {
 
 		// Call the next handler
 		next.ServeHTTP(recorder, r)
+		// Failures are cheap and never cached, so only successes set the baseline
+		if recorder.status == http.StatusOK {
+			metrics.RecordCacheMiss(kind, time.Since(start))
+		}
 
-		// Only cache successful responses
-		if recorder.status == http.StatusOK || recorder.status == http.StatusCreated {
+		// Only cache successful responses the handler allows to be stored
+		if (recorder.status == http.StatusOK || recorder.status == http.StatusCreated) &&
+			recorder.Header().Get("Cache-Control") != "no-store" {
 			if err := m.cache.Set(r.Context(), cacheKey, buf.Bytes(), m.duration); err != nil {
 				log.Printf("Failed to set cache for key %s: %v", cacheKey, err)
 			} else {
 				log.Printf("Successfully cached response for key: %s", cacheKey)
+				if local {
+					m.local.Set(cacheKey, buf.Bytes())
+				}
+			}
+		}
+
+		// Warm the entries a list response hints at, e.g. its tasks' detail URLs
+		if recorder.status == http.StatusOK {
+			if links := preloadLinks(recorder.Header()); len(links) > 0 {
+				via := next
+				if m.prefetchVia != nil {
+					via = m.prefetchVia
+				}
+				m.prefetch(via, r, links)
 			}
 		}
 	})
@@ -207,4 +367,9 @@ func (r *responseRecorder) Write(b []byte) (int, error) {
 func (r *responseRecorder) WriteHeader(statusCode int) {
 	r.status = statusCode
 	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
 } 
\ No newline at end of file
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/cache"
+	"sample/task-management-system/pkg/metrics"
+)
+
+func TestCacheKeys_TenantNamespaces(t *testing.T) {
+	m := NewCacheMiddleware(nil, 0)
+	request := func(method, path, orgID string) *http.Request {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{UserID: "user-1", OrgID: orgID}).
+			SignedString([]byte("secret"))
+		require.NoError(t, err)
+		r := httptest.NewRequest(method, path, nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		return r
+	}
+
+	assert.Regexp(t, `^v1:tasks:org-acme:[0-9a-f]+:task-1$`, m.buildCacheKey(request(http.MethodGet, "/api/v1/tasks/task-1", "acme")))
+	assert.Regexp(t, `^v1:tasks:default:[0-9a-f]+:task-1$`, m.buildCacheKey(request(http.MethodGet, "/api/v1/tasks/task-1", "")))
+
+	// A write invalidates only its own tenant's entries
+	assert.Equal(t, []string{
+		"v1:tasks:org-acme:*",
+		"v1:tasks:org-acme:*:task-1",
+		"v1:tasks:org-acme:*:task-1:*",
+	}, m.buildCachePatterns(request(http.MethodPut, "/api/v1/tasks/task-1", "acme")))
+}
+
+func TestCacheHandler_LocalCache(t *testing.T) {
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisCache(server.Addr(), "", 0)
+	require.NoError(t, err)
+	m := NewCacheMiddleware(redisCache, time.Minute).WithLocalCache(cache.NewLocalCache(redisCache, time.Minute, 10))
+
+	calls := 0
+	handler := m.CacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"4f9b5e4e-8c4a-4d8e-9a53-0d6f3b1f6a2e"}`))
+	}))
+	serve := func(method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, "/api/v1/tasks/4f9b5e4e-8c4a-4d8e-9a53-0d6f3b1f6a2e", nil))
+		return rec
+	}
+
+	serve(http.MethodGet)
+	assert.Equal(t, 1, calls)
+
+	// Served from memory even once Redis has lost the entry
+	before := metrics.CacheLookups()
+	server.FlushAll()
+	rec := serve(http.MethodGet)
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, before.L1Hits+1, metrics.CacheLookups().L1Hits)
+	assert.Equal(t, before.BytesServed+uint64(rec.Body.Len()), metrics.CacheLookups().BytesServed)
+
+	// A write drops the local copy along with the Redis entry
+	m.local = cache.NewLocalCache(redisCache, time.Minute, 10)
+	serve(http.MethodGet)
+	serve(http.MethodPut)
+	serve(http.MethodGet)
+	assert.Equal(t, 4, calls)
+}
+
+func TestCacheHandler_RestoreAndPurgeInvalidateListsAndTasks(t *testing.T) {
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisCache(server.Addr(), "", 0)
+	require.NoError(t, err)
+	m := NewCacheMiddleware(redisCache, time.Minute)
+
+	calls := 0
+	handler := m.CacheHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+	}))
+	serve := func(method, path string) {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+	}
+	const task = "/api/v1/tasks/4f9b5e4e-8c4a-4d8e-9a53-0d6f3b1f6a2e"
+
+	for _, write := range []struct{ method, path string }{
+		{http.MethodPost, task + "/restore"},
+		{http.MethodDelete, task + "/purge"},
+	} {
+		server.FlushAll()
+		calls = 0
+		serve(http.MethodGet, "/api/v1/tasks")
+		serve(http.MethodGet, task)
+		serve(write.method, write.path)
+		serve(http.MethodGet, "/api/v1/tasks")
+		serve(http.MethodGet, task)
+		assert.Equal(t, 5, calls, "%s %s", write.method, write.path)
+	}
+}
+
+func TestCacheHandler_PrefetchesThroughRouter(t *testing.T) {
+	server := miniredis.RunT(t)
+	redisCache, err := cache.NewRedisCache(server.Addr(), "", 0)
+	require.NoError(t, err)
+	m := NewCacheMiddleware(redisCache, time.Minute)
+
+	const detail = "/api/v1/tasks/4f9b5e4e-8c4a-4d8e-9a53-0d6f3b1f6a2e"
+	var detailCalls atomic.Int32
+	router := mux.NewRouter()
+	router.Use(m.CacheHandler)
+	// The list spends the caller's only request; prefetches don't count against it
+	router.Use(NewLocalRateLimiter(0, 1).RateLimit)
+	router.HandleFunc("/api/v1/tasks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", "<"+detail+">; rel=preload")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"tasks":[]}`))
+	})
+	router.HandleFunc("/api/v1/tasks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		detailCalls.Add(1)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"4f9b5e4e-8c4a-4d8e-9a53-0d6f3b1f6a2e"}`))
+	})
+	// Inside the router the cache only wraps the list's route, so prefetches go through it
+	m.WithPrefetchHandler(router)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil))
+	// The list and the prefetched detail
+	require.Eventually(t, func() bool { return len(server.Keys()) == 2 }, time.Second, 10*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, detail, nil))
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	assert.JSONEq(t, `{"id":"4f9b5e4e-8c4a-4d8e-9a53-0d6f3b1f6a2e"}`, rec.Body.String())
+	assert.Equal(t, int32(1), detailCalls.Load())
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// DeprecationRecorder counts requests using deprecations, e.g. service.DeprecationService
+type DeprecationRecorder interface {
+	Record(usage *models.DeprecationUsage)
+}
+
+// DeprecationMiddleware marks responses to requests using a deprecated endpoint,
+// parameter or API version with the Deprecation and Sunset (RFC 8594) headers, and counts
+// the request for the client: its API key, or its user agent without one. It runs after
+// authentication so the client's user can be told before the sunset date.
+func DeprecationMiddleware(deprecations []models.Deprecation, recorder DeprecationRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(deprecations) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			route := routeTemplate(r)
+			query := r.URL.Query()
+			var sunset *models.Deprecation
+			for i := range deprecations {
+				deprecation := &deprecations[i]
+				if !deprecation.Matches(r.Method, route, r.URL.Path, query) {
+					continue
+				}
+				if sunset == nil || deprecation.Sunset.Before(sunset.Sunset) {
+					sunset = deprecation
+				}
+
+				kind, client := models.DeprecationClient(auth.APIKeyID(r.Context()), r.UserAgent())
+				usage := &models.DeprecationUsage{DeprecationID: deprecation.ID, ClientKind: kind, Client: client}
+				if user, err := auth.GetUserFromContext(r.Context()); err == nil {
+					usage.UserID, usage.OrgID = user.ID, user.OrgID
+				}
+				recorder.Record(usage)
+			}
+
+			if sunset != nil {
+				w.Header().Set("Deprecation", "true")
+				w.Header().Set("Sunset", sunset.Sunset.UTC().Format(http.TimeFormat))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
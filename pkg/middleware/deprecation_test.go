@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// recordingDeprecations collects the usage it is asked to record
+type recordingDeprecations struct {
+	usage []*models.DeprecationUsage
+}
+
+func (r *recordingDeprecations) Record(usage *models.DeprecationUsage) {
+	r.usage = append(r.usage, usage)
+}
+
+// apiKeyVerifier authenticates the "tms_key" API key as user-1's key-1
+type apiKeyVerifier struct{}
+
+func (apiKeyVerifier) VerifyAPIKey(ctx context.Context, key string) (*auth.Claims, error) {
+	if key != "tms_key" {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	return &auth.Claims{UserID: "user-1", Roles: []string{"user"}, OrgID: "acme", APIKeyID: "key-1"}, nil
+}
+
+func TestDeprecationMiddleware(t *testing.T) {
+	deprecations := []models.Deprecation{
+		{ID: "v1", Kind: models.DeprecatedVersion, Version: "v1", Sunset: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "tasks-per-page", Kind: models.DeprecatedParameter, Route: "/api/v1/tasks", Methods: []string{http.MethodGet},
+			Parameter: "per_page", Sunset: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)},
+		{ID: "task-watchers", Kind: models.DeprecatedEndpoint, Route: "/api/v1/tasks/{id}/watchers",
+			Sunset: time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	recorder := &recordingDeprecations{}
+
+	router := mux.NewRouter()
+	router.Use(auth.APIKeyMiddleware(apiKeyVerifier{}), func(next http.Handler) http.Handler {
+		// Stands in for AuthMiddleware, which puts the key's claims in the context
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(auth.APIKeyHeader) != "" {
+				claims, _ := apiKeyVerifier{}.VerifyAPIKey(r.Context(), r.Header.Get(auth.APIKeyHeader))
+				r = r.WithContext(context.WithValue(r.Context(), "claims", claims))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, DeprecationMiddleware(deprecations, recorder))
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.HandleFunc("/api/v1/tasks", ok)
+	router.HandleFunc("/api/v1/tasks/{id}/watchers", ok)
+	router.HandleFunc("/api/v2/tasks", ok)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		apiKey     string
+		wantIDs    []string
+		wantSunset string
+	}{
+		{"current version", http.MethodGet, "/api/v2/tasks", "", nil, ""},
+		{"deprecated version", http.MethodGet, "/api/v1/tasks", "", []string{"v1"}, "Wed, 01 Jan 2025 00:00:00 GMT"},
+		{"earliest sunset wins", http.MethodGet, "/api/v1/tasks?per_page=10", "tms_key", []string{"v1", "tasks-per-page"}, "Sun, 30 Jun 2024 00:00:00 GMT"},
+		{"parameter limited to reads", http.MethodPost, "/api/v1/tasks?per_page=10", "", []string{"v1"}, "Wed, 01 Jan 2025 00:00:00 GMT"},
+		{"endpoint by route", http.MethodGet, "/api/v1/tasks/task-1/watchers", "", []string{"v1", "task-watchers"}, "Sun, 01 Sep 2024 00:00:00 GMT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder.usage = nil
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			req.Header.Set("User-Agent", "sync/1.0")
+			if tt.apiKey != "" {
+				req.Header.Set(auth.APIKeyHeader, tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.wantSunset, rec.Header().Get("Sunset"))
+			var ids []string
+			for _, usage := range recorder.usage {
+				ids = append(ids, usage.DeprecationID)
+				if tt.apiKey != "" {
+					assert.Equal(t, &models.DeprecationUsage{DeprecationID: usage.DeprecationID, ClientKind: models.DeprecationClientAPIKey,
+						Client: "key-1", UserID: "user-1", OrgID: "acme"}, usage)
+				} else {
+					assert.Equal(t, &models.DeprecationUsage{DeprecationID: usage.DeprecationID, ClientKind: models.DeprecationClientUserAgent,
+						Client: "sync/1.0"}, usage)
+				}
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			if tt.wantIDs == nil {
+				assert.Empty(t, rec.Header().Get("Deprecation"))
+			} else {
+				assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+			}
+		})
+	}
+}
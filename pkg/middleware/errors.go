@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Error codes of throttling and overload responses, for clients to decide how to retry
+const (
+	CodeRateLimited        = "rate_limited"
+	CodeOverloaded         = "overloaded"
+	CodeMaintenance        = "maintenance"
+	CodeServiceUnavailable = "service_unavailable"
+)
+
+// RetryableError is the body of every 429 and 503 response
+type RetryableError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	RetryAfter       int    `json:"retry_after"` // seconds, same as the Retry-After header
+	RequestID        string `json:"request_id,omitempty"`
+}
+
+// WriteRetryableError writes a throttling or overload response telling the client when
+// to retry. retryAfter is rounded up to whole seconds, and is at least one second.
+func WriteRetryableError(w http.ResponseWriter, r *http.Request, status int, code, description string, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(RetryableError{
+		Error:            code,
+		ErrorDescription: description,
+		RetryAfter:       seconds,
+		RequestID:        RequestIDFromContext(r.Context()),
+	})
+}
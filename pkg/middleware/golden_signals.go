@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"sample/task-management-system/pkg/metrics"
+)
+
+// maxQueueWait bounds the queue wait taken from X-Request-Start, so a skewed proxy
+// clock or a bogus header can't distort the metric
+const maxQueueWait = time.Minute
+
+// GoldenSignalsMiddleware tracks requests in flight, server errors per route and how
+// long requests queued in front of the API. Together with the request latency recorded
+// by LoggingMiddleware these are the four golden signals. It should be the outermost
+// router middleware so in-flight counts include requests rejected by later middleware.
+func GoldenSignalsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if wait, ok := queueWait(r.Header.Get("X-Request-Start"), time.Now()); ok {
+			metrics.RecordQueueWait(wait)
+		}
+
+		done := metrics.StartRequest()
+		rw := NewResponseWriter(w)
+		defer func() {
+			// A panicking handler still leaves flight, as a server error
+			if err := recover(); err != nil {
+				done(r.Method, routeTemplate(r), http.StatusInternalServerError)
+				panic(err)
+			}
+		}()
+
+		next.ServeHTTP(rw, r)
+		done(r.Method, routeTemplate(r), rw.StatusCode())
+	})
+}
+
+// queueWait parses the time a proxy received the request from an X-Request-Start
+// header, e.g. "t=1700000000.123" as set by nginx's ${msec}, and returns how long ago
+// that was. Seconds, milliseconds and microseconds since the epoch are accepted.
+func queueWait(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(header), "t="), 64)
+	if err != nil || value <= 0 {
+		return 0, false
+	}
+
+	var start time.Time
+	switch {
+	case value >= 1e15:
+		start = time.UnixMicro(int64(value))
+	case value >= 1e12:
+		start = time.UnixMilli(int64(value))
+	default:
+		start = time.Unix(0, int64(value*float64(time.Second)))
+	}
+
+	wait := now.Sub(start)
+	if wait > maxQueueWait {
+		return 0, false
+	}
+	if wait < 0 {
+		// The proxy's clock is ahead of ours
+		wait = 0
+	}
+	return wait, true
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueueWait(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "missing", header: ""},
+		{name: "nginx seconds", header: "t=1699999999.750", want: 250 * time.Millisecond, wantOK: true},
+		{name: "milliseconds", header: "1699999999900", want: 100 * time.Millisecond, wantOK: true},
+		{name: "microseconds", header: "t=1699999999990000", want: 10 * time.Millisecond, wantOK: true},
+		{name: "proxy clock ahead", header: "t=1700000001", want: 0, wantOK: true},
+		{name: "too long ago", header: "t=1699990000"},
+		{name: "malformed", header: "t=soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := queueWait(tt.header, now)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.InDelta(t, float64(tt.want), float64(got), float64(time.Millisecond))
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"sample/task-management-system/pkg/audit"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// allowlistRetryAfter is how long clients are asked to wait when the allowlist can't be read
+const allowlistRetryAfter = 5 * time.Second
+
+// IPAllowlistChecker decides whether an IP may access an organization
+type IPAllowlistChecker interface {
+	IsAllowed(ctx context.Context, orgID string, ip net.IP) (bool, error)
+}
+
+// IPAllowlistMiddleware rejects requests from users of an organization when the
+// client IP is outside the organization's allowlist. Denials are audited.
+// Requests without an authenticated organization member pass through.
+func IPAllowlistMiddleware(checker IPAllowlistChecker, resolver *ClientIPResolver, logger audit.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, err := auth.GetUserFromContext(r.Context())
+			if err != nil || user.OrgID == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ip := resolver.ClientIP(r)
+			allowed, err := checker.IsAllowed(r.Context(), user.OrgID, ip)
+			if err != nil {
+				// Fail closed; an allowlist we can't read must not be bypassed
+				log.Printf("Failed to check IP allowlist for org %s: %v", user.OrgID, err)
+				WriteRetryableError(w, r, http.StatusServiceUnavailable, CodeServiceUnavailable,
+					"unable to verify IP allowlist", allowlistRetryAfter)
+				return
+			}
+
+			if !allowed {
+				logger.Record(r.Context(), &models.AuditEvent{
+					Action:     "ip_allowlist.denied",
+					Method:     r.Method,
+					Path:       r.URL.Path,
+					StatusCode: http.StatusForbidden,
+					RemoteAddr: ip.String(),
+					Metadata:   map[string]string{"org_id": user.OrgID},
+				})
+				http.Error(w, "access from this IP address is not allowed", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+func TestClientIPResolver(t *testing.T) {
+	resolver, err := NewClientIPResolver([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+	}{
+		{"direct client", "203.0.113.7:1234", nil, "203.0.113.7"},
+		{"untrusted peer cannot spoof", "203.0.113.7:1234", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"trusted proxy", "10.0.0.5:1234", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"proxy chain", "10.0.0.5:1234", []string{"198.51.100.1, 192.168.1.1"}, "198.51.100.1"},
+		{"spoofed leftmost entry is ignored", "10.0.0.5:1234", []string{"1.2.3.4, 198.51.100.1"}, "198.51.100.1"},
+		{"multiple headers", "10.0.0.5:1234", []string{"1.2.3.4", "198.51.100.1"}, "198.51.100.1"},
+		{"malformed hop", "10.0.0.5:1234", []string{"not-an-ip"}, "10.0.0.5"},
+		{"all hops trusted", "10.0.0.5:1234", []string{"10.1.1.1"}, "10.1.1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, header := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", header)
+			}
+			assert.Equal(t, tt.want, resolver.ClientIP(req).String())
+		})
+	}
+
+	_, err = NewClientIPResolver([]string{"not-a-cidr"})
+	assert.Error(t, err)
+}
+
+type allowlistFunc func(ctx context.Context, orgID string, ip net.IP) (bool, error)
+
+func (f allowlistFunc) IsAllowed(ctx context.Context, orgID string, ip net.IP) (bool, error) {
+	return f(ctx, orgID, ip)
+}
+
+type recordingLogger struct {
+	events []*models.AuditEvent
+}
+
+func (l *recordingLogger) Record(ctx context.Context, event *models.AuditEvent) {
+	l.events = append(l.events, event)
+}
+
+func TestIPAllowlistMiddleware(t *testing.T) {
+	resolver, err := NewClientIPResolver(nil)
+	require.NoError(t, err)
+	_, office, _ := net.ParseCIDR("203.0.113.0/24")
+
+	checker := allowlistFunc(func(ctx context.Context, orgID string, ip net.IP) (bool, error) {
+		switch orgID {
+		case "broken":
+			return false, errors.New("database unavailable")
+		case "acme":
+			return office.Contains(ip), nil
+		}
+		return true, nil
+	})
+
+	tests := []struct {
+		name       string
+		claims     *auth.Claims
+		remoteAddr string
+		want       int
+		wantAudit  bool
+	}{
+		{"no user", nil, "198.51.100.1:1234", http.StatusOK, false},
+		{"user without org", &auth.Claims{UserID: "u1"}, "198.51.100.1:1234", http.StatusOK, false},
+		{"inside allowlist", &auth.Claims{UserID: "u1", OrgID: "acme"}, "203.0.113.9:1234", http.StatusOK, false},
+		{"outside allowlist", &auth.Claims{UserID: "u1", OrgID: "acme"}, "198.51.100.1:1234", http.StatusForbidden, true},
+		{"checker failure fails closed", &auth.Claims{UserID: "u1", OrgID: "broken"}, "203.0.113.9:1234", http.StatusServiceUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			handler := IPAllowlistMiddleware(checker, resolver, logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.claims != nil {
+				req = req.WithContext(context.WithValue(req.Context(), "claims", tt.claims))
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+
+			if tt.wantAudit {
+				require.Len(t, logger.events, 1)
+				assert.Equal(t, "ip_allowlist.denied", logger.events[0].Action)
+				assert.Equal(t, "198.51.100.1", logger.events[0].RemoteAddr)
+				assert.Equal(t, "acme", logger.events[0].Metadata["org_id"])
+			} else {
+				assert.Empty(t, logger.events)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/gorilla/mux"
 	"sample/task-management-system/pkg/metrics"
 )
 
@@ -23,13 +24,19 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // LoggingMiddleware logs request details and records metrics
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
 		// Log incoming request
-		log.Printf("Incoming request: %s %s", r.Method, r.RequestURI)
+		requestID := RequestIDFromContext(r.Context())
+		log.Printf("Incoming request: %s %s (request_id: %s)", r.Method, r.RequestURI, requestID)
 
 		// Create a response wrapper to capture the status code
 		rw := newResponseWriter(w)
@@ -41,11 +48,21 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 		duration := time.Since(start).Seconds()
 
 		// Log completion
-		log.Printf("Completed request: %s %s (status: %d, duration: %.2fs)",
-			r.Method, r.RequestURI, rw.statusCode, duration)
+		log.Printf("Completed request: %s %s (status: %d, duration: %.2fs, request_id: %s)",
+			r.Method, r.RequestURI, rw.statusCode, duration, requestID)
 
-		// Record metrics if enabled
-		metrics.RecordRequestDuration(r.Method, r.URL.Path, duration)
-		metrics.RecordAPICall(r.Method, r.URL.Path, rw.statusCode)
+		// Record metrics if enabled and sampled
+		metrics.RecordRequest(r.Method, routeTemplate(r), rw.statusCode, duration)
 	})
+}
+
+// routeTemplate returns the matched route's path template, e.g. /api/v1/tasks/{id}, so
+// metrics for different IDs aggregate together; unmatched requests use the raw path
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return r.URL.Path
 } 
\ No newline at end of file
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MaintenanceMiddleware answers every request with 503 and a Retry-After while the API
+// is under maintenance. Health checks still pass through, so load balancers keep
+// routing clients here to receive the maintenance response.
+func MaintenanceMiddleware(retryAfter time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" || strings.HasPrefix(r.URL.Path, "/health/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			WriteRetryableError(w, r, http.StatusServiceUnavailable, CodeMaintenance,
+				"The API is down for maintenance", retryAfter)
+		})
+	}
+}
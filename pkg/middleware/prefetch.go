@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"sample/task-management-system/pkg/prefetch"
+)
+
+// prefetchTimeout bounds how long warming a list response's hints may take
+const prefetchTimeout = 10 * time.Second
+
+// isPrefetch reports whether r was made to warm the cache
+func isPrefetch(r *http.Request) bool {
+	return prefetch.FromContext(r.Context())
+}
+
+// preloadLinks returns the same-origin API paths a response hints at with
+// `Link: <path>; rel=preload` headers
+func preloadLinks(header http.Header) []string {
+	var links []string
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			target = target[1 : len(target)-1]
+			// Only warm our own task reads, never a URL a handler was tricked into echoing
+			if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") || !isCacheablePath(strings.SplitN(target, "?", 2)[0]) {
+				continue
+			}
+			for _, param := range parts[1:] {
+				if strings.EqualFold(strings.ReplaceAll(strings.TrimSpace(param), `"`, ""), "rel=preload") {
+					links = append(links, target)
+					break
+				}
+			}
+		}
+	}
+	return links
+}
+
+// prefetch warms the cache entries of links for the caller of r, so the detail fetches
+// hinted by a list response are served from cache. Links run one at a time through the
+// full handler with the caller's credentials, so they are authorized like the caller's
+// own requests, but don't count against their rate limit or record views. The cache is
+// warmed in the background, after the list was answered.
+func (m *CacheMiddleware) prefetch(next http.Handler, r *http.Request, links []string) {
+	// The request is done with once the list was answered, so keep a copy of it
+	r = r.Clone(context.WithoutCancel(r.Context()))
+	go m.warm(next, r, links)
+}
+
+func (m *CacheMiddleware) warm(next http.Handler, r *http.Request, links []string) {
+	ctx, cancel := context.WithTimeout(prefetch.With(r.Context()), prefetchTimeout)
+	defer cancel()
+
+	for _, link := range links {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+		if err != nil {
+			continue
+		}
+		req.Header = r.Header.Clone()
+		req.RemoteAddr = r.RemoteAddr
+
+		key := m.buildCacheKey(req)
+		var cached []byte
+		if m.cache.Get(ctx, key, &cached) == nil {
+			continue
+		}
+
+		rec := &prefetchRecorder{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, req)
+		if rec.status != http.StatusOK || rec.header.Get("Cache-Control") == "no-store" {
+			continue
+		}
+		if err := m.cache.Set(ctx, key, rec.buf.Bytes(), m.duration); err != nil {
+			log.Printf("Failed to prefetch %s: %v", link, err)
+			return
+		}
+	}
+}
+
+// prefetchRecorder collects a prefetched response without a client to send it to
+type prefetchRecorder struct {
+	header http.Header
+	buf    bytes.Buffer
+	status int
+}
+
+func (r *prefetchRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *prefetchRecorder) Write(b []byte) (int, error) {
+	return r.buf.Write(b)
+}
+
+func (r *prefetchRecorder) WriteHeader(statusCode int) {
+	r.status = statusCode
+}
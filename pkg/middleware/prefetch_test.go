@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreloadLinks(t *testing.T) {
+	header := http.Header{}
+	header.Add("Link", "</api/v1/tasks/1>; rel=preload; as=fetch")
+	header.Add("Link", `</api/v1/tasks/2?link=abc>; rel="preload", </api/v1/tasks/3>; rel=next`)
+	header.Add("Link", "<https://example.com/api/v1/tasks/4>; rel=preload")
+	header.Add("Link", "<//example.com/api/v1/tasks/5>; rel=preload")
+	header.Add("Link", "</api/v1/projects/6>; rel=preload")
+
+	assert.Equal(t, []string{"/api/v1/tasks/1", "/api/v1/tasks/2?link=abc"}, preloadLinks(header))
+}
@@ -1,11 +1,23 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"golang.org/x/time/rate"
+
+	"sample/task-management-system/pkg/auth"
+)
+
+// Rate limit headers are sent on every response, so clients can slow down before they
+// are limited
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset" // seconds until the window starts over
 )
 
 type RateLimiter struct {
@@ -28,26 +40,46 @@ func NewRateLimiter(redisURL string, maxRequests int, window time.Duration) (*Ra
 	}, nil
 }
 
+// RateLimit counts requests per caller in fixed windows. Authenticated callers are
+// counted by user, everyone else by address. Prefetches aren't counted.
 func (rl *RateLimiter) RateLimit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Use IP address as key
+		if isPrefetch(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		key := "ratelimit:" + r.RemoteAddr
+		if claims, ok := r.Context().Value("claims").(*auth.Claims); ok && claims.UserID != "" {
+			key = "ratelimit:user:" + claims.UserID
+		}
 
-		// Use Redis INCR to count requests
-		val, err := rl.client.Incr(r.Context(), key).Result()
+		// Count the request and read the window's remaining time in one round trip
+		var incr *redis.IntCmd
+		var ttl *redis.DurationCmd
+		_, err := rl.client.Pipelined(r.Context(), func(pipe redis.Pipeliner) error {
+			incr = pipe.Incr(r.Context(), key)
+			ttl = pipe.PTTL(r.Context(), key)
+			return nil
+		})
 		if err != nil {
 			http.Error(w, "Rate limiting error", http.StatusInternalServerError)
 			return
 		}
+		val := incr.Val()
 
-		// Set expiry for the first request in window
-		if val == 1 {
+		// The first request of a window starts it
+		reset := ttl.Val()
+		if reset <= 0 {
 			rl.client.Expire(r.Context(), key, rl.window)
+			reset = rl.window
 		}
 
+		SetRateLimitHeaders(w, rl.maxRequests, rl.maxRequests-int(val), reset)
+
 		// Check if request count exceeds limit
 		if val > int64(rl.maxRequests) {
-			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			WriteRetryableError(w, r, http.StatusTooManyRequests, CodeRateLimited, "Too many requests", reset)
 			return
 		}
 
@@ -55,6 +87,17 @@ func (rl *RateLimiter) RateLimit(next http.Handler) http.Handler {
 	})
 }
 
+// SetRateLimitHeaders reports a limit's state to the client; remaining is clamped at zero
+// and reset rounded up to whole seconds
+func SetRateLimitHeaders(w http.ResponseWriter, limit, remaining int, reset time.Duration) {
+	if remaining < 0 {
+		remaining = 0
+	}
+	w.Header().Set(RateLimitLimitHeader, strconv.Itoa(limit))
+	w.Header().Set(RateLimitRemainingHeader, strconv.Itoa(remaining))
+	w.Header().Set(RateLimitResetHeader, strconv.Itoa(int(math.Ceil(reset.Seconds()))))
+}
+
 // Local rate limiter as fallback
 type LocalRateLimiter struct {
 	limiter *rate.Limiter
@@ -68,8 +111,8 @@ func NewLocalRateLimiter(r rate.Limit, b int) *LocalRateLimiter {
 
 func (l *LocalRateLimiter) RateLimit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !l.limiter.Allow() {
-			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+		if !isPrefetch(r) && !l.limiter.Allow() {
+			WriteRetryableError(w, r, http.StatusTooManyRequests, CodeRateLimited, "Too many requests", tokenInterval(l.limiter))
 			return
 		}
 		next.ServeHTTP(w, r)
@@ -93,9 +136,17 @@ func NewSafetyLimiter() *SafetyLimiter {
 func (l *SafetyLimiter) Limit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !l.limiter.Allow() {
-			http.Error(w, "Service Protection", http.StatusTooManyRequests)
+			WriteRetryableError(w, r, http.StatusTooManyRequests, CodeOverloaded, "Service Protection", tokenInterval(l.limiter))
 			return
 		}
 		next.ServeHTTP(w, r)
 	})
+}
+
+// tokenInterval returns how long the limiter takes to allow another request
+func tokenInterval(limiter *rate.Limiter) time.Duration {
+	if limiter.Limit() <= 0 {
+		return time.Second
+	}
+	return time.Duration(float64(time.Second) / float64(limiter.Limit()))
 } 
\ No newline at end of file
@@ -19,6 +19,11 @@ func (rw *ResponseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (rw *ResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // StatusCode returns the captured status code
 func (rw *ResponseWriter) StatusCode() int {
 	return rw.statusCode
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// timeFormatParam is the Accept media type parameter that picks the time format, e.g.
+// Accept: application/json; time-format=epoch_millis
+const timeFormatParam = "time-format"
+
+// TimeFormatMiddleware writes the timestamps in JSON responses in the format asked for
+// by the Accept header's time-format parameter, or defaultFormat. Responses are encoded
+// with Go's default RFC3339Nano timestamps and their timestamp fields rewritten on the way
+// out (see models.TimeFormat.ReformatJSON), so handlers and the response cache stay
+// format-agnostic. Downloads (responses with a
+// Content-Disposition) and other content types are passed through untouched.
+func TimeFormatMiddleware(defaultFormat models.TimeFormat) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			format, err := requestedTimeFormat(r.Header, defaultFormat)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.Header().Add("Vary", "Accept")
+			if format == models.TimeFormatRFC3339Nano {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tw := &timeFormatWriter{ResponseWriter: w, format: format, status: http.StatusOK}
+			next.ServeHTTP(tw, r)
+			tw.flush()
+		})
+	}
+}
+
+// requestedTimeFormat returns the time-format parameter of the first Accept media type
+// that has one, or defaultFormat
+func requestedTimeFormat(header http.Header, defaultFormat models.TimeFormat) (models.TimeFormat, error) {
+	for _, accept := range header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			_, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil {
+				continue
+			}
+			if value, ok := params[timeFormatParam]; ok {
+				return models.ParseTimeFormat(value)
+			}
+		}
+	}
+	return defaultFormat, nil
+}
+
+// timeFormatWriter buffers a JSON response so its timestamps can be rewritten. Whether
+// to buffer is decided from the headers when the status is written.
+type timeFormatWriter struct {
+	http.ResponseWriter
+	format      models.TimeFormat
+	status      int
+	wroteHeader bool
+	passthrough bool
+	body        bytes.Buffer
+}
+
+func (tw *timeFormatWriter) WriteHeader(status int) {
+	if tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.status = status
+
+	header := tw.Header()
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	tw.passthrough = mediaType != "application/json" || header.Get("Content-Disposition") != ""
+	if tw.passthrough {
+		tw.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (tw *timeFormatWriter) Write(b []byte) (int, error) {
+	if !tw.wroteHeader {
+		if tw.Header().Get("Content-Type") == "" {
+			tw.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		tw.WriteHeader(http.StatusOK)
+	}
+	if tw.passthrough {
+		return tw.ResponseWriter.Write(b)
+	}
+	return tw.body.Write(b)
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (tw *timeFormatWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+// Flush passes through streamed responses; buffered ones are written by flush
+func (tw *timeFormatWriter) Flush() {
+	if flusher, ok := tw.ResponseWriter.(http.Flusher); ok && tw.passthrough {
+		flusher.Flush()
+	}
+}
+
+// flush writes the buffered response with its timestamps rewritten
+func (tw *timeFormatWriter) flush() {
+	if !tw.wroteHeader {
+		tw.WriteHeader(tw.status)
+	}
+	if tw.passthrough {
+		return
+	}
+
+	body := tw.format.ReformatJSON(tw.body.Bytes())
+	tw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	tw.ResponseWriter.WriteHeader(tw.status)
+	tw.ResponseWriter.Write(body)
+}
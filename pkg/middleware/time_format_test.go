@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sample/task-management-system/pkg/models"
+)
+
+const timeFormatBody = `{"id":"1","title":"2024-05-02T09:00:00Z","created_at":"2024-05-02T09:00:00.123456+02:00","stars":{"2024-05-02T09:00:00Z":1},"metadata":{"due_date":"2024-05-02T09:00:00Z"},"subtasks":[{"updated_at":"2024-05-02T09:00:00Z"}],"due_date":null}`
+
+func serveTimeFormat(defaultFormat models.TimeFormat, accept string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/1", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	TimeFormatMiddleware(defaultFormat)(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func jsonHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(timeFormatBody))
+}
+
+func TestTimeFormatMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultFormat models.TimeFormat
+		accept        string
+		expected      string
+	}{
+		{
+			name:          "default format is left alone",
+			defaultFormat: models.TimeFormatRFC3339Nano,
+			expected:      timeFormatBody,
+		},
+		{
+			name:          "rfc3339 drops fractional seconds",
+			defaultFormat: models.TimeFormatRFC3339Nano,
+			accept:        "application/json; time-format=rfc3339",
+			expected:      `{"id":"1","title":"2024-05-02T09:00:00Z","created_at":"2024-05-02T09:00:00+02:00","stars":{"2024-05-02T09:00:00Z":1},"metadata":{"due_date":"2024-05-02T09:00:00Z"},"subtasks":[{"updated_at":"2024-05-02T09:00:00Z"}],"due_date":null}`,
+		},
+		{
+			name:          "epoch millis from the configured default",
+			defaultFormat: models.TimeFormatEpochMillis,
+			expected:      `{"id":"1","title":"2024-05-02T09:00:00Z","created_at":1714633200123,"stars":{"2024-05-02T09:00:00Z":1},"metadata":{"due_date":"2024-05-02T09:00:00Z"},"subtasks":[{"updated_at":1714640400000}],"due_date":null}`,
+		},
+		{
+			name:          "accept parameter overrides the default",
+			defaultFormat: models.TimeFormatEpochMillis,
+			accept:        "text/html, application/json;time-format=date",
+			expected:      `{"id":"1","title":"2024-05-02T09:00:00Z","created_at":"2024-05-02","stars":{"2024-05-02T09:00:00Z":1},"metadata":{"due_date":"2024-05-02T09:00:00Z"},"subtasks":[{"updated_at":"2024-05-02"}],"due_date":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveTimeFormat(tt.defaultFormat, tt.accept, jsonHandler)
+
+			assert.Equal(t, http.StatusCreated, rec.Code)
+			assert.Equal(t, tt.expected, rec.Body.String())
+			assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestTimeFormatMiddleware_InvalidFormat(t *testing.T) {
+	rec := serveTimeFormat(models.TimeFormatRFC3339Nano, "application/json; time-format=unix", jsonHandler)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTimeFormatMiddleware_PassesThroughDownloads(t *testing.T) {
+	body := "id,created_at\n1,2024-05-02T09:00:00.123Z\n"
+	rec := serveTimeFormat(models.TimeFormatEpochMillis, "", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", `attachment; filename="tasks.json"`)
+		w.Write([]byte(body))
+	})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, body, rec.Body.String())
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Password length limits. bcrypt only hashes the first 72 bytes, so longer passwords are
+// rejected rather than silently truncated.
+const (
+	MinPasswordLength = 12
+	MaxPasswordLength = 72
+)
+
+// PasswordResetTTL is how long a password reset link can be used
+const PasswordResetTTL = 30 * time.Minute
+
+var (
+	// ErrUserNotFound is returned for unknown users and email addresses
+	ErrUserNotFound = errors.New("user not found")
+	// ErrEmailTaken is returned when signing up with an address that already has an account
+	ErrEmailTaken = errors.New("an account with this email already exists")
+	// ErrInvalidCredentials is returned for unknown addresses and wrong passwords alike
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrPasswordResetInvalid is returned for unknown, expired and already used reset tokens
+	ErrPasswordResetInvalid = errors.New("password reset link is invalid or has expired")
+)
+
+// Account is a user who signed up with an email address and password
+type Account struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	Roles     []string  `json:"roles"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AccountSignup represents the data required to sign up
+type AccountSignup struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Validate checks the email address and password
+func (s *AccountSignup) Validate() error {
+	email, err := normalizeEmail(s.Email)
+	if err != nil {
+		return err
+	}
+	s.Email = email
+	return validatePassword(s.Password)
+}
+
+// AccountLogin carries the credentials of a password sign-in
+type AccountLogin struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// PasswordResetRequest asks for a password reset link to be emailed to an address
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+// Validate checks the email address
+func (p *PasswordResetRequest) Validate() error {
+	email, err := normalizeEmail(p.Email)
+	if err != nil {
+		return err
+	}
+	p.Email = email
+	return nil
+}
+
+// PasswordReset sets a new password with the token from a reset link
+type PasswordReset struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// Validate checks the token is present and the new password
+func (p *PasswordReset) Validate() error {
+	if p.Token == "" {
+		return errors.New("token is required")
+	}
+	return validatePassword(p.Password)
+}
+
+// normalizeEmail parses a bare email address and lowercases it
+func normalizeEmail(email string) (string, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || address.Name != "" {
+		return "", errors.New("a valid email is required")
+	}
+	return strings.ToLower(address.Address), nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return errors.New("password must be 12-72 characters")
+	}
+	return nil
+}
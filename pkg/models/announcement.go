@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AnnouncementSeverity says how prominently clients should show an announcement
+type AnnouncementSeverity string
+
+const (
+	SeverityInfo     AnnouncementSeverity = "info"
+	SeverityWarning  AnnouncementSeverity = "warning"
+	SeverityCritical AnnouncementSeverity = "critical"
+)
+
+// MaxAnnouncementLength is the longest announcement message
+const MaxAnnouncementLength = 500
+
+// Announcement is an org-wide notice, such as planned maintenance, shown to API clients
+// between StartsAt and EndsAt
+type Announcement struct {
+	ID        string               `json:"id"`
+	Message   string               `json:"message"`
+	Severity  AnnouncementSeverity `json:"severity"`
+	StartsAt  time.Time            `json:"starts_at"`
+	EndsAt    *time.Time           `json:"ends_at,omitempty"` // nil shows the announcement until it is removed
+	CreatedBy string               `json:"created_by"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// IsActive reports whether the announcement is shown at t
+func (a *Announcement) IsActive(t time.Time) bool {
+	return !t.Before(a.StartsAt) && (a.EndsAt == nil || t.Before(*a.EndsAt))
+}
+
+// AnnouncementInput represents the data required to create or replace an announcement
+type AnnouncementInput struct {
+	Message  string               `json:"message"`
+	Severity AnnouncementSeverity `json:"severity"`
+	StartsAt *time.Time           `json:"starts_at,omitempty"` // defaults to now
+	EndsAt   *time.Time           `json:"ends_at,omitempty"`
+}
+
+// Validate checks the announcement, defaulting the severity to info and the start to now.
+// Messages are single lines so they can be sent in a response header.
+func (a *AnnouncementInput) Validate() error {
+	a.Message = strings.TrimSpace(a.Message)
+	if a.Message == "" {
+		return errors.New("message is required")
+	}
+	if len(a.Message) > MaxAnnouncementLength {
+		return fmt.Errorf("message must be at most %d characters", MaxAnnouncementLength)
+	}
+	if strings.ContainsAny(a.Message, "\r\n") {
+		return errors.New("message must be a single line")
+	}
+	if a.Severity == "" {
+		a.Severity = SeverityInfo
+	}
+	switch a.Severity {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+	default:
+		return errors.New("severity must be info, warning or critical")
+	}
+	if a.StartsAt == nil {
+		now := time.Now()
+		a.StartsAt = &now
+	}
+	if a.EndsAt != nil && !a.EndsAt.After(*a.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	return nil
+}
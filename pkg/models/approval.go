@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ApprovalStatus is where a task's approval stands
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "pending"
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalRejected ApprovalStatus = "rejected"
+)
+
+// MaxApprovalCommentLength caps the comment left with a decision
+const MaxApprovalCommentLength = 2000
+
+// ErrApprovalNotFound is returned when a task doesn't need approval
+var ErrApprovalNotFound = errors.New("approval not found")
+
+// ErrApprovalNotPending is returned when deciding an approval that was already decided
+var ErrApprovalNotPending = errors.New("approval was already decided; request it again to start over")
+
+// TaskApproval is the approval a task needs before it can be completed
+type TaskApproval struct {
+	TaskID      string         `json:"task_id"`
+	ApproverID  string         `json:"approver_id"`
+	RequestedBy string         `json:"requested_by"`
+	Status      ApprovalStatus `json:"status"`
+	Comment     string         `json:"comment,omitempty"`
+	RequestedAt time.Time      `json:"requested_at"`
+	// DecidedBy and DecidedAt record who approved or rejected the task, and when
+	DecidedBy string     `json:"decided_by,omitempty"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+}
+
+// ApprovalRequest represents the data required to make a task need approval
+type ApprovalRequest struct {
+	ApproverID string `json:"approver_id"`
+}
+
+// Validate checks the approver
+func (r *ApprovalRequest) Validate() error {
+	r.ApproverID = strings.TrimSpace(r.ApproverID)
+	if r.ApproverID == "" {
+		return errors.New("approver_id is required")
+	}
+	return nil
+}
+
+// ApprovalDecision is an approver's approval or rejection of a task
+type ApprovalDecision struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// Validate checks the comment length
+func (d *ApprovalDecision) Validate() error {
+	d.Comment = strings.TrimSpace(d.Comment)
+	if len(d.Comment) > MaxApprovalCommentLength {
+		return errors.New("comment must be at most 2000 characters")
+	}
+	return nil
+}
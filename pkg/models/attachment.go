@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// TaskAttachment is the metadata of a file attached to a task; the content lives in object storage
+type TaskAttachment struct {
+	ID          string    `json:"id"`
+	TaskID      string    `json:"task_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-"`
+	UploadedBy  string    `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// AuditEvent records a security-relevant action.
+// ActorID is who performed the action; SubjectID is whose identity it was performed as.
+// They differ when an admin is impersonating a user or a client acts on a user's behalf via token exchange.
+type AuditEvent struct {
+	ID           string            `json:"id"`
+	Action       string            `json:"action"`
+	ActorID      string            `json:"actor_id"`
+	SubjectID    string            `json:"subject_id"`
+	Impersonated bool              `json:"impersonated"`
+	Method       string            `json:"method,omitempty"`
+	Path         string            `json:"path,omitempty"`
+	StatusCode   int               `json:"status_code,omitempty"`
+	RemoteAddr   string            `json:"remote_addr,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
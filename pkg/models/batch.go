@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// MaxBatchBytes caps the size of a batch request body
+const MaxBatchBytes = 1 << 20
+
+// BatchPath is the path of the batch endpoint, which sub-requests can't call
+const BatchPath = "/api/v1/batch"
+
+// BatchRequest is one sub-request of a batch
+type BatchRequest struct {
+	Method string `json:"method"`
+	// Path is an API path with an optional query, e.g. /api/v1/tasks?status=pending
+	Path string          `json:"path"`
+	Body json.RawMessage `json:"body,omitempty"`
+	// Headers are added to the caller's headers; they can't replace its credentials
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Batch is a list of sub-requests run one after another with the caller's credentials
+type Batch struct {
+	Requests []*BatchRequest `json:"requests"`
+}
+
+// Validate checks there are between 1 and max sub-requests to API paths
+func (b *Batch) Validate(max int) error {
+	if len(b.Requests) == 0 {
+		return errors.New("requests are required")
+	}
+	if len(b.Requests) > max {
+		return fmt.Errorf("at most %d requests can be batched", max)
+	}
+	for i, request := range b.Requests {
+		request.Method = strings.ToUpper(request.Method)
+		switch request.Method {
+		case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			return fmt.Errorf("request %d: method must be GET, POST, PUT, PATCH or DELETE", i)
+		}
+		// Checked as decoded, the way routes are matched
+		target, err := url.ParseRequestURI(request.Path)
+		if err != nil || target.Host != "" || !strings.HasPrefix(path.Clean(target.Path), "/api/") {
+			return fmt.Errorf("request %d: path must be an API path such as /api/v1/tasks", i)
+		}
+		if path.Clean(target.Path) == BatchPath {
+			return fmt.Errorf("request %d: batches can't be nested", i)
+		}
+	}
+	return nil
+}
+
+// BatchResponse is the response to one sub-request
+type BatchResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the response as JSON, or as a JSON string for other content
+	Body json.RawMessage `json:"body,omitempty"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "errors"
+
+// TaskMove places a task in a board column, between two of the column's tasks. Without
+// either neighbour the task goes to the bottom of the column.
+type TaskMove struct {
+	// Status is the column to move the task to; the task's own status when empty
+	Status TaskStatus `json:"status,omitempty"`
+	// AfterID is the task to place it below
+	AfterID string `json:"after_id,omitempty"`
+	// BeforeID is the task to place it above
+	BeforeID string `json:"before_id,omitempty"`
+}
+
+// Validate checks the column and that the task is not placed next to itself
+func (m *TaskMove) Validate(taskID string) error {
+	if m.Status != "" && !IsValidStatus(m.Status) {
+		return errors.New("invalid status")
+	}
+	if m.AfterID == taskID || m.BeforeID == taskID {
+		return errors.New("a task cannot be placed next to itself")
+	}
+	if m.AfterID != "" && m.AfterID == m.BeforeID {
+		return errors.New("after_id and before_id must be different tasks")
+	}
+	return nil
+}
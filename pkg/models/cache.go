@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// CacheTags name groups of cache keys operators can invalidate together, mapped to the
+// key pattern each covers
+var CacheTags = map[string]string{
+	"tasks":     "v1:tasks:*",
+	"progress":  "progress:*",
+	"ratelimit": "ratelimit:*",
+}
+
+// CacheInvalidation selects the cache keys to delete, by pattern or by tag
+type CacheInvalidation struct {
+	Pattern string `json:"pattern"`
+	Tag     string `json:"tag"`
+}
+
+// Validate checks that exactly one of pattern and tag is set and returns the key pattern
+// to delete
+func (c *CacheInvalidation) Validate() (string, error) {
+	pattern := strings.TrimSpace(c.Pattern)
+	switch {
+	case pattern != "" && c.Tag != "":
+		return "", errors.New("set either pattern or tag, not both")
+	case pattern != "":
+		return pattern, nil
+	case c.Tag == "":
+		return "", errors.New("pattern or tag is required")
+	}
+	if tagged, ok := CacheTags[c.Tag]; ok {
+		return tagged, nil
+	}
+	tags := make([]string, 0, len(CacheTags))
+	for tag := range CacheTags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return "", errors.New("tag must be one of " + strings.Join(tags, ", "))
+}
+
+// CacheInvalidationResult reports what an invalidation deleted
+type CacheInvalidationResult struct {
+	Pattern string `json:"pattern"`
+	Deleted int    `json:"deleted"`
+}
+
+// CacheStats describes the cache's contents and effectiveness. The hit counters are
+// Redis's own since it started, so they cover every client of the instance.
+type CacheStats struct {
+	TotalKeys       int            `json:"total_keys"`
+	KeysByPrefix    map[string]int `json:"keys_by_prefix"`
+	UsedMemoryBytes int64          `json:"used_memory_bytes"`
+	Hits            int64          `json:"hits"`
+	Misses          int64          `json:"misses"`
+	HitRatio        float64        `json:"hit_ratio"`
+}
+
+// TaskCacheTenant is the tenant segment of response cache keys for the organization
+func TaskCacheTenant(orgID string) string {
+	if orgID == "" {
+		return "default"
+	}
+	return "org-" + orgID
+}
+
+// TaskCachePattern matches every cached task response of the organization, lists and
+// single tasks alike
+func TaskCachePattern(orgID string) string {
+	return "v1:tasks:" + TaskCacheTenant(orgID) + ":*"
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ChecklistItem is one step of a task's checklist
+type ChecklistItem struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"task_id"`
+	Text      string    `json:"text"`
+	Done      bool      `json:"done"`
+	Position  int       `json:"position"` // items are listed by ascending position
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ChecklistItemInput represents the data required to add a checklist item
+type ChecklistItemInput struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// Validate checks the item text
+func (i *ChecklistItemInput) Validate() error {
+	text, err := checklistText(i.Text)
+	if err != nil {
+		return err
+	}
+	i.Text = text
+	return nil
+}
+
+// ChecklistItemUpdate represents the data that can be updated for a checklist item
+type ChecklistItemUpdate struct {
+	Text *string `json:"text,omitempty"`
+	Done *bool   `json:"done,omitempty"`
+}
+
+// Validate checks the item text if it is set
+func (u *ChecklistItemUpdate) Validate() error {
+	if u.Text == nil {
+		return nil
+	}
+	text, err := checklistText(*u.Text)
+	if err != nil {
+		return err
+	}
+	u.Text = &text
+	return nil
+}
+
+// ChecklistOrder lists every item of a checklist in its new order
+type ChecklistOrder struct {
+	ItemIDs []string `json:"item_ids"`
+}
+
+// ChecklistToggle checks or unchecks several items at once
+type ChecklistToggle struct {
+	ItemIDs []string `json:"item_ids,omitempty"`
+	All     bool     `json:"all,omitempty"` // applies to every item instead of ItemIDs
+	Done    bool     `json:"done"`
+}
+
+// Validate checks that the toggle names its items
+func (t *ChecklistToggle) Validate() error {
+	if t.All == (len(t.ItemIDs) > 0) {
+		return errors.New("either item_ids or all is required")
+	}
+	return nil
+}
+
+// ChecklistProgress summarizes a task's checklist
+type ChecklistProgress struct {
+	Total   int `json:"total"`
+	Done    int `json:"done"`
+	Percent int `json:"percent"` // share of done items, rounded down
+}
+
+// NewChecklistProgress returns the progress of a checklist, or nil if it has no items
+func NewChecklistProgress(total, done int) *ChecklistProgress {
+	if total == 0 {
+		return nil
+	}
+	return &ChecklistProgress{Total: total, Done: done, Percent: done * 100 / total}
+}
+
+// checklistText trims an item and checks its length
+func checklistText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("text is required")
+	}
+	if len(text) > MaxChecklistItemLength {
+		return "", fmt.Errorf("checklist items must be at most %d characters", MaxChecklistItemLength)
+	}
+	return text, nil
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// MaxClonedSubtasks bounds the subtasks copied along with a task
+const MaxClonedSubtasks = 100
+
+// TaskClone represents the options for duplicating a task
+type TaskClone struct {
+	Title string `json:"title,omitempty"` // defaults to the task's title
+	// DueDate defaults to the task's due date; subtasks keep their due dates relative to it
+	DueDate   *time.Time `json:"due_date,omitempty"`
+	Subtasks  bool       `json:"subtasks,omitempty"`  // also copy the task's subtasks, recursively
+	Checklist bool       `json:"checklist,omitempty"` // also copy checklist items, unchecked
+}
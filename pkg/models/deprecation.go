@@ -0,0 +1,168 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DeprecationKind is what a deprecation retires
+type DeprecationKind string
+
+const (
+	DeprecatedEndpoint  DeprecationKind = "endpoint"
+	DeprecatedParameter DeprecationKind = "parameter"
+	DeprecatedVersion   DeprecationKind = "version"
+)
+
+// DeprecationNoticeEvent is the webhook event sent to integrators still using a
+// deprecation as its sunset date approaches
+const DeprecationNoticeEvent = "deprecation.notice"
+
+// maxDeprecationUserAgentLength bounds the user agents kept as client names
+const maxDeprecationUserAgentLength = 255
+
+// Deprecation is an endpoint, query parameter or API version that stops working at
+// its sunset date. Deprecations are declared by maintainers in DEPRECATIONS_FILE.
+type Deprecation struct {
+	// ID names the deprecation in reports and notices, e.g. "tasks-per-page"
+	ID   string          `json:"id"`
+	Kind DeprecationKind `json:"kind"`
+	// Route is the route template of deprecated endpoints and parameters, e.g. /api/v1/tasks/{id}
+	Route string `json:"route,omitempty"`
+	// Methods limits the deprecation to these methods; empty means any
+	Methods   []string `json:"methods,omitempty"`
+	Parameter string   `json:"parameter,omitempty"`
+	// Version is a deprecated API version, matched against the /api/{version}/ path prefix
+	Version string    `json:"version,omitempty"`
+	Sunset  time.Time `json:"sunset"`
+	// Replacement tells integrators what to use instead
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// Validate checks that the deprecation says what it retires and when
+func (d *Deprecation) Validate() error {
+	if d.ID == "" {
+		return errors.New("id is required")
+	}
+	if d.Sunset.IsZero() {
+		return fmt.Errorf("deprecation %s needs a sunset date", d.ID)
+	}
+
+	switch d.Kind {
+	case DeprecatedEndpoint:
+		if d.Route == "" {
+			return fmt.Errorf("deprecated endpoint %s needs a route", d.ID)
+		}
+	case DeprecatedParameter:
+		if d.Route == "" || d.Parameter == "" {
+			return fmt.Errorf("deprecated parameter %s needs a route and a parameter", d.ID)
+		}
+	case DeprecatedVersion:
+		if d.Version == "" || strings.Contains(d.Version, "/") {
+			return fmt.Errorf("deprecated version %s needs a version, e.g. v1", d.ID)
+		}
+	default:
+		return fmt.Errorf("deprecation %s has kind %q, expected endpoint, parameter or version", d.ID, d.Kind)
+	}
+	return nil
+}
+
+// Matches reports whether a request uses the deprecation. route is the template of the
+// route the request matched, or its path when it matched none.
+func (d *Deprecation) Matches(method, route, path string, query url.Values) bool {
+	if len(d.Methods) > 0 {
+		allowed := false
+		for _, m := range d.Methods {
+			allowed = allowed || strings.EqualFold(m, method)
+		}
+		if !allowed {
+			return false
+		}
+	}
+
+	switch d.Kind {
+	case DeprecatedEndpoint:
+		return route == d.Route
+	case DeprecatedParameter:
+		return route == d.Route && query.Has(d.Parameter)
+	case DeprecatedVersion:
+		return strings.HasPrefix(path, "/api/"+d.Version+"/")
+	}
+	return false
+}
+
+// ParseDeprecations parses a JSON list of deprecations, as kept in DEPRECATIONS_FILE
+func ParseDeprecations(data []byte) ([]Deprecation, error) {
+	var deprecations []Deprecation
+	if err := json.Unmarshal(data, &deprecations); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for i := range deprecations {
+		if err := deprecations[i].Validate(); err != nil {
+			return nil, err
+		}
+		if seen[deprecations[i].ID] {
+			return nil, fmt.Errorf("deprecation %s is declared twice", deprecations[i].ID)
+		}
+		seen[deprecations[i].ID] = true
+	}
+	return deprecations, nil
+}
+
+// DeprecationClientKind is how a client using a deprecation is identified
+type DeprecationClientKind string
+
+const (
+	// DeprecationClientAPIKey identifies integrations by the ID of their API key
+	DeprecationClientAPIKey DeprecationClientKind = "api_key"
+	// DeprecationClientUserAgent identifies other clients by their User-Agent header
+	DeprecationClientUserAgent DeprecationClientKind = "user_agent"
+)
+
+// DeprecationClient returns how to identify a client: by its API key when it has one,
+// otherwise by its user agent
+func DeprecationClient(apiKeyID, userAgent string) (DeprecationClientKind, string) {
+	if apiKeyID != "" {
+		return DeprecationClientAPIKey, apiKeyID
+	}
+	if len(userAgent) > maxDeprecationUserAgentLength {
+		userAgent = userAgent[:maxDeprecationUserAgentLength]
+	}
+	return DeprecationClientUserAgent, userAgent
+}
+
+// DeprecationUsage counts the requests a client made as a user to a deprecation
+type DeprecationUsage struct {
+	DeprecationID string                `json:"deprecation_id"`
+	ClientKind    DeprecationClientKind `json:"client_kind"`
+	// Client is the API key ID or the user agent, which may be empty
+	Client string `json:"client"`
+	// UserID is empty for requests that weren't authenticated
+	UserID      string    `json:"user_id,omitempty"`
+	OrgID       string    `json:"org_id,omitempty"`
+	Requests    int64     `json:"requests"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	// NotifiedAt is when the user was told about the sunset date
+	NotifiedAt *time.Time `json:"notified_at,omitempty"`
+}
+
+// DeprecationReport is a deprecation with the clients still using it, most requests first
+type DeprecationReport struct {
+	Deprecation
+	Requests int64               `json:"requests"`
+	Clients  []*DeprecationUsage `json:"clients"`
+}
+
+// WebhookDeprecationData is the data of deprecation.notice events
+type WebhookDeprecationData struct {
+	Deprecation *Deprecation `json:"deprecation"`
+	// Clients are the receiver's clients that used the deprecation
+	Clients []*DeprecationUsage `json:"clients"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"sample/task-management-system/pkg/textdiff"
+)
+
+// MaxDescriptionVersions is the number of description versions kept per task; older
+// versions are dropped as new ones are saved
+const MaxDescriptionVersions = 100
+
+// DescriptionVersion is a task's description as saved by one edit
+type DescriptionVersion struct {
+	TaskID      string    `json:"task_id"`
+	Version     int       `json:"version"` // numbered from 1 in the order of edits
+	Description string    `json:"description"`
+	EditorID    string    `json:"editor_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	// Diff lists the line changes from the previous kept version
+	Diff []textdiff.Line `json:"diff,omitempty"`
+}
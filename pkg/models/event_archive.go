@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// ReplaySinkWebhook replays events to a webhook subscription
+	ReplaySinkWebhook = "webhook"
+	// ReplaySinkKafka names a Kafka topic; this build has no Kafka producer, so it is rejected
+	ReplaySinkKafka = "kafka"
+	// MaxReplayEvents is the most events one replay request sends; longer ranges are resumed
+	// with the returned cursor
+	MaxReplayEvents = 1000
+)
+
+// ArchivedEvent is a task event kept in the event archive for replay
+type ArchivedEvent struct {
+	// ID is the event's position in the archive, increasing in the order it was archived
+	ID      int64  `json:"id"`
+	EventID string `json:"event_id"`
+	Type    string `json:"type"`
+	TaskID  string `json:"task_id"`
+	// Task is the task after the change; nil for task.deleted
+	Task       *Task     `json:"task,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ReplaySink is where replayed events are sent
+type ReplaySink struct {
+	Type      string `json:"type"`
+	WebhookID string `json:"webhook_id,omitempty"`
+	Topic     string `json:"topic,omitempty"`
+}
+
+// ReplayRequest asks to send the archived events that occurred in [From, To) to a sink again
+type ReplayRequest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Types limits the replay to some event types; empty replays every type the sink takes
+	Types []string   `json:"types,omitempty"`
+	Sink  ReplaySink `json:"sink"`
+	// After resumes an earlier replay after the archived event with this ID
+	After int64 `json:"after,omitempty"`
+}
+
+// Validate checks if the replay request is valid
+func (r *ReplayRequest) Validate() error {
+	if r.From.IsZero() || r.To.IsZero() {
+		return errors.New("from and to are required")
+	}
+	if !r.From.Before(r.To) {
+		return errors.New("from must be before to")
+	}
+	switch r.Sink.Type {
+	case ReplaySinkWebhook:
+		if r.Sink.WebhookID == "" {
+			return errors.New("webhook sinks need a webhook_id")
+		}
+	case ReplaySinkKafka:
+		return errors.New("kafka sinks are not available: no Kafka producer is configured")
+	default:
+		return errors.New("sink type must be webhook")
+	}
+	return nil
+}
+
+// ReplayResult reports how far a replay got
+type ReplayResult struct {
+	Replayed int `json:"replayed"`
+	// Complete is false when the replay stopped early, at MaxReplayEvents or on a failed
+	// delivery; it is resumed by passing NextAfter as after
+	Complete  bool   `json:"complete"`
+	NextAfter int64  `json:"next_after,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
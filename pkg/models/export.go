@@ -0,0 +1,194 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportKind is what an export job produces
+type ExportKind string
+
+const (
+	// ExportTasks is a listing of tasks matching a filter
+	ExportTasks ExportKind = "tasks"
+	// ExportReport is the result of a saved report; only admins may request it
+	ExportReport ExportKind = "report"
+	// ExportAccount is everything stored about the requester, for data access requests
+	ExportAccount ExportKind = "account"
+)
+
+// ExportFormat is the file format of an export
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json"
+	// ExportNDJSON is one JSON task per line; only streamed task exports offer it
+	ExportNDJSON ExportFormat = "ndjson"
+)
+
+// ExportStatus is where an export job is in the queue
+type ExportStatus string
+
+const (
+	ExportPending   ExportStatus = "pending"
+	ExportRunning   ExportStatus = "running"
+	ExportCompleted ExportStatus = "completed"
+	ExportFailed    ExportStatus = "failed"
+)
+
+const (
+	// MaxExportTasks is the most tasks a single export may contain
+	MaxExportTasks = 50000
+	// MaxExportAttempts is how many times a job runs before it is given up on, unless
+	// its retry policy says otherwise
+	MaxExportAttempts = 3
+	// ExportRetention is how long a finished export can be downloaded
+	ExportRetention = 7 * 24 * time.Hour
+)
+
+// ExportTaskFilter selects the tasks of a tasks export, like the task listing's filters
+type ExportTaskFilter struct {
+	Status     []TaskStatus `json:"status,omitempty"`
+	Priority   TaskPriority `json:"priority,omitempty"`
+	AssigneeID string       `json:"assignee_id,omitempty"`
+	Tags       []string     `json:"tags,omitempty"`
+}
+
+// ExportRequest represents the data required to queue an export
+type ExportRequest struct {
+	Kind     ExportKind        `json:"kind"`
+	Format   ExportFormat      `json:"format,omitempty"`    // csv by default; account exports are always json
+	Filter   *ExportTaskFilter `json:"filter,omitempty"`    // tasks exports only
+	ReportID string            `json:"report_id,omitempty"` // report exports only
+}
+
+// Validate checks the request, defaulting the format
+func (r *ExportRequest) Validate() error {
+	switch r.Kind {
+	case ExportTasks:
+		if r.ReportID != "" {
+			return errors.New("report_id is only accepted for report exports")
+		}
+		if r.Filter != nil {
+			for _, status := range r.Filter.Status {
+				if !IsValidStatus(status) {
+					return fmt.Errorf("invalid status %q", status)
+				}
+			}
+			if r.Filter.Priority != "" && !IsValidPriority(r.Filter.Priority) {
+				return errors.New("invalid priority")
+			}
+		}
+	case ExportReport:
+		if r.ReportID == "" {
+			return errors.New("report_id is required for report exports")
+		}
+		if r.Filter != nil {
+			return errors.New("filter is only accepted for tasks exports")
+		}
+	case ExportAccount:
+		if r.Filter != nil || r.ReportID != "" {
+			return errors.New("account exports take no filter or report_id")
+		}
+		if r.Format == "" {
+			r.Format = ExportJSON
+		}
+		if r.Format != ExportJSON {
+			return errors.New("account exports are only available as json")
+		}
+	default:
+		return errors.New("kind must be tasks, report or account")
+	}
+
+	if r.Format == "" {
+		r.Format = ExportCSV
+	}
+	if r.Format != ExportCSV && r.Format != ExportJSON {
+		return errors.New("format must be csv or json")
+	}
+	return nil
+}
+
+// ExportJob is a queued export and, once it completes, the file it produced
+type ExportJob struct {
+	ID          string        `json:"id"`
+	Request     ExportRequest `json:"request"`
+	RequestedBy string        `json:"requested_by"`
+	Status      ExportStatus  `json:"status"`
+	Progress    int           `json:"progress"` // percent done
+	Error       string        `json:"error,omitempty"`
+	FileName    string        `json:"file_name,omitempty"`
+	ContentType string        `json:"content_type,omitempty"`
+	Size        int64         `json:"size,omitempty"`
+	// DownloadURL is set on completed exports; it is a presigned URL when the file is in S3
+	DownloadURL string     `json:"download_url,omitempty"`
+	StorageKey  string     `json:"-"`
+	Attempts    int        `json:"-"`
+	OrgID       string     `json:"-"` // tenant the export was requested in; it only sees its tasks
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // after which the file is deleted
+	// RetryAt is when a pending job that failed is tried again
+	RetryAt *time.Time `json:"retry_at,omitempty"`
+}
+
+// ExportFile is the output of a finished export. Content is empty when the file was
+// uploaded to StorageKey.
+type ExportFile struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	StorageKey  string
+	Content     []byte
+}
+
+// ErrInvalidExportCursor is returned for cursors not handed out by a streamed export
+var ErrInvalidExportCursor = errors.New("invalid export cursor")
+
+// exportCursorPrefix versions cursors, so their encoding can change
+const exportCursorPrefix = "v1:"
+
+// ExportCursor is where a streamed export that was cut short resumes: the number of tasks
+// in the filter's order that were already delivered
+type ExportCursor struct {
+	Offset int
+}
+
+// String encodes the cursor for clients, who pass it back unchanged
+func (c ExportCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(exportCursorPrefix + strconv.Itoa(c.Offset)))
+}
+
+// ParseExportCursor decodes a cursor returned by ExportCursor.String; "" starts at the
+// first task
+func ParseExportCursor(value string) (ExportCursor, error) {
+	if value == "" {
+		return ExportCursor{}, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || !strings.HasPrefix(string(decoded), exportCursorPrefix) {
+		return ExportCursor{}, ErrInvalidExportCursor
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(decoded), exportCursorPrefix))
+	if err != nil || offset < 0 {
+		return ExportCursor{}, ErrInvalidExportCursor
+	}
+	return ExportCursor{Offset: offset}, nil
+}
+
+// ExportPacing bounds how long a streamed export may hold its database cursor, so a slow
+// client cannot keep a transaction and connection open indefinitely. Once either limit
+// is reached the export stops at the next task and hands back a cursor to resume from.
+// Zero limits are not enforced.
+type ExportPacing struct {
+	// MaxDuration is how long the cursor may stay open
+	MaxDuration time.Duration
+	// MaxStall is how long, in total, the export may wait for the client to accept writes
+	MaxStall time.Duration
+}
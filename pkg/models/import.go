@@ -0,0 +1,125 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ImportSource is the layout of an imported file
+type ImportSource string
+
+const (
+	// ImportCSV is a CSV file with a header of task field names, see pkg/importer
+	ImportCSV ImportSource = "csv"
+	// ImportJira is a CSV export of Jira issues
+	ImportJira ImportSource = "jira"
+)
+
+// ImportStatus is where an import job is in the queue
+type ImportStatus string
+
+const (
+	ImportPending   ImportStatus = "pending"
+	ImportRunning   ImportStatus = "running"
+	ImportCompleted ImportStatus = "completed"
+	ImportFailed    ImportStatus = "failed"
+	ImportCancelled ImportStatus = "cancelled"
+)
+
+const (
+	// MaxImportBytes is the largest file accepted for import
+	MaxImportBytes = 10 << 20
+	// MaxImportRows is the most tasks a single import may create
+	MaxImportRows = 10000
+	// MaxImportAttempts is how many times a job runs before it is given up on, unless
+	// its retry policy says otherwise
+	MaxImportAttempts = 3
+	// MaxTaskImportBytes is the largest file imported while the caller waits
+	MaxTaskImportBytes = 1 << 20
+	// MaxTaskImportRows is the most rows imported while the caller waits; larger files
+	// go through import jobs
+	MaxTaskImportRows = 1000
+)
+
+// ImportOptions are the choices made when uploading a file for import
+type ImportOptions struct {
+	Source ImportSource `json:"source"`
+	// ProjectKey is the project of rows that don't name one; defaults to DefaultProjectKey
+	ProjectKey string `json:"project_key,omitempty"`
+	// DefaultDueDate is the due date of rows without one; such rows fail without it
+	DefaultDueDate *time.Time `json:"default_due_date,omitempty"`
+	// Columns maps header names of csv files to task fields, for files whose header
+	// doesn't use the field names; columns mapped to "" are ignored
+	Columns map[string]string `json:"columns,omitempty"`
+}
+
+// Validate checks the options, normalizing the project key
+func (o *ImportOptions) Validate() error {
+	switch o.Source {
+	case ImportCSV, ImportJira:
+	default:
+		return errors.New("source must be csv or jira")
+	}
+	if len(o.Columns) > 0 && o.Source != ImportCSV {
+		return errors.New("columns can only be mapped for csv files")
+	}
+	if o.ProjectKey == "" {
+		o.ProjectKey = DefaultProjectKey
+	}
+	projectKey, err := NormalizeProjectKey(o.ProjectKey)
+	if err != nil {
+		return err
+	}
+	o.ProjectKey = projectKey
+	return nil
+}
+
+// ImportJob is an uploaded file whose rows are turned into tasks in the background.
+// Rows are processed in chunks; ProcessedRows is the checkpoint a resumed job starts from.
+type ImportJob struct {
+	ID            string        `json:"id"`
+	FileName      string        `json:"file_name"`
+	Options       ImportOptions `json:"options"`
+	RequestedBy   string        `json:"requested_by"`
+	Status        ImportStatus  `json:"status"`
+	TotalRows     int           `json:"total_rows"`
+	ProcessedRows int           `json:"processed_rows"`
+	CreatedCount  int           `json:"created_count"`
+	FailedCount   int           `json:"failed_count"` // see the job's failures for why
+	Progress      int           `json:"progress"`     // percent of the rows processed
+	Error         string        `json:"error,omitempty"`
+	Attempts      int           `json:"-"`
+	OrgID         string        `json:"-"` // tenant the import was requested in; tasks are created in it
+	CreatedAt     time.Time     `json:"created_at"`
+	StartedAt     *time.Time    `json:"started_at,omitempty"`
+	CompletedAt   *time.Time    `json:"completed_at,omitempty"`
+	// RetryAt is when a pending job that failed is tried again
+	RetryAt *time.Time `json:"retry_at,omitempty"`
+}
+
+// ComputeProgress sets Progress from the processed and total rows
+func (j *ImportJob) ComputeProgress() {
+	switch {
+	case j.Status == ImportCompleted:
+		j.Progress = 100
+	case j.TotalRows > 0:
+		j.Progress = j.ProcessedRows * 100 / j.TotalRows
+	default:
+		j.Progress = 0
+	}
+}
+
+// ImportRowResult is the outcome of importing one row; rows are numbered from 1,
+// after the header
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	TaskID string `json:"task_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// TaskImportResult is the outcome of a file imported while the caller waited
+type TaskImportResult struct {
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"` // every row, in file order
+}
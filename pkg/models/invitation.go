@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// InvitationTTL is how long an invitation can be accepted
+const InvitationTTL = 7 * 24 * time.Hour
+
+// InvitationRoles are the roles a user may be invited into an organization with
+var InvitationRoles = map[string]bool{
+	"viewer":    true,
+	"user":      true,
+	"org_admin": true,
+}
+
+var (
+	// ErrInvitationNotFound is returned for unknown invitations and tokens
+	ErrInvitationNotFound = errors.New("invitation not found")
+	// ErrInvitationUnavailable is returned when accepting an expired or already accepted invitation
+	ErrInvitationUnavailable = errors.New("invitation has expired or was already accepted")
+	// ErrMembershipNotFound is returned for users who are not a member of any organization
+	ErrMembershipNotFound = errors.New("membership not found")
+)
+
+// Invitation invites the owner of an email address to join an organization
+type Invitation struct {
+	ID         string     `json:"id"`
+	OrgID      string     `json:"org_id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	InvitedBy  string     `json:"invited_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedBy string     `json:"accepted_by,omitempty"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+}
+
+// InvitationCreate represents the data required to invite a user
+type InvitationCreate struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// Validate checks the email address and role; the role defaults to user
+func (i *InvitationCreate) Validate() error {
+	address, err := mail.ParseAddress(strings.TrimSpace(i.Email))
+	if err != nil || address.Name != "" {
+		return errors.New("a valid email is required")
+	}
+	i.Email = strings.ToLower(address.Address)
+	if i.Role == "" {
+		i.Role = "user"
+	}
+	if !InvitationRoles[i.Role] {
+		return errors.New("role must be viewer, user or org_admin")
+	}
+	return nil
+}
+
+// InvitationAccept carries the token of the invitation being accepted
+type InvitationAccept struct {
+	Token string `json:"token"`
+}
+
+// OrgMembership is a user's membership of an organization
+type OrgMembership struct {
+	OrgID    string    `json:"org_id"`
+	UserID   string    `json:"user_id"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
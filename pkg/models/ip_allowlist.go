@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// IPAllowlistEntry is a CIDR range an organization's users may connect from
+type IPAllowlistEntry struct {
+	ID          string    `json:"id"`
+	OrgID       string    `json:"org_id"`
+	CIDR        string    `json:"cidr"`
+	Description string    `json:"description"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// IPAllowlistEntryCreate represents the data required to add an allowlist entry
+type IPAllowlistEntryCreate struct {
+	CIDR        string `json:"cidr"`
+	Description string `json:"description"`
+}
+
+// Validate checks the entry and normalizes CIDR; a bare IP becomes a single-address range
+func (e *IPAllowlistEntryCreate) Validate() error {
+	cidr := strings.TrimSpace(e.CIDR)
+	if cidr == "" {
+		return errors.New("cidr is required")
+	}
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return errors.New("invalid cidr")
+		}
+		if ip.To4() != nil {
+			cidr += "/32"
+		} else {
+			cidr += "/128"
+		}
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return errors.New("invalid cidr")
+	}
+	e.CIDR = network.String()
+	if len(e.Description) > 255 {
+		return errors.New("description must be at most 255 characters")
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JobType names a kind of background job
+type JobType string
+
+const (
+	JobExport JobType = "export"
+	JobImport JobType = "import"
+)
+
+// IsValidJobType reports whether jobType is a known job type
+func IsValidJobType(jobType JobType) bool {
+	return jobType == JobExport || jobType == JobImport
+}
+
+// BackoffCurve is how the wait between attempts grows
+type BackoffCurve string
+
+const (
+	// BackoffConstant waits the base delay before every retry
+	BackoffConstant BackoffCurve = "constant"
+	// BackoffLinear waits the base delay times the number of attempts so far
+	BackoffLinear BackoffCurve = "linear"
+	// BackoffExponential doubles the wait after every attempt
+	BackoffExponential BackoffCurve = "exponential"
+)
+
+// RetryPolicy decides whether and when a failed job is tried again
+type RetryPolicy struct {
+	// MaxAttempts is how many times a job runs before it is dead-lettered
+	MaxAttempts int
+	Curve       BackoffCurve
+	// Delay is the wait before the first retry
+	Delay time.Duration
+	// MaxDelay caps the wait; 0 leaves it uncapped
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicies apply to job types JOB_RETRY_POLICIES leaves out
+var DefaultRetryPolicies = map[JobType]RetryPolicy{
+	JobExport: {MaxAttempts: MaxExportAttempts, Curve: BackoffExponential, Delay: time.Minute, MaxDelay: 15 * time.Minute},
+	JobImport: {MaxAttempts: MaxImportAttempts, Curve: BackoffExponential, Delay: time.Minute, MaxDelay: 15 * time.Minute},
+}
+
+// Retries reports whether a job that failed its attempts-th run is tried again
+func (p RetryPolicy) Retries(attempts int) bool {
+	return attempts < p.MaxAttempts
+}
+
+// Backoff is the wait before the retry following a job's attempts-th run
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := p.Delay
+	switch p.Curve {
+	case BackoffLinear:
+		delay = p.Delay * time.Duration(attempts)
+	case BackoffExponential:
+		factor := math.Pow(2, float64(attempts-1))
+		if float64(p.Delay)*factor >= math.MaxInt64 {
+			delay = time.Duration(math.MaxInt64)
+		} else {
+			delay = time.Duration(float64(p.Delay) * factor)
+		}
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// ParseRetryPolicies parses retry policies such as "export=5/exponential/30s/30m": each
+// entry is type=attempts/curve/delay/max delay, where trailing parts may be left out.
+// Job types not listed keep their DefaultRetryPolicies.
+func ParseRetryPolicies(value string) (map[JobType]RetryPolicy, error) {
+	policies := make(map[JobType]RetryPolicy, len(DefaultRetryPolicies))
+	for jobType, policy := range DefaultRetryPolicies {
+		policies[jobType] = policy
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		jobType := JobType(strings.TrimSpace(parts[0]))
+		if len(parts) != 2 || !IsValidJobType(jobType) {
+			return nil, fmt.Errorf("invalid retry policy %q, expected export or import=attempts/curve/delay/max delay", entry)
+		}
+		fields := strings.Split(parts[1], "/")
+		if len(fields) > 4 {
+			return nil, fmt.Errorf("invalid retry policy %q, expected type=attempts/curve/delay/max delay", entry)
+		}
+
+		policy := policies[jobType]
+		var err error
+		if policy.MaxAttempts, err = strconv.Atoi(fields[0]); err != nil || policy.MaxAttempts < 1 {
+			return nil, fmt.Errorf("invalid retry policy %q: attempts must be a positive number", entry)
+		}
+		if len(fields) > 1 {
+			policy.Curve = BackoffCurve(fields[1])
+			if policy.Curve != BackoffConstant && policy.Curve != BackoffLinear && policy.Curve != BackoffExponential {
+				return nil, fmt.Errorf("invalid retry policy %q: curve must be constant, linear or exponential", entry)
+			}
+		}
+		if len(fields) > 2 {
+			if policy.Delay, err = time.ParseDuration(fields[2]); err != nil || policy.Delay < 0 {
+				return nil, fmt.Errorf("invalid retry policy %q: invalid delay", entry)
+			}
+		}
+		if len(fields) > 3 {
+			if policy.MaxDelay, err = time.ParseDuration(fields[3]); err != nil || policy.MaxDelay < 0 {
+				return nil, fmt.Errorf("invalid retry policy %q: invalid max delay", entry)
+			}
+		}
+		policies[jobType] = policy
+	}
+	return policies, nil
+}
+
+// DeadLetter is a job given up on after its retry policy ran out, kept for admins to
+// inspect and requeue
+type DeadLetter struct {
+	JobType  JobType   `json:"job_type"`
+	JobID    string    `json:"job_id"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error,omitempty"`
+	DeadAt   time.Time `json:"dead_at"`
+	// RequeuedAt is set once an admin queued the job again
+	RequeuedAt *time.Time `json:"requeued_at,omitempty"`
+	// Job is the export or import job, when inspected on its own
+	Job interface{} `json:"job,omitempty"`
+}
+
+// ErrJobNotRequeueable is returned when a dead-lettered job is gone or already queued again
+var ErrJobNotRequeueable = errors.New("job can no longer be requeued")
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// OrgKPIs are an organization's business metrics over a reporting window. OrgID is
+// empty for tasks created outside any organization.
+type OrgKPIs struct {
+	OrgID string
+	// TasksCreated counts the tasks created in the window, drafts left out
+	TasksCreated int
+	// TasksCompleted counts the tasks completed in the window
+	TasksCompleted int
+	// Overdue counts the open tasks past their due date at the end of the window
+	Overdue int
+	// AvgCycleTime is how long the tasks completed in the window took on average, from
+	// when work on them started, or their creation if it never did, to completion
+	AvgCycleTime time.Duration
+}
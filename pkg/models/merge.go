@@ -0,0 +1,31 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// TaskMergeRequest names the duplicate task to fold into the target
+type TaskMergeRequest struct {
+	SourceID string `json:"source_id"`
+}
+
+// Validate checks the source task
+func (r *TaskMergeRequest) Validate() error {
+	r.SourceID = strings.TrimSpace(r.SourceID)
+	if r.SourceID == "" {
+		return errors.New("source_id is required")
+	}
+	return nil
+}
+
+// TaskMerge is the outcome of folding a duplicate task into another. The source is
+// cancelled and marked a duplicate_of the target, which is its pointer to the merged task.
+type TaskMerge struct {
+	Task   *Task `json:"task"`
+	Source *Task `json:"source"`
+	// MovedAttachments and MovedWatchers count what the target took over; users who
+	// starred both tasks are counted once
+	MovedAttachments int `json:"moved_attachments"`
+	MovedWatchers    int `json:"moved_watchers"`
+}
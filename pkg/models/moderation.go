@@ -0,0 +1,40 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// FlagStatus represents the review state of flagged content
+type FlagStatus string
+
+const (
+	FlagPending  FlagStatus = "pending"
+	FlagApproved FlagStatus = "approved"
+	FlagRejected FlagStatus = "rejected"
+)
+
+// ModerationFlag represents user-generated content awaiting admin review
+type ModerationFlag struct {
+	ID           string     `json:"id"`
+	ResourceType string     `json:"resource_type"`
+	ResourceID   string     `json:"resource_id"`
+	Reasons      []string   `json:"reasons"`
+	Status       FlagStatus `json:"status"`
+	ReviewedBy   *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt   *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// FlagReview represents an admin decision on a flag
+type FlagReview struct {
+	Status FlagStatus `json:"status"`
+}
+
+// Validate checks if the flag review request is valid
+func (r *FlagReview) Validate() error {
+	if r.Status != FlagApproved && r.Status != FlagRejected {
+		return errors.New("status must be approved or rejected")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"sample/task-management-system/pkg/auth"
+)
+
+// OAuthClient represents a backend integration using the client_credentials grant
+type OAuthClient struct {
+	ID                 string     `json:"id"`
+	ClientID           string     `json:"client_id"`
+	SecretHash         string     `json:"-"`
+	Name               string     `json:"name"`
+	Roles              []string   `json:"roles"`
+	Scopes             []string   `json:"scopes"`
+	AllowTokenExchange bool       `json:"allow_token_exchange"` // may act on behalf of users via token exchange
+	CreatedAt          time.Time  `json:"created_at"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty"`
+}
+
+// OAuthClientCreate represents the data required to register a new client
+type OAuthClientCreate struct {
+	Name               string   `json:"name"`
+	Roles              []string `json:"roles"`
+	Scopes             []string `json:"scopes"`
+	AllowTokenExchange bool     `json:"allow_token_exchange"`
+}
+
+// Validate checks if the client create request is valid
+func (c *OAuthClientCreate) Validate() error {
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(c.Roles) == 0 {
+		return errors.New("at least one role is required")
+	}
+	// A client without scopes would be limited by its roles alone
+	if len(c.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	for _, scope := range c.Scopes {
+		if !slices.Contains(auth.KnownScopes, scope) {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// OIDCLoginTTL is how long a user has to complete a sign-in at an OpenID Connect provider
+const OIDCLoginTTL = 10 * time.Minute
+
+// OIDCIdentity links a user of an OpenID Connect provider to a local user
+type OIDCIdentity struct {
+	Provider    string    `json:"provider"`
+	Subject     string    `json:"subject"`
+	UserID      string    `json:"user_id"`
+	Email       string    `json:"email,omitempty"`
+	Roles       []string  `json:"roles"` // granted by the provider at the last sign-in
+	CreatedAt   time.Time `json:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at"`
+}
+
+// ErrOIDCProviderNotFound is returned for sign-ins with a provider that isn't configured
+var ErrOIDCProviderNotFound = errors.New("identity provider not found")
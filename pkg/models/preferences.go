@@ -0,0 +1,157 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DigestFrequency is how often a user receives the deadlines digest
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "off"
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly" // sent on Mondays
+)
+
+// DefaultDigestHour is the local hour digests are sent at unless the user picks another
+const DefaultDigestHour = 8
+
+const (
+	// DefaultLocale is the locale of users who never picked one
+	DefaultLocale = "en"
+	// DefaultPageSize is how many items a list returns when the request sets no limit and
+	// the user picked no page size
+	DefaultPageSize = 10
+	// MaxPageSize is the largest page size a user may pick
+	MaxPageSize = 100
+)
+
+// localePattern matches BCP 47 language tags such as "en", "de-CH" or "zh-Hant-TW"
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// NotificationPreferences are a user's settings: notifications by email and how the API
+// presents data to them
+type NotificationPreferences struct {
+	UserID     string          `json:"user_id"`
+	Email      string          `json:"email"`
+	Digest     DigestFrequency `json:"digest"`
+	Timezone   string          `json:"timezone"`    // IANA name, e.g. Europe/Berlin
+	DigestHour int             `json:"digest_hour"` // local hour, 0-23
+	Locale     string          `json:"locale"`      // BCP 47 tag, e.g. de-CH
+	// PageSize is how many tasks a list returns when the request sets no limit
+	PageSize int `json:"page_size"`
+	// StaleAlerts emails the user when their tasks go stale, if they have an email
+	StaleAlerts bool `json:"stale_alerts"`
+	// LastDigestAt is when the user's latest digest was sent
+	LastDigestAt *time.Time `json:"last_digest_at,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"` // nil until the preferences are first saved
+}
+
+// DefaultNotificationPreferences returns the preferences of a user who never changed them
+func DefaultNotificationPreferences(userID string) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:      userID,
+		Digest:      DigestOff,
+		Timezone:    "UTC",
+		DigestHour:  DefaultDigestHour,
+		Locale:      DefaultLocale,
+		PageSize:    DefaultPageSize,
+		StaleAlerts: true,
+	}
+}
+
+// Location returns the user's time zone, falling back to UTC for unknown names
+func (p *NotificationPreferences) Location() *time.Location {
+	location, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// PreferencesUpdate represents a change to a user's preferences; fields that are not set
+// keep their value
+type PreferencesUpdate struct {
+	Email       *string          `json:"email,omitempty"`
+	Digest      *DigestFrequency `json:"digest,omitempty"`
+	Timezone    *string          `json:"timezone,omitempty"`
+	DigestHour  *int             `json:"digest_hour,omitempty"`
+	Locale      *string          `json:"locale,omitempty"`
+	PageSize    *int             `json:"page_size,omitempty"`
+	StaleAlerts *bool            `json:"stale_alerts,omitempty"`
+}
+
+// Apply validates the update and applies it to preferences
+func (u *PreferencesUpdate) Apply(preferences *NotificationPreferences) error {
+	if u.Email != nil {
+		email := strings.TrimSpace(*u.Email)
+		if email != "" {
+			address, err := mail.ParseAddress(email)
+			if err != nil || address.Address != email || len(email) > 255 {
+				return errors.New("invalid email address")
+			}
+		}
+		preferences.Email = email
+	}
+	if u.Digest != nil {
+		switch *u.Digest {
+		case DigestOff, DigestDaily, DigestWeekly:
+		default:
+			return errors.New("digest must be off, daily or weekly")
+		}
+		preferences.Digest = *u.Digest
+	}
+	if u.Timezone != nil {
+		if _, err := time.LoadLocation(*u.Timezone); err != nil || *u.Timezone == "" || *u.Timezone == "Local" {
+			return fmt.Errorf("unknown timezone %q", *u.Timezone)
+		}
+		preferences.Timezone = *u.Timezone
+	}
+	if u.DigestHour != nil {
+		if *u.DigestHour < 0 || *u.DigestHour > 23 {
+			return errors.New("digest_hour must be between 0 and 23")
+		}
+		preferences.DigestHour = *u.DigestHour
+	}
+	if u.Locale != nil {
+		if !localePattern.MatchString(*u.Locale) {
+			return fmt.Errorf("invalid locale %q, expected a language tag such as en or de-CH", *u.Locale)
+		}
+		preferences.Locale = *u.Locale
+	}
+	if u.PageSize != nil {
+		if *u.PageSize < 1 || *u.PageSize > MaxPageSize {
+			return fmt.Errorf("page_size must be between 1 and %d", MaxPageSize)
+		}
+		preferences.PageSize = *u.PageSize
+	}
+	if u.StaleAlerts != nil {
+		preferences.StaleAlerts = *u.StaleAlerts
+	}
+
+	if preferences.Digest != DigestOff && preferences.Email == "" {
+		return errors.New("an email address is required for digests")
+	}
+	return nil
+}
+
+// Digest is the summary of a user's deadlines sent by email
+type Digest struct {
+	Frequency        DigestFrequency
+	DueSoon          []*Task // due before the next digest
+	Overdue          []*Task
+	RecentlyAssigned []*Task // assigned to the user since the previous digest
+	UnsubscribeURL   string
+	Location         *time.Location // due dates are shown in the user's time zone
+	Locale           string         // the user's language, sent as Content-Language
+}
+
+// IsEmpty reports whether the digest has nothing to tell
+func (d *Digest) IsEmpty() bool {
+	return len(d.DueSoon) == 0 && len(d.Overdue) == 0 && len(d.RecentlyAssigned) == 0
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// MaxChartDays is the longest date range of burnup and burndown charts
+const MaxChartDays = 366
+
+// ChartDateLayout is the format of chart dates, e.g. 2024-03-04
+const ChartDateLayout = "2006-01-02"
+
+// ProgressCount is the number of a project's open and completed tasks at the end of a
+// day (UTC). Cancelled tasks count as neither.
+type ProgressCount struct {
+	Day       time.Time `json:"day"`
+	Open      int       `json:"open"`
+	Completed int       `json:"completed"`
+}
+
+// BurnupPoint is a day of a burnup chart
+type BurnupPoint struct {
+	Date      string `json:"date"`
+	Completed int    `json:"completed"`
+	Scope     int    `json:"scope"` // open and completed tasks
+}
+
+// BurnupChart is the daily completed work and total scope of a project
+type BurnupChart struct {
+	ProjectKey string         `json:"project_key"`
+	From       string         `json:"from"`
+	To         string         `json:"to"`
+	Points     []*BurnupPoint `json:"points"`
+}
+
+// BurndownPoint is a day of a burndown chart
+type BurndownPoint struct {
+	Date      string `json:"date"`
+	Remaining int    `json:"remaining"` // open tasks
+}
+
+// BurndownChart is the daily remaining work of a project
+type BurndownChart struct {
+	ProjectKey string           `json:"project_key"`
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	Points     []*BurndownPoint `json:"points"`
+}
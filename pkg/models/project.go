@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultStaleAfterDays is how long open tasks may go without updates before they are
+// flagged as stale, unless configured otherwise
+const DefaultStaleAfterDays = 14
+
+// MaxStaleAfterDays is the longest configurable stale threshold
+const MaxStaleAfterDays = 365
+
+// SearchLanguage is the PostgreSQL text search configuration used to index and match a
+// project's tasks
+type SearchLanguage string
+
+const (
+	SearchLanguageEnglish SearchLanguage = "english"
+	SearchLanguageSpanish SearchLanguage = "spanish"
+	SearchLanguageGerman  SearchLanguage = "german"
+	// SearchLanguageSimple lowercases words without stemming or stop words, for projects
+	// written in other languages or mostly identifiers
+	SearchLanguageSimple SearchLanguage = "simple"
+)
+
+// DefaultSearchLanguage is the search language of projects that don't configure one
+const DefaultSearchLanguage = SearchLanguageEnglish
+
+// SearchLanguages lists the supported search languages
+var SearchLanguages = []SearchLanguage{SearchLanguageEnglish, SearchLanguageSpanish, SearchLanguageGerman, SearchLanguageSimple}
+
+// IsValid reports whether l is a supported search language
+func (l SearchLanguage) IsValid() bool {
+	for _, language := range SearchLanguages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectSettings are the configurable settings of a project
+type ProjectSettings struct {
+	ProjectKey string `json:"project_key"`
+	// StaleAfterDays overrides the default stale threshold; 0 disables stale detection
+	// and nil uses the default
+	StaleAfterDays *int `json:"stale_after_days"`
+	// EffectiveStaleAfterDays is the threshold in use, taking the default into account
+	EffectiveStaleAfterDays int `json:"effective_stale_after_days"`
+	// SearchLanguage is the dictionary the project's tasks are searched with
+	SearchLanguage SearchLanguage `json:"search_language"`
+	UpdatedBy      string         `json:"updated_by,omitempty"`
+	UpdatedAt      *time.Time     `json:"updated_at,omitempty"` // nil until the settings are first saved
+}
+
+// ProjectSettingsUpdate replaces a project's settings
+type ProjectSettingsUpdate struct {
+	StaleAfterDays *int           `json:"stale_after_days"` // null restores the default
+	SearchLanguage SearchLanguage `json:"search_language"`  // empty restores the default
+}
+
+// Validate checks the stale threshold and search language
+func (u *ProjectSettingsUpdate) Validate() error {
+	if u.StaleAfterDays != nil && (*u.StaleAfterDays < 0 || *u.StaleAfterDays > MaxStaleAfterDays) {
+		return fmt.Errorf("stale_after_days must be between 0 and %d", MaxStaleAfterDays)
+	}
+	if u.SearchLanguage != "" && !u.SearchLanguage.IsValid() {
+		return fmt.Errorf("search_language must be one of %v", SearchLanguages)
+	}
+	return nil
+}
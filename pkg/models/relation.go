@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// RelationType is how one task relates to another
+type RelationType string
+
+const (
+	// RelationDuplicateOf marks a task as a duplicate of the related task
+	RelationDuplicateOf RelationType = "duplicate_of"
+	// RelationRelatesTo links two tasks without a direction
+	RelationRelatesTo RelationType = "relates_to"
+	// RelationCausedBy marks a task as caused by the related task
+	RelationCausedBy RelationType = "caused_by"
+
+	// RelationDuplicatedBy and RelationCauses are the other tasks' side of duplicate_of
+	// and caused_by, reported when listing relations
+	RelationDuplicatedBy RelationType = "duplicated_by"
+	RelationCauses       RelationType = "causes"
+)
+
+// IsValidRelationType reports whether relationType can be used to relate two tasks
+func IsValidRelationType(relationType RelationType) bool {
+	switch relationType {
+	case RelationDuplicateOf, RelationRelatesTo, RelationCausedBy:
+		return true
+	}
+	return false
+}
+
+// Inverse is the relation seen from the related task, e.g. duplicated_by for duplicate_of
+func (t RelationType) Inverse() RelationType {
+	switch t {
+	case RelationDuplicateOf:
+		return RelationDuplicatedBy
+	case RelationDuplicatedBy:
+		return RelationDuplicateOf
+	case RelationCausedBy:
+		return RelationCauses
+	case RelationCauses:
+		return RelationCausedBy
+	}
+	return t
+}
+
+// TaskRelation is a link from a task to a related task, seen from the task
+type TaskRelation struct {
+	Type RelationType `json:"type"`
+	// TaskID, TaskKey, Title and Status describe the related task
+	TaskID    string     `json:"task_id"`
+	TaskKey   string     `json:"task_key"`
+	Title     string     `json:"title"`
+	Status    TaskStatus `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TaskRelationCreate represents the data required to relate a task to another
+type TaskRelationCreate struct {
+	Type   RelationType `json:"type"`
+	TaskID string       `json:"task_id"`
+}
+
+// Validate checks the relation type and related task
+func (r *TaskRelationCreate) Validate() error {
+	if !IsValidRelationType(r.Type) {
+		return errors.New("invalid relation type, expected duplicate_of, relates_to or caused_by")
+	}
+	if r.TaskID == "" {
+		return errors.New("task_id is required")
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReportDimension is a task attribute a report groups by
+type ReportDimension string
+
+const (
+	DimensionStatus   ReportDimension = "status"
+	DimensionAssignee ReportDimension = "assignee" // unassigned tasks are grouped under ""
+	DimensionTag      ReportDimension = "tag"      // a task is counted once per tag, untagged tasks under ""
+	DimensionProject  ReportDimension = "project"
+)
+
+// ReportMeasure is a value a report computes for each group
+type ReportMeasure string
+
+const (
+	MeasureCount         ReportMeasure = "count"
+	MeasureTotalEstimate ReportMeasure = "total_estimate" // in minutes; unestimated tasks count as zero
+)
+
+// ReportDateField is the task date a report's range applies to
+type ReportDateField string
+
+const (
+	DateFieldCreatedAt ReportDateField = "created_at"
+	DateFieldUpdatedAt ReportDateField = "updated_at"
+	DateFieldDueDate   ReportDateField = "due_date"
+)
+
+const (
+	// DefaultReportRowLimit is the number of rows a report returns unless it sets another
+	DefaultReportRowLimit = 1000
+	// MaxReportRowLimit is the most rows a report may return
+	MaxReportRowLimit = 10000
+	// MaxReportLastDays is the longest relative range a report may cover
+	MaxReportLastDays = 3660
+	// MaxReportNameLength is the longest report name
+	MaxReportNameLength = 100
+)
+
+// ReportDefinition describes what a saved report computes. The range is either fixed,
+// with From and To, or relative to when the report runs, with LastDays.
+type ReportDefinition struct {
+	Dimensions []ReportDimension `json:"dimensions"`
+	Measures   []ReportMeasure   `json:"measures"`
+	DateField  ReportDateField   `json:"date_field,omitempty"` // defaults to created_at
+	From       *time.Time        `json:"from,omitempty"`
+	To         *time.Time        `json:"to,omitempty"` // exclusive
+	LastDays   int               `json:"last_days,omitempty"`
+	RowLimit   int               `json:"row_limit,omitempty"` // defaults to DefaultReportRowLimit
+}
+
+// Validate checks the definition, filling in the default date field and row limit
+func (d *ReportDefinition) Validate() error {
+	if len(d.Dimensions) == 0 {
+		return errors.New("at least one dimension is required")
+	}
+	seen := make(map[ReportDimension]bool)
+	for _, dimension := range d.Dimensions {
+		switch dimension {
+		case DimensionStatus, DimensionAssignee, DimensionTag, DimensionProject:
+		default:
+			return fmt.Errorf("unknown dimension %q: must be status, assignee, tag or project", dimension)
+		}
+		if seen[dimension] {
+			return fmt.Errorf("dimension %q is listed twice", dimension)
+		}
+		seen[dimension] = true
+	}
+
+	if len(d.Measures) == 0 {
+		return errors.New("at least one measure is required")
+	}
+	seenMeasures := make(map[ReportMeasure]bool)
+	for _, measure := range d.Measures {
+		switch measure {
+		case MeasureCount, MeasureTotalEstimate:
+		default:
+			return fmt.Errorf("unknown measure %q: must be count or total_estimate", measure)
+		}
+		if seenMeasures[measure] {
+			return fmt.Errorf("measure %q is listed twice", measure)
+		}
+		seenMeasures[measure] = true
+	}
+
+	if d.DateField == "" {
+		d.DateField = DateFieldCreatedAt
+	}
+	switch d.DateField {
+	case DateFieldCreatedAt, DateFieldUpdatedAt, DateFieldDueDate:
+	default:
+		return errors.New("date_field must be created_at, updated_at or due_date")
+	}
+	if d.LastDays < 0 || d.LastDays > MaxReportLastDays {
+		return fmt.Errorf("last_days must be between 1 and %d", MaxReportLastDays)
+	}
+	if d.LastDays > 0 && (d.From != nil || d.To != nil) {
+		return errors.New("last_days cannot be combined with from or to")
+	}
+	if d.From != nil && d.To != nil && !d.To.After(*d.From) {
+		return errors.New("to must be after from")
+	}
+
+	if d.RowLimit == 0 {
+		d.RowLimit = DefaultReportRowLimit
+	}
+	if d.RowLimit < 0 || d.RowLimit > MaxReportRowLimit {
+		return fmt.Errorf("row_limit must be between 1 and %d", MaxReportRowLimit)
+	}
+	return nil
+}
+
+// Range returns the bounds of the report's range when it runs at now; nil bounds are open
+func (d *ReportDefinition) Range(now time.Time) (from, to *time.Time) {
+	if d.LastDays > 0 {
+		start := now.AddDate(0, 0, -d.LastDays)
+		return &start, &now
+	}
+	return d.From, d.To
+}
+
+// Columns returns the names of the report's columns: its dimensions, then its measures
+func (d *ReportDefinition) Columns() []string {
+	columns := make([]string, 0, len(d.Dimensions)+len(d.Measures))
+	for _, dimension := range d.Dimensions {
+		columns = append(columns, string(dimension))
+	}
+	for _, measure := range d.Measures {
+		columns = append(columns, string(measure))
+	}
+	return columns
+}
+
+// Report is a report definition saved by an admin to be run on demand
+type Report struct {
+	ID         string           `json:"id"`
+	Name       string           `json:"name"`
+	Definition ReportDefinition `json:"definition"`
+	CreatedBy  string           `json:"created_by"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// ReportInput represents the data required to create or replace a report
+type ReportInput struct {
+	Name       string           `json:"name"`
+	Definition ReportDefinition `json:"definition"`
+}
+
+// Validate checks the report's name and definition
+func (r *ReportInput) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(r.Name) > MaxReportNameLength {
+		return fmt.Errorf("name must be at most %d characters", MaxReportNameLength)
+	}
+	return r.Definition.Validate()
+}
+
+// ReportRow is one group of a report's result: the dimension values, in the order of
+// the definition's dimensions, and the measure values, in the order of its measures
+type ReportRow struct {
+	Dimensions []string `json:"dimensions"`
+	Measures   []int64  `json:"measures"`
+}
+
+// ReportResult is the output of running a report
+type ReportResult struct {
+	ReportID    string      `json:"report_id"`
+	Name        string      `json:"name"`
+	Columns     []string    `json:"columns"`
+	Rows        []ReportRow `json:"rows"`
+	From        *time.Time  `json:"from,omitempty"`
+	To          *time.Time  `json:"to,omitempty"`
+	Truncated   bool        `json:"truncated"` // more rows matched than the row limit
+	GeneratedAt time.Time   `json:"generated_at"`
+}
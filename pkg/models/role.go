@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrRoleNotFound is returned for roles that are neither built in nor stored
+	ErrRoleNotFound = errors.New("role not found")
+
+	// roleNameRe matches role names such as "admin" or "org_admin"
+	roleNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+	// rolePatternRe matches route patterns such as "/api/v1/tasks/{id}"; patterns are turned
+	// into regular expressions, so anything else could match more than it reads
+	rolePatternRe = regexp.MustCompile(`^(/([A-Za-z0-9_-]+|\{id\}))+$`)
+	// roleMethods are the methods a role may be granted
+	roleMethods = map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+)
+
+// RoleDefinition is a role and the methods it may call on each route pattern
+type RoleDefinition struct {
+	Name        string              `json:"name"`
+	Permissions map[string][]string `json:"permissions"` // route pattern -> allowed methods
+	// Builtin is set for roles served with the permissions compiled into the server, i.e.
+	// built-in roles that were never edited
+	Builtin   bool       `json:"builtin"`
+	UpdatedBy string     `json:"updated_by,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// RoleInput replaces a role's permissions
+type RoleInput struct {
+	Permissions map[string][]string `json:"permissions"`
+}
+
+// ValidateRoleName checks a role name is lowercase letters, digits and underscores
+func ValidateRoleName(name string) error {
+	if !roleNameRe.MatchString(name) {
+		return errors.New("role name must be 1-50 lowercase letters, digits or underscores, starting with a letter")
+	}
+	return nil
+}
+
+// Validate checks every route pattern and method
+func (r *RoleInput) Validate() error {
+	if r.Permissions == nil {
+		return errors.New("permissions are required")
+	}
+	patterns := make([]string, 0, len(r.Permissions))
+	for pattern := range r.Permissions {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		if !rolePatternRe.MatchString(pattern) {
+			return fmt.Errorf("invalid route pattern %q: use path segments and {id}", pattern)
+		}
+		if len(r.Permissions[pattern]) == 0 {
+			return fmt.Errorf("route pattern %q has no methods", pattern)
+		}
+		for _, method := range r.Permissions[pattern] {
+			if !roleMethods[method] {
+				return fmt.Errorf("invalid method %q for route pattern %q", method, pattern)
+			}
+		}
+	}
+	return nil
+}
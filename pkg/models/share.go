@@ -0,0 +1,61 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// SharePermission is the access level granted by a share link
+type SharePermission string
+
+const (
+	SharePermissionRead    SharePermission = "read"
+	SharePermissionComment SharePermission = "comment"
+)
+
+// MaxShareExpiry is the longest lifetime a share link may have
+const MaxShareExpiry = 30 * 24 * time.Hour
+
+// TaskShare represents a link granting account-less access to a single task
+type TaskShare struct {
+	ID         string          `json:"id"`
+	TaskID     string          `json:"task_id"`
+	Permission SharePermission `json:"permission"`
+	CreatedBy  string          `json:"created_by"`
+	ExpiresAt  time.Time       `json:"expires_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+	RevokedAt  *time.Time      `json:"revoked_at,omitempty"`
+}
+
+// IsActive reports whether the share can still be used
+func (s *TaskShare) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Allows reports whether the share grants the given permission; comment implies read
+func (s *TaskShare) Allows(permission SharePermission) bool {
+	return s.Permission == permission || s.Permission == SharePermissionComment
+}
+
+// TaskShareCreate represents the data required to share a task
+type TaskShareCreate struct {
+	Permission SharePermission `json:"permission"`
+	ExpiresIn  int64           `json:"expires_in"` // seconds; defaults to 7 days
+}
+
+// Validate checks if the share request is valid
+func (s *TaskShareCreate) Validate() error {
+	if s.Permission == "" {
+		s.Permission = SharePermissionRead
+	}
+	if s.Permission != SharePermissionRead && s.Permission != SharePermissionComment {
+		return errors.New("invalid permission")
+	}
+	if s.ExpiresIn == 0 {
+		s.ExpiresIn = int64((7 * 24 * time.Hour).Seconds())
+	}
+	if s.ExpiresIn < 0 || time.Duration(s.ExpiresIn)*time.Second > MaxShareExpiry {
+		return errors.New("expires_in must be between 1 second and 30 days")
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxSLAWindow is the longest time an SLA may allow for a response or resolution
+const MaxSLAWindow = 365 * 24 * time.Hour
+
+// SLAKind names the two targets of an SLA
+type SLAKind string
+
+const (
+	// SLARespond is met once the task leaves pending
+	SLARespond SLAKind = "respond"
+	// SLAResolve is met once the task is completed or cancelled
+	SLAResolve SLAKind = "resolve"
+)
+
+// SLAPolicy is how long a task may take to be responded to and resolved, as durations
+// such as "4h" counted from its creation. Either may be left out.
+type SLAPolicy struct {
+	RespondWithin string `json:"respond_within,omitempty"`
+	ResolveWithin string `json:"resolve_within,omitempty"`
+}
+
+// Parse validates the policy and returns its windows, nil for the ones left out
+func (p *SLAPolicy) Parse() (respond, resolve *time.Duration, err error) {
+	if p.RespondWithin == "" && p.ResolveWithin == "" {
+		return nil, nil, errors.New("respond_within or resolve_within is required")
+	}
+	if respond, err = parseSLAWindow("respond_within", p.RespondWithin); err != nil {
+		return nil, nil, err
+	}
+	if resolve, err = parseSLAWindow("resolve_within", p.ResolveWithin); err != nil {
+		return nil, nil, err
+	}
+	if respond != nil && resolve != nil && *resolve < *respond {
+		return nil, nil, errors.New("resolve_within must not be shorter than respond_within")
+	}
+	return respond, resolve, nil
+}
+
+func parseSLAWindow(field, value string) (*time.Duration, error) {
+	if value == "" {
+		return nil, nil
+	}
+	window, err := time.ParseDuration(value)
+	if err != nil || window < time.Second {
+		return nil, fmt.Errorf("%s must be a duration such as 30m or 4h", field)
+	}
+	if window > MaxSLAWindow {
+		return nil, fmt.Errorf("%s must be at most 365 days", field)
+	}
+	window = window.Truncate(time.Second)
+	return &window, nil
+}
+
+// TaskSLA is a task's SLA policy and how the task is doing against it
+type TaskSLA struct {
+	Policy        SLAPolicy      `json:"policy"`
+	RespondWithin *time.Duration `json:"-"`
+	ResolveWithin *time.Duration `json:"-"`
+	// RespondedAt is when the task left pending, ResolvedAt when it was last completed or
+	// cancelled; nil until then
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+
+	// Respond and Resolve are set by Evaluate for the targets the policy has
+	Respond *SLATarget `json:"respond,omitempty"`
+	Resolve *SLATarget `json:"resolve,omitempty"`
+}
+
+// NewTaskSLA creates the SLA of a task with the given windows, nil for a target it
+// doesn't have
+func NewTaskSLA(respondWithin, resolveWithin *time.Duration) *TaskSLA {
+	sla := &TaskSLA{RespondWithin: respondWithin, ResolveWithin: resolveWithin}
+	if respondWithin != nil {
+		sla.Policy.RespondWithin = respondWithin.String()
+	}
+	if resolveWithin != nil {
+		sla.Policy.ResolveWithin = resolveWithin.String()
+	}
+	return sla
+}
+
+// SLATarget is where a task stands against one SLA target
+type SLATarget struct {
+	// Due is when the target is breached unless met before
+	Due time.Time `json:"due"`
+	// RemainingSeconds is the time left until Due, negative once breached. Once the
+	// target is met it is the time that was left then.
+	RemainingSeconds int64 `json:"remaining_seconds"`
+	Breached         bool  `json:"breached"`
+}
+
+// Evaluate sets the targets of an SLA whose clock started at start, as of now
+func (s *TaskSLA) Evaluate(start, now time.Time) {
+	s.Respond = evaluateSLATarget(s.RespondWithin, s.RespondedAt, start, now)
+	s.Resolve = evaluateSLATarget(s.ResolveWithin, s.ResolvedAt, start, now)
+}
+
+func evaluateSLATarget(within *time.Duration, metAt *time.Time, start, now time.Time) *SLATarget {
+	if within == nil {
+		return nil
+	}
+	target := &SLATarget{Due: start.Add(*within).UTC()}
+	until := now
+	if metAt != nil {
+		until = *metAt
+	}
+	remaining := target.Due.Sub(until)
+	target.RemainingSeconds = int64(remaining / time.Second)
+	target.Breached = remaining < 0
+	return target
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxSnooze is the longest a task can be snoozed for
+const MaxSnooze = 365 * 24 * time.Hour
+
+// TaskSnooze is how long to snooze a task: for a duration such as "4h", or until a time
+type TaskSnooze struct {
+	Duration string     `json:"duration,omitempty"`
+	Until    *time.Time `json:"until,omitempty"`
+}
+
+// WakeAt returns when a task snoozed at now shows up again
+func (s *TaskSnooze) WakeAt(now time.Time) (time.Time, error) {
+	if (s.Duration == "") == (s.Until == nil) {
+		return time.Time{}, errors.New("either duration or until is required")
+	}
+	until := s.Until
+	if s.Duration != "" {
+		duration, err := time.ParseDuration(s.Duration)
+		if err != nil {
+			return time.Time{}, errors.New("duration must be a duration such as 30m or 4h")
+		}
+		wake := now.Add(duration)
+		until = &wake
+	}
+	if !until.After(now) {
+		return time.Time{}, errors.New("snooze must end in the future")
+	}
+	if until.Sub(now) > MaxSnooze {
+		return time.Time{}, errors.New("tasks can be snoozed for at most 365 days")
+	}
+	return until.UTC(), nil
+}
@@ -0,0 +1,206 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sync change operations
+const (
+	SyncUpsert = "upsert"
+	SyncDelete = "delete"
+)
+
+// DefaultSyncLimit and MaxSyncLimit bound the changes returned by one sync call
+const (
+	DefaultSyncLimit = 100
+	MaxSyncLimit     = 500
+)
+
+// ErrInvalidSyncCursor is returned for cursors not handed out by a sync
+var ErrInvalidSyncCursor = errors.New("invalid sync cursor")
+
+// SyncChange is a task created, updated or deleted since a sync cursor
+type SyncChange struct {
+	Op     string `json:"op"` // SyncUpsert or SyncDelete
+	TaskID string `json:"task_id"`
+	// Task is the task's current state for upserts
+	Task      *Task      `json:"task,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Seq is the change's position in the change sequence
+	Seq int64 `json:"-"`
+}
+
+// SyncCursor is a position in the change sequence: changes are ordered by Seq and then
+// task ID, and a sync returns the changes after the cursor. The zero cursor syncs every task.
+type SyncCursor struct {
+	Seq    int64
+	TaskID string
+}
+
+// IsZero reports whether the cursor is the start of the change sequence
+func (c SyncCursor) IsZero() bool {
+	return c.Seq == 0 && c.TaskID == ""
+}
+
+// String encodes the cursor for clients, who pass it back unchanged
+func (c SyncCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.Seq, 10) + ":" + c.TaskID))
+}
+
+// ParseSyncCursor decodes a cursor returned by SyncCursor.String; "" is the zero cursor
+func ParseSyncCursor(value string) (SyncCursor, error) {
+	if value == "" {
+		return SyncCursor{}, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return SyncCursor{}, ErrInvalidSyncCursor
+	}
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return SyncCursor{}, ErrInvalidSyncCursor
+	}
+	seq, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || seq < 0 {
+		return SyncCursor{}, ErrInvalidSyncCursor
+	}
+	return SyncCursor{Seq: seq, TaskID: parts[1]}, nil
+}
+
+// SyncPage is one call's worth of changes
+type SyncPage struct {
+	Changes []*SyncChange `json:"changes"`
+	// Cursor is passed as since to the next sync
+	Cursor string `json:"cursor"`
+	// HasMore is set when changes were left out for the limit; sync again right away
+	HasMore bool `json:"has_more"`
+}
+
+// ConflictPolicy decides what happens to an offline edit of a task that changed on the
+// server since the client last synced it
+type ConflictPolicy string
+
+const (
+	// ConflictLastWriteWins keeps whichever edit was made last, the client's going by
+	// its ClientUpdatedAt
+	ConflictLastWriteWins ConflictPolicy = "last_write_wins"
+	// ConflictServerWins drops the client's edit
+	ConflictServerWins ConflictPolicy = "server_wins"
+	// ConflictFieldMerge applies the edited fields the server left alone and keeps the
+	// server's value of fields both sides changed
+	ConflictFieldMerge ConflictPolicy = "field_merge"
+)
+
+// ParseConflictPolicy parses a conflict policy; "" is ConflictFieldMerge
+func ParseConflictPolicy(value string) (ConflictPolicy, error) {
+	switch policy := ConflictPolicy(value); policy {
+	case "":
+		return ConflictFieldMerge, nil
+	case ConflictLastWriteWins, ConflictServerWins, ConflictFieldMerge:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown conflict policy %q, expected last_write_wins, server_wins or field_merge", value)
+}
+
+// Offline edit operations
+const (
+	SyncEditUpdate = "update"
+	SyncEditDelete = "delete"
+)
+
+// MaxSyncEdits is the most edits one push may carry
+const MaxSyncEdits = 100
+
+// SyncFields are the task fields offline clients can edit; nil fields are left alone
+type SyncFields struct {
+	Title       *string       `json:"title,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Status      *TaskStatus   `json:"status,omitempty"`
+	Priority    *TaskPriority `json:"priority,omitempty"`
+	DueDate     *time.Time    `json:"due_date,omitempty"`
+	AssigneeID  *string       `json:"assignee_id,omitempty"`
+	Tags        *[]string     `json:"tags,omitempty"`
+}
+
+// SyncEdit is a change a client made to a task while offline
+type SyncEdit struct {
+	TaskID string `json:"task_id"`
+	Op     string `json:"op"` // SyncEditUpdate (the default) or SyncEditDelete
+	// BaseUpdatedAt is the updated_at of the task as the client last synced it
+	BaseUpdatedAt time.Time `json:"base_updated_at"`
+	// ClientUpdatedAt is when the edit was made, compared by ConflictLastWriteWins
+	ClientUpdatedAt time.Time `json:"client_updated_at"`
+	// Changes are the fields' new values, and Base their values before the edit.
+	// Without Base every edited field that differs on the server is a conflict.
+	Changes SyncFields `json:"changes"`
+	Base    SyncFields `json:"base"`
+}
+
+// SyncPush is a batch of offline edits
+type SyncPush struct {
+	// Policy overrides the server's conflict policy for these edits
+	Policy ConflictPolicy `json:"policy,omitempty"`
+	Edits  []*SyncEdit    `json:"edits"`
+}
+
+// Validate checks the policy and edits
+func (p *SyncPush) Validate() error {
+	if p.Policy != "" {
+		if _, err := ParseConflictPolicy(string(p.Policy)); err != nil {
+			return err
+		}
+	}
+	if len(p.Edits) == 0 {
+		return errors.New("edits are required")
+	}
+	if len(p.Edits) > MaxSyncEdits {
+		return fmt.Errorf("at most %d edits can be pushed at once", MaxSyncEdits)
+	}
+	for _, edit := range p.Edits {
+		if edit.Op == "" {
+			edit.Op = SyncEditUpdate
+		}
+		if edit.TaskID == "" {
+			return errors.New("task_id is required")
+		}
+		if edit.Op != SyncEditUpdate && edit.Op != SyncEditDelete {
+			return fmt.Errorf("invalid op %q, expected update or delete", edit.Op)
+		}
+		if edit.BaseUpdatedAt.IsZero() {
+			return errors.New("base_updated_at is required")
+		}
+	}
+	return nil
+}
+
+// Outcomes of an offline edit
+const (
+	SyncApplied  = "applied"  // every edited field was applied
+	SyncMerged   = "merged"   // some fields were applied, the conflicting ones kept the server's value
+	SyncRejected = "rejected" // nothing was applied because of conflicts
+	SyncFailed   = "failed"   // the edit was invalid or not allowed
+)
+
+// SyncConflict is a field both the client and the server changed
+type SyncConflict struct {
+	Field       string      `json:"field"`
+	ServerValue interface{} `json:"server_value"`
+	ClientValue interface{} `json:"client_value"`
+	// Resolution is "client" when the client's value was applied and "server" when kept
+	Resolution string `json:"resolution"`
+}
+
+// SyncEditResult is what became of an offline edit
+type SyncEditResult struct {
+	TaskID    string          `json:"task_id"`
+	Status    string          `json:"status"`
+	Conflicts []*SyncConflict `json:"conflicts,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	// Task is the task as it is now; nil once deleted
+	Task *Task `json:"task,omitempty"`
+}
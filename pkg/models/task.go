@@ -1,10 +1,19 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
+// ErrTaskNotFound is returned for tasks that don't exist or aren't visible to the caller
+var ErrTaskNotFound = errors.New("task not found")
+
 // TaskStatus represents the current status of a task
 type TaskStatus string
 
@@ -13,15 +22,56 @@ const (
 	StatusInProgress TaskStatus = "in_progress"
 	StatusCompleted TaskStatus = "completed"
 	StatusCancelled TaskStatus = "cancelled"
+	// StatusDraft marks an unpublished task, visible only to its owner. Drafts are saved
+	// with TaskDraft and published explicitly; status updates never set or clear it.
+	StatusDraft TaskStatus = "draft"
+)
+
+// TaskPriority represents how urgent a task is
+type TaskPriority string
+
+const (
+	PriorityLow      TaskPriority = "low"
+	PriorityMedium   TaskPriority = "medium"
+	PriorityHigh     TaskPriority = "high"
+	PriorityCritical TaskPriority = "critical"
 )
 
 // Task represents a task in the system
 type Task struct {
 	ID          string     `json:"id"`
+	Key         string     `json:"key"` // human-friendly ID, e.g. PROJ-123
+	ProjectKey  string     `json:"project_key"`
+	Number      int64      `json:"number"`
+	Slug        string     `json:"slug"` // URL-safe title, unique within the project
 	Title       string     `json:"title"`
 	Description string     `json:"description"`
 	Status      TaskStatus `json:"status"`
+	Priority    TaskPriority `json:"priority"`
 	DueDate     time.Time  `json:"due_date"`
+	OwnerID     string     `json:"owner_id,omitempty"`
+	AssigneeID  string     `json:"assignee_id,omitempty"`
+	TeamID      string     `json:"team_id,omitempty"` // the team the task is assigned to, see Team
+	ParentID    string     `json:"parent_id,omitempty"`
+	OrgID       string     `json:"-"` // the tenant the task belongs to; empty for the default tenant
+	Tags        []string   `json:"tags,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"` // custom fields, see ValidateMetadata
+	Checklist   *ChecklistProgress `json:"checklist,omitempty"` // nil without checklist items
+	EstimatedMinutes *int `json:"estimated_minutes,omitempty"` // nil without an estimate
+	LoggedMinutes    int  `json:"logged_minutes"`              // total of the task's time entries
+	// StaleSince is when the task was flagged for going without updates; nil unless stale
+	StaleSince  *time.Time `json:"stale_since,omitempty"`
+	// OverdueSince is when the task was flagged for passing its due date; nil unless overdue
+	OverdueSince *time.Time `json:"overdue_since,omitempty"`
+	// SnoozedUntil is when a snoozed task shows up in lists again; nil unless snoozed
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+	// SLA is the task's SLA policy and its targets; nil unless the task has one
+	SLA *TaskSLA `json:"sla,omitempty"`
+	// Position orders the task within its board column, the project's tasks in its status
+	Position    float64    `json:"position"`
+	Starred     bool       `json:"starred"` // starred by the requesting user
+	// Relations links the task to related tasks; only set when a single task is fetched
+	Relations   []*TaskRelation `json:"relations,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 }
@@ -31,7 +81,16 @@ type TaskCreate struct {
 	Title       string     `json:"title"`
 	Description string     `json:"description"`
 	Status      TaskStatus `json:"status"`
+	Priority    TaskPriority `json:"priority"`
 	DueDate     time.Time  `json:"due_date"`
+	AssigneeID  string     `json:"assignee_id,omitempty"`
+	ParentID    string     `json:"parent_id,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	EstimatedMinutes int   `json:"estimated_minutes,omitempty"`
+	ProjectKey  string     `json:"project_key,omitempty"` // defaults to DefaultProjectKey
+	OwnerID     string     `json:"-"` // set from the authenticated user, never from the request body
+	OrgID       string     `json:"-"` // the authenticated user's organization, if any
 }
 
 // TaskUpdate represents the data that can be updated for a task
@@ -39,7 +98,62 @@ type TaskUpdate struct {
 	Title       *string     `json:"title,omitempty"`
 	Description *string     `json:"description,omitempty"`
 	Status      *TaskStatus `json:"status,omitempty"`
+	Priority    *TaskPriority `json:"priority,omitempty"`
 	DueDate     *time.Time  `json:"due_date,omitempty"`
+	AssigneeID  *string     `json:"assignee_id,omitempty"` // empty string unassigns
+	ParentID    *string     `json:"parent_id,omitempty"`   // empty string makes the task top-level
+	Tags        *[]string   `json:"tags,omitempty"`        // replaces all tags; empty list removes them
+	Metadata    map[string]interface{} `json:"metadata,omitempty"` // replaces all metadata; empty object removes it
+	EstimatedMinutes *int `json:"estimated_minutes,omitempty"` // 0 removes the estimate
+	// CascadeStatus applies Status to every subtask as well
+	CascadeStatus bool `json:"cascade_status,omitempty"`
+	// Force completes the task even if it has open blockers
+	Force bool `json:"force,omitempty"`
+	// EditorID is the user making the change, recorded with description versions
+	EditorID string `json:"-"`
+}
+
+// TaskDraft is the content of a draft, saved whole on every autosave. Only the
+// fields that are set are checked, so incomplete drafts can be saved.
+type TaskDraft struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Priority    TaskPriority `json:"priority"`
+	DueDate     *time.Time   `json:"due_date,omitempty"`
+	AssigneeID  string       `json:"assignee_id,omitempty"`
+	ParentID    string       `json:"parent_id,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	ProjectKey  string       `json:"project_key,omitempty"` // fixed once the draft is first saved
+	OwnerID     string       `json:"-"`
+	OrgID       string       `json:"-"` // set on the first save
+}
+
+// Validate checks the fields a draft sets
+func (d *TaskDraft) Validate() error {
+	if d.Priority == "" {
+		d.Priority = PriorityMedium
+	}
+	if !IsValidPriority(d.Priority) {
+		return errors.New("invalid priority")
+	}
+	tags, err := NormalizeTags(d.Tags)
+	if err != nil {
+		return err
+	}
+	d.Tags = tags
+	if err := ValidateMetadata(d.Metadata); err != nil {
+		return err
+	}
+	if d.ProjectKey == "" {
+		d.ProjectKey = DefaultProjectKey
+	}
+	projectKey, err := NormalizeProjectKey(d.ProjectKey)
+	if err != nil {
+		return err
+	}
+	d.ProjectKey = projectKey
+	return nil
 }
 
 // Validate checks if the task create request is valid
@@ -50,35 +164,272 @@ func (t *TaskCreate) Validate() error {
 	if t.Status == "" {
 		t.Status = StatusPending
 	}
-	if !isValidStatus(t.Status) {
+	if !IsValidStatus(t.Status) {
 		return errors.New("invalid status")
 	}
+	if t.Priority == "" {
+		t.Priority = PriorityMedium
+	}
+	if !IsValidPriority(t.Priority) {
+		return errors.New("invalid priority")
+	}
 	if t.DueDate.IsZero() {
 		return errors.New("due date is required")
 	}
 	if t.DueDate.Before(time.Now()) {
 		return errors.New("due date must be in the future")
 	}
+	tags, err := NormalizeTags(t.Tags)
+	if err != nil {
+		return err
+	}
+	t.Tags = tags
+	if err := ValidateMetadata(t.Metadata); err != nil {
+		return err
+	}
+	if err := validateEstimate(t.EstimatedMinutes); err != nil {
+		return err
+	}
+	if t.ProjectKey == "" {
+		t.ProjectKey = DefaultProjectKey
+	}
+	projectKey, err := NormalizeProjectKey(t.ProjectKey)
+	if err != nil {
+		return err
+	}
+	t.ProjectKey = projectKey
 	return nil
 }
 
 // Validate checks if the task update request is valid
 func (t *TaskUpdate) Validate() error {
-	if t.Status != nil && !isValidStatus(*t.Status) {
+	if t.Status != nil && !IsValidStatus(*t.Status) {
 		return errors.New("invalid status")
 	}
+	if t.Priority != nil && !IsValidPriority(*t.Priority) {
+		return errors.New("invalid priority")
+	}
 	if t.DueDate != nil && t.DueDate.Before(time.Now()) {
 		return errors.New("due date must be in the future")
 	}
+	if t.CascadeStatus && t.Status == nil {
+		return errors.New("cascade_status requires status")
+	}
+	if t.Tags != nil {
+		tags, err := NormalizeTags(*t.Tags)
+		if err != nil {
+			return err
+		}
+		t.Tags = &tags
+	}
+	if err := ValidateMetadata(t.Metadata); err != nil {
+		return err
+	}
+	if t.EstimatedMinutes != nil {
+		if err := validateEstimate(*t.EstimatedMinutes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MaxTags is the number of tags a task may carry; MaxTagLength is the longest tag
+const (
+	MaxTags      = 20
+	MaxTagLength = 64
+)
+
+// Metadata limits: the number of keys, the longest key and the largest value in bytes
+// of JSON
+const (
+	MaxMetadataKeys      = 50
+	MaxMetadataKeyLength = 64
+	MaxMetadataValueSize = 1024
+)
+
+// ValidateMetadata checks custom fields against the metadata limits. Keys may contain
+// letters, digits, "_" and "-" so they can be used in ?metadata.<key>= filters.
+func ValidateMetadata(metadata map[string]interface{}) error {
+	if len(metadata) > MaxMetadataKeys {
+		return fmt.Errorf("metadata may have at most %d keys", MaxMetadataKeys)
+	}
+	for key, value := range metadata {
+		if err := ValidateMetadataKey(key); err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("metadata %q: %v", key, err)
+		}
+		if len(encoded) > MaxMetadataValueSize {
+			return fmt.Errorf("metadata %q is larger than %d bytes", key, MaxMetadataValueSize)
+		}
+	}
+	return nil
+}
+
+// ValidateMetadataKey checks a single metadata key
+func ValidateMetadataKey(key string) error {
+	if key == "" || len(key) > MaxMetadataKeyLength {
+		return fmt.Errorf("metadata keys must be 1 to %d characters", MaxMetadataKeyLength)
+	}
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			return fmt.Errorf("metadata key %q contains invalid character %q", key, r)
+		}
+	}
 	return nil
 }
 
-// isValidStatus checks if the given status is valid
-func isValidStatus(status TaskStatus) bool {
+// DefaultProjectKey is the project of tasks created without one
+const DefaultProjectKey = "TASK"
+
+// MaxProjectKeyLength is the longest project key
+const MaxProjectKeyLength = 10
+
+// NormalizeProjectKey uppercases a project key and checks that it is a letter followed
+// by letters or digits, e.g. PROJ or WEB2
+func NormalizeProjectKey(key string) (string, error) {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if len(key) < 2 || len(key) > MaxProjectKeyLength {
+		return "", fmt.Errorf("project key must be 2 to %d characters", MaxProjectKeyLength)
+	}
+	for i, r := range key {
+		if (r < 'A' || r > 'Z') && (i == 0 || r < '0' || r > '9') {
+			return "", fmt.Errorf("project key %q must be a letter followed by letters or digits", key)
+		}
+	}
+	return key, nil
+}
+
+// FormatTaskKey returns the human-friendly ID of a task, e.g. PROJ-123
+func FormatTaskKey(projectKey string, number int64) string {
+	return projectKey + "-" + strconv.FormatInt(number, 10)
+}
+
+// ParseTaskKey splits a key such as proj-123 into its normalized project key and number
+func ParseTaskKey(key string) (string, int64, error) {
+	i := strings.LastIndex(key, "-")
+	if i < 0 {
+		return "", 0, fmt.Errorf("invalid task key %q", key)
+	}
+	projectKey, err := NormalizeProjectKey(key[:i])
+	if err != nil {
+		return "", 0, err
+	}
+	number, err := strconv.ParseInt(key[i+1:], 10, 64)
+	if err != nil || number < 1 {
+		return "", 0, fmt.Errorf("invalid task key %q", key)
+	}
+	return projectKey, number, nil
+}
+
+// MaxSlugLength is the longest slug derived from a title, before any suffix that
+// keeps it unique
+const MaxSlugLength = 80
+
+// Slugify derives a URL-safe slug from a title: lowercase ASCII letters and digits
+// separated by single hyphens, e.g. "Fix Login (iOS)" becomes "fix-login-ios"
+func Slugify(title string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(title) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingHyphen = true
+	}
+
+	slug := b.String()
+	if len(slug) > MaxSlugLength {
+		slug = strings.TrimRight(slug[:MaxSlugLength], "-")
+	}
+	if slug == "" {
+		return "task"
+	}
+	return slug
+}
+
+// TagCount is a tag and the number of tasks carrying it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TaskBadges are the task counts shown as badges in the UI
+type TaskBadges struct {
+	Open         int64 `json:"open"`           // pending or in progress
+	Overdue      int64 `json:"overdue"`        // open and past the due date
+	DueToday     int64 `json:"due_today"`      // open and due today
+	AssignedToMe int64 `json:"assigned_to_me"` // open and assigned to the caller
+}
+
+// TaskView records when a user last viewed a task
+type TaskView struct {
+	TaskID   string    `json:"task_id"`
+	ViewedAt time.Time `json:"viewed_at"`
+}
+
+// RecentTask is a recently viewed task
+type RecentTask struct {
+	Task     *Task     `json:"task"`
+	ViewedAt time.Time `json:"viewed_at"`
+}
+
+// IsOpen reports whether a task still needs work
+func (t *Task) IsOpen() bool {
+	return t.Status == StatusPending || t.Status == StatusInProgress
+}
+
+// NormalizeTags lowercases, trims, de-duplicates and sorts tags. Tags may contain
+// letters, digits and "-", "_", ":" or "."; commas are reserved for filtering.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > MaxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, MaxTagLength)
+		}
+		for _, r := range tag {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_:.", r) {
+				return nil, fmt.Errorf("tag %q contains invalid character %q", tag, r)
+			}
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxTags {
+		return nil, fmt.Errorf("a task may have at most %d tags", MaxTags)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// IsValidStatus checks if the given status is valid
+func IsValidStatus(status TaskStatus) bool {
 	switch status {
 	case StatusPending, StatusInProgress, StatusCompleted, StatusCancelled:
 		return true
 	default:
 		return false
 	}
-} 
\ No newline at end of file
+}
+
+// IsValidPriority checks if the given priority is valid
+func IsValidPriority(priority TaskPriority) bool {
+	switch priority {
+	case PriorityLow, PriorityMedium, PriorityHigh, PriorityCritical:
+		return true
+	default:
+		return false
+	}
+}
\ No newline at end of file
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxTeamNameLength is the longest team name; MaxTeamDescriptionLength the longest description
+const (
+	MaxTeamNameLength        = 100
+	MaxTeamDescriptionLength = 2000
+)
+
+var (
+	// ErrTeamNotFound is returned for teams that don't exist
+	ErrTeamNotFound = errors.New("team not found")
+	// ErrTeamNameTaken is returned when another team already has the name
+	ErrTeamNameTaken = errors.New("a team with this name already exists")
+)
+
+// Team is a group of users tasks can be assigned to. Members of a task's team may
+// change it like its owner and assignee.
+type Team struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Members lists the team's members; only set when a single team is fetched
+	Members []*TeamMember `json:"members,omitempty"`
+}
+
+// TeamMember is a user's membership of a team
+type TeamMember struct {
+	UserID  string    `json:"user_id"`
+	AddedBy string    `json:"added_by"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// TeamInput represents the data required to create or replace a team
+type TeamInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Validate checks the team's name and description
+func (t *TeamInput) Validate() error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(t.Name) > MaxTeamNameLength {
+		return fmt.Errorf("name must be at most %d characters", MaxTeamNameLength)
+	}
+	if len(t.Description) > MaxTeamDescriptionLength {
+		return fmt.Errorf("description must be at most %d characters", MaxTeamDescriptionLength)
+	}
+	return nil
+}
+
+// TaskTeamAssignment assigns a task to a team
+type TaskTeamAssignment struct {
+	TeamID string `json:"team_id"`
+}
+
+// Validate checks that a team is given
+func (a *TaskTeamAssignment) Validate() error {
+	if a.TeamID == "" {
+		return errors.New("team_id is required")
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Telemetry limits: events per batch, properties per event and their size in bytes of
+// JSON, and how far an event's time may lie in the past
+const (
+	MaxTelemetryBatchSize      = 100
+	MaxTelemetryProperties     = 20
+	MaxTelemetryPropertiesSize = 2048
+	MaxTelemetryEventAge       = 24 * time.Hour
+	// telemetryClockSkew is how far ahead of the server a client's clock may be
+	telemetryClockSkew = 5 * time.Minute
+)
+
+// TelemetryClients are the first-party clients that report usage events
+var TelemetryClients = []string{"web", "ios", "android", "desktop"}
+
+// telemetryEventName matches dotted lowercase names such as "task.board.opened"
+var telemetryEventName = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+// TelemetryEvent is a stored usage event
+type TelemetryEvent struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Client     string                 `json:"client"`
+	UserID     string                 `json:"user_id"`
+	OrgID      string                 `json:"org_id,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	SampleRate float64                `json:"sample_rate"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	ReceivedAt time.Time              `json:"received_at"`
+}
+
+// TelemetryEventInput is a single event reported by a client
+type TelemetryEventInput struct {
+	Name       string                 `json:"name"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+	OccurredAt *time.Time             `json:"occurred_at,omitempty"` // defaults to when it was received
+}
+
+// TelemetryBatch is a batch of events reported by a client
+type TelemetryBatch struct {
+	Client string                `json:"client"`
+	Events []TelemetryEventInput `json:"events"`
+}
+
+// Validate checks the whole batch against the event schema; one invalid event rejects
+// the batch so client bugs surface instead of skewing the data
+func (b *TelemetryBatch) Validate(now time.Time) error {
+	if !isTelemetryClient(b.Client) {
+		return fmt.Errorf("client must be one of %v", TelemetryClients)
+	}
+	if len(b.Events) == 0 || len(b.Events) > MaxTelemetryBatchSize {
+		return fmt.Errorf("a batch must have 1 to %d events", MaxTelemetryBatchSize)
+	}
+	for i := range b.Events {
+		if err := b.Events[i].validate(now); err != nil {
+			return fmt.Errorf("events[%d]: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func (e *TelemetryEventInput) validate(now time.Time) error {
+	if len(e.Name) > 64 || !telemetryEventName.MatchString(e.Name) {
+		return errors.New("name must be up to 64 lowercase letters, digits and \"_\" in dot-separated parts")
+	}
+	if e.OccurredAt == nil {
+		e.OccurredAt = &now
+	}
+	if e.OccurredAt.Before(now.Add(-MaxTelemetryEventAge)) || e.OccurredAt.After(now.Add(telemetryClockSkew)) {
+		return fmt.Errorf("occurred_at must be within the last %v", MaxTelemetryEventAge)
+	}
+
+	if len(e.Properties) > MaxTelemetryProperties {
+		return fmt.Errorf("properties may have at most %d keys", MaxTelemetryProperties)
+	}
+	for key, value := range e.Properties {
+		if err := ValidateMetadataKey(key); err != nil {
+			return err
+		}
+		switch value.(type) {
+		case string, float64, bool, nil:
+		default:
+			return fmt.Errorf("property %q must be a string, number, boolean or null", key)
+		}
+	}
+	encoded, err := json.Marshal(e.Properties)
+	if err != nil {
+		return err
+	}
+	if len(encoded) > MaxTelemetryPropertiesSize {
+		return fmt.Errorf("properties are larger than %d bytes", MaxTelemetryPropertiesSize)
+	}
+	return nil
+}
+
+func isTelemetryClient(client string) bool {
+	for _, known := range TelemetryClients {
+		if client == known {
+			return true
+		}
+	}
+	return false
+}
+
+// TelemetrySettings are an organization's usage analytics settings
+type TelemetrySettings struct {
+	OrgID     string     `json:"org_id"`
+	OptedOut  bool       `json:"opted_out"`
+	UpdatedBy string     `json:"updated_by,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"` // nil until the settings are first changed
+}
+
+// TelemetrySettingsUpdate represents a change to an organization's telemetry settings
+type TelemetrySettingsUpdate struct {
+	OptedOut *bool `json:"opted_out"`
+}
+
+// Validate checks that the update sets opted_out
+func (u *TelemetrySettingsUpdate) Validate() error {
+	if u.OptedOut == nil {
+		return errors.New("opted_out is required")
+	}
+	return nil
+}
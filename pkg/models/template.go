@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxChecklistItems is the number of checklist items a template or task may have;
+// MaxChecklistItemLength is the longest item
+const (
+	MaxChecklistItems      = 50
+	MaxChecklistItemLength = 255
+)
+
+// TaskTemplate is a reusable blueprint for creating tasks
+type TaskTemplate struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      TaskStatus `json:"status"` // status of tasks created from the template
+	Checklist   []string   `json:"checklist"`
+	OwnerID     string     `json:"owner_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TaskTemplateInput represents the data required to create or replace a template
+type TaskTemplateInput struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      TaskStatus `json:"status"`
+	Checklist   []string   `json:"checklist"`
+}
+
+// Validate checks the template, defaulting the status to pending and dropping blank
+// checklist items
+func (t *TaskTemplateInput) Validate() error {
+	t.Title = strings.TrimSpace(t.Title)
+	if t.Title == "" {
+		return errors.New("title is required")
+	}
+	if t.Status == "" {
+		t.Status = StatusPending
+	}
+	if !IsValidStatus(t.Status) {
+		return errors.New("invalid status")
+	}
+
+	checklist := make([]string, 0, len(t.Checklist))
+	for _, item := range t.Checklist {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if len(item) > MaxChecklistItemLength {
+			return fmt.Errorf("checklist items must be at most %d characters", MaxChecklistItemLength)
+		}
+		checklist = append(checklist, item)
+	}
+	if len(checklist) > MaxChecklistItems {
+		return fmt.Errorf("a template may have at most %d checklist items", MaxChecklistItems)
+	}
+	t.Checklist = checklist
+	return nil
+}
+
+// TaskTemplateInstantiate holds the task fields a template does not provide
+type TaskTemplateInstantiate struct {
+	DueDate    time.Time    `json:"due_date"`
+	Priority   TaskPriority `json:"priority,omitempty"`
+	AssigneeID string       `json:"assignee_id,omitempty"`
+	ParentID   string       `json:"parent_id,omitempty"`
+	ProjectKey string       `json:"project_key,omitempty"`
+}
+
+// NewTask builds the task to create from the template. The checklist is appended to
+// the description as a Markdown task list.
+func (t *TaskTemplate) NewTask(input *TaskTemplateInstantiate) *TaskCreate {
+	description := t.Description
+	if len(t.Checklist) > 0 {
+		var b strings.Builder
+		b.WriteString(description)
+		if description != "" {
+			b.WriteString("\n\n")
+		}
+		for i, item := range t.Checklist {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString("- [ ] " + item)
+		}
+		description = b.String()
+	}
+
+	return &TaskCreate{
+		Title:       t.Title,
+		Description: description,
+		Status:      t.Status,
+		Priority:    input.Priority,
+		DueDate:     input.DueDate,
+		AssigneeID:  input.AssigneeID,
+		ParentID:    input.ParentID,
+		ProjectKey:  input.ProjectKey,
+	}
+}
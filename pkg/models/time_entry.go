@@ -0,0 +1,118 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxEstimatedMinutes is the largest estimate of a task; MaxTimeEntryMinutes is the
+// most time a single entry may log
+const (
+	MaxEstimatedMinutes    = 100000
+	MaxTimeEntryMinutes    = 24 * 60
+	MaxTimeEntryNoteLength = 255
+)
+
+// TimeEntry is time a user spent on a task, either logged directly or measured by a
+// timer. A running timer has no EndedAt and does not count towards the task's total
+// until it is stopped.
+type TimeEntry struct {
+	ID        string     `json:"id"`
+	TaskID    string     `json:"task_id"`
+	UserID    string     `json:"user_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Minutes   int        `json:"minutes"`
+	Note      string     `json:"note,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IsRunning reports whether the entry is a timer that hasn't been stopped
+func (e *TimeEntry) IsRunning() bool {
+	return e.EndedAt == nil
+}
+
+// TimeEntryLog represents time logged directly, without a timer
+type TimeEntryLog struct {
+	Minutes   int        `json:"minutes"`
+	StartedAt *time.Time `json:"started_at,omitempty"` // defaults to Minutes before now
+	Note      string     `json:"note,omitempty"`
+	UserID    string     `json:"-"`
+}
+
+// Validate checks the logged time and fills in when the work started
+func (l *TimeEntryLog) Validate() error {
+	if l.Minutes < 1 || l.Minutes > MaxTimeEntryMinutes {
+		return fmt.Errorf("minutes must be between 1 and %d", MaxTimeEntryMinutes)
+	}
+	if l.StartedAt == nil {
+		startedAt := time.Now().Add(-time.Duration(l.Minutes) * time.Minute)
+		l.StartedAt = &startedAt
+	}
+	if l.StartedAt.After(time.Now()) {
+		return errors.New("started_at must not be in the future")
+	}
+	note, err := timeEntryNote(l.Note)
+	if err != nil {
+		return err
+	}
+	l.Note = note
+	return nil
+}
+
+// TimerStart represents starting a timer on a task
+type TimerStart struct {
+	Note   string `json:"note,omitempty"`
+	UserID string `json:"-"`
+}
+
+// Validate checks the timer's note
+func (t *TimerStart) Validate() error {
+	note, err := timeEntryNote(t.Note)
+	if err != nil {
+		return err
+	}
+	t.Note = note
+	return nil
+}
+
+// TimeSummary is the time tracked on a task against its estimate
+type TimeSummary struct {
+	EstimatedMinutes *int         `json:"estimated_minutes,omitempty"`
+	LoggedMinutes    int          `json:"logged_minutes"`
+	RemainingMinutes *int         `json:"remaining_minutes,omitempty"` // estimate less logged time, never negative
+	Entries          []*TimeEntry `json:"entries"`
+}
+
+// NewTimeSummary summarizes the time entries of a task
+func NewTimeSummary(task *Task, entries []*TimeEntry) *TimeSummary {
+	summary := &TimeSummary{
+		EstimatedMinutes: task.EstimatedMinutes,
+		LoggedMinutes:    task.LoggedMinutes,
+		Entries:          entries,
+	}
+	if task.EstimatedMinutes != nil {
+		remaining := max(*task.EstimatedMinutes-task.LoggedMinutes, 0)
+		summary.RemainingMinutes = &remaining
+	}
+	return summary
+}
+
+// validateEstimate checks a task's estimate; 0 means none
+func validateEstimate(minutes int) error {
+	if minutes < 0 || minutes > MaxEstimatedMinutes {
+		return fmt.Errorf("estimated_minutes must be between 0 and %d", MaxEstimatedMinutes)
+	}
+	return nil
+}
+
+// timeEntryNote trims a note and checks its length
+func timeEntryNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if len(note) > MaxTimeEntryNoteLength {
+		return "", fmt.Errorf("note must be at most %d characters", MaxTimeEntryNoteLength)
+	}
+	return note, nil
+}
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeFormat is how timestamps are written in JSON responses
+type TimeFormat string
+
+const (
+	// TimeFormatRFC3339Nano is Go's default encoding, with fractional seconds, e.g.
+	// "2024-05-02T09:00:00.123456Z"
+	TimeFormatRFC3339Nano TimeFormat = "rfc3339nano"
+	// TimeFormatRFC3339 drops fractional seconds, e.g. "2024-05-02T09:00:00Z"
+	TimeFormatRFC3339 TimeFormat = "rfc3339"
+	// TimeFormatEpochMillis writes milliseconds since the Unix epoch as a number
+	TimeFormatEpochMillis TimeFormat = "epoch_millis"
+	// TimeFormatDate writes only the date, e.g. "2024-05-02", in the timestamp's own offset
+	TimeFormatDate TimeFormat = "date"
+)
+
+// ParseTimeFormat parses a time format name; "" is TimeFormatRFC3339Nano
+func ParseTimeFormat(value string) (TimeFormat, error) {
+	switch format := TimeFormat(value); format {
+	case "":
+		return TimeFormatRFC3339Nano, nil
+	case TimeFormatRFC3339Nano, TimeFormatRFC3339, TimeFormatEpochMillis, TimeFormatDate:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown time format %q, expected rfc3339nano, rfc3339, epoch_millis or date", value)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so formats can be read from config
+func (f *TimeFormat) UnmarshalText(text []byte) error {
+	format, err := ParseTimeFormat(string(text))
+	if err != nil {
+		return err
+	}
+	*f = format
+	return nil
+}
+
+// MarshalTime encodes t as a JSON value in the format
+func (f TimeFormat) MarshalTime(t time.Time) []byte {
+	switch f {
+	case TimeFormatRFC3339:
+		return strconv.AppendQuote(nil, t.Format(time.RFC3339))
+	case TimeFormatEpochMillis:
+		return strconv.AppendInt(nil, t.UnixMilli(), 10)
+	case TimeFormatDate:
+		return strconv.AppendQuote(nil, t.Format(time.DateOnly))
+	default:
+		return strconv.AppendQuote(nil, t.Format(time.RFC3339Nano))
+	}
+}
+
+// timestampFields are the timestamp fields not named like "*_at", "*_date", "*_since" or
+// "*_until"
+var timestampFields = map[string]bool{"until": true, "sunset": true, "from": true, "to": true, "day": true, "due": true}
+
+// userDataFields hold objects whose keys and values come from clients, such as custom
+// fields, so nothing in them is a timestamp field whatever it is called
+var userDataFields = map[string]bool{"metadata": true, "properties": true, "headers": true, "columns": true, "body": true}
+
+// isTimestampField reports whether the JSON field with the name holds a timestamp
+func isTimestampField(name string) bool {
+	for _, suffix := range []string{"_at", "_date", "_since", "_until"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return timestampFields[name]
+}
+
+// ReformatJSON rewrites the timestamps in a JSON document written with Go's default
+// encoding into the format. Only the string values of timestamp fields are rewritten, so
+// titles, descriptions and custom fields that happen to look like timestamps are left
+// alone; the document is scanned once and the order of fields is kept.
+func (f TimeFormat) ReformatJSON(data []byte) []byte {
+	if f == TimeFormatRFC3339Nano {
+		return data
+	}
+
+	// Each open object or array, and whether it holds client data
+	type container struct {
+		object   bool
+		userData bool
+	}
+	var stack []container
+	inObject := func() bool { return len(stack) > 0 && stack[len(stack)-1].object }
+	inUserData := func() bool { return len(stack) > 0 && stack[len(stack)-1].userData }
+
+	var out bytes.Buffer
+	out.Grow(len(data))
+	key := ""          // the field whose value is being read, in the innermost object
+	expectKey := false // whether the next string is a field name
+	for i := 0; i < len(data); {
+		switch data[i] {
+		case '{', '[':
+			userData := inUserData() || (inObject() && userDataFields[key])
+			stack = append(stack, container{object: data[i] == '{', userData: userData})
+			expectKey = data[i] == '{'
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			expectKey = false
+		case ',':
+			expectKey = inObject()
+		case '"':
+			end := stringEnd(data, i)
+			literal := data[i:end]
+			i = end
+			if expectKey {
+				key = strings.Trim(string(literal), `"`)
+				expectKey = false
+			} else if inObject() && !inUserData() && isTimestampField(key) {
+				if t, ok := parseTimestamp(literal); ok {
+					out.Write(f.MarshalTime(t))
+					continue
+				}
+			}
+			out.Write(literal)
+			continue
+		}
+		out.WriteByte(data[i])
+		i++
+	}
+	return out.Bytes()
+}
+
+// stringEnd returns the index just past the JSON string starting at data[start]
+func stringEnd(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
+
+// parseTimestamp parses a JSON string literal holding an RFC3339 timestamp. Timestamps
+// contain no escapes, so the literal is the timestamp in quotes.
+func parseTimestamp(literal []byte) (time.Time, bool) {
+	// The shortest timestamp is "2006-01-02T15:04:05Z"
+	if len(literal) < len(`"2006-01-02T15:04:05Z"`) || literal[11] != 'T' {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, string(literal[1:len(literal)-1]))
+	return t, err == nil
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	// WebhookTestEvent is the type of the sample event sent to check a subscription
+	WebhookTestEvent = "webhook.test"
+	// MaxWebhookResponseBytes is how much of a receiver's response a delivery log keeps
+	MaxWebhookResponseBytes = 1024
+	// WebhookDeliveryRetention is how long delivery logs are kept
+	WebhookDeliveryRetention = 30 * 24 * time.Hour
+)
+
+// Webhook is a subscription posting task events to an integrator's URL
+type Webhook struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret signs deliveries; it is only shown when the webhook is created
+	Secret    string    `json:"-"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookCreate represents the data required to subscribe a URL to events
+type WebhookCreate struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// Validate checks if the webhook create request is valid
+func (w *WebhookCreate) Validate() error {
+	target, err := url.Parse(w.URL)
+	if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	if len(w.Events) == 0 {
+		return errors.New("at least one event is required")
+	}
+	return nil
+}
+
+// WebhookEvent is the body of a delivery
+type WebhookEvent struct {
+	// ID identifies the event; receivers can use it to drop deliveries they already handled
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// WebhookTaskData is the data of task events
+type WebhookTaskData struct {
+	TaskID string `json:"task_id"`
+	// Task is the task after the change; left out for task.deleted
+	Task *Task `json:"task,omitempty"`
+}
+
+// WebhookDelivery is the log of one attempt to deliver an event to a webhook
+type WebhookDelivery struct {
+	ID        string `json:"id"`
+	WebhookID string `json:"webhook_id"`
+	EventID   string `json:"event_id"`
+	EventType string `json:"event_type"`
+	Success   bool   `json:"success"`
+	// StatusCode is 0 when the receiver could not be reached
+	StatusCode int   `json:"status_code,omitempty"`
+	DurationMs int64 `json:"duration_ms"`
+	// Response is the start of the response body, up to MaxWebhookResponseBytes
+	Response    string    `json:"response,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Redelivery  bool      `json:"redelivery"`
+	DeliveredAt time.Time `json:"delivered_at"`
+	// Event is what was delivered, sent again on redelivery
+	Event *WebhookEvent `json:"event,omitempty"`
+}
@@ -0,0 +1,44 @@
+package models
+
+// UnestimatedTaskMinutes is the load counted for an open task without an estimate
+const UnestimatedTaskMinutes = 60
+
+// MaxWorkloadCandidates bounds the assignees without open tasks that may be offered work
+const MaxWorkloadCandidates = 50
+
+// AssigneeWorkload is the open work of one assignee in a project
+type AssigneeWorkload struct {
+	AssigneeID       string `json:"assignee_id"`
+	OpenTasks        int    `json:"open_tasks"`
+	InProgressTasks  int    `json:"in_progress_tasks"`
+	OverdueTasks     int    `json:"overdue_tasks"`
+	EstimatedMinutes int    `json:"estimated_minutes"` // sum of the estimates less time already logged
+	UnestimatedTasks int    `json:"unestimated_tasks"`
+	// LoadMinutes is the remaining estimated work, counting UnestimatedTaskMinutes for
+	// each task without an estimate; suggestions balance this
+	LoadMinutes int `json:"load_minutes"`
+}
+
+// ComputeLoad sets LoadMinutes from the estimates and the number of unestimated tasks
+func (w *AssigneeWorkload) ComputeLoad() {
+	w.LoadMinutes = w.EstimatedMinutes + w.UnestimatedTasks*UnestimatedTaskMinutes
+}
+
+// Reassignment proposes moving a task to another assignee
+type Reassignment struct {
+	TaskID      string `json:"task_id"`
+	TaskKey     string `json:"task_key"`
+	Title       string `json:"title"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	LoadMinutes int    `json:"load_minutes"`
+}
+
+// Workload summarizes the open tasks of a project per assignee
+type Workload struct {
+	ProjectKey string              `json:"project_key"`
+	Assignees  []*AssigneeWorkload `json:"assignees"`  // most loaded first
+	Unassigned *AssigneeWorkload   `json:"unassigned"` // open tasks without an assignee
+	// Suggestions are only computed on request; null otherwise
+	Suggestions []*Reassignment `json:"suggestions"`
+}
@@ -0,0 +1,158 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Result describes the outcome of a content check
+type Result struct {
+	Flagged bool     `json:"flagged"`
+	Reasons []string `json:"reasons"`
+}
+
+// Checker inspects user-generated text for spam or abuse
+type Checker interface {
+	Check(ctx context.Context, text string) (*Result, error)
+}
+
+var urlPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// DefaultBlocklist is the built-in list of blocked words
+var DefaultBlocklist = []string{
+	"viagra",
+	"casino",
+	"crypto giveaway",
+	"free money",
+}
+
+// HeuristicChecker flags blocklisted words and excessive links
+type HeuristicChecker struct {
+	blocklist []string
+	maxURLs   int
+}
+
+// NewHeuristicChecker creates a checker with the given blocklist and link limit
+func NewHeuristicChecker(blocklist []string, maxURLs int) *HeuristicChecker {
+	words := make([]string, 0, len(blocklist))
+	for _, word := range blocklist {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			words = append(words, word)
+		}
+	}
+
+	return &HeuristicChecker{
+		blocklist: words,
+		maxURLs:   maxURLs,
+	}
+}
+
+// Check implements Checker
+func (c *HeuristicChecker) Check(ctx context.Context, text string) (*Result, error) {
+	result := &Result{}
+	lower := strings.ToLower(text)
+
+	for _, word := range c.blocklist {
+		if strings.Contains(lower, word) {
+			result.Flagged = true
+			result.Reasons = append(result.Reasons, fmt.Sprintf("blocked term: %s", word))
+		}
+	}
+
+	if urls := urlPattern.FindAllString(text, -1); len(urls) > c.maxURLs {
+		result.Flagged = true
+		result.Reasons = append(result.Reasons, fmt.Sprintf("too many links: %d", len(urls)))
+	}
+
+	return result, nil
+}
+
+// ExternalChecker delegates checks to an HTTP moderation API.
+// The API receives {"text": "..."} and must respond with a Result.
+type ExternalChecker struct {
+	url        string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewExternalChecker creates a checker backed by an external moderation API
+func NewExternalChecker(url, apiKey string) *ExternalChecker {
+	return &ExternalChecker{
+		url:        url,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+// Check implements Checker
+func (c *ExternalChecker) Check(ctx context.Context, text string) (*Result, error) {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("moderation API returned status %d", resp.StatusCode)
+	}
+
+	var result Result
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// MultiChecker runs several checkers and merges their results.
+// A failing checker doesn't stop the others; the first error is returned alongside the merged result.
+type MultiChecker struct {
+	checkers []Checker
+}
+
+// NewMultiChecker combines checkers into one
+func NewMultiChecker(checkers ...Checker) *MultiChecker {
+	return &MultiChecker{checkers: checkers}
+}
+
+// Check implements Checker
+func (c *MultiChecker) Check(ctx context.Context, text string) (*Result, error) {
+	merged := &Result{}
+	var firstErr error
+
+	for _, checker := range c.checkers {
+		result, err := checker.Check(ctx, text)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if result.Flagged {
+			merged.Flagged = true
+			merged.Reasons = append(merged.Reasons, result.Reasons...)
+		}
+	}
+
+	return merged, firstErr
+}
@@ -0,0 +1,164 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockModerationRepository is a mock implementation of ModerationRepository
+type MockModerationRepository struct {
+	mock.Mock
+}
+
+func (m *MockModerationRepository) CreateFlag(ctx context.Context, flag *models.ModerationFlag) (*models.ModerationFlag, error) {
+	args := m.Called(ctx, flag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ModerationFlag), args.Error(1)
+}
+
+func (m *MockModerationRepository) GetFlag(ctx context.Context, id string) (*models.ModerationFlag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ModerationFlag), args.Error(1)
+}
+
+func (m *MockModerationRepository) ListFlags(ctx context.Context, status models.FlagStatus, page, limit int) ([]*models.ModerationFlag, int, error) {
+	args := m.Called(ctx, status, page, limit)
+	return args.Get(0).([]*models.ModerationFlag), args.Int(1), args.Error(2)
+}
+
+func (m *MockModerationRepository) ReviewFlag(ctx context.Context, id string, status models.FlagStatus, reviewer string) (*models.ModerationFlag, error) {
+	args := m.Called(ctx, id, status, reviewer)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ModerationFlag), args.Error(1)
+}
+
+type failingChecker struct{}
+
+func (failingChecker) Check(ctx context.Context, text string) (*Result, error) {
+	return nil, errors.New("moderation API unavailable")
+}
+
+func TestHeuristicChecker_Check(t *testing.T) {
+	checker := NewHeuristicChecker([]string{"Casino", " "}, 1)
+
+	tests := []struct {
+		name        string
+		text        string
+		wantFlagged bool
+	}{
+		{"clean text", "Write the quarterly report", false},
+		{"blocked term is case insensitive", "Visit our CASINO tonight", true},
+		{"single link allowed", "See https://example.com/spec", false},
+		{"too many links", "https://a.example www.b.example", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := checker.Check(context.Background(), tt.text)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFlagged, result.Flagged)
+			if tt.wantFlagged {
+				assert.NotEmpty(t, result.Reasons)
+			}
+		})
+	}
+}
+
+func TestExternalChecker_Check(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"flagged": true, "reasons": ["toxicity"]}`))
+	}))
+	defer server.Close()
+
+	result, err := NewExternalChecker(server.URL, "secret").Check(context.Background(), "some text")
+	require.NoError(t, err)
+	assert.True(t, result.Flagged)
+	assert.Equal(t, []string{"toxicity"}, result.Reasons)
+}
+
+func TestModerator_Check(t *testing.T) {
+	heuristic := NewHeuristicChecker(DefaultBlocklist, 2)
+
+	tests := []struct {
+		name        string
+		checker     Checker
+		mode        Mode
+		texts       []string
+		wantFlagged bool
+		wantErr     error
+	}{
+		{
+			name:    "block mode rejects flagged content",
+			checker: heuristic,
+			mode:    ModeBlock,
+			texts:   []string{"Free money", ""},
+			wantErr: ErrContentRejected,
+		},
+		{
+			name:        "flag mode accepts flagged content",
+			checker:     heuristic,
+			mode:        ModeFlag,
+			texts:       []string{"Free money"},
+			wantFlagged: true,
+		},
+		{
+			name:    "off mode skips checks",
+			checker: heuristic,
+			mode:    ModeOff,
+			texts:   []string{"Free money"},
+		},
+		{
+			name:    "checker failure allows content",
+			checker: NewMultiChecker(failingChecker{}),
+			mode:    ModeBlock,
+			texts:   []string{"Free money"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewModerator(tt.checker, tt.mode, nil).Check(context.Background(), tt.texts...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFlagged, result.Flagged)
+		})
+	}
+}
+
+func TestModerator_Flag(t *testing.T) {
+	repo := new(MockModerationRepository)
+	moderator := NewModerator(NewHeuristicChecker(nil, 0), ModeFlag, repo)
+
+	repo.On("CreateFlag", mock.Anything, mock.MatchedBy(func(flag *models.ModerationFlag) bool {
+		return flag.ResourceType == "task" && flag.ResourceID == "task-1"
+	})).Return(&models.ModerationFlag{ID: "flag-1"}, nil).Once()
+
+	err := moderator.Flag(context.Background(), "task", "task-1", &Result{Flagged: true, Reasons: []string{"spam"}})
+	assert.NoError(t, err)
+
+	// Clean content is never queued
+	err = moderator.Flag(context.Background(), "task", "task-2", &Result{})
+	assert.NoError(t, err)
+
+	repo.AssertExpectations(t)
+}
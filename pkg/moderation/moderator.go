@@ -0,0 +1,101 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// ErrContentRejected is returned when content is blocked by moderation
+var ErrContentRejected = errors.New("content rejected by moderation")
+
+// Mode controls what happens to flagged content
+type Mode string
+
+const (
+	// ModeOff disables moderation
+	ModeOff Mode = "off"
+	// ModeFlag accepts flagged content and queues it for admin review
+	ModeFlag Mode = "flag"
+	// ModeBlock rejects flagged content
+	ModeBlock Mode = "block"
+)
+
+// ParseMode converts a configuration value into a Mode
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(value)); mode {
+	case ModeOff, ModeFlag, ModeBlock:
+		return mode, nil
+	case "":
+		return ModeOff, nil
+	default:
+		return "", fmt.Errorf("unknown moderation mode: %s", value)
+	}
+}
+
+// Moderator applies a Checker to user-generated content according to a Mode
+type Moderator struct {
+	checker Checker
+	mode    Mode
+	flags   repository.ModerationRepository
+}
+
+// NewModerator creates a new moderator; flags may be nil unless mode is ModeFlag
+func NewModerator(checker Checker, mode Mode, flags repository.ModerationRepository) *Moderator {
+	return &Moderator{
+		checker: checker,
+		mode:    mode,
+		flags:   flags,
+	}
+}
+
+// Check inspects the given texts. In block mode flagged content returns
+// ErrContentRejected. Checker failures are logged and the content is allowed.
+func (m *Moderator) Check(ctx context.Context, texts ...string) (*Result, error) {
+	if m.mode == ModeOff {
+		return &Result{}, nil
+	}
+
+	var nonEmpty []string
+	for _, text := range texts {
+		if text != "" {
+			nonEmpty = append(nonEmpty, text)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return &Result{}, nil
+	}
+
+	result, err := m.checker.Check(ctx, strings.Join(nonEmpty, "\n"))
+	if err != nil {
+		log.Printf("Content moderation check failed: %v", err)
+		if result == nil {
+			return &Result{}, nil
+		}
+	}
+
+	if result.Flagged && m.mode == ModeBlock {
+		return result, fmt.Errorf("%w: %s", ErrContentRejected, strings.Join(result.Reasons, ", "))
+	}
+
+	return result, nil
+}
+
+// Flag queues flagged content for admin review. It is a no-op for clean results.
+func (m *Moderator) Flag(ctx context.Context, resourceType, resourceID string, result *Result) error {
+	if result == nil || !result.Flagged || m.mode != ModeFlag || m.flags == nil {
+		return nil
+	}
+
+	_, err := m.flags.CreateFlag(ctx, &models.ModerationFlag{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Reasons:      result.Reasons,
+	})
+	return err
+}
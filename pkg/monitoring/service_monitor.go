@@ -11,6 +11,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+
+	"sample/task-management-system/pkg/metrics"
 )
 
 // ServiceState represents different states of service components
@@ -31,6 +33,7 @@ type ServiceMonitor struct {
 	statesMutex sync.RWMutex
 	interval    time.Duration
 	stopCh      chan struct{}
+	circuit     *metrics.Breaker // skips CloudWatch calls while it is unreachable
 }
 
 // NewServiceMonitor creates a new service monitor
@@ -42,6 +45,7 @@ func NewServiceMonitor(client CloudWatchClient, alarmSvc AlarmService, namespace
 		states:    make(map[string]*ServiceState),
 		interval:  interval,
 		stopCh:    make(chan struct{}),
+		circuit:   metrics.Circuit(),
 	}
 }
 
@@ -77,15 +81,26 @@ func (sm *ServiceMonitor) UpdateServiceState(state ServiceState) error {
 	}
 
 	sm.statesMutex.Lock()
-	defer sm.statesMutex.Unlock()
-
 	sm.states[state.Name] = &state
+	sm.statesMutex.Unlock()
 
 	// Skip metric publishing if metrics are disabled
 	if os.Getenv("ENABLE_METRICS") != "true" {
 		return nil
 	}
 
+	// Don't make callers wait on CloudWatch while it is known to be unreachable
+	if !sm.circuit.Allow() {
+		return metrics.ErrCircuitOpen
+	}
+
+	err := sm.publishState(state)
+	sm.circuit.Record(err)
+	return err
+}
+
+// publishState publishes the status and custom metrics of a service to CloudWatch
+func (sm *ServiceMonitor) publishState(state ServiceState) error {
 	// Create context with timeout for metric publishing
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -171,10 +186,11 @@ func (sm *ServiceMonitor) checkAndUpdateStates(ctx context.Context) {
 				},
 			}
 
-			err := sm.alarmSvc.CreateAlarm(ctx, alarm)
-			if err != nil {
-				log.Printf("Failed to create/update alarm for service %s: %v", name, err)
+			// Failures are reported by the circuit when it opens, not once per service
+			if !sm.circuit.Allow() {
+				return
 			}
+			sm.circuit.Record(sm.alarmSvc.CreateAlarm(ctx, alarm))
 		}
 	}
 }
@@ -214,7 +230,12 @@ func (sm *ServiceMonitor) CreateServiceAlarm(ctx context.Context, serviceName, a
 		},
 	}
 
-	return sm.alarmSvc.CreateAlarm(ctx, alarm)
+	if !sm.circuit.Allow() {
+		return metrics.ErrCircuitOpen
+	}
+	err := sm.alarmSvc.CreateAlarm(ctx, alarm)
+	sm.circuit.Record(err)
+	return err
 }
 
 // IsAlarmsEnabled returns whether alarms are enabled
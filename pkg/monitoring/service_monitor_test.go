@@ -2,6 +2,7 @@ package monitoring
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -9,6 +10,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/metrics"
 )
 
 // MockCloudWatchClient is a mock implementation of CloudWatchClient
@@ -158,4 +161,30 @@ func TestServiceMonitor_CheckAndUpdateStates(t *testing.T) {
 	monitor.checkAndUpdateStates(context.Background())
 
 	mockAlarmService.AssertExpectations(t)
-} 
\ No newline at end of file
+} 
+func TestServiceMonitor_SkipsCloudWatchWhileUnreachable(t *testing.T) {
+	mockClient := &MockCloudWatchClient{}
+	mockAlarmService := &MockAlarmService{}
+	monitor := NewServiceMonitor(mockClient, mockAlarmService, "TestNamespace", time.Minute)
+	monitor.circuit = metrics.NewBreaker("test", time.Hour, time.Hour)
+
+	os.Setenv("ENABLE_METRICS", "true")
+	defer os.Unsetenv("ENABLE_METRICS")
+
+	mockClient.On("PutMetricData", mock.Anything, mock.Anything).
+		Return(&cloudwatch.PutMetricDataOutput{}, errors.New("no route to host")).Once()
+
+	state := ServiceState{Name: "TestService", Status: "UP"}
+	assert.Error(t, monitor.UpdateServiceState(state))
+
+	// Further updates are still stored but don't call CloudWatch until the backoff elapses
+	state.Status = "DOWN"
+	assert.ErrorIs(t, monitor.UpdateServiceState(state), metrics.ErrCircuitOpen)
+	assert.Equal(t, "DOWN", monitor.states["TestService"].Status)
+
+	mockAlarmService.On("IsAlarmsEnabled").Return(true)
+	assert.ErrorIs(t, monitor.CreateServiceAlarm(context.Background(), "TestService", "TestAlarm", 0.5, LessThanThreshold), metrics.ErrCircuitOpen)
+
+	mockClient.AssertExpectations(t)
+	mockAlarmService.AssertNotCalled(t, "CreateAlarm", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"sample/task-management-system/pkg/models"
+)
+
+var (
+	deprecationText = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/deprecation.txt.tmpl"))
+	deprecationHTML = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/deprecation.html.tmpl"))
+)
+
+// RenderDeprecationNotice renders the email telling the given address which of their
+// clients still use a deprecation and when it stops working
+func RenderDeprecationNotice(to string, deprecation *models.Deprecation, clients []*models.DeprecationUsage) (*Message, error) {
+	data := struct {
+		Deprecation *models.Deprecation
+		Clients     []*models.DeprecationUsage
+	}{deprecation, clients}
+
+	var text bytes.Buffer
+	if err := deprecationText.Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	var html bytes.Buffer
+	if err := deprecationHTML.Execute(&html, data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      to,
+		Subject: deprecation.ID + " stops working on " + deprecation.Sunset.UTC().Format(updatedLayout),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
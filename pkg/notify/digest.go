@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"sample/task-management-system/pkg/models"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// digestSection is a titled list of tasks in a digest
+type digestSection struct {
+	Title string
+	Tasks []*models.Task
+}
+
+func section(title string, tasks []*models.Task) digestSection {
+	return digestSection{Title: title, Tasks: tasks}
+}
+
+// dueDate is replaced when rendering, to show due dates in the recipient's time zone
+func dueDate(task *models.Task) string {
+	return task.DueDate.Format(dueDateLayout)
+}
+
+const dueDateLayout = "Mon Jan 2 15:04 MST"
+
+var (
+	digestText = texttemplate.Must(texttemplate.New("digest.txt.tmpl").
+			Funcs(texttemplate.FuncMap{"section": section, "due": dueDate}).
+			ParseFS(templateFS, "templates/digest.txt.tmpl"))
+	digestHTML = htmltemplate.Must(htmltemplate.New("digest.html.tmpl").
+			Funcs(htmltemplate.FuncMap{"section": section, "due": dueDate}).
+			ParseFS(templateFS, "templates/digest.html.tmpl"))
+)
+
+// RenderDigest renders the digest email for the given address. The unsubscribe link is
+// also offered as one-click List-Unsubscribe (RFC 8058).
+func RenderDigest(to string, digest *models.Digest) (*Message, error) {
+	due := func(task *models.Task) string {
+		return task.DueDate.In(digest.Location).Format(dueDateLayout)
+	}
+
+	var text bytes.Buffer
+	textTmpl, err := digestText.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if err := textTmpl.Funcs(texttemplate.FuncMap{"due": due}).Execute(&text, digest); err != nil {
+		return nil, err
+	}
+
+	var html bytes.Buffer
+	htmlTmpl, err := digestHTML.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if err := htmlTmpl.Funcs(htmltemplate.FuncMap{"due": due}).Execute(&html, digest); err != nil {
+		return nil, err
+	}
+
+	subject := "Your " + string(digest.Frequency) + " deadlines digest"
+	if n := len(digest.Overdue); n > 0 {
+		subject += fmt.Sprintf(": %d overdue", n)
+	}
+
+	headers := map[string]string{
+		"List-Unsubscribe":      "<" + digest.UnsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	if digest.Locale != "" {
+		headers["Content-Language"] = digest.Locale
+	}
+
+	return &Message{
+		To:      to,
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+		Headers: headers,
+	}, nil
+}
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"sample/task-management-system/pkg/models"
+)
+
+var (
+	invitationText = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/invitation.txt.tmpl"))
+	invitationHTML = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/invitation.html.tmpl"))
+)
+
+// RenderInvitation renders the email inviting the invitation's address to its organization.
+// acceptURL carries the invitation token, so the message must only go to that address.
+func RenderInvitation(invitation *models.Invitation, acceptURL string) (*Message, error) {
+	data := struct {
+		Invitation *models.Invitation
+		AcceptURL  string
+	}{invitation, acceptURL}
+
+	var text bytes.Buffer
+	if err := invitationText.Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	var html bytes.Buffer
+	if err := invitationHTML.Execute(&html, data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      invitation.Email,
+		Subject: "You are invited to join " + invitation.OrgID,
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+func TestRenderDigest(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	due := time.Date(2024, 3, 4, 16, 0, 0, 0, time.UTC)
+
+	msg, err := RenderDigest("someone@example.com", &models.Digest{
+		Frequency:      models.DigestDaily,
+		DueSoon:        []*models.Task{{Key: "PROJ-2", Title: "Ship <release>", DueDate: due}},
+		UnsubscribeURL: "https://tasks.example.com/unsubscribe?token=abc",
+		Location:       berlin,
+		Locale:         "de-DE",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Your daily deadlines digest", msg.Subject)
+	assert.Equal(t, "Your daily deadlines digest\n\nDue soon:\n  - PROJ-2 Ship <release> (due Mon Mar 4 17:00 CET)\n\n"+
+		"--\nTo stop receiving this digest, open https://tasks.example.com/unsubscribe?token=abc\n", msg.Text)
+	assert.Contains(t, msg.HTML, "Ship &lt;release&gt;")
+	assert.NotContains(t, msg.HTML, "Overdue")
+	assert.Equal(t, "<https://tasks.example.com/unsubscribe?token=abc>", msg.Headers["List-Unsubscribe"])
+	assert.Equal(t, "de-DE", msg.Headers["Content-Language"])
+}
+
+func TestEncode(t *testing.T) {
+	body, err := encode("Task Manager <noreply@example.com>", &Message{
+		To:      "someone@example.com",
+		Subject: "Your daily deadlines digest",
+		Text:    "text body",
+		HTML:    "<p>html body</p>",
+		Headers: map[string]string{"List-Unsubscribe-Post": "List-Unsubscribe=One-Click"},
+	})
+	require.NoError(t, err)
+
+	encoded := string(body)
+	assert.Contains(t, encoded, "List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	assert.Contains(t, encoded, "Content-Type: multipart/alternative; boundary=")
+	assert.Less(t, strings.Index(encoded, "text body"), strings.Index(encoded, "<p>html body</p>"))
+}
+
+func TestUnsubscribeSigner(t *testing.T) {
+	signer := NewUnsubscribeSigner([]byte("test-secret"), "test-issuer")
+
+	token, err := signer.Sign("user-1")
+	require.NoError(t, err)
+	userID, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", userID)
+
+	other, err := NewUnsubscribeSigner([]byte("other-secret"), "test-issuer").Sign("user-1")
+	require.NoError(t, err)
+	_, err = signer.Verify(other)
+	assert.ErrorIs(t, err, ErrInvalidUnsubscribeToken)
+}
+
+func TestRenderStaleAlert(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	updated := time.Date(2024, 3, 4, 20, 0, 0, 0, time.UTC)
+
+	msg, err := RenderStaleAlert("someone@example.com", []*models.Task{
+		{Key: "PROJ-7", Title: "Write <docs>", UpdatedAt: updated},
+	}, tokyo)
+	require.NoError(t, err)
+
+	assert.Equal(t, "PROJ-7 is stale and needs your attention", msg.Subject)
+	assert.Contains(t, msg.Text, "  - PROJ-7 Write <docs> (last updated Tue Mar 5 2024)\n")
+	assert.Contains(t, msg.HTML, "Write &lt;docs&gt;")
+}
+
+func TestRenderInvitation(t *testing.T) {
+	msg, err := RenderInvitation(&models.Invitation{
+		OrgID:     "acme",
+		Email:     "new@example.com",
+		Role:      "org_admin",
+		InvitedBy: "admin-1",
+		ExpiresAt: time.Date(2024, 3, 11, 9, 30, 0, 0, time.UTC),
+	}, "https://tasks.example.com/invitations/accept?token=abc&x=<y>")
+	require.NoError(t, err)
+
+	assert.Equal(t, "new@example.com", msg.To)
+	assert.Equal(t, "You are invited to join acme", msg.Subject)
+	assert.Contains(t, msg.Text, "You are invited to join acme as org_admin\n")
+	assert.Contains(t, msg.Text, "https://tasks.example.com/invitations/accept?token=abc&x=<y>\n")
+	assert.Contains(t, msg.Text, "expires on Mon Mar 11 2024 09:30 UTC")
+	assert.Contains(t, msg.HTML, `href="https://tasks.example.com/invitations/accept?token=abc&amp;x=%3cy%3e"`)
+}
+
+func TestRenderPasswordReset(t *testing.T) {
+	msg, err := RenderPasswordReset(&models.Account{ID: "user-1", Email: "dev@example.com"},
+		"https://tasks.example.com/account/reset?token=abc&x=<y>", time.Date(2024, 3, 11, 9, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, "dev@example.com", msg.To)
+	assert.Equal(t, "Reset your password", msg.Subject)
+	assert.Contains(t, msg.Text, "https://tasks.example.com/account/reset?token=abc&x=<y>\n")
+	assert.Contains(t, msg.Text, "expires on Mon Mar 11 2024 09:30 UTC")
+	assert.Contains(t, msg.HTML, `href="https://tasks.example.com/account/reset?token=abc&amp;x=%3cy%3e"`)
+}
+
+func TestRenderDeprecationNotice(t *testing.T) {
+	lastSeen := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)
+	msg, err := RenderDeprecationNotice("dev@example.com", &models.Deprecation{
+		ID:          "tasks-per-page",
+		Kind:        models.DeprecatedParameter,
+		Sunset:      time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC),
+		Replacement: "limit",
+	}, []*models.DeprecationUsage{
+		{ClientKind: models.DeprecationClientAPIKey, Client: "key-1", Requests: 12, LastSeenAt: lastSeen},
+		{ClientKind: models.DeprecationClientUserAgent, Client: "sync-bot/<2>", Requests: 3, LastSeenAt: lastSeen},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "dev@example.com", msg.To)
+	assert.Equal(t, "tasks-per-page stops working on Sun Jun 30 2024", msg.Subject)
+	assert.Contains(t, msg.Text, "  - API key key-1 (12 requests, last on Mon Mar 4 2024)\n")
+	assert.Contains(t, msg.Text, "  - sync-bot/<2> (3 requests, last on Mon Mar 4 2024)\n")
+	assert.Contains(t, msg.Text, "Use limit instead before the sunset date")
+	assert.Contains(t, msg.HTML, "sync-bot/&lt;2&gt;")
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+var (
+	passwordResetText = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/password_reset.txt.tmpl"))
+	passwordResetHTML = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/password_reset.html.tmpl"))
+)
+
+// RenderPasswordReset renders the email with a link to reset the account's password.
+// resetURL carries the reset token, so the message must only go to the account's address.
+func RenderPasswordReset(account *models.Account, resetURL string, expiresAt time.Time) (*Message, error) {
+	data := struct {
+		Account   *models.Account
+		ResetURL  string
+		ExpiresAt time.Time
+	}{account, resetURL, expiresAt}
+
+	var text bytes.Buffer
+	if err := passwordResetText.Execute(&text, data); err != nil {
+		return nil, err
+	}
+
+	var html bytes.Buffer
+	if err := passwordResetHTML.Execute(&html, data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      account.Email,
+		Subject: "Reset your password",
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
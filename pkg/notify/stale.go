@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+const updatedLayout = "Mon Jan 2 2006"
+
+var (
+	staleText = texttemplate.Must(texttemplate.New("stale.txt.tmpl").
+			Funcs(texttemplate.FuncMap{"updated": lastUpdated(time.UTC)}).
+			ParseFS(templateFS, "templates/stale.txt.tmpl"))
+	staleHTML = htmltemplate.Must(htmltemplate.New("stale.html.tmpl").
+			Funcs(htmltemplate.FuncMap{"updated": lastUpdated(time.UTC)}).
+			ParseFS(templateFS, "templates/stale.html.tmpl"))
+)
+
+// lastUpdated formats when a task was last updated in the recipient's time zone
+func lastUpdated(location *time.Location) func(task *models.Task) string {
+	return func(task *models.Task) string {
+		return task.UpdatedAt.In(location).Format(updatedLayout)
+	}
+}
+
+// RenderStaleAlert renders the email telling the given address which of their tasks
+// were flagged as stale
+func RenderStaleAlert(to string, tasks []*models.Task, location *time.Location) (*Message, error) {
+	updated := lastUpdated(location)
+
+	var text bytes.Buffer
+	textTmpl, err := staleText.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if err := textTmpl.Funcs(texttemplate.FuncMap{"updated": updated}).Execute(&text, tasks); err != nil {
+		return nil, err
+	}
+
+	var html bytes.Buffer
+	htmlTmpl, err := staleHTML.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if err := htmlTmpl.Funcs(htmltemplate.FuncMap{"updated": updated}).Execute(&html, tasks); err != nil {
+		return nil, err
+	}
+
+	subject := fmt.Sprintf("%d stale tasks need your attention", len(tasks))
+	if len(tasks) == 1 {
+		subject = tasks[0].Key + " is stale and needs your attention"
+	}
+
+	return &Message{
+		To:      to,
+		Subject: subject,
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
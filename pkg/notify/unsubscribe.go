@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// UnsubscribeAudience is the "aud" claim of unsubscribe tokens; it keeps them from being
+// accepted as access or share tokens
+const UnsubscribeAudience = "digest-unsubscribe"
+
+// unsubscribeTokenTTL is how long the link in an email keeps working
+const unsubscribeTokenTTL = 90 * 24 * time.Hour
+
+// ErrInvalidUnsubscribeToken is returned for forged, expired or malformed tokens
+var ErrInvalidUnsubscribeToken = errors.New("invalid or expired unsubscribe link")
+
+// UnsubscribeSigner issues and verifies the tokens in unsubscribe links, so users can
+// unsubscribe without signing in
+type UnsubscribeSigner struct {
+	secret []byte
+	issuer string
+}
+
+// NewUnsubscribeSigner creates an unsubscribe token signer
+func NewUnsubscribeSigner(secret []byte, issuer string) *UnsubscribeSigner {
+	return &UnsubscribeSigner{secret: secret, issuer: issuer}
+}
+
+// Sign creates a token unsubscribing userID
+func (s *UnsubscribeSigner) Sign(userID string) (string, error) {
+	now := time.Now()
+	claims := &jwt.RegisteredClaims{
+		Subject:   userID,
+		Issuer:    s.issuer,
+		Audience:  jwt.ClaimStrings{UnsubscribeAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(unsubscribeTokenTTL)),
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
+
+// Verify checks a token and returns the user it unsubscribes
+func (s *UnsubscribeSigner) Verify(token string) (string, error) {
+	claims := &jwt.RegisteredClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(UnsubscribeAudience),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !parsed.Valid || claims.Subject == "" {
+		return "", ErrInvalidUnsubscribeToken
+	}
+
+	return claims.Subject, nil
+}
@@ -0,0 +1,18 @@
+// Package prefetch marks requests the server makes to itself to warm the response cache,
+// so work done on behalf of a caller, such as recording views or counting requests
+// against their rate limit, can skip them.
+package prefetch
+
+import "context"
+
+type contextKey struct{}
+
+// With marks ctx as the context of a prefetch
+func With(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey{}, true)
+}
+
+// FromContext reports whether ctx is the context of a prefetch
+func FromContext(ctx context.Context) bool {
+	return ctx.Value(contextKey{}) != nil
+}
@@ -0,0 +1,231 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// Priority orders job types competing for a worker's job slots
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+// ParsePriority parses "low", "normal" or "high"
+func ParsePriority(value string) (Priority, error) {
+	switch strings.TrimSpace(value) {
+	case "low":
+		return PriorityLow, nil
+	case "normal":
+		return PriorityNormal, nil
+	case "high":
+		return PriorityHigh, nil
+	default:
+		return 0, fmt.Errorf("unknown priority %q, expected low, normal or high", value)
+	}
+}
+
+// Limits are the rate controls of one job type
+type Limits struct {
+	Priority Priority
+	// Concurrency is how many of the type's jobs run at once, at least 1
+	Concurrency int
+	// Rate caps how many of the type's jobs start per second; 0 leaves it uncapped
+	Rate float64
+}
+
+// JobQueue is a queue a lane reads jobs from, e.g. SQSQueue or cache.JobStream
+type JobQueue interface {
+	Publish(ctx context.Context, jobID string) error
+	Consume(ctx context.Context, handle func(ctx context.Context, jobID string) error)
+}
+
+// Scheduler shares a worker's job slots between job types. Each type reads its own queue
+// in a lane; when slots run out, waiting jobs of higher priority lanes get the next free
+// slot, so a flood of one type can't hold back jobs of a more important one.
+type Scheduler struct {
+	mu      sync.Mutex
+	free    int
+	waiting []*slotWaiter
+}
+
+// slotWaiter is a job waiting for a slot; ready is closed when the slot is granted
+type slotWaiter struct {
+	priority Priority
+	ready    chan struct{}
+}
+
+// NewScheduler creates a scheduler running up to slots jobs at once
+func NewScheduler(slots int) *Scheduler {
+	if slots < 1 {
+		slots = 1
+	}
+	return &Scheduler{free: slots}
+}
+
+// Lane wraps a job type's queue so its jobs run within limits and the scheduler's slots
+func (s *Scheduler) Lane(name string, queue JobQueue, limits Limits) *Lane {
+	if limits.Concurrency < 1 {
+		limits.Concurrency = 1
+	}
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if limits.Rate > 0 {
+		limiter = rate.NewLimiter(rate.Limit(limits.Rate), 1)
+	}
+	return &Lane{name: name, queue: queue, limits: limits, limiter: limiter, scheduler: s}
+}
+
+// acquire waits for a free slot, granted to higher priorities first and in arrival order
+// within a priority
+func (s *Scheduler) acquire(ctx context.Context, priority Priority) error {
+	s.mu.Lock()
+	if s.free > 0 && len(s.waiting) == 0 {
+		s.free--
+		s.mu.Unlock()
+		return nil
+	}
+	waiter := &slotWaiter{priority: priority, ready: make(chan struct{})}
+	s.waiting = append(s.waiting, waiter)
+	s.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, other := range s.waiting {
+		if other == waiter {
+			s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+			return ctx.Err()
+		}
+	}
+	// The slot was granted as ctx was done; pass it on
+	s.grant()
+	return ctx.Err()
+}
+
+// release frees a slot for the next waiting job
+func (s *Scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grant()
+}
+
+// grant gives a freed slot to the first waiting job of the highest priority. s.mu must
+// be held.
+func (s *Scheduler) grant() {
+	if len(s.waiting) == 0 {
+		s.free++
+		return
+	}
+	next := 0
+	for i, waiter := range s.waiting {
+		if waiter.priority > s.waiting[next].priority {
+			next = i
+		}
+	}
+	close(s.waiting[next].ready)
+	s.waiting = append(s.waiting[:next], s.waiting[next+1:]...)
+}
+
+// Lane is a job type's queue run under its limits
+type Lane struct {
+	name      string
+	queue     JobQueue
+	limits    Limits
+	limiter   *rate.Limiter
+	scheduler *Scheduler
+}
+
+// Publish queues a job
+func (l *Lane) Publish(ctx context.Context, jobID string) error {
+	return l.queue.Publish(ctx, jobID)
+}
+
+// Consume reads the queue with Concurrency consumers until ctx is done. Each job waits
+// for the lane's rate cap and a scheduler slot before handle runs it.
+func (l *Lane) Consume(ctx context.Context, handle func(ctx context.Context, jobID string) error) {
+	limited := func(ctx context.Context, jobID string) error {
+		if err := l.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		if err := l.scheduler.acquire(ctx, l.limits.Priority); err != nil {
+			return err
+		}
+		defer l.scheduler.release()
+		return handle(ctx, jobID)
+	}
+
+	log.Printf("Job lane %s: %s priority, concurrency %d, rate %g/s", l.name, l.limits.Priority, l.limits.Concurrency, l.limits.Rate)
+	var wg sync.WaitGroup
+	for i := 0; i < l.limits.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.queue.Consume(ctx, limited)
+		}()
+	}
+	wg.Wait()
+}
+
+// ParseLimits parses job type limits such as "exports=high/2/5,imports=low/1/0.5": each
+// entry is type=priority/concurrency/rate, with rate in jobs started per second and 0
+// for no cap. Trailing parts may be left out.
+func ParseLimits(value string) (map[string]Limits, error) {
+	limits := make(map[string]Limits)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid job limits %q, expected type=priority/concurrency/rate", entry)
+		}
+
+		fields := strings.Split(parts[1], "/")
+		if len(fields) > 3 {
+			return nil, fmt.Errorf("invalid job limits %q, expected type=priority/concurrency/rate", entry)
+		}
+		limit := Limits{Concurrency: 1}
+		var err error
+		if limit.Priority, err = ParsePriority(fields[0]); err != nil {
+			return nil, fmt.Errorf("invalid job limits %q: %v", entry, err)
+		}
+		if len(fields) > 1 {
+			if limit.Concurrency, err = strconv.Atoi(fields[1]); err != nil || limit.Concurrency < 1 {
+				return nil, fmt.Errorf("invalid job limits %q: concurrency must be a positive number", entry)
+			}
+		}
+		if len(fields) > 2 {
+			if limit.Rate, err = strconv.ParseFloat(fields[2], 64); err != nil || limit.Rate < 0 {
+				return nil, fmt.Errorf("invalid job limits %q: rate must be a number of jobs per second", entry)
+			}
+		}
+		limits[strings.TrimSpace(parts[0])] = limit
+	}
+	return limits, nil
+}
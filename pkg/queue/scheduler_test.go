@@ -0,0 +1,127 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chanQueue is an in-memory job queue; Consume handles jobs until ctx is done
+type chanQueue struct {
+	jobs chan string
+}
+
+func (q *chanQueue) Publish(ctx context.Context, jobID string) error {
+	q.jobs <- jobID
+	return nil
+}
+
+func (q *chanQueue) Consume(ctx context.Context, handle func(ctx context.Context, jobID string) error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case jobID := <-q.jobs:
+			handle(ctx, jobID)
+		}
+	}
+}
+
+func TestScheduler_GrantsSlotsByPriority(t *testing.T) {
+	ctx := context.Background()
+	scheduler := NewScheduler(1)
+	require.NoError(t, scheduler.acquire(ctx, PriorityNormal))
+
+	var mu sync.Mutex
+	var order []Priority
+	var wg sync.WaitGroup
+	for i, priority := range []Priority{PriorityLow, PriorityHigh, PriorityNormal} {
+		wg.Add(1)
+		go func(priority Priority) {
+			defer wg.Done()
+			require.NoError(t, scheduler.acquire(ctx, priority))
+			mu.Lock()
+			order = append(order, priority)
+			mu.Unlock()
+			scheduler.release()
+		}(priority)
+		// Queue the waiters in a known order
+		require.Eventually(t, func() bool {
+			scheduler.mu.Lock()
+			defer scheduler.mu.Unlock()
+			return len(scheduler.waiting) == i+1
+		}, time.Second, time.Millisecond)
+	}
+
+	scheduler.release()
+	wg.Wait()
+	assert.Equal(t, []Priority{PriorityHigh, PriorityNormal, PriorityLow}, order)
+}
+
+func TestScheduler_CancelledWaiterGivesUpItsTurn(t *testing.T) {
+	scheduler := NewScheduler(1)
+	require.NoError(t, scheduler.acquire(context.Background(), PriorityNormal))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- scheduler.acquire(ctx, PriorityHigh) }()
+	require.Eventually(t, func() bool {
+		scheduler.mu.Lock()
+		defer scheduler.mu.Unlock()
+		return len(scheduler.waiting) == 1
+	}, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	scheduler.release()
+	assert.NoError(t, scheduler.acquire(context.Background(), PriorityLow))
+}
+
+func TestLane_LimitsConcurrency(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobs := &chanQueue{jobs: make(chan string, 10)}
+	lane := NewScheduler(10).Lane("webhooks", jobs, Limits{Priority: PriorityHigh, Concurrency: 2})
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		require.NoError(t, lane.Publish(ctx, id))
+	}
+
+	var running, peak, handled atomic.Int32
+	go lane.Consume(ctx, func(ctx context.Context, jobID string) error {
+		now := running.Add(1)
+		for {
+			previous := peak.Load()
+			if now <= previous || peak.CompareAndSwap(previous, now) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		handled.Add(1)
+		return nil
+	})
+
+	require.Eventually(t, func() bool { return handled.Load() == 5 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("webhooks=high/4/10, digests=low, exports=normal/2")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Limits{
+		"webhooks": {Priority: PriorityHigh, Concurrency: 4, Rate: 10},
+		"digests":  {Priority: PriorityLow, Concurrency: 1},
+		"exports":  {Priority: PriorityNormal, Concurrency: 2},
+	}, limits)
+
+	for _, value := range []string{"webhooks", "webhooks=urgent", "webhooks=high/0", "webhooks=high/1/-1", "webhooks=high/1/1/1"} {
+		_, err := ParseLimits(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,278 @@
+package queue
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+const (
+	// sqsMaxWait is the longest long poll SQS allows
+	sqsMaxWait = 20 * time.Second
+	// sqsBatch is the most messages SQS returns from one receive
+	sqsBatch = 10
+)
+
+// SQSOptions tunes how an SQS queue is read
+type SQSOptions struct {
+	// VisibilityTimeout is how long a received job stays hidden from other workers. It is
+	// extended while the job runs, so it bounds how soon a job is retried after its
+	// handler failed or its worker stopped rather than how long a job may run.
+	VisibilityTimeout time.Duration
+	// WaitTime is how long a receive waits for jobs, at most 20 seconds
+	WaitTime time.Duration
+}
+
+// SQSQueue is a job queue on an Amazon SQS queue with at-least-once delivery: jobs are
+// deleted only once handled, so a job whose handler failed or whose worker stopped
+// becomes visible again and is received by another worker. Jobs received too often are
+// moved to a dead-letter queue by SQS itself once one is configured with SetDeadLetter.
+type SQSQueue struct {
+	queueURL string
+	endpoint string
+	region   string
+	options  SQSOptions
+	creds    aws.CredentialsProvider
+	client   *http.Client
+	signer   *v4.Signer
+}
+
+// NewSQSQueue creates a job queue on the SQS queue at queueURL, signing requests with
+// the credentials of cfg. endpoint may point at a local emulator such as ElasticMQ or
+// LocalStack; empty uses AWS in cfg's region.
+func NewSQSQueue(cfg aws.Config, endpoint, queueURL string, options SQSOptions) *SQSQueue {
+	endpoint = strings.TrimRight(endpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://sqs.%s.amazonaws.com", cfg.Region)
+	}
+	if options.WaitTime > sqsMaxWait {
+		options.WaitTime = sqsMaxWait
+	}
+	return &SQSQueue{
+		queueURL: queueURL,
+		endpoint: endpoint,
+		region:   cfg.Region,
+		options:  options,
+		creds:    cfg.Credentials,
+		// Leave room for long polls on top of the request itself
+		client: &http.Client{Timeout: sqsMaxWait + 30*time.Second},
+		signer: v4.NewSigner(),
+	}
+}
+
+// sqsMessage is a message as returned by ReceiveMessage
+type sqsMessage struct {
+	MessageID     string            `json:"MessageId"`
+	ReceiptHandle string            `json:"ReceiptHandle"`
+	Body          string            `json:"Body"`
+	Attributes    map[string]string `json:"Attributes"`
+}
+
+// Publish queues a job
+func (q *SQSQueue) Publish(ctx context.Context, jobID string) error {
+	return q.call(ctx, "SendMessage", map[string]interface{}{
+		"QueueUrl":    q.queueURL,
+		"MessageBody": jobID,
+	}, nil)
+}
+
+// Consume passes queued jobs to handle until ctx is done
+func (q *SQSQueue) Consume(ctx context.Context, handle func(ctx context.Context, jobID string) error) {
+	for ctx.Err() == nil {
+		if err := q.poll(ctx, handle); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to read SQS queue %s: %v", q.queueURL, err)
+			// Back off rather than spin while SQS is unreachable
+			select {
+			case <-ctx.Done():
+			case <-time.After(q.options.WaitTime + time.Second):
+			}
+		}
+	}
+}
+
+// poll waits up to WaitTime for jobs and runs them
+func (q *SQSQueue) poll(ctx context.Context, handle func(ctx context.Context, jobID string) error) error {
+	var received struct {
+		Messages []sqsMessage `json:"Messages"`
+	}
+	err := q.call(ctx, "ReceiveMessage", map[string]interface{}{
+		"QueueUrl":            q.queueURL,
+		"MaxNumberOfMessages": sqsBatch,
+		"WaitTimeSeconds":     int(q.options.WaitTime.Seconds()),
+		"VisibilityTimeout":   q.visibilitySeconds(),
+		"AttributeNames":      []string{"ApproximateReceiveCount"},
+	}, &received)
+	if err != nil {
+		return err
+	}
+
+	for i, message := range received.Messages {
+		if ctx.Err() != nil {
+			// Jobs not started are handed straight back to the other workers
+			for _, skipped := range received.Messages[i:] {
+				q.release(skipped)
+			}
+			return nil
+		}
+		q.run(ctx, message, handle)
+	}
+	return nil
+}
+
+// run hands a job to handle, keeping it hidden from other workers while it runs, and
+// deletes it when handled; failed jobs become visible again after VisibilityTimeout
+func (q *SQSQueue) run(ctx context.Context, message sqsMessage, handle func(ctx context.Context, jobID string) error) {
+	heartbeatCtx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		q.heartbeat(heartbeatCtx, message)
+	}()
+	err := handle(ctx, message.Body)
+	stop()
+	<-done
+
+	if err != nil {
+		log.Printf("Job %s from SQS queue %s failed on receive %s, it will be retried: %v",
+			message.Body, q.queueURL, message.Attributes["ApproximateReceiveCount"], err)
+		return
+	}
+	err = q.call(context.WithoutCancel(ctx), "DeleteMessage", map[string]interface{}{
+		"QueueUrl":      q.queueURL,
+		"ReceiptHandle": message.ReceiptHandle,
+	}, nil)
+	if err != nil {
+		log.Printf("Failed to delete job %s from SQS queue %s: %v", message.Body, q.queueURL, err)
+	}
+}
+
+// heartbeat extends a running job's visibility timeout halfway through each timeout
+// until ctx is done
+func (q *SQSQueue) heartbeat(ctx context.Context, message sqsMessage) {
+	ticker := time.NewTicker(q.options.VisibilityTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := q.changeVisibility(ctx, message, q.visibilitySeconds())
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Failed to extend job %s on SQS queue %s: %v", message.Body, q.queueURL, err)
+			}
+		}
+	}
+}
+
+// release makes a received job visible to other workers right away
+func (q *SQSQueue) release(message sqsMessage) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := q.changeVisibility(ctx, message, 0); err != nil {
+		log.Printf("Failed to release job %s on SQS queue %s: %v", message.Body, q.queueURL, err)
+	}
+}
+
+func (q *SQSQueue) changeVisibility(ctx context.Context, message sqsMessage, seconds int) error {
+	return q.call(ctx, "ChangeMessageVisibility", map[string]interface{}{
+		"QueueUrl":          q.queueURL,
+		"ReceiptHandle":     message.ReceiptHandle,
+		"VisibilityTimeout": seconds,
+	}, nil)
+}
+
+// visibilitySeconds is VisibilityTimeout in whole seconds, as SQS takes it, rounded up
+func (q *SQSQueue) visibilitySeconds() int {
+	return int(math.Ceil(q.options.VisibilityTimeout.Seconds()))
+}
+
+// SetDeadLetter sets the queue's redrive policy so SQS moves jobs received maxReceives
+// times to the queue at deadLetterURL
+func (q *SQSQueue) SetDeadLetter(ctx context.Context, deadLetterURL string, maxReceives int64) error {
+	var attributes struct {
+		Attributes map[string]string `json:"Attributes"`
+	}
+	err := q.call(ctx, "GetQueueAttributes", map[string]interface{}{
+		"QueueUrl":       deadLetterURL,
+		"AttributeNames": []string{"QueueArn"},
+	}, &attributes)
+	if err != nil {
+		return err
+	}
+	arn := attributes.Attributes["QueueArn"]
+	if arn == "" {
+		return fmt.Errorf("dead-letter queue %s has no ARN", deadLetterURL)
+	}
+
+	policy, err := json.Marshal(map[string]string{
+		"deadLetterTargetArn": arn,
+		"maxReceiveCount":     strconv.FormatInt(maxReceives, 10),
+	})
+	if err != nil {
+		return err
+	}
+	return q.call(ctx, "SetQueueAttributes", map[string]interface{}{
+		"QueueUrl":   q.queueURL,
+		"Attributes": map[string]string{"RedrivePolicy": string(policy)},
+	}, nil)
+}
+
+// call sends a signed request for action using the SQS JSON protocol and decodes the
+// response into out unless it is nil
+func (q *SQSQueue) call(ctx context.Context, action string, input map[string]interface{}, out interface{}) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, q.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	req.Header.Set("X-Amz-Target", "AmazonSQS."+action)
+
+	creds, err := q.creds.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %v", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := q.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "sqs", q.region, time.Now()); err != nil {
+		return err
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var failure struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if json.Unmarshal(msg, &failure) == nil && failure.Type != "" {
+			return fmt.Errorf("sqs %s: %s: %s", action, failure.Type, failure.Message)
+		}
+		return fmt.Errorf("sqs %s: %s: %s", action, resp.Status, msg)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,207 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSQS emulates the parts of the SQS JSON protocol the queue uses, with visibility
+// timeouts measured in receives rather than time
+type fakeSQS struct {
+	mu         sync.Mutex
+	messages   map[string]*fakeMessage
+	order      []string
+	redrive    map[string]string
+	visibility []int
+}
+
+type fakeMessage struct {
+	body     string
+	visible  bool
+	receives int
+}
+
+func newFakeSQS(t *testing.T) (*fakeSQS, *httptest.Server) {
+	fake := &fakeSQS{messages: make(map[string]*fakeMessage), redrive: make(map[string]string)}
+	server := httptest.NewServer(http.HandlerFunc(fake.serve))
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeSQS) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	var input map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var output interface{} = map[string]interface{}{}
+	switch r.Header.Get("X-Amz-Target") {
+	case "AmazonSQS.SendMessage":
+		id := fmt.Sprintf("msg-%d", len(f.order)+1)
+		f.messages[id] = &fakeMessage{body: input["MessageBody"].(string), visible: true}
+		f.order = append(f.order, id)
+	case "AmazonSQS.ReceiveMessage":
+		messages := []map[string]interface{}{}
+		for _, id := range f.order {
+			message := f.messages[id]
+			if message == nil || !message.visible {
+				continue
+			}
+			message.visible = false
+			message.receives++
+			messages = append(messages, map[string]interface{}{
+				"MessageId":     id,
+				"ReceiptHandle": id,
+				"Body":          message.body,
+				"Attributes":    map[string]string{"ApproximateReceiveCount": fmt.Sprint(message.receives)},
+			})
+		}
+		output = map[string]interface{}{"Messages": messages}
+	case "AmazonSQS.DeleteMessage":
+		delete(f.messages, input["ReceiptHandle"].(string))
+	case "AmazonSQS.ChangeMessageVisibility":
+		seconds := int(input["VisibilityTimeout"].(float64))
+		f.visibility = append(f.visibility, seconds)
+		if message := f.messages[input["ReceiptHandle"].(string)]; message != nil && seconds == 0 {
+			message.visible = true
+		}
+	case "AmazonSQS.GetQueueAttributes":
+		if input["QueueUrl"] != "http://sqs.local/000000000000/jobs-dead" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"com.amazonaws.sqs#QueueDoesNotExist","message":"The specified queue does not exist."}`))
+			return
+		}
+		output = map[string]interface{}{"Attributes": map[string]string{"QueueArn": "arn:aws:sqs:us-west-2:000000000000:jobs-dead"}}
+	case "AmazonSQS.SetQueueAttributes":
+		for name, value := range input["Attributes"].(map[string]interface{}) {
+			f.redrive[name] = value.(string)
+		}
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(output)
+}
+
+// expire makes every received message visible again, as if its visibility timeout passed
+func (f *fakeSQS) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, message := range f.messages {
+		message.visible = true
+	}
+}
+
+func newTestSQSQueue(endpoint string, visibility time.Duration) *SQSQueue {
+	cfg := aws.Config{
+		Region: "us-west-2",
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "test", SecretAccessKey: "test"}, nil
+		}),
+	}
+	return NewSQSQueue(cfg, endpoint, "http://sqs.local/000000000000/jobs", SQSOptions{VisibilityTimeout: visibility})
+}
+
+func TestSQSQueue_DeletesHandledJobsAndRetriesFailed(t *testing.T) {
+	fake, server := newFakeSQS(t)
+	queue := newTestSQSQueue(server.URL, time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, queue.Publish(ctx, "job-1"))
+	require.NoError(t, queue.Publish(ctx, "job-2"))
+
+	var handled []string
+	handle := func(ctx context.Context, jobID string) error {
+		handled = append(handled, jobID)
+		if jobID == "job-2" {
+			return errors.New("storage unavailable")
+		}
+		return nil
+	}
+
+	require.NoError(t, queue.poll(ctx, handle))
+	assert.Equal(t, []string{"job-1", "job-2"}, handled)
+	assert.Len(t, fake.messages, 1)
+
+	// The failed job stays hidden until its visibility timeout passes
+	handled = nil
+	require.NoError(t, queue.poll(ctx, handle))
+	assert.Empty(t, handled)
+
+	fake.expire()
+	require.NoError(t, queue.poll(ctx, handle))
+	assert.Equal(t, []string{"job-2"}, handled)
+	assert.Equal(t, 2, fake.messages["msg-2"].receives)
+}
+
+func TestSQSQueue_ExtendsVisibilityWhileRunning(t *testing.T) {
+	fake, server := newFakeSQS(t)
+	queue := newTestSQSQueue(server.URL, 40*time.Millisecond)
+	ctx := context.Background()
+
+	require.NoError(t, queue.Publish(ctx, "job-1"))
+	require.NoError(t, queue.poll(ctx, func(ctx context.Context, jobID string) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}))
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.NotEmpty(t, fake.visibility)
+	assert.Empty(t, fake.messages)
+}
+
+func TestSQSQueue_ReleasesJobsOnShutdown(t *testing.T) {
+	fake, server := newFakeSQS(t)
+	queue := newTestSQSQueue(server.URL, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	require.NoError(t, queue.Publish(ctx, "job-1"))
+	require.NoError(t, queue.Publish(ctx, "job-2"))
+
+	var handled []string
+	require.NoError(t, queue.poll(ctx, func(ctx context.Context, jobID string) error {
+		handled = append(handled, jobID)
+		cancel()
+		return ctx.Err()
+	}))
+
+	assert.Equal(t, []string{"job-1"}, handled)
+	// The job that was never started is visible to other workers again right away
+	assert.True(t, fake.messages["msg-2"].visible)
+	assert.False(t, fake.messages["msg-1"].visible)
+}
+
+func TestSQSQueue_SetDeadLetter(t *testing.T) {
+	fake, server := newFakeSQS(t)
+	queue := newTestSQSQueue(server.URL, time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, queue.SetDeadLetter(ctx, "http://sqs.local/000000000000/jobs-dead", 5))
+	assert.JSONEq(t,
+		`{"deadLetterTargetArn":"arn:aws:sqs:us-west-2:000000000000:jobs-dead","maxReceiveCount":"5"}`,
+		fake.redrive["RedrivePolicy"])
+
+	err := queue.SetDeadLetter(ctx, "http://sqs.local/000000000000/missing", 5)
+	assert.ErrorContains(t, err, "QueueDoesNotExist")
+}
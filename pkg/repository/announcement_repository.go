@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// AnnouncementRepository defines the interface for announcement data access
+type AnnouncementRepository interface {
+	// Create creates a new announcement
+	Create(ctx context.Context, announcement *models.Announcement) (*models.Announcement, error)
+
+	// List retrieves all announcements, latest start first
+	List(ctx context.Context) ([]*models.Announcement, error)
+
+	// Update replaces the message, severity and window of an announcement
+	Update(ctx context.Context, id string, input *models.AnnouncementInput) (*models.Announcement, error)
+
+	// Delete removes an announcement by its ID
+	Delete(ctx context.Context, id string) error
+}
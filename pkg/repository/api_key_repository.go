@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// APIKeyRepository defines the interface for API key data access
+type APIKeyRepository interface {
+	// Create stores a new key with the hash of the key itself
+	Create(ctx context.Context, key *models.APIKey, keyHash string) (*models.APIKey, error)
+
+	// ListByUser retrieves the user's keys, revoked ones included, newest first
+	ListByUser(ctx context.Context, userID string) ([]*models.APIKey, error)
+
+	// GetActiveByHash retrieves the key with the hash unless it was revoked or expired
+	// before now; otherwise it returns models.ErrAPIKeyNotFound
+	GetActiveByHash(ctx context.Context, keyHash string, now time.Time) (*models.APIKey, error)
+
+	// Revoke disables one of the user's keys. It returns models.ErrAPIKeyNotFound for keys
+	// that don't exist, belong to someone else or were revoked already.
+	Revoke(ctx context.Context, userID, id string, now time.Time) error
+
+	// MarkUsed records that the key was used at the time
+	MarkUsed(ctx context.Context, id string, at time.Time) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// ApprovalRepository defines the interface for task approvals
+type ApprovalRepository interface {
+	// Get retrieves a task's approval
+	Get(ctx context.Context, taskID string) (*models.TaskApproval, error)
+
+	// Request makes a task need approval, replacing any earlier approval with a pending one
+	Request(ctx context.Context, approval *models.TaskApproval) error
+
+	// Decide approves or rejects a pending approval, returning models.ErrApprovalNotPending
+	// when it was already decided
+	Decide(ctx context.Context, taskID string, status models.ApprovalStatus, decidedBy, comment string, decidedAt time.Time) (*models.TaskApproval, error)
+
+	// Remove drops a task's approval requirement
+	Remove(ctx context.Context, taskID string) error
+}
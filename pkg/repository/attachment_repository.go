@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// AttachmentRepository defines the interface for task attachment metadata access
+type AttachmentRepository interface {
+	// Create stores the metadata of an uploaded attachment
+	Create(ctx context.Context, attachment *models.TaskAttachment) (*models.TaskAttachment, error)
+
+	// GetByID retrieves an attachment of the given task
+	GetByID(ctx context.Context, taskID, id string) (*models.TaskAttachment, error)
+
+	// ListByTask retrieves all attachments of a task
+	ListByTask(ctx context.Context, taskID string) ([]*models.TaskAttachment, error)
+
+	// Delete removes an attachment of the given task
+	Delete(ctx context.Context, taskID, id string) error
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// AuditFilter represents the filtering options for audit events
+type AuditFilter struct {
+	ActorID   string
+	SubjectID string
+	Action    string
+	// ActionPrefix matches actions starting with the prefix, e.g. "security."
+	ActionPrefix string
+	Page         int
+	Limit        int
+}
+
+// AuditRepository defines the interface for audit log storage
+type AuditRepository interface {
+	// Create appends an event to the audit log
+	Create(ctx context.Context, event *models.AuditEvent) error
+
+	// List retrieves events matching the filter, newest first
+	List(ctx context.Context, filter AuditFilter) ([]*models.AuditEvent, int, error)
+}
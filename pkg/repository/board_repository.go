@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// BoardRepository defines the interface for the manual order of tasks on the board
+type BoardRepository interface {
+	// Move places a task in its board column below afterID and above beforeID, either
+	// of which may be empty, and returns the moved task. Both must be tasks of the
+	// same column.
+	Move(ctx context.Context, id, afterID, beforeID string) (*models.Task, error)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// ChecklistRepository defines the interface for the checklist items of tasks. Every
+// write also updates the checklist progress reported on the task.
+type ChecklistRepository interface {
+	// List returns the items of a task's checklist in order
+	List(ctx context.Context, taskID string) ([]*models.ChecklistItem, error)
+
+	// Add appends an item to the end of a task's checklist
+	Add(ctx context.Context, taskID string, input *models.ChecklistItemInput) (*models.ChecklistItem, error)
+
+	// Update changes the text or done flag of an item
+	Update(ctx context.Context, taskID, itemID string, update *models.ChecklistItemUpdate) (*models.ChecklistItem, error)
+
+	// Delete removes an item from a task's checklist
+	Delete(ctx context.Context, taskID, itemID string) error
+
+	// Reorder moves the items into the order of itemIDs, which lists every item once
+	Reorder(ctx context.Context, taskID string, itemIDs []string) ([]*models.ChecklistItem, error)
+
+	// SetDone sets the done flag of the given items, or of every item if itemIDs is nil
+	SetDone(ctx context.Context, taskID string, itemIDs []string, done bool) ([]*models.ChecklistItem, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// DeadLetterRepository defines the interface for jobs given up on after their retries
+type DeadLetterRepository interface {
+	// List retrieves the dead letters not requeued yet, newest first, of jobType or of
+	// every type when it is empty
+	List(ctx context.Context, jobType models.JobType, page, limit int) ([]*models.DeadLetter, int, error)
+
+	// Get retrieves the dead letter of a job
+	Get(ctx context.Context, jobType models.JobType, jobID string) (*models.DeadLetter, error)
+
+	// Requeue queues a dead-lettered job again with its attempts reset. It returns
+	// models.ErrJobNotRequeueable when the job was requeued already, or is gone.
+	Requeue(ctx context.Context, jobType models.JobType, jobID string, now time.Time) error
+}
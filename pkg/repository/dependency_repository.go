@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// DependencyRepository defines the interface for blocked-by relationships between tasks
+type DependencyRepository interface {
+	// Add records that a task is blocked by another; adding an existing dependency is a no-op
+	Add(ctx context.Context, taskID, blockerID string) error
+
+	// Remove deletes a dependency
+	Remove(ctx context.Context, taskID, blockerID string) error
+
+	// ListBlockers retrieves the tasks a task is directly blocked by
+	ListBlockers(ctx context.Context, taskID string) ([]*models.Task, error)
+
+	// ListBlocked retrieves the tasks directly blocked by a task
+	ListBlocked(ctx context.Context, taskID string) ([]*models.Task, error)
+
+	// ListBlockerIDs returns the IDs of every task blocking a task, directly or through other blockers
+	ListBlockerIDs(ctx context.Context, taskID string) ([]string, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// DeprecationRepository defines the interface for deprecation usage data access
+type DeprecationRepository interface {
+	// RecordUsage adds each usage's requests to its client's totals, creating them on
+	// first use
+	RecordUsage(ctx context.Context, usage []*models.DeprecationUsage) error
+
+	// ListUsage retrieves the usage of every client, most requests first
+	ListUsage(ctx context.Context) ([]*models.DeprecationUsage, error)
+
+	// MarkNotified records when the client's user was notified of the deprecation, unless
+	// they were already. It reports whether this call marked it, so that only one replica
+	// sends the notice.
+	MarkNotified(ctx context.Context, usage *models.DeprecationUsage, at time.Time) (bool, error)
+}
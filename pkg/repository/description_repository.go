@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// DescriptionRepository defines the interface for reading the description history of
+// tasks. Versions are saved by the task repository whenever a description changes.
+type DescriptionRepository interface {
+	// ListVersions returns the kept versions of a task's description, oldest first
+	ListVersions(ctx context.Context, taskID string) ([]*models.DescriptionVersion, error)
+
+	// GetVersion returns one version of a task's description
+	GetVersion(ctx context.Context, taskID string, version int) (*models.DescriptionVersion, error)
+}
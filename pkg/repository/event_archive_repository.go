@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// EventArchiveRepository defines the interface for the archive of task events
+type EventArchiveRepository interface {
+	// Append archives an event
+	Append(ctx context.Context, event *models.ArchivedEvent) error
+	// List retrieves up to limit events of types (or of every type when empty) that
+	// occurred in [from, to), after the archived event after, in archive order
+	List(ctx context.Context, from, to time.Time, types []string, after int64, limit int) ([]*models.ArchivedEvent, error)
+	// DeleteBefore removes the events that occurred before before
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
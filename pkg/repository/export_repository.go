@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// ExportRepository defines the interface for the export job queue
+type ExportRepository interface {
+	// Create queues a new export job
+	Create(ctx context.Context, job *models.ExportJob) (*models.ExportJob, error)
+
+	// GetByID retrieves an export job by its ID, without its content
+	GetByID(ctx context.Context, id string) (*models.ExportJob, error)
+
+	// Claim marks the pending job id, or the oldest pending job when id is empty, as
+	// running and returns it, or nil when there is none; jobs waiting to be retried are
+	// skipped until their retry time. Jobs running for longer than lease are assumed
+	// abandoned by a stopped worker and are claimed again, until they reach maxAttempts
+	// and are dead-lettered.
+	Claim(ctx context.Context, id string, now time.Time, lease time.Duration, maxAttempts int) (*models.ExportJob, error)
+
+	// CountPending counts the jobs waiting for a worker
+	CountPending(ctx context.Context) (int, error)
+
+	// Retry puts a running job that failed back in the queue, to be claimed from runAfter
+	Retry(ctx context.Context, id, message string, runAfter time.Time) error
+
+	// DeadLetter fails a running job for good and records it as a dead letter
+	DeadLetter(ctx context.Context, id, message string, now time.Time) error
+
+	// ReleaseRetries makes the jobs whose retry time came ordinary pending jobs and
+	// returns their IDs, so they can be queued again
+	ReleaseRetries(ctx context.Context, now time.Time) ([]string, error)
+
+	// SetProgress records how far a running job is, in percent
+	SetProgress(ctx context.Context, id string, progress int) error
+
+	// Complete records the file a job produced
+	Complete(ctx context.Context, id string, file *models.ExportFile, completedAt, expiresAt time.Time) error
+
+	// Fail records why a job could not be completed
+	Fail(ctx context.Context, id, message string, at time.Time) error
+
+	// Content retrieves the file of a completed job kept in the database
+	Content(ctx context.Context, id string) ([]byte, error)
+
+	// DeleteExpired removes jobs whose file expired before now, returning the storage
+	// keys of the files kept in S3
+	DeleteExpired(ctx context.Context, now time.Time) ([]string, error)
+}
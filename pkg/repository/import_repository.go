@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// ImportRepository defines the interface for the import job queue
+type ImportRepository interface {
+	// Create queues a new import job with the uploaded file
+	Create(ctx context.Context, job *models.ImportJob, content []byte) (*models.ImportJob, error)
+
+	// GetByID retrieves an import job by its ID, without its file
+	GetByID(ctx context.Context, id string) (*models.ImportJob, error)
+
+	// Claim marks the pending job id, or the oldest pending job when id is empty, as
+	// running and returns it, or nil when there is none; jobs waiting to be retried are
+	// skipped until their retry time. Running jobs whose worker made no progress for
+	// longer than lease are resumed from their checkpoint, until they reach maxAttempts
+	// and are dead-lettered.
+	Claim(ctx context.Context, id string, now time.Time, lease time.Duration, maxAttempts int) (*models.ImportJob, error)
+
+	// CountPending counts the jobs waiting for a worker
+	CountPending(ctx context.Context) (int, error)
+
+	// Retry puts a running job that failed back in the queue, to be claimed from runAfter
+	Retry(ctx context.Context, id, message string, runAfter time.Time) error
+
+	// DeadLetter fails a running job for good and records it as a dead letter
+	DeadLetter(ctx context.Context, id, message string, now time.Time) error
+
+	// ReleaseRetries makes the jobs whose retry time came ordinary pending jobs and
+	// returns their IDs, so they can be queued again
+	ReleaseRetries(ctx context.Context, now time.Time) ([]string, error)
+
+	// Content retrieves the uploaded file of a job that has not finished
+	Content(ctx context.Context, id string) ([]byte, error)
+
+	// ProcessedRows returns the numbers of the rows at or after from that already have
+	// an outcome, which a resumed job must not import again
+	ProcessedRows(ctx context.Context, id string, from int) (map[int]bool, error)
+
+	// RecordRow stores the outcome of a row
+	RecordRow(ctx context.Context, id string, result *models.ImportRowResult) error
+
+	// Checkpoint records that every row up to processed is done. It reports false when
+	// the job is no longer running, e.g. because it was cancelled.
+	Checkpoint(ctx context.Context, id string, processed int, now time.Time) (bool, error)
+
+	// Finish marks a running job completed or failed and drops its file
+	Finish(ctx context.Context, id string, status models.ImportStatus, message string, now time.Time) error
+
+	// Cancel stops a pending or running job and drops its file, reporting whether it
+	// was still pending or running
+	Cancel(ctx context.Context, id string, now time.Time) (bool, error)
+
+	// Failures retrieves the rows of a job that could not be imported, in order
+	Failures(ctx context.Context, id string, page, limit int) ([]*models.ImportRowResult, int, error)
+}
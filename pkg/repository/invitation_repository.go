@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// InvitationRepository defines the interface for organization invitation and membership data access
+type InvitationRepository interface {
+	// Create stores an invitation with the hash of its token
+	Create(ctx context.Context, invitation *models.Invitation, tokenHash string) (*models.Invitation, error)
+
+	// ListByOrg retrieves an organization's invitations, newest first
+	ListByOrg(ctx context.Context, orgID string) ([]*models.Invitation, error)
+
+	// Delete revokes one of an organization's invitations that was not accepted yet
+	Delete(ctx context.Context, orgID, id string) error
+
+	// Accept marks the invitation with the token hash as accepted by the user and makes
+	// them a member of its organization with its role. It returns
+	// models.ErrInvitationNotFound for unknown tokens and models.ErrInvitationUnavailable
+	// for invitations that expired before now or were accepted already.
+	Accept(ctx context.Context, tokenHash, userID string, now time.Time) (*models.OrgMembership, error)
+
+	// ListMembers retrieves an organization's members by when they joined
+	ListMembers(ctx context.Context, orgID string) ([]*models.OrgMembership, error)
+
+	// GetMembership retrieves the membership the user joined last, or
+	// models.ErrMembershipNotFound for users outside any organization
+	GetMembership(ctx context.Context, userID string) (*models.OrgMembership, error)
+}
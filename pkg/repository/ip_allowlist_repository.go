@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// IPAllowlistRepository defines the interface for organization IP allowlist data access
+type IPAllowlistRepository interface {
+	// Create adds an entry to an organization's allowlist
+	Create(ctx context.Context, entry *models.IPAllowlistEntry) (*models.IPAllowlistEntry, error)
+
+	// ListByOrg retrieves an organization's allowlist
+	ListByOrg(ctx context.Context, orgID string) ([]*models.IPAllowlistEntry, error)
+
+	// Delete removes an entry from an organization's allowlist
+	Delete(ctx context.Context, orgID, id string) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// KPIRepository computes business metrics from the task tables
+type KPIRepository interface {
+	// OrgKPIs returns the metrics of every organization with tasks created, completed or
+	// overdue in [since, until), ordered by organization
+	OrgKPIs(ctx context.Context, since, until time.Time) ([]*models.OrgKPIs, error)
+}
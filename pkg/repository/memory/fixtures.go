@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"fmt"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// fixtureEpoch is when the first fixture task was created; every fixture time is fixed
+// relative to it so responses are the same on every run
+var fixtureEpoch = time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)
+
+// TaskFixtures returns the sandbox's tasks, owned by ownerID. Their IDs, keys and times
+// never change, so SDK tests can refer to them directly.
+func TaskFixtures(ownerID string) []*models.Task {
+	fixtures := []struct {
+		title    string
+		status   models.TaskStatus
+		priority models.TaskPriority
+		assignee string
+		parent   int // number of the parent task, 0 for top-level tasks
+		tags     []string
+		metadata map[string]interface{}
+	}{
+		{"Plan the Q3 release", models.StatusInProgress, models.PriorityHigh, ownerID, 0, []string{"planning", "release"}, nil},
+		{"Write release notes", models.StatusPending, models.PriorityMedium, ownerID, 1, []string{"docs", "release"}, nil},
+		{"Freeze the API surface", models.StatusCompleted, models.PriorityHigh, "sandbox-teammate", 1, []string{"release"}, nil},
+		{"Fix login redirect loop", models.StatusInProgress, models.PriorityCritical, ownerID, 0, []string{"bug"}, map[string]interface{}{"customer": "acme", "seats": 50}},
+		{"Update onboarding emails", models.StatusPending, models.PriorityLow, "", 0, []string{"marketing"}, nil},
+		{"Migrate CI to the new runners", models.StatusPending, models.PriorityMedium, "sandbox-teammate", 0, []string{"infra"}, nil},
+		{"Archive the 2023 roadmap", models.StatusCancelled, models.PriorityLow, "", 0, nil, nil},
+		{"Draft the hiring plan", models.StatusDraft, models.PriorityMedium, "", 0, []string{"planning"}, nil},
+	}
+
+	tasks := make([]*models.Task, len(fixtures))
+	for i, fixture := range fixtures {
+		number := int64(i + 1)
+		createdAt := fixtureEpoch.Add(time.Duration(i) * 24 * time.Hour)
+		task := &models.Task{
+			ID:          fixtureID(number),
+			Key:         models.FormatTaskKey(models.DefaultProjectKey, number),
+			ProjectKey:  models.DefaultProjectKey,
+			Number:      number,
+			Slug:        models.Slugify(fixture.title),
+			Title:       fixture.title,
+			Description: "Sandbox fixture: " + fixture.title + ".",
+			Status:      fixture.status,
+			Priority:    fixture.priority,
+			// Due well after the fixtures were made, so none of them become overdue
+			DueDate:    createdAt.AddDate(10, 0, 0),
+			OwnerID:    ownerID,
+			AssigneeID: fixture.assignee,
+			Tags:       fixture.tags,
+			Metadata:   fixture.metadata,
+			Position:   float64(number),
+			CreatedAt:  createdAt,
+			UpdatedAt:  createdAt.Add(time.Hour),
+		}
+		if fixture.parent > 0 {
+			task.ParentID = fixtureID(int64(fixture.parent))
+		}
+		tasks[i] = task
+	}
+	return tasks
+}
+
+// fixtureID returns the fixed UUID of the fixture task with the number
+func fixtureID(number int64) string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", number)
+}
@@ -0,0 +1,465 @@
+// Package memory implements repositories over fixed in-memory data, for sandbox
+// instances that run without a database.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+var errTaskNotFound = models.ErrTaskNotFound
+
+type taskRepository struct {
+	tasks []*models.Task
+	now   func() time.Time
+}
+
+// NewTaskRepository creates a task repository serving the given tasks. It is stateless:
+// writes are checked and answered as if they succeeded, but every request sees the
+// same tasks, so clients always develop against the same data.
+func NewTaskRepository(tasks []*models.Task) repository.TaskRepository {
+	return &taskRepository{tasks: tasks, now: time.Now}
+}
+
+func (r *taskRepository) Create(ctx context.Context, create *models.TaskCreate) (*models.Task, error) {
+	now := r.now().UTC()
+	number := r.nextNumber(create.ProjectKey)
+	task := &models.Task{
+		ID:          uuid.NewString(),
+		Key:         models.FormatTaskKey(create.ProjectKey, number),
+		ProjectKey:  create.ProjectKey,
+		Number:      number,
+		Slug:        models.Slugify(create.Title),
+		Title:       create.Title,
+		Description: create.Description,
+		Status:      create.Status,
+		Priority:    create.Priority,
+		DueDate:     create.DueDate,
+		OwnerID:     create.OwnerID,
+		AssigneeID:  create.AssigneeID,
+		ParentID:    create.ParentID,
+		Tags:        create.Tags,
+		Metadata:    create.Metadata,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if create.EstimatedMinutes > 0 {
+		estimate := create.EstimatedMinutes
+		task.EstimatedMinutes = &estimate
+	}
+	return task, nil
+}
+
+func (r *taskRepository) CreateBatch(ctx context.Context, creates []*models.TaskCreate) ([]*models.Task, error) {
+	tasks := make([]*models.Task, len(creates))
+	for i, create := range creates {
+		task, err := r.Create(ctx, create)
+		if err != nil {
+			return nil, err
+		}
+		tasks[i] = task
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) GetByID(ctx context.Context, id string) (*models.Task, error) {
+	for _, task := range r.tasks {
+		if task.ID == id {
+			return cloneTask(task), nil
+		}
+	}
+	return nil, errTaskNotFound
+}
+
+func (r *taskRepository) GetByKey(ctx context.Context, projectKey string, number int64) (*models.Task, error) {
+	for _, task := range r.tasks {
+		if task.ProjectKey == projectKey && task.Number == number {
+			return cloneTask(task), nil
+		}
+	}
+	return nil, errTaskNotFound
+}
+
+func (r *taskRepository) GetBySlug(ctx context.Context, projectKey, slug string) (*models.Task, error) {
+	for _, task := range r.tasks {
+		if task.ProjectKey == projectKey && task.Slug == slug {
+			return cloneTask(task), nil
+		}
+	}
+	return nil, errTaskNotFound
+}
+
+func (r *taskRepository) Update(ctx context.Context, id string, update *models.TaskUpdate) (*models.Task, error) {
+	task, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Title != nil {
+		task.Title = *update.Title
+		task.Slug = models.Slugify(task.Title)
+	}
+	if update.Description != nil {
+		task.Description = *update.Description
+	}
+	if update.Status != nil {
+		task.Status = *update.Status
+	}
+	if update.Priority != nil {
+		task.Priority = *update.Priority
+	}
+	if update.DueDate != nil {
+		task.DueDate = *update.DueDate
+	}
+	if update.AssigneeID != nil {
+		task.AssigneeID = *update.AssigneeID
+	}
+	if update.ParentID != nil {
+		task.ParentID = *update.ParentID
+	}
+	if update.Tags != nil {
+		task.Tags = *update.Tags
+	}
+	if update.Metadata != nil {
+		task.Metadata = update.Metadata
+	}
+	if update.EstimatedMinutes != nil {
+		task.EstimatedMinutes = nil
+		if *update.EstimatedMinutes > 0 {
+			task.EstimatedMinutes = update.EstimatedMinutes
+		}
+	}
+	task.UpdatedAt = r.now().UTC()
+	return task, nil
+}
+
+func (r *taskRepository) SaveDraft(ctx context.Context, id string, draft *models.TaskDraft) (*models.Task, error) {
+	now := r.now().UTC()
+	task := &models.Task{
+		ID:          id,
+		ProjectKey:  draft.ProjectKey,
+		Slug:        models.Slugify(draft.Title),
+		Title:       draft.Title,
+		Description: draft.Description,
+		Status:      models.StatusDraft,
+		Priority:    draft.Priority,
+		OwnerID:     draft.OwnerID,
+		AssigneeID:  draft.AssigneeID,
+		ParentID:    draft.ParentID,
+		Tags:        draft.Tags,
+		Metadata:    draft.Metadata,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if draft.DueDate != nil {
+		task.DueDate = *draft.DueDate
+	}
+	return task, nil
+}
+
+func (r *taskRepository) PublishDraft(ctx context.Context, id string, status models.TaskStatus) (*models.Task, error) {
+	task, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != models.StatusDraft {
+		return nil, errors.New("draft not found")
+	}
+	task.Status = status
+	task.UpdatedAt = r.now().UTC()
+	return task, nil
+}
+
+func (r *taskRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.GetByID(ctx, id)
+	return err
+}
+
+func (r *taskRepository) List(ctx context.Context, filter repository.TaskFilter) ([]*models.Task, int, error) {
+	tasks := r.matching(filter)
+	total := len(tasks)
+
+	start := (filter.Page - 1) * filter.Limit
+	if start < 0 || filter.Limit <= 0 {
+		return tasks, total, nil
+	}
+	if start > total {
+		start = total
+	}
+	end := start + filter.Limit
+	if end > total {
+		end = total
+	}
+	return tasks[start:end], total, nil
+}
+
+func (r *taskRepository) Stream(ctx context.Context, filter repository.TaskFilter, fn func(task *models.Task) error) error {
+	tasks := r.matching(filter)
+	if filter.Offset < len(tasks) {
+		tasks = tasks[filter.Offset:]
+	} else {
+		tasks = nil
+	}
+	for _, task := range tasks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *taskRepository) ListSubtasks(ctx context.Context, parentID string, recursive bool) ([]*models.Task, error) {
+	var subtasks []*models.Task
+	parents := map[string]bool{parentID: true}
+	// Fixtures list parents before their subtasks, so one pass finds every descendant
+	for _, task := range r.tasks {
+		if !parents[task.ParentID] || task.Status == models.StatusDraft {
+			continue
+		}
+		subtasks = append(subtasks, cloneTask(task))
+		if recursive {
+			parents[task.ID] = true
+		}
+	}
+	return subtasks, nil
+}
+
+func (r *taskRepository) ListAncestorIDs(ctx context.Context, id string) ([]string, error) {
+	var ids []string
+	task, err := r.GetByID(ctx, id)
+	for err == nil && task.ParentID != "" {
+		ids = append(ids, task.ParentID)
+		task, err = r.GetByID(ctx, task.ParentID)
+	}
+	return ids, nil
+}
+
+func (r *taskRepository) UpdateSubtaskStatus(ctx context.Context, parentID string, status models.TaskStatus) ([]*models.Task, error) {
+	subtasks, err := r.ListSubtasks(ctx, parentID, true)
+	if err != nil {
+		return nil, err
+	}
+	now := r.now().UTC()
+	for _, task := range subtasks {
+		task.Status = status
+		task.UpdatedAt = now
+	}
+	return subtasks, nil
+}
+
+func (r *taskRepository) ListTags(ctx context.Context) ([]models.TagCount, error) {
+	counts := make(map[string]int)
+	for _, task := range r.tasks {
+		if task.Status == models.StatusDraft {
+			continue
+		}
+		for _, tag := range task.Tags {
+			counts[tag]++
+		}
+	}
+
+	tags := make([]models.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, models.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags, nil
+}
+
+// nextNumber returns the number the next task in the project would get
+func (r *taskRepository) nextNumber(projectKey string) int64 {
+	var number int64
+	for _, task := range r.tasks {
+		if task.ProjectKey == projectKey && task.Number > number {
+			number = task.Number
+		}
+	}
+	return number + 1
+}
+
+// matching returns copies of the tasks matching filter, in the filter's order
+func (r *taskRepository) matching(filter repository.TaskFilter) []*models.Task {
+	now := r.now()
+	var tasks []*models.Task
+	for _, task := range r.tasks {
+		if matchesFilter(task, filter, now) {
+			tasks = append(tasks, cloneTask(task))
+		}
+	}
+	sortTasks(tasks, filter)
+	return tasks
+}
+
+// matchesFilter mirrors the conditions the Postgres repository applies. Stars are not
+// kept, so a StarredBy filter matches nothing.
+func matchesFilter(task *models.Task, filter repository.TaskFilter, now time.Time) bool {
+	if len(filter.Status) > 0 && !containsStatus(filter.Status, task.Status) {
+		return false
+	}
+	if task.Status == models.StatusDraft && task.OwnerID != filter.Viewer {
+		return false
+	}
+	if filter.Priority != "" && task.Priority != filter.Priority {
+		return false
+	}
+	if filter.AssigneeID != "" && task.AssigneeID != filter.AssigneeID {
+		return false
+	}
+	if filter.TeamID != "" && task.TeamID != filter.TeamID {
+		return false
+	}
+	if filter.ProjectKey != "" && task.ProjectKey != filter.ProjectKey {
+		return false
+	}
+	if filter.OwnerID != "" && task.OwnerID != filter.OwnerID {
+		return false
+	}
+	if filter.StarredBy != "" {
+		return false
+	}
+	if filter.DueAfter != nil && task.DueDate.Before(*filter.DueAfter) {
+		return false
+	}
+	if filter.DueBefore != nil && !task.DueDate.Before(*filter.DueBefore) {
+		return false
+	}
+	for _, tag := range filter.Tags {
+		if !containsString(task.Tags, tag) {
+			return false
+		}
+	}
+	if filter.Stale && (task.StaleSince == nil || !isOpen(task.Status)) {
+		return false
+	}
+	snoozed := task.SnoozedUntil != nil && task.SnoozedUntil.After(now)
+	switch filter.Snoozed {
+	case repository.SnoozedHidden:
+		if snoozed {
+			return false
+		}
+	case repository.SnoozedOnly:
+		if !snoozed {
+			return false
+		}
+	}
+	if len(filter.IDs) > 0 && !containsString(filter.IDs, task.ID) {
+		return false
+	}
+	for key, value := range filter.Metadata {
+		field, ok := task.Metadata[key]
+		if !ok || fmt.Sprint(field) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// sortTasks orders tasks like the Postgres repository, newest first on ties
+func sortTasks(tasks []*models.Task, filter repository.TaskFilter) {
+	var compare func(a, b *models.Task) int
+	descending := true
+	switch filter.Sort {
+	case repository.SortUpdatedAt:
+		compare = func(a, b *models.Task) int { return a.UpdatedAt.Compare(b.UpdatedAt) }
+	case repository.SortDueDate:
+		compare, descending = func(a, b *models.Task) int { return a.DueDate.Compare(b.DueDate) }, false
+	case repository.SortPriority:
+		compare = func(a, b *models.Task) int { return priorityRank(a.Priority) - priorityRank(b.Priority) }
+	case repository.SortTitle:
+		compare, descending = func(a, b *models.Task) int {
+			return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+		}, false
+	case repository.SortPosition:
+		compare, descending = func(a, b *models.Task) int {
+			switch {
+			case a.Position < b.Position:
+				return -1
+			case a.Position > b.Position:
+				return 1
+			}
+			return 0
+		}, false
+	default:
+		compare = func(a, b *models.Task) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	}
+	switch filter.Order {
+	case "asc":
+		descending = false
+	case "desc":
+		descending = true
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if c := compare(tasks[i], tasks[j]); c != 0 {
+			return (c > 0) == descending
+		}
+		if !tasks[i].CreatedAt.Equal(tasks[j].CreatedAt) {
+			return tasks[i].CreatedAt.After(tasks[j].CreatedAt)
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+}
+
+func priorityRank(priority models.TaskPriority) int {
+	switch priority {
+	case models.PriorityCritical:
+		return 4
+	case models.PriorityHigh:
+		return 3
+	case models.PriorityMedium:
+		return 2
+	}
+	return 1
+}
+
+func isOpen(status models.TaskStatus) bool {
+	return status == models.StatusPending || status == models.StatusInProgress
+}
+
+func containsStatus(statuses []models.TaskStatus, status models.TaskStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneTask copies a task so callers can't change the fixtures
+func cloneTask(task *models.Task) *models.Task {
+	clone := *task
+	clone.Tags = append([]string(nil), task.Tags...)
+	if task.Metadata != nil {
+		clone.Metadata = make(map[string]interface{}, len(task.Metadata))
+		for key, value := range task.Metadata {
+			clone.Metadata[key] = value
+		}
+	}
+	return &clone
+}
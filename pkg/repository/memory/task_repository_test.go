@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+func TestTaskRepository_List(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTaskRepository(TaskFixtures("demo"))
+
+	tests := []struct {
+		name      string
+		filter    repository.TaskFilter
+		wantTotal int
+		wantFirst string
+	}{
+		{"drafts hidden without viewer", repository.TaskFilter{Page: 1, Limit: 3}, 7, "Archive the 2023 roadmap"},
+		{"owner sees drafts", repository.TaskFilter{Viewer: "demo", Page: 1, Limit: 3}, 8, "Draft the hiring plan"},
+		{"status", repository.TaskFilter{Status: []models.TaskStatus{models.StatusPending}, Page: 1, Limit: 10}, 3, "Migrate CI to the new runners"},
+		{"all tags", repository.TaskFilter{Tags: []string{"release", "docs"}, Page: 1, Limit: 10}, 1, "Write release notes"},
+		{"metadata as text", repository.TaskFilter{Metadata: map[string]string{"seats": "50"}, Page: 1, Limit: 10}, 1, "Fix login redirect loop"},
+		{"priority sort", repository.TaskFilter{Sort: repository.SortPriority, Page: 1, Limit: 10}, 7, "Fix login redirect loop"},
+		{"title sort", repository.TaskFilter{Sort: repository.SortTitle, Page: 1, Limit: 10}, 7, "Archive the 2023 roadmap"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, total, err := repo.List(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, total)
+			require.NotEmpty(t, tasks)
+			assert.Equal(t, tt.wantFirst, tasks[0].Title)
+		})
+	}
+}
+
+func TestTaskRepository_WritesAreNotKept(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTaskRepository(TaskFixtures("demo"))
+	id := fixtureID(1)
+
+	title := "Renamed"
+	updated, err := repo.Update(ctx, id, &models.TaskUpdate{Title: &title})
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", updated.Title)
+
+	require.NoError(t, repo.Delete(ctx, id))
+
+	task, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Plan the Q3 release", task.Title)
+	assert.Equal(t, "TASK-1", task.Key)
+
+	subtasks, err := repo.ListSubtasks(ctx, id, false)
+	require.NoError(t, err)
+	assert.Len(t, subtasks, 2)
+
+	_, err = repo.GetByID(ctx, "missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MergeRepository defines the interface for merging duplicate tasks
+type MergeRepository interface {
+	// Merge moves the source task's attachments and stars to the target, cancels the source
+	// and relates it to the target as a duplicate, all in one transaction
+	Merge(ctx context.Context, targetID, sourceID string) (*models.TaskMerge, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// ModerationRepository defines the interface for the moderation review queue
+type ModerationRepository interface {
+	// CreateFlag adds flagged content to the review queue
+	CreateFlag(ctx context.Context, flag *models.ModerationFlag) (*models.ModerationFlag, error)
+
+	// GetFlag retrieves a flag by its ID
+	GetFlag(ctx context.Context, id string) (*models.ModerationFlag, error)
+
+	// ListFlags retrieves flags with the given status, newest first
+	ListFlags(ctx context.Context, status models.FlagStatus, page, limit int) ([]*models.ModerationFlag, int, error)
+
+	// ReviewFlag records an admin decision on a flag
+	ReviewFlag(ctx context.Context, id string, status models.FlagStatus, reviewer string) (*models.ModerationFlag, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// OAuthClientRepository defines the interface for OAuth client data access
+type OAuthClientRepository interface {
+	// Create stores a new client; SecretHash must already be set
+	Create(ctx context.Context, client *models.OAuthClient) (*models.OAuthClient, error)
+
+	// GetByClientID retrieves a client by its public client ID
+	GetByClientID(ctx context.Context, clientID string) (*models.OAuthClient, error)
+
+	// List retrieves all clients, including revoked ones
+	List(ctx context.Context) ([]*models.OAuthClient, error)
+
+	// Revoke disables a client so it can no longer obtain tokens
+	Revoke(ctx context.Context, clientID string) error
+}
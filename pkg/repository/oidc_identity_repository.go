@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// OIDCIdentityRepository defines the interface for OpenID Connect identity data access
+type OIDCIdentityRepository interface {
+	// Link records a sign-in of the provider's subject at now and returns its identity.
+	// The first sign-in creates the identity with a new local user ID; later ones keep it
+	// and update the email and roles.
+	Link(ctx context.Context, provider, subject, email string, roles []string, now time.Time) (*models.OIDCIdentity, error)
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// OverdueTaskRepository defines the interface for overdue task detection
+type OverdueTaskRepository interface {
+	// MarkOverdue flags the open tasks whose due date passed before now and returns the
+	// newly flagged ones. Tasks already flagged are not returned again unless their due
+	// date is moved later and passes once more.
+	MarkOverdue(ctx context.Context, now time.Time) ([]*models.Task, error)
+
+	// CountOverdue counts the open tasks whose due date passed before now
+	CountOverdue(ctx context.Context, now time.Time) (int, error)
+}
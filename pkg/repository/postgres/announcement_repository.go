@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// announcementColumns lists the columns read by scanAnnouncement, in order
+const announcementColumns = "id, message, severity, starts_at, ends_at, created_by, created_at, updated_at"
+
+// scanAnnouncement reads an announcement selected with announcementColumns
+func scanAnnouncement(row rowScanner) (*models.Announcement, error) {
+	announcement := &models.Announcement{}
+	var endsAt sql.NullTime
+	err := row.Scan(
+		&announcement.ID,
+		&announcement.Message,
+		&announcement.Severity,
+		&announcement.StartsAt,
+		&endsAt,
+		&announcement.CreatedBy,
+		&announcement.CreatedAt,
+		&announcement.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endsAt.Valid {
+		announcement.EndsAt = &endsAt.Time
+	}
+	return announcement, nil
+}
+
+type announcementRepository struct {
+	db *sql.DB
+}
+
+// NewAnnouncementRepository creates a new PostgreSQL announcement repository
+func NewAnnouncementRepository(db *sql.DB) repository.AnnouncementRepository {
+	return &announcementRepository{db: db}
+}
+
+func (r *announcementRepository) Create(ctx context.Context, announcement *models.Announcement) (*models.Announcement, error) {
+	query := `
+		INSERT INTO announcements (id, message, severity, starts_at, ends_at, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + announcementColumns
+
+	now := time.Now()
+	return scanAnnouncement(r.db.QueryRowContext(
+		ctx,
+		query,
+		uuid.New().String(),
+		announcement.Message,
+		announcement.Severity,
+		announcement.StartsAt,
+		announcement.EndsAt,
+		announcement.CreatedBy,
+		now,
+		now,
+	))
+}
+
+func (r *announcementRepository) List(ctx context.Context) ([]*models.Announcement, error) {
+	query := `
+		SELECT ` + announcementColumns + `
+		FROM announcements
+		ORDER BY starts_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	announcements := []*models.Announcement{}
+	for rows.Next() {
+		announcement, err := scanAnnouncement(rows)
+		if err != nil {
+			return nil, err
+		}
+		announcements = append(announcements, announcement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return announcements, nil
+}
+
+func (r *announcementRepository) Update(ctx context.Context, id string, input *models.AnnouncementInput) (*models.Announcement, error) {
+	query := `
+		UPDATE announcements
+		SET message = $1, severity = $2, starts_at = $3, ends_at = $4, updated_at = $5
+		WHERE id = $6
+		RETURNING ` + announcementColumns
+
+	result, err := scanAnnouncement(r.db.QueryRowContext(
+		ctx,
+		query,
+		input.Message,
+		input.Severity,
+		input.StartsAt,
+		input.EndsAt,
+		time.Now(),
+		id,
+	))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("announcement not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *announcementRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM announcements WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("announcement not found")
+	}
+
+	return nil
+}
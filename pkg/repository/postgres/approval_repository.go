@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// approvalColumns lists the columns read by scanApproval, in order
+const approvalColumns = "task_id, approver_id, requested_by, status, comment, requested_at, decided_by, decided_at"
+
+// scanApproval reads an approval selected with approvalColumns
+func scanApproval(row rowScanner) (*models.TaskApproval, error) {
+	approval := &models.TaskApproval{}
+	var comment, decidedBy sql.NullString
+	var decidedAt sql.NullTime
+	err := row.Scan(
+		&approval.TaskID,
+		&approval.ApproverID,
+		&approval.RequestedBy,
+		&approval.Status,
+		&comment,
+		&approval.RequestedAt,
+		&decidedBy,
+		&decidedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	approval.Comment = comment.String
+	approval.DecidedBy = decidedBy.String
+	if decidedAt.Valid {
+		approval.DecidedAt = &decidedAt.Time
+	}
+	return approval, nil
+}
+
+type approvalRepository struct {
+	db *sql.DB
+}
+
+// NewApprovalRepository creates a new PostgreSQL task approval repository
+func NewApprovalRepository(db *sql.DB) repository.ApprovalRepository {
+	return &approvalRepository{db: db}
+}
+
+func (r *approvalRepository) Get(ctx context.Context, taskID string) (*models.TaskApproval, error) {
+	approval, err := scanApproval(r.db.QueryRowContext(ctx,
+		`SELECT `+approvalColumns+` FROM task_approvals WHERE task_id = $1 AND `+taskInTenant("", 2), taskID, tenant.Arg(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrApprovalNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+func (r *approvalRepository) Request(ctx context.Context, approval *models.TaskApproval) error {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO task_approvals (task_id, approver_id, requested_by, status, requested_at)
+		SELECT id, $2, $3, $4, $5 FROM tasks WHERE id = $1 AND `+tenantMatch("", 6)+`
+		ON CONFLICT (task_id) DO UPDATE
+		SET approver_id = EXCLUDED.approver_id, requested_by = EXCLUDED.requested_by,
+			status = EXCLUDED.status, requested_at = EXCLUDED.requested_at,
+			comment = NULL, decided_by = NULL, decided_at = NULL`,
+		approval.TaskID, approval.ApproverID, approval.RequestedBy, approval.Status, approval.RequestedAt, tenant.Arg(ctx))
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrTaskNotFound
+	}
+	return nil
+}
+
+func (r *approvalRepository) Decide(ctx context.Context, taskID string, status models.ApprovalStatus, decidedBy, comment string, decidedAt time.Time) (*models.TaskApproval, error) {
+	approval, err := scanApproval(r.db.QueryRowContext(ctx, `
+		UPDATE task_approvals
+		SET status = $2, decided_by = $3, comment = NULLIF($4, ''), decided_at = $5
+		WHERE task_id = $1 AND status = 'pending' AND `+taskInTenant("", 6)+`
+		RETURNING `+approvalColumns,
+		taskID, status, decidedBy, comment, decidedAt, tenant.Arg(ctx)))
+	if err == sql.ErrNoRows {
+		if _, err := r.Get(ctx, taskID); err != nil {
+			return nil, err
+		}
+		return nil, models.ErrApprovalNotPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+func (r *approvalRepository) Remove(ctx context.Context, taskID string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM task_approvals WHERE task_id = $1 AND `+taskInTenant("", 2), taskID, tenant.Arg(ctx))
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrApprovalNotFound
+	}
+	return nil
+}
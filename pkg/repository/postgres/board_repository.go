@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// positionGap separates the positions of neighbouring tasks when they are assigned afresh
+const positionGap = 1024
+
+// minPositionGap is the closest two neighbours may get before their column is renumbered
+const minPositionGap = 1e-6
+
+// boardColumn is the project and status whose tasks share a board column
+type boardColumn struct {
+	projectKey string
+	status     models.TaskStatus
+}
+
+type boardRepository struct {
+	db *sql.DB
+}
+
+// NewBoardRepository creates a new PostgreSQL board repository
+func NewBoardRepository(db *sql.DB) repository.BoardRepository {
+	return &boardRepository{db: db}
+}
+
+func (r *boardRepository) Move(ctx context.Context, id, afterID, beforeID string) (*models.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var column boardColumn
+	err = tx.QueryRowContext(ctx,
+		`SELECT project_key FROM tasks WHERE id = $1 AND status <> 'draft' AND `+tenantMatch("", 2),
+		id, tenant.Arg(ctx)).Scan(&column.projectKey)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("task not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Moves within a project take turns, so positions are computed from a settled column
+	if err := lockProject(ctx, tx, column.projectKey); err != nil {
+		return nil, err
+	}
+	err = tx.QueryRowContext(ctx,
+		`SELECT status FROM tasks WHERE id = $1 AND status <> 'draft'`, id).Scan(&column.status)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("task not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Tasks sharing a position, e.g. published drafts, are spread out first so the task
+	// can land between any two of them
+	var tied bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) <> COUNT(DISTINCT position) FROM tasks
+		WHERE project_key = $1 AND status = $2`, column.projectKey, column.status).Scan(&tied)
+	if err != nil {
+		return nil, err
+	}
+	if tied {
+		if err := renumberColumn(ctx, tx, column); err != nil {
+			return nil, err
+		}
+	}
+
+	position, ok, err := placement(ctx, tx, column, id, afterID, beforeID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := renumberColumn(ctx, tx, column); err != nil {
+			return nil, err
+		}
+		if position, _, err = placement(ctx, tx, column, id, afterID, beforeID); err != nil {
+			return nil, err
+		}
+	}
+
+	task, err := scanTask(tx.QueryRowContext(ctx, `
+		UPDATE tasks SET position = $1, updated_at = $2
+		WHERE id = $3
+		RETURNING `+taskColumns, position, time.Now(), id))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// placement returns a position below afterID and above beforeID in the column, reporting
+// false when the neighbours are too close together to fit the task between them
+func placement(ctx context.Context, tx *sql.Tx, column boardColumn, id, afterID, beforeID string) (float64, bool, error) {
+	var lower, upper sql.NullFloat64
+	var err error
+	if afterID != "" {
+		if lower.Float64, err = columnPosition(ctx, tx, column, afterID, "after_id"); err != nil {
+			return 0, false, err
+		}
+		lower.Valid = true
+	}
+	if beforeID != "" {
+		if upper.Float64, err = columnPosition(ctx, tx, column, beforeID, "before_id"); err != nil {
+			return 0, false, err
+		}
+		upper.Valid = true
+	}
+
+	// A missing neighbour is the one next to the given neighbour, other than the task itself
+	switch {
+	case lower.Valid && upper.Valid:
+		if lower.Float64 >= upper.Float64 {
+			return 0, false, errors.New("after_id must be above before_id")
+		}
+	case lower.Valid:
+		err = tx.QueryRowContext(ctx, `
+			SELECT MIN(position) FROM tasks
+			WHERE project_key = $1 AND status = $2 AND position > $3 AND id <> $4`,
+			column.projectKey, column.status, lower.Float64, id).Scan(&upper)
+	case upper.Valid:
+		err = tx.QueryRowContext(ctx, `
+			SELECT MAX(position) FROM tasks
+			WHERE project_key = $1 AND status = $2 AND position < $3 AND id <> $4`,
+			column.projectKey, column.status, upper.Float64, id).Scan(&lower)
+	default:
+		err = tx.QueryRowContext(ctx, `
+			SELECT MAX(position) FROM tasks
+			WHERE project_key = $1 AND status = $2 AND id <> $3`,
+			column.projectKey, column.status, id).Scan(&lower)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch {
+	case !lower.Valid && !upper.Valid:
+		return positionGap, true, nil
+	case !upper.Valid:
+		return lower.Float64 + positionGap, true, nil
+	case !lower.Valid:
+		return upper.Float64 - positionGap, true, nil
+	case upper.Float64-lower.Float64 < minPositionGap:
+		return 0, false, nil
+	}
+	return (lower.Float64 + upper.Float64) / 2, true, nil
+}
+
+// columnPosition returns the position of a neighbour named by param, which must be in the column
+func columnPosition(ctx context.Context, tx *sql.Tx, column boardColumn, id, param string) (float64, error) {
+	var position float64
+	err := tx.QueryRowContext(ctx,
+		`SELECT position FROM tasks WHERE id = $1 AND project_key = $2 AND status = $3`,
+		id, column.projectKey, column.status).Scan(&position)
+	if err == sql.ErrNoRows {
+		return 0, errors.New(param + " must be a task in the same column")
+	}
+	return position, err
+}
+
+// renumberColumn spreads the column's positions positionGap apart, keeping its order
+func renumberColumn(ctx context.Context, tx *sql.Tx, column boardColumn) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE tasks
+		SET position = ranked.rank * $3
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position, created_at DESC) AS rank
+			FROM tasks
+			WHERE project_key = $1 AND status = $2
+		) ranked
+		WHERE tasks.id = ranked.id`,
+		column.projectKey, column.status, positionGap)
+	return err
+}
@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// checklistColumns lists the columns read by scanChecklistItem, in order
+const checklistColumns = "id, task_id, text, done, position, created_at, updated_at"
+
+type checklistRepository struct {
+	db *sql.DB
+}
+
+// NewChecklistRepository creates a new PostgreSQL checklist repository
+func NewChecklistRepository(db *sql.DB) repository.ChecklistRepository {
+	return &checklistRepository{db: db}
+}
+
+func scanChecklistItem(row rowScanner) (*models.ChecklistItem, error) {
+	item := &models.ChecklistItem{}
+	err := row.Scan(
+		&item.ID,
+		&item.TaskID,
+		&item.Text,
+		&item.Done,
+		&item.Position,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *checklistRepository) List(ctx context.Context, taskID string) ([]*models.ChecklistItem, error) {
+	return listChecklist(ctx, r.db, taskID)
+}
+
+func (r *checklistRepository) Add(ctx context.Context, taskID string, input *models.ChecklistItemInput) (*models.ChecklistItem, error) {
+	var item *models.ChecklistItem
+	err := r.write(ctx, taskID, func(tx *sql.Tx) error {
+		var count, last int
+		err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*), COALESCE(MAX(position), 0) FROM task_checklist_items WHERE task_id = $1`,
+			taskID).Scan(&count, &last)
+		if err != nil {
+			return err
+		}
+		if count >= models.MaxChecklistItems {
+			return fmt.Errorf("a checklist may have at most %d items", models.MaxChecklistItems)
+		}
+
+		now := time.Now()
+		item, err = scanChecklistItem(tx.QueryRowContext(ctx, `
+			INSERT INTO task_checklist_items (id, task_id, text, done, position, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING `+checklistColumns,
+			uuid.New().String(), taskID, input.Text, input.Done, last+1, now, now))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *checklistRepository) Update(ctx context.Context, taskID, itemID string, update *models.ChecklistItemUpdate) (*models.ChecklistItem, error) {
+	var item *models.ChecklistItem
+	err := r.write(ctx, taskID, func(tx *sql.Tx) error {
+		var err error
+		item, err = scanChecklistItem(tx.QueryRowContext(ctx, `
+			UPDATE task_checklist_items
+			SET text = COALESCE($1, text),
+				done = COALESCE($2, done),
+				updated_at = $3
+			WHERE id = $4 AND task_id = $5
+			RETURNING `+checklistColumns,
+			update.Text, update.Done, time.Now(), itemID, taskID))
+		if err == sql.ErrNoRows {
+			return errors.New("checklist item not found")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *checklistRepository) Delete(ctx context.Context, taskID, itemID string) error {
+	return r.write(ctx, taskID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`DELETE FROM task_checklist_items WHERE id = $1 AND task_id = $2`, itemID, taskID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return errors.New("checklist item not found")
+		}
+		return nil
+	})
+}
+
+func (r *checklistRepository) Reorder(ctx context.Context, taskID string, itemIDs []string) ([]*models.ChecklistItem, error) {
+	var items []*models.ChecklistItem
+	err := r.write(ctx, taskID, func(tx *sql.Tx) error {
+		current, err := listChecklist(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+		if !sameItems(current, itemIDs) {
+			return errors.New("item_ids must list every checklist item once")
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE task_checklist_items AS item
+			SET position = ordered.position, updated_at = $3
+			FROM unnest($2::text[]) WITH ORDINALITY AS ordered(id, position)
+			WHERE item.task_id = $1 AND item.id = ordered.id`,
+			taskID, pq.Array(itemIDs), time.Now())
+		if err != nil {
+			return err
+		}
+
+		items, err = listChecklist(ctx, tx, taskID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *checklistRepository) SetDone(ctx context.Context, taskID string, itemIDs []string, done bool) ([]*models.ChecklistItem, error) {
+	var items []*models.ChecklistItem
+	err := r.write(ctx, taskID, func(tx *sql.Tx) error {
+		query := `UPDATE task_checklist_items SET done = $1, updated_at = $2 WHERE task_id = $3`
+		args := []interface{}{done, time.Now(), taskID}
+		if itemIDs != nil {
+			query += ` AND id = ANY($4)`
+			args = append(args, pq.Array(itemIDs))
+		}
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		if itemIDs != nil {
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if int(rowsAffected) != len(uniqueStrings(itemIDs)) {
+				return errors.New("checklist item not found")
+			}
+		}
+
+		items, err = listChecklist(ctx, tx, taskID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// write runs fn in a transaction holding the task's row lock, then recounts the task's
+// checklist progress. The lock keeps concurrent writes from racing on positions and
+// the item limit.
+func (r *checklistRepository) write(ctx context.Context, taskID string, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx,
+		`SELECT id FROM tasks WHERE id = $1 AND `+tenantMatch("", 2)+` FOR UPDATE`, taskID, tenant.Arg(ctx)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.New("task not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tasks
+		SET checklist_total = (SELECT COUNT(*) FROM task_checklist_items WHERE task_id = $1),
+			checklist_done = (SELECT COUNT(*) FROM task_checklist_items WHERE task_id = $1 AND done),
+			updated_at = $2
+		WHERE id = $1`, taskID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// listChecklist returns the items of a task's checklist in order
+func listChecklist(ctx context.Context, q querier, taskID string) ([]*models.ChecklistItem, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT `+checklistColumns+`
+		FROM task_checklist_items
+		WHERE task_id = $1
+		ORDER BY position, created_at`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*models.ChecklistItem{}
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// sameItems reports whether ids lists each of the items exactly once
+func sameItems(items []*models.ChecklistItem, ids []string) bool {
+	if len(ids) != len(items) || len(uniqueStrings(ids)) != len(ids) {
+		return false
+	}
+	current := make(map[string]bool, len(items))
+	for _, item := range items {
+		current[item.ID] = true
+	}
+	for _, id := range ids {
+		if !current[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// uniqueStrings returns values without duplicates, keeping the first of each
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// deadLetterInsert follows a given_up CTE returning id, attempts and error, recording
+// those jobs as dead letters of the job type $1 at $2. A job dead-lettered again
+// replaces its earlier dead letter.
+const deadLetterInsert = `
+	INSERT INTO dead_letter_jobs (job_type, job_id, attempts, error, dead_at)
+	SELECT $1::text, id, attempts, error, $2::timestamp FROM given_up
+	ON CONFLICT (job_type, job_id) DO UPDATE
+	SET attempts = EXCLUDED.attempts, error = EXCLUDED.error, dead_at = EXCLUDED.dead_at, requeued_at = NULL`
+
+// deadLetterColumns lists the columns read by scanDeadLetter, in order
+const deadLetterColumns = "job_type, job_id, attempts, error, dead_at, requeued_at"
+
+// requeueQueries reset a failed job of each type so workers pick it up again. The job is
+// due for a retry right away, so services reading a queue queue it again.
+var requeueQueries = map[models.JobType]string{
+	models.JobExport: `
+		UPDATE export_jobs
+		SET status = 'pending', progress = 0, attempts = 0, error = NULL, run_after = $2,
+			started_at = NULL, completed_at = NULL, expires_at = NULL
+		WHERE id = $1 AND status = 'failed'`,
+	// Imports resume from their checkpoint, which needs the file they were given up with
+	models.JobImport: `
+		UPDATE import_jobs
+		SET status = 'pending', attempts = 0, error = NULL, run_after = $2, completed_at = NULL
+		WHERE id = $1 AND status = 'failed' AND content IS NOT NULL`,
+}
+
+// scanDeadLetter reads a dead letter selected with deadLetterColumns
+func scanDeadLetter(row rowScanner) (*models.DeadLetter, error) {
+	letter := &models.DeadLetter{}
+	var errorMessage sql.NullString
+	var requeuedAt sql.NullTime
+	err := row.Scan(
+		&letter.JobType,
+		&letter.JobID,
+		&letter.Attempts,
+		&errorMessage,
+		&letter.DeadAt,
+		&requeuedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	letter.Error = errorMessage.String
+	if requeuedAt.Valid {
+		letter.RequeuedAt = &requeuedAt.Time
+	}
+	return letter, nil
+}
+
+// releaseRetries runs a query clearing run_after on jobs due for a retry and returns
+// their IDs
+func releaseRetries(ctx context.Context, q querier, query string, now time.Time) ([]string, error) {
+	rows, err := q.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+type deadLetterRepository struct {
+	db *sql.DB
+}
+
+// NewDeadLetterRepository creates a new PostgreSQL dead-lettered job repository
+func NewDeadLetterRepository(db *sql.DB) repository.DeadLetterRepository {
+	return &deadLetterRepository{db: db}
+}
+
+func (r *deadLetterRepository) List(ctx context.Context, jobType models.JobType, page, limit int) ([]*models.DeadLetter, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM dead_letter_jobs
+		WHERE requeued_at IS NULL AND ($1 = '' OR job_type = $1)`,
+		jobType).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+deadLetterColumns+`
+		FROM dead_letter_jobs
+		WHERE requeued_at IS NULL AND ($1 = '' OR job_type = $1)
+		ORDER BY dead_at DESC
+		LIMIT $2 OFFSET $3`,
+		jobType, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	letters := []*models.DeadLetter{}
+	for rows.Next() {
+		letter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		letters = append(letters, letter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return letters, total, nil
+}
+
+func (r *deadLetterRepository) Get(ctx context.Context, jobType models.JobType, jobID string) (*models.DeadLetter, error) {
+	letter, err := scanDeadLetter(r.db.QueryRowContext(ctx,
+		`SELECT `+deadLetterColumns+` FROM dead_letter_jobs WHERE job_type = $1 AND job_id = $2`,
+		jobType, jobID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("dead letter not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return letter, nil
+}
+
+func (r *deadLetterRepository) Requeue(ctx context.Context, jobType models.JobType, jobID string, now time.Time) error {
+	requeue, ok := requeueQueries[jobType]
+	if !ok {
+		return errors.New("unknown job type")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE dead_letter_jobs SET requeued_at = $1
+		WHERE job_type = $2 AND job_id = $3 AND requeued_at IS NULL`,
+		now, jobType, jobID)
+	if err != nil {
+		return err
+	}
+	if err := requeued(result); err != nil {
+		return err
+	}
+
+	result, err = tx.ExecContext(ctx, requeue, jobID, now)
+	if err != nil {
+		return err
+	}
+	if err := requeued(result); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// requeued reports models.ErrJobNotRequeueable when a requeue step changed no row
+func requeued(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrJobNotRequeueable
+	}
+	return nil
+}
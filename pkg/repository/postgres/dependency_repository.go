@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+type dependencyRepository struct {
+	db    *sql.DB
+	tasks *taskRepository
+}
+
+// NewDependencyRepository creates a new PostgreSQL task dependency repository
+func NewDependencyRepository(db *sql.DB) repository.DependencyRepository {
+	return &dependencyRepository{db: db, tasks: &taskRepository{db: db}}
+}
+
+func (r *dependencyRepository) Add(ctx context.Context, taskID, blockerID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO task_dependencies (task_id, blocked_by_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`,
+		taskID, blockerID)
+	return err
+}
+
+func (r *dependencyRepository) Remove(ctx context.Context, taskID, blockerID string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_id = $2`,
+		taskID, blockerID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("dependency not found")
+	}
+
+	return nil
+}
+
+func (r *dependencyRepository) ListBlockers(ctx context.Context, taskID string) ([]*models.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id IN (SELECT blocked_by_id FROM task_dependencies WHERE task_id = $1) AND ` + tenantMatch("", 2) + `
+		ORDER BY created_at`
+
+	return r.tasks.queryTasks(ctx, query, taskID, tenant.Arg(ctx))
+}
+
+func (r *dependencyRepository) ListBlocked(ctx context.Context, taskID string) ([]*models.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id IN (SELECT task_id FROM task_dependencies WHERE blocked_by_id = $1) AND ` + tenantMatch("", 2) + `
+		ORDER BY created_at`
+
+	return r.tasks.queryTasks(ctx, query, taskID, tenant.Arg(ctx))
+}
+
+func (r *dependencyRepository) ListBlockerIDs(ctx context.Context, taskID string) ([]string, error) {
+	// UNION (not UNION ALL) stops at rows already visited, so corrupt cyclic data can't loop forever
+	query := `
+		WITH RECURSIVE blockers AS (
+			SELECT blocked_by_id FROM task_dependencies WHERE task_id = $1
+			UNION
+			SELECT d.blocked_by_id
+			FROM task_dependencies d
+			JOIN blockers b ON d.task_id = b.blocked_by_id
+		)
+		SELECT blocked_by_id FROM blockers`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
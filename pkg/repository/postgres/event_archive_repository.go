@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type eventArchiveRepository struct {
+	db *sql.DB
+}
+
+// NewEventArchiveRepository creates a new PostgreSQL event archive repository
+func NewEventArchiveRepository(db *sql.DB) repository.EventArchiveRepository {
+	return &eventArchiveRepository{db: db}
+}
+
+func (r *eventArchiveRepository) Append(ctx context.Context, event *models.ArchivedEvent) error {
+	var task interface{}
+	if event.Task != nil {
+		encoded, err := json.Marshal(event.Task)
+		if err != nil {
+			return err
+		}
+		task = string(encoded)
+	}
+
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO event_archive (event_id, type, task_id, task, occurred_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`,
+		event.EventID, event.Type, event.TaskID, task, event.OccurredAt,
+	).Scan(&event.ID)
+}
+
+func (r *eventArchiveRepository) List(ctx context.Context, from, to time.Time, types []string, after int64, limit int) ([]*models.ArchivedEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, event_id, type, task_id, task, occurred_at
+		FROM event_archive
+		WHERE occurred_at >= $1 AND occurred_at < $2 AND id > $3
+			AND (cardinality($4::text[]) = 0 OR type = ANY($4))
+		ORDER BY id
+		LIMIT $5`,
+		from, to, after, pq.Array(types), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	archived := []*models.ArchivedEvent{}
+	for rows.Next() {
+		event := &models.ArchivedEvent{}
+		var task []byte
+		if err := rows.Scan(&event.ID, &event.EventID, &event.Type, &event.TaskID, &task, &event.OccurredAt); err != nil {
+			return nil, err
+		}
+		if task != nil {
+			if err := json.Unmarshal(task, &event.Task); err != nil {
+				return nil, err
+			}
+		}
+		archived = append(archived, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return archived, nil
+}
+
+func (r *eventArchiveRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM event_archive WHERE occurred_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,256 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// exportColumns lists the columns read by scanExportJob, in order; content is only read on download
+const exportColumns = "id, request, requested_by, status, progress, attempts, error, file_name, content_type, size, storage_key, created_at, started_at, completed_at, expires_at, run_after, COALESCE(org_id, '')"
+
+// scanExportJob reads an export job selected with exportColumns
+func scanExportJob(row rowScanner) (*models.ExportJob, error) {
+	job := &models.ExportJob{}
+	var request []byte
+	var errorMessage, fileName, contentType, storageKey sql.NullString
+	var size sql.NullInt64
+	var startedAt, completedAt, expiresAt, runAfter sql.NullTime
+	err := row.Scan(
+		&job.ID,
+		&request,
+		&job.RequestedBy,
+		&job.Status,
+		&job.Progress,
+		&job.Attempts,
+		&errorMessage,
+		&fileName,
+		&contentType,
+		&size,
+		&storageKey,
+		&job.CreatedAt,
+		&startedAt,
+		&completedAt,
+		&expiresAt,
+		&runAfter,
+		&job.OrgID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(request, &job.Request); err != nil {
+		return nil, err
+	}
+	job.Error = errorMessage.String
+	job.FileName = fileName.String
+	job.ContentType = contentType.String
+	job.Size = size.Int64
+	job.StorageKey = storageKey.String
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		job.CompletedAt = &completedAt.Time
+	}
+	if expiresAt.Valid {
+		job.ExpiresAt = &expiresAt.Time
+	}
+	if runAfter.Valid {
+		job.RetryAt = &runAfter.Time
+	}
+	return job, nil
+}
+
+type exportRepository struct {
+	db *sql.DB
+}
+
+// NewExportRepository creates a new PostgreSQL export job repository
+func NewExportRepository(db *sql.DB) repository.ExportRepository {
+	return &exportRepository{db: db}
+}
+
+func (r *exportRepository) Create(ctx context.Context, job *models.ExportJob) (*models.ExportJob, error) {
+	request, err := json.Marshal(job.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO export_jobs (id, kind, request, requested_by, status, created_at, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + exportColumns
+
+	return scanExportJob(r.db.QueryRowContext(
+		ctx,
+		query,
+		uuid.New().String(),
+		job.Request.Kind,
+		string(request),
+		job.RequestedBy,
+		models.ExportPending,
+		time.Now(),
+		tenant.Arg(ctx),
+	))
+}
+
+func (r *exportRepository) GetByID(ctx context.Context, id string) (*models.ExportJob, error) {
+	job, err := scanExportJob(r.db.QueryRowContext(ctx,
+		`SELECT `+exportColumns+` FROM export_jobs WHERE id = $1 AND `+tenantMatch("", 2), id, tenant.Arg(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("export not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *exportRepository) CountPending(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM export_jobs WHERE status = 'pending'`).Scan(&count)
+	return count, err
+}
+
+func (r *exportRepository) Claim(ctx context.Context, id string, now time.Time, lease time.Duration, maxAttempts int) (*models.ExportJob, error) {
+	abandonedBefore := now.Add(-lease)
+
+	// Jobs that stopped their workers too often are not retried again
+	_, err := r.db.ExecContext(ctx, `
+		WITH given_up AS (
+			UPDATE export_jobs
+			SET status = 'failed', error = 'export did not finish in time', completed_at = $2, expires_at = $3
+			WHERE status = 'running' AND started_at < $4 AND attempts >= $5
+			RETURNING id, attempts, error
+		)`+deadLetterInsert,
+		models.JobExport, now, now.Add(models.ExportRetention), abandonedBefore, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	// SKIP LOCKED lets every instance run workers without claiming the same job
+	query := `
+		UPDATE export_jobs
+		SET status = 'running', progress = 0, attempts = attempts + 1, started_at = $1
+		WHERE id = (
+			SELECT id FROM export_jobs
+			WHERE ((status = 'pending' AND (run_after IS NULL OR run_after <= $1))
+					OR (status = 'running' AND started_at < $2))
+				AND ($3::text = '' OR id = $3::text)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + exportColumns
+
+	job, err := scanExportJob(r.db.QueryRowContext(ctx, query, now, abandonedBefore, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *exportRepository) SetProgress(ctx context.Context, id string, progress int) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE export_jobs SET progress = $1 WHERE id = $2 AND status = 'running'`, progress, id)
+	return err
+}
+
+func (r *exportRepository) Complete(ctx context.Context, id string, file *models.ExportFile, completedAt, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE export_jobs
+		SET status = 'completed', progress = 100, file_name = $1, content_type = $2, size = $3,
+			storage_key = NULLIF($4, ''), content = $5, completed_at = $6, expires_at = $7
+		WHERE id = $8 AND status = 'running'`,
+		file.FileName,
+		file.ContentType,
+		file.Size,
+		file.StorageKey,
+		file.Content,
+		completedAt,
+		expiresAt,
+		id,
+	)
+	return err
+}
+
+func (r *exportRepository) Fail(ctx context.Context, id, message string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE export_jobs
+		SET status = 'failed', error = $1, completed_at = $2, expires_at = $3
+		WHERE id = $4`,
+		message, at, at.Add(models.ExportRetention), id)
+	return err
+}
+
+func (r *exportRepository) Retry(ctx context.Context, id, message string, runAfter time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE export_jobs
+		SET status = 'pending', progress = 0, error = $1, run_after = $2
+		WHERE id = $3 AND status = 'running'`,
+		message, runAfter, id)
+	return err
+}
+
+func (r *exportRepository) DeadLetter(ctx context.Context, id, message string, now time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		WITH given_up AS (
+			UPDATE export_jobs
+			SET status = 'failed', error = $3, completed_at = $2, expires_at = $4, run_after = NULL
+			WHERE id = $5 AND status = 'running'
+			RETURNING id, attempts, error
+		)`+deadLetterInsert,
+		models.JobExport, now, message, now.Add(models.ExportRetention), id)
+	return err
+}
+
+func (r *exportRepository) ReleaseRetries(ctx context.Context, now time.Time) ([]string, error) {
+	return releaseRetries(ctx, r.db, `
+		UPDATE export_jobs SET run_after = NULL
+		WHERE status = 'pending' AND run_after <= $1
+		RETURNING id`, now)
+}
+
+func (r *exportRepository) Content(ctx context.Context, id string) ([]byte, error) {
+	var content []byte
+	err := r.db.QueryRowContext(ctx,
+		`SELECT content FROM export_jobs WHERE id = $1 AND status = 'completed'`, id).Scan(&content)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("export not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (r *exportRepository) DeleteExpired(ctx context.Context, now time.Time) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`DELETE FROM export_jobs WHERE expires_at < $1 RETURNING COALESCE(storage_key, '')`, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,302 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// importColumns lists the columns read by scanImportJob, in order; the file is read separately
+const importColumns = "id, file_name, options, requested_by, status, total_rows, processed_rows, created_count, failed_count, attempts, error, created_at, started_at, completed_at, run_after, COALESCE(org_id, '')"
+
+// scanImportJob reads an import job selected with importColumns
+func scanImportJob(row rowScanner) (*models.ImportJob, error) {
+	job := &models.ImportJob{}
+	var options []byte
+	var errorMessage sql.NullString
+	var startedAt, completedAt, runAfter sql.NullTime
+	err := row.Scan(
+		&job.ID,
+		&job.FileName,
+		&options,
+		&job.RequestedBy,
+		&job.Status,
+		&job.TotalRows,
+		&job.ProcessedRows,
+		&job.CreatedCount,
+		&job.FailedCount,
+		&job.Attempts,
+		&errorMessage,
+		&job.CreatedAt,
+		&startedAt,
+		&completedAt,
+		&runAfter,
+		&job.OrgID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(options, &job.Options); err != nil {
+		return nil, err
+	}
+	job.Error = errorMessage.String
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		job.CompletedAt = &completedAt.Time
+	}
+	if runAfter.Valid {
+		job.RetryAt = &runAfter.Time
+	}
+	job.ComputeProgress()
+	return job, nil
+}
+
+type importRepository struct {
+	db *sql.DB
+}
+
+// NewImportRepository creates a new PostgreSQL import job repository
+func NewImportRepository(db *sql.DB) repository.ImportRepository {
+	return &importRepository{db: db}
+}
+
+func (r *importRepository) Create(ctx context.Context, job *models.ImportJob, content []byte) (*models.ImportJob, error) {
+	options, err := json.Marshal(job.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO import_jobs (id, file_name, options, requested_by, status, total_rows, content, created_at, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + importColumns
+
+	return scanImportJob(r.db.QueryRowContext(
+		ctx,
+		query,
+		uuid.New().String(),
+		job.FileName,
+		string(options),
+		job.RequestedBy,
+		models.ImportPending,
+		job.TotalRows,
+		content,
+		time.Now(),
+		tenant.Arg(ctx),
+	))
+}
+
+func (r *importRepository) GetByID(ctx context.Context, id string) (*models.ImportJob, error) {
+	job, err := scanImportJob(r.db.QueryRowContext(ctx,
+		`SELECT `+importColumns+` FROM import_jobs WHERE id = $1 AND `+tenantMatch("", 2), id, tenant.Arg(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("import not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *importRepository) CountPending(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM import_jobs WHERE status = 'pending'`).Scan(&count)
+	return count, err
+}
+
+func (r *importRepository) Claim(ctx context.Context, id string, now time.Time, lease time.Duration, maxAttempts int) (*models.ImportJob, error) {
+	abandonedBefore := now.Add(-lease)
+
+	// Jobs that stopped their workers too often are not resumed again; their file is
+	// kept so they can be requeued
+	_, err := r.db.ExecContext(ctx, `
+		WITH given_up AS (
+			UPDATE import_jobs
+			SET status = 'failed', error = 'import stopped making progress', completed_at = $2
+			WHERE status = 'running' AND heartbeat_at < $3 AND attempts >= $4
+			RETURNING id, attempts, error
+		)`+deadLetterInsert,
+		models.JobImport, now, abandonedBefore, maxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	// SKIP LOCKED lets every instance run workers without claiming the same job
+	query := `
+		UPDATE import_jobs
+		SET status = 'running', attempts = attempts + 1, started_at = COALESCE(started_at, $1), heartbeat_at = $1
+		WHERE id = (
+			SELECT id FROM import_jobs
+			WHERE ((status = 'pending' AND (run_after IS NULL OR run_after <= $1))
+					OR (status = 'running' AND heartbeat_at < $2))
+				AND ($3::text = '' OR id = $3::text)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + importColumns
+
+	job, err := scanImportJob(r.db.QueryRowContext(ctx, query, now, abandonedBefore, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *importRepository) Content(ctx context.Context, id string) ([]byte, error) {
+	var content []byte
+	err := r.db.QueryRowContext(ctx,
+		`SELECT content FROM import_jobs WHERE id = $1 AND content IS NOT NULL`, id).Scan(&content)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("import file not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func (r *importRepository) ProcessedRows(ctx context.Context, id string, from int) (map[int]bool, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT row_number FROM import_job_rows WHERE job_id = $1 AND row_number >= $2`, id, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	processed := make(map[int]bool)
+	for rows.Next() {
+		var row int
+		if err := rows.Scan(&row); err != nil {
+			return nil, err
+		}
+		processed[row] = true
+	}
+	return processed, rows.Err()
+}
+
+func (r *importRepository) RecordRow(ctx context.Context, id string, result *models.ImportRowResult) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO import_job_rows (job_id, row_number, task_id, error)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''))
+		ON CONFLICT (job_id, row_number) DO NOTHING`,
+		id, result.Row, result.TaskID, result.Error)
+	return err
+}
+
+func (r *importRepository) Checkpoint(ctx context.Context, id string, processed int, now time.Time) (bool, error) {
+	// The counts come from the row outcomes so they stay right across resumes
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE import_jobs
+		SET processed_rows = $1, heartbeat_at = $2,
+			created_count = (SELECT COUNT(*) FROM import_job_rows WHERE job_id = $3 AND task_id IS NOT NULL),
+			failed_count = (SELECT COUNT(*) FROM import_job_rows WHERE job_id = $3 AND error IS NOT NULL)
+		WHERE id = $3 AND status = 'running'`,
+		processed, now, id)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *importRepository) Finish(ctx context.Context, id string, status models.ImportStatus, message string, now time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE import_jobs
+		SET status = $1, error = NULLIF($2, ''), completed_at = $3, content = NULL
+		WHERE id = $4 AND status = 'running'`,
+		status, message, now, id)
+	return err
+}
+
+func (r *importRepository) Retry(ctx context.Context, id, message string, runAfter time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE import_jobs
+		SET status = 'pending', error = $1, run_after = $2
+		WHERE id = $3 AND status = 'running'`,
+		message, runAfter, id)
+	return err
+}
+
+func (r *importRepository) DeadLetter(ctx context.Context, id, message string, now time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		WITH given_up AS (
+			UPDATE import_jobs
+			SET status = 'failed', error = $3, completed_at = $2, run_after = NULL
+			WHERE id = $4 AND status = 'running'
+			RETURNING id, attempts, error
+		)`+deadLetterInsert,
+		models.JobImport, now, message, id)
+	return err
+}
+
+func (r *importRepository) ReleaseRetries(ctx context.Context, now time.Time) ([]string, error) {
+	return releaseRetries(ctx, r.db, `
+		UPDATE import_jobs SET run_after = NULL
+		WHERE status = 'pending' AND run_after <= $1
+		RETURNING id`, now)
+}
+
+func (r *importRepository) Cancel(ctx context.Context, id string, now time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE import_jobs
+		SET status = 'cancelled', completed_at = $1, content = NULL
+		WHERE id = $2 AND status IN ('pending', 'running')`,
+		now, id)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *importRepository) Failures(ctx context.Context, id string, page, limit int) ([]*models.ImportRowResult, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM import_job_rows WHERE job_id = $1 AND error IS NOT NULL`, id).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT row_number, error
+		FROM import_job_rows
+		WHERE job_id = $1 AND error IS NOT NULL
+		ORDER BY row_number
+		LIMIT $2 OFFSET $3`,
+		id, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	failures := []*models.ImportRowResult{}
+	for rows.Next() {
+		failure := &models.ImportRowResult{}
+		if err := rows.Scan(&failure.Row, &failure.Error); err != nil {
+			return nil, 0, err
+		}
+		failures = append(failures, failure)
+	}
+	return failures, total, rows.Err()
+}
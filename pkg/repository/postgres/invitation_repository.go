@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// invitationColumns lists the columns read by scanInvitation, in order
+const invitationColumns = "id, org_id, email, role, invited_by, created_at, expires_at, accepted_by, accepted_at"
+
+// scanInvitation reads an invitation selected with invitationColumns
+func scanInvitation(row rowScanner) (*models.Invitation, error) {
+	invitation := &models.Invitation{}
+	var acceptedBy sql.NullString
+	var acceptedAt sql.NullTime
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.OrgID,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.InvitedBy,
+		&invitation.CreatedAt,
+		&invitation.ExpiresAt,
+		&acceptedBy,
+		&acceptedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	invitation.AcceptedBy = acceptedBy.String
+	if acceptedAt.Valid {
+		invitation.AcceptedAt = &acceptedAt.Time
+	}
+	return invitation, nil
+}
+
+type invitationRepository struct {
+	db *sql.DB
+}
+
+// NewInvitationRepository creates a new PostgreSQL organization invitation repository
+func NewInvitationRepository(db *sql.DB) repository.InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *models.Invitation, tokenHash string) (*models.Invitation, error) {
+	return scanInvitation(r.db.QueryRowContext(ctx, `
+		INSERT INTO org_invitations (id, org_id, email, role, token_hash, invited_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+invitationColumns,
+		uuid.New().String(), invitation.OrgID, invitation.Email, invitation.Role, tokenHash,
+		invitation.InvitedBy, time.Now(), invitation.ExpiresAt))
+}
+
+func (r *invitationRepository) ListByOrg(ctx context.Context, orgID string) ([]*models.Invitation, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+invitationColumns+`
+		FROM org_invitations
+		WHERE org_id = $1
+		ORDER BY created_at DESC`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []*models.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+	return invitations, rows.Err()
+}
+
+func (r *invitationRepository) Delete(ctx context.Context, orgID, id string) error {
+	// Accepted invitations stay as the record of how members joined
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM org_invitations WHERE id = $1 AND org_id = $2 AND accepted_at IS NULL`, id, orgID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrInvitationNotFound
+	}
+	return nil
+}
+
+func (r *invitationRepository) Accept(ctx context.Context, tokenHash, userID string, now time.Time) (*models.OrgMembership, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// A single statement, so an invitation is never accepted twice
+	var id string
+	membership := &models.OrgMembership{UserID: userID, JoinedAt: now}
+	err = tx.QueryRowContext(ctx, `
+		UPDATE org_invitations
+		SET accepted_by = $2, accepted_at = $3
+		WHERE token_hash = $1 AND accepted_at IS NULL AND expires_at > $3
+		RETURNING id, org_id, role`,
+		tokenHash, userID, now).Scan(&id, &membership.OrgID, &membership.Role)
+	if errors.Is(err, sql.ErrNoRows) {
+		var exists bool
+		if err := tx.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM org_invitations WHERE token_hash = $1)`, tokenHash).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, models.ErrInvitationUnavailable
+		}
+		return nil, models.ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Accepting another invitation to the same organization changes the member's role
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO org_memberships (org_id, user_id, role, invitation_id, joined_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (org_id, user_id) DO UPDATE SET role = EXCLUDED.role, invitation_id = EXCLUDED.invitation_id`,
+		membership.OrgID, userID, membership.Role, id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+func (r *invitationRepository) ListMembers(ctx context.Context, orgID string) ([]*models.OrgMembership, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT org_id, user_id, role, joined_at
+		FROM org_memberships
+		WHERE org_id = $1
+		ORDER BY joined_at, user_id`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*models.OrgMembership{}
+	for rows.Next() {
+		member := &models.OrgMembership{}
+		if err := rows.Scan(&member.OrgID, &member.UserID, &member.Role, &member.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+func (r *invitationRepository) GetMembership(ctx context.Context, userID string) (*models.OrgMembership, error) {
+	member := &models.OrgMembership{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT org_id, user_id, role, joined_at
+		FROM org_memberships
+		WHERE user_id = $1
+		ORDER BY joined_at DESC, org_id
+		LIMIT 1`, userID).Scan(&member.OrgID, &member.UserID, &member.Role, &member.JoinedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrMembershipNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type ipAllowlistRepository struct {
+	db *sql.DB
+}
+
+// NewIPAllowlistRepository creates a new PostgreSQL IP allowlist repository
+func NewIPAllowlistRepository(db *sql.DB) repository.IPAllowlistRepository {
+	return &ipAllowlistRepository{db: db}
+}
+
+func (r *ipAllowlistRepository) Create(ctx context.Context, entry *models.IPAllowlistEntry) (*models.IPAllowlistEntry, error) {
+	query := `
+		INSERT INTO org_ip_allowlist (id, org_id, cidr, description, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, org_id, cidr::text, description, created_by, created_at`
+
+	result := &models.IPAllowlistEntry{}
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		uuid.New().String(),
+		entry.OrgID,
+		entry.CIDR,
+		entry.Description,
+		entry.CreatedBy,
+		time.Now(),
+	).Scan(
+		&result.ID,
+		&result.OrgID,
+		&result.CIDR,
+		&result.Description,
+		&result.CreatedBy,
+		&result.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *ipAllowlistRepository) ListByOrg(ctx context.Context, orgID string) ([]*models.IPAllowlistEntry, error) {
+	query := `
+		SELECT id, org_id, cidr::text, description, created_by, created_at
+		FROM org_ip_allowlist
+		WHERE org_id = $1
+		ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*models.IPAllowlistEntry
+	for rows.Next() {
+		entry := &models.IPAllowlistEntry{}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.OrgID,
+			&entry.CIDR,
+			&entry.Description,
+			&entry.CreatedBy,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *ipAllowlistRepository) Delete(ctx context.Context, orgID, id string) error {
+	query := `DELETE FROM org_ip_allowlist WHERE id = $1 AND org_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("allowlist entry not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type oidcIdentityRepository struct {
+	db *sql.DB
+}
+
+// NewOIDCIdentityRepository creates a new PostgreSQL OpenID Connect identity repository
+func NewOIDCIdentityRepository(db *sql.DB) repository.OIDCIdentityRepository {
+	return &oidcIdentityRepository{db: db}
+}
+
+func (r *oidcIdentityRepository) Link(ctx context.Context, provider, subject, email string, roles []string, now time.Time) (*models.OIDCIdentity, error) {
+	identity := &models.OIDCIdentity{}
+	var storedEmail sql.NullString
+	// A single statement, so concurrent first sign-ins agree on the user ID
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO oidc_identities (provider, subject, user_id, email, roles, created_at, last_login_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $6)
+		ON CONFLICT (provider, subject) DO UPDATE
+		SET email = COALESCE(EXCLUDED.email, oidc_identities.email),
+			roles = EXCLUDED.roles,
+			last_login_at = EXCLUDED.last_login_at
+		RETURNING provider, subject, user_id, email, roles, created_at, last_login_at`,
+		provider, subject, uuid.New().String(), email, pq.Array(roles), now).Scan(
+		&identity.Provider,
+		&identity.Subject,
+		&identity.UserID,
+		&storedEmail,
+		pq.Array(&identity.Roles),
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	identity.Email = storedEmail.String
+	return identity, nil
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type overdueTaskRepository struct {
+	db *sql.DB
+}
+
+// NewOverdueTaskRepository creates a new PostgreSQL overdue task repository
+func NewOverdueTaskRepository(db *sql.DB) repository.OverdueTaskRepository {
+	return &overdueTaskRepository{db: db}
+}
+
+func (r *overdueTaskRepository) MarkOverdue(ctx context.Context, now time.Time) ([]*models.Task, error) {
+	// A single statement, so concurrent instances never flag (and report) a task twice.
+	// Setting overdue_at leaves updated_at alone, so flagging doesn't reset stale detection.
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE tasks
+		SET overdue_at = $1
+		WHERE status IN ('pending', 'in_progress')
+			AND due_date < $1
+			AND (overdue_at IS NULL OR overdue_at < due_date)
+		RETURNING `+taskColumns, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+func (r *overdueTaskRepository) CountOverdue(ctx context.Context, now time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM tasks
+		WHERE status IN ('pending', 'in_progress') AND due_date < $1`, now).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// preferencesColumns lists the columns read by scanPreferences, in order
+const preferencesColumns = "user_id, email, digest, timezone, digest_hour, locale, page_size, stale_alerts, last_digest_at, updated_at"
+
+func scanPreferences(row rowScanner) (*models.NotificationPreferences, error) {
+	preferences := &models.NotificationPreferences{}
+	var lastDigestAt sql.NullTime
+	var updatedAt time.Time
+	err := row.Scan(
+		&preferences.UserID,
+		&preferences.Email,
+		&preferences.Digest,
+		&preferences.Timezone,
+		&preferences.DigestHour,
+		&preferences.Locale,
+		&preferences.PageSize,
+		&preferences.StaleAlerts,
+		&lastDigestAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if lastDigestAt.Valid {
+		preferences.LastDigestAt = &lastDigestAt.Time
+	}
+	preferences.UpdatedAt = &updatedAt
+	return preferences, nil
+}
+
+type preferencesRepository struct {
+	db *sql.DB
+}
+
+// NewPreferencesRepository creates a new PostgreSQL notification preferences repository
+func NewPreferencesRepository(db *sql.DB) repository.PreferencesRepository {
+	return &preferencesRepository{db: db}
+}
+
+func (r *preferencesRepository) Get(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	preferences, err := scanPreferences(r.db.QueryRowContext(ctx,
+		`SELECT `+preferencesColumns+` FROM user_notification_preferences WHERE user_id = $1`, userID))
+	if err == sql.ErrNoRows {
+		return models.DefaultNotificationPreferences(userID), nil
+	}
+	return preferences, err
+}
+
+func (r *preferencesRepository) Save(ctx context.Context, preferences *models.NotificationPreferences) (*models.NotificationPreferences, error) {
+	query := `
+		INSERT INTO user_notification_preferences (user_id, email, digest, timezone, digest_hour, locale, page_size, stale_alerts, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email = EXCLUDED.email,
+			digest = EXCLUDED.digest,
+			timezone = EXCLUDED.timezone,
+			digest_hour = EXCLUDED.digest_hour,
+			locale = EXCLUDED.locale,
+			page_size = EXCLUDED.page_size,
+			stale_alerts = EXCLUDED.stale_alerts,
+			updated_at = EXCLUDED.updated_at
+		RETURNING ` + preferencesColumns
+
+	return scanPreferences(r.db.QueryRowContext(
+		ctx,
+		query,
+		preferences.UserID,
+		preferences.Email,
+		preferences.Digest,
+		preferences.Timezone,
+		preferences.DigestHour,
+		preferences.Locale,
+		preferences.PageSize,
+		preferences.StaleAlerts,
+		time.Now(),
+	))
+}
+
+func (r *preferencesRepository) ListDigestRecipients(ctx context.Context) ([]*models.NotificationPreferences, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+preferencesColumns+`
+		FROM user_notification_preferences
+		WHERE digest <> 'off' AND email <> ''`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipients := []*models.NotificationPreferences{}
+	for rows.Next() {
+		preferences, err := scanPreferences(rows)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, preferences)
+	}
+	return recipients, rows.Err()
+}
+
+func (r *preferencesRepository) ClaimDigest(ctx context.Context, userID string, since, at time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE user_notification_preferences
+		SET last_digest_at = $1
+		WHERE user_id = $2 AND (last_digest_at IS NULL OR last_digest_at < $3)`,
+		at, userID, since)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+type digestRepository struct {
+	db *sql.DB
+}
+
+// NewDigestRepository creates a new PostgreSQL digest repository
+func NewDigestRepository(db *sql.DB) repository.DigestRepository {
+	return &digestRepository{db: db}
+}
+
+func (r *digestRepository) DigestTasks(ctx context.Context, userID string, now, dueBefore, assignedSince time.Time) (*models.Digest, error) {
+	// A user's tasks are those assigned to them and their own unassigned ones
+	const open = `status NOT IN ('completed', 'cancelled', 'draft')
+		AND (assignee_id = $1 OR (assignee_id IS NULL AND owner_id = $1))`
+
+	digest := &models.Digest{}
+	var err error
+	digest.DueSoon, err = r.list(ctx, `
+		SELECT `+taskColumns+` FROM tasks
+		WHERE `+open+` AND due_date >= $2 AND due_date < $3
+		ORDER BY due_date`, userID, now, dueBefore)
+	if err != nil {
+		return nil, err
+	}
+	digest.Overdue, err = r.list(ctx, `
+		SELECT `+taskColumns+` FROM tasks
+		WHERE `+open+` AND due_date < $2
+		ORDER BY due_date`, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	digest.RecentlyAssigned, err = r.list(ctx, `
+		SELECT `+taskColumns+` FROM tasks
+		WHERE status NOT IN ('completed', 'cancelled', 'draft')
+			AND assignee_id = $1 AND owner_id IS DISTINCT FROM $1
+			AND assigned_at > $2 AND assigned_at <= $3
+		ORDER BY assigned_at DESC`, userID, assignedSince, now)
+	if err != nil {
+		return nil, err
+	}
+	return digest, nil
+}
+
+// list runs a query selecting taskColumns; digests list at most 50 tasks per section
+func (r *digestRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.Task, error) {
+	rows, err := r.db.QueryContext(ctx, query+` LIMIT 50`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+type progressRepository struct {
+	db *sql.DB
+}
+
+// NewProgressRepository creates a new PostgreSQL task status history repository
+func NewProgressRepository(db *sql.DB) repository.ProgressRepository {
+	return &progressRepository{db: db}
+}
+
+func (r *progressRepository) RecordStatus(ctx context.Context, task *models.Task, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO task_status_history (task_id, project_key, status, changed_at, org_id)
+		SELECT $1, $2, $3::varchar, $4, NULLIF($5, '')
+		WHERE $3::varchar IS DISTINCT FROM (
+			SELECT status FROM task_status_history
+			WHERE task_id = $1
+			ORDER BY changed_at DESC, id DESC
+			LIMIT 1
+		)`, task.ID, task.ProjectKey, string(task.Status), at, task.OrgID)
+	return err
+}
+
+func (r *progressRepository) RecordDeleted(ctx context.Context, taskID string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO task_status_history (task_id, project_key, status, changed_at, org_id)
+		SELECT task_id, project_key, 'deleted', $2, org_id
+		FROM (
+			SELECT task_id, project_key, status, org_id FROM task_status_history
+			WHERE task_id = $1
+			ORDER BY changed_at DESC, id DESC
+			LIMIT 1
+		) latest
+		WHERE status <> 'deleted'`, taskID, at)
+	return err
+}
+
+func (r *progressRepository) DailyCounts(ctx context.Context, projectKey string, from, to time.Time) ([]*models.ProgressCount, error) {
+	// The status of every task at the end of each day is its latest change before midnight
+	rows, err := r.db.QueryContext(ctx, `
+		WITH days AS (
+			SELECT generate_series($2::date, $3::date, INTERVAL '1 day')::date AS day
+		),
+		latest AS (
+			SELECT DISTINCT ON (d.day, h.task_id) d.day, h.status
+			FROM days d
+			JOIN task_status_history h ON h.project_key = $1 AND h.changed_at < d.day + 1
+				AND `+tenantMatch("h.", 4)+`
+			ORDER BY d.day, h.task_id, h.changed_at DESC, h.id DESC
+		)
+		SELECT day,
+			COUNT(*) FILTER (WHERE status IN ('pending', 'in_progress')),
+			COUNT(*) FILTER (WHERE status = 'completed')
+		FROM latest
+		GROUP BY day
+		ORDER BY day`, projectKey, from.Format(models.ChartDateLayout), to.Format(models.ChartDateLayout), tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []*models.ProgressCount{}
+	for rows.Next() {
+		count := &models.ProgressCount{}
+		if err := rows.Scan(&count.Day, &count.Open, &count.Completed); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
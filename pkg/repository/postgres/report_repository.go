@@ -0,0 +1,260 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// reportColumns lists the columns read by scanReport, in order
+const reportColumns = "id, name, definition, created_by, created_at, updated_at"
+
+// reportDimensionColumns and reportMeasureColumns map what a report may ask for to SQL;
+// nothing else from a definition is ever put in a query
+var (
+	reportDimensionColumns = map[models.ReportDimension]string{
+		models.DimensionStatus:   "t.status",
+		models.DimensionAssignee: "COALESCE(t.assignee_id, '')",
+		models.DimensionTag:      "COALESCE(tt.tag, '')",
+		models.DimensionProject:  "t.project_key",
+	}
+	reportMeasureColumns = map[models.ReportMeasure]string{
+		models.MeasureCount:         "COUNT(*)",
+		models.MeasureTotalEstimate: "COALESCE(SUM(t.estimated_minutes), 0)",
+	}
+	reportDateColumns = map[models.ReportDateField]string{
+		models.DateFieldCreatedAt: "t.created_at",
+		models.DateFieldUpdatedAt: "t.updated_at",
+		models.DateFieldDueDate:   "t.due_date",
+	}
+)
+
+// scanReport reads a report selected with reportColumns
+func scanReport(row rowScanner) (*models.Report, error) {
+	report := &models.Report{}
+	var definition []byte
+	err := row.Scan(
+		&report.ID,
+		&report.Name,
+		&definition,
+		&report.CreatedBy,
+		&report.CreatedAt,
+		&report.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(definition, &report.Definition); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+type reportRepository struct {
+	db *sql.DB
+}
+
+// NewReportRepository creates a new PostgreSQL report repository
+func NewReportRepository(db *sql.DB) repository.ReportRepository {
+	return &reportRepository{db: db}
+}
+
+func (r *reportRepository) Create(ctx context.Context, report *models.Report) (*models.Report, error) {
+	definition, err := json.Marshal(report.Definition)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO reports (id, name, definition, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + reportColumns
+
+	now := time.Now()
+	return scanReport(r.db.QueryRowContext(
+		ctx,
+		query,
+		uuid.New().String(),
+		report.Name,
+		string(definition),
+		report.CreatedBy,
+		now,
+		now,
+	))
+}
+
+func (r *reportRepository) GetByID(ctx context.Context, id string) (*models.Report, error) {
+	report, err := scanReport(r.db.QueryRowContext(ctx,
+		`SELECT `+reportColumns+` FROM reports WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("report not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *reportRepository) List(ctx context.Context) ([]*models.Report, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+reportColumns+` FROM reports ORDER BY name, created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []*models.Report{}
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+func (r *reportRepository) Update(ctx context.Context, id string, input *models.ReportInput) (*models.Report, error) {
+	definition, err := json.Marshal(input.Definition)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE reports
+		SET name = $1, definition = $2, updated_at = $3
+		WHERE id = $4
+		RETURNING ` + reportColumns
+
+	report, err := scanReport(r.db.QueryRowContext(ctx, query, input.Name, string(definition), time.Now(), id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("report not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *reportRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM reports WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("report not found")
+	}
+	return nil
+}
+
+func (r *reportRepository) Run(ctx context.Context, definition *models.ReportDefinition, from, to *time.Time) ([]models.ReportRow, bool, error) {
+	var selects, groups []string
+	joinTags := false
+	for i, dimension := range definition.Dimensions {
+		column, ok := reportDimensionColumns[dimension]
+		if !ok {
+			return nil, false, fmt.Errorf("unknown dimension %q", dimension)
+		}
+		selects = append(selects, column)
+		groups = append(groups, fmt.Sprintf("%d", i+1))
+		if dimension == models.DimensionTag {
+			joinTags = true
+		}
+	}
+	for _, measure := range definition.Measures {
+		column, ok := reportMeasureColumns[measure]
+		if !ok {
+			return nil, false, fmt.Errorf("unknown measure %q", measure)
+		}
+		selects = append(selects, column)
+	}
+	dateColumn, ok := reportDateColumns[definition.DateField]
+	if !ok {
+		return nil, false, fmt.Errorf("unknown date field %q", definition.DateField)
+	}
+
+	conditions := []string{"t.status <> 'draft'", tenantMatch("t.", 1)}
+	args := []interface{}{tenant.Arg(ctx)}
+	if from != nil {
+		args = append(args, *from)
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", dateColumn, len(args)))
+	}
+	if to != nil {
+		args = append(args, *to)
+		conditions = append(conditions, fmt.Sprintf("%s < $%d", dateColumn, len(args)))
+	}
+
+	query := "SELECT " + strings.Join(selects, ", ") + " FROM tasks t"
+	if joinTags {
+		query += " LEFT JOIN task_tags tt ON tt.task_id = t.id"
+	}
+	// One extra row tells whether the result was cut at the limit
+	args = append(args, definition.RowLimit+1)
+	query += " WHERE " + strings.Join(conditions, " AND ") +
+		" GROUP BY " + strings.Join(groups, ", ") +
+		" ORDER BY " + strings.Join(groups, ", ") +
+		fmt.Sprintf(" LIMIT $%d", len(args))
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	// Cancelling ctx only asks the server to stop; the statement timeout makes sure it does
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout := time.Until(deadline).Milliseconds()
+		if timeout < 1 {
+			return nil, false, context.DeadlineExceeded
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout)); err != nil {
+			return nil, false, err
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	result := []models.ReportRow{}
+	for rows.Next() {
+		row := models.ReportRow{
+			Dimensions: make([]string, len(definition.Dimensions)),
+			Measures:   make([]int64, len(definition.Measures)),
+		}
+		dest := make([]interface{}, 0, len(selects))
+		for i := range row.Dimensions {
+			dest = append(dest, &row.Dimensions[i])
+		}
+		for i := range row.Measures {
+			dest = append(dest, &row.Measures[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, false, err
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if len(result) > definition.RowLimit {
+		return result[:definition.RowLimit], true, nil
+	}
+	return result, false, nil
+}
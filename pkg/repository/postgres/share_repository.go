@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type shareRepository struct {
+	db *sql.DB
+}
+
+// NewShareRepository creates a new PostgreSQL task share repository
+func NewShareRepository(db *sql.DB) repository.ShareRepository {
+	return &shareRepository{db: db}
+}
+
+func (r *shareRepository) Create(ctx context.Context, share *models.TaskShare) (*models.TaskShare, error) {
+	query := `
+		INSERT INTO task_shares (id, task_id, permission, created_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, task_id, permission, created_by, expires_at, created_at, revoked_at`
+
+	result := &models.TaskShare{}
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		uuid.New().String(),
+		share.TaskID,
+		share.Permission,
+		share.CreatedBy,
+		share.ExpiresAt,
+		time.Now(),
+	).Scan(
+		&result.ID,
+		&result.TaskID,
+		&result.Permission,
+		&result.CreatedBy,
+		&result.ExpiresAt,
+		&result.CreatedAt,
+		&result.RevokedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *shareRepository) GetByID(ctx context.Context, id string) (*models.TaskShare, error) {
+	query := `
+		SELECT id, task_id, permission, created_by, expires_at, created_at, revoked_at
+		FROM task_shares
+		WHERE id = $1`
+
+	share := &models.TaskShare{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&share.ID,
+		&share.TaskID,
+		&share.Permission,
+		&share.CreatedBy,
+		&share.ExpiresAt,
+		&share.CreatedAt,
+		&share.RevokedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.New("share not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return share, nil
+}
+
+func (r *shareRepository) ListByTask(ctx context.Context, taskID string) ([]*models.TaskShare, error) {
+	query := `
+		SELECT id, task_id, permission, created_by, expires_at, created_at, revoked_at
+		FROM task_shares
+		WHERE task_id = $1
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []*models.TaskShare
+	for rows.Next() {
+		share := &models.TaskShare{}
+		err := rows.Scan(
+			&share.ID,
+			&share.TaskID,
+			&share.Permission,
+			&share.CreatedBy,
+			&share.ExpiresAt,
+			&share.CreatedAt,
+			&share.RevokedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+func (r *shareRepository) Revoke(ctx context.Context, taskID, id string) error {
+	query := `UPDATE task_shares SET revoked_at = $1 WHERE id = $2 AND task_id = $3 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id, taskID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("share not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+type snoozeRepository struct {
+	db *sql.DB
+}
+
+// NewSnoozeRepository creates a new PostgreSQL task snooze repository
+func NewSnoozeRepository(db *sql.DB) repository.SnoozeRepository {
+	return &snoozeRepository{db: db}
+}
+
+func (r *snoozeRepository) Snooze(ctx context.Context, id string, until *time.Time) (*models.Task, error) {
+	// Snoozing leaves updated_at alone, so it doesn't reset stale detection
+	task, err := scanTask(r.db.QueryRowContext(ctx, `
+		UPDATE tasks
+		SET snoozed_until = $2
+		WHERE id = $1 AND status IN ('pending', 'in_progress') AND `+tenantMatch("", 3)+`
+		RETURNING `+taskColumns,
+		id, until, tenant.Arg(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("task not found or not open")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := loadTags(ctx, r.db, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type staleTaskRepository struct {
+	db *sql.DB
+}
+
+// NewStaleTaskRepository creates a new PostgreSQL stale task repository
+func NewStaleTaskRepository(db *sql.DB) repository.StaleTaskRepository {
+	return &staleTaskRepository{db: db}
+}
+
+// GetProjectSettings returns the tenant's settings of the project; each tenant configures
+// its projects separately
+func (r *staleTaskRepository) GetProjectSettings(ctx context.Context, projectKey string) (*models.ProjectSettings, error) {
+	settings := &models.ProjectSettings{ProjectKey: projectKey}
+	var staleAfterDays sql.NullInt64
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx,
+		`SELECT stale_after_days, search_language, updated_by, updated_at FROM project_settings
+		WHERE project_key = $1 AND COALESCE(org_id, '') = $2`,
+		projectKey, tenantOrgID(ctx, "")).Scan(&staleAfterDays, &settings.SearchLanguage, &settings.UpdatedBy, &updatedAt)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if staleAfterDays.Valid {
+		days := int(staleAfterDays.Int64)
+		settings.StaleAfterDays = &days
+	}
+	settings.UpdatedAt = &updatedAt
+	return settings, nil
+}
+
+func (r *staleTaskRepository) SaveProjectSettings(ctx context.Context, settings *models.ProjectSettings) (*models.ProjectSettings, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO project_settings (project_key, stale_after_days, search_language, updated_by, updated_at, org_id)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		ON CONFLICT ((COALESCE(org_id, '')), project_key) DO UPDATE
+		SET stale_after_days = EXCLUDED.stale_after_days,
+			search_language = EXCLUDED.search_language,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING project_key, stale_after_days, search_language, updated_by, updated_at`
+
+	result := &models.ProjectSettings{}
+	var staleAfterDays sql.NullInt64
+	var updatedAt time.Time
+	orgID := tenantOrgID(ctx, "")
+	err = tx.QueryRowContext(ctx, query, settings.ProjectKey, settings.StaleAfterDays, settings.SearchLanguage, settings.UpdatedBy, time.Now(), orgID).
+		Scan(&result.ProjectKey, &staleAfterDays, &result.SearchLanguage, &result.UpdatedBy, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tasks are indexed with their project's search language (kept by a trigger for new
+	// tasks), so changing it regenerates the search vectors of the project's existing tasks
+	_, err = tx.ExecContext(ctx,
+		`UPDATE tasks SET search_language = $2::regconfig
+		WHERE project_key = $1 AND COALESCE(org_id, '') = $3 AND search_language <> $2::regconfig`,
+		result.ProjectKey, result.SearchLanguage, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if staleAfterDays.Valid {
+		days := int(staleAfterDays.Int64)
+		result.StaleAfterDays = &days
+	}
+	result.UpdatedAt = &updatedAt
+	return result, nil
+}
+
+func (r *staleTaskRepository) MarkStale(ctx context.Context, now time.Time, defaultDays int) ([]*models.Task, error) {
+	// A single statement, so concurrent instances never flag (and report) a task twice:
+	// the conditions on tasks are checked again on rows another instance just flagged.
+	// Setting stale_at leaves updated_at alone, so the task stays stale until updated.
+	rows, err := r.db.QueryContext(ctx, `
+		WITH thresholds AS (
+			SELECT t.id, COALESCE(s.stale_after_days, $2) AS days
+			FROM tasks t
+			LEFT JOIN project_settings s
+				ON s.project_key = t.project_key AND COALESCE(s.org_id, '') = COALESCE(t.org_id, '')
+			WHERE t.status IN ('pending', 'in_progress')
+				AND (t.stale_at IS NULL OR t.stale_at < t.updated_at)
+		)
+		UPDATE tasks
+		SET stale_at = $1
+		FROM thresholds
+		WHERE tasks.id = thresholds.id
+			AND thresholds.days > 0
+			AND tasks.status IN ('pending', 'in_progress')
+			AND (tasks.stale_at IS NULL OR tasks.stale_at < tasks.updated_at)
+			AND tasks.updated_at < $1 - make_interval(days => thresholds.days)
+		RETURNING `+qualifiedTaskColumns("tasks"), now, defaultDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+type starRepository struct {
+	db *sql.DB
+}
+
+// NewStarRepository creates a new PostgreSQL task star repository
+func NewStarRepository(db *sql.DB) repository.StarRepository {
+	return &starRepository{db: db}
+}
+
+func (r *starRepository) Star(ctx context.Context, taskID, userID string) error {
+	query := `
+		INSERT INTO task_stars (task_id, user_id)
+		SELECT id, $2 FROM tasks WHERE id = $1 AND ` + tenantMatch("", 3) + `
+		ON CONFLICT (task_id, user_id) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query, taskID, userID, tenant.Arg(ctx))
+	return err
+}
+
+func (r *starRepository) Unstar(ctx context.Context, taskID, userID string) error {
+	query := `DELETE FROM task_stars WHERE task_id = $1 AND user_id = $2 AND ` + taskInTenant("", 3)
+
+	_, err := r.db.ExecContext(ctx, query, taskID, userID, tenant.Arg(ctx))
+	return err
+}
+
+func (r *starRepository) StarredIDs(ctx context.Context, userID string, taskIDs []string) (map[string]bool, error) {
+	starred := make(map[string]bool)
+	if len(taskIDs) == 0 {
+		return starred, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT task_id FROM task_stars WHERE user_id = $1 AND task_id = ANY($2) AND `+taskInTenant("", 3),
+		userID, pq.Array(taskIDs), tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID string
+		if err := rows.Scan(&taskID); err != nil {
+			return nil, err
+		}
+		starred[taskID] = true
+	}
+	return starred, rows.Err()
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+type syncRepository struct {
+	db *sql.DB
+}
+
+// NewSyncRepository creates a new PostgreSQL task change repository
+func NewSyncRepository(db *sql.DB) repository.SyncRepository {
+	return &syncRepository{db: db}
+}
+
+// seqScanner reads a change_seq column ahead of the columns scanned by the wrapped reader
+type seqScanner struct {
+	row rowScanner
+	seq *int64
+}
+
+func (s seqScanner) Scan(dest ...interface{}) error {
+	return s.row.Scan(append([]interface{}{s.seq}, dest...)...)
+}
+
+func (r *syncRepository) Changes(ctx context.Context, after models.SyncCursor, viewer string, limit int) ([]*models.SyncChange, int64, error) {
+	// Tasks and tombstones are read from one snapshot, which also fixes the bound. IDs are
+	// compared in byte order ("C" collation), as mergeChanges and cursors compare them.
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	// Transactions below the oldest one still running have all finished
+	var bound int64
+	err = tx.QueryRowContext(ctx, `SELECT pg_snapshot_xmin(pg_current_snapshot())::text::bigint`).Scan(&bound)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT change_seq, `+taskColumns+`
+		FROM tasks
+		WHERE (change_seq, id COLLATE "C") > ($1, $2) AND change_seq < $3
+			AND (status <> 'draft' OR owner_id = $4) AND `+tenantMatch("", 6)+`
+		ORDER BY change_seq, id COLLATE "C"
+		LIMIT $5`,
+		after.Seq, after.TaskID, bound, viewer, limit, tenant.Arg(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var upserts []*models.SyncChange
+	var tasks []*models.Task
+	for rows.Next() {
+		change := &models.SyncChange{Op: models.SyncUpsert}
+		task, err := scanTask(seqScanner{row: rows, seq: &change.Seq})
+		if err != nil {
+			return nil, 0, err
+		}
+		change.TaskID, change.Task = task.ID, task
+		upserts = append(upserts, change)
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	rows.Close()
+	if err := loadTags(ctx, tx, tasks...); err != nil {
+		return nil, 0, err
+	}
+
+	// A first sync has no tasks to drop
+	var deletes []*models.SyncChange
+	if !after.IsZero() {
+		if deletes, err = r.tombstones(ctx, tx, after, bound, viewer, limit); err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+
+	return mergeChanges(upserts, deletes, limit), bound, nil
+}
+
+// tombstones reads deleted tasks after the cursor, like Changes
+func (r *syncRepository) tombstones(ctx context.Context, tx *sql.Tx, after models.SyncCursor, bound int64, viewer string, limit int) ([]*models.SyncChange, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT change_seq, task_id, deleted_at
+		FROM task_tombstones
+		WHERE (change_seq, task_id COLLATE "C") > ($1, $2) AND change_seq < $3
+			AND (NOT draft OR owner_id = $4) AND `+tenantMatch("", 6)+`
+		ORDER BY change_seq, task_id COLLATE "C"
+		LIMIT $5`,
+		after.Seq, after.TaskID, bound, viewer, limit, tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deletes []*models.SyncChange
+	for rows.Next() {
+		change := &models.SyncChange{Op: models.SyncDelete}
+		var deletedAt time.Time
+		if err := rows.Scan(&change.Seq, &change.TaskID, &deletedAt); err != nil {
+			return nil, err
+		}
+		change.DeletedAt = &deletedAt
+		deletes = append(deletes, change)
+	}
+	return deletes, rows.Err()
+}
+
+// mergeChanges merges two lists in sequence order into one of at most limit changes
+func mergeChanges(upserts, deletes []*models.SyncChange, limit int) []*models.SyncChange {
+	changes := make([]*models.SyncChange, 0, len(upserts)+len(deletes))
+	for len(changes) < limit && (len(upserts) > 0 || len(deletes) > 0) {
+		if len(deletes) == 0 || (len(upserts) > 0 && !changeAfter(upserts[0], deletes[0])) {
+			changes, upserts = append(changes, upserts[0]), upserts[1:]
+		} else {
+			changes, deletes = append(changes, deletes[0]), deletes[1:]
+		}
+	}
+	return changes
+}
+
+// changeAfter reports whether a comes after b in the change sequence
+func changeAfter(a, b *models.SyncChange) bool {
+	if a.Seq != b.Seq {
+		return a.Seq > b.Seq
+	}
+	return a.TaskID > b.TaskID
+}
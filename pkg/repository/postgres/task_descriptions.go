@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// Descriptions are versioned as gzip-compressed snapshots: prose compresses well, and a
+// snapshot can be read without replaying earlier versions.
+
+type descriptionRepository struct {
+	db *sql.DB
+}
+
+// NewDescriptionRepository creates a new PostgreSQL description history repository
+func NewDescriptionRepository(db *sql.DB) repository.DescriptionRepository {
+	return &descriptionRepository{db: db}
+}
+
+func scanDescriptionVersion(row rowScanner) (*models.DescriptionVersion, error) {
+	version := &models.DescriptionVersion{}
+	var content []byte
+	var editorID sql.NullString
+	err := row.Scan(
+		&version.TaskID,
+		&version.Version,
+		&content,
+		&editorID,
+		&version.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	version.EditorID = editorID.String
+	if version.Description, err = decompressDescription(content); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func (r *descriptionRepository) ListVersions(ctx context.Context, taskID string) ([]*models.DescriptionVersion, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT task_id, version, content, editor_id, created_at
+		FROM task_description_versions
+		WHERE task_id = $1
+		ORDER BY version`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []*models.DescriptionVersion{}
+	for rows.Next() {
+		version, err := scanDescriptionVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+func (r *descriptionRepository) GetVersion(ctx context.Context, taskID string, version int) (*models.DescriptionVersion, error) {
+	result, err := scanDescriptionVersion(r.db.QueryRowContext(ctx, `
+		SELECT task_id, version, content, editor_id, created_at
+		FROM task_description_versions
+		WHERE task_id = $1 AND version = $2`, taskID, version))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("description version not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// lockDescription locks a published task's row until tx ends and returns its current
+// description, so concurrent edits number their versions one after another
+func lockDescription(ctx context.Context, tx *sql.Tx, id string) (string, error) {
+	var description string
+	err := tx.QueryRowContext(ctx,
+		`SELECT description FROM tasks WHERE id = $1 AND status <> 'draft' AND `+tenantMatch("", 2)+` FOR UPDATE`,
+		id, tenant.Arg(ctx)).Scan(&description)
+	if err == sql.ErrNoRows {
+		return "", errors.New("task not found")
+	}
+	return description, err
+}
+
+// recordDescription saves description as a new version if it differs from previous.
+// A task without history, e.g. one created before descriptions were versioned, first
+// gets previous as its initial version. Only the newest MaxDescriptionVersions are kept.
+func recordDescription(ctx context.Context, tx *sql.Tx, taskID, previous, description, editorID string) error {
+	if description == previous {
+		return nil
+	}
+
+	var last int
+	err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM task_description_versions WHERE task_id = $1`,
+		taskID).Scan(&last)
+	if err != nil {
+		return err
+	}
+	if last == 0 && previous != "" {
+		if err := insertDescriptionVersion(ctx, tx, taskID, 1, previous, ""); err != nil {
+			return err
+		}
+		last = 1
+	}
+	if err := insertDescriptionVersion(ctx, tx, taskID, last+1, description, editorID); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM task_description_versions WHERE task_id = $1 AND version <= $2`,
+		taskID, last+1-models.MaxDescriptionVersions)
+	return err
+}
+
+func insertDescriptionVersion(ctx context.Context, tx *sql.Tx, taskID string, version int, description, editorID string) error {
+	content, err := compressDescription(description)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO task_description_versions (task_id, version, content, editor_id, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)`,
+		taskID, version, content, editorID, time.Now())
+	return err
+}
+
+func compressDescription(description string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(description)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompressDescription(content []byte) (string, error) {
+	r, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	description, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(description), nil
+}
@@ -3,101 +3,373 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"sample/task-management-system/pkg/models"
 	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
 )
 
+// taskColumns lists the columns read by scanTask, in order
+const taskColumns = "id, project_key, number, slug, title, description, status, priority, due_date, owner_id, assignee_id, parent_id, metadata, checklist_total, checklist_done, estimated_minutes, logged_minutes, stale_at, overdue_at, position, created_at, updated_at, snoozed_until, sla_respond_within, sla_resolve_within, responded_at, resolved_at, team_id, org_id"
+
+// qualifiedTaskColumns lists taskColumns qualified with a table alias, e.g. "t.id, t.title"
+func qualifiedTaskColumns(alias string) string {
+	return alias + "." + strings.ReplaceAll(taskColumns, ", ", ", "+alias+".")
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTask reads a task selected with taskColumns
+func scanTask(row rowScanner) (*models.Task, error) {
+	task := &models.Task{}
+	var ownerID, assigneeID, parentID, teamID, orgID sql.NullString
+	var dueDate sql.NullTime
+	var metadata []byte
+	var checklistTotal, checklistDone int
+	var estimatedMinutes sql.NullInt64
+	var staleAt, overdueAt, snoozedUntil sql.NullTime
+	var respondWithin, resolveWithin sql.NullInt64
+	var respondedAt, resolvedAt sql.NullTime
+	err := row.Scan(
+		&task.ID,
+		&task.ProjectKey,
+		&task.Number,
+		&task.Slug,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.Priority,
+		&dueDate,
+		&ownerID,
+		&assigneeID,
+		&parentID,
+		&metadata,
+		&checklistTotal,
+		&checklistDone,
+		&estimatedMinutes,
+		&task.LoggedMinutes,
+		&staleAt,
+		&overdueAt,
+		&task.Position,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&snoozedUntil,
+		&respondWithin,
+		&resolveWithin,
+		&respondedAt,
+		&resolvedAt,
+		&teamID,
+		&orgID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	task.DueDate = dueDate.Time // drafts may not have one yet
+	task.OwnerID = ownerID.String
+	task.AssigneeID = assigneeID.String
+	task.TeamID = teamID.String
+	task.ParentID = parentID.String
+	task.OrgID = orgID.String
+	if err := json.Unmarshal(metadata, &task.Metadata); err != nil {
+		return nil, err
+	}
+	if len(task.Metadata) == 0 {
+		task.Metadata = nil
+	}
+	task.Checklist = models.NewChecklistProgress(checklistTotal, checklistDone)
+	if estimatedMinutes.Valid {
+		minutes := int(estimatedMinutes.Int64)
+		task.EstimatedMinutes = &minutes
+	}
+	// Flags from before the latest update no longer apply
+	if staleAt.Valid && !staleAt.Time.Before(task.UpdatedAt) && task.IsOpen() {
+		task.StaleSince = &staleAt.Time
+	}
+	// Flags from before the due date was moved later no longer apply
+	if overdueAt.Valid && !overdueAt.Time.Before(task.DueDate) && task.IsOpen() {
+		task.OverdueSince = &overdueAt.Time
+	}
+	// Snoozes that ran out no longer apply
+	if snoozedUntil.Valid && snoozedUntil.Time.After(time.Now()) {
+		task.SnoozedUntil = &snoozedUntil.Time
+	}
+	// The SLA's targets are evaluated by the service layer
+	if respondWithin.Valid || resolveWithin.Valid {
+		task.SLA = models.NewTaskSLA(nullSeconds(respondWithin), nullSeconds(resolveWithin))
+		if respondedAt.Valid {
+			task.SLA.RespondedAt = &respondedAt.Time
+		}
+		if resolvedAt.Valid {
+			task.SLA.ResolvedAt = &resolvedAt.Time
+		}
+	}
+	task.Key = models.FormatTaskKey(task.ProjectKey, task.Number)
+	return task, nil
+}
+
+// nullSeconds reads a nullable number of seconds as a duration
+func nullSeconds(value sql.NullInt64) *time.Duration {
+	if !value.Valid {
+		return nil
+	}
+	duration := time.Duration(value.Int64) * time.Second
+	return &duration
+}
+
 type taskRepository struct {
 	db *sql.DB
 }
 
+// tenantMatch restricts tasks to the tenant passed as parameter n: the tenant's own
+// tasks, or the tasks without an organization for the default tenant. alias qualifies
+// org_id, e.g. "t." or "" for none. Unscoped contexts pass NULL, see tenant.Arg, and
+// match every task. Every query reading or changing tasks applies it.
+func tenantMatch(alias string, n int) string {
+	return fmt.Sprintf("($%d::text IS NULL OR COALESCE(%sorg_id, '') = $%d)", n, alias, n)
+}
+
+// taskInTenant restricts rows referencing a task by task_id to the tasks tenantMatch
+// allows for parameter n, for task data without an org_id of its own
+func taskInTenant(alias string, n int) string {
+	return fmt.Sprintf("%stask_id IN (SELECT id FROM tasks WHERE %s)", alias, tenantMatch("", n))
+}
+
+// tenantOrgID returns the organization new tasks belong to: the tenant of ctx, or
+// orgID in unscoped contexts such as import jobs
+func tenantOrgID(ctx context.Context, orgID string) string {
+	if id, ok := tenant.FromContext(ctx); ok {
+		return id
+	}
+	return orgID
+}
+
 // NewTaskRepository creates a new PostgreSQL task repository
 func NewTaskRepository(db *sql.DB) repository.TaskRepository {
 	return &taskRepository{db: db}
 }
 
 func (r *taskRepository) Create(ctx context.Context, task *models.TaskCreate) (*models.Task, error) {
-	query := `
-		INSERT INTO tasks (id, title, description, status, due_date, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, title, description, status, due_date, created_at, updated_at`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := createTask(ctx, tx, task, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateBatch creates the tasks in one transaction, so a batch costs a single commit
+func (r *taskRepository) CreateBatch(ctx context.Context, tasks []*models.TaskCreate) ([]*models.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
 	now := time.Now()
+	created := make([]*models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		result, err := createTask(ctx, tx, task, now)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, result)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// createTask inserts a task with its tags and first description version in tx
+func createTask(ctx context.Context, tx *sql.Tx, task *models.TaskCreate, now time.Time) (*models.Task, error) {
+	// New tasks go to the bottom of their board column; the project's counter row, locked
+	// by nextTaskNumber, keeps concurrent creates from taking the same position
+	query := `
+		INSERT INTO tasks (id, project_key, number, slug, title, description, status, priority, due_date, owner_id, assignee_id, assigned_at, parent_id, metadata, estimated_minutes, position, created_at, updated_at, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''), CASE WHEN $11 <> '' THEN $15::timestamp END, NULLIF($12, ''), $13, NULLIF($14, 0),
+			(SELECT COALESCE(MAX(position), 0) + $17 FROM tasks WHERE project_key = $2 AND status = $7), $15, $16, NULLIF($18, ''))
+		RETURNING ` + taskColumns
+
 	id := uuid.New().String()
 
-	result := &models.Task{}
-	err := r.db.QueryRowContext(
+	number, err := nextTaskNumber(ctx, tx, task.ProjectKey)
+	if err != nil {
+		return nil, err
+	}
+	orgID := tenantOrgID(ctx, task.OrgID)
+	slug, err := uniqueSlug(ctx, tx, orgID, task.ProjectKey, models.Slugify(task.Title), id)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := encodeMetadata(task.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := scanTask(tx.QueryRowContext(
 		ctx,
 		query,
 		id,
+		task.ProjectKey,
+		number,
+		slug,
 		task.Title,
 		task.Description,
 		task.Status,
+		task.Priority,
 		task.DueDate,
+		task.OwnerID,
+		task.AssigneeID,
+		task.ParentID,
+		metadata,
+		task.EstimatedMinutes,
 		now,
 		now,
-	).Scan(
-		&result.ID,
-		&result.Title,
-		&result.Description,
-		&result.Status,
-		&result.DueDate,
-		&result.CreatedAt,
-		&result.UpdatedAt,
-	)
-
+		positionGap,
+		orgID,
+	))
 	if err != nil {
 		return nil, err
 	}
 
+	if err := replaceTags(ctx, tx, id, task.Tags); err != nil {
+		return nil, err
+	}
+	if err := recordDescription(ctx, tx, id, "", task.Description, task.OwnerID); err != nil {
+		return nil, err
+	}
+
+	result.Tags = task.Tags
 	return result, nil
 }
 
 func (r *taskRepository) GetByID(ctx context.Context, id string) (*models.Task, error) {
 	query := `
-		SELECT id, title, description, status, due_date, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks
-		WHERE id = $1`
+		WHERE id = $1 AND ` + tenantMatch("", 2)
 
-	task := &models.Task{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&task.ID,
-		&task.Title,
-		&task.Description,
-		&task.Status,
-		&task.DueDate,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, id, tenant.Arg(ctx)))
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadTags(ctx, r.db, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func (r *taskRepository) GetByKey(ctx context.Context, projectKey string, number int64) (*models.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE project_key = $1 AND number = $2 AND ` + tenantMatch("", 3)
+
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, projectKey, number, tenant.Arg(ctx)))
+
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadTags(ctx, r.db, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func (r *taskRepository) GetBySlug(ctx context.Context, projectKey, slug string) (*models.Task, error) {
+	// The current slug wins over a slug the task or another task had before a rename
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE project_key = $1 AND (slug = $2 OR id IN (
+			SELECT task_id FROM task_slug_history WHERE project_key = $1 AND slug = $2 AND ` + tenantMatch("", 3) + `
+		)) AND ` + tenantMatch("", 3) + `
+		ORDER BY slug = $2 DESC
+		LIMIT 1`
+
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, projectKey, slug, tenant.Arg(ctx)))
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("task not found")
+		return nil, models.ErrTaskNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
 
+	if err := loadTags(ctx, r.db, task); err != nil {
+		return nil, err
+	}
+
 	return task, nil
 }
 
+// nextTaskNumber hands out the next number of a project. The counter row stays locked
+// until tx ends, so concurrent creates in the same project are numbered one after another.
+func nextTaskNumber(ctx context.Context, tx *sql.Tx, projectKey string) (int64, error) {
+	var number int64
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO project_task_counters (project_key, last_number)
+		VALUES ($1, 1)
+		ON CONFLICT (project_key) DO UPDATE SET last_number = project_task_counters.last_number + 1
+		RETURNING last_number`, projectKey).Scan(&number)
+	return number, err
+}
+
 func (r *taskRepository) Update(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error) {
+	// An empty assignee or parent clears it. Drafts are only changed with SaveDraft.
+	// Changing the assignee restarts assigned_at.
 	query := `
 		UPDATE tasks
 		SET title = COALESCE($1, title),
 			description = COALESCE($2, description),
 			status = COALESCE($3, status),
-			due_date = COALESCE($4, due_date),
-			updated_at = $5
-		WHERE id = $6
-		RETURNING id, title, description, status, due_date, created_at, updated_at`
-
-	var title, description *string
+			priority = COALESCE($4, priority),
+			due_date = COALESCE($5, due_date),
+			assignee_id = NULLIF(COALESCE($6, assignee_id), ''),
+			assigned_at = CASE
+				WHEN NULLIF(COALESCE($6, assignee_id), '') IS DISTINCT FROM assignee_id THEN $10
+				ELSE assigned_at
+			END,
+			parent_id = NULLIF(COALESCE($7, parent_id), ''),
+			slug = COALESCE($8, slug),
+			metadata = COALESCE($9::jsonb, metadata),
+			updated_at = $10,
+			estimated_minutes = NULLIF(COALESCE($12, estimated_minutes), 0)
+		WHERE id = $11 AND status <> 'draft' AND ` + tenantMatch("", 13) + `
+		RETURNING ` + taskColumns
+
+	var title, description, assigneeID, parentID *string
 	var status *models.TaskStatus
+	var priority *models.TaskPriority
 	var dueDate *time.Time
 
 	if task.Title != nil {
@@ -109,44 +381,98 @@ func (r *taskRepository) Update(ctx context.Context, id string, task *models.Tas
 	if task.Status != nil {
 		status = task.Status
 	}
+	if task.Priority != nil {
+		priority = task.Priority
+	}
 	if task.DueDate != nil {
 		dueDate = task.DueDate
 	}
+	if task.AssigneeID != nil {
+		assigneeID = task.AssigneeID
+	}
+	if task.ParentID != nil {
+		parentID = task.ParentID
+	}
+	var metadata *string
+	if task.Metadata != nil {
+		encoded, err := encodeMetadata(task.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		metadata = &encoded
+	}
 
-	result := &models.Task{}
-	err := r.db.QueryRowContext(
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var previousDescription string
+	if description != nil {
+		if previousDescription, err = lockDescription(ctx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	var slug *string
+	if title != nil {
+		renamed, err := renameSlug(ctx, tx, id, *title)
+		if err != nil {
+			return nil, err
+		}
+		slug = &renamed
+	}
+
+	result, err := scanTask(tx.QueryRowContext(
 		ctx,
 		query,
 		title,
 		description,
 		status,
+		priority,
 		dueDate,
+		assigneeID,
+		parentID,
+		slug,
+		metadata,
 		time.Now(),
 		id,
-	).Scan(
-		&result.ID,
-		&result.Title,
-		&result.Description,
-		&result.Status,
-		&result.DueDate,
-		&result.CreatedAt,
-		&result.UpdatedAt,
-	)
+		task.EstimatedMinutes,
+		tenant.Arg(ctx),
+	))
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("task not found")
+		return nil, models.ErrTaskNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
 
+	if task.Tags != nil {
+		if err := replaceTags(ctx, tx, id, *task.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if description != nil {
+		if err := recordDescription(ctx, tx, id, previousDescription, *description, task.EditorID); err != nil {
+			return nil, err
+		}
+	}
+	if err := loadTags(ctx, tx, result); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
 func (r *taskRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM tasks WHERE id = $1`
+	query := `DELETE FROM tasks WHERE id = $1 AND ` + tenantMatch("", 2)
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := r.db.ExecContext(ctx, query, id, tenant.Arg(ctx))
 	if err != nil {
 		return err
 	}
@@ -157,24 +483,121 @@ func (r *taskRepository) Delete(ctx context.Context, id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return errors.New("task not found")
+		return models.ErrTaskNotFound
 	}
 
 	return nil
 }
 
+// taskConditions builds the WHERE clause selecting a filter's tasks in the tenant of ctx,
+// with its parameters and the number of the next parameter
+func taskConditions(ctx context.Context, filter repository.TaskFilter) (string, []interface{}, int) {
+	params := []interface{}{tenant.Arg(ctx)}
+	conditions := []string{tenantMatch("", 1)}
+
+	paramCount := 2
+	if len(filter.Status) > 0 {
+		placeholders := make([]string, len(filter.Status))
+		for i, status := range filter.Status {
+			placeholders[i] = fmt.Sprintf("$%d", paramCount)
+			params = append(params, status)
+			paramCount++
+		}
+		conditions = append(conditions, "status IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if filter.Priority != "" {
+		conditions = append(conditions, fmt.Sprintf("priority = $%d", paramCount))
+		params = append(params, filter.Priority)
+		paramCount++
+	}
+	if filter.AssigneeID != "" {
+		conditions = append(conditions, fmt.Sprintf("assignee_id = $%d", paramCount))
+		params = append(params, filter.AssigneeID)
+		paramCount++
+	}
+	if filter.TeamID != "" {
+		conditions = append(conditions, fmt.Sprintf("team_id = $%d", paramCount))
+		params = append(params, filter.TeamID)
+		paramCount++
+	}
+	if filter.ProjectKey != "" {
+		conditions = append(conditions, fmt.Sprintf("project_key = $%d", paramCount))
+		params = append(params, filter.ProjectKey)
+		paramCount++
+	}
+	if filter.OwnerID != "" {
+		conditions = append(conditions, fmt.Sprintf("owner_id = $%d", paramCount))
+		params = append(params, filter.OwnerID)
+		paramCount++
+	}
+	if filter.DueAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("due_date >= $%d", paramCount))
+		params = append(params, *filter.DueAfter)
+		paramCount++
+	}
+	if filter.DueBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("due_date < $%d", paramCount))
+		params = append(params, *filter.DueBefore)
+		paramCount++
+	}
+	if len(filter.Tags) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"id IN (SELECT task_id FROM task_tags WHERE tag = ANY($%d) GROUP BY task_id HAVING COUNT(*) = $%d)",
+			paramCount, paramCount+1))
+		params = append(params, pq.Array(filter.Tags), len(filter.Tags))
+		paramCount += 2
+	}
+	if filter.Stale {
+		conditions = append(conditions, "stale_at >= updated_at AND status IN ('pending', 'in_progress')")
+	}
+	switch filter.Snoozed {
+	case repository.SnoozedHidden:
+		conditions = append(conditions, fmt.Sprintf("(snoozed_until IS NULL OR snoozed_until <= $%d)", paramCount))
+		params = append(params, time.Now().UTC())
+		paramCount++
+	case repository.SnoozedOnly:
+		conditions = append(conditions, fmt.Sprintf("snoozed_until > $%d", paramCount))
+		params = append(params, time.Now().UTC())
+		paramCount++
+	}
+	if len(filter.IDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("id = ANY($%d)", paramCount))
+		params = append(params, pq.Array(filter.IDs))
+		paramCount++
+	}
+	// Sorted so the same filter always yields the same query
+	metadataKeys := make([]string, 0, len(filter.Metadata))
+	for key := range filter.Metadata {
+		metadataKeys = append(metadataKeys, key)
+	}
+	sort.Strings(metadataKeys)
+	for _, key := range metadataKeys {
+		conditions = append(conditions, fmt.Sprintf("metadata ->> $%d = $%d", paramCount, paramCount+1))
+		params = append(params, key, filter.Metadata[key])
+		paramCount += 2
+	}
+	conditions = append(conditions, fmt.Sprintf("(status <> 'draft' OR owner_id = $%d)", paramCount))
+	params = append(params, filter.Viewer)
+	paramCount++
+	if filter.StarredBy != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"id IN (SELECT task_id FROM task_stars WHERE user_id = $%d)", paramCount))
+		params = append(params, filter.StarredBy)
+		paramCount++
+	}
+
+	var whereClause string
+	if len(conditions) > 0 {
+		whereClause = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return whereClause, params, paramCount
+}
+
 func (r *taskRepository) List(ctx context.Context, filter repository.TaskFilter) ([]*models.Task, int, error) {
 	// First, get total count
 	countQuery := `SELECT COUNT(*) FROM tasks`
-	var params []interface{}
-	var whereClause string
-
-	paramCount := 1
-	if filter.Status != "" {
-		whereClause = fmt.Sprintf(" WHERE status = $%d", paramCount)
-		params = append(params, filter.Status)
-		paramCount++
-	}
+	whereClause, params, paramCount := taskConditions(ctx, filter)
 
 	var total int
 	err := r.db.QueryRowContext(ctx, countQuery+whereClause, params...).Scan(&total)
@@ -184,7 +607,7 @@ func (r *taskRepository) List(ctx context.Context, filter repository.TaskFilter)
 
 	// Then get paginated results
 	query := `
-		SELECT id, title, description, status, due_date, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks`
 
 	if whereClause != "" {
@@ -192,7 +615,7 @@ func (r *taskRepository) List(ctx context.Context, filter repository.TaskFilter)
 	}
 
 	// Add pagination
-	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d OFFSET $%d", paramCount, paramCount+1)
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", orderBy(filter), paramCount, paramCount+1)
 	params = append(params, filter.Limit, (filter.Page-1)*filter.Limit)
 
 	rows, err := r.db.QueryContext(ctx, query, params...)
@@ -203,16 +626,7 @@ func (r *taskRepository) List(ctx context.Context, filter repository.TaskFilter)
 
 	var tasks []*models.Task
 	for rows.Next() {
-		task := &models.Task{}
-		err := rows.Scan(
-			&task.ID,
-			&task.Title,
-			&task.Description,
-			&task.Status,
-			&task.DueDate,
-			&task.CreatedAt,
-			&task.UpdatedAt,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -223,5 +637,284 @@ func (r *taskRepository) List(ctx context.Context, filter repository.TaskFilter)
 		return nil, 0, err
 	}
 
+	if err := loadTags(ctx, r.db, tasks...); err != nil {
+		return nil, 0, err
+	}
+
 	return tasks, total, nil
-} 
\ No newline at end of file
+}
+
+// streamBatch is the number of rows Stream fetches from its cursor at a time
+const streamBatch = 500
+
+func (r *taskRepository) Stream(ctx context.Context, filter repository.TaskFilter, fn func(task *models.Task) error) error {
+	whereClause, params, paramCount := taskConditions(ctx, filter)
+
+	// Cursors live in a transaction; read-only keeps a long export from holding locks
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		DECLARE task_stream NO SCROLL CURSOR FOR
+		SELECT `+taskColumns+`
+		FROM tasks`+whereClause+`
+		ORDER BY `+orderBy(filter)+`, id
+		OFFSET `+fmt.Sprintf("$%d", paramCount), append(params, filter.Offset)...)
+	if err != nil {
+		return err
+	}
+
+	for {
+		tasks, err := fetchTasks(ctx, tx, fmt.Sprintf("FETCH %d FROM task_stream", streamBatch))
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if err := fn(task); err != nil {
+				return err
+			}
+		}
+		if len(tasks) < streamBatch {
+			return tx.Commit()
+		}
+	}
+}
+
+// priorityRank orders priorities from low (1) to critical (4)
+const priorityRank = `CASE priority WHEN 'critical' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END`
+
+// orderBy builds the ORDER BY clause for a validated filter, newest first on ties.
+// Only the fixed expressions below reach the query, never the filter's own strings.
+func orderBy(filter repository.TaskFilter) string {
+	column, order := "created_at", "DESC"
+	switch filter.Sort {
+	case repository.SortUpdatedAt:
+		column = "updated_at"
+	case repository.SortDueDate:
+		column, order = "due_date", "ASC"
+	case repository.SortPriority:
+		column = priorityRank
+	case repository.SortTitle:
+		column, order = "LOWER(title)", "ASC"
+	case repository.SortPosition:
+		column, order = "position", "ASC"
+	}
+	switch filter.Order {
+	case "asc":
+		order = "ASC"
+	case "desc":
+		order = "DESC"
+	}
+
+	if column == "created_at" {
+		return "created_at " + order
+	}
+	return column + " " + order + ", created_at DESC"
+}
+
+func (r *taskRepository) ListSubtasks(ctx context.Context, parentID string, recursive bool) ([]*models.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE parent_id = $1 AND ` + tenantMatch("", 2) + `
+		ORDER BY created_at`
+	if recursive {
+		// UNION (not UNION ALL) stops at rows already visited, so corrupt cyclic data can't loop forever
+		query = `
+		WITH RECURSIVE subtasks AS (
+			SELECT ` + taskColumns + ` FROM tasks WHERE parent_id = $1 AND ` + tenantMatch("", 2) + `
+			UNION
+			SELECT ` + qualifiedTaskColumns("t") + `
+			FROM tasks t
+			JOIN subtasks s ON t.parent_id = s.id
+			WHERE ` + tenantMatch("t.", 2) + `
+		)
+		SELECT ` + taskColumns + `
+		FROM subtasks
+		ORDER BY created_at`
+	}
+
+	return r.queryTasks(ctx, query, parentID, tenant.Arg(ctx))
+}
+
+func (r *taskRepository) ListAncestorIDs(ctx context.Context, id string) ([]string, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_id FROM tasks WHERE id = $1 AND parent_id IS NOT NULL AND ` + tenantMatch("", 2) + `
+			UNION
+			SELECT t.parent_id
+			FROM tasks t
+			JOIN ancestors a ON t.id = a.parent_id
+			WHERE t.parent_id IS NOT NULL AND ` + tenantMatch("t.", 2) + `
+		)
+		SELECT parent_id FROM ancestors`
+
+	rows, err := r.db.QueryContext(ctx, query, id, tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var ancestorID string
+		if err := rows.Scan(&ancestorID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, ancestorID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *taskRepository) UpdateSubtaskStatus(ctx context.Context, parentID string, status models.TaskStatus) ([]*models.Task, error) {
+	query := `
+		WITH RECURSIVE subtasks AS (
+			SELECT id FROM tasks WHERE parent_id = $1 AND ` + tenantMatch("", 4) + `
+			UNION
+			SELECT t.id
+			FROM tasks t
+			JOIN subtasks s ON t.parent_id = s.id
+			WHERE ` + tenantMatch("t.", 4) + `
+		)
+		UPDATE tasks
+		SET status = $2,
+			updated_at = $3
+		WHERE id IN (SELECT id FROM subtasks) AND status <> 'draft'
+		RETURNING ` + taskColumns
+
+	return r.queryTasks(ctx, query, parentID, status, time.Now(), tenant.Arg(ctx))
+}
+
+// queryTasks runs a query selecting taskColumns and scans every row
+func (r *taskRepository) queryTasks(ctx context.Context, query string, args ...interface{}) ([]*models.Task, error) {
+	return fetchTasks(ctx, r.db, query, args...)
+}
+
+// fetchTasks runs a query selecting taskColumns on q, scans every row and loads the
+// tasks' tags
+func fetchTasks(ctx context.Context, q querier, query string, args ...interface{}) ([]*models.Task, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadTags(ctx, q, tasks...); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (r *taskRepository) ListTags(ctx context.Context) ([]models.TagCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT tag, COUNT(*)
+		FROM task_tags
+		WHERE task_id IN (SELECT id FROM tasks WHERE status <> 'draft' AND `+tenantMatch("", 1)+`)
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag`, tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.TagCount{}
+	for rows.Next() {
+		var tag models.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// querier is implemented by *sql.DB and *sql.Tx
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// encodeMetadata returns metadata as a JSON object for the metadata column
+func encodeMetadata(metadata map[string]interface{}) (string, error) {
+	if metadata == nil {
+		return "{}", nil
+	}
+	encoded, err := json.Marshal(metadata)
+	return string(encoded), err
+}
+
+// replaceTags sets the tags of a task, removing any it had before
+func replaceTags(ctx context.Context, q querier, taskID string, tags []string) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM task_tags WHERE task_id = $1`, taskID); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := q.ExecContext(ctx,
+		`INSERT INTO task_tags (task_id, tag) SELECT $1, unnest($2::text[])`,
+		taskID, pq.Array(tags))
+	return err
+}
+
+// loadTags fills in the tags of tasks with a single query
+func loadTags(ctx context.Context, q querier, tasks ...*models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.Task, len(tasks))
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+		ids = append(ids, task.ID)
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT task_id, tag FROM task_tags WHERE task_id = ANY($1) ORDER BY tag`,
+		pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, tag string
+		if err := rows.Scan(&taskID, &tag); err != nil {
+			return err
+		}
+		if task, ok := byID[taskID]; ok {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+	return rows.Err()
+}
+
@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// keyMatch matches the task whose key equals the search text
+const keyMatch = "project_key || '-' || number = upper(trim($1))"
+
+// textMatch matches tasks against the search text, parsed with language or, when empty,
+// with each task's own search language. The per-language alternatives keep the GIN index
+// on search_vector usable, which a query parsed from the search_language column would not.
+func textMatch(language models.SearchLanguage) string {
+	if language != "" {
+		return fmt.Sprintf("search_vector @@ websearch_to_tsquery('%s', $1)", language)
+	}
+	alternatives := make([]string, len(models.SearchLanguages))
+	for i, l := range models.SearchLanguages {
+		alternatives[i] = fmt.Sprintf("(search_language = '%s' AND search_vector @@ websearch_to_tsquery('%s', $1))", l, l)
+	}
+	return strings.Join(alternatives, " OR ")
+}
+
+// textRank ranks tasks matched by textMatch(language)
+func textRank(language models.SearchLanguage) string {
+	if language != "" {
+		return fmt.Sprintf("ts_rank(search_vector, websearch_to_tsquery('%s', $1))", language)
+	}
+	return "ts_rank(search_vector, websearch_to_tsquery(search_language, $1))"
+}
+
+type taskSearcher struct {
+	db *sql.DB
+}
+
+// NewTaskSearcher creates a task searcher backed by PostgreSQL full-text search
+func NewTaskSearcher(db *sql.DB) repository.TaskSearcher {
+	return &taskSearcher{db: db}
+}
+
+func (s *taskSearcher) Search(ctx context.Context, query repository.TaskSearchQuery) (*repository.TaskSearchResult, error) {
+	// Only supported languages are inlined in the query
+	if query.Language != "" && !query.Language.IsValid() {
+		return nil, fmt.Errorf("unsupported search language %q", query.Language)
+	}
+
+	// A query that is a task key such as PROJ-123 also finds that task
+	matchClause := " WHERE (" + textMatch(query.Language) + " OR " + keyMatch + ") AND status <> 'draft' AND " + tenantMatch("", 2)
+	params := []interface{}{query.Text, tenant.Arg(ctx)}
+	paramCount := 3
+	if query.ProjectKey != "" {
+		matchClause += fmt.Sprintf(" AND project_key = $%d", paramCount)
+		params = append(params, query.ProjectKey)
+		paramCount++
+	}
+	matchParams := params
+
+	whereClause := matchClause
+	if query.Status != "" {
+		whereClause += fmt.Sprintf(" AND status = $%d", paramCount)
+		params = append(params, query.Status)
+		paramCount++
+	}
+
+	var total int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks`+whereClause, params...).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	// Facets ignore the status filter so clients can show counts for every status
+	facets, err := s.statusFacets(ctx, matchClause, matchParams...)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlQuery := `
+		SELECT ` + taskColumns + `
+		FROM tasks` + whereClause
+
+	sqlQuery += fmt.Sprintf(
+		" ORDER BY "+keyMatch+" DESC, "+textRank(query.Language)+" DESC, created_at DESC LIMIT $%d OFFSET $%d",
+		paramCount, paramCount+1)
+	params = append(params, query.Limit, (query.Page-1)*query.Limit)
+
+	rows, err := s.db.QueryContext(ctx, sqlQuery, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := &repository.TaskSearchResult{Total: total, Facets: facets}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		result.Tasks = append(result.Tasks, task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadTags(ctx, s.db, result.Tasks...); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *taskSearcher) Related(ctx context.Context, id string, limit int) ([]*models.Task, error) {
+	// Score by title trigram similarity plus a weaker description similarity, and a bonus
+	// per tag shared with the source task (capped, so tags alone don't outrank the text)
+	query := `
+		WITH shared_tags AS (
+			SELECT other.task_id, COUNT(*) AS shared
+			FROM task_tags own
+			JOIN task_tags other ON other.tag = own.tag AND other.task_id <> own.task_id
+			WHERE own.task_id = $1
+			GROUP BY other.task_id
+		)
+		SELECT ` + qualifiedTaskColumns("t") + `
+		FROM tasks t
+		JOIN tasks src ON src.id = $1
+		LEFT JOIN shared_tags st ON st.task_id = t.id
+		WHERE t.id <> src.id AND t.status <> 'draft'
+			AND ` + tenantMatch("src.", 3) + ` AND ` + tenantMatch("t.", 3) + `
+			AND (t.title % src.title OR coalesce(t.description, '') % coalesce(src.description, '')
+				OR st.shared IS NOT NULL)
+		ORDER BY similarity(t.title, src.title) +
+			0.5 * similarity(coalesce(t.description, ''), coalesce(src.description, '')) +
+			0.25 * LEAST(coalesce(st.shared, 0), 4) DESC,
+			t.created_at DESC
+		LIMIT $2`
+
+	rows, err := s.db.QueryContext(ctx, query, id, limit, tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadTags(ctx, s.db, tasks...); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// statusFacets counts matching tasks per status
+func (s *taskSearcher) statusFacets(ctx context.Context, matchClause string, params ...interface{}) (map[string]map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM tasks`+matchClause+` GROUP BY status`, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return map[string]map[string]int{"status": counts}, nil
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// Slugs are assigned while the project's counter row is locked (see nextTaskNumber and
+// lockProject), so two tasks of a project never claim the same free slug concurrently.
+
+// lockProject locks the counter row of a project until tx ends
+func lockProject(ctx context.Context, tx *sql.Tx, projectKey string) error {
+	_, err := tx.ExecContext(ctx,
+		`SELECT 1 FROM project_task_counters WHERE project_key = $1 FOR UPDATE`, projectKey)
+	return err
+}
+
+// uniqueSlug returns base, or base with the lowest numeric suffix that no other task of
+// the project uses now or used before a rename. Slugs are unique within the tenant orgID,
+// so other tenants' slugs neither clash nor show.
+func uniqueSlug(ctx context.Context, q querier, orgID, projectKey, base, taskID string) (string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT slug FROM tasks
+		WHERE project_key = $1 AND (slug = $2 OR slug LIKE $2 || '-%') AND id <> $3
+			AND COALESCE(org_id, '') = $4
+		UNION
+		SELECT slug FROM task_slug_history
+		WHERE project_key = $1 AND (slug = $2 OR slug LIKE $2 || '-%') AND task_id <> $3
+			AND COALESCE(org_id, '') = $4`,
+		projectKey, base, taskID, orgID)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return "", err
+		}
+		taken[slug] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	slug := base
+	for n := 2; taken[slug]; n++ {
+		slug = base + "-" + strconv.Itoa(n)
+	}
+	return slug, nil
+}
+
+// renameSlug gives a task the slug for its new title, keeping the previous slug in the
+// history. The slug is unchanged if the new title yields the same one.
+func renameSlug(ctx context.Context, tx *sql.Tx, id, title string) (string, error) {
+	var orgID, projectKey, current string
+	err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(org_id, ''), project_key, slug FROM tasks WHERE id = $1 AND `+tenantMatch("", 2),
+		id, tenant.Arg(ctx)).Scan(&orgID, &projectKey, &current)
+	if err == sql.ErrNoRows {
+		return "", errors.New("task not found")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := lockProject(ctx, tx, projectKey); err != nil {
+		return "", err
+	}
+	slug, err := uniqueSlug(ctx, tx, orgID, projectKey, models.Slugify(title), id)
+	if err != nil || slug == current {
+		return slug, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO task_slug_history (project_key, slug, task_id, org_id)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		ON CONFLICT ((COALESCE(org_id, '')), project_key, slug) DO NOTHING`, projectKey, current, id, orgID)
+	if err != nil {
+		return "", err
+	}
+	// A task renamed back to an earlier title reclaims that slug
+	_, err = tx.ExecContext(ctx,
+		`DELETE FROM task_slug_history WHERE project_key = $1 AND slug = $2 AND COALESCE(org_id, '') = $3`,
+		projectKey, slug, orgID)
+	if err != nil {
+		return "", err
+	}
+	return slug, nil
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// accountColumns lists the columns read by scanAccount, in order
+const accountColumns = "id, email, roles, created_at, updated_at"
+
+// scanAccount reads an account selected with accountColumns
+func scanAccount(row rowScanner, extra ...interface{}) (*models.Account, error) {
+	account := &models.Account{}
+	dest := append([]interface{}{
+		&account.ID,
+		&account.Email,
+		pq.Array(&account.Roles),
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+type userRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new PostgreSQL account repository
+func NewUserRepository(db *sql.DB) repository.UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) Create(ctx context.Context, account *models.Account, passwordHash string) (*models.Account, error) {
+	now := time.Now()
+	created, err := scanAccount(r.db.QueryRowContext(ctx, `
+		INSERT INTO users (id, email, password_hash, roles, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT ((lower(email))) DO NOTHING
+		RETURNING `+accountColumns,
+		uuid.New().String(), account.Email, passwordHash, pq.Array(account.Roles), now))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrEmailTaken
+	}
+	return created, err
+}
+
+func (r *userRepository) GetRoles(ctx context.Context, userID string) ([]string, error) {
+	var roles []string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT roles FROM users WHERE id = $1
+		UNION ALL
+		SELECT roles FROM oidc_identities WHERE user_id = $1
+		LIMIT 1`, userID).Scan(pq.Array(&roles))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.Account, string, error) {
+	var passwordHash string
+	account, err := scanAccount(r.db.QueryRowContext(ctx, `
+		SELECT `+accountColumns+`, password_hash
+		FROM users
+		WHERE lower(email) = lower($1)`, email), &passwordHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", models.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return account, passwordHash, nil
+}
+
+func (r *userRepository) CreatePasswordReset(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO password_resets (token_hash, user_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)`,
+		tokenHash, userID, time.Now(), expiresAt)
+	return err
+}
+
+func (r *userRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string, now time.Time) (*models.Account, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// A single statement, so a token is never used twice
+	var userID string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE password_resets
+		SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING user_id`,
+		tokenHash, now).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrPasswordResetInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Other links sent before the reset stop working with it
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE password_resets SET used_at = $2
+		WHERE user_id = $1 AND used_at IS NULL`, userID, now); err != nil {
+		return nil, err
+	}
+
+	account, err := scanAccount(tx.QueryRowContext(ctx, `
+		UPDATE users SET password_hash = $2, updated_at = $3
+		WHERE id = $1
+		RETURNING `+accountColumns,
+		userID, passwordHash, now))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
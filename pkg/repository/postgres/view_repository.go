@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type viewRepository struct {
+	db *sql.DB
+}
+
+// NewViewRepository creates a new PostgreSQL task view repository
+func NewViewRepository(db *sql.DB) repository.ViewRepository {
+	return &viewRepository{db: db}
+}
+
+func (r *viewRepository) SaveViews(ctx context.Context, userID string, views []models.TaskView, keep int) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]string, len(views))
+	viewedAt := make([]time.Time, len(views))
+	for i, view := range views {
+		taskIDs[i] = view.TaskID
+		viewedAt[i] = view.ViewedAt
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Joining tasks skips views of deleted tasks instead of failing on the foreign key
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO task_views (user_id, task_id, viewed_at)
+		SELECT $1, v.task_id, v.viewed_at
+		FROM unnest($2::text[], $3::timestamp[]) AS v(task_id, viewed_at)
+		JOIN tasks ON tasks.id = v.task_id
+		ON CONFLICT (user_id, task_id) DO UPDATE
+		SET viewed_at = GREATEST(task_views.viewed_at, EXCLUDED.viewed_at)`,
+		userID, pq.Array(taskIDs), pq.Array(viewedAt))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM task_views
+		WHERE user_id = $1 AND task_id NOT IN (
+			SELECT task_id FROM task_views WHERE user_id = $1 ORDER BY viewed_at DESC LIMIT $2
+		)`, userID, keep)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *viewRepository) ListViews(ctx context.Context, userID string, limit int) ([]models.TaskView, error) {
+	query := `
+		SELECT task_id, viewed_at
+		FROM task_views
+		WHERE user_id = $1
+		ORDER BY viewed_at DESC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var views []models.TaskView
+	for rows.Next() {
+		var view models.TaskView
+		if err := rows.Scan(&view.TaskID, &view.ViewedAt); err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+
+	return views, rows.Err()
+}
@@ -0,0 +1,239 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// webhookColumns lists the columns read by scanWebhook, in order
+const webhookColumns = "id, url, events, secret, created_by, created_at"
+
+// deliveryColumns lists the columns read by scanDelivery, in order
+const deliveryColumns = "id, webhook_id, event_id, event_type, success, status_code, duration_ms, response, error, redelivery, event, delivered_at"
+
+type webhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a new PostgreSQL webhook subscription repository
+func NewWebhookRepository(db *sql.DB) repository.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// scanWebhook reads a subscription selected with webhookColumns
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	hook := &models.Webhook{}
+	err := row.Scan(
+		&hook.ID,
+		&hook.URL,
+		pq.Array(&hook.Events),
+		&hook.Secret,
+		&hook.CreatedBy,
+		&hook.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (r *webhookRepository) Create(ctx context.Context, hook *models.Webhook) (*models.Webhook, error) {
+	query := `
+		INSERT INTO webhooks (id, url, events, secret, created_by, created_at, org_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+		RETURNING ` + webhookColumns
+
+	return scanWebhook(r.db.QueryRowContext(
+		ctx,
+		query,
+		uuid.New().String(),
+		hook.URL,
+		pq.Array(hook.Events),
+		hook.Secret,
+		hook.CreatedBy,
+		time.Now(),
+		tenantOrgID(ctx, ""),
+	))
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id string) (*models.Webhook, error) {
+	hook, err := scanWebhook(r.db.QueryRowContext(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE id = $1 AND `+tenantMatch("", 2), id, tenant.Arg(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("webhook not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (r *webhookRepository) List(ctx context.Context) ([]*models.Webhook, error) {
+	return r.list(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE `+tenantMatch("", 1)+` ORDER BY created_at DESC`, tenant.Arg(ctx))
+}
+
+func (r *webhookRepository) ListForEvent(ctx context.Context, eventType string) ([]*models.Webhook, error) {
+	return r.list(ctx,
+		`SELECT `+webhookColumns+` FROM webhooks WHERE events @> ARRAY[$1]::text[] AND `+tenantMatch("", 2),
+		eventType, tenant.Arg(ctx))
+}
+
+func (r *webhookRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hooks := []*models.Webhook{}
+	for rows.Next() {
+		hook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, hook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM webhooks WHERE id = $1 AND `+tenantMatch("", 2), id, tenant.Arg(ctx))
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("webhook not found")
+	}
+	return nil
+}
+
+// scanDelivery reads a delivery log selected with deliveryColumns
+func scanDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	var statusCode sql.NullInt64
+	var response, errorMessage sql.NullString
+	var event []byte
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&delivery.Success,
+		&statusCode,
+		&delivery.DurationMs,
+		&response,
+		&errorMessage,
+		&delivery.Redelivery,
+		&event,
+		&delivery.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	delivery.StatusCode = int(statusCode.Int64)
+	delivery.Response = response.String
+	delivery.Error = errorMessage.String
+	if err := json.Unmarshal(event, &delivery.Event); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (r *webhookRepository) RecordDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	event, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return err
+	}
+
+	delivery.ID = uuid.New().String()
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO webhook_deliveries (`+deliveryColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		delivery.ID,
+		delivery.WebhookID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Success,
+		sql.NullInt64{Int64: int64(delivery.StatusCode), Valid: delivery.StatusCode != 0},
+		delivery.DurationMs,
+		sql.NullString{String: delivery.Response, Valid: delivery.Response != ""},
+		sql.NullString{String: delivery.Error, Valid: delivery.Error != ""},
+		delivery.Redelivery,
+		string(event),
+		delivery.DeliveredAt,
+	)
+	return err
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, webhookID string, page, limit int) ([]*models.WebhookDelivery, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1`, webhookID).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY delivered_at DESC
+		LIMIT $2 OFFSET $3`,
+		webhookID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, webhookID, id string) (*models.WebhookDelivery, error) {
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE webhook_id = $1 AND id = $2`,
+		webhookID, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("delivery not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (r *webhookRepository) DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE delivered_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// PreferencesRepository defines the interface for notification preferences data access
+type PreferencesRepository interface {
+	// Get retrieves a user's preferences; users who never saved any get the defaults
+	Get(ctx context.Context, userID string) (*models.NotificationPreferences, error)
+
+	// Save creates or replaces a user's preferences
+	Save(ctx context.Context, preferences *models.NotificationPreferences) (*models.NotificationPreferences, error)
+
+	// ListDigestRecipients retrieves the preferences of every user receiving a digest
+	ListDigestRecipients(ctx context.Context) ([]*models.NotificationPreferences, error)
+
+	// ClaimDigest records that the user's digest is sent at, unless one was already sent
+	// at or after since. It reports whether the caller should send the digest, so only
+	// one instance sends it.
+	ClaimDigest(ctx context.Context, userID string, since, at time.Time) (bool, error)
+}
+
+// DigestRepository defines the interface for the task queries behind deadline digests
+type DigestRepository interface {
+	// DigestTasks retrieves the user's open tasks due before dueBefore, those overdue at
+	// now, and tasks of others assigned to them after assignedSince
+	DigestTasks(ctx context.Context, userID string, now, dueBefore, assignedSince time.Time) (*models.Digest, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// ProgressRepository defines the interface for the task status history behind charts
+type ProgressRepository interface {
+	// RecordStatus stores a task's status at the given time, unless it is unchanged
+	RecordStatus(ctx context.Context, task *models.Task, at time.Time) error
+
+	// RecordDeleted stores that a task was deleted; tasks without history are ignored
+	RecordDeleted(ctx context.Context, taskID string, at time.Time) error
+
+	// DailyCounts counts the project's open and completed tasks at the end of every day
+	// from from to to, inclusive. Days before the project had tasks may be missing.
+	DailyCounts(ctx context.Context, projectKey string, from, to time.Time) ([]*models.ProgressCount, error)
+}
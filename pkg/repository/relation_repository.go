@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// RelationRepository defines the interface for typed links between tasks
+type RelationRepository interface {
+	// Add records that a task relates to another; adding an existing relation is a no-op
+	Add(ctx context.Context, taskID, relatedID string, relationType models.RelationType) error
+
+	// Remove deletes a relation
+	Remove(ctx context.Context, taskID, relatedID string, relationType models.RelationType) error
+
+	// List retrieves a task's relations in both directions, oldest first. Relations
+	// pointing at the task are reported with their inverse type.
+	List(ctx context.Context, taskID string) ([]*models.TaskRelation, error)
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// ReportRepository defines the interface for saved report data access
+type ReportRepository interface {
+	// Create creates a new report
+	Create(ctx context.Context, report *models.Report) (*models.Report, error)
+
+	// GetByID retrieves a report by its ID
+	GetByID(ctx context.Context, id string) (*models.Report, error)
+
+	// List retrieves all reports, by name
+	List(ctx context.Context) ([]*models.Report, error)
+
+	// Update replaces the name and definition of a report
+	Update(ctx context.Context, id string, input *models.ReportInput) (*models.Report, error)
+
+	// Delete removes a report by its ID
+	Delete(ctx context.Context, id string) error
+
+	// Run computes the rows of a definition over tasks whose date field is within
+	// [from, to), returning at most definition.RowLimit rows. Drafts are left out.
+	// The query is cancelled when ctx is done.
+	Run(ctx context.Context, definition *models.ReportDefinition, from, to *time.Time) (rows []models.ReportRow, truncated bool, err error)
+}
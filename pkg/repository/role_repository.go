@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// RoleRepository defines the interface for stored role definitions, which replace the
+// built-in roles of the same name
+type RoleRepository interface {
+	// List retrieves every stored role by name
+	List(ctx context.Context) ([]*models.RoleDefinition, error)
+
+	// Save creates or replaces a role's permissions
+	Save(ctx context.Context, role *models.RoleDefinition) (*models.RoleDefinition, error)
+
+	// Delete removes a stored role, returning models.ErrRoleNotFound when none is stored
+	Delete(ctx context.Context, name string) error
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// TaskSearchQuery represents a full-text search over tasks
+type TaskSearchQuery struct {
+	Text   string
+	Status models.TaskStatus
+	// Language parses the text with one search language for every task; empty matches
+	// each task in its project's language. Only the PostgreSQL backend uses it.
+	Language models.SearchLanguage
+	// ProjectKey limits the results and facets to one project when set
+	ProjectKey string
+	Page       int
+	Limit      int
+}
+
+// TaskSearchResult holds the matching tasks and facet counts
+type TaskSearchResult struct {
+	Tasks  []*models.Task            `json:"tasks"`
+	Total  int                       `json:"total"`
+	Facets map[string]map[string]int `json:"facets"`
+}
+
+// TaskSearcher defines the interface for task search backends
+type TaskSearcher interface {
+	// Search returns tasks matching the query along with facet counts
+	Search(ctx context.Context, query TaskSearchQuery) (*TaskSearchResult, error)
+
+	// Related returns up to limit tasks similar to the task with the given ID
+	Related(ctx context.Context, id string, limit int) ([]*models.Task, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// ShareRepository defines the interface for task share link data access
+type ShareRepository interface {
+	// Create stores a new share link
+	Create(ctx context.Context, share *models.TaskShare) (*models.TaskShare, error)
+
+	// GetByID retrieves a share link, including revoked and expired ones
+	GetByID(ctx context.Context, id string) (*models.TaskShare, error)
+
+	// ListByTask retrieves all share links for a task
+	ListByTask(ctx context.Context, taskID string) ([]*models.TaskShare, error)
+
+	// Revoke disables a share link of the given task
+	Revoke(ctx context.Context, taskID, id string) error
+}
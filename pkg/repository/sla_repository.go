@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// SLARepository defines the interface for task SLA policies and breach detection
+type SLARepository interface {
+	// SetPolicy sets a task's SLA windows, nil for a target the task doesn't have, and
+	// clears its breach flags so a changed policy is reported anew
+	SetPolicy(ctx context.Context, taskID string, respondWithin, resolveWithin *time.Duration) (*models.Task, error)
+
+	// MarkBreached flags the tasks that missed their kind target before now, whether they
+	// are still waiting or met it late, and returns the newly flagged ones
+	MarkBreached(ctx context.Context, kind models.SLAKind, now time.Time) ([]*models.Task, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// SnoozeRepository defines the interface for snoozing tasks
+type SnoozeRepository interface {
+	// Snooze hides an open task from default lists until the given time, or wakes it when
+	// until is nil, and returns the task
+	Snooze(ctx context.Context, id string, until *time.Time) (*models.Task, error)
+}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// apiKeyColumns lists the columns read by scanAPIKey, in order
+const apiKeyColumns = "id, user_id, org_id, name, prefix, roles, scopes, created_at, expires_at, last_used_at, revoked_at"
+
+// scanAPIKey reads a key selected with apiKeyColumns
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	var orgID sql.NullString
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&orgID,
+		&key.Name,
+		&key.Prefix,
+		(*stringList)(&key.Roles),
+		(*stringList)(&key.Scopes),
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	key.OrgID = orgID.String
+	return key, nil
+}
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates a new SQLite API key repository
+func NewAPIKeyRepository(db *sql.DB) repository.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey, keyHash string) (*models.APIKey, error) {
+	return scanAPIKey(r.db.QueryRowContext(ctx, `
+		INSERT INTO api_keys (id, user_id, org_id, name, prefix, key_hash, roles, scopes, created_at, expires_at)
+		VALUES (?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?, ?)
+		RETURNING `+apiKeyColumns,
+		uuid.New().String(), key.UserID, key.OrgID, key.Name, key.Prefix, keyHash,
+		stringList(key.Roles), stringList(key.Scopes), time.Now().UTC(), utc(key.ExpiresAt)))
+}
+
+func (r *apiKeyRepository) ListByUser(ctx context.Context, userID string) ([]*models.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE user_id = ?
+		ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*models.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *apiKeyRepository) GetActiveByHash(ctx context.Context, keyHash string, now time.Time) (*models.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE key_hash = ?1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?2)`,
+		keyHash, now.UTC()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrAPIKeyNotFound
+	}
+	return key, err
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, userID, id string, now time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE api_keys SET revoked_at = ?3 WHERE id = ?1 AND user_id = ?2 AND revoked_at IS NULL`,
+		id, userID, now.UTC())
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) MarkUsed(ctx context.Context, id string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE api_keys SET last_used_at = ?2 WHERE id = ?1 AND (last_used_at IS NULL OR last_used_at < ?2)`,
+		id, at.UTC())
+	return err
+}
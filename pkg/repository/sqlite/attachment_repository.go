@@ -0,0 +1,130 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+type attachmentRepository struct {
+	db *sql.DB
+}
+
+// NewAttachmentRepository creates a new SQLite task attachment repository
+func NewAttachmentRepository(db *sql.DB) repository.AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+const attachmentColumns = `id, task_id, file_name, content_type, size_bytes, storage_key, uploaded_by, created_at`
+
+func scanAttachment(row rowScanner) (*models.TaskAttachment, error) {
+	attachment := &models.TaskAttachment{}
+	err := row.Scan(
+		&attachment.ID,
+		&attachment.TaskID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.StorageKey,
+		&attachment.UploadedBy,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// Create stores the attachment under its ID, since the storage key is derived from it
+func (r *attachmentRepository) Create(ctx context.Context, attachment *models.TaskAttachment) (*models.TaskAttachment, error) {
+	query := `
+		INSERT INTO task_attachments (` + attachmentColumns + `)
+		SELECT ?1, id, ?3, ?4, ?5, ?6, ?7, ?8
+		FROM tasks
+		WHERE id = ?2 AND ` + tenantMatch("", 9) + `
+		RETURNING ` + attachmentColumns
+
+	created, err := scanAttachment(r.db.QueryRowContext(
+		ctx,
+		query,
+		attachment.ID,
+		attachment.TaskID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.StorageKey,
+		attachment.UploadedBy,
+		time.Now().UTC(),
+		tenant.Arg(ctx),
+	))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTaskNotFound
+	}
+	return created, err
+}
+
+func (r *attachmentRepository) GetByID(ctx context.Context, taskID, id string) (*models.TaskAttachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM task_attachments WHERE id = ?1 AND task_id = ?2 AND ` + taskInTenant("", 3)
+
+	attachment, err := scanAttachment(r.db.QueryRowContext(ctx, query, id, taskID, tenant.Arg(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("attachment not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (r *attachmentRepository) ListByTask(ctx context.Context, taskID string) ([]*models.TaskAttachment, error) {
+	query := `
+		SELECT ` + attachmentColumns + `
+		FROM task_attachments
+		WHERE task_id = ?1 AND ` + taskInTenant("", 2) + `
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID, tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*models.TaskAttachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, taskID, id string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM task_attachments WHERE id = ?1 AND task_id = ?2 AND `+taskInTenant("", 3), id, taskID, tenant.Arg(ctx))
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("attachment not found")
+	}
+
+	return nil
+}
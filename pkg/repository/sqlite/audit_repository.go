@@ -0,0 +1,139 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new SQLite audit repository
+func NewAuditRepository(db *sql.DB) repository.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, event *models.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (id, action, actor_id, subject_id, impersonated, method, path, status_code, remote_addr, metadata, created_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11)`
+
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return err
+	}
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		event.ID,
+		event.Action,
+		event.ActorID,
+		event.SubjectID,
+		event.Impersonated,
+		event.Method,
+		event.Path,
+		event.StatusCode,
+		event.RemoteAddr,
+		string(metadata),
+		event.CreatedAt.UTC(),
+	)
+	return err
+}
+
+func (r *auditRepository) List(ctx context.Context, filter repository.AuditFilter) ([]*models.AuditEvent, int, error) {
+	var conditions []string
+	var params []interface{}
+	paramCount := 1
+
+	addCondition := func(column, value string) {
+		if value == "" {
+			return
+		}
+		conditions = append(conditions, fmt.Sprintf("%s = ?%d", column, paramCount))
+		params = append(params, value)
+		paramCount++
+	}
+	addCondition("actor_id", filter.ActorID)
+	addCondition("subject_id", filter.SubjectID)
+	addCondition("action", filter.Action)
+	if filter.ActionPrefix != "" {
+		conditions = append(conditions, fmt.Sprintf("action LIKE ?%d ESCAPE '\\'", paramCount))
+		params = append(params, escapeLike(filter.ActionPrefix)+"%")
+		paramCount++
+	}
+
+	whereClause := ""
+	for i, condition := range conditions {
+		if i == 0 {
+			whereClause = " WHERE " + condition
+		} else {
+			whereClause += " AND " + condition
+		}
+	}
+
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM audit_events`+whereClause, params...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, action, actor_id, subject_id, impersonated, method, path, status_code, remote_addr, metadata, created_at
+		FROM audit_events` + whereClause + fmt.Sprintf(" ORDER BY created_at DESC LIMIT ?%d OFFSET ?%d", paramCount, paramCount+1)
+	params = append(params, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var events []*models.AuditEvent
+	for rows.Next() {
+		event := &models.AuditEvent{}
+		var method, path, remoteAddr sql.NullString
+		var statusCode sql.NullInt64
+		var metadata []byte
+		err := rows.Scan(
+			&event.ID,
+			&event.Action,
+			&event.ActorID,
+			&event.SubjectID,
+			&event.Impersonated,
+			&method,
+			&path,
+			&statusCode,
+			&remoteAddr,
+			&metadata,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		event.Method = method.String
+		event.Path = path.String
+		event.StatusCode = int(statusCode.Int64)
+		event.RemoteAddr = remoteAddr.String
+		if err := json.Unmarshal(metadata, &event.Metadata); err != nil {
+			return nil, 0, err
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
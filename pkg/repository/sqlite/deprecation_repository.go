@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// deprecationUsageColumns lists the columns read by scanDeprecationUsage, in order
+const deprecationUsageColumns = "deprecation_id, client_kind, client, user_id, org_id, requests, first_seen_at, last_seen_at, notified_at"
+
+// scanDeprecationUsage reads a usage row selected with deprecationUsageColumns
+func scanDeprecationUsage(row rowScanner) (*models.DeprecationUsage, error) {
+	usage := &models.DeprecationUsage{}
+	var orgID sql.NullString
+	err := row.Scan(
+		&usage.DeprecationID,
+		&usage.ClientKind,
+		&usage.Client,
+		&usage.UserID,
+		&orgID,
+		&usage.Requests,
+		&usage.FirstSeenAt,
+		&usage.LastSeenAt,
+		&usage.NotifiedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	usage.OrgID = orgID.String
+	return usage, nil
+}
+
+type deprecationRepository struct {
+	db *sql.DB
+}
+
+// NewDeprecationRepository creates a new SQLite deprecation usage repository
+func NewDeprecationRepository(db *sql.DB) repository.DeprecationRepository {
+	return &deprecationRepository{db: db}
+}
+
+func (r *deprecationRepository) RecordUsage(ctx context.Context, usage []*models.DeprecationUsage) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO deprecation_usage (deprecation_id, client_kind, client, user_id, org_id, requests, first_seen_at, last_seen_at)
+		VALUES (?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?)
+		ON CONFLICT (deprecation_id, client_kind, client, user_id) DO UPDATE SET
+			org_id = COALESCE(excluded.org_id, org_id),
+			requests = requests + excluded.requests,
+			first_seen_at = MIN(first_seen_at, excluded.first_seen_at),
+			last_seen_at = MAX(last_seen_at, excluded.last_seen_at)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, u := range usage {
+		_, err := stmt.ExecContext(ctx,
+			u.DeprecationID,
+			u.ClientKind,
+			u.Client,
+			u.UserID,
+			u.OrgID,
+			u.Requests,
+			u.FirstSeenAt.UTC(),
+			u.LastSeenAt.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *deprecationRepository) ListUsage(ctx context.Context) ([]*models.DeprecationUsage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+deprecationUsageColumns+`
+		FROM deprecation_usage
+		ORDER BY requests DESC, last_seen_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := []*models.DeprecationUsage{}
+	for rows.Next() {
+		u, err := scanDeprecationUsage(rows)
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+func (r *deprecationRepository) MarkNotified(ctx context.Context, usage *models.DeprecationUsage, at time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE deprecation_usage SET notified_at = ?5
+		WHERE deprecation_id = ?1 AND client_kind = ?2 AND client = ?3 AND user_id = ?4 AND notified_at IS NULL`,
+		usage.DeprecationID, usage.ClientKind, usage.Client, usage.UserID, at.UTC())
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
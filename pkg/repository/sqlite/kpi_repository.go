@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type kpiRepository struct {
+	db *sql.DB
+}
+
+// NewKPIRepository creates a new SQLite business metrics repository
+func NewKPIRepository(db *sql.DB) repository.KPIRepository {
+	return &kpiRepository{db: db}
+}
+
+func (r *kpiRepository) OrgKPIs(ctx context.Context, since, until time.Time) ([]*models.OrgKPIs, error) {
+	// Completions come from the status history, so a task reopened and completed again
+	// counts twice; its cycle starts at its first move to in_progress before completion
+	rows, err := r.db.QueryContext(ctx, `
+		WITH created AS (
+			SELECT COALESCE(org_id, '') AS org_id, COUNT(*) AS tasks
+			FROM tasks
+			WHERE status <> 'draft' AND created_at >= ?1 AND created_at < ?2
+			GROUP BY 1
+		), completed AS (
+			SELECT COALESCE(t.org_id, '') AS org_id, COUNT(*) AS tasks,
+				AVG(unixepoch(h.changed_at, 'subsec') - unixepoch(COALESCE(
+					(SELECT MIN(s.changed_at) FROM task_status_history s
+					WHERE s.task_id = h.task_id AND s.status = 'in_progress' AND s.changed_at <= h.changed_at),
+					t.created_at), 'subsec')) AS cycle_seconds
+			FROM task_status_history h
+			JOIN tasks t ON t.id = h.task_id
+			WHERE h.status = 'completed' AND h.changed_at >= ?1 AND h.changed_at < ?2
+			GROUP BY 1
+		), overdue AS (
+			SELECT COALESCE(org_id, '') AS org_id, COUNT(*) AS tasks
+			FROM tasks
+			WHERE status IN ('pending', 'in_progress') AND due_date < ?2
+			GROUP BY 1
+		), orgs AS (
+			SELECT org_id FROM created
+			UNION SELECT org_id FROM completed
+			UNION SELECT org_id FROM overdue
+		)
+		SELECT orgs.org_id, COALESCE(created.tasks, 0), COALESCE(completed.tasks, 0),
+			COALESCE(overdue.tasks, 0), COALESCE(completed.cycle_seconds, 0)
+		FROM orgs
+		LEFT JOIN created ON created.org_id = orgs.org_id
+		LEFT JOIN completed ON completed.org_id = orgs.org_id
+		LEFT JOIN overdue ON overdue.org_id = orgs.org_id
+		ORDER BY orgs.org_id`, since.UTC(), until.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	kpis := []*models.OrgKPIs{}
+	for rows.Next() {
+		var org models.OrgKPIs
+		var cycleSeconds float64
+		if err := rows.Scan(&org.OrgID, &org.TasksCreated, &org.TasksCompleted, &org.Overdue, &cycleSeconds); err != nil {
+			return nil, err
+		}
+		org.AvgCycleTime = time.Duration(cycleSeconds * float64(time.Second))
+		kpis = append(kpis, &org)
+	}
+	return kpis, rows.Err()
+}
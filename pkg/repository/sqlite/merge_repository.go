@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+type mergeRepository struct {
+	db *sql.DB
+}
+
+// NewMergeRepository creates a new SQLite task merge repository
+func NewMergeRepository(db *sql.DB) repository.MergeRepository {
+	return &mergeRepository{db: db}
+}
+
+func (r *mergeRepository) Merge(ctx context.Context, targetID, sourceID string) (*models.TaskMerge, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var found int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM tasks WHERE id IN (?1, ?2) AND `+tenantMatch("", 3),
+		targetID, sourceID, tenant.Arg(ctx)).Scan(&found)
+	if err != nil {
+		return nil, err
+	}
+	if found != 2 {
+		return nil, errors.New("task not found")
+	}
+
+	merge := &models.TaskMerge{}
+	result, err := tx.ExecContext(ctx,
+		`UPDATE task_attachments SET task_id = ? WHERE task_id = ?`, targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if merge.MovedAttachments, err = rowsAffected(result); err != nil {
+		return nil, err
+	}
+
+	result, err = tx.ExecContext(ctx, `
+		INSERT INTO task_stars (task_id, user_id, created_at)
+		SELECT ?1, user_id, created_at FROM task_stars WHERE task_id = ?2
+		ON CONFLICT (task_id, user_id) DO NOTHING`,
+		targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if merge.MovedWatchers, err = rowsAffected(result); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_stars WHERE task_id = ?`, sourceID); err != nil {
+		return nil, err
+	}
+
+	// Whatever related the two tasks before, the source is now a duplicate of the target
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM task_relations
+		WHERE (task_id = ?1 AND related_id = ?2) OR (task_id = ?2 AND related_id = ?1)`,
+		targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO task_relations (task_id, related_id, type) VALUES (?, ?, ?)`,
+		sourceID, targetID, models.RelationDuplicateOf)
+	if err != nil {
+		return nil, err
+	}
+
+	if merge.Source, err = scanTask(tx.QueryRowContext(ctx, `
+		UPDATE tasks SET status = ?1, updated_at = ?2, `+slaTimes("?1", "?2")+`
+		WHERE id = ?3
+		RETURNING `+taskColumns,
+		models.StatusCancelled, time.Now().UTC(), sourceID)); err != nil {
+		return nil, err
+	}
+	if merge.Task, err = scanTask(tx.QueryRowContext(ctx,
+		`SELECT `+taskColumns+` FROM tasks WHERE id = ?`, targetID)); err != nil {
+		return nil, err
+	}
+	if err := loadTags(ctx, tx, merge.Source, merge.Task); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
+
+// rowsAffected returns how many rows a statement changed
+func rowsAffected(result sql.Result) (int, error) {
+	rows, err := result.RowsAffected()
+	return int(rows), err
+}
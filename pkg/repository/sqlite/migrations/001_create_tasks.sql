@@ -0,0 +1,42 @@
+-- +migrate Up
+-- The task store of dev mode: the columns of the PostgreSQL tasks table the task API
+-- reads and writes, with tags and project counters as in PostgreSQL
+CREATE TABLE tasks (
+    id TEXT PRIMARY KEY,
+    project_key TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    slug TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    priority TEXT NOT NULL DEFAULT 'medium',
+    due_date DATETIME,
+    owner_id TEXT,
+    assignee_id TEXT,
+    parent_id TEXT REFERENCES tasks(id) ON DELETE SET NULL,
+    metadata TEXT NOT NULL DEFAULT '{}',
+    estimated_minutes INTEGER,
+    logged_minutes INTEGER NOT NULL DEFAULT 0,
+    position REAL NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_tasks_project_number ON tasks(project_key, number);
+CREATE UNIQUE INDEX idx_tasks_project_slug ON tasks(project_key, slug);
+CREATE INDEX idx_tasks_parent_id ON tasks(parent_id);
+CREATE INDEX idx_tasks_created_at ON tasks(created_at);
+
+CREATE TABLE task_tags (
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    tag TEXT NOT NULL,
+    PRIMARY KEY (task_id, tag)
+);
+
+CREATE INDEX idx_task_tags_tag ON task_tags(tag);
+
+-- Numbers of deleted tasks are never handed out again
+CREATE TABLE project_task_counters (
+    project_key TEXT PRIMARY KEY,
+    last_number INTEGER NOT NULL
+);
@@ -0,0 +1,599 @@
+-- +migrate Up
+-- The rest of the PostgreSQL schema, so dev mode serves every API. Arrays are stored as
+-- JSON arrays, JSONB as TEXT and BYTEA as BLOB; times are written in UTC so they compare
+-- as text in time order.
+CREATE TABLE teams (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE team_members (
+    team_id TEXT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    added_by TEXT NOT NULL,
+    added_at DATETIME NOT NULL,
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_members_user_id ON team_members(user_id);
+
+ALTER TABLE tasks ADD COLUMN checklist_total INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN checklist_done INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN assigned_at DATETIME;
+ALTER TABLE tasks ADD COLUMN stale_at DATETIME;
+ALTER TABLE tasks ADD COLUMN overdue_at DATETIME;
+ALTER TABLE tasks ADD COLUMN snoozed_until DATETIME;
+ALTER TABLE tasks ADD COLUMN sla_respond_within INTEGER;
+ALTER TABLE tasks ADD COLUMN sla_resolve_within INTEGER;
+ALTER TABLE tasks ADD COLUMN responded_at DATETIME;
+ALTER TABLE tasks ADD COLUMN resolved_at DATETIME;
+ALTER TABLE tasks ADD COLUMN sla_respond_breached_at DATETIME;
+ALTER TABLE tasks ADD COLUMN sla_resolve_breached_at DATETIME;
+ALTER TABLE tasks ADD COLUMN team_id TEXT REFERENCES teams(id) ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN org_id TEXT;
+ALTER TABLE tasks ADD COLUMN change_seq INTEGER NOT NULL DEFAULT 0;
+
+UPDATE tasks SET assigned_at = created_at WHERE assignee_id IS NOT NULL;
+UPDATE tasks SET responded_at = updated_at WHERE status NOT IN ('pending', 'draft');
+UPDATE tasks SET resolved_at = updated_at WHERE status IN ('completed', 'cancelled');
+
+-- Slugs are unique per organization, as in PostgreSQL
+DROP INDEX idx_tasks_project_slug;
+CREATE UNIQUE INDEX idx_tasks_org_project_slug ON tasks(COALESCE(org_id, ''), project_key, slug);
+CREATE INDEX idx_tasks_org ON tasks(org_id);
+CREATE INDEX idx_tasks_board_position ON tasks(project_key, status, position);
+CREATE INDEX idx_tasks_change_seq ON tasks(change_seq, id);
+
+CREATE TABLE task_slug_history (
+    project_key TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    org_id TEXT
+);
+
+CREATE UNIQUE INDEX idx_task_slug_history_org_slug ON task_slug_history(COALESCE(org_id, ''), project_key, slug);
+CREATE INDEX idx_task_slug_history_task_id ON task_slug_history(task_id);
+
+CREATE TABLE task_description_versions (
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    content BLOB NOT NULL,
+    editor_id TEXT,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (task_id, version)
+);
+
+-- PostgreSQL numbers changes with transaction IDs. SQLite runs one write transaction at a
+-- time, so a counter bumped by every change gives the same order.
+CREATE TABLE task_change_sequence (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    last_seq INTEGER NOT NULL
+);
+
+INSERT INTO task_change_sequence (id, last_seq) VALUES (1, 1);
+UPDATE tasks SET change_seq = 1;
+
+CREATE TABLE task_tombstones (
+    task_id TEXT PRIMARY KEY,
+    owner_id TEXT,
+    draft BOOLEAN NOT NULL DEFAULT FALSE,
+    change_seq INTEGER NOT NULL,
+    deleted_at DATETIME NOT NULL,
+    org_id TEXT
+);
+
+CREATE INDEX idx_task_tombstones_change_seq ON task_tombstones(change_seq, task_id);
+
+CREATE TRIGGER tasks_change_seq_insert AFTER INSERT ON tasks
+BEGIN
+    UPDATE task_change_sequence SET last_seq = last_seq + 1;
+    UPDATE tasks SET change_seq = (SELECT last_seq FROM task_change_sequence) WHERE id = NEW.id;
+END;
+
+-- Only changes made by the repositories bump the sequence, not the trigger's own update
+CREATE TRIGGER tasks_change_seq_update AFTER UPDATE ON tasks
+WHEN NEW.change_seq = OLD.change_seq
+BEGIN
+    UPDATE task_change_sequence SET last_seq = last_seq + 1;
+    UPDATE tasks SET change_seq = (SELECT last_seq FROM task_change_sequence) WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER tasks_tombstone AFTER DELETE ON tasks
+BEGIN
+    UPDATE task_change_sequence SET last_seq = last_seq + 1;
+    INSERT INTO task_tombstones (task_id, owner_id, draft, change_seq, deleted_at, org_id)
+    VALUES (OLD.id, OLD.owner_id, OLD.status = 'draft', (SELECT last_seq FROM task_change_sequence),
+        strftime('%Y-%m-%d %H:%M:%f', 'now'), OLD.org_id)
+    ON CONFLICT (task_id) DO UPDATE SET change_seq = excluded.change_seq, deleted_at = excluded.deleted_at;
+END;
+
+-- Starting work on a task responds to it and closing it resolves it; reopening it undoes
+-- the resolution. The task repository sets both in the statements it returns tasks from,
+-- since RETURNING doesn't see what AFTER triggers change.
+CREATE TRIGGER tasks_sla_times_insert AFTER INSERT ON tasks
+WHEN (NEW.status NOT IN ('pending', 'draft') AND NEW.responded_at IS NULL)
+    OR (NEW.status IN ('completed', 'cancelled') AND NEW.resolved_at IS NULL)
+BEGIN
+    UPDATE tasks
+    SET responded_at = CASE WHEN status NOT IN ('pending', 'draft') THEN COALESCE(responded_at, strftime('%Y-%m-%d %H:%M:%f', 'now')) ELSE responded_at END,
+        resolved_at = CASE WHEN status IN ('completed', 'cancelled') THEN COALESCE(resolved_at, strftime('%Y-%m-%d %H:%M:%f', 'now')) END
+    WHERE id = NEW.id;
+END;
+
+CREATE TRIGGER tasks_sla_times_update AFTER UPDATE OF status ON tasks
+WHEN (NEW.status NOT IN ('pending', 'draft') AND NEW.responded_at IS NULL)
+    OR (NEW.status IN ('completed', 'cancelled') AND NEW.resolved_at IS NULL)
+    OR (NEW.status NOT IN ('completed', 'cancelled') AND NEW.resolved_at IS NOT NULL)
+BEGIN
+    UPDATE tasks
+    SET responded_at = CASE WHEN status NOT IN ('pending', 'draft') THEN COALESCE(responded_at, strftime('%Y-%m-%d %H:%M:%f', 'now')) ELSE responded_at END,
+        resolved_at = CASE WHEN status IN ('completed', 'cancelled') THEN COALESCE(resolved_at, strftime('%Y-%m-%d %H:%M:%f', 'now')) END
+    WHERE id = NEW.id;
+END;
+
+CREATE TABLE task_stars (
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_stars_user_id ON task_stars(user_id);
+
+CREATE TABLE task_views (
+    user_id TEXT NOT NULL,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    viewed_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, task_id)
+);
+
+CREATE INDEX idx_task_views_user_viewed_at ON task_views(user_id, viewed_at);
+
+CREATE TABLE task_dependencies (
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocked_by_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, blocked_by_id),
+    CHECK (task_id <> blocked_by_id)
+);
+
+CREATE INDEX idx_task_dependencies_blocked_by_id ON task_dependencies(blocked_by_id);
+
+CREATE TABLE task_relations (
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    related_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, related_id, type),
+    CHECK (task_id <> related_id)
+);
+
+CREATE INDEX idx_task_relations_related_id ON task_relations(related_id);
+
+CREATE TABLE task_approvals (
+    task_id TEXT PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    approver_id TEXT NOT NULL,
+    requested_by TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    comment TEXT,
+    requested_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    decided_by TEXT,
+    decided_at DATETIME
+);
+
+CREATE INDEX idx_task_approvals_approver_id ON task_approvals(approver_id) WHERE status = 'pending';
+
+CREATE TABLE task_shares (
+    id TEXT PRIMARY KEY,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    permission TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME
+);
+
+CREATE INDEX idx_task_shares_task_id ON task_shares(task_id);
+
+CREATE TABLE task_attachments (
+    id TEXT PRIMARY KEY,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    storage_key TEXT NOT NULL,
+    uploaded_by TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_task_attachments_task_id ON task_attachments(task_id);
+
+CREATE TABLE task_checklist_items (
+    id TEXT PRIMARY KEY,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT FALSE,
+    position INTEGER NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_task_checklist_items_task_id ON task_checklist_items(task_id, position);
+
+CREATE TABLE task_time_entries (
+    id TEXT PRIMARY KEY,
+    task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL,
+    started_at DATETIME NOT NULL,
+    ended_at DATETIME,
+    minutes INTEGER NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_task_time_entries_task_id ON task_time_entries(task_id, started_at);
+CREATE UNIQUE INDEX idx_task_time_entries_running ON task_time_entries(task_id, user_id) WHERE ended_at IS NULL;
+
+CREATE TABLE task_templates (
+    id TEXT PRIMARY KEY,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    checklist TEXT NOT NULL DEFAULT '[]',
+    owner_id TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    org_id TEXT
+);
+
+CREATE INDEX idx_task_templates_owner_id ON task_templates(owner_id);
+CREATE INDEX idx_task_templates_org ON task_templates(org_id);
+
+CREATE TABLE task_status_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_id TEXT NOT NULL,
+    project_key TEXT NOT NULL,
+    status TEXT NOT NULL, -- the task's new status, or 'deleted'
+    changed_at DATETIME NOT NULL,
+    org_id TEXT
+);
+
+CREATE INDEX idx_task_status_history_org_project ON task_status_history(org_id, project_key, changed_at);
+CREATE INDEX idx_task_status_history_task ON task_status_history(task_id, changed_at);
+CREATE INDEX idx_task_status_history_changed ON task_status_history(changed_at);
+
+INSERT INTO task_status_history (task_id, project_key, status, changed_at)
+SELECT id, project_key, CASE WHEN status IN ('completed', 'cancelled') THEN 'pending' ELSE status END, created_at
+FROM tasks
+WHERE status <> 'draft';
+
+INSERT INTO task_status_history (task_id, project_key, status, changed_at)
+SELECT id, project_key, status, updated_at
+FROM tasks
+WHERE status IN ('completed', 'cancelled');
+
+CREATE TABLE project_settings (
+    project_key TEXT NOT NULL,
+    stale_after_days INTEGER, -- NULL uses STALE_TASK_DAYS, 0 disables stale detection
+    search_language TEXT NOT NULL DEFAULT 'english',
+    updated_by TEXT NOT NULL,
+    updated_at DATETIME NOT NULL,
+    org_id TEXT
+);
+
+CREATE UNIQUE INDEX idx_project_settings_org_project ON project_settings(COALESCE(org_id, ''), project_key);
+
+CREATE TABLE moderation_flags (
+    id TEXT PRIMARY KEY,
+    resource_type TEXT NOT NULL,
+    resource_id TEXT NOT NULL,
+    reasons TEXT NOT NULL DEFAULT '[]',
+    status TEXT NOT NULL DEFAULT 'pending',
+    reviewed_by TEXT,
+    reviewed_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_moderation_flags_status ON moderation_flags(status, created_at);
+
+CREATE TABLE oauth_clients (
+    id TEXT PRIMARY KEY,
+    client_id TEXT NOT NULL UNIQUE,
+    secret_hash TEXT NOT NULL,
+    name TEXT NOT NULL,
+    roles TEXT NOT NULL DEFAULT '[]',
+    scopes TEXT NOT NULL DEFAULT '[]',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME,
+    allow_token_exchange BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE audit_events (
+    id TEXT PRIMARY KEY,
+    action TEXT NOT NULL,
+    actor_id TEXT NOT NULL,
+    subject_id TEXT NOT NULL,
+    impersonated BOOLEAN NOT NULL DEFAULT FALSE,
+    method TEXT,
+    path TEXT,
+    status_code INTEGER,
+    remote_addr TEXT,
+    metadata TEXT NOT NULL DEFAULT '{}',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_actor ON audit_events(actor_id, created_at);
+CREATE INDEX idx_audit_events_subject ON audit_events(subject_id, created_at);
+
+CREATE TABLE org_ip_allowlist (
+    id TEXT PRIMARY KEY,
+    org_id TEXT NOT NULL,
+    cidr TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (org_id, cidr)
+);
+
+CREATE TABLE announcements (
+    id TEXT PRIMARY KEY,
+    message TEXT NOT NULL,
+    severity TEXT NOT NULL DEFAULT 'info',
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME,
+    created_by TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_announcements_window ON announcements(starts_at, ends_at);
+
+CREATE TABLE telemetry_events (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    client TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    org_id TEXT,
+    properties TEXT NOT NULL DEFAULT '{}',
+    sample_rate REAL NOT NULL,
+    occurred_at DATETIME NOT NULL,
+    received_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_telemetry_events_name ON telemetry_events(name, occurred_at);
+CREATE INDEX idx_telemetry_events_org ON telemetry_events(org_id, occurred_at);
+
+CREATE TABLE org_telemetry_settings (
+    org_id TEXT PRIMARY KEY,
+    opted_out BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by TEXT NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE user_notification_preferences (
+    user_id TEXT PRIMARY KEY,
+    email TEXT NOT NULL DEFAULT '',
+    digest TEXT NOT NULL DEFAULT 'off',
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    digest_hour INTEGER NOT NULL DEFAULT 8,
+    last_digest_at DATETIME,
+    updated_at DATETIME NOT NULL,
+    stale_alerts BOOLEAN NOT NULL DEFAULT TRUE,
+    locale TEXT NOT NULL DEFAULT 'en',
+    page_size INTEGER NOT NULL DEFAULT 10
+);
+
+CREATE TABLE reports (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    definition TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE export_jobs (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    request TEXT NOT NULL,
+    requested_by TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    progress INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    file_name TEXT,
+    content_type TEXT,
+    size INTEGER,
+    storage_key TEXT,
+    content BLOB,
+    created_at DATETIME NOT NULL,
+    started_at DATETIME,
+    completed_at DATETIME,
+    expires_at DATETIME,
+    run_after DATETIME,
+    org_id TEXT
+);
+
+CREATE INDEX idx_export_jobs_queue ON export_jobs(status, created_at);
+
+CREATE TABLE import_jobs (
+    id TEXT PRIMARY KEY,
+    file_name TEXT NOT NULL,
+    options TEXT NOT NULL,
+    requested_by TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    total_rows INTEGER NOT NULL,
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    created_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    content BLOB,
+    created_at DATETIME NOT NULL,
+    started_at DATETIME,
+    heartbeat_at DATETIME,
+    completed_at DATETIME,
+    run_after DATETIME,
+    org_id TEXT
+);
+
+CREATE INDEX idx_import_jobs_queue ON import_jobs(status, created_at);
+
+CREATE TABLE import_job_rows (
+    job_id TEXT NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+    row_number INTEGER NOT NULL,
+    task_id TEXT,
+    error TEXT,
+    PRIMARY KEY (job_id, row_number)
+);
+
+CREATE TABLE dead_letter_jobs (
+    job_type TEXT NOT NULL,
+    job_id TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    error TEXT,
+    dead_at DATETIME NOT NULL,
+    requeued_at DATETIME,
+    PRIMARY KEY (job_type, job_id)
+);
+
+CREATE TABLE webhooks (
+    id TEXT PRIMARY KEY,
+    url TEXT NOT NULL,
+    events TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    org_id TEXT
+);
+
+CREATE INDEX idx_webhooks_org ON webhooks(org_id);
+
+CREATE TABLE webhook_deliveries (
+    id TEXT PRIMARY KEY,
+    webhook_id TEXT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    success BOOLEAN NOT NULL,
+    status_code INTEGER, -- NULL when the receiver could not be reached
+    duration_ms INTEGER NOT NULL,
+    response TEXT,
+    error TEXT,
+    redelivery BOOLEAN NOT NULL DEFAULT FALSE,
+    event TEXT NOT NULL,
+    delivered_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, delivered_at);
+CREATE INDEX idx_webhook_deliveries_delivered_at ON webhook_deliveries(delivered_at);
+
+CREATE TABLE event_archive (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    task_id TEXT NOT NULL,
+    task TEXT, -- NULL for task.deleted
+    occurred_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_event_archive_occurred_at ON event_archive(occurred_at);
+
+CREATE TABLE org_invitations (
+    id TEXT PRIMARY KEY,
+    org_id TEXT NOT NULL,
+    email TEXT NOT NULL,
+    role TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    invited_by TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    accepted_by TEXT,
+    accepted_at DATETIME
+);
+
+CREATE INDEX idx_org_invitations_org_id ON org_invitations(org_id, created_at);
+
+CREATE TABLE org_memberships (
+    org_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL,
+    invitation_id TEXT REFERENCES org_invitations(id) ON DELETE SET NULL,
+    joined_at DATETIME NOT NULL,
+    PRIMARY KEY (org_id, user_id)
+);
+
+CREATE INDEX idx_org_memberships_user_id ON org_memberships(user_id);
+
+CREATE TABLE oidc_identities (
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    user_id TEXT NOT NULL UNIQUE,
+    email TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    roles TEXT NOT NULL DEFAULT '[]',
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE TABLE api_keys (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    org_id TEXT,
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    key_hash TEXT NOT NULL UNIQUE,
+    roles TEXT NOT NULL DEFAULT '[]',
+    scopes TEXT NOT NULL DEFAULT '[]',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME,
+    last_used_at DATETIME,
+    revoked_at DATETIME
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id, created_at);
+
+CREATE TABLE deprecation_usage (
+    deprecation_id TEXT NOT NULL,
+    client_kind TEXT NOT NULL,
+    client TEXT NOT NULL,
+    user_id TEXT NOT NULL DEFAULT '',
+    org_id TEXT,
+    requests INTEGER NOT NULL,
+    first_seen_at DATETIME NOT NULL,
+    last_seen_at DATETIME NOT NULL,
+    notified_at DATETIME,
+    PRIMARY KEY (deprecation_id, client_kind, client, user_id)
+);
+
+CREATE TABLE roles (
+    name TEXT PRIMARY KEY,
+    permissions TEXT NOT NULL, -- route pattern -> allowed methods
+    updated_by TEXT NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE users (
+    id TEXT PRIMARY KEY,
+    email TEXT NOT NULL,
+    password_hash TEXT NOT NULL, -- bcrypt
+    roles TEXT NOT NULL DEFAULT '["user"]',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_users_email ON users(lower(email));
+
+CREATE TABLE password_resets (
+    token_hash TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME
+);
+
+CREATE INDEX idx_password_resets_user_id ON password_resets(user_id);
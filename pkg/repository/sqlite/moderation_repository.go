@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type moderationRepository struct {
+	db *sql.DB
+}
+
+// NewModerationRepository creates a new SQLite moderation repository
+func NewModerationRepository(db *sql.DB) repository.ModerationRepository {
+	return &moderationRepository{db: db}
+}
+
+func (r *moderationRepository) CreateFlag(ctx context.Context, flag *models.ModerationFlag) (*models.ModerationFlag, error) {
+	query := `
+		INSERT INTO moderation_flags (id, resource_type, resource_id, reasons, status, created_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+		RETURNING id, resource_type, resource_id, reasons, status, reviewed_by, reviewed_at, created_at`
+
+	result := &models.ModerationFlag{}
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		uuid.New().String(),
+		flag.ResourceType,
+		flag.ResourceID,
+		stringList(flag.Reasons),
+		models.FlagPending,
+		time.Now().UTC(),
+	).Scan(
+		&result.ID,
+		&result.ResourceType,
+		&result.ResourceID,
+		(*stringList)(&result.Reasons),
+		&result.Status,
+		&result.ReviewedBy,
+		&result.ReviewedAt,
+		&result.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *moderationRepository) GetFlag(ctx context.Context, id string) (*models.ModerationFlag, error) {
+	query := `
+		SELECT id, resource_type, resource_id, reasons, status, reviewed_by, reviewed_at, created_at
+		FROM moderation_flags
+		WHERE id = ?1`
+
+	flag := &models.ModerationFlag{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&flag.ID,
+		&flag.ResourceType,
+		&flag.ResourceID,
+		(*stringList)(&flag.Reasons),
+		&flag.Status,
+		&flag.ReviewedBy,
+		&flag.ReviewedAt,
+		&flag.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.New("flag not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return flag, nil
+}
+
+func (r *moderationRepository) ListFlags(ctx context.Context, status models.FlagStatus, page, limit int) ([]*models.ModerationFlag, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM moderation_flags WHERE status = ?1`, status).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, resource_type, resource_id, reasons, status, reviewed_by, reviewed_at, created_at
+		FROM moderation_flags
+		WHERE status = ?1
+		ORDER BY created_at DESC
+		LIMIT ?2 OFFSET ?3`
+
+	rows, err := r.db.QueryContext(ctx, query, status, limit, (page-1)*limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var flags []*models.ModerationFlag
+	for rows.Next() {
+		flag := &models.ModerationFlag{}
+		err := rows.Scan(
+			&flag.ID,
+			&flag.ResourceType,
+			&flag.ResourceID,
+			(*stringList)(&flag.Reasons),
+			&flag.Status,
+			&flag.ReviewedBy,
+			&flag.ReviewedAt,
+			&flag.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		flags = append(flags, flag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return flags, total, nil
+}
+
+func (r *moderationRepository) ReviewFlag(ctx context.Context, id string, status models.FlagStatus, reviewer string) (*models.ModerationFlag, error) {
+	query := `
+		UPDATE moderation_flags
+		SET status = ?2, reviewed_by = ?3, reviewed_at = ?4
+		WHERE id = ?1
+		RETURNING id, resource_type, resource_id, reasons, status, reviewed_by, reviewed_at, created_at`
+
+	flag := &models.ModerationFlag{}
+	err := r.db.QueryRowContext(ctx, query, id, status, reviewer, time.Now().UTC()).Scan(
+		&flag.ID,
+		&flag.ResourceType,
+		&flag.ResourceID,
+		(*stringList)(&flag.Reasons),
+		&flag.Status,
+		&flag.ReviewedBy,
+		&flag.ReviewedAt,
+		&flag.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.New("flag not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return flag, nil
+}
@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type oauthClientRepository struct {
+	db *sql.DB
+}
+
+// NewOAuthClientRepository creates a new SQLite OAuth client repository
+func NewOAuthClientRepository(db *sql.DB) repository.OAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+func (r *oauthClientRepository) Create(ctx context.Context, client *models.OAuthClient) (*models.OAuthClient, error) {
+	query := `
+		INSERT INTO oauth_clients (id, client_id, secret_hash, name, roles, scopes, allow_token_exchange, created_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+		RETURNING id, client_id, secret_hash, name, roles, scopes, allow_token_exchange, created_at, revoked_at`
+
+	result := &models.OAuthClient{}
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		uuid.New().String(),
+		client.ClientID,
+		client.SecretHash,
+		client.Name,
+		stringList(client.Roles),
+		stringList(client.Scopes),
+		client.AllowTokenExchange,
+		time.Now().UTC(),
+	).Scan(
+		&result.ID,
+		&result.ClientID,
+		&result.SecretHash,
+		&result.Name,
+		(*stringList)(&result.Roles),
+		(*stringList)(&result.Scopes),
+		&result.AllowTokenExchange,
+		&result.CreatedAt,
+		&result.RevokedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *oauthClientRepository) GetByClientID(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+	query := `
+		SELECT id, client_id, secret_hash, name, roles, scopes, allow_token_exchange, created_at, revoked_at
+		FROM oauth_clients
+		WHERE client_id = ?1`
+
+	client := &models.OAuthClient{}
+	err := r.db.QueryRowContext(ctx, query, clientID).Scan(
+		&client.ID,
+		&client.ClientID,
+		&client.SecretHash,
+		&client.Name,
+		(*stringList)(&client.Roles),
+		(*stringList)(&client.Scopes),
+		&client.AllowTokenExchange,
+		&client.CreatedAt,
+		&client.RevokedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.New("client not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (r *oauthClientRepository) List(ctx context.Context) ([]*models.OAuthClient, error) {
+	query := `
+		SELECT id, client_id, secret_hash, name, roles, scopes, allow_token_exchange, created_at, revoked_at
+		FROM oauth_clients
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clients []*models.OAuthClient
+	for rows.Next() {
+		client := &models.OAuthClient{}
+		err := rows.Scan(
+			&client.ID,
+			&client.ClientID,
+			&client.SecretHash,
+			&client.Name,
+			(*stringList)(&client.Roles),
+			(*stringList)(&client.Scopes),
+			&client.AllowTokenExchange,
+			&client.CreatedAt,
+			&client.RevokedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return clients, nil
+}
+
+func (r *oauthClientRepository) Revoke(ctx context.Context, clientID string) error {
+	query := `UPDATE oauth_clients SET revoked_at = ?1 WHERE client_id = ?2 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now().UTC(), clientID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("client not found")
+	}
+
+	return nil
+}
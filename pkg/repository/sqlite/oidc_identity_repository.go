@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type oidcIdentityRepository struct {
+	db *sql.DB
+}
+
+// NewOIDCIdentityRepository creates a new SQLite OpenID Connect identity repository
+func NewOIDCIdentityRepository(db *sql.DB) repository.OIDCIdentityRepository {
+	return &oidcIdentityRepository{db: db}
+}
+
+func (r *oidcIdentityRepository) Link(ctx context.Context, provider, subject, email string, roles []string, now time.Time) (*models.OIDCIdentity, error) {
+	identity := &models.OIDCIdentity{}
+	var storedEmail sql.NullString
+	// A single statement, so concurrent first sign-ins agree on the user ID
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO oidc_identities (provider, subject, user_id, email, roles, created_at, last_login_at)
+		VALUES (?1, ?2, ?3, NULLIF(?4, ''), ?5, ?6, ?6)
+		ON CONFLICT (provider, subject) DO UPDATE
+		SET email = COALESCE(excluded.email, email),
+			roles = excluded.roles,
+			last_login_at = excluded.last_login_at
+		RETURNING provider, subject, user_id, email, roles, created_at, last_login_at`,
+		provider, subject, uuid.New().String(), email, stringList(roles), now.UTC()).Scan(
+		&identity.Provider,
+		&identity.Subject,
+		&identity.UserID,
+		&storedEmail,
+		(*stringList)(&identity.Roles),
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	identity.Email = storedEmail.String
+	return identity, nil
+}
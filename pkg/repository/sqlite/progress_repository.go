@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+type progressRepository struct {
+	db *sql.DB
+}
+
+// NewProgressRepository creates a new SQLite task status history repository
+func NewProgressRepository(db *sql.DB) repository.ProgressRepository {
+	return &progressRepository{db: db}
+}
+
+func (r *progressRepository) RecordStatus(ctx context.Context, task *models.Task, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO task_status_history (task_id, project_key, status, changed_at, org_id)
+		SELECT ?1, ?2, ?3, ?4, NULLIF(?5, '')
+		WHERE ?3 IS NOT (
+			SELECT status FROM task_status_history
+			WHERE task_id = ?1
+			ORDER BY changed_at DESC, id DESC
+			LIMIT 1
+		)`, task.ID, task.ProjectKey, string(task.Status), at.UTC(), task.OrgID)
+	return err
+}
+
+func (r *progressRepository) RecordDeleted(ctx context.Context, taskID string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO task_status_history (task_id, project_key, status, changed_at, org_id)
+		SELECT task_id, project_key, 'deleted', ?2, org_id
+		FROM (
+			SELECT task_id, project_key, status, org_id FROM task_status_history
+			WHERE task_id = ?1
+			ORDER BY changed_at DESC, id DESC
+			LIMIT 1
+		) latest
+		WHERE status <> 'deleted'`, taskID, at.UTC())
+	return err
+}
+
+func (r *progressRepository) DailyCounts(ctx context.Context, projectKey string, from, to time.Time) ([]*models.ProgressCount, error) {
+	// The status of every task at the end of each day is its latest change before midnight
+	rows, err := r.db.QueryContext(ctx, `
+		WITH RECURSIVE days (day) AS (
+			SELECT date(?2)
+			UNION ALL
+			SELECT date(day, '+1 day') FROM days WHERE day < date(?3)
+		),
+		latest AS (
+			SELECT d.day, h.status,
+				ROW_NUMBER() OVER (PARTITION BY d.day, h.task_id ORDER BY h.changed_at DESC, h.id DESC) AS n
+			FROM days d
+			JOIN task_status_history h ON h.project_key = ?1 AND h.changed_at < date(d.day, '+1 day')
+				AND `+tenantMatch("h.", 4)+`
+		)
+		SELECT day,
+			COUNT(*) FILTER (WHERE status IN ('pending', 'in_progress')),
+			COUNT(*) FILTER (WHERE status = 'completed')
+		FROM latest
+		WHERE n = 1
+		GROUP BY day
+		ORDER BY day`, projectKey, from.Format(models.ChartDateLayout), to.Format(models.ChartDateLayout), tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []*models.ProgressCount{}
+	for rows.Next() {
+		count := &models.ProgressCount{}
+		if err := rows.Scan((*timeValue)(&count.Day), &count.Open, &count.Completed); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
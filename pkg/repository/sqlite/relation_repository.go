@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+type relationRepository struct {
+	db *sql.DB
+}
+
+// NewRelationRepository creates a new SQLite task relation repository
+func NewRelationRepository(db *sql.DB) repository.RelationRepository {
+	return &relationRepository{db: db}
+}
+
+func (r *relationRepository) Add(ctx context.Context, taskID, relatedID string, relationType models.RelationType) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO task_relations (task_id, related_id, type)
+		VALUES (?, ?, ?)
+		ON CONFLICT DO NOTHING`,
+		taskID, relatedID, relationType)
+	return err
+}
+
+func (r *relationRepository) Remove(ctx context.Context, taskID, relatedID string, relationType models.RelationType) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM task_relations WHERE task_id = ? AND related_id = ? AND type = ?`,
+		taskID, relatedID, relationType)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("relation not found")
+	}
+
+	return nil
+}
+
+func (r *relationRepository) List(ctx context.Context, taskID string) ([]*models.TaskRelation, error) {
+	// outgoing tells relations the task holds from relations pointing at it
+	query := `
+		SELECT r.type, TRUE AS outgoing, t.id, t.project_key, t.number, t.title, t.status, r.created_at AS created_at
+		FROM task_relations r
+		JOIN tasks t ON t.id = r.related_id
+		WHERE r.task_id = ?1 AND ` + tenantMatch("t.", 2) + `
+		UNION ALL
+		SELECT r.type, FALSE AS outgoing, t.id, t.project_key, t.number, t.title, t.status, r.created_at AS created_at
+		FROM task_relations r
+		JOIN tasks t ON t.id = r.task_id
+		WHERE r.related_id = ?1 AND ` + tenantMatch("t.", 2) + `
+		ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID, tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	relations := []*models.TaskRelation{}
+	for rows.Next() {
+		relation := &models.TaskRelation{}
+		var outgoing bool
+		var projectKey string
+		var number int64
+		err := rows.Scan(
+			&relation.Type,
+			&outgoing,
+			&relation.TaskID,
+			&projectKey,
+			&number,
+			&relation.Title,
+			&relation.Status,
+			(*timeValue)(&relation.CreatedAt),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if !outgoing {
+			relation.Type = relation.Type.Inverse()
+		}
+		relation.TaskKey = models.FormatTaskKey(projectKey, number)
+		relations = append(relations, relation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return relations, nil
+}
@@ -0,0 +1,224 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository/memory"
+)
+
+// openSeeded opens an in-memory database seeded with the demo fixtures
+func openSeeded(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(context.Background(), ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, Seed(context.Background(), db, memory.TaskFixtures("demo")))
+	return db
+}
+
+func TestSyncRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openSeeded(t)
+	repo := NewSyncRepository(db)
+
+	changes, bound, err := repo.Changes(ctx, models.SyncCursor{}, "demo", 100)
+	require.NoError(t, err)
+	assert.Len(t, changes, len(memory.TaskFixtures("demo")))
+	last := changes[len(changes)-1]
+	cursor := models.SyncCursor{Seq: last.Seq, TaskID: last.TaskID}
+	assert.Greater(t, bound, last.Seq)
+
+	// Changing and deleting tasks moves them past the cursor
+	title := "Renamed"
+	updated, err := NewTaskRepository(db).Update(ctx, changes[0].TaskID, &models.TaskUpdate{Title: &title})
+	require.NoError(t, err)
+	require.NoError(t, NewTaskRepository(db).Delete(ctx, changes[1].TaskID))
+
+	changes, _, err = repo.Changes(ctx, cursor, "demo", 100)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, models.SyncUpsert, changes[0].Op)
+	assert.Equal(t, updated.ID, changes[0].Task.ID)
+	assert.Equal(t, "Renamed", changes[0].Task.Title)
+	assert.Equal(t, models.SyncDelete, changes[1].Op)
+	assert.NotNil(t, changes[1].DeletedAt)
+}
+
+func TestChecklistRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openSeeded(t)
+	repo := NewChecklistRepository(db)
+	taskID := memory.TaskFixtures("demo")[0].ID
+
+	first, err := repo.Add(ctx, taskID, &models.ChecklistItemInput{Text: "Write"})
+	require.NoError(t, err)
+	second, err := repo.Add(ctx, taskID, &models.ChecklistItemInput{Text: "Review", Done: true})
+	require.NoError(t, err)
+
+	items, err := repo.Reorder(ctx, taskID, []string{second.ID, first.ID})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, second.ID, items[0].ID)
+
+	items, err = repo.SetDone(ctx, taskID, []string{first.ID}, true)
+	require.NoError(t, err)
+	assert.True(t, items[1].Done)
+	_, err = repo.SetDone(ctx, taskID, []string{"missing"}, true)
+	assert.Error(t, err)
+
+	task, err := NewTaskRepository(db).GetByID(ctx, taskID)
+	require.NoError(t, err)
+	require.NotNil(t, task.Checklist)
+	assert.Equal(t, 2, task.Checklist.Total)
+	assert.Equal(t, 2, task.Checklist.Done)
+}
+
+func TestTimeEntryRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openSeeded(t)
+	repo := NewTimeEntryRepository(db)
+	taskID := memory.TaskFixtures("demo")[0].ID
+
+	startedAt := time.Now().Add(-time.Hour)
+	_, err := repo.Log(ctx, taskID, &models.TimeEntryLog{Minutes: 30, StartedAt: &startedAt, UserID: "demo"})
+	require.NoError(t, err)
+	_, err = repo.Start(ctx, taskID, &models.TimerStart{UserID: "demo"})
+	require.NoError(t, err)
+	stopped, err := repo.Stop(ctx, taskID, "demo")
+	require.NoError(t, err)
+	assert.NotNil(t, stopped.EndedAt)
+	assert.Zero(t, stopped.Minutes)
+	_, err = repo.Stop(ctx, taskID, "demo")
+	assert.Error(t, err, "no timer is running")
+
+	entries, err := repo.List(ctx, taskID)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	task, err := NewTaskRepository(db).GetByID(ctx, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, 30, task.LoggedMinutes)
+}
+
+func TestExportRepository_DeadLetter(t *testing.T) {
+	ctx := context.Background()
+	db := openSeeded(t)
+	exports := NewExportRepository(db)
+	deadLetters := NewDeadLetterRepository(db)
+	now := time.Now()
+
+	job, err := exports.Create(ctx, &models.ExportJob{Request: models.ExportRequest{Kind: models.ExportTasks}, RequestedBy: "demo"})
+	require.NoError(t, err)
+	claimed, err := exports.Claim(ctx, "", now, time.Minute, 1)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, job.ID, claimed.ID)
+	assert.Equal(t, models.ExportRunning, claimed.Status)
+
+	// A worker that stopped after the last attempt gives the job up
+	claimed, err = exports.Claim(ctx, "", now.Add(time.Hour), time.Minute, 1)
+	require.NoError(t, err)
+	assert.Nil(t, claimed)
+	letter, err := deadLetters.Get(ctx, models.JobExport, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, letter.Attempts)
+	assert.Equal(t, "export did not finish in time", letter.Error)
+
+	require.NoError(t, deadLetters.Requeue(ctx, models.JobExport, job.ID, now.Add(time.Hour)))
+	assert.ErrorIs(t, deadLetters.Requeue(ctx, models.JobExport, job.ID, now.Add(time.Hour)), models.ErrJobNotRequeueable)
+	letters, total, err := deadLetters.List(ctx, "", 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, letters)
+	released, err := exports.ReleaseRetries(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{job.ID}, released)
+}
+
+func TestUserRepository(t *testing.T) {
+	ctx := context.Background()
+	db := openSeeded(t)
+	repo := NewUserRepository(db)
+
+	account, err := repo.Create(ctx, &models.Account{Email: "dev@example.com", Roles: []string{"user"}}, "hash")
+	require.NoError(t, err)
+	_, err = repo.Create(ctx, &models.Account{Email: "DEV@example.com", Roles: []string{"user"}}, "hash")
+	assert.ErrorIs(t, err, models.ErrEmailTaken)
+
+	found, hash, err := repo.GetByEmail(ctx, "Dev@Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, account.ID, found.ID)
+	assert.Equal(t, "hash", hash)
+	roles, err := repo.GetRoles(ctx, account.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user"}, roles)
+
+	now := time.Now()
+	require.NoError(t, repo.CreatePasswordReset(ctx, account.ID, "token", now.Add(time.Hour)))
+	_, err = repo.ResetPassword(ctx, "token", "new-hash", now)
+	require.NoError(t, err)
+	_, err = repo.ResetPassword(ctx, "token", "newer-hash", now)
+	assert.ErrorIs(t, err, models.ErrPasswordResetInvalid)
+}
+
+func TestBoardRepository_Move(t *testing.T) {
+	ctx := context.Background()
+	db := openSeeded(t)
+	tasks := NewTaskRepository(db)
+	var created []*models.Task
+	for _, title := range []string{"First", "Second", "Third"} {
+		task, err := tasks.Create(ctx, &models.TaskCreate{ProjectKey: "BOARD", Title: title,
+			Status: models.StatusPending, Priority: models.PriorityLow, OwnerID: "demo"})
+		require.NoError(t, err)
+		created = append(created, task)
+	}
+
+	moved, err := NewBoardRepository(db).Move(ctx, created[2].ID, created[0].ID, created[1].ID)
+	require.NoError(t, err)
+	assert.Greater(t, moved.Position, created[0].Position)
+	assert.Less(t, moved.Position, created[1].Position)
+
+	_, err = NewBoardRepository(db).Move(ctx, created[2].ID, created[1].ID, created[0].ID)
+	assert.Error(t, err, "after_id must be above before_id")
+}
+
+func TestRelationRepository_List(t *testing.T) {
+	ctx := context.Background()
+	db := openSeeded(t)
+	repo := NewRelationRepository(db)
+	fixtures := memory.TaskFixtures("demo")
+
+	require.NoError(t, repo.Add(ctx, fixtures[0].ID, fixtures[2].ID, models.RelationCausedBy))
+
+	// Each side sees the relation from its end
+	relations, err := repo.List(ctx, fixtures[0].ID)
+	require.NoError(t, err)
+	require.Len(t, relations, 1)
+	assert.Equal(t, models.RelationCausedBy, relations[0].Type)
+	assert.Equal(t, fixtures[2].ID, relations[0].TaskID)
+	relations, err = repo.List(ctx, fixtures[2].ID)
+	require.NoError(t, err)
+	require.Len(t, relations, 1)
+	assert.Equal(t, models.RelationCausedBy.Inverse(), relations[0].Type)
+}
+
+func TestSeedUser(t *testing.T) {
+	ctx := context.Background()
+	db := openSeeded(t)
+	admin := &models.Account{ID: "demo", Email: "demo@localhost", Roles: []string{"admin"}}
+
+	require.NoError(t, SeedUser(ctx, db, admin))
+	// Seeding again, e.g. on every start, keeps the user as it is
+	require.NoError(t, SeedUser(ctx, db, &models.Account{ID: "demo", Email: "demo@localhost", Roles: []string{"user"}}))
+	roles, err := NewUserRepository(db).GetRoles(ctx, "demo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, roles)
+	_, err = NewUserRepository(db).Create(ctx, &models.Account{Email: "Demo@localhost", Roles: []string{"user"}}, "hash")
+	assert.ErrorIs(t, err, models.ErrEmailTaken)
+}
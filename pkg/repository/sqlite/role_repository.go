@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// roleColumns lists the columns read by scanRole, in order
+const roleColumns = "name, permissions, updated_by, updated_at"
+
+// scanRole reads a role row selected with roleColumns
+func scanRole(row rowScanner) (*models.RoleDefinition, error) {
+	role := &models.RoleDefinition{}
+	var permissions []byte
+	var updatedAt time.Time
+	if err := row.Scan(&role.Name, &permissions, &role.UpdatedBy, &updatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(permissions, &role.Permissions); err != nil {
+		return nil, err
+	}
+	role.UpdatedAt = &updatedAt
+	return role, nil
+}
+
+type roleRepository struct {
+	db *sql.DB
+}
+
+// NewRoleRepository creates a new SQLite role repository
+func NewRoleRepository(db *sql.DB) repository.RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) List(ctx context.Context) ([]*models.RoleDefinition, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+roleColumns+` FROM roles ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []*models.RoleDefinition{}
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, rows.Err()
+}
+
+func (r *roleRepository) Save(ctx context.Context, role *models.RoleDefinition) (*models.RoleDefinition, error) {
+	permissions, err := json.Marshal(role.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRole(r.db.QueryRowContext(ctx, `
+		INSERT INTO roles (name, permissions, updated_by, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE
+		SET permissions = excluded.permissions,
+			updated_by = excluded.updated_by,
+			updated_at = excluded.updated_at
+		RETURNING `+roleColumns,
+		role.Name, string(permissions), role.UpdatedBy, time.Now().UTC()))
+}
+
+func (r *roleRepository) Delete(ctx context.Context, name string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM roles WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrRoleNotFound
+	}
+	return nil
+}
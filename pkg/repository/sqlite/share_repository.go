@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+const shareColumns = "id, task_id, permission, created_by, expires_at, created_at, revoked_at"
+
+func scanShare(row rowScanner) (*models.TaskShare, error) {
+	share := &models.TaskShare{}
+	var revokedAt sql.NullTime
+	err := row.Scan(
+		&share.ID,
+		&share.TaskID,
+		&share.Permission,
+		&share.CreatedBy,
+		&share.ExpiresAt,
+		&share.CreatedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		share.RevokedAt = &revokedAt.Time
+	}
+	return share, nil
+}
+
+type shareRepository struct {
+	db *sql.DB
+}
+
+// NewShareRepository creates a new SQLite task share repository
+func NewShareRepository(db *sql.DB) repository.ShareRepository {
+	return &shareRepository{db: db}
+}
+
+func (r *shareRepository) Create(ctx context.Context, share *models.TaskShare) (*models.TaskShare, error) {
+	query := `
+		INSERT INTO task_shares (id, task_id, permission, created_by, expires_at, created_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+		RETURNING ` + shareColumns
+
+	return scanShare(r.db.QueryRowContext(
+		ctx,
+		query,
+		uuid.New().String(),
+		share.TaskID,
+		share.Permission,
+		share.CreatedBy,
+		share.ExpiresAt.UTC(),
+		time.Now().UTC(),
+	))
+}
+
+func (r *shareRepository) GetByID(ctx context.Context, id string) (*models.TaskShare, error) {
+	share, err := scanShare(r.db.QueryRowContext(ctx, `SELECT `+shareColumns+` FROM task_shares WHERE id = ?1`, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("share not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return share, nil
+}
+
+func (r *shareRepository) ListByTask(ctx context.Context, taskID string) ([]*models.TaskShare, error) {
+	query := `
+		SELECT ` + shareColumns + `
+		FROM task_shares
+		WHERE task_id = ?1
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []*models.TaskShare
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, share)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+func (r *shareRepository) Revoke(ctx context.Context, taskID, id string) error {
+	query := `UPDATE task_shares SET revoked_at = ?1 WHERE id = ?2 AND task_id = ?3 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now().UTC(), id, taskID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("share not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// slaColumns names the columns of each SLA target: its window in seconds, when it was
+// met and when its breach was flagged
+var slaColumns = map[models.SLAKind][3]string{
+	models.SLARespond: {"sla_respond_within", "responded_at", "sla_respond_breached_at"},
+	models.SLAResolve: {"sla_resolve_within", "resolved_at", "sla_resolve_breached_at"},
+}
+
+type slaRepository struct {
+	db *sql.DB
+}
+
+// NewSLARepository creates a new SQLite task SLA repository
+func NewSLARepository(db *sql.DB) repository.SLARepository {
+	return &slaRepository{db: db}
+}
+
+func (r *slaRepository) SetPolicy(ctx context.Context, taskID string, respondWithin, resolveWithin *time.Duration) (*models.Task, error) {
+	// Changing the policy leaves updated_at alone, so it doesn't reset stale detection
+	task, err := scanTask(r.db.QueryRowContext(ctx, `
+		UPDATE tasks
+		SET sla_respond_within = ?2, sla_resolve_within = ?3,
+			sla_respond_breached_at = NULL, sla_resolve_breached_at = NULL
+		WHERE id = ?1 AND `+tenantMatch("", 4)+`
+		RETURNING `+taskColumns,
+		taskID, durationSeconds(respondWithin), durationSeconds(resolveWithin), tenant.Arg(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("task not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := loadTags(ctx, r.db, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (r *slaRepository) MarkBreached(ctx context.Context, kind models.SLAKind, now time.Time) ([]*models.Task, error) {
+	columns, ok := slaColumns[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown SLA target %q", kind)
+	}
+	within, metAt, breachedAt := columns[0], columns[1], columns[2]
+
+	// A single statement, so a breach is never flagged (and reported) twice
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE tasks
+		SET `+breachedAt+` = ?1
+		WHERE status <> 'draft'
+			AND `+within+` IS NOT NULL
+			AND `+breachedAt+` IS NULL
+			AND unixepoch(COALESCE(`+metAt+`, ?1), 'subsec') > unixepoch(created_at, 'subsec') + `+within+`
+		RETURNING `+taskColumns, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// durationSeconds stores an optional duration as whole seconds
+func durationSeconds(duration *time.Duration) interface{} {
+	if duration == nil {
+		return nil
+	}
+	return int64(*duration / time.Second)
+}
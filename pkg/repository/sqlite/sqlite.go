@@ -0,0 +1,279 @@
+// Package sqlite implements repositories over an SQLite database, for dev instances
+// that run without external services.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// migrationFS holds the SQLite schema, applied in file name order
+//
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// Open opens the SQLite database at path, creating it if needed, and applies the
+// migrations it does not have yet. ":memory:" opens a database that lives as long as
+// the returned handle.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_foreign_keys=on&_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+	// One connection serializes writes, which SQLite requires anyway, and keeps an
+	// in-memory database from being a different one on every connection
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// migrate applies the embedded migrations missing from schema_migrations, each in its
+// own transaction
+func migrate(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at DATETIME NOT NULL
+		)`)
+	if err != nil {
+		return err
+	}
+
+	names, err := fs.Glob(migrationFS, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var applied bool
+		err := db.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = ?)`, name).Scan(&applied)
+		if err != nil {
+			return err
+		}
+		if applied {
+			continue
+		}
+
+		script, err := migrationFS.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if err := applyMigration(ctx, db, name, string(script)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration runs a migration script and records it as applied
+func applyMigration(ctx context.Context, db *sql.DB, name, script string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`, name, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Seed inserts tasks as they are, keeping their IDs, numbers and times, into a
+// database without tasks. Databases that already have tasks are left alone, so a dev
+// database keeps its changes across restarts.
+func Seed(ctx context.Context, db *sql.DB, tasks []*models.Task) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var seeded bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tasks)`).Scan(&seeded); err != nil {
+		return err
+	}
+	if seeded {
+		return nil
+	}
+
+	for _, task := range tasks {
+		metadata, err := encodeMetadata(task.Metadata)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO tasks (id, project_key, number, slug, title, description, status, priority, due_date, owner_id, assignee_id, parent_id, metadata, estimated_minutes, position, created_at, updated_at, assigned_at, responded_at, resolved_at)
+			VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, NULLIF(?10, ''), NULLIF(?11, ''), NULLIF(?12, ''), ?13, ?14, ?15, ?16, ?17, CASE WHEN ?11 <> '' THEN ?16 END,
+				CASE WHEN ?7 NOT IN ('pending', 'draft') THEN ?17 END, CASE WHEN ?7 IN ('completed', 'cancelled') THEN ?17 END)`,
+			task.ID,
+			task.ProjectKey,
+			task.Number,
+			task.Slug,
+			task.Title,
+			task.Description,
+			task.Status,
+			task.Priority,
+			task.DueDate.UTC(),
+			task.OwnerID,
+			task.AssigneeID,
+			task.ParentID,
+			metadata,
+			task.EstimatedMinutes,
+			task.Position,
+			task.CreatedAt.UTC(),
+			task.UpdatedAt.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+		if err := replaceTags(ctx, tx, task.ID, task.Tags); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO project_task_counters (project_key, last_number)
+			VALUES (?, ?)
+			ON CONFLICT (project_key) DO UPDATE SET last_number = MAX(last_number, excluded.last_number)`,
+			task.ProjectKey, task.Number)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The fixtures' history starts when they were created, and closed ones were closed
+	// when last updated, as migration 002 records it for existing tasks
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO task_status_history (task_id, project_key, status, changed_at)
+		SELECT id, project_key, CASE WHEN status IN ('completed', 'cancelled') THEN 'pending' ELSE status END, created_at
+		FROM tasks
+		WHERE status <> 'draft'
+		UNION ALL
+		SELECT id, project_key, status, updated_at
+		FROM tasks
+		WHERE status IN ('completed', 'cancelled')`)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SeedUser inserts account, keeping its ID, unless a user with its ID or email exists.
+// It gets no password, so it can only use tokens issued for it.
+func SeedUser(ctx context.Context, db *sql.DB, account *models.Account) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO users (id, email, password_hash, roles, created_at, updated_at)
+		VALUES (?1, ?2, '', ?3, ?4, ?4)
+		ON CONFLICT DO NOTHING`,
+		account.ID, account.Email, stringList(account.Roles), time.Now().UTC())
+	return err
+}
+
+// stringList stores a list of strings as a JSON array, where PostgreSQL has a TEXT[]
+// column. Like pq.Array, it is passed as stringList(list) and scanned into
+// (*stringList)(&list).
+type stringList []string
+
+func (l stringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	encoded, err := json.Marshal([]string(l))
+	return string(encoded), err
+}
+
+func (l *stringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), (*[]string)(l))
+	case []byte:
+		return json.Unmarshal(v, (*[]string)(l))
+	}
+	return fmt.Errorf("can't scan %T into a string list", value)
+}
+
+// nullTime is sql.NullTime for SQLite. Columns declared DATETIME come back as times, but
+// computed values such as MAX(updated_at) come back as the text the times are stored as.
+type nullTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+func (t *nullTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		t.Time, t.Valid = time.Time{}, false
+		return nil
+	case time.Time:
+		t.Time, t.Valid = v, true
+		return nil
+	case []byte:
+		return t.parse(string(v))
+	case string:
+		return t.parse(v)
+	}
+	return fmt.Errorf("can't scan %T into a time", value)
+}
+
+// parse reads a time in one of the formats the driver reads DATETIME columns in
+func (t *nullTime) parse(value string) error {
+	value = strings.TrimSuffix(value, "Z")
+	for _, format := range sqlite3.SQLiteTimestampFormats {
+		if parsed, err := time.ParseInLocation(format, value, time.UTC); err == nil {
+			t.Time, t.Valid = parsed, true
+			return nil
+		}
+	}
+	return fmt.Errorf("can't parse %q as a time", value)
+}
+
+// timeValue scans a computed time that is never NULL into a time.Time, passed as
+// (*timeValue)(&t)
+type timeValue time.Time
+
+func (t *timeValue) Scan(value interface{}) error {
+	var scanned nullTime
+	if err := scanned.Scan(value); err != nil {
+		return err
+	}
+	if !scanned.Valid {
+		return fmt.Errorf("can't scan NULL into a time")
+	}
+	*t = timeValue(scanned.Time)
+	return nil
+}
+
+// utc returns t in UTC, or nil. Times are bound as text with their zone, so every time
+// is written in UTC to keep the text in time order.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	in := t.UTC()
+	return &in
+}
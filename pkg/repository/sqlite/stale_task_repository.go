@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type staleTaskRepository struct {
+	db *sql.DB
+}
+
+// NewStaleTaskRepository creates a new SQLite stale task repository
+func NewStaleTaskRepository(db *sql.DB) repository.StaleTaskRepository {
+	return &staleTaskRepository{db: db}
+}
+
+// GetProjectSettings returns the tenant's settings of the project; each tenant configures
+// its projects separately
+func (r *staleTaskRepository) GetProjectSettings(ctx context.Context, projectKey string) (*models.ProjectSettings, error) {
+	settings := &models.ProjectSettings{ProjectKey: projectKey}
+	var staleAfterDays sql.NullInt64
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx,
+		`SELECT stale_after_days, search_language, updated_by, updated_at FROM project_settings
+		WHERE project_key = ? AND COALESCE(org_id, '') = ?`,
+		projectKey, tenantOrgID(ctx, "")).Scan(&staleAfterDays, &settings.SearchLanguage, &settings.UpdatedBy, &updatedAt)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if staleAfterDays.Valid {
+		days := int(staleAfterDays.Int64)
+		settings.StaleAfterDays = &days
+	}
+	settings.UpdatedAt = &updatedAt
+	return settings, nil
+}
+
+// SaveProjectSettings keeps the search language for the PostgreSQL backend; the SQLite
+// searcher matches every language alike
+func (r *staleTaskRepository) SaveProjectSettings(ctx context.Context, settings *models.ProjectSettings) (*models.ProjectSettings, error) {
+	query := `
+		INSERT INTO project_settings (project_key, stale_after_days, search_language, updated_by, updated_at, org_id)
+		VALUES (?, ?, ?, ?, ?, NULLIF(?, ''))
+		ON CONFLICT (COALESCE(org_id, ''), project_key) DO UPDATE
+		SET stale_after_days = excluded.stale_after_days,
+			search_language = excluded.search_language,
+			updated_by = excluded.updated_by,
+			updated_at = excluded.updated_at
+		RETURNING project_key, stale_after_days, search_language, updated_by, updated_at`
+
+	result := &models.ProjectSettings{}
+	var staleAfterDays sql.NullInt64
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, settings.ProjectKey, settings.StaleAfterDays, settings.SearchLanguage,
+		settings.UpdatedBy, time.Now().UTC(), tenantOrgID(ctx, "")).
+		Scan(&result.ProjectKey, &staleAfterDays, &result.SearchLanguage, &result.UpdatedBy, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if staleAfterDays.Valid {
+		days := int(staleAfterDays.Int64)
+		result.StaleAfterDays = &days
+	}
+	result.UpdatedAt = &updatedAt
+	return result, nil
+}
+
+func (r *staleTaskRepository) MarkStale(ctx context.Context, now time.Time, defaultDays int) ([]*models.Task, error) {
+	// A single statement, so a task is never flagged (and reported) twice. Setting
+	// stale_at leaves updated_at alone, so the task stays stale until updated.
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE tasks
+		SET stale_at = ?1
+		WHERE id IN (
+			SELECT t.id
+			FROM tasks t
+			LEFT JOIN project_settings s
+				ON s.project_key = t.project_key AND COALESCE(s.org_id, '') = COALESCE(t.org_id, '')
+			WHERE t.status IN ('pending', 'in_progress')
+				AND (t.stale_at IS NULL OR t.stale_at < t.updated_at)
+				AND COALESCE(s.stale_after_days, ?2) > 0
+				AND unixepoch(t.updated_at, 'subsec') < unixepoch(?1, 'subsec') - COALESCE(s.stale_after_days, ?2) * 86400
+		)
+		RETURNING `+taskColumns, now.UTC(), defaultDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/tenant"
+)
+
+func (r *taskRepository) SaveDraft(ctx context.Context, id string, draft *models.TaskDraft) (*models.Task, error) {
+	metadata, err := encodeMetadata(draft.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM tasks WHERE id = ?)`, id).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *models.Task
+	if exists {
+		result, err = updateDraft(ctx, tx, id, draft, metadata)
+	} else {
+		result, err = insertDraft(ctx, tx, id, draft, metadata)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := replaceTags(ctx, tx, id, draft.Tags); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	result.Tags = draft.Tags
+	return result, nil
+}
+
+// insertDraft numbers a new draft in its project and inserts it
+func insertDraft(ctx context.Context, tx *sql.Tx, id string, draft *models.TaskDraft, metadata string) (*models.Task, error) {
+	number, err := nextTaskNumber(ctx, tx, draft.ProjectKey)
+	if err != nil {
+		return nil, err
+	}
+	slug, err := uniqueSlug(ctx, tx, tenantOrgID(ctx, draft.OrgID), draft.ProjectKey, models.Slugify(draft.Title), id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	return scanTask(tx.QueryRowContext(ctx, `
+		INSERT INTO tasks (id, project_key, number, slug, title, description, status, priority, due_date, owner_id, assignee_id, parent_id, metadata, created_at, updated_at, org_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), ?, ?, ?, NULLIF(?, ''))
+		RETURNING `+taskColumns,
+		id,
+		draft.ProjectKey,
+		number,
+		slug,
+		draft.Title,
+		draft.Description,
+		models.StatusDraft,
+		draft.Priority,
+		utc(draft.DueDate),
+		draft.OwnerID,
+		draft.AssigneeID,
+		draft.ParentID,
+		metadata,
+		now,
+		now,
+		tenantOrgID(ctx, draft.OrgID),
+	))
+}
+
+// updateDraft replaces the content of a draft. Its slug follows the title without
+// keeping a history, since nobody links to a draft.
+func updateDraft(ctx context.Context, tx *sql.Tx, id string, draft *models.TaskDraft, metadata string) (*models.Task, error) {
+	slug, err := uniqueSlug(ctx, tx, tenantOrgID(ctx, draft.OrgID), draft.ProjectKey, models.Slugify(draft.Title), id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := scanTask(tx.QueryRowContext(ctx, `
+		UPDATE tasks
+		SET title = ?1,
+			description = ?2,
+			priority = ?3,
+			due_date = ?4,
+			assignee_id = NULLIF(?5, ''),
+			parent_id = NULLIF(?6, ''),
+			slug = ?7,
+			metadata = ?8,
+			updated_at = ?9
+		WHERE id = ?10 AND status = 'draft' AND owner_id = ?11 AND project_key = ?12 AND `+tenantMatch("", 13)+`
+		RETURNING `+taskColumns,
+		draft.Title,
+		draft.Description,
+		draft.Priority,
+		utc(draft.DueDate),
+		draft.AssigneeID,
+		draft.ParentID,
+		slug,
+		metadata,
+		time.Now().UTC(),
+		id,
+		draft.OwnerID,
+		draft.ProjectKey,
+		tenant.Arg(ctx),
+	))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("draft not found")
+	}
+	return result, err
+}
+
+func (r *taskRepository) PublishDraft(ctx context.Context, id string, status models.TaskStatus) (*models.Task, error) {
+	task, err := scanTask(r.db.QueryRowContext(ctx, `
+		UPDATE tasks
+		SET status = ?1,
+			assigned_at = CASE WHEN assignee_id IS NOT NULL THEN ?2 END,
+			updated_at = ?2,
+			`+slaTimes("?1", "?2")+`
+		WHERE id = ?3 AND status = 'draft' AND `+tenantMatch("", 4)+`
+		RETURNING `+taskColumns, status, time.Now().UTC(), id, tenant.Arg(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("draft not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadTags(ctx, r.db, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
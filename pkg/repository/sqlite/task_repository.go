@@ -0,0 +1,769 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// taskColumns lists the columns read by scanTask, in order
+const taskColumns = "id, project_key, number, slug, title, description, status, priority, due_date, owner_id, assignee_id, parent_id, metadata, checklist_total, checklist_done, estimated_minutes, logged_minutes, stale_at, overdue_at, position, created_at, updated_at, snoozed_until, sla_respond_within, sla_resolve_within, responded_at, resolved_at, team_id, org_id"
+
+// positionGap is the room left between the positions of tasks added to a board column,
+// as in the PostgreSQL repository
+const positionGap = 1024
+
+// qualifiedTaskColumns lists taskColumns qualified with a table alias, e.g. "t.id, t.title"
+func qualifiedTaskColumns(alias string) string {
+	return alias + "." + strings.ReplaceAll(taskColumns, ", ", ", "+alias+".")
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTask reads a task selected with taskColumns
+func scanTask(row rowScanner) (*models.Task, error) {
+	task := &models.Task{}
+	var ownerID, assigneeID, parentID, teamID, orgID sql.NullString
+	var dueDate nullTime
+	var metadata string
+	var checklistTotal, checklistDone int
+	var estimatedMinutes sql.NullInt64
+	var staleAt, overdueAt, snoozedUntil nullTime
+	var respondWithin, resolveWithin sql.NullInt64
+	var respondedAt, resolvedAt nullTime
+	err := row.Scan(
+		&task.ID,
+		&task.ProjectKey,
+		&task.Number,
+		&task.Slug,
+		&task.Title,
+		&task.Description,
+		&task.Status,
+		&task.Priority,
+		&dueDate,
+		&ownerID,
+		&assigneeID,
+		&parentID,
+		&metadata,
+		&checklistTotal,
+		&checklistDone,
+		&estimatedMinutes,
+		&task.LoggedMinutes,
+		&staleAt,
+		&overdueAt,
+		&task.Position,
+		(*timeValue)(&task.CreatedAt),
+		(*timeValue)(&task.UpdatedAt),
+		&snoozedUntil,
+		&respondWithin,
+		&resolveWithin,
+		&respondedAt,
+		&resolvedAt,
+		&teamID,
+		&orgID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	task.DueDate = dueDate.Time // drafts may not have one yet
+	task.OwnerID = ownerID.String
+	task.AssigneeID = assigneeID.String
+	task.TeamID = teamID.String
+	task.ParentID = parentID.String
+	task.OrgID = orgID.String
+	if err := json.Unmarshal([]byte(metadata), &task.Metadata); err != nil {
+		return nil, err
+	}
+	if len(task.Metadata) == 0 {
+		task.Metadata = nil
+	}
+	task.Checklist = models.NewChecklistProgress(checklistTotal, checklistDone)
+	if estimatedMinutes.Valid {
+		minutes := int(estimatedMinutes.Int64)
+		task.EstimatedMinutes = &minutes
+	}
+	// Flags from before the latest update no longer apply
+	if staleAt.Valid && !staleAt.Time.Before(task.UpdatedAt) && task.IsOpen() {
+		task.StaleSince = &staleAt.Time
+	}
+	// Flags from before the due date was moved later no longer apply
+	if overdueAt.Valid && !overdueAt.Time.Before(task.DueDate) && task.IsOpen() {
+		task.OverdueSince = &overdueAt.Time
+	}
+	// Snoozes that ran out no longer apply
+	if snoozedUntil.Valid && snoozedUntil.Time.After(time.Now()) {
+		task.SnoozedUntil = &snoozedUntil.Time
+	}
+	// The SLA's targets are evaluated by the service layer
+	if respondWithin.Valid || resolveWithin.Valid {
+		task.SLA = models.NewTaskSLA(nullSeconds(respondWithin), nullSeconds(resolveWithin))
+		if respondedAt.Valid {
+			task.SLA.RespondedAt = &respondedAt.Time
+		}
+		if resolvedAt.Valid {
+			task.SLA.ResolvedAt = &resolvedAt.Time
+		}
+	}
+	task.Key = models.FormatTaskKey(task.ProjectKey, task.Number)
+	return task, nil
+}
+
+// nullSeconds reads a nullable number of seconds as a duration
+func nullSeconds(value sql.NullInt64) *time.Duration {
+	if !value.Valid {
+		return nil
+	}
+	duration := time.Duration(value.Int64) * time.Second
+	return &duration
+}
+
+// slaTimes sets responded_at and resolved_at for the status expression a task is updated
+// to, at the time parameter now, as the tasks_sla_times triggers do. Statements
+// returning the task use it, since RETURNING doesn't see what the triggers change.
+func slaTimes(status, now string) string {
+	return fmt.Sprintf(`responded_at = CASE WHEN %[1]s NOT IN ('pending', 'draft') THEN COALESCE(responded_at, %[2]s) ELSE responded_at END,
+			resolved_at = CASE WHEN %[1]s IN ('completed', 'cancelled') THEN COALESCE(resolved_at, %[2]s) END`, status, now)
+}
+
+type taskRepository struct {
+	db *sql.DB
+}
+
+// tenantMatch restricts tasks to the tenant passed as parameter n: the tenant's own
+// tasks, or the tasks without an organization for the default tenant. alias qualifies
+// org_id, e.g. "t." or "" for none. Unscoped contexts pass NULL, see tenant.Arg, and
+// match every task. Every query reading or changing tasks applies it.
+func tenantMatch(alias string, n int) string {
+	return fmt.Sprintf("(?%d IS NULL OR COALESCE(%sorg_id, '') = ?%d)", n, alias, n)
+}
+
+// taskInTenant restricts rows referencing a task by task_id to the tasks tenantMatch
+// allows for parameter n, for task data without an org_id of its own
+func taskInTenant(alias string, n int) string {
+	return fmt.Sprintf("%stask_id IN (SELECT id FROM tasks WHERE %s)", alias, tenantMatch("", n))
+}
+
+// tenantOrgID returns the organization new tasks belong to: the tenant of ctx, or
+// orgID in unscoped contexts such as import jobs
+func tenantOrgID(ctx context.Context, orgID string) string {
+	if id, ok := tenant.FromContext(ctx); ok {
+		return id
+	}
+	return orgID
+}
+
+// NewTaskRepository creates a new SQLite task repository over a database opened with
+// Open
+func NewTaskRepository(db *sql.DB) repository.TaskRepository {
+	return &taskRepository{db: db}
+}
+
+func (r *taskRepository) Create(ctx context.Context, task *models.TaskCreate) (*models.Task, error) {
+	created, err := r.CreateBatch(ctx, []*models.TaskCreate{task})
+	if err != nil {
+		return nil, err
+	}
+	return created[0], nil
+}
+
+// CreateBatch creates the tasks in one transaction, so a batch costs a single commit
+func (r *taskRepository) CreateBatch(ctx context.Context, tasks []*models.TaskCreate) ([]*models.Task, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	created := make([]*models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		result, err := createTask(ctx, tx, task, now)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, result)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// createTask inserts a task with its tags and first description version in tx, at the
+// bottom of its board column
+func createTask(ctx context.Context, tx *sql.Tx, task *models.TaskCreate, now time.Time) (*models.Task, error) {
+	id := uuid.New().String()
+
+	number, err := nextTaskNumber(ctx, tx, task.ProjectKey)
+	if err != nil {
+		return nil, err
+	}
+	orgID := tenantOrgID(ctx, task.OrgID)
+	slug, err := uniqueSlug(ctx, tx, orgID, task.ProjectKey, models.Slugify(task.Title), id)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := encodeMetadata(task.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := scanTask(tx.QueryRowContext(ctx, `
+		INSERT INTO tasks (id, project_key, number, slug, title, description, status, priority, due_date, owner_id, assignee_id, assigned_at, parent_id, metadata, estimated_minutes, position, created_at, updated_at, org_id, responded_at, resolved_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, NULLIF(?10, ''), NULLIF(?11, ''), CASE WHEN ?11 <> '' THEN ?15 END, NULLIF(?12, ''), ?13, NULLIF(?14, 0),
+			(SELECT COALESCE(MAX(position), 0) + ?16 FROM tasks WHERE project_key = ?2 AND status = ?7), ?15, ?15, NULLIF(?17, ''),
+			CASE WHEN ?7 NOT IN ('pending', 'draft') THEN ?15 END, CASE WHEN ?7 IN ('completed', 'cancelled') THEN ?15 END)
+		RETURNING `+taskColumns,
+		id,
+		task.ProjectKey,
+		number,
+		slug,
+		task.Title,
+		task.Description,
+		task.Status,
+		task.Priority,
+		task.DueDate.UTC(),
+		task.OwnerID,
+		task.AssigneeID,
+		task.ParentID,
+		metadata,
+		task.EstimatedMinutes,
+		now,
+		positionGap,
+		orgID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := replaceTags(ctx, tx, id, task.Tags); err != nil {
+		return nil, err
+	}
+	if err := recordDescription(ctx, tx, id, "", task.Description, task.OwnerID); err != nil {
+		return nil, err
+	}
+	result.Tags = task.Tags
+	return result, nil
+}
+
+func (r *taskRepository) GetByID(ctx context.Context, id string) (*models.Task, error) {
+	return r.getTask(ctx, `WHERE id = ?1 AND `+tenantMatch("", 2), id, tenant.Arg(ctx))
+}
+
+func (r *taskRepository) GetByKey(ctx context.Context, projectKey string, number int64) (*models.Task, error) {
+	return r.getTask(ctx, `WHERE project_key = ?1 AND number = ?2 AND `+tenantMatch("", 3),
+		projectKey, number, tenant.Arg(ctx))
+}
+
+func (r *taskRepository) GetBySlug(ctx context.Context, projectKey, slug string) (*models.Task, error) {
+	// The current slug wins over a slug the task or another task had before a rename
+	return r.getTask(ctx, `
+		WHERE project_key = ?1 AND (slug = ?2 OR id IN (
+			SELECT task_id FROM task_slug_history WHERE project_key = ?1 AND slug = ?2 AND `+tenantMatch("", 3)+`
+		)) AND `+tenantMatch("", 3)+`
+		ORDER BY slug = ?2 DESC
+		LIMIT 1`, projectKey, slug, tenant.Arg(ctx))
+}
+
+// getTask reads the task selected by the rest of a query, with its tags
+func (r *taskRepository) getTask(ctx context.Context, rest string, args ...interface{}) (*models.Task, error) {
+	task, err := scanTask(r.db.QueryRowContext(ctx, `SELECT `+taskColumns+` FROM tasks `+rest, args...))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadTags(ctx, r.db, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// nextTaskNumber hands out the next number of a project. Writes are serialized by
+// SQLite, so concurrent creates in the same project are numbered one after another.
+func nextTaskNumber(ctx context.Context, tx *sql.Tx, projectKey string) (int64, error) {
+	var number int64
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO project_task_counters (project_key, last_number)
+		VALUES (?, 1)
+		ON CONFLICT (project_key) DO UPDATE SET last_number = last_number + 1
+		RETURNING last_number`, projectKey).Scan(&number)
+	return number, err
+}
+
+func (r *taskRepository) Update(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error) {
+	var metadata *string
+	if task.Metadata != nil {
+		encoded, err := encodeMetadata(task.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		metadata = &encoded
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var previousDescription string
+	if task.Description != nil {
+		if previousDescription, err = currentDescription(ctx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	var slug *string
+	if task.Title != nil {
+		renamed, err := renameSlug(ctx, tx, id, *task.Title)
+		if err != nil {
+			return nil, err
+		}
+		slug = &renamed
+	}
+
+	// An empty assignee or parent clears it. Drafts are only changed with SaveDraft.
+	// Changing the assignee restarts assigned_at.
+	result, err := scanTask(tx.QueryRowContext(ctx, `
+		UPDATE tasks
+		SET title = COALESCE(?1, title),
+			description = COALESCE(?2, description),
+			status = COALESCE(?3, status),
+			priority = COALESCE(?4, priority),
+			due_date = COALESCE(?5, due_date),
+			assignee_id = NULLIF(COALESCE(?6, assignee_id), ''),
+			assigned_at = CASE
+				WHEN NULLIF(COALESCE(?6, assignee_id), '') IS NOT assignee_id THEN ?10
+				ELSE assigned_at
+			END,
+			parent_id = NULLIF(COALESCE(?7, parent_id), ''),
+			slug = COALESCE(?8, slug),
+			metadata = COALESCE(?9, metadata),
+			updated_at = ?10,
+			estimated_minutes = NULLIF(COALESCE(?12, estimated_minutes), 0),
+			`+slaTimes("COALESCE(?3, status)", "?10")+`
+		WHERE id = ?11 AND status <> 'draft' AND `+tenantMatch("", 13)+`
+		RETURNING `+taskColumns,
+		task.Title,
+		task.Description,
+		task.Status,
+		task.Priority,
+		utc(task.DueDate),
+		task.AssigneeID,
+		task.ParentID,
+		slug,
+		metadata,
+		time.Now().UTC(),
+		id,
+		task.EstimatedMinutes,
+		tenant.Arg(ctx),
+	))
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Tags != nil {
+		if err := replaceTags(ctx, tx, id, *task.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if task.Description != nil {
+		if err := recordDescription(ctx, tx, id, previousDescription, *task.Description, task.EditorID); err != nil {
+			return nil, err
+		}
+	}
+	if err := loadTags(ctx, tx, result); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *taskRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM tasks WHERE id = ?1 AND `+tenantMatch("", 2), id, tenant.Arg(ctx))
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return models.ErrTaskNotFound
+	}
+	return nil
+}
+
+// taskConditions builds the WHERE clause selecting a filter's tasks in the tenant of ctx,
+// with its parameters. The tenant is the first parameter, ?1.
+func taskConditions(ctx context.Context, filter repository.TaskFilter) (string, []interface{}) {
+	conditions := []string{tenantMatch("", 1)}
+	params := []interface{}{tenant.Arg(ctx)}
+
+	if len(filter.Status) > 0 {
+		conditions = append(conditions, "status IN ("+placeholders(len(filter.Status))+")")
+		for _, status := range filter.Status {
+			params = append(params, status)
+		}
+	}
+	if filter.Priority != "" {
+		conditions = append(conditions, "priority = ?")
+		params = append(params, filter.Priority)
+	}
+	if filter.AssigneeID != "" {
+		conditions = append(conditions, "assignee_id = ?")
+		params = append(params, filter.AssigneeID)
+	}
+	if filter.TeamID != "" {
+		conditions = append(conditions, "team_id = ?")
+		params = append(params, filter.TeamID)
+	}
+	if filter.ProjectKey != "" {
+		conditions = append(conditions, "project_key = ?")
+		params = append(params, filter.ProjectKey)
+	}
+	if filter.OwnerID != "" {
+		conditions = append(conditions, "owner_id = ?")
+		params = append(params, filter.OwnerID)
+	}
+	if filter.DueAfter != nil {
+		conditions = append(conditions, "due_date >= ?")
+		params = append(params, filter.DueAfter.UTC())
+	}
+	if filter.DueBefore != nil {
+		conditions = append(conditions, "due_date < ?")
+		params = append(params, filter.DueBefore.UTC())
+	}
+	if len(filter.Tags) > 0 {
+		conditions = append(conditions,
+			"id IN (SELECT task_id FROM task_tags WHERE tag IN ("+placeholders(len(filter.Tags))+") GROUP BY task_id HAVING COUNT(*) = ?)")
+		for _, tag := range filter.Tags {
+			params = append(params, tag)
+		}
+		params = append(params, len(filter.Tags))
+	}
+	if filter.Stale {
+		conditions = append(conditions, "stale_at >= updated_at AND status IN ('pending', 'in_progress')")
+	}
+	switch filter.Snoozed {
+	case repository.SnoozedHidden:
+		conditions = append(conditions, "(snoozed_until IS NULL OR snoozed_until <= ?)")
+		params = append(params, time.Now().UTC())
+	case repository.SnoozedOnly:
+		conditions = append(conditions, "snoozed_until > ?")
+		params = append(params, time.Now().UTC())
+	}
+	if len(filter.IDs) > 0 {
+		conditions = append(conditions, "id IN ("+placeholders(len(filter.IDs))+")")
+		for _, id := range filter.IDs {
+			params = append(params, id)
+		}
+	}
+	// Sorted so the same filter always yields the same query
+	metadataKeys := make([]string, 0, len(filter.Metadata))
+	for key := range filter.Metadata {
+		metadataKeys = append(metadataKeys, key)
+	}
+	sort.Strings(metadataKeys)
+	for _, key := range metadataKeys {
+		conditions = append(conditions, "CAST(metadata ->> ? AS TEXT) = ?")
+		params = append(params, key, filter.Metadata[key])
+	}
+	conditions = append(conditions, "(status <> 'draft' OR owner_id = ?)")
+	params = append(params, filter.Viewer)
+	if filter.StarredBy != "" {
+		conditions = append(conditions, "id IN (SELECT task_id FROM task_stars WHERE user_id = ?)")
+		params = append(params, filter.StarredBy)
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), params
+}
+
+// placeholders returns n comma-separated parameter placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func (r *taskRepository) List(ctx context.Context, filter repository.TaskFilter) ([]*models.Task, int, error) {
+	whereClause, params := taskConditions(ctx, filter)
+
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks`+whereClause, params...).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks` + whereClause + ` ORDER BY ` + orderBy(filter)
+	if filter.Limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		params = append(params, filter.Limit, (filter.Page-1)*filter.Limit)
+	}
+	tasks, err := fetchTasks(ctx, r.db, query, params...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
+
+// Stream reads the matching tasks before calling fn, so fn can use the database, which
+// has a single connection
+func (r *taskRepository) Stream(ctx context.Context, filter repository.TaskFilter, fn func(task *models.Task) error) error {
+	whereClause, params := taskConditions(ctx, filter)
+	tasks, err := fetchTasks(ctx, r.db, `
+		SELECT `+taskColumns+`
+		FROM tasks`+whereClause+`
+		ORDER BY `+orderBy(filter)+`, id
+		LIMIT -1 OFFSET ?`, append(params, filter.Offset)...)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// priorityRank orders priorities from low (1) to critical (4)
+const priorityRank = `CASE priority WHEN 'critical' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 ELSE 1 END`
+
+// orderBy builds the ORDER BY clause for a validated filter, newest first on ties.
+// Only the fixed expressions below reach the query, never the filter's own strings.
+func orderBy(filter repository.TaskFilter) string {
+	column, order := "created_at", "DESC"
+	switch filter.Sort {
+	case repository.SortUpdatedAt:
+		column = "updated_at"
+	case repository.SortDueDate:
+		column, order = "due_date", "ASC"
+	case repository.SortPriority:
+		column = priorityRank
+	case repository.SortTitle:
+		column, order = "LOWER(title)", "ASC"
+	case repository.SortPosition:
+		column, order = "position", "ASC"
+	}
+	switch filter.Order {
+	case "asc":
+		order = "ASC"
+	case "desc":
+		order = "DESC"
+	}
+
+	if column == "created_at" {
+		return "created_at " + order
+	}
+	return column + " " + order + ", created_at DESC"
+}
+
+func (r *taskRepository) ListSubtasks(ctx context.Context, parentID string, recursive bool) ([]*models.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE parent_id = ?1 AND ` + tenantMatch("", 2) + `
+		ORDER BY created_at`
+	if recursive {
+		// UNION (not UNION ALL) stops at rows already visited, so cyclic data can't loop forever
+		query = `
+		WITH RECURSIVE subtasks AS (
+			SELECT ` + taskColumns + ` FROM tasks WHERE parent_id = ?1 AND ` + tenantMatch("", 2) + `
+			UNION
+			SELECT ` + qualifiedTaskColumns("t") + `
+			FROM tasks t
+			JOIN subtasks s ON t.parent_id = s.id
+			WHERE ` + tenantMatch("t.", 2) + `
+		)
+		SELECT ` + taskColumns + `
+		FROM subtasks
+		ORDER BY created_at`
+	}
+
+	return fetchTasks(ctx, r.db, query, parentID, tenant.Arg(ctx))
+}
+
+func (r *taskRepository) ListAncestorIDs(ctx context.Context, id string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_id FROM tasks WHERE id = ?1 AND parent_id IS NOT NULL AND `+tenantMatch("", 2)+`
+			UNION
+			SELECT t.parent_id
+			FROM tasks t
+			JOIN ancestors a ON t.id = a.parent_id
+			WHERE t.parent_id IS NOT NULL AND `+tenantMatch("t.", 2)+`
+		)
+		SELECT parent_id FROM ancestors`, id, tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var ancestorID string
+		if err := rows.Scan(&ancestorID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, ancestorID)
+	}
+	return ids, rows.Err()
+}
+
+func (r *taskRepository) UpdateSubtaskStatus(ctx context.Context, parentID string, status models.TaskStatus) ([]*models.Task, error) {
+	return fetchTasks(ctx, r.db, `
+		WITH RECURSIVE subtasks AS (
+			SELECT id FROM tasks WHERE parent_id = ?1 AND `+tenantMatch("", 4)+`
+			UNION
+			SELECT t.id
+			FROM tasks t
+			JOIN subtasks s ON t.parent_id = s.id
+			WHERE `+tenantMatch("t.", 4)+`
+		)
+		UPDATE tasks
+		SET status = ?2,
+			updated_at = ?3,
+			`+slaTimes("?2", "?3")+`
+		WHERE id IN (SELECT id FROM subtasks) AND status <> 'draft'
+		RETURNING `+taskColumns, parentID, status, time.Now().UTC(), tenant.Arg(ctx))
+}
+
+// fetchTasks runs a query selecting taskColumns on q, scans every row and loads the
+// tasks' tags. The rows are closed before the tags are read, since the database has a
+// single connection.
+func fetchTasks(ctx context.Context, q querier, query string, args ...interface{}) ([]*models.Task, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := loadTags(ctx, q, tasks...); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) ListTags(ctx context.Context) ([]models.TagCount, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT tag, COUNT(*)
+		FROM task_tags
+		WHERE task_id IN (SELECT id FROM tasks WHERE status <> 'draft' AND `+tenantMatch("", 1)+`)
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag`, tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.TagCount{}
+	for rows.Next() {
+		var tag models.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// querier is implemented by *sql.DB and *sql.Tx
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// encodeMetadata returns metadata as a JSON object for the metadata column
+func encodeMetadata(metadata map[string]interface{}) (string, error) {
+	if metadata == nil {
+		return "{}", nil
+	}
+	encoded, err := json.Marshal(metadata)
+	return string(encoded), err
+}
+
+// replaceTags sets the tags of a task, removing any it had before
+func replaceTags(ctx context.Context, q querier, taskID string, tags []string) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM task_tags WHERE task_id = ?`, taskID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		_, err := q.ExecContext(ctx,
+			`INSERT INTO task_tags (task_id, tag) VALUES (?, ?) ON CONFLICT DO NOTHING`, taskID, tag)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTags fills in the tags of tasks with a single query
+func loadTags(ctx context.Context, q querier, tasks ...*models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.Task, len(tasks))
+	ids := make([]interface{}, 0, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+		ids = append(ids, task.ID)
+	}
+
+	rows, err := q.QueryContext(ctx,
+		`SELECT task_id, tag FROM task_tags WHERE task_id IN (`+placeholders(len(ids))+`) ORDER BY tag`, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var taskID, tag string
+		if err := rows.Scan(&taskID, &tag); err != nil {
+			return err
+		}
+		if task, ok := byID[taskID]; ok {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+	return rows.Err()
+}
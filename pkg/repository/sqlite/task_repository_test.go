@@ -0,0 +1,131 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/repository/memory"
+)
+
+func TestOpen_MigratesOnce(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "dev.db")
+
+	db, err := Open(ctx, path)
+	require.NoError(t, err)
+	require.NoError(t, Seed(ctx, db, memory.TaskFixtures("demo")))
+	_, err = NewTaskRepository(db).Create(ctx, &models.TaskCreate{ProjectKey: "TASK", Title: "Kept",
+		Status: models.StatusPending, Priority: models.PriorityLow, OwnerID: "demo"})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// Reopening applies nothing twice and seeding leaves the changed database alone
+	db, err = Open(ctx, path)
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, Seed(ctx, db, memory.TaskFixtures("demo")))
+
+	var migrations int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&migrations))
+	assert.Equal(t, 2, migrations)
+
+	task, err := NewTaskRepository(db).GetByKey(ctx, "TASK", 9)
+	require.NoError(t, err)
+	assert.Equal(t, "Kept", task.Title)
+}
+
+func TestTaskRepository(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(ctx, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, Seed(ctx, db, memory.TaskFixtures("demo")))
+	repo := NewTaskRepository(db)
+
+	fixture := memory.TaskFixtures("demo")[0]
+	task, err := repo.GetByID(ctx, fixture.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fixture.Title, task.Title)
+	assert.Equal(t, fixture.Tags, task.Tags)
+	assert.True(t, fixture.CreatedAt.Equal(task.CreatedAt))
+
+	created, err := repo.Create(ctx, &models.TaskCreate{ProjectKey: "TASK", Title: fixture.Title,
+		Status: models.StatusPending, Priority: models.PriorityHigh, OwnerID: "demo",
+		DueDate: time.Now().Add(time.Hour), Tags: []string{"release"}, Metadata: map[string]interface{}{"seats": 5}})
+	require.NoError(t, err)
+	assert.Equal(t, "TASK-9", created.Key)
+	assert.Equal(t, fixture.Slug+"-2", created.Slug)
+
+	title := "Renamed"
+	updated, err := repo.Update(ctx, created.ID, &models.TaskUpdate{Title: &title, Tags: &[]string{"bug"}})
+	require.NoError(t, err)
+	assert.Equal(t, "renamed", updated.Slug)
+	assert.Equal(t, []string{"bug"}, updated.Tags)
+	found, err := repo.GetBySlug(ctx, "TASK", "renamed")
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+
+	tasks, total, err := repo.List(ctx, repository.TaskFilter{Page: 1, Limit: 2, Tags: []string{"release"},
+		Metadata: map[string]string{}, Sort: repository.SortTitle})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Freeze the API surface", tasks[0].Title)
+
+	tasks, _, err = repo.List(ctx, repository.TaskFilter{Page: 1, Limit: 10, Metadata: map[string]string{"seats": "5"}})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, created.ID, tasks[0].ID)
+
+	// Drafts are only listed for their owner
+	_, total, err = repo.List(ctx, repository.TaskFilter{Page: 1, Limit: 10, Viewer: "someone-else"})
+	require.NoError(t, err)
+	assert.Equal(t, 8, total)
+
+	subtasks, err := repo.ListSubtasks(ctx, fixture.ID, true)
+	require.NoError(t, err)
+	assert.Len(t, subtasks, 2)
+	ancestors, err := repo.ListAncestorIDs(ctx, subtasks[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{fixture.ID}, ancestors)
+	changed, err := repo.UpdateSubtaskStatus(ctx, fixture.ID, models.StatusCompleted)
+	require.NoError(t, err)
+	assert.Len(t, changed, 2)
+
+	tags, err := repo.ListTags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, models.TagCount{Tag: "release", Count: 3}, tags[0])
+
+	require.NoError(t, repo.Delete(ctx, created.ID))
+	_, err = repo.GetByID(ctx, created.ID)
+	assert.ErrorIs(t, err, models.ErrTaskNotFound)
+}
+
+func TestTaskRepository_PublishDraft(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(ctx, ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	repo := NewTaskRepository(db)
+
+	draft, err := repo.SaveDraft(ctx, "draft-1", &models.TaskDraft{ProjectKey: "TASK", Title: "Draft", OwnerID: "demo"})
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusDraft, draft.Status)
+	_, err = repo.SaveDraft(ctx, "draft-1", &models.TaskDraft{ProjectKey: "TASK", Title: "Draft, edited", OwnerID: "demo"})
+	require.NoError(t, err)
+
+	published, err := repo.PublishDraft(ctx, "draft-1", models.StatusPending)
+	require.NoError(t, err)
+	assert.Equal(t, "TASK-1", published.Key)
+	assert.Equal(t, "Draft, edited", published.Title)
+
+	_, err = repo.PublishDraft(ctx, "draft-1", models.StatusPending)
+	assert.Error(t, err)
+}
@@ -0,0 +1,203 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// keyMatch matches the task whose key equals the search text
+const keyMatch = "project_key || '-' || number = upper(trim(?1))"
+
+// relatedThreshold is the word similarity above which Related counts a title or
+// description as similar, as pg_trgm's default similarity threshold does
+const relatedThreshold = 0.3
+
+type taskSearcher struct {
+	db *sql.DB
+}
+
+// NewTaskSearcher creates a task searcher over a database opened with Open. SQLite has
+// no full-text search without a build tag, so tasks match when their title or
+// description contains every word of the text, in any language, and rank by the words
+// found in the title.
+func NewTaskSearcher(db *sql.DB) repository.TaskSearcher {
+	return &taskSearcher{db: db}
+}
+
+// textMatch matches tasks containing every word of text, with its parameters numbered
+// from n, and ranks them by the words in the title
+func textMatch(text string, n int) (match, rank string, params []interface{}) {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return "0", "0", nil
+	}
+	conditions := make([]string, len(words))
+	ranks := make([]string, len(words))
+	for i, word := range words {
+		conditions[i] = fmt.Sprintf(`(title LIKE ?%[1]d ESCAPE '\' OR description LIKE ?%[1]d ESCAPE '\')`, n+i)
+		ranks[i] = fmt.Sprintf(`(title LIKE ?%d ESCAPE '\')`, n+i)
+		params = append(params, "%"+escapeLike(word)+"%")
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")", strings.Join(ranks, " + "), params
+}
+
+// escapeLike escapes the LIKE wildcards in s, for patterns with ESCAPE '\'
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func (s *taskSearcher) Search(ctx context.Context, query repository.TaskSearchQuery) (*repository.TaskSearchResult, error) {
+	if query.Language != "" && !query.Language.IsValid() {
+		return nil, fmt.Errorf("unsupported search language %q", query.Language)
+	}
+
+	// A query that is a task key such as PROJ-123 also finds that task
+	match, rank, textParams := textMatch(query.Text, 3)
+	matchClause := " WHERE (" + match + " OR " + keyMatch + ") AND status <> 'draft' AND " + tenantMatch("", 2)
+	params := append([]interface{}{query.Text, tenant.Arg(ctx)}, textParams...)
+	if query.ProjectKey != "" {
+		matchClause += fmt.Sprintf(" AND project_key = ?%d", len(params)+1)
+		params = append(params, query.ProjectKey)
+	}
+	matchParams := params
+
+	whereClause := matchClause
+	if query.Status != "" {
+		whereClause += fmt.Sprintf(" AND status = ?%d", len(params)+1)
+		params = append(params, query.Status)
+	}
+
+	var total int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM tasks`+whereClause, params...).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	// Facets ignore the status filter so clients can show counts for every status
+	facets, err := s.statusFacets(ctx, matchClause, matchParams...)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := fetchTasks(ctx, s.db, `
+		SELECT `+taskColumns+`
+		FROM tasks`+whereClause+fmt.Sprintf(`
+		ORDER BY `+keyMatch+` DESC, `+rank+` DESC, created_at DESC
+		LIMIT ?%d OFFSET ?%d`, len(params)+1, len(params)+2),
+		append(params, query.Limit, (query.Page-1)*query.Limit)...)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.TaskSearchResult{Tasks: tasks, Total: total, Facets: facets}, nil
+}
+
+// Related scores the tenant's other tasks in Go, since SQLite has no trigram similarity:
+// shared title words, half as much for shared description words, and a bonus per tag
+// shared with the source task (capped, so tags alone don't outrank the text)
+func (s *taskSearcher) Related(ctx context.Context, id string, limit int) ([]*models.Task, error) {
+	sources, err := fetchTasks(ctx, s.db, `
+		SELECT `+taskColumns+` FROM tasks WHERE id = ?1 AND `+tenantMatch("", 2), id, tenant.Arg(ctx))
+	if err != nil || len(sources) == 0 {
+		return nil, err
+	}
+	source := sources[0]
+
+	candidates, err := fetchTasks(ctx, s.db, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE id <> ?1 AND status <> 'draft' AND `+tenantMatch("", 2)+`
+		ORDER BY created_at DESC`, id, tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	sourceTags := make(map[string]bool, len(source.Tags))
+	for _, tag := range source.Tags {
+		sourceTags[tag] = true
+	}
+	type scored struct {
+		task  *models.Task
+		score float64
+	}
+	var related []scored
+	for _, task := range candidates {
+		shared := 0
+		for _, tag := range task.Tags {
+			if sourceTags[tag] {
+				shared++
+			}
+		}
+		title := wordSimilarity(task.Title, source.Title)
+		description := wordSimilarity(task.Description, source.Description)
+		if title < relatedThreshold && description < relatedThreshold && shared == 0 {
+			continue
+		}
+		related = append(related, scored{task, title + 0.5*description + 0.25*float64(min(shared, 4))})
+	}
+	// Stable, so ties stay newest first
+	sort.SliceStable(related, func(i, j int) bool { return related[i].score > related[j].score })
+
+	var tasks []*models.Task
+	for i := 0; i < len(related) && i < limit; i++ {
+		tasks = append(tasks, related[i].task)
+	}
+	return tasks, nil
+}
+
+// wordSimilarity is the share of the distinct words of a and b that both contain
+func wordSimilarity(a, b string) float64 {
+	wordsA, wordsB := words(a), words(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range wordsA {
+		if wordsB[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA)+len(wordsB)-shared)
+}
+
+// words returns the distinct lowercase words of s
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		set[word] = true
+	}
+	return set
+}
+
+// statusFacets counts matching tasks per status
+func (s *taskSearcher) statusFacets(ctx context.Context, matchClause string, params ...interface{}) (map[string]map[string]int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM tasks`+matchClause+` GROUP BY status`, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		counts[status] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return map[string]map[string]int{"status": counts}, nil
+}
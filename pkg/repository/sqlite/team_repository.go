@@ -0,0 +1,216 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// teamColumns lists the columns read by scanTeam, in order
+const teamColumns = "id, name, description, created_by, created_at, updated_at"
+
+// scanTeam reads a team selected with teamColumns
+func scanTeam(row rowScanner) (*models.Team, error) {
+	team := &models.Team{}
+	err := row.Scan(
+		&team.ID,
+		&team.Name,
+		&team.Description,
+		&team.CreatedBy,
+		&team.CreatedAt,
+		&team.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+type teamRepository struct {
+	db *sql.DB
+}
+
+// NewTeamRepository creates a new SQLite team repository
+func NewTeamRepository(db *sql.DB) repository.TeamRepository {
+	return &teamRepository{db: db}
+}
+
+func (r *teamRepository) Create(ctx context.Context, team *models.Team) (*models.Team, error) {
+	now := time.Now().UTC()
+	result, err := scanTeam(r.db.QueryRowContext(ctx, `
+		INSERT INTO teams (id, name, description, created_by, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?5)
+		ON CONFLICT (name) DO NOTHING
+		RETURNING `+teamColumns,
+		uuid.New().String(), team.Name, team.Description, team.CreatedBy, now))
+	if errors.Is(err, models.ErrTeamNotFound) {
+		return nil, models.ErrTeamNameTaken
+	}
+	return result, err
+}
+
+func (r *teamRepository) GetByID(ctx context.Context, id string) (*models.Team, error) {
+	team, err := scanTeam(r.db.QueryRowContext(ctx,
+		`SELECT `+teamColumns+` FROM teams WHERE id = ?1`, id))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id, added_by, added_at
+		FROM team_members
+		WHERE team_id = ?1
+		ORDER BY added_at, user_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	team.Members = []*models.TeamMember{}
+	for rows.Next() {
+		member := &models.TeamMember{}
+		if err := rows.Scan(&member.UserID, &member.AddedBy, &member.AddedAt); err != nil {
+			return nil, err
+		}
+		team.Members = append(team.Members, member)
+	}
+	return team, rows.Err()
+}
+
+func (r *teamRepository) List(ctx context.Context) ([]*models.Team, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+teamColumns+` FROM teams ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []*models.Team{}
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	return teams, rows.Err()
+}
+
+func (r *teamRepository) Update(ctx context.Context, id string, input *models.TeamInput) (*models.Team, error) {
+	// Checked up front so a taken name gets its own error; the unique index still guards
+	// against concurrent renames
+	var taken bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM teams WHERE name = ?1 AND id <> ?2)`, input.Name, id).Scan(&taken)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, models.ErrTeamNameTaken
+	}
+
+	return scanTeam(r.db.QueryRowContext(ctx, `
+		UPDATE teams
+		SET name = ?1, description = ?2, updated_at = ?3
+		WHERE id = ?4
+		RETURNING `+teamColumns,
+		input.Name, input.Description, time.Now().UTC(), id))
+}
+
+func (r *teamRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM teams WHERE id = ?1`, id)
+	if err != nil {
+		return err
+	}
+	return teamAffected(result)
+}
+
+func (r *teamRepository) AddMember(ctx context.Context, teamID, userID, addedBy string) error {
+	// Selecting from teams makes a missing team add nothing rather than fail on the key.
+	// The no-op update on conflict counts an existing member as a row, not a missing team.
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO team_members (team_id, user_id, added_by, added_at)
+		SELECT id, ?2, ?3, ?4 FROM teams WHERE id = ?1
+		ON CONFLICT (team_id, user_id) DO UPDATE SET team_id = excluded.team_id`,
+		teamID, userID, addedBy, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	return teamAffected(result)
+}
+
+func (r *teamRepository) RemoveMember(ctx context.Context, teamID, userID string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM team_members WHERE team_id = ?1 AND user_id = ?2`, teamID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("team member not found")
+	}
+	return nil
+}
+
+func (r *teamRepository) IsMember(ctx context.Context, teamID, userID string) (bool, error) {
+	var member bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM team_members WHERE team_id = ?1 AND user_id = ?2)`,
+		teamID, userID).Scan(&member)
+	return member, err
+}
+
+func (r *teamRepository) AssignTask(ctx context.Context, taskID, teamID string) (*models.Task, error) {
+	if teamID != "" {
+		var exists bool
+		err := r.db.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM teams WHERE id = ?1)`, teamID).Scan(&exists)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, models.ErrTeamNotFound
+		}
+	}
+
+	// Drafts are only changed with SaveDraft
+	task, err := scanTask(r.db.QueryRowContext(ctx, `
+		UPDATE tasks
+		SET team_id = NULLIF(?1, ''), updated_at = ?2
+		WHERE id = ?3 AND status <> 'draft' AND `+tenantMatch("", 4)+`
+		RETURNING `+taskColumns,
+		teamID, time.Now().UTC(), taskID, tenant.Arg(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("task not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := loadTags(ctx, r.db, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// teamAffected reports a statement on a team that changed no rows as a missing team
+func teamAffected(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return models.ErrTeamNotFound
+	}
+	return nil
+}
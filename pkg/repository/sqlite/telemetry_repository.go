@@ -0,0 +1,105 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+type telemetryRepository struct {
+	db *sql.DB
+}
+
+// NewTelemetryRepository creates a new SQLite telemetry repository
+func NewTelemetryRepository(db *sql.DB) repository.TelemetryRepository {
+	return &telemetryRepository{db: db}
+}
+
+func (r *telemetryRepository) InsertEvents(ctx context.Context, events []*models.TelemetryEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO telemetry_events (id, name, client, user_id, org_id, properties, sample_rate, occurred_at, received_at)
+		VALUES (?, ?, ?, ?, NULLIF(?, ''), ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, event := range events {
+		properties, err := json.Marshal(event.Properties)
+		if err != nil {
+			return err
+		}
+		if event.Properties == nil {
+			properties = []byte("{}")
+		}
+		if event.ID == "" {
+			event.ID = uuid.New().String()
+		}
+
+		_, err = stmt.ExecContext(ctx,
+			event.ID,
+			event.Name,
+			event.Client,
+			event.UserID,
+			event.OrgID,
+			string(properties),
+			event.SampleRate,
+			event.OccurredAt.UTC(),
+			event.ReceivedAt.UTC(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *telemetryRepository) GetSettings(ctx context.Context, orgID string) (*models.TelemetrySettings, error) {
+	settings := &models.TelemetrySettings{OrgID: orgID}
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx,
+		`SELECT opted_out, updated_by, updated_at FROM org_telemetry_settings WHERE org_id = ?`,
+		orgID).Scan(&settings.OptedOut, &settings.UpdatedBy, &updatedAt)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	settings.UpdatedAt = &updatedAt
+	return settings, nil
+}
+
+func (r *telemetryRepository) SaveSettings(ctx context.Context, settings *models.TelemetrySettings) (*models.TelemetrySettings, error) {
+	query := `
+		INSERT INTO org_telemetry_settings (org_id, opted_out, updated_by, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (org_id) DO UPDATE
+		SET opted_out = excluded.opted_out,
+			updated_by = excluded.updated_by,
+			updated_at = excluded.updated_at
+		RETURNING org_id, opted_out, updated_by, updated_at`
+
+	result := &models.TelemetrySettings{}
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, settings.OrgID, settings.OptedOut, settings.UpdatedBy, time.Now().UTC()).
+		Scan(&result.OrgID, &result.OptedOut, &result.UpdatedBy, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	result.UpdatedAt = &updatedAt
+	return result, nil
+}
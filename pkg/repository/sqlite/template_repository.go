@@ -0,0 +1,161 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// templateColumns lists the columns read by scanTemplate, in order
+const templateColumns = "id, title, description, status, checklist, owner_id, created_at, updated_at"
+
+// scanTemplate reads a template selected with templateColumns
+func scanTemplate(row rowScanner) (*models.TaskTemplate, error) {
+	template := &models.TaskTemplate{}
+	err := row.Scan(
+		&template.ID,
+		&template.Title,
+		&template.Description,
+		&template.Status,
+		(*stringList)(&template.Checklist),
+		&template.OwnerID,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if template.Checklist == nil {
+		template.Checklist = []string{}
+	}
+	return template, nil
+}
+
+type templateRepository struct {
+	db *sql.DB
+}
+
+// NewTemplateRepository creates a new SQLite task template repository
+func NewTemplateRepository(db *sql.DB) repository.TemplateRepository {
+	return &templateRepository{db: db}
+}
+
+func (r *templateRepository) Create(ctx context.Context, template *models.TaskTemplate) (*models.TaskTemplate, error) {
+	query := `
+		INSERT INTO task_templates (id, title, description, status, checklist, owner_id, created_at, updated_at, org_id)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, NULLIF(?9, ''))
+		RETURNING ` + templateColumns
+
+	now := time.Now().UTC()
+	return scanTemplate(r.db.QueryRowContext(
+		ctx,
+		query,
+		uuid.New().String(),
+		template.Title,
+		template.Description,
+		template.Status,
+		stringList(template.Checklist),
+		template.OwnerID,
+		now,
+		now,
+		tenantOrgID(ctx, ""),
+	))
+}
+
+func (r *templateRepository) GetByID(ctx context.Context, id string) (*models.TaskTemplate, error) {
+	query := `
+		SELECT ` + templateColumns + `
+		FROM task_templates
+		WHERE id = ?1 AND ` + tenantMatch("", 2)
+
+	template, err := scanTemplate(r.db.QueryRowContext(ctx, query, id, tenant.Arg(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("template not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (r *templateRepository) List(ctx context.Context) ([]*models.TaskTemplate, error) {
+	query := `
+		SELECT ` + templateColumns + `
+		FROM task_templates
+		WHERE ` + tenantMatch("", 1) + `
+		ORDER BY updated_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*models.TaskTemplate
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (r *templateRepository) Update(ctx context.Context, id string, template *models.TaskTemplateInput) (*models.TaskTemplate, error) {
+	query := `
+		UPDATE task_templates
+		SET title = ?1, description = ?2, status = ?3, checklist = ?4, updated_at = ?5
+		WHERE id = ?6 AND ` + tenantMatch("", 7) + `
+		RETURNING ` + templateColumns
+
+	result, err := scanTemplate(r.db.QueryRowContext(
+		ctx,
+		query,
+		template.Title,
+		template.Description,
+		template.Status,
+		stringList(template.Checklist),
+		time.Now().UTC(),
+		id,
+		tenant.Arg(ctx),
+	))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("template not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *templateRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM task_templates WHERE id = ?1 AND `+tenantMatch("", 2), id, tenant.Arg(ctx))
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.New("template not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// timeEntryColumns lists the columns read by scanTimeEntry, in order
+const timeEntryColumns = "id, task_id, user_id, started_at, ended_at, minutes, note, created_at"
+
+type timeEntryRepository struct {
+	db *sql.DB
+}
+
+// NewTimeEntryRepository creates a new SQLite time entry repository
+func NewTimeEntryRepository(db *sql.DB) repository.TimeEntryRepository {
+	return &timeEntryRepository{db: db}
+}
+
+func scanTimeEntry(row rowScanner) (*models.TimeEntry, error) {
+	entry := &models.TimeEntry{}
+	var endedAt sql.NullTime
+	err := row.Scan(
+		&entry.ID,
+		&entry.TaskID,
+		&entry.UserID,
+		&entry.StartedAt,
+		&endedAt,
+		&entry.Minutes,
+		&entry.Note,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endedAt.Valid {
+		entry.EndedAt = &endedAt.Time
+	}
+	return entry, nil
+}
+
+func (r *timeEntryRepository) List(ctx context.Context, taskID string) ([]*models.TimeEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+timeEntryColumns+`
+		FROM task_time_entries
+		WHERE task_id = ?1
+		ORDER BY started_at DESC`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.TimeEntry{}
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (r *timeEntryRepository) Log(ctx context.Context, taskID string, log *models.TimeEntryLog) (*models.TimeEntry, error) {
+	var entry *models.TimeEntry
+	err := r.write(ctx, taskID, func(tx *sql.Tx) error {
+		endedAt := log.StartedAt.Add(time.Duration(log.Minutes) * time.Minute)
+		var err error
+		entry, err = scanTimeEntry(tx.QueryRowContext(ctx, `
+			INSERT INTO task_time_entries (id, task_id, user_id, started_at, ended_at, minutes, note, created_at)
+			VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
+			RETURNING `+timeEntryColumns,
+			uuid.New().String(), taskID, log.UserID, log.StartedAt.UTC(), endedAt.UTC(), log.Minutes, log.Note, time.Now().UTC()))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r *timeEntryRepository) Start(ctx context.Context, taskID string, timer *models.TimerStart) (*models.TimeEntry, error) {
+	var entry *models.TimeEntry
+	err := r.write(ctx, taskID, func(tx *sql.Tx) error {
+		now := time.Now().UTC()
+		var err error
+		entry, err = scanTimeEntry(tx.QueryRowContext(ctx, `
+			INSERT INTO task_time_entries (id, task_id, user_id, started_at, note, created_at)
+			VALUES (?1, ?2, ?3, ?4, ?5, ?6)
+			RETURNING `+timeEntryColumns,
+			uuid.New().String(), taskID, timer.UserID, now, timer.Note, now))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r *timeEntryRepository) Stop(ctx context.Context, taskID, userID string) (*models.TimeEntry, error) {
+	var entry *models.TimeEntry
+	err := r.write(ctx, taskID, func(tx *sql.Tx) error {
+		// Timers count whole minutes, rounded to the nearest, capped like logged entries
+		var err error
+		entry, err = scanTimeEntry(tx.QueryRowContext(ctx, `
+			UPDATE task_time_entries
+			SET ended_at = ?1,
+				minutes = MIN(CAST(ROUND((unixepoch(?1, 'subsec') - unixepoch(started_at, 'subsec')) / 60.0) AS INTEGER), ?2)
+			WHERE task_id = ?3 AND user_id = ?4 AND ended_at IS NULL
+			RETURNING `+timeEntryColumns,
+			time.Now().UTC(), models.MaxTimeEntryMinutes, taskID, userID))
+		if err == sql.ErrNoRows {
+			return errors.New("no timer is running")
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r *timeEntryRepository) Delete(ctx context.Context, taskID, entryID string) error {
+	return r.write(ctx, taskID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`DELETE FROM task_time_entries WHERE id = ?1 AND task_id = ?2`, entryID, taskID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return errors.New("time entry not found")
+		}
+		return nil
+	})
+}
+
+// write runs fn in a transaction, then recounts the time logged on the task. Running timers don't count until they are stopped.
+func (r *timeEntryRepository) write(ctx context.Context, taskID string, fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx,
+		`SELECT id FROM tasks WHERE id = ?1 AND `+tenantMatch("", 2), taskID, tenant.Arg(ctx)).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.New("task not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE tasks
+		SET logged_minutes = (
+			SELECT COALESCE(SUM(minutes), 0) FROM task_time_entries
+			WHERE task_id = ?1 AND ended_at IS NOT NULL
+		)
+		WHERE id = ?1`, taskID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+type workloadRepository struct {
+	db *sql.DB
+}
+
+// NewWorkloadRepository creates a new SQLite workload repository
+func NewWorkloadRepository(db *sql.DB) repository.WorkloadRepository {
+	return &workloadRepository{db: db}
+}
+
+func (r *workloadRepository) AssigneeWorkloads(ctx context.Context, projectKey string) ([]*models.AssigneeWorkload, error) {
+	// Time already logged is subtracted from each estimate, never below zero
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT COALESCE(assignee_id, ''),
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'in_progress'),
+			COUNT(*) FILTER (WHERE due_date < ?3),
+			COALESCE(SUM(MAX(estimated_minutes - logged_minutes, 0)), 0),
+			COUNT(*) FILTER (WHERE estimated_minutes IS NULL)
+		FROM tasks
+		WHERE project_key = ?1 AND status IN ('pending', 'in_progress') AND `+tenantMatch("", 2)+`
+		GROUP BY assignee_id`, projectKey, tenant.Arg(ctx), time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workloads := []*models.AssigneeWorkload{}
+	for rows.Next() {
+		workload := &models.AssigneeWorkload{}
+		err := rows.Scan(
+			&workload.AssigneeID,
+			&workload.OpenTasks,
+			&workload.InProgressTasks,
+			&workload.OverdueTasks,
+			&workload.EstimatedMinutes,
+			&workload.UnestimatedTasks,
+		)
+		if err != nil {
+			return nil, err
+		}
+		workloads = append(workloads, workload)
+	}
+	return workloads, rows.Err()
+}
+
+func (r *workloadRepository) ReassignableTasks(ctx context.Context, projectKey string) ([]*models.Task, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE project_key = ?1 AND status = 'pending' AND assignee_id IS NOT NULL AND `+tenantMatch("", 2)+`
+		ORDER BY due_date DESC, created_at DESC`, projectKey, tenant.Arg(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// StaleTaskRepository defines the interface for project settings and stale task detection
+type StaleTaskRepository interface {
+	// GetProjectSettings retrieves a project's settings; projects without saved settings
+	// get an empty StaleAfterDays
+	GetProjectSettings(ctx context.Context, projectKey string) (*models.ProjectSettings, error)
+
+	// SaveProjectSettings creates or replaces a project's settings
+	SaveProjectSettings(ctx context.Context, settings *models.ProjectSettings) (*models.ProjectSettings, error)
+
+	// MarkStale flags the open tasks that went without updates for longer than their
+	// project's threshold, defaultDays unless configured, and returns the newly flagged
+	// ones. Tasks already flagged are not returned again until they are updated.
+	MarkStale(ctx context.Context, now time.Time, defaultDays int) ([]*models.Task, error)
+}
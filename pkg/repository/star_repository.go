@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+)
+
+// StarRepository defines the interface for per-user task stars
+type StarRepository interface {
+	// Star marks a task as starred by a user; starring twice is a no-op
+	Star(ctx context.Context, taskID, userID string) error
+
+	// Unstar removes a user's star from a task; unstarring an unstarred task is a no-op
+	Unstar(ctx context.Context, taskID, userID string) error
+
+	// StarredIDs returns which of the given tasks the user has starred
+	StarredIDs(ctx context.Context, userID string, taskIDs []string) (map[string]bool, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// SyncRepository defines the interface for reading task changes in sequence
+type SyncRepository interface {
+	// Changes returns up to limit changes after the cursor in sequence order: the current
+	// state of tasks visible to viewer and tombstones of deleted ones. Only changes below
+	// the returned bound are read; every change committed later is sequenced at or above it.
+	Changes(ctx context.Context, after models.SyncCursor, viewer string, limit int) ([]*models.SyncChange, int64, error)
+}
@@ -2,31 +2,141 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"sample/task-management-system/pkg/models"
 )
 
+// Sort fields accepted by TaskFilter.Sort
+const (
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+	SortDueDate   = "due_date"
+	SortPriority  = "priority"
+	SortTitle     = "title"
+	SortPosition  = "position" // manual board order, see Task.Position
+)
+
+// Snooze filters accepted by TaskFilter.Snoozed
+const (
+	SnoozedHidden = "hidden" // leave out snoozed tasks
+	SnoozedOnly   = "only"   // only snoozed tasks
+)
+
 // TaskFilter represents the filtering options for tasks
 type TaskFilter struct {
-	Status models.TaskStatus
-	Page   int
-	Limit  int
+	Status     []models.TaskStatus // tasks in any of these statuses
+	Priority   models.TaskPriority
+	AssigneeID string
+	TeamID     string // only tasks assigned to this team
+	ProjectKey string // only tasks in this project
+	OwnerID    string
+	Tags       []string // tasks must carry every listed tag
+	StarredBy  string   // only tasks starred by this user
+	Stale      bool     // only open tasks flagged as stale, see Task.StaleSince
+	Snoozed    string   // one of the Snoozed* filters; empty lists tasks whether snoozed or not
+	IDs        []string // only these tasks
+	Viewer     string   // drafts are listed only for their owner; none without a viewer
+	// Metadata matches tasks whose metadata has every listed key with the value, compared
+	// as text so {"seats": 5} matches "5"
+	Metadata map[string]string
+	// DueAfter and DueBefore limit tasks to due dates in [DueAfter, DueBefore)
+	DueAfter  *time.Time
+	DueBefore *time.Time
+	// Sort is one of the Sort* fields, newest first when empty. Order is "asc" or
+	// "desc" and defaults to newest, latest updated, soonest due, most urgent,
+	// alphabetical or board order first.
+	Sort  string
+	Order string
+	Page  int
+	Limit int
+	// Offset skips the first tasks of a stream, to resume an export that was cut short
+	Offset int
+}
+
+// Validate checks the filter's statuses, priority, due date range and sort options
+func (f TaskFilter) Validate() error {
+	for _, status := range f.Status {
+		if status != models.StatusDraft && !models.IsValidStatus(status) {
+			return fmt.Errorf("invalid status %q", status)
+		}
+	}
+	if f.Priority != "" && !models.IsValidPriority(f.Priority) {
+		return errors.New("invalid priority")
+	}
+	switch f.Sort {
+	case "", SortCreatedAt, SortUpdatedAt, SortDueDate, SortPriority, SortTitle, SortPosition:
+	default:
+		return errors.New("sort must be created_at, updated_at, due_date, priority, title or position")
+	}
+	if f.Snoozed != "" && f.Snoozed != SnoozedHidden && f.Snoozed != SnoozedOnly {
+		return errors.New("invalid snooze filter")
+	}
+	if f.Order != "" && f.Order != "asc" && f.Order != "desc" {
+		return errors.New("order must be asc or desc")
+	}
+	if f.Offset < 0 {
+		return errors.New("offset must not be negative")
+	}
+	if f.DueAfter != nil && f.DueBefore != nil && !f.DueAfter.Before(*f.DueBefore) {
+		return errors.New("due_after must be before due_before")
+	}
+	for key := range f.Metadata {
+		if err := models.ValidateMetadataKey(key); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // TaskRepository defines the interface for task data access
 type TaskRepository interface {
 	// Create creates a new task
 	Create(ctx context.Context, task *models.TaskCreate) (*models.Task, error)
+	// CreateBatch creates the tasks together, all or none, in the given order
+	CreateBatch(ctx context.Context, tasks []*models.TaskCreate) ([]*models.Task, error)
 
 	// GetByID retrieves a task by its ID
 	GetByID(ctx context.Context, id string) (*models.Task, error)
 
+	// GetByKey retrieves a task by its project key and number, e.g. PROJ and 123
+	GetByKey(ctx context.Context, projectKey string, number int64) (*models.Task, error)
+
+	// GetBySlug retrieves a task by its project key and current or previous slug
+	GetBySlug(ctx context.Context, projectKey, slug string) (*models.Task, error)
+
 	// Update updates an existing task
 	Update(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error)
 
+	// SaveDraft creates a draft with the given ID, or replaces the content of the
+	// existing draft of draft.OwnerID
+	SaveDraft(ctx context.Context, id string, draft *models.TaskDraft) (*models.Task, error)
+
+	// PublishDraft gives a draft the status, making it visible to everyone
+	PublishDraft(ctx context.Context, id string, status models.TaskStatus) (*models.Task, error)
+
 	// Delete removes a task by its ID
 	Delete(ctx context.Context, id string) error
 
 	// List retrieves tasks with pagination and filtering
 	List(ctx context.Context, filter TaskFilter) ([]*models.Task, int, error)
+
+	// Stream calls fn with every task matching filter, in the filter's order, reading them
+	// through a cursor rather than all at once, after skipping Offset tasks. Page and Limit
+	// are ignored; an error from fn stops the stream and is returned.
+	Stream(ctx context.Context, filter TaskFilter, fn func(task *models.Task) error) error
+
+	// ListSubtasks retrieves the direct subtasks of a task, or all descendants if recursive
+	ListSubtasks(ctx context.Context, parentID string, recursive bool) ([]*models.Task, error)
+
+	// ListAncestorIDs returns the IDs of a task's parent, grandparent and so on
+	ListAncestorIDs(ctx context.Context, id string) ([]string, error)
+
+	// UpdateSubtaskStatus sets the status of every descendant of a task and returns them
+	UpdateSubtaskStatus(ctx context.Context, parentID string, status models.TaskStatus) ([]*models.Task, error)
+
+	// ListTags returns every tag in use with the number of tasks carrying it, most used first
+	ListTags(ctx context.Context) ([]models.TagCount, error)
 } 
\ No newline at end of file
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// TeamRepository defines the interface for team and team membership data access
+type TeamRepository interface {
+	// Create creates a new team, returning models.ErrTeamNameTaken for a name in use
+	Create(ctx context.Context, team *models.Team) (*models.Team, error)
+
+	// GetByID retrieves a team with its members
+	GetByID(ctx context.Context, id string) (*models.Team, error)
+
+	// List retrieves all teams by name, without their members
+	List(ctx context.Context) ([]*models.Team, error)
+
+	// Update replaces the name and description of a team
+	Update(ctx context.Context, id string, input *models.TeamInput) (*models.Team, error)
+
+	// Delete removes a team; its tasks are left without a team
+	Delete(ctx context.Context, id string) error
+
+	// AddMember adds a user to a team; adding a member again changes nothing
+	AddMember(ctx context.Context, teamID, userID, addedBy string) error
+
+	// RemoveMember removes a user from a team
+	RemoveMember(ctx context.Context, teamID, userID string) error
+
+	// IsMember reports whether the user belongs to the team
+	IsMember(ctx context.Context, teamID, userID string) (bool, error)
+
+	// AssignTask assigns a task to a team, or takes it off its team when teamID is empty
+	AssignTask(ctx context.Context, taskID, teamID string) (*models.Task, error)
+}
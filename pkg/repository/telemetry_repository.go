@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// TelemetryRepository defines the interface for usage analytics data access
+type TelemetryRepository interface {
+	// InsertEvents stores a batch of events
+	InsertEvents(ctx context.Context, events []*models.TelemetryEvent) error
+
+	// GetSettings retrieves an organization's settings; organizations that never
+	// changed them get the defaults
+	GetSettings(ctx context.Context, orgID string) (*models.TelemetrySettings, error)
+
+	// SaveSettings creates or replaces an organization's settings
+	SaveSettings(ctx context.Context, settings *models.TelemetrySettings) (*models.TelemetrySettings, error)
+}
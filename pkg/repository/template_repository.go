@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// TemplateRepository defines the interface for task template data access
+type TemplateRepository interface {
+	// Create creates a new template
+	Create(ctx context.Context, template *models.TaskTemplate) (*models.TaskTemplate, error)
+
+	// GetByID retrieves a template by its ID
+	GetByID(ctx context.Context, id string) (*models.TaskTemplate, error)
+
+	// List retrieves all templates, most recently updated first
+	List(ctx context.Context) ([]*models.TaskTemplate, error)
+
+	// Update replaces the title, description, status and checklist of a template
+	Update(ctx context.Context, id string, template *models.TaskTemplateInput) (*models.TaskTemplate, error)
+
+	// Delete removes a template by its ID
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// TimeEntryRepository defines the interface for the time entries of tasks. Every write
+// also updates the logged time reported on the task.
+type TimeEntryRepository interface {
+	// List returns the time entries of a task, most recent first
+	List(ctx context.Context, taskID string) ([]*models.TimeEntry, error)
+
+	// Log records time spent on a task without a timer
+	Log(ctx context.Context, taskID string, entry *models.TimeEntryLog) (*models.TimeEntry, error)
+
+	// Start starts a timer for the user on a task
+	Start(ctx context.Context, taskID string, timer *models.TimerStart) (*models.TimeEntry, error)
+
+	// Stop stops the user's running timer on a task, logging the time it ran
+	Stop(ctx context.Context, taskID, userID string) (*models.TimeEntry, error)
+
+	// Delete removes a time entry of a task
+	Delete(ctx context.Context, taskID, entryID string) error
+}
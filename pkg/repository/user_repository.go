@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// UserRepository defines the interface for accounts and their password reset links
+type UserRepository interface {
+	// Create stores an account with its password hash, returning models.ErrEmailTaken when
+	// the address already has one
+	Create(ctx context.Context, account *models.Account, passwordHash string) (*models.Account, error)
+
+	// GetRoles retrieves the current roles of an account or OpenID Connect user, returning
+	// models.ErrUserNotFound for unknown users
+	GetRoles(ctx context.Context, userID string) ([]string, error)
+
+	// GetByEmail retrieves an account and its password hash by email address
+	GetByEmail(ctx context.Context, email string) (*models.Account, string, error)
+
+	// CreatePasswordReset stores the hash of a reset token for the account
+	CreatePasswordReset(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error
+
+	// ResetPassword uses the reset token with the hash to replace the account's password
+	// hash. Every reset token of the account stops working. It returns
+	// models.ErrPasswordResetInvalid for unknown, used and expired tokens.
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string, now time.Time) (*models.Account, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// ViewRepository defines the interface for the durable copy of recently viewed tasks
+type ViewRepository interface {
+	// SaveViews upserts a user's views and keeps only the most recent keep of them.
+	// Views of tasks that no longer exist are skipped.
+	SaveViews(ctx context.Context, userID string, views []models.TaskView, keep int) error
+
+	// ListViews retrieves up to limit of a user's views, most recent first
+	ListViews(ctx context.Context, userID string, limit int) ([]models.TaskView, error)
+}
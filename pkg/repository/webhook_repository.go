@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// WebhookRepository defines the interface for webhook subscriptions
+type WebhookRepository interface {
+	// Create stores a new subscription
+	Create(ctx context.Context, hook *models.Webhook) (*models.Webhook, error)
+	// GetByID retrieves a subscription by its ID
+	GetByID(ctx context.Context, id string) (*models.Webhook, error)
+	// List retrieves every subscription, newest first
+	List(ctx context.Context) ([]*models.Webhook, error)
+	// ListForEvent retrieves the subscriptions to eventType
+	ListForEvent(ctx context.Context, eventType string) ([]*models.Webhook, error)
+	// Delete removes a subscription along with its delivery logs
+	Delete(ctx context.Context, id string) error
+	// RecordDelivery logs a delivery attempt, assigning its ID
+	RecordDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	// ListDeliveries retrieves the delivery logs of a subscription, newest first
+	ListDeliveries(ctx context.Context, webhookID string, page, limit int) ([]*models.WebhookDelivery, int, error)
+	// GetDelivery retrieves a delivery log of a subscription
+	GetDelivery(ctx context.Context, webhookID, id string) (*models.WebhookDelivery, error)
+	// DeleteDeliveriesBefore removes the delivery logs older than before
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// WorkloadRepository defines the interface for the aggregates behind project workloads
+type WorkloadRepository interface {
+	// AssigneeWorkloads sums up the open tasks of a project per assignee; unassigned
+	// tasks are reported with an empty assignee ID
+	AssigneeWorkloads(ctx context.Context, projectKey string) ([]*models.AssigneeWorkload, error)
+
+	// ReassignableTasks retrieves the project's assigned tasks that haven't been started,
+	// which are the ones that can move to someone else
+	ReassignableTasks(ctx context.Context, projectKey string) ([]*models.Task, error)
+}
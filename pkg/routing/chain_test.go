@@ -0,0 +1,109 @@
+package routing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// trace returns middleware appending name to the request's trace header
+func trace(name string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Add("X-Trace", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// reject returns middleware answering every request with status
+func reject(status int) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		})
+	}
+}
+
+// serve runs a request to path through the chain and returns the middleware it passed
+func serve(chain *Chain, path string) (int, string) {
+	handler := chain.Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Join(r.Header.Values("X-Trace"), ",")))
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestChain_RunsStagesInOrder(t *testing.T) {
+	chain := &Chain{}
+	// Added out of order on purpose
+	chain.Use(Cache, trace("cache"))
+	chain.Use(RateLimit, trace("ratelimit"))
+	chain.Use(Auth, trace("auth"))
+	chain.Use(Policy, trace("audit"))
+	chain.Use(Observe, trace("metrics"), trace("logging"))
+	chain.Use(Guard, trace("maintenance"))
+
+	_, got := serve(chain, "/api/v1/tasks")
+	assert.Equal(t, "metrics,logging,maintenance,auth,ratelimit,audit,cache", got)
+}
+
+func TestChain_CacheRunsOnlyAfterAuth(t *testing.T) {
+	chain := &Chain{}
+	chain.Use(Cache, trace("cache"))
+	chain.Use(Auth, reject(http.StatusUnauthorized))
+
+	status, got := serve(chain, "/api/v1/tasks/1")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Empty(t, got)
+}
+
+func TestChain_Override(t *testing.T) {
+	chain := &Chain{}
+	chain.Use(Auth, trace("auth"))
+	chain.Use(RateLimit, trace("ratelimit"))
+	chain.Use(Cache, trace("cache"))
+	chain.Override("/api/v1/batch", Cache)
+	chain.Override("/api/v1/exports/", RateLimit, trace("export-limit"))
+	chain.Override("/api/v1/exports/bulk", RateLimit, trace("bulk-limit"))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/tasks", "auth,ratelimit,cache"},
+		{"/api/v1/batch", "auth,ratelimit"},
+		{"/api/v1/batch/status", "auth,ratelimit"},
+		{"/api/v1/batches", "auth,ratelimit,cache"},
+		{"/api/v1/exports/1", "auth,export-limit,cache"},
+		{"/api/v1/exports/bulk/1", "auth,bulk-limit,cache"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			_, got := serve(chain, tt.path)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestChain_AsRouterMiddleware(t *testing.T) {
+	chain := &Chain{}
+	chain.Use(Cache, trace("cache"))
+	chain.Use(Auth, trace("auth"))
+
+	router := mux.NewRouter()
+	router.Use(chain.Middleware())
+	router.HandleFunc("/tasks", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Join(r.Header.Values("X-Trace"), ",")))
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+	assert.Equal(t, "auth,cache", rec.Body.String())
+}
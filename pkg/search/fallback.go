@@ -0,0 +1,46 @@
+package search
+
+import (
+	"context"
+	"log"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// FallbackSearcher queries a primary search backend and falls back to a
+// secondary one (typically Postgres full-text search) when the primary fails
+type FallbackSearcher struct {
+	primary  repository.TaskSearcher
+	fallback repository.TaskSearcher
+}
+
+// NewFallbackSearcher creates a searcher that degrades to fallback on primary errors
+func NewFallbackSearcher(primary, fallback repository.TaskSearcher) repository.TaskSearcher {
+	return &FallbackSearcher{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+// Search implements repository.TaskSearcher
+func (s *FallbackSearcher) Search(ctx context.Context, query repository.TaskSearchQuery) (*repository.TaskSearchResult, error) {
+	result, err := s.primary.Search(ctx, query)
+	if err == nil {
+		return result, nil
+	}
+
+	log.Printf("Primary search backend failed, falling back: %v", err)
+	return s.fallback.Search(ctx, query)
+}
+
+// Related implements repository.TaskSearcher
+func (s *FallbackSearcher) Related(ctx context.Context, id string, limit int) ([]*models.Task, error) {
+	tasks, err := s.primary.Related(ctx, id, limit)
+	if err == nil {
+		return tasks, nil
+	}
+
+	log.Printf("Primary search backend failed, falling back: %v", err)
+	return s.fallback.Related(ctx, id, limit)
+}
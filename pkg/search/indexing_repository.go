@@ -0,0 +1,96 @@
+package search
+
+import (
+	"context"
+	"log"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// Indexer keeps a search index in sync with task writes
+type Indexer interface {
+	IndexTask(ctx context.Context, task *models.Task) error
+	DeleteTask(ctx context.Context, id string) error
+}
+
+// indexingRepository wraps a TaskRepository and mirrors writes to a search index.
+// Index failures are logged and never fail the write; Postgres stays the source of truth.
+type indexingRepository struct {
+	repository.TaskRepository
+	indexer Indexer
+}
+
+// NewIndexingRepository creates a TaskRepository that mirrors writes to indexer
+func NewIndexingRepository(repo repository.TaskRepository, indexer Indexer) repository.TaskRepository {
+	return &indexingRepository{
+		TaskRepository: repo,
+		indexer:        indexer,
+	}
+}
+
+func (r *indexingRepository) Create(ctx context.Context, input *models.TaskCreate) (*models.Task, error) {
+	task, err := r.TaskRepository.Create(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	r.index(ctx, task)
+	return task, nil
+}
+
+func (r *indexingRepository) CreateBatch(ctx context.Context, inputs []*models.TaskCreate) ([]*models.Task, error) {
+	tasks, err := r.TaskRepository.CreateBatch(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		r.index(ctx, task)
+	}
+	return tasks, nil
+}
+
+func (r *indexingRepository) Update(ctx context.Context, id string, input *models.TaskUpdate) (*models.Task, error) {
+	task, err := r.TaskRepository.Update(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+	r.index(ctx, task)
+	return task, nil
+}
+
+// PublishDraft indexes the task; drafts are only indexed once published
+func (r *indexingRepository) PublishDraft(ctx context.Context, id string, status models.TaskStatus) (*models.Task, error) {
+	task, err := r.TaskRepository.PublishDraft(ctx, id, status)
+	if err != nil {
+		return nil, err
+	}
+	r.index(ctx, task)
+	return task, nil
+}
+
+func (r *indexingRepository) UpdateSubtaskStatus(ctx context.Context, parentID string, status models.TaskStatus) ([]*models.Task, error) {
+	tasks, err := r.TaskRepository.UpdateSubtaskStatus(ctx, parentID, status)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		r.index(ctx, task)
+	}
+	return tasks, nil
+}
+
+func (r *indexingRepository) Delete(ctx context.Context, id string) error {
+	if err := r.TaskRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := r.indexer.DeleteTask(ctx, id); err != nil {
+		log.Printf("Failed to remove task %s from search index: %v", id, err)
+	}
+	return nil
+}
+
+func (r *indexingRepository) index(ctx context.Context, task *models.Task) {
+	if err := r.indexer.IndexTask(ctx, task); err != nil {
+		log.Printf("Failed to index task %s: %v", task.ID, err)
+	}
+}
@@ -0,0 +1,274 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// OpenSearchClient implements task search and indexing on top of the OpenSearch REST API
+type OpenSearchClient struct {
+	baseURL    string
+	index      string
+	username   string
+	password   string
+	httpClient *http.Client
+	// tasks loads the tasks of search hits, so results are never stale
+	tasks repository.TaskRepository
+}
+
+// NewOpenSearchClient creates a new OpenSearch client for the given index. The index only
+// decides which tasks match; the tasks returned are loaded from tasks, since many task
+// writes (merges, board moves, snoozes, SLA and team changes...) never reach the index.
+func NewOpenSearchClient(baseURL, index, username, password string, tasks repository.TaskRepository) *OpenSearchClient {
+	return &OpenSearchClient{
+		baseURL:    baseURL,
+		index:      index,
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		tasks:      tasks,
+	}
+}
+
+// searchHit is a search hit without its source, which may be stale
+type searchHit struct {
+	ID string `json:"_id"`
+}
+
+// Search implements repository.TaskSearcher using fuzzy multi-field matching
+func (c *OpenSearchClient) Search(ctx context.Context, query repository.TaskSearchQuery) (*repository.TaskSearchResult, error) {
+	match := map[string]interface{}{
+		"multi_match": map[string]interface{}{
+			"query":     query.Text,
+			"fields":    []string{"key^3", "title^2", "description"},
+			"fuzziness": "AUTO",
+		},
+	}
+	// The tenant and project filters apply to the facets too. Project keys are matched
+	// exactly on the keyword sub-field dynamic mapping adds to every string.
+	filters := tenantFilters(ctx)
+	if query.ProjectKey != "" {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"project_key.keyword": query.ProjectKey}})
+	}
+
+	// Statuses are dynamically mapped text, so they are aggregated and filtered on their
+	// keyword sub-field
+	body := map[string]interface{}{
+		"from":    (query.Page - 1) * query.Limit,
+		"size":    query.Limit,
+		"_source": false,
+		"query":   filtered(match, filters),
+		"aggs": map[string]interface{}{
+			"status": map[string]interface{}{
+				"terms": map[string]interface{}{"field": "status.keyword"},
+			},
+		},
+	}
+
+	// The status filter is applied as a post filter so facets still count every status
+	if query.Status != "" {
+		body["post_filter"] = map[string]interface{}{
+			"term": map[string]interface{}{"status.keyword": query.Status},
+		}
+	}
+
+	var response struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []searchHit `json:"hits"`
+		} `json:"hits"`
+		Aggregations map[string]struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				DocCount int    `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"aggregations"`
+	}
+
+	if err := c.do(ctx, http.MethodPost, "/"+url.PathEscape(c.index)+"/_search", body, &response); err != nil {
+		return nil, err
+	}
+
+	tasks, err := c.loadTasks(ctx, response.Hits.Hits)
+	if err != nil {
+		return nil, err
+	}
+	result := &repository.TaskSearchResult{
+		Tasks:  tasks,
+		Total:  response.Hits.Total.Value,
+		Facets: make(map[string]map[string]int),
+	}
+	for name, agg := range response.Aggregations {
+		counts := make(map[string]int)
+		for _, bucket := range agg.Buckets {
+			counts[bucket.Key] = bucket.DocCount
+		}
+		result.Facets[name] = counts
+	}
+
+	return result, nil
+}
+
+// Related implements repository.TaskSearcher using a more_like_this query
+func (c *OpenSearchClient) Related(ctx context.Context, id string, limit int) ([]*models.Task, error) {
+	moreLikeThis := map[string]interface{}{
+		"more_like_this": map[string]interface{}{
+			"fields":        []string{"title", "description", "tags"},
+			"like":          []interface{}{map[string]interface{}{"_index": c.index, "_id": id}},
+			"min_term_freq": 1,
+			"min_doc_freq":  1,
+		},
+	}
+	body := map[string]interface{}{
+		"size":    limit,
+		"_source": false,
+		"query":   filtered(moreLikeThis, tenantFilters(ctx)),
+	}
+
+	var response struct {
+		Hits struct {
+			Hits []searchHit `json:"hits"`
+		} `json:"hits"`
+	}
+
+	if err := c.do(ctx, http.MethodPost, "/"+url.PathEscape(c.index)+"/_search", body, &response); err != nil {
+		return nil, err
+	}
+
+	return c.loadTasks(ctx, response.Hits.Hits)
+}
+
+// loadTasks loads the tasks of hits in hit order. Tasks deleted since they were indexed,
+// or outside the tenant of ctx, are left out.
+func (c *OpenSearchClient) loadTasks(ctx context.Context, hits []searchHit) ([]*models.Task, error) {
+	if len(hits) == 0 {
+		return []*models.Task{}, nil
+	}
+
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	found, _, err := c.tasks.List(ctx, repository.TaskFilter{IDs: ids, Page: 1, Limit: len(ids)})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Task, len(found))
+	for _, task := range found {
+		byID[task.ID] = task
+	}
+
+	tasks := make([]*models.Task, 0, len(ids))
+	for _, id := range ids {
+		if task, ok := byID[id]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// taskDocument is the indexed form of a task. It carries the task's tenant, which the
+// task's own JSON leaves out, so searches can be confined to it.
+type taskDocument struct {
+	*models.Task
+	OrgID string `json:"org_id"`
+}
+
+// IndexTask adds or replaces a task document in the index
+func (c *OpenSearchClient) IndexTask(ctx context.Context, task *models.Task) error {
+	path := fmt.Sprintf("/%s/_doc/%s", url.PathEscape(c.index), url.PathEscape(task.ID))
+	return c.do(ctx, http.MethodPut, path, taskDocument{Task: task, OrgID: task.OrgID}, nil)
+}
+
+// tenantFilters returns the filter confining a search to the tenant of ctx, whose tasks
+// are indexed with its org_id ("" for the default tenant); none for unscoped contexts
+func tenantFilters(ctx context.Context) []interface{} {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return []interface{}{map[string]interface{}{"term": map[string]interface{}{"org_id.keyword": orgID}}}
+}
+
+// filtered restricts query to the documents matching every filter
+func filtered(query map[string]interface{}, filters []interface{}) map[string]interface{} {
+	if len(filters) == 0 {
+		return query
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{"must": query, "filter": filters},
+	}
+}
+
+// DeleteTask removes a task document from the index
+func (c *OpenSearchClient) DeleteTask(ctx context.Context, id string) error {
+	path := fmt.Sprintf("/%s/_doc/%s", url.PathEscape(c.index), url.PathEscape(id))
+	err := c.do(ctx, http.MethodDelete, path, nil, nil)
+	if statusErr, ok := err.(*StatusError); ok && statusErr.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// Ping checks if the OpenSearch cluster is reachable
+func (c *OpenSearchClient) Ping(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/", nil, nil)
+}
+
+// StatusError is returned when OpenSearch responds with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("opensearch returned status %d: %s", e.StatusCode, e.Body)
+}
+
+func (c *OpenSearchClient) do(ctx context.Context, method, path string, body, dest interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(snippet)}
+	}
+
+	if dest == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
@@ -0,0 +1,208 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/repository/memory"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// MockSearcher is a mock implementation of repository.TaskSearcher
+type MockSearcher struct {
+	mock.Mock
+}
+
+func (m *MockSearcher) Search(ctx context.Context, query repository.TaskSearchQuery) (*repository.TaskSearchResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.TaskSearchResult), args.Error(1)
+}
+
+func (m *MockSearcher) Related(ctx context.Context, id string, limit int) ([]*models.Task, error) {
+	args := m.Called(ctx, id, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
+func TestFallbackSearcher_Search(t *testing.T) {
+	query := repository.TaskSearchQuery{Text: "docs", Page: 1, Limit: 10}
+	primaryResult := &repository.TaskSearchResult{Total: 1}
+	fallbackResult := &repository.TaskSearchResult{Total: 2}
+
+	tests := []struct {
+		name       string
+		primaryErr error
+		want       *repository.TaskSearchResult
+	}{
+		{
+			name: "primary succeeds",
+			want: primaryResult,
+		},
+		{
+			name:       "primary fails",
+			primaryErr: errors.New("connection refused"),
+			want:       fallbackResult,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := new(MockSearcher)
+			fallback := new(MockSearcher)
+
+			if tt.primaryErr != nil {
+				primary.On("Search", mock.Anything, query).Return(nil, tt.primaryErr)
+				fallback.On("Search", mock.Anything, query).Return(fallbackResult, nil)
+			} else {
+				primary.On("Search", mock.Anything, query).Return(primaryResult, nil)
+			}
+
+			got, err := NewFallbackSearcher(primary, fallback).Search(context.Background(), query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			primary.AssertExpectations(t)
+			fallback.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOpenSearchClient_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tasks/_search", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, float64(10), body["from"])
+		assert.Equal(t, float64(5), body["size"])
+		assert.Equal(t, false, body["_source"])
+		assert.Equal(t, map[string]interface{}{"term": map[string]interface{}{"status.keyword": "pending"}}, body["post_filter"])
+		assert.Equal(t, map[string]interface{}{"terms": map[string]interface{}{"field": "status.keyword"}},
+			body["aggs"].(map[string]interface{})["status"])
+
+		w.Write([]byte(`{
+			"hits": {
+				"total": {"value": 2},
+				"hits": [{"_id": "1"}, {"_id": "deleted"}]
+			},
+			"aggregations": {
+				"status": {"buckets": [{"key": "pending", "doc_count": 1}, {"key": "completed", "doc_count": 3}]}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	// Hits are loaded from the repository, not the possibly stale index
+	tasks := memory.NewTaskRepository([]*models.Task{{ID: "1", Title: "Write the docs", Status: models.StatusInProgress}})
+	client := NewOpenSearchClient(server.URL, "tasks", "", "", tasks)
+	result, err := client.Search(context.Background(), repository.TaskSearchQuery{
+		Text:   "docs",
+		Status: models.StatusPending,
+		Page:   3,
+		Limit:  5,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	require.Len(t, result.Tasks, 1)
+	assert.Equal(t, "Write the docs", result.Tasks[0].Title)
+	assert.Equal(t, models.StatusInProgress, result.Tasks[0].Status)
+	assert.Equal(t, map[string]int{"pending": 1, "completed": 3}, result.Facets["status"])
+}
+
+func TestOpenSearchClient_ConfinesToTenant(t *testing.T) {
+	var indexed, query map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if r.Method == http.MethodPut {
+			indexed = body
+			return
+		}
+		query = body["query"].(map[string]interface{})
+		w.Write([]byte(`{"hits": {"total": {"value": 0}, "hits": []}}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenSearchClient(server.URL, "tasks", "", "", memory.NewTaskRepository(nil))
+	ctx := tenant.WithID(context.Background(), "acme")
+
+	require.NoError(t, client.IndexTask(ctx, &models.Task{ID: "1", Title: "Write docs", OrgID: "acme"}))
+	assert.Equal(t, "acme", indexed["org_id"])
+	assert.Equal(t, "Write docs", indexed["title"])
+
+	_, err := client.Search(ctx, repository.TaskSearchQuery{Text: "docs", Page: 1, Limit: 10})
+	require.NoError(t, err)
+	tenantTerm := map[string]interface{}{"term": map[string]interface{}{"org_id.keyword": "acme"}}
+	assert.Contains(t, query["bool"].(map[string]interface{})["filter"], tenantTerm)
+
+	_, err = client.Related(ctx, "1", 5)
+	require.NoError(t, err)
+	assert.Contains(t, query["bool"].(map[string]interface{})["filter"], tenantTerm)
+}
+
+func TestOpenSearchClient_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewOpenSearchClient(server.URL, "tasks", "", "", memory.NewTaskRepository(nil))
+	_, err := client.Search(context.Background(), repository.TaskSearchQuery{Text: "docs", Page: 1, Limit: 10})
+
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+}
+
+func TestFallbackSearcher_Related(t *testing.T) {
+	primary := new(MockSearcher)
+	fallback := new(MockSearcher)
+	related := []*models.Task{{ID: "2", Title: "Write docs"}}
+
+	primary.On("Related", mock.Anything, "1", 5).Return(nil, errors.New("connection refused"))
+	fallback.On("Related", mock.Anything, "1", 5).Return(related, nil)
+
+	got, err := NewFallbackSearcher(primary, fallback).Related(context.Background(), "1", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, related, got)
+	primary.AssertExpectations(t)
+	fallback.AssertExpectations(t)
+}
+
+func TestOpenSearchClient_Related(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Contains(t, body["query"], "more_like_this")
+
+		w.Write([]byte(`{"hits": {"hits": [{"_id": "3"}, {"_id": "2"}]}}`))
+	}))
+	defer server.Close()
+
+	repo := memory.NewTaskRepository([]*models.Task{
+		{ID: "2", Title: "Write docs", Status: models.StatusPending},
+		{ID: "3", Title: "Review docs", Status: models.StatusPending},
+	})
+	client := NewOpenSearchClient(server.URL, "tasks", "", "", repo)
+	tasks, err := client.Related(context.Background(), "1", 5)
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "3", tasks[0].ID)
+	assert.Equal(t, "2", tasks[1].ID)
+}
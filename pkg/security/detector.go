@@ -0,0 +1,191 @@
+package security
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"sample/task-management-system/pkg/audit"
+	"sample/task-management-system/pkg/metrics"
+	"sample/task-management-system/pkg/models"
+)
+
+// Audit actions consumed by the detector
+const (
+	ActionUnauthorized = "auth.unauthorized"
+	ActionForbidden    = "auth.forbidden"
+	ActionRevokedToken = "auth.revoked_token"
+)
+
+// ActionPrefix prefixes the audit actions of raised alerts, e.g. "security.brute_force"
+const ActionPrefix = "security."
+
+// Alert kinds
+const (
+	KindBruteForce        = "brute_force"
+	KindRepeatedForbidden = "repeated_forbidden"
+	KindNewCountry        = "new_country"
+	KindRevokedTokenReuse = "revoked_token_reuse"
+)
+
+// maxTrackedKeys bounds MemoryStore; stale keys are swept when it is exceeded
+const maxTrackedKeys = 10000
+
+// Alert describes suspicious activity
+type Alert struct {
+	Kind       string            `json:"kind"`
+	UserID     string            `json:"user_id,omitempty"`
+	RemoteAddr string            `json:"remote_addr,omitempty"`
+	Message    string            `json:"message"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// Config holds detector thresholds
+type Config struct {
+	Window                time.Duration // sliding window for counting failures
+	UnauthorizedThreshold int           // 401s from one address within Window
+	ForbiddenThreshold    int           // 403s for one user within Window
+}
+
+// DefaultConfig returns the default detector thresholds
+func DefaultConfig() Config {
+	return Config{
+		Window:                5 * time.Minute,
+		UnauthorizedThreshold: 20,
+		ForbiddenThreshold:    10,
+	}
+}
+
+// Detector is an audit.Logger that passes events through to the next logger while
+// looking for brute-force attempts, permission probing, revoked token reuse and
+// access from new countries. Its counters are kept in store.
+type Detector struct {
+	next     audit.Logger
+	notifier Notifier
+	config   Config
+	store    Store
+	now      func() time.Time
+}
+
+// NewDetector creates a detector recording events and alerts to next
+func NewDetector(next audit.Logger, notifier Notifier, config Config, store Store) *Detector {
+	return &Detector{
+		next:     next,
+		notifier: notifier,
+		config:   config,
+		store:    store,
+		now:      time.Now,
+	}
+}
+
+// Record implements audit.Logger
+func (d *Detector) Record(ctx context.Context, event *models.AuditEvent) {
+	d.next.Record(ctx, event)
+
+	switch event.Action {
+	case ActionUnauthorized:
+		if event.RemoteAddr == "" {
+			return
+		}
+		if d.countFailure(ctx, "401:"+event.RemoteAddr, d.config.UnauthorizedThreshold) {
+			d.raise(ctx, Alert{
+				Kind:       KindBruteForce,
+				RemoteAddr: event.RemoteAddr,
+				Message:    fmt.Sprintf("%d or more failed authentications within %s", d.config.UnauthorizedThreshold, d.config.Window),
+			})
+		}
+	case ActionForbidden:
+		if event.SubjectID == "" {
+			return
+		}
+		if d.countFailure(ctx, "403:"+event.SubjectID, d.config.ForbiddenThreshold) {
+			d.raise(ctx, Alert{
+				Kind:       KindRepeatedForbidden,
+				UserID:     event.SubjectID,
+				RemoteAddr: event.RemoteAddr,
+				Message:    fmt.Sprintf("%d or more forbidden requests within %s", d.config.ForbiddenThreshold, d.config.Window),
+			})
+		}
+	case ActionRevokedToken:
+		d.raise(ctx, Alert{
+			Kind:       KindRevokedTokenReuse,
+			UserID:     event.SubjectID,
+			RemoteAddr: event.RemoteAddr,
+			Message:    "a revoked token was presented",
+		})
+	}
+}
+
+// ObserveAccess tracks the countries a user authenticates from and alerts the first
+// time a user who has history in one country shows up from another
+func (d *Detector) ObserveAccess(ctx context.Context, userID, remoteAddr, country string) {
+	if userID == "" || country == "" {
+		return
+	}
+
+	isNew, err := d.store.AddCountry(ctx, userID, country)
+	if err != nil {
+		log.Printf("Failed to record access country of user %s: %v", userID, err)
+		return
+	}
+
+	if isNew {
+		d.raise(ctx, Alert{
+			Kind:       KindNewCountry,
+			UserID:     userID,
+			RemoteAddr: remoteAddr,
+			Message:    fmt.Sprintf("access from a new country: %s", country),
+			Metadata:   map[string]string{"country": country},
+		})
+	}
+}
+
+// countFailure adds a failure for key and reports whether it crossed the threshold
+func (d *Detector) countFailure(ctx context.Context, key string, threshold int) bool {
+	if threshold <= 0 {
+		return false
+	}
+
+	count, err := d.store.AddFailure(ctx, key, d.now(), d.config.Window)
+	if err != nil {
+		log.Printf("Failed to count security failure %s: %v", key, err)
+		return false
+	}
+	return count >= threshold
+}
+
+// raise records and notifies an alert, at most once per kind and target within the window
+func (d *Detector) raise(ctx context.Context, alert Alert) {
+	alert.CreatedAt = d.now()
+	key := alert.Kind + ":" + alert.UserID + ":" + alert.RemoteAddr
+
+	// An alert that can't be deduplicated is raised anyway; repeats beat missed alerts
+	first, err := d.store.MarkAlerted(ctx, key, alert.CreatedAt, d.config.Window)
+	if err != nil {
+		log.Printf("Failed to deduplicate security alert %s: %v", alert.Kind, err)
+	} else if !first {
+		return
+	}
+
+	metadata := map[string]string{"message": alert.Message}
+	for k, v := range alert.Metadata {
+		metadata[k] = v
+	}
+	d.next.Record(ctx, &models.AuditEvent{
+		Action:     ActionPrefix + alert.Kind,
+		ActorID:    alert.UserID,
+		SubjectID:  alert.UserID,
+		RemoteAddr: alert.RemoteAddr,
+		Metadata:   metadata,
+		CreatedAt:  alert.CreatedAt,
+	})
+	metrics.RecordSecurityEvent(alert.Kind)
+
+	if d.notifier != nil {
+		if err := d.notifier.Notify(ctx, alert); err != nil {
+			log.Printf("Failed to send security alert %s: %v", alert.Kind, err)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+type recordingLogger struct {
+	mu     sync.Mutex
+	events []*models.AuditEvent
+}
+
+func (l *recordingLogger) Record(ctx context.Context, event *models.AuditEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func (l *recordingLogger) actions() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var actions []string
+	for _, e := range l.events {
+		actions = append(actions, e.Action)
+	}
+	return actions
+}
+
+type recordingNotifier struct {
+	alerts []Alert
+	err    error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, alert Alert) error {
+	n.alerts = append(n.alerts, alert)
+	return n.err
+}
+
+func newTestDetector(config Config) (*Detector, *recordingLogger, *recordingNotifier, *time.Time) {
+	logger := &recordingLogger{}
+	notifier := &recordingNotifier{}
+	detector := NewDetector(logger, notifier, config, NewMemoryStore())
+	now := time.Now()
+	detector.now = func() time.Time { return now }
+	return detector, logger, notifier, &now
+}
+
+func TestDetector_Thresholds(t *testing.T) {
+	config := Config{Window: time.Minute, UnauthorizedThreshold: 3, ForbiddenThreshold: 2}
+
+	tests := []struct {
+		name      string
+		events    []*models.AuditEvent
+		spacing   time.Duration
+		wantKinds []string
+	}{
+		{
+			name: "brute force from one address",
+			events: []*models.AuditEvent{
+				{Action: ActionUnauthorized, RemoteAddr: "198.51.100.1"},
+				{Action: ActionUnauthorized, RemoteAddr: "198.51.100.1"},
+				{Action: ActionUnauthorized, RemoteAddr: "198.51.100.1"},
+			},
+			wantKinds: []string{KindBruteForce},
+		},
+		{
+			name: "failures spread across addresses",
+			events: []*models.AuditEvent{
+				{Action: ActionUnauthorized, RemoteAddr: "198.51.100.1"},
+				{Action: ActionUnauthorized, RemoteAddr: "198.51.100.2"},
+				{Action: ActionUnauthorized, RemoteAddr: "198.51.100.3"},
+			},
+		},
+		{
+			name: "failures outside the window",
+			events: []*models.AuditEvent{
+				{Action: ActionUnauthorized, RemoteAddr: "198.51.100.1"},
+				{Action: ActionUnauthorized, RemoteAddr: "198.51.100.1"},
+				{Action: ActionUnauthorized, RemoteAddr: "198.51.100.1"},
+			},
+			spacing: 40 * time.Second,
+		},
+		{
+			name: "repeated forbidden for one user",
+			events: []*models.AuditEvent{
+				{Action: ActionForbidden, SubjectID: "user-1"},
+				{Action: ActionForbidden, SubjectID: "user-1"},
+			},
+			wantKinds: []string{KindRepeatedForbidden},
+		},
+		{
+			name:      "revoked token reuse alerts immediately",
+			events:    []*models.AuditEvent{{Action: ActionRevokedToken, SubjectID: "user-1"}},
+			wantKinds: []string{KindRevokedTokenReuse},
+		},
+		{
+			name: "alerts are not repeated within the window",
+			events: []*models.AuditEvent{
+				{Action: ActionRevokedToken, SubjectID: "user-1"},
+				{Action: ActionRevokedToken, SubjectID: "user-1"},
+			},
+			wantKinds: []string{KindRevokedTokenReuse},
+		},
+		{
+			name:   "unrelated events are ignored",
+			events: []*models.AuditEvent{{Action: "http.request", SubjectID: "user-1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector, logger, notifier, now := newTestDetector(config)
+
+			for _, event := range tt.events {
+				detector.Record(context.Background(), event)
+				*now = now.Add(tt.spacing)
+			}
+
+			var kinds []string
+			for _, alert := range notifier.alerts {
+				kinds = append(kinds, alert.Kind)
+			}
+			assert.Equal(t, tt.wantKinds, kinds)
+
+			// Every input event is passed through, followed by one event per alert
+			assert.Len(t, logger.actions(), len(tt.events)+len(tt.wantKinds))
+			for _, kind := range tt.wantKinds {
+				assert.Contains(t, logger.actions(), ActionPrefix+kind)
+			}
+		})
+	}
+}
+
+func TestDetector_NewCountry(t *testing.T) {
+	detector, logger, notifier, _ := newTestDetector(DefaultConfig())
+	ctx := context.Background()
+
+	detector.ObserveAccess(ctx, "user-1", "198.51.100.1", "US")
+	detector.ObserveAccess(ctx, "user-1", "198.51.100.1", "US")
+	detector.ObserveAccess(ctx, "user-1", "203.0.113.9", "")
+	assert.Empty(t, notifier.alerts)
+
+	detector.ObserveAccess(ctx, "user-1", "203.0.113.9", "BR")
+	require.Len(t, notifier.alerts, 1)
+	assert.Equal(t, KindNewCountry, notifier.alerts[0].Kind)
+	assert.Equal(t, "user-1", notifier.alerts[0].UserID)
+
+	require.Len(t, logger.events, 1)
+	assert.Equal(t, ActionPrefix+KindNewCountry, logger.events[0].Action)
+	assert.Equal(t, "user-1", logger.events[0].SubjectID)
+	assert.Equal(t, "BR", logger.events[0].Metadata["country"])
+
+	// Known countries don't alert again
+	detector.ObserveAccess(ctx, "user-1", "198.51.100.1", "US")
+	assert.Len(t, notifier.alerts, 1)
+}
+
+func TestDetector_NotifierFailureStillRecords(t *testing.T) {
+	detector, logger, notifier, _ := newTestDetector(DefaultConfig())
+	notifier.err = errors.New("webhook down")
+
+	detector.Record(context.Background(), &models.AuditEvent{Action: ActionRevokedToken, SubjectID: "user-1"})
+	assert.Equal(t, []string{ActionRevokedToken, ActionPrefix + KindRevokedTokenReuse}, logger.actions())
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received Alert
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL).Notify(context.Background(), Alert{Kind: KindBruteForce, RemoteAddr: "198.51.100.1"})
+	require.NoError(t, err)
+	assert.Equal(t, KindBruteForce, received.Kind)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	assert.Error(t, NewWebhookNotifier(failing.URL).Notify(context.Background(), Alert{Kind: KindBruteForce}))
+}
+
+func TestAuthEventHook(t *testing.T) {
+	logger := &recordingLogger{}
+	notifier := &recordingNotifier{}
+	detector := NewDetector(logger, notifier, DefaultConfig(), NewMemoryStore())
+	clientIP := func(r *http.Request) net.IP { return net.ParseIP("198.51.100.1") }
+	hook := AuthEventHook(detector, detector, clientIP, "CloudFront-Viewer-Country")
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/1", nil)
+	req.Header.Set("CloudFront-Viewer-Country", "US")
+	hook(req, auth.AuthEvent{Type: auth.AuthEventForbidden, UserID: "user-1", Err: auth.ErrInsufficientRole})
+
+	// Events are recorded in the background
+	require.Eventually(t, func() bool { return len(logger.actions()) == 1 }, time.Second, time.Millisecond)
+	event := logger.events[0]
+	assert.Equal(t, ActionForbidden, event.Action)
+	assert.Equal(t, "user-1", event.SubjectID)
+	assert.Equal(t, "198.51.100.1", event.RemoteAddr)
+	assert.Equal(t, auth.ErrInsufficientRole.Error(), event.Metadata["reason"])
+	assert.Equal(t, "US", event.Metadata["country"])
+
+	// Successful requests aren't stored; they only feed the country check
+	hook(req, auth.AuthEvent{Type: auth.AuthEventSuccess, UserID: "user-1"})
+	req.Header.Set("CloudFront-Viewer-Country", "BR")
+	hook(req, auth.AuthEvent{Type: auth.AuthEventSuccess, UserID: "user-1"})
+	assert.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{ActionForbidden, ActionPrefix + KindNewCountry}, logger.actions())
+	}, time.Second, time.Millisecond)
+}
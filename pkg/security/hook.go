@@ -0,0 +1,93 @@
+package security
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"sample/task-management-system/pkg/audit"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// authEventQueueSize bounds the auth events waiting to be handled
+const authEventQueueSize = 1024
+
+// AuthEventHook returns an auth.AuthConfig.OnAuthEvent hook that records failed
+// authentications in the audit log and feeds successful ones to the detector's
+// new-country check. countryHeader names a header set by a trusted CDN or proxy
+// (e.g. "CloudFront-Viewer-Country"); leave it empty to disable country tracking.
+//
+// Events are handled in the background, one at a time, so a burst of failed
+// authentications neither slows requests down nor floods the audit database.
+// Events arriving while authEventQueueSize are waiting are dropped.
+func AuthEventHook(logger audit.Logger, detector *Detector, clientIP func(*http.Request) net.IP, countryHeader string) func(*http.Request, auth.AuthEvent) {
+	queue := newEventQueue(authEventQueueSize)
+	return func(r *http.Request, event auth.AuthEvent) {
+		remoteAddr := ""
+		if ip := clientIP(r); ip != nil {
+			remoteAddr = ip.String()
+		}
+		country := ""
+		if countryHeader != "" {
+			country = r.Header.Get(countryHeader)
+		}
+		// The request is over by the time the event is handled
+		ctx := context.WithoutCancel(r.Context())
+
+		if event.Type == auth.AuthEventSuccess {
+			queue.add(func() { detector.ObserveAccess(ctx, event.UserID, remoteAddr, country) })
+			return
+		}
+
+		metadata := map[string]string{}
+		if event.Err != nil {
+			metadata["reason"] = event.Err.Error()
+		}
+		if country != "" {
+			metadata["country"] = country
+		}
+		auditEvent := &models.AuditEvent{
+			Action:     "auth." + string(event.Type),
+			ActorID:    event.UserID,
+			SubjectID:  event.UserID,
+			Method:     r.Method,
+			Path:       r.URL.Path,
+			RemoteAddr: remoteAddr,
+			Metadata:   metadata,
+		}
+		queue.add(func() { logger.Record(ctx, auditEvent) })
+	}
+}
+
+// eventQueue runs handlers one at a time in the background. Handlers added while it is
+// full are dropped and counted.
+type eventQueue struct {
+	handlers chan func()
+	dropped  uint64
+}
+
+func newEventQueue(size int) *eventQueue {
+	q := &eventQueue{handlers: make(chan func(), size)}
+	go q.run()
+	return q
+}
+
+func (q *eventQueue) add(handler func()) {
+	select {
+	case q.handlers <- handler:
+	default:
+		atomic.AddUint64(&q.dropped, 1)
+	}
+}
+
+func (q *eventQueue) run() {
+	for handler := range q.handlers {
+		handler()
+		if dropped := atomic.SwapUint64(&q.dropped, 0); dropped > 0 {
+			log.Printf("Dropped %d auth events while the security queue was full", dropped)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Notifier delivers security alerts to humans or other systems
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// LogNotifier writes alerts to the application log
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, alert Alert) error {
+	log.Printf("SECURITY ALERT [%s] user=%q addr=%q: %s", alert.Kind, alert.UserID, alert.RemoteAddr, alert.Message)
+	return nil
+}
+
+// WebhookNotifier posts alerts as JSON to a URL (e.g. a chat or paging integration)
+type WebhookNotifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting to url
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:        url,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("security webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// MultiNotifier sends alerts to every notifier, returning the first error
+type MultiNotifier []Notifier
+
+func (m MultiNotifier) Notify(ctx context.Context, alert Alert) error {
+	var firstErr error
+	for _, n := range m {
+		if err := n.Notify(ctx, alert); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
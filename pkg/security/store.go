@@ -0,0 +1,108 @@
+package security
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps the detector's failure counts, raised alerts and known countries.
+// MemoryStore keeps them per process; cache.SecurityStore shares them between instances.
+type Store interface {
+	// AddFailure records a failure for key at now and returns the number of failures
+	// for key within window
+	AddFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error)
+	// MarkAlerted records an alert for key at now, reporting false if one was already
+	// recorded within window
+	MarkAlerted(ctx context.Context, key string, now time.Time, window time.Duration) (bool, error)
+	// AddCountry records an access by the user from country, reporting whether it is
+	// the first from country by a user seen in other countries before
+	AddCountry(ctx context.Context, userID, country string) (bool, error)
+}
+
+// MemoryStore is a Store for a single instance
+type MemoryStore struct {
+	mu        sync.Mutex
+	failures  map[string][]time.Time
+	alerted   map[string]time.Time
+	countries map[string]map[string]bool
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		failures:  make(map[string][]time.Time),
+		alerted:   make(map[string]time.Time),
+		countries: make(map[string]map[string]bool),
+	}
+}
+
+// AddFailure implements Store
+func (s *MemoryStore) AddFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := now.Add(-window)
+	if len(s.failures) >= maxTrackedKeys {
+		s.sweepLocked(cutoff)
+	}
+
+	times := s.failures[key]
+	kept := times[:0]
+	for _, t := range times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	kept = append(kept, now)
+	s.failures[key] = kept
+
+	return len(kept), nil
+}
+
+// MarkAlerted implements Store
+func (s *MemoryStore) MarkAlerted(ctx context.Context, key string, now time.Time, window time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.alerted[key]; ok && now.Sub(last) < window {
+		return false, nil
+	}
+	if len(s.alerted) >= maxTrackedKeys {
+		s.sweepLocked(now.Add(-window))
+	}
+	s.alerted[key] = now
+	return true, nil
+}
+
+// AddCountry implements Store
+func (s *MemoryStore) AddCountry(ctx context.Context, userID, country string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen, known := s.countries[userID]
+	if !known {
+		if len(s.countries) >= maxTrackedKeys {
+			s.countries = make(map[string]map[string]bool)
+		}
+		seen = make(map[string]bool)
+		s.countries[userID] = seen
+	}
+	isNew := len(seen) > 0 && !seen[country]
+	seen[country] = true
+	return isNew, nil
+}
+
+// sweepLocked drops keys without failures or alerts inside the window
+func (s *MemoryStore) sweepLocked(cutoff time.Time) {
+	for key, times := range s.failures {
+		if len(times) == 0 || !times[len(times)-1].After(cutoff) {
+			delete(s.failures, key)
+		}
+	}
+	for key, at := range s.alerted {
+		if !at.After(cutoff) {
+			delete(s.alerted, key)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/notify"
+	"sample/task-management-system/pkg/repository"
+)
+
+// accountRoles are the roles of new accounts
+var accountRoles = []string{"user"}
+
+// AccountService signs users up with an email address and password and lets them reset
+// forgotten passwords
+type AccountService interface {
+	// SignUp creates an account and issues tokens for it
+	SignUp(ctx context.Context, input *models.AccountSignup) (*auth.TokenPair, error)
+	// Login issues tokens for the account with the email address and password
+	Login(ctx context.Context, input *models.AccountLogin) (*auth.TokenPair, error)
+	// RequestPasswordReset emails a reset link to the address if it has an account. It
+	// succeeds either way, so it can't be used to find out which addresses have one.
+	RequestPasswordReset(ctx context.Context, input *models.PasswordResetRequest) error
+	// ResetPassword sets a new password with the token from a reset link and issues tokens
+	ResetPassword(ctx context.Context, input *models.PasswordReset) (*auth.TokenPair, error)
+}
+
+type accountService struct {
+	repo    repository.UserRepository
+	tokens  *auth.TokenManager
+	sender  notify.Sender
+	baseURL string
+	cost    int
+	now     func() time.Time
+}
+
+// NewAccountService creates an account service. Reset emails link to
+// baseURL + "/account/reset?token=...".
+func NewAccountService(repo repository.UserRepository, tokens *auth.TokenManager, sender notify.Sender, baseURL string) AccountService {
+	return &accountService{
+		repo:    repo,
+		tokens:  tokens,
+		sender:  sender,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		cost:    bcrypt.DefaultCost,
+		now:     time.Now,
+	}
+}
+
+func (s *accountService) SignUp(ctx context.Context, input *models.AccountSignup) (*auth.TokenPair, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), s.cost)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.repo.Create(ctx, &models.Account{Email: input.Email, Roles: accountRoles}, string(hash))
+	if err != nil {
+		return nil, err
+	}
+	return s.tokens.CreateTokenPair(ctx, account.ID, account.Roles)
+}
+
+func (s *accountService) Login(ctx context.Context, input *models.AccountLogin) (*auth.TokenPair, error) {
+	account, hash, err := s.repo.GetByEmail(ctx, strings.TrimSpace(input.Email))
+	if errors.Is(err, models.ErrUserNotFound) {
+		// Hash anyway, so unknown addresses take as long as wrong passwords
+		bcrypt.GenerateFromPassword([]byte(input.Password), s.cost)
+		return nil, models.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(input.Password)) != nil {
+		return nil, models.ErrInvalidCredentials
+	}
+	return s.tokens.CreateTokenPair(ctx, account.ID, account.Roles)
+}
+
+func (s *accountService) RequestPasswordReset(ctx context.Context, input *models.PasswordResetRequest) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+	account, _, err := s.repo.GetByEmail(ctx, input.Email)
+	if errors.Is(err, models.ErrUserNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// Reset tokens are random and stored hashed like invitation tokens
+	token, err := newInvitationToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := s.now().Add(models.PasswordResetTTL)
+	if err := s.repo.CreatePasswordReset(ctx, account.ID, hashInvitationToken(token), expiresAt); err != nil {
+		return err
+	}
+
+	// Failing here would tell the caller the address has an account
+	msg, err := notify.RenderPasswordReset(account, s.baseURL+"/account/reset?token="+url.QueryEscape(token), expiresAt)
+	if err == nil {
+		err = s.sender.Send(ctx, msg)
+	}
+	if err != nil {
+		log.Printf("Failed to send password reset email to account %s: %v", account.ID, err)
+	}
+	return nil
+}
+
+func (s *accountService) ResetPassword(ctx context.Context, input *models.PasswordReset) (*auth.TokenPair, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), s.cost)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.repo.ResetPassword(ctx, hashInvitationToken(input.Token), string(hash), s.now())
+	if err != nil {
+		return nil, err
+	}
+	return s.tokens.CreateTokenPair(ctx, account.ID, account.Roles)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// MockUserRepository is a mock implementation of UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, account *models.Account, passwordHash string) (*models.Account, error) {
+	args := m.Called(ctx, account, passwordHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Account), args.Error(1)
+}
+
+func (m *MockUserRepository) GetRoles(ctx context.Context, userID string) ([]string, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*models.Account, string, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*models.Account), args.String(1), args.Error(2)
+}
+
+func (m *MockUserRepository) CreatePasswordReset(ctx context.Context, userID, tokenHash string, expiresAt time.Time) error {
+	args := m.Called(ctx, userID, tokenHash, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string, now time.Time) (*models.Account, error) {
+	args := m.Called(ctx, tokenHash, passwordHash, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Account), args.Error(1)
+}
+
+func newTestAccountService(repo *MockUserRepository, sender *recordingSender, now time.Time) *accountService {
+	tokens := auth.NewTokenManager([]byte("secret"), "test")
+	svc := NewAccountService(repo, tokens, sender, "https://tasks.example.com/").(*accountService)
+	svc.cost = bcrypt.MinCost
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestSignUpAndLogin(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockUserRepository)
+	svc := newTestAccountService(repo, &recordingSender{}, time.Now())
+	account := &models.Account{ID: "user-1", Email: "dev@example.com", Roles: []string{"user"}}
+
+	var hash string
+	repo.On("Create", ctx, &models.Account{Email: "dev@example.com", Roles: []string{"user"}}, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { hash = args.String(2) }).
+		Return(account, nil).Once()
+
+	tokens, err := svc.SignUp(ctx, &models.AccountSignup{Email: " Dev@Example.com ", Password: "correct horse battery"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotContains(t, hash, "correct horse")
+
+	repo.On("GetByEmail", ctx, "dev@example.com").Return(account, hash, nil)
+	repo.On("GetByEmail", ctx, "nobody@example.com").Return(nil, "", models.ErrUserNotFound)
+
+	_, err = svc.Login(ctx, &models.AccountLogin{Email: "dev@example.com", Password: "correct horse battery"})
+	assert.NoError(t, err)
+	_, err = svc.Login(ctx, &models.AccountLogin{Email: "dev@example.com", Password: "wrong horse battery"})
+	assert.ErrorIs(t, err, models.ErrInvalidCredentials)
+	_, err = svc.Login(ctx, &models.AccountLogin{Email: "nobody@example.com", Password: "correct horse battery"})
+	assert.ErrorIs(t, err, models.ErrInvalidCredentials)
+
+	_, err = svc.SignUp(ctx, &models.AccountSignup{Email: "dev@example.com", Password: "short"})
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestRequestPasswordReset_EmailsTokenAndStoresItsHash(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo := new(MockUserRepository)
+	sender := &recordingSender{}
+	svc := newTestAccountService(repo, sender, now)
+	account := &models.Account{ID: "user-1", Email: "dev@example.com", Roles: []string{"user"}}
+
+	repo.On("GetByEmail", ctx, "dev@example.com").Return(account, "hash", nil)
+	repo.On("GetByEmail", ctx, "nobody@example.com").Return(nil, "", models.ErrUserNotFound)
+	var tokenHash string
+	repo.On("CreatePasswordReset", ctx, "user-1", mock.AnythingOfType("string"), now.Add(models.PasswordResetTTL)).
+		Run(func(args mock.Arguments) { tokenHash = args.String(2) }).
+		Return(nil).Once()
+
+	require.NoError(t, svc.RequestPasswordReset(ctx, &models.PasswordResetRequest{Email: "dev@example.com"}))
+	// Unknown addresses succeed too, without an email
+	require.NoError(t, svc.RequestPasswordReset(ctx, &models.PasswordResetRequest{Email: "nobody@example.com"}))
+
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "dev@example.com", sender.sent[0].To)
+	match := regexp.MustCompile(`/account/reset\?token=(\S+)`).FindStringSubmatch(sender.sent[0].Text)
+	require.Len(t, match, 2)
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+	assert.Equal(t, hashInvitationToken(token), tokenHash)
+
+	repo.On("ResetPassword", ctx, tokenHash, mock.AnythingOfType("string"), now).Return(account, nil).Once()
+	tokens, err := svc.ResetPassword(ctx, &models.PasswordReset{Token: token, Password: "new correct horse"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	repo.AssertExpectations(t)
+}
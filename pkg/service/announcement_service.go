@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// announcementCacheTTL bounds how long other instances may show a changed announcement
+const announcementCacheTTL = 30 * time.Second
+
+// AnnouncementService manages org-wide announcements
+type AnnouncementService interface {
+	CreateAnnouncement(ctx context.Context, createdBy string, input *models.AnnouncementInput) (*models.Announcement, error)
+	ListAnnouncements(ctx context.Context) ([]*models.Announcement, error)
+	UpdateAnnouncement(ctx context.Context, id string, input *models.AnnouncementInput) (*models.Announcement, error)
+	DeleteAnnouncement(ctx context.Context, id string) error
+	// ActiveAnnouncements returns the announcements shown now, most severe first. It
+	// is served from memory so it can run on every request.
+	ActiveAnnouncements(ctx context.Context) ([]*models.Announcement, error)
+}
+
+type announcementService struct {
+	repo repository.AnnouncementRepository
+
+	mu       sync.RWMutex
+	cached   []*models.Announcement
+	loadedAt time.Time
+	now      func() time.Time
+}
+
+// NewAnnouncementService creates a new announcement service
+func NewAnnouncementService(repo repository.AnnouncementRepository) AnnouncementService {
+	return &announcementService{repo: repo, now: time.Now}
+}
+
+func (s *announcementService) CreateAnnouncement(ctx context.Context, createdBy string, input *models.AnnouncementInput) (*models.Announcement, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	announcement, err := s.repo.Create(ctx, &models.Announcement{
+		Message:   input.Message,
+		Severity:  input.Severity,
+		StartsAt:  *input.StartsAt,
+		EndsAt:    input.EndsAt,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	return announcement, nil
+}
+
+func (s *announcementService) ListAnnouncements(ctx context.Context) ([]*models.Announcement, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *announcementService) UpdateAnnouncement(ctx context.Context, id string, input *models.AnnouncementInput) (*models.Announcement, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	announcement, err := s.repo.Update(ctx, id, input)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	return announcement, nil
+}
+
+func (s *announcementService) DeleteAnnouncement(ctx context.Context, id string) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.invalidate()
+	return nil
+}
+
+func (s *announcementService) ActiveAnnouncements(ctx context.Context) ([]*models.Announcement, error) {
+	all, err := s.announcements(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	active := []*models.Announcement{}
+	for _, announcement := range all {
+		if announcement.IsActive(now) {
+			active = append(active, announcement)
+		}
+	}
+	// The repository lists the latest start first, which breaks ties
+	sort.SliceStable(active, func(i, j int) bool {
+		return severityRank(active[i].Severity) > severityRank(active[j].Severity)
+	})
+	return active, nil
+}
+
+// announcements returns every announcement, loading them if the cached copy is stale.
+// Whole windows are cached, so announcements still start and end on time.
+func (s *announcementService) announcements(ctx context.Context) ([]*models.Announcement, error) {
+	s.mu.RLock()
+	cached, loadedAt := s.cached, s.loadedAt
+	s.mu.RUnlock()
+	if cached != nil && s.now().Sub(loadedAt) < announcementCacheTTL {
+		return cached, nil
+	}
+
+	all, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if all == nil {
+		all = []*models.Announcement{}
+	}
+
+	s.mu.Lock()
+	s.cached, s.loadedAt = all, s.now()
+	s.mu.Unlock()
+
+	return all, nil
+}
+
+func (s *announcementService) invalidate() {
+	s.mu.Lock()
+	s.cached = nil
+	s.mu.Unlock()
+}
+
+// severityRank orders severities from info (1) to critical (3)
+func severityRank(severity models.AnnouncementSeverity) int {
+	switch severity {
+	case models.SeverityCritical:
+		return 3
+	case models.SeverityWarning:
+		return 2
+	default:
+		return 1
+	}
+}
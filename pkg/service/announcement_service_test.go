@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockAnnouncementRepository is a mock implementation of AnnouncementRepository
+type MockAnnouncementRepository struct {
+	mock.Mock
+}
+
+func (m *MockAnnouncementRepository) Create(ctx context.Context, announcement *models.Announcement) (*models.Announcement, error) {
+	args := m.Called(ctx, announcement)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementRepository) List(ctx context.Context) ([]*models.Announcement, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementRepository) Update(ctx context.Context, id string, input *models.AnnouncementInput) (*models.Announcement, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Announcement), args.Error(1)
+}
+
+func (m *MockAnnouncementRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestActiveAnnouncements(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ended := now.Add(-time.Minute)
+	ends := now.Add(time.Hour)
+
+	repo := new(MockAnnouncementRepository)
+	svc := NewAnnouncementService(repo).(*announcementService)
+	svc.now = func() time.Time { return now }
+
+	repo.On("List", ctx).Return([]*models.Announcement{
+		{ID: "scheduled", Severity: models.SeverityCritical, StartsAt: now.Add(time.Hour)},
+		{ID: "info", Severity: models.SeverityInfo, StartsAt: now.Add(-time.Minute)},
+		{ID: "warning", Severity: models.SeverityWarning, StartsAt: now.Add(-time.Hour), EndsAt: &ends},
+		{ID: "ended", Severity: models.SeverityCritical, StartsAt: now.Add(-2 * time.Hour), EndsAt: &ended},
+	}, nil).Once()
+
+	active, err := svc.ActiveAnnouncements(ctx)
+	require.NoError(t, err)
+	require.Len(t, active, 2)
+	assert.Equal(t, "warning", active[0].ID)
+	assert.Equal(t, "info", active[1].ID)
+
+	// Served from memory until the cache expires
+	_, err = svc.ActiveAnnouncements(ctx)
+	require.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "List", 1)
+}
+
+func TestActiveAnnouncements_ReloadedAfterWrite(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockAnnouncementRepository)
+	svc := NewAnnouncementService(repo)
+
+	repo.On("List", ctx).Return([]*models.Announcement{}, nil).Once()
+	repo.On("Create", ctx, mock.AnythingOfType("*models.Announcement")).
+		Return(&models.Announcement{ID: "a-1"}, nil)
+	repo.On("List", ctx).Return([]*models.Announcement{
+		{ID: "a-1", Severity: models.SeverityInfo, StartsAt: time.Now().Add(-time.Second)},
+	}, nil).Once()
+
+	active, err := svc.ActiveAnnouncements(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	_, err = svc.CreateAnnouncement(ctx, "admin-1", &models.AnnouncementInput{Message: "Maintenance tonight"})
+	require.NoError(t, err)
+
+	active, err = svc.ActiveAnnouncements(ctx)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, "a-1", active[0].ID)
+}
+
+func TestAnnouncementInput_Validate(t *testing.T) {
+	start := time.Now()
+	before := start.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		input   models.AnnouncementInput
+		wantErr bool
+	}{
+		{name: "defaults", input: models.AnnouncementInput{Message: " Maintenance tonight "}},
+		{name: "empty message", input: models.AnnouncementInput{Message: "  "}, wantErr: true},
+		{name: "multi-line message", input: models.AnnouncementInput{Message: "a\nb"}, wantErr: true},
+		{name: "unknown severity", input: models.AnnouncementInput{Message: "a", Severity: "urgent"}, wantErr: true},
+		{name: "ends before start", input: models.AnnouncementInput{Message: "a", StartsAt: &start, EndsAt: &before}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Maintenance tonight", tt.input.Message)
+			assert.Equal(t, models.SeverityInfo, tt.input.Severity)
+			assert.NotNil(t, tt.input.StartsAt)
+		})
+	}
+}
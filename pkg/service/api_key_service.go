@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// APIKeyService manages API keys for integrations and authenticates requests made with them
+type APIKeyService interface {
+	// CreateKey creates a key acting as the creator, limited to the requested scopes. The
+	// key itself is only returned here; only its hash is stored.
+	CreateKey(ctx context.Context, creator *auth.Claims, input *models.APIKeyCreate) (*models.APIKey, string, error)
+	ListKeys(ctx context.Context, userID string) ([]*models.APIKey, error)
+	RevokeKey(ctx context.Context, userID, id string) error
+	// VerifyAPIKey returns the claims a key authenticates with and records its use
+	VerifyAPIKey(ctx context.Context, key string) (*auth.Claims, error)
+}
+
+type apiKeyService struct {
+	repo repository.APIKeyRepository
+	now  func() time.Time
+}
+
+// NewAPIKeyService creates an API key service
+func NewAPIKeyService(repo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{repo: repo, now: time.Now}
+}
+
+func (s *apiKeyService) CreateKey(ctx context.Context, creator *auth.Claims, input *models.APIKeyCreate) (*models.APIKey, string, error) {
+	if err := input.Validate(s.now()); err != nil {
+		return nil, "", err
+	}
+	// A key acts as its creator indefinitely, so impersonation and delegated tokens, which
+	// act for someone else briefly, can't create one
+	if creator.Act != nil {
+		return nil, "", errors.New("API keys can't be created while acting for another user")
+	}
+
+	// A key never grants more than the token that created it
+	granted := creator.GrantedScopes()
+	for _, scope := range input.Scopes {
+		if !slices.Contains(granted, scope) {
+			return nil, "", fmt.Errorf("scope %q is not granted to you", scope)
+		}
+	}
+	var roles []string
+	for _, role := range creator.Roles {
+		if models.APIKeyRoles[role] {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		return nil, "", errors.New("your roles can't be granted to an API key")
+	}
+
+	key, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+	created, err := s.repo.Create(ctx, &models.APIKey{
+		UserID:    creator.UserID,
+		OrgID:     creator.OrgID,
+		Name:      input.Name,
+		Prefix:    prefix,
+		Roles:     roles,
+		Scopes:    input.Scopes,
+		ExpiresAt: input.ExpiresAt,
+	}, auth.HashAPIKey(key))
+	if err != nil {
+		return nil, "", err
+	}
+	return created, key, nil
+}
+
+func (s *apiKeyService) ListKeys(ctx context.Context, userID string) ([]*models.APIKey, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+func (s *apiKeyService) RevokeKey(ctx context.Context, userID, id string) error {
+	return s.repo.Revoke(ctx, userID, id, s.now())
+}
+
+func (s *apiKeyService) VerifyAPIKey(ctx context.Context, key string) (*auth.Claims, error) {
+	now := s.now()
+	apiKey, err := s.repo.GetActiveByHash(ctx, auth.HashAPIKey(key), now)
+	if errors.Is(err, models.ErrAPIKeyNotFound) {
+		return nil, auth.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Failing to record a use shouldn't fail the request it was used for
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= models.APIKeyUsageGranularity {
+		if err := s.repo.MarkUsed(ctx, apiKey.ID, now); err != nil {
+			log.Printf("Failed to record use of API key %s: %v", apiKey.ID, err)
+		}
+	}
+
+	return &auth.Claims{
+		UserID:   apiKey.UserID,
+		Roles:    apiKey.Roles,
+		Scope:    strings.Join(apiKey.Scopes, " "),
+		OrgID:    apiKey.OrgID,
+		APIKeyID: apiKey.ID,
+	}, nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *models.APIKey, keyHash string) (*models.APIKey, error) {
+	args := m.Called(ctx, key, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) ListByUser(ctx context.Context, userID string) ([]*models.APIKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetActiveByHash(ctx context.Context, keyHash string, now time.Time) (*models.APIKey, error) {
+	args := m.Called(ctx, keyHash, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, userID, id string, now time.Time) error {
+	args := m.Called(ctx, userID, id, now)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) MarkUsed(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func newTestAPIKeyService(repo *MockAPIKeyRepository, now time.Time) *apiKeyService {
+	svc := NewAPIKeyService(repo).(*apiKeyService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestCreateKey_StoresHashWithCreatorsRoles(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo := new(MockAPIKeyRepository)
+	svc := newTestAPIKeyService(repo, now)
+
+	var storedHash string
+	repo.On("Create", ctx, mock.MatchedBy(func(key *models.APIKey) bool {
+		return key.UserID == "user-1" && key.OrgID == "acme" && key.Name == "CI" &&
+			assert.ObjectsAreEqual([]string{"user"}, key.Roles) &&
+			assert.ObjectsAreEqual([]string{auth.ScopeTasksRead}, key.Scopes)
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		storedHash = args.String(2)
+	}).Return(&models.APIKey{ID: "key-1", Name: "CI"}, nil).Once()
+
+	creator := &auth.Claims{UserID: "user-1", Roles: []string{"user", "org_admin"}, OrgID: "acme", FirstParty: true}
+	created, key, err := svc.CreateKey(ctx, creator, &models.APIKeyCreate{Name: " CI ", Scopes: []string{auth.ScopeTasksRead}})
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", created.ID)
+	assert.True(t, strings.HasPrefix(key, "tms_"))
+
+	// Only the hash of the returned key is stored
+	assert.Equal(t, auth.HashAPIKey(key), storedHash)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateKey_Validation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	svc := newTestAPIKeyService(new(MockAPIKeyRepository), now)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		creator auth.Claims
+		input   models.APIKeyCreate
+		want    string
+	}{
+		{"no name", auth.Claims{Roles: []string{"user"}, FirstParty: true}, models.APIKeyCreate{Scopes: []string{"tasks:read"}},
+			"name is required and must be at most 100 characters"},
+		{"no scopes", auth.Claims{Roles: []string{"user"}, FirstParty: true}, models.APIKeyCreate{Name: "CI"},
+			"at least one scope is required"},
+		{"expired", auth.Claims{Roles: []string{"user"}, FirstParty: true}, models.APIKeyCreate{Name: "CI", Scopes: []string{"tasks:read"}, ExpiresAt: &past},
+			"expires_at must be in the future"},
+		{"scope beyond the roles", auth.Claims{Roles: []string{"viewer"}, FirstParty: true}, models.APIKeyCreate{Name: "CI", Scopes: []string{"tasks:write"}},
+			`scope "tasks:write" is not granted to you`},
+		{"scope beyond the token", auth.Claims{Roles: []string{"user"}, Scope: "tasks:read"}, models.APIKeyCreate{Name: "CI", Scopes: []string{"tasks:write"}},
+			`scope "tasks:write" is not granted to you`},
+		{"impersonated", auth.Claims{Roles: []string{"user"}, FirstParty: true, Act: &auth.Actor{Subject: "admin-1"}},
+			models.APIKeyCreate{Name: "CI", Scopes: []string{"tasks:read"}},
+			"API keys can't be created while acting for another user"},
+		{"unscoped token", auth.Claims{Roles: []string{"user"}}, models.APIKeyCreate{Name: "CI", Scopes: []string{"tasks:read"}},
+			`scope "tasks:read" is not granted to you`},
+		{"admin only", auth.Claims{Roles: []string{"admin"}, FirstParty: true}, models.APIKeyCreate{Name: "CI", Scopes: []string{"tasks:read"}},
+			"your roles can't be granted to an API key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := svc.CreateKey(ctx, &tt.creator, &tt.input)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestVerifyAPIKey(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo := new(MockAPIKeyRepository)
+	svc := newTestAPIKeyService(repo, now)
+
+	recent := now.Add(-10 * time.Second)
+	stale := now.Add(-time.Hour)
+	key := func(lastUsed *time.Time) *models.APIKey {
+		return &models.APIKey{ID: "key-1", UserID: "user-1", OrgID: "acme", Roles: []string{"user"},
+			Scopes: []string{auth.ScopeTasksRead, auth.ScopeTasksWrite}, LastUsedAt: lastUsed}
+	}
+	repo.On("GetActiveByHash", ctx, auth.HashAPIKey("tms_stale"), now).Return(key(&stale), nil).Once()
+	repo.On("GetActiveByHash", ctx, auth.HashAPIKey("tms_recent"), now).Return(key(&recent), nil).Once()
+	repo.On("GetActiveByHash", ctx, auth.HashAPIKey("tms_revoked"), now).Return(nil, models.ErrAPIKeyNotFound).Once()
+	repo.On("MarkUsed", ctx, "key-1", now).Return(errors.New("db down")).Once()
+
+	// A failure to record the use doesn't fail the request
+	claims, err := svc.VerifyAPIKey(ctx, "tms_stale")
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Claims{UserID: "user-1", Roles: []string{"user"}, Scope: "tasks:read tasks:write", OrgID: "acme", APIKeyID: "key-1"}, claims)
+
+	// Uses within the granularity aren't recorded again
+	_, err = svc.VerifyAPIKey(ctx, "tms_recent")
+	require.NoError(t, err)
+
+	_, err = svc.VerifyAPIKey(ctx, "tms_revoked")
+	assert.ErrorIs(t, err, auth.ErrInvalidAPIKey)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sample/task-management-system/pkg/audit"
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// ApprovalService manages the approval tasks can require before they are completed
+type ApprovalService interface {
+	// GetApproval is open to whoever may access the task and to its approver
+	GetApproval(ctx context.Context, taskID string) (*models.TaskApproval, error)
+	// RequestApproval makes the task need approver_id's approval, starting over if it
+	// was already approved or rejected
+	RequestApproval(ctx context.Context, taskID string, request *models.ApprovalRequest) (*models.TaskApproval, error)
+	// RemoveApproval lets the task be completed without approval again
+	RemoveApproval(ctx context.Context, taskID string) error
+	Approve(ctx context.Context, taskID string, decision *models.ApprovalDecision) (*models.TaskApproval, error)
+	Reject(ctx context.Context, taskID string, decision *models.ApprovalDecision) (*models.TaskApproval, error)
+}
+
+var (
+	// ErrNotApprover is returned when someone other than the assigned approver or an
+	// admin decides an approval
+	ErrNotApprover = errors.New("only the assigned approver can decide this approval")
+
+	// ErrSelfApproval is returned when the requester names themselves as the approver
+	ErrSelfApproval = errors.New("approval must be requested from someone else")
+
+	// ErrApprovalRequired is returned when completing a task that isn't approved yet
+	ErrApprovalRequired = errors.New("task needs approval before it can be completed")
+)
+
+type approvalService struct {
+	approvals repository.ApprovalRepository
+	ownership auth.OwnershipChecker
+	audit     audit.Logger
+	now       func() time.Time
+}
+
+// NewApprovalService creates a new approval service recording requests and decisions
+// with logger
+func NewApprovalService(approvals repository.ApprovalRepository, tasks repository.TaskRepository, teams repository.TeamRepository, logger audit.Logger) ApprovalService {
+	return &approvalService{
+		approvals: approvals,
+		ownership: TaskOwnership(tasks, teams),
+		audit:     logger,
+		now:       time.Now,
+	}
+}
+
+func (s *approvalService) GetApproval(ctx context.Context, taskID string) (*models.TaskApproval, error) {
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	approval, err := s.approvals.Get(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if approval.ApproverID != user.ID {
+		if err := auth.CanAccessResource(ctx, taskID, s.ownership); err != nil {
+			return nil, err
+		}
+	}
+	return approval, nil
+}
+
+func (s *approvalService) RequestApproval(ctx context.Context, taskID string, request *models.ApprovalRequest) (*models.TaskApproval, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.CanAccessResource(ctx, taskID, s.ownership); err != nil {
+		return nil, err
+	}
+	if request.ApproverID == user.ID {
+		return nil, ErrSelfApproval
+	}
+
+	approval := &models.TaskApproval{
+		TaskID:      taskID,
+		ApproverID:  request.ApproverID,
+		RequestedBy: user.ID,
+		Status:      models.ApprovalPending,
+		RequestedAt: s.now().UTC(),
+	}
+	if err := s.approvals.Request(ctx, approval); err != nil {
+		return nil, err
+	}
+	s.record(ctx, "task.approval_requested", approval)
+	return approval, nil
+}
+
+func (s *approvalService) RemoveApproval(ctx context.Context, taskID string) error {
+	if err := auth.CanAccessResource(ctx, taskID, s.ownership); err != nil {
+		return err
+	}
+	approval, err := s.approvals.Get(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if err := s.approvals.Remove(ctx, taskID); err != nil {
+		return err
+	}
+	s.record(ctx, "task.approval_removed", approval)
+	return nil
+}
+
+func (s *approvalService) Approve(ctx context.Context, taskID string, decision *models.ApprovalDecision) (*models.TaskApproval, error) {
+	return s.decide(ctx, taskID, models.ApprovalApproved, decision)
+}
+
+func (s *approvalService) Reject(ctx context.Context, taskID string, decision *models.ApprovalDecision) (*models.TaskApproval, error) {
+	return s.decide(ctx, taskID, models.ApprovalRejected, decision)
+}
+
+// decide records the approver's decision. Admins may decide in the approver's place,
+// which the audit event and DecidedBy make visible.
+func (s *approvalService) decide(ctx context.Context, taskID string, status models.ApprovalStatus, decision *models.ApprovalDecision) (*models.TaskApproval, error) {
+	if err := decision.Validate(); err != nil {
+		return nil, err
+	}
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	approval, err := s.approvals.Get(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if approval.ApproverID != user.ID && !auth.HasRole(user, "admin") {
+		return nil, ErrNotApprover
+	}
+
+	approval, err = s.approvals.Decide(ctx, taskID, status, user.ID, decision.Comment, s.now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	s.record(ctx, "task."+string(status), approval)
+	return approval, nil
+}
+
+func (s *approvalService) record(ctx context.Context, action string, approval *models.TaskApproval) {
+	metadata := map[string]string{
+		"task_id":     approval.TaskID,
+		"approver_id": approval.ApproverID,
+	}
+	if approval.Comment != "" {
+		metadata["comment"] = approval.Comment
+	}
+	s.audit.Record(ctx, &models.AuditEvent{Action: action, Metadata: metadata})
+}
+
+// approvalTaskService rejects completing tasks that need approval and aren't approved
+type approvalTaskService struct {
+	TaskService
+	approvals repository.ApprovalRepository
+}
+
+// NewApprovalTaskService wraps a TaskService so a task that needs approval can only be
+// completed once approved. Unlike blockers, TaskUpdate.Force doesn't skip the check.
+func NewApprovalTaskService(next TaskService, approvals repository.ApprovalRepository) TaskService {
+	return &approvalTaskService{
+		TaskService: next,
+		approvals:   approvals,
+	}
+}
+
+func (s *approvalTaskService) UpdateTask(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error) {
+	if task.Status != nil && *task.Status == models.StatusCompleted {
+		approval, err := s.approvals.Get(ctx, id)
+		if err != nil && !errors.Is(err, models.ErrApprovalNotFound) {
+			return nil, err
+		}
+		if approval != nil && approval.Status != models.ApprovalApproved {
+			return nil, ErrApprovalRequired
+		}
+	}
+
+	return s.TaskService.UpdateTask(ctx, id, task)
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// MockApprovalRepository is a mock implementation of ApprovalRepository
+type MockApprovalRepository struct {
+	mock.Mock
+}
+
+func (m *MockApprovalRepository) Get(ctx context.Context, taskID string) (*models.TaskApproval, error) {
+	args := m.Called(ctx, taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaskApproval), args.Error(1)
+}
+
+func (m *MockApprovalRepository) Request(ctx context.Context, approval *models.TaskApproval) error {
+	args := m.Called(ctx, approval)
+	return args.Error(0)
+}
+
+func (m *MockApprovalRepository) Decide(ctx context.Context, taskID string, status models.ApprovalStatus, decidedBy, comment string, decidedAt time.Time) (*models.TaskApproval, error) {
+	args := m.Called(ctx, taskID, status, decidedBy, comment, decidedAt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaskApproval), args.Error(1)
+}
+
+func (m *MockApprovalRepository) Remove(ctx context.Context, taskID string) error {
+	args := m.Called(ctx, taskID)
+	return args.Error(0)
+}
+
+// recordingAuditLogger keeps the audit events it is given
+type recordingAuditLogger struct {
+	events []*models.AuditEvent
+}
+
+func (l *recordingAuditLogger) Record(ctx context.Context, event *models.AuditEvent) {
+	l.events = append(l.events, event)
+}
+
+func TestRequestApproval(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: "user-1"})
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		owner   string
+		request models.ApprovalRequest
+		wantErr error
+	}{
+		{name: "owner requests approval", owner: "user-1", request: models.ApprovalRequest{ApproverID: " lead "}},
+		{name: "approver required", owner: "user-1", request: models.ApprovalRequest{}},
+		{name: "self approval", owner: "user-1", request: models.ApprovalRequest{ApproverID: "user-1"}, wantErr: ErrSelfApproval},
+		{name: "someone else's task", owner: "user-2", request: models.ApprovalRequest{ApproverID: "lead"}, wantErr: auth.ErrResourceNotOwned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			approvals := new(MockApprovalRepository)
+			tasks := new(MockTaskRepository)
+			logger := &recordingAuditLogger{}
+			svc := NewApprovalService(approvals, tasks, nil, logger).(*approvalService)
+			svc.now = func() time.Time { return now }
+
+			tasks.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1", OwnerID: tt.owner}, nil).Maybe()
+			approvals.On("Request", ctx, mock.Anything).Return(nil).Maybe()
+
+			approval, err := svc.RequestApproval(ctx, "task-1", &tt.request)
+			if tt.request.ApproverID == "" || tt.wantErr != nil {
+				assert.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				approvals.AssertNotCalled(t, "Request", mock.Anything, mock.Anything)
+				assert.Empty(t, logger.events)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, &models.TaskApproval{
+				TaskID:      "task-1",
+				ApproverID:  "lead",
+				RequestedBy: "user-1",
+				Status:      models.ApprovalPending,
+				RequestedAt: now,
+			}, approval)
+			approvals.AssertCalled(t, "Request", ctx, approval)
+			if assert.Len(t, logger.events, 1) {
+				assert.Equal(t, "task.approval_requested", logger.events[0].Action)
+				assert.Equal(t, "task-1", logger.events[0].Metadata["task_id"])
+			}
+		})
+	}
+}
+
+func TestDecideApproval(t *testing.T) {
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	pending := &models.TaskApproval{TaskID: "task-1", ApproverID: "lead", RequestedBy: "user-1", Status: models.ApprovalPending}
+
+	tests := []struct {
+		name    string
+		claims  *auth.Claims
+		approve bool
+		wantErr error
+	}{
+		{name: "approver approves", claims: &auth.Claims{UserID: "lead"}, approve: true},
+		{name: "approver rejects", claims: &auth.Claims{UserID: "lead"}},
+		{name: "admin approves", claims: &auth.Claims{UserID: "admin-1", Roles: []string{"admin"}}, approve: true},
+		{name: "requester can't approve", claims: &auth.Claims{UserID: "user-1"}, approve: true, wantErr: ErrNotApprover},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), "claims", tt.claims)
+			approvals := new(MockApprovalRepository)
+			logger := &recordingAuditLogger{}
+			svc := &approvalService{approvals: approvals, audit: logger, now: func() time.Time { return now }}
+
+			status, decide, action := models.ApprovalRejected, svc.Reject, "task.rejected"
+			if tt.approve {
+				status, decide, action = models.ApprovalApproved, svc.Approve, "task.approved"
+			}
+			decided := &models.TaskApproval{TaskID: "task-1", ApproverID: "lead", Status: status, Comment: "ok", DecidedBy: tt.claims.UserID, DecidedAt: &now}
+			approvals.On("Get", ctx, "task-1").Return(pending, nil)
+			approvals.On("Decide", ctx, "task-1", status, tt.claims.UserID, "ok", now).Return(decided, nil).Maybe()
+
+			approval, err := decide(ctx, "task-1", &models.ApprovalDecision{Comment: " ok "})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				approvals.AssertNotCalled(t, "Decide", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				assert.Empty(t, logger.events)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, decided, approval)
+			if assert.Len(t, logger.events, 1) {
+				assert.Equal(t, action, logger.events[0].Action)
+				assert.Equal(t, "ok", logger.events[0].Metadata["comment"])
+			}
+		})
+	}
+}
+
+func TestApprovalTaskService_UpdateTask(t *testing.T) {
+	ctx := context.Background()
+	completed := models.StatusCompleted
+	inProgress := models.StatusInProgress
+
+	tests := []struct {
+		name     string
+		update   *models.TaskUpdate
+		approval *models.TaskApproval
+		wantErr  bool
+	}{
+		{
+			name:   "completing without an approval step",
+			update: &models.TaskUpdate{Status: &completed},
+		},
+		{
+			name:     "completing while pending",
+			update:   &models.TaskUpdate{Status: &completed},
+			approval: &models.TaskApproval{Status: models.ApprovalPending},
+			wantErr:  true,
+		},
+		{
+			name:     "completing after rejection, even forced",
+			update:   &models.TaskUpdate{Status: &completed, Force: true},
+			approval: &models.TaskApproval{Status: models.ApprovalRejected},
+			wantErr:  true,
+		},
+		{
+			name:     "completing once approved",
+			update:   &models.TaskUpdate{Status: &completed},
+			approval: &models.TaskApproval{Status: models.ApprovalApproved},
+		},
+		{
+			name:     "other status changes are not checked",
+			update:   &models.TaskUpdate{Status: &inProgress},
+			approval: &models.TaskApproval{Status: models.ApprovalPending},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			approvals := new(MockApprovalRepository)
+			next := &updateOnlyTaskService{}
+			svc := NewApprovalTaskService(next, approvals)
+
+			if tt.approval != nil {
+				approvals.On("Get", ctx, "task-1").Return(tt.approval, nil).Maybe()
+			} else {
+				approvals.On("Get", ctx, "task-1").Return(nil, models.ErrApprovalNotFound).Maybe()
+			}
+
+			_, err := svc.UpdateTask(ctx, "task-1", tt.update)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrApprovalRequired)
+				assert.False(t, next.updated)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, next.updated)
+		})
+	}
+}
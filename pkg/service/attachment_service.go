@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/storage"
+)
+
+// DownloadURLExpiry is how long a presigned attachment download URL stays valid
+const DownloadURLExpiry = 15 * time.Minute
+
+// ErrAttachmentTooLarge is returned for uploads over the configured size limit
+var ErrAttachmentTooLarge = errors.New("attachment too large")
+
+// AttachmentService manages files attached to tasks
+type AttachmentService interface {
+	Upload(ctx context.Context, taskID, uploadedBy, fileName, contentType string, size int64, body io.Reader) (*models.TaskAttachment, error)
+	ListAttachments(ctx context.Context, taskID string) ([]*models.TaskAttachment, error)
+	DownloadURL(ctx context.Context, taskID, id string) (string, time.Time, error)
+	DeleteAttachment(ctx context.Context, taskID, id string) error
+}
+
+type attachmentService struct {
+	attachments repository.AttachmentRepository
+	tasks       repository.TaskRepository
+	store       storage.ObjectStore
+	maxSize     int64
+}
+
+// NewAttachmentService creates a new attachment service accepting files up to maxSize bytes
+func NewAttachmentService(attachments repository.AttachmentRepository, tasks repository.TaskRepository, store storage.ObjectStore, maxSize int64) AttachmentService {
+	return &attachmentService{attachments: attachments, tasks: tasks, store: store, maxSize: maxSize}
+}
+
+// Upload stores the file content and then its metadata, removing the content again
+// if the metadata can't be saved
+func (s *attachmentService) Upload(ctx context.Context, taskID, uploadedBy, fileName, contentType string, size int64, body io.Reader) (*models.TaskAttachment, error) {
+	fileName = cleanFileName(fileName)
+	if fileName == "" {
+		return nil, errors.New("file name is required")
+	}
+	if size <= 0 {
+		return nil, errors.New("file is empty")
+	}
+	if size > s.maxSize {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrAttachmentTooLarge, s.maxSize)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	key := "tasks/" + taskID + "/attachments/" + id
+	if err := s.store.Put(ctx, key, body, size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %v", err)
+	}
+
+	created, err := s.attachments.Create(ctx, &models.TaskAttachment{
+		ID:          id,
+		TaskID:      taskID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+		UploadedBy:  uploadedBy,
+	})
+	if err != nil {
+		if delErr := s.store.Delete(ctx, key); delErr != nil {
+			log.Printf("Failed to remove orphaned attachment %s: %v", key, delErr)
+		}
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *attachmentService) ListAttachments(ctx context.Context, taskID string) ([]*models.TaskAttachment, error) {
+	return s.attachments.ListByTask(ctx, taskID)
+}
+
+// DownloadURL returns a presigned URL for the attachment and when it expires
+func (s *attachmentService) DownloadURL(ctx context.Context, taskID, id string) (string, time.Time, error) {
+	attachment, err := s.attachments.GetByID(ctx, taskID, id)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(DownloadURLExpiry)
+	url, err := s.store.PresignGet(ctx, attachment.StorageKey, attachment.FileName, DownloadURLExpiry)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return url, expiresAt, nil
+}
+
+// DeleteAttachment removes the file content before its metadata, so a failed delete can be retried
+func (s *attachmentService) DeleteAttachment(ctx context.Context, taskID, id string) error {
+	attachment, err := s.attachments.GetByID(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		return fmt.Errorf("failed to delete attachment: %v", err)
+	}
+
+	return s.attachments.Delete(ctx, taskID, id)
+}
+
+// cleanFileName strips any client-supplied directories and control characters
+func cleanFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "." || name == "/" {
+		return ""
+	}
+	if len(name) > 255 {
+		name = strings.ToValidUTF8(name[:255], "")
+	}
+	return strings.TrimSpace(name)
+}
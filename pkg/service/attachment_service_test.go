@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockAttachmentRepository is a mock implementation of AttachmentRepository
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) Create(ctx context.Context, a *models.TaskAttachment) (*models.TaskAttachment, error) {
+	args := m.Called(ctx, a)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	if fn, ok := args.Get(0).(func(context.Context, *models.TaskAttachment) *models.TaskAttachment); ok {
+		return fn(ctx, a), args.Error(1)
+	}
+	return args.Get(0).(*models.TaskAttachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, taskID, id string) (*models.TaskAttachment, error) {
+	args := m.Called(ctx, taskID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaskAttachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) ListByTask(ctx context.Context, taskID string) ([]*models.TaskAttachment, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]*models.TaskAttachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) Delete(ctx context.Context, taskID, id string) error {
+	args := m.Called(ctx, taskID, id)
+	return args.Error(0)
+}
+
+// MockObjectStore is a mock implementation of storage.ObjectStore
+type MockObjectStore struct {
+	mock.Mock
+}
+
+func (m *MockObjectStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	args := m.Called(ctx, key, body, size, contentType)
+	return args.Error(0)
+}
+
+func (m *MockObjectStore) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockObjectStore) PresignGet(ctx context.Context, key, fileName string, expires time.Duration) (string, error) {
+	args := m.Called(ctx, key, fileName, expires)
+	return args.String(0), args.Error(1)
+}
+
+func TestUploadAttachment(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		fileName    string
+		contentType string
+		size        int64
+		taskErr     error
+		putErr      error
+		createErr   error
+		wantErr     error
+		wantName    string
+		wantType    string
+	}{
+		{
+			name:        "stores content and metadata",
+			fileName:    "report.pdf",
+			contentType: "application/pdf",
+			size:        1024,
+			wantName:    "report.pdf",
+			wantType:    "application/pdf",
+		},
+		{
+			name:     "strips client paths and defaults content type",
+			fileName: `C:\Users\me\notes.txt`,
+			size:     10,
+			wantName: "notes.txt",
+			wantType: "application/octet-stream",
+		},
+		{
+			name:     "too large",
+			fileName: "big.bin",
+			size:     2048,
+			wantErr:  ErrAttachmentTooLarge,
+		},
+		{
+			name:     "empty file",
+			fileName: "empty.txt",
+			wantErr:  errors.New("file is empty"),
+		},
+		{
+			name:     "task not found",
+			fileName: "report.pdf",
+			size:     10,
+			taskErr:  errors.New("task not found"),
+			wantErr:  errors.New("task not found"),
+		},
+		{
+			name:     "storage failure",
+			fileName: "report.pdf",
+			size:     10,
+			putErr:   errors.New("access denied"),
+			wantErr:  errors.New("failed to store attachment: access denied"),
+		},
+		{
+			name:      "metadata failure removes content",
+			fileName:  "report.pdf",
+			size:      10,
+			createErr: errors.New("connection reset"),
+			wantErr:   errors.New("connection reset"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachments := new(MockAttachmentRepository)
+			tasks := new(MockTaskRepository)
+			store := new(MockObjectStore)
+			svc := NewAttachmentService(attachments, tasks, store, 1024)
+
+			if tt.taskErr != nil {
+				tasks.On("GetByID", ctx, "task-1").Return(nil, tt.taskErr)
+			} else {
+				tasks.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1"}, nil)
+			}
+			keyPrefix := mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "tasks/task-1/attachments/") })
+			store.On("Put", ctx, keyPrefix, mock.Anything, tt.size, mock.Anything).Return(tt.putErr).Maybe()
+			store.On("Delete", ctx, keyPrefix).Return(nil).Maybe()
+			if tt.createErr != nil {
+				attachments.On("Create", ctx, mock.Anything).Return(nil, tt.createErr)
+			} else {
+				attachments.On("Create", ctx, mock.Anything).Return(
+					func(ctx context.Context, a *models.TaskAttachment) *models.TaskAttachment {
+						created := *a
+						created.CreatedAt = time.Now()
+						return &created
+					}, nil).Maybe()
+			}
+
+			created, err := svc.Upload(ctx, "task-1", "user-1", tt.fileName, tt.contentType, tt.size, strings.NewReader("content"))
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr.Error())
+				if tt.createErr != nil {
+					store.AssertCalled(t, "Delete", ctx, keyPrefix)
+				} else {
+					attachments.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, created.ID)
+			assert.Equal(t, "tasks/task-1/attachments/"+created.ID, created.StorageKey)
+			assert.Equal(t, tt.wantName, created.FileName)
+			assert.Equal(t, tt.wantType, created.ContentType)
+			assert.Equal(t, "user-1", created.UploadedBy)
+			store.AssertCalled(t, "Put", ctx, created.StorageKey, mock.Anything, tt.size, tt.wantType)
+		})
+	}
+}
+
+func TestAttachmentDownloadURL(t *testing.T) {
+	ctx := context.Background()
+	attachments := new(MockAttachmentRepository)
+	store := new(MockObjectStore)
+	svc := NewAttachmentService(attachments, new(MockTaskRepository), store, 1024)
+
+	stored := &models.TaskAttachment{ID: "att-1", TaskID: "task-1", FileName: "report.pdf", StorageKey: "tasks/task-1/attachments/att-1"}
+	attachments.On("GetByID", ctx, "task-1", "att-1").Return(stored, nil)
+	attachments.On("GetByID", ctx, "task-2", "att-1").Return(nil, errors.New("attachment not found"))
+	store.On("PresignGet", ctx, stored.StorageKey, "report.pdf", DownloadURLExpiry).Return("https://bucket/signed", nil)
+
+	url, expiresAt, err := svc.DownloadURL(ctx, "task-1", "att-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://bucket/signed", url)
+	assert.WithinDuration(t, time.Now().Add(DownloadURLExpiry), expiresAt, time.Minute)
+
+	// Attachments are only reachable through the task they belong to
+	_, _, err = svc.DownloadURL(ctx, "task-2", "att-1")
+	assert.Error(t, err)
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	ctx := context.Background()
+	stored := &models.TaskAttachment{ID: "att-1", TaskID: "task-1", StorageKey: "tasks/task-1/attachments/att-1"}
+
+	t.Run("removes content then metadata", func(t *testing.T) {
+		attachments := new(MockAttachmentRepository)
+		store := new(MockObjectStore)
+		svc := NewAttachmentService(attachments, new(MockTaskRepository), store, 1024)
+
+		attachments.On("GetByID", ctx, "task-1", "att-1").Return(stored, nil)
+		store.On("Delete", ctx, stored.StorageKey).Return(nil)
+		attachments.On("Delete", ctx, "task-1", "att-1").Return(nil)
+
+		require.NoError(t, svc.DeleteAttachment(ctx, "task-1", "att-1"))
+		attachments.AssertExpectations(t)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("keeps metadata when storage fails", func(t *testing.T) {
+		attachments := new(MockAttachmentRepository)
+		store := new(MockObjectStore)
+		svc := NewAttachmentService(attachments, new(MockTaskRepository), store, 1024)
+
+		attachments.On("GetByID", ctx, "task-1", "att-1").Return(stored, nil)
+		store.On("Delete", ctx, stored.StorageKey).Return(errors.New("throttled"))
+
+		assert.Error(t, svc.DeleteAttachment(ctx, "task-1", "att-1"))
+		attachments.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// BadgeStore keeps badge counts up to date, e.g. cache.BadgeCounter
+type BadgeStore interface {
+	Counts(ctx context.Context, userID string, now time.Time) (*models.TaskBadges, error)
+	Built(ctx context.Context) (bool, error)
+	Rebuild(ctx context.Context, open []*models.Task) error
+}
+
+// BadgeService serves the task counts the UI shows as badges
+type BadgeService interface {
+	// GetBadges returns the counts for userID, with "today" in loc
+	GetBadges(ctx context.Context, userID string, loc *time.Location) (*models.TaskBadges, error)
+
+	// EnsureBuilt rebuilds the counts from the database if the store has none
+	EnsureBuilt(ctx context.Context) error
+}
+
+// rebuildPageSize is the number of tasks loaded per query when rebuilding counts
+const rebuildPageSize = 500
+
+type badgeService struct {
+	store BadgeStore
+	tasks repository.TaskRepository
+	now   func() time.Time
+}
+
+// NewBadgeService creates a new badge service
+func NewBadgeService(store BadgeStore, tasks repository.TaskRepository) BadgeService {
+	return &badgeService{store: store, tasks: tasks, now: time.Now}
+}
+
+func (s *badgeService) GetBadges(ctx context.Context, userID string, loc *time.Location) (*models.TaskBadges, error) {
+	return s.store.Counts(ctx, userID, s.now().In(loc))
+}
+
+func (s *badgeService) EnsureBuilt(ctx context.Context) error {
+	built, err := s.store.Built(ctx)
+	if err != nil || built {
+		return err
+	}
+
+	var open []*models.Task
+	statuses := []models.TaskStatus{models.StatusPending, models.StatusInProgress}
+	for page := 1; ; page++ {
+		tasks, _, err := s.tasks.List(ctx, repository.TaskFilter{Status: statuses, Page: page, Limit: rebuildPageSize})
+		if err != nil {
+			return err
+		}
+		open = append(open, tasks...)
+		if len(tasks) < rebuildPageSize {
+			break
+		}
+	}
+	return s.store.Rebuild(ctx, open)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// MockBadgeStore is a mock implementation of BadgeStore
+type MockBadgeStore struct {
+	mock.Mock
+}
+
+func (m *MockBadgeStore) Counts(ctx context.Context, userID string, now time.Time) (*models.TaskBadges, error) {
+	args := m.Called(ctx, userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaskBadges), args.Error(1)
+}
+
+func (m *MockBadgeStore) Built(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBadgeStore) Rebuild(ctx context.Context, open []*models.Task) error {
+	args := m.Called(ctx, open)
+	return args.Error(0)
+}
+
+func TestBadgeService_GetBadgesUsesLocation(t *testing.T) {
+	store := new(MockBadgeStore)
+	svc := NewBadgeService(store, new(MockTaskRepository)).(*badgeService)
+	svc.now = func() time.Time { return time.Date(2024, 3, 15, 23, 30, 0, 0, time.UTC) }
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	store.On("Counts", mock.Anything, "alice", mock.MatchedBy(func(now time.Time) bool {
+		// 23:30 UTC is already the next day in Berlin
+		return now.Location() == berlin && now.Day() == 16
+	})).Return(&models.TaskBadges{Open: 2}, nil)
+
+	badges, err := svc.GetBadges(context.Background(), "alice", berlin)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), badges.Open)
+	store.AssertExpectations(t)
+}
+
+func TestBadgeService_EnsureBuilt(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("already built", func(t *testing.T) {
+		store := new(MockBadgeStore)
+		store.On("Built", ctx).Return(true, nil)
+
+		assert.NoError(t, NewBadgeService(store, new(MockTaskRepository)).EnsureBuilt(ctx))
+		store.AssertNotCalled(t, "Rebuild", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rebuilds from open tasks", func(t *testing.T) {
+		store := new(MockBadgeStore)
+		repo := new(MockTaskRepository)
+		store.On("Built", ctx).Return(false, nil)
+
+		fullPage := make([]*models.Task, rebuildPageSize)
+		for i := range fullPage {
+			fullPage[i] = &models.Task{ID: "pending", Status: models.StatusPending}
+		}
+		open := []models.TaskStatus{models.StatusPending, models.StatusInProgress}
+		repo.On("List", ctx, repository.TaskFilter{Status: open, Page: 1, Limit: rebuildPageSize}).
+			Return(fullPage, rebuildPageSize+2, nil)
+		repo.On("List", ctx, repository.TaskFilter{Status: open, Page: 2, Limit: rebuildPageSize}).
+			Return([]*models.Task{{ID: "last-pending"}, {ID: "working"}}, rebuildPageSize+2, nil)
+		store.On("Rebuild", ctx, mock.MatchedBy(func(open []*models.Task) bool {
+			return len(open) == rebuildPageSize+2
+		})).Return(nil)
+
+		assert.NoError(t, NewBadgeService(store, repo).EnsureBuilt(ctx))
+		store.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+}
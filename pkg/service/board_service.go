@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// BoardService manages the manual order of tasks in their board columns
+type BoardService interface {
+	// MoveTask places a task between two tasks of its column, or of another column when
+	// the move changes its status
+	MoveTask(ctx context.Context, id string, move *models.TaskMove) (*models.Task, error)
+}
+
+type boardService struct {
+	repo  repository.BoardRepository
+	tasks TaskService
+}
+
+// NewBoardService creates a board service changing statuses through tasks, so a move
+// to another column is checked and recorded like any other status update
+func NewBoardService(repo repository.BoardRepository, tasks TaskService) BoardService {
+	return &boardService{repo: repo, tasks: tasks}
+}
+
+func (s *boardService) MoveTask(ctx context.Context, id string, move *models.TaskMove) (*models.Task, error) {
+	if err := move.Validate(id); err != nil {
+		return nil, err
+	}
+	task, err := s.tasks.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if move.Status != "" && move.Status != task.Status {
+		status := move.Status
+		if _, err := s.tasks.UpdateTask(ctx, id, &models.TaskUpdate{Status: &status}); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Move(ctx, id, move.AfterID, move.BeforeID)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockBoardRepository is a mock implementation of BoardRepository
+type MockBoardRepository struct {
+	mock.Mock
+}
+
+func (m *MockBoardRepository) Move(ctx context.Context, id, afterID, beforeID string) (*models.Task, error) {
+	args := m.Called(ctx, id, afterID, beforeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func TestMoveTask(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("within the column", func(t *testing.T) {
+		repo := new(MockBoardRepository)
+		tasks := new(MockTaskRepository)
+		svc := NewBoardService(repo, NewTaskService(tasks))
+
+		tasks.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1", Status: models.StatusPending}, nil).Once()
+		moved := &models.Task{ID: "task-1", Status: models.StatusPending, Position: 1536}
+		repo.On("Move", ctx, "task-1", "task-2", "task-3").Return(moved, nil).Once()
+
+		task, err := svc.MoveTask(ctx, "task-1", &models.TaskMove{Status: models.StatusPending, AfterID: "task-2", BeforeID: "task-3"})
+		require.NoError(t, err)
+		assert.Equal(t, moved, task)
+		tasks.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("to another column", func(t *testing.T) {
+		repo := new(MockBoardRepository)
+		tasks := new(MockTaskRepository)
+		svc := NewBoardService(repo, NewTaskService(tasks))
+
+		tasks.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1", Status: models.StatusPending}, nil).Once()
+		tasks.On("Update", ctx, "task-1", mock.MatchedBy(func(update *models.TaskUpdate) bool {
+			return update.Status != nil && *update.Status == models.StatusInProgress
+		})).Return(&models.Task{ID: "task-1", Status: models.StatusInProgress}, nil).Once()
+		moved := &models.Task{ID: "task-1", Status: models.StatusInProgress, Position: 1024}
+		repo.On("Move", ctx, "task-1", "", "").Return(moved, nil).Once()
+
+		task, err := svc.MoveTask(ctx, "task-1", &models.TaskMove{Status: models.StatusInProgress})
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusInProgress, task.Status)
+		tasks.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("invalid moves", func(t *testing.T) {
+		repo := new(MockBoardRepository)
+		svc := NewBoardService(repo, NewTaskService(new(MockTaskRepository)))
+
+		for _, move := range []*models.TaskMove{
+			{Status: "blocked"},
+			{AfterID: "task-1"},
+			{AfterID: "task-2", BeforeID: "task-2"},
+		} {
+			_, err := svc.MoveTask(ctx, "task-1", move)
+			assert.Error(t, err)
+		}
+		repo.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
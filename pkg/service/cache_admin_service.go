@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"sample/task-management-system/pkg/metrics"
+	"sample/task-management-system/pkg/models"
+)
+
+// CacheAdminStore is the cache operators manage, e.g. cache.RedisCache
+type CacheAdminStore interface {
+	Invalidate(ctx context.Context, pattern string) (int, error)
+	KeyCounts(ctx context.Context) (map[string]int, error)
+	ServerStats(ctx context.Context) (*models.CacheStats, error)
+}
+
+// CacheAdminService lets operators inspect and flush the cache
+type CacheAdminService interface {
+	// Invalidate deletes the cache keys selected by pattern or tag
+	Invalidate(ctx context.Context, input *models.CacheInvalidation) (*models.CacheInvalidationResult, error)
+
+	// Stats counts the cached keys by prefix and reports memory usage and the hit ratio
+	Stats(ctx context.Context) (*models.CacheStats, error)
+}
+
+type cacheAdminService struct {
+	store CacheAdminStore
+}
+
+// NewCacheAdminService creates a new cache admin service
+func NewCacheAdminService(store CacheAdminStore) CacheAdminService {
+	return &cacheAdminService{store: store}
+}
+
+func (s *cacheAdminService) Invalidate(ctx context.Context, input *models.CacheInvalidation) (*models.CacheInvalidationResult, error) {
+	pattern, err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.store.Invalidate(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Invalidated %d cache keys matching %s", deleted, pattern)
+	metrics.RecordCacheInvalidation(metrics.InvalidationAdmin, deleted)
+	return &models.CacheInvalidationResult{Pattern: pattern, Deleted: deleted}, nil
+}
+
+func (s *cacheAdminService) Stats(ctx context.Context) (*models.CacheStats, error) {
+	stats, err := s.store.ServerStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.store.KeyCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats.KeysByPrefix = counts
+	for _, n := range counts {
+		stats.TotalKeys += n
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	return stats, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockCacheAdminStore is a mock implementation of CacheAdminStore
+type MockCacheAdminStore struct {
+	mock.Mock
+}
+
+func (m *MockCacheAdminStore) Invalidate(ctx context.Context, pattern string) (int, error) {
+	args := m.Called(ctx, pattern)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCacheAdminStore) KeyCounts(ctx context.Context) (map[string]int, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockCacheAdminStore) ServerStats(ctx context.Context) (*models.CacheStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CacheStats), args.Error(1)
+}
+
+func TestCacheAdminInvalidate(t *testing.T) {
+	ctx := context.Background()
+	store := new(MockCacheAdminStore)
+	svc := NewCacheAdminService(store)
+
+	store.On("Invalidate", ctx, "v1:tasks:*").Return(4, nil).Once()
+	store.On("Invalidate", ctx, "progress:proj-1:*").Return(1, nil).Once()
+
+	result, err := svc.Invalidate(ctx, &models.CacheInvalidation{Tag: "tasks"})
+	require.NoError(t, err)
+	assert.Equal(t, &models.CacheInvalidationResult{Pattern: "v1:tasks:*", Deleted: 4}, result)
+
+	result, err = svc.Invalidate(ctx, &models.CacheInvalidation{Pattern: " progress:proj-1:* "})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Deleted)
+	store.AssertExpectations(t)
+}
+
+func TestCacheAdminInvalidate_Validation(t *testing.T) {
+	svc := NewCacheAdminService(new(MockCacheAdminStore))
+
+	tests := []struct {
+		name  string
+		input models.CacheInvalidation
+		want  string
+	}{
+		{"nothing selected", models.CacheInvalidation{}, "pattern or tag is required"},
+		{"both selected", models.CacheInvalidation{Pattern: "v1:*", Tag: "tasks"}, "set either pattern or tag, not both"},
+		{"unknown tag", models.CacheInvalidation{Tag: "everything"}, "tag must be one of progress, ratelimit, tasks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Invalidate(context.Background(), &tt.input)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestCacheAdminStats(t *testing.T) {
+	ctx := context.Background()
+	store := new(MockCacheAdminStore)
+	svc := NewCacheAdminService(store)
+
+	store.On("ServerStats", ctx).Return(&models.CacheStats{UsedMemoryBytes: 2048, Hits: 30, Misses: 10}, nil).Once()
+	store.On("KeyCounts", ctx).Return(map[string]int{"v1": 5, "badges": 3}, nil).Once()
+
+	stats, err := svc.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 8, stats.TotalKeys)
+	assert.Equal(t, map[string]int{"v1": 5, "badges": 3}, stats.KeysByPrefix)
+	assert.Equal(t, int64(2048), stats.UsedMemoryBytes)
+	assert.InDelta(t, 0.75, stats.HitRatio, 1e-9)
+	store.AssertExpectations(t)
+}
@@ -0,0 +1,12 @@
+package service
+
+import "context"
+
+// TaskCacheInvalidator drops cached task responses, e.g. cache.RedisCache. The response
+// cache invalidates on write requests to the API, so services call it for changes made
+// any other way: by background jobs such as imports, and by restoring or purging deleted
+// tasks in bulk, which must drop task lists and single tasks alike.
+type TaskCacheInvalidator interface {
+	// InvalidateTasks drops every cached task response of the organization
+	InvalidateTasks(ctx context.Context, orgID string) error
+}
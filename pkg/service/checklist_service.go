@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// ChecklistService manages the ordered checklist items of a task
+type ChecklistService interface {
+	ListItems(ctx context.Context, taskID string) ([]*models.ChecklistItem, error)
+	AddItem(ctx context.Context, taskID string, input *models.ChecklistItemInput) (*models.ChecklistItem, error)
+	UpdateItem(ctx context.Context, taskID, itemID string, update *models.ChecklistItemUpdate) (*models.ChecklistItem, error)
+	DeleteItem(ctx context.Context, taskID, itemID string) error
+	// ReorderItems puts the checklist in the given order, which must list every item
+	ReorderItems(ctx context.Context, taskID string, order *models.ChecklistOrder) ([]*models.ChecklistItem, error)
+	// ToggleItems checks or unchecks several items, or all of them, at once
+	ToggleItems(ctx context.Context, taskID string, toggle *models.ChecklistToggle) ([]*models.ChecklistItem, error)
+}
+
+type checklistService struct {
+	items repository.ChecklistRepository
+	tasks repository.TaskRepository
+}
+
+// NewChecklistService creates a new checklist service
+func NewChecklistService(items repository.ChecklistRepository, tasks repository.TaskRepository) ChecklistService {
+	return &checklistService{items: items, tasks: tasks}
+}
+
+func (s *checklistService) ListItems(ctx context.Context, taskID string) ([]*models.ChecklistItem, error) {
+	if err := s.checkTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.items.List(ctx, taskID)
+}
+
+func (s *checklistService) AddItem(ctx context.Context, taskID string, input *models.ChecklistItemInput) (*models.ChecklistItem, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.items.Add(ctx, taskID, input)
+}
+
+func (s *checklistService) UpdateItem(ctx context.Context, taskID, itemID string, update *models.ChecklistItemUpdate) (*models.ChecklistItem, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.items.Update(ctx, taskID, itemID, update)
+}
+
+func (s *checklistService) DeleteItem(ctx context.Context, taskID, itemID string) error {
+	if err := s.checkTask(ctx, taskID); err != nil {
+		return err
+	}
+
+	return s.items.Delete(ctx, taskID, itemID)
+}
+
+func (s *checklistService) ReorderItems(ctx context.Context, taskID string, order *models.ChecklistOrder) ([]*models.ChecklistItem, error) {
+	if len(order.ItemIDs) == 0 {
+		return nil, errors.New("item_ids is required")
+	}
+	if err := s.checkTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.items.Reorder(ctx, taskID, order.ItemIDs)
+}
+
+func (s *checklistService) ToggleItems(ctx context.Context, taskID string, toggle *models.ChecklistToggle) ([]*models.ChecklistItem, error) {
+	if err := toggle.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	itemIDs := toggle.ItemIDs
+	if toggle.All {
+		itemIDs = nil
+	}
+	return s.items.SetDone(ctx, taskID, itemIDs, toggle.Done)
+}
+
+// checkTask makes sure the task exists and the caller can see it
+func (s *checklistService) checkTask(ctx context.Context, taskID string) error {
+	task, err := s.tasks.GetByID(ctx, taskID)
+	_, err = visibleTask(ctx, task, err)
+	return err
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockChecklistRepository is a mock implementation of ChecklistRepository
+type MockChecklistRepository struct {
+	mock.Mock
+}
+
+func (m *MockChecklistRepository) List(ctx context.Context, taskID string) ([]*models.ChecklistItem, error) {
+	args := m.Called(ctx, taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ChecklistItem), args.Error(1)
+}
+
+func (m *MockChecklistRepository) Add(ctx context.Context, taskID string, input *models.ChecklistItemInput) (*models.ChecklistItem, error) {
+	args := m.Called(ctx, taskID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChecklistItem), args.Error(1)
+}
+
+func (m *MockChecklistRepository) Update(ctx context.Context, taskID, itemID string, update *models.ChecklistItemUpdate) (*models.ChecklistItem, error) {
+	args := m.Called(ctx, taskID, itemID, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ChecklistItem), args.Error(1)
+}
+
+func (m *MockChecklistRepository) Delete(ctx context.Context, taskID, itemID string) error {
+	args := m.Called(ctx, taskID, itemID)
+	return args.Error(0)
+}
+
+func (m *MockChecklistRepository) Reorder(ctx context.Context, taskID string, itemIDs []string) ([]*models.ChecklistItem, error) {
+	args := m.Called(ctx, taskID, itemIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ChecklistItem), args.Error(1)
+}
+
+func (m *MockChecklistRepository) SetDone(ctx context.Context, taskID string, itemIDs []string, done bool) ([]*models.ChecklistItem, error) {
+	args := m.Called(ctx, taskID, itemIDs, done)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ChecklistItem), args.Error(1)
+}
+
+func TestAddChecklistItem(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{name: "trims text", text: "  Write tests ", want: "Write tests"},
+		{name: "empty text", text: "   ", wantErr: true},
+		{name: "too long", text: strings.Repeat("x", models.MaxChecklistItemLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := new(MockChecklistRepository)
+			tasks := new(MockTaskRepository)
+			svc := NewChecklistService(items, tasks)
+
+			tasks.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1"}, nil).Maybe()
+			items.On("Add", ctx, "task-1", mock.MatchedBy(func(input *models.ChecklistItemInput) bool {
+				return input.Text == tt.want
+			})).Return(&models.ChecklistItem{ID: "item-1", Text: tt.want}, nil).Maybe()
+
+			item, err := svc.AddItem(ctx, "task-1", &models.ChecklistItemInput{Text: tt.text})
+			if tt.wantErr {
+				assert.Error(t, err)
+				items.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, item.Text)
+		})
+	}
+}
+
+func TestToggleChecklistItems(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		toggle  models.ChecklistToggle
+		wantIDs []string
+		wantErr bool
+	}{
+		{name: "listed items", toggle: models.ChecklistToggle{ItemIDs: []string{"a", "b"}, Done: true}, wantIDs: []string{"a", "b"}},
+		{name: "all items", toggle: models.ChecklistToggle{All: true, Done: true}, wantIDs: nil},
+		{name: "items and all", toggle: models.ChecklistToggle{ItemIDs: []string{"a"}, All: true}, wantErr: true},
+		{name: "neither", toggle: models.ChecklistToggle{Done: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := new(MockChecklistRepository)
+			tasks := new(MockTaskRepository)
+			svc := NewChecklistService(items, tasks)
+
+			tasks.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1"}, nil).Maybe()
+			items.On("SetDone", ctx, "task-1", tt.wantIDs, true).
+				Return([]*models.ChecklistItem{{ID: "a", Done: true}}, nil).Maybe()
+
+			_, err := svc.ToggleItems(ctx, "task-1", &tt.toggle)
+			if tt.wantErr {
+				assert.Error(t, err)
+				items.AssertNotCalled(t, "SetDone", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			items.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListChecklistItems_HiddenDraft(t *testing.T) {
+	ctx := context.Background()
+	items := new(MockChecklistRepository)
+	tasks := new(MockTaskRepository)
+	svc := NewChecklistService(items, tasks)
+
+	tasks.On("GetByID", ctx, "draft-1").
+		Return(&models.Task{ID: "draft-1", Status: models.StatusDraft, OwnerID: "alice"}, nil)
+
+	_, err := svc.ListItems(ctx, "draft-1")
+
+	assert.Error(t, err)
+	items.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func TestNewChecklistProgress(t *testing.T) {
+	assert.Nil(t, models.NewChecklistProgress(0, 0))
+	assert.Equal(t, &models.ChecklistProgress{Total: 3, Done: 2, Percent: 66}, models.NewChecklistProgress(3, 2))
+	assert.Equal(t, 100, models.NewChecklistProgress(4, 4).Percent)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// CloneService duplicates tasks
+type CloneService interface {
+	// CloneTask copies a task the caller can see into a new pending task owned by ownerID,
+	// optionally with its subtasks and checklist items
+	CloneTask(ctx context.Context, id, ownerID string, input *models.TaskClone) (*models.Task, error)
+}
+
+type cloneService struct {
+	tasks     TaskService
+	checklist repository.ChecklistRepository
+	now       func() time.Time
+}
+
+// NewCloneService creates a new clone service. Copies are created through tasks so they
+// are validated and moderated like any other task.
+func NewCloneService(tasks TaskService, checklist repository.ChecklistRepository) CloneService {
+	return &cloneService{tasks: tasks, checklist: checklist, now: time.Now}
+}
+
+func (s *cloneService) CloneTask(ctx context.Context, id, ownerID string, input *models.TaskClone) (*models.Task, error) {
+	source, err := s.tasks.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if source.Status == models.StatusDraft {
+		return nil, errors.New("drafts cannot be cloned")
+	}
+
+	dueDate := source.DueDate
+	if input.DueDate != nil {
+		dueDate = *input.DueDate
+	}
+	if !dueDate.After(s.now()) {
+		return nil, errors.New("the task's due date has passed, a new due_date is required")
+	}
+
+	// Subtasks are loaded up front so an oversized tree is rejected before anything is created
+	children := make(map[string][]*models.Task)
+	if input.Subtasks {
+		subtasks, err := s.tasks.ListSubtasks(ctx, id, true)
+		if err != nil {
+			return nil, err
+		}
+		if len(subtasks) > models.MaxClonedSubtasks {
+			return nil, fmt.Errorf("tasks with more than %d subtasks cannot be cloned", models.MaxClonedSubtasks)
+		}
+		for _, subtask := range subtasks {
+			if subtask.Status != models.StatusDraft {
+				children[subtask.ParentID] = append(children[subtask.ParentID], subtask)
+			}
+		}
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		title = source.Title
+	}
+	clone := &cloneRun{
+		service:   s,
+		ownerID:   ownerID,
+		checklist: input.Checklist,
+		shift:     dueDate.Sub(source.DueDate),
+		dueDate:   dueDate,
+		children:  children,
+	}
+	// Copies are not created in one transaction: if a subtask fails, the copies made so
+	// far are kept and the error is returned
+	return clone.copy(ctx, source, title, source.ParentID, dueDate)
+}
+
+// cloneRun copies a task and its subtree
+type cloneRun struct {
+	service   *cloneService
+	ownerID   string
+	checklist bool
+	shift     time.Duration // added to the due dates of subtasks
+	dueDate   time.Time     // due date of the copied task, used for subtasks that would be due already
+	children  map[string][]*models.Task
+}
+
+func (c *cloneRun) copy(ctx context.Context, source *models.Task, title, parentID string, dueDate time.Time) (*models.Task, error) {
+	create := &models.TaskCreate{
+		Title:       title,
+		Description: source.Description,
+		Status:      models.StatusPending,
+		Priority:    source.Priority,
+		DueDate:     dueDate,
+		AssigneeID:  source.AssigneeID,
+		ParentID:    parentID,
+		Tags:        source.Tags,
+		Metadata:    source.Metadata,
+		ProjectKey:  source.ProjectKey,
+		OwnerID:     c.ownerID,
+	}
+	if source.EstimatedMinutes != nil {
+		create.EstimatedMinutes = *source.EstimatedMinutes
+	}
+
+	task, err := c.service.tasks.CreateTask(ctx, create)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.checklist && source.Checklist != nil {
+		items, err := c.service.checklist.List(ctx, source.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if _, err := c.service.checklist.Add(ctx, task.ID, &models.ChecklistItemInput{Text: item.Text}); err != nil {
+				return nil, err
+			}
+		}
+		task.Checklist = models.NewChecklistProgress(len(items), 0)
+	}
+
+	for _, subtask := range c.children[source.ID] {
+		subtaskDue := subtask.DueDate.Add(c.shift)
+		if !subtaskDue.After(c.service.now()) {
+			subtaskDue = c.dueDate
+		}
+		if _, err := c.copy(ctx, subtask, subtask.Title, task.ID, subtaskDue); err != nil {
+			return nil, err
+		}
+	}
+
+	return task, nil
+}
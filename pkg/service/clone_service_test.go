@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+func TestCloneTask(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	due := now.Add(48 * time.Hour)
+	estimate := 90
+
+	source := &models.Task{
+		ID: "task-1", ProjectKey: "PROJ", Title: "Monthly report", Description: "Numbers",
+		Status: models.StatusCompleted, Priority: models.PriorityHigh, DueDate: due, OwnerID: "alice",
+		Tags: []string{"reports"}, EstimatedMinutes: &estimate, Checklist: models.NewChecklistProgress(2, 2),
+	}
+	subtask := &models.Task{ID: "task-2", ProjectKey: "PROJ", Title: "Collect", Status: models.StatusCompleted,
+		Priority: models.PriorityMedium, DueDate: due.Add(-24 * time.Hour), ParentID: "task-1"}
+	draft := &models.Task{ID: "task-3", Title: "Unpublished", Status: models.StatusDraft, ParentID: "task-1", OwnerID: "alice"}
+
+	t.Run("with subtasks and checklist", func(t *testing.T) {
+		repo := new(MockTaskRepository)
+		checklist := new(MockChecklistRepository)
+		svc := NewCloneService(NewTaskService(repo), checklist)
+		nextWeek := due.AddDate(0, 0, 7)
+
+		repo.On("GetByID", ctx, "task-1").Return(source, nil)
+		repo.On("ListSubtasks", ctx, "task-1", true).Return([]*models.Task{subtask, draft}, nil)
+		repo.On("Create", ctx, mock.MatchedBy(func(task *models.TaskCreate) bool {
+			return task.Title == "Monthly report" && task.Status == models.StatusPending && task.OwnerID == "bob" &&
+				task.DueDate.Equal(nextWeek) && task.EstimatedMinutes == 90 && task.Priority == models.PriorityHigh
+		})).Return(&models.Task{ID: "copy-1", Title: "Monthly report"}, nil).Once()
+		repo.On("GetByID", ctx, "copy-1").Return(&models.Task{ID: "copy-1"}, nil)
+		repo.On("Create", ctx, mock.MatchedBy(func(task *models.TaskCreate) bool {
+			// The subtask stays due a day before its parent
+			return task.Title == "Collect" && task.ParentID == "copy-1" && task.DueDate.Equal(nextWeek.Add(-24*time.Hour))
+		})).Return(&models.Task{ID: "copy-2"}, nil).Once()
+		checklist.On("List", ctx, "task-1").Return([]*models.ChecklistItem{
+			{Text: "Draft", Done: true}, {Text: "Send", Done: true},
+		}, nil)
+		checklist.On("Add", ctx, "copy-1", &models.ChecklistItemInput{Text: "Draft"}).Return(&models.ChecklistItem{}, nil)
+		checklist.On("Add", ctx, "copy-1", &models.ChecklistItemInput{Text: "Send"}).Return(&models.ChecklistItem{}, nil)
+
+		task, err := svc.CloneTask(ctx, "task-1", "bob", &models.TaskClone{DueDate: &nextWeek, Subtasks: true, Checklist: true})
+		require.NoError(t, err)
+		assert.Equal(t, "copy-1", task.ID)
+		assert.Equal(t, &models.ChecklistProgress{Total: 2}, task.Checklist)
+		repo.AssertNumberOfCalls(t, "Create", 2)
+		checklist.AssertExpectations(t)
+	})
+
+	t.Run("past due date needs a new one", func(t *testing.T) {
+		repo := new(MockTaskRepository)
+		svc := NewCloneService(NewTaskService(repo), new(MockChecklistRepository))
+		overdue := *source
+		overdue.DueDate = now.Add(-time.Hour)
+		repo.On("GetByID", ctx, "task-1").Return(&overdue, nil)
+
+		_, err := svc.CloneTask(ctx, "task-1", "bob", &models.TaskClone{})
+		assert.Error(t, err)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// ErrUnknownJobType is returned for a job type other than export or import
+var ErrUnknownJobType = errors.New("job type must be export or import")
+
+// DeadLetterService lets admins inspect and requeue jobs given up on after their retries
+type DeadLetterService interface {
+	// ListDeadLetters lists the dead letters not requeued yet, of jobType or of every type
+	// when it is empty
+	ListDeadLetters(ctx context.Context, jobType models.JobType, page, limit int) ([]*models.DeadLetter, int, error)
+	// GetDeadLetter retrieves a dead letter along with its job
+	GetDeadLetter(ctx context.Context, jobType models.JobType, jobID string) (*models.DeadLetter, error)
+	// Requeue queues a dead-lettered job again with a fresh set of attempts. Workers pick
+	// it up on their next poll, or queue it again when they read from a queue.
+	Requeue(ctx context.Context, jobType models.JobType, jobID string) (*models.DeadLetter, error)
+}
+
+type deadLetterService struct {
+	letters repository.DeadLetterRepository
+	exports repository.ExportRepository
+	imports repository.ImportRepository
+	now     func() time.Time
+}
+
+// NewDeadLetterService creates a dead letter service reading jobs from exports and imports
+func NewDeadLetterService(letters repository.DeadLetterRepository, exports repository.ExportRepository, imports repository.ImportRepository) DeadLetterService {
+	return &deadLetterService{letters: letters, exports: exports, imports: imports, now: time.Now}
+}
+
+func (s *deadLetterService) ListDeadLetters(ctx context.Context, jobType models.JobType, page, limit int) ([]*models.DeadLetter, int, error) {
+	if jobType != "" && !models.IsValidJobType(jobType) {
+		return nil, 0, ErrUnknownJobType
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.letters.List(ctx, jobType, page, limit)
+}
+
+func (s *deadLetterService) GetDeadLetter(ctx context.Context, jobType models.JobType, jobID string) (*models.DeadLetter, error) {
+	if !models.IsValidJobType(jobType) {
+		return nil, ErrUnknownJobType
+	}
+	letter, err := s.letters.Get(ctx, jobType, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch jobType {
+	case models.JobExport:
+		job, err := s.exports.GetByID(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		letter.Job = job
+	case models.JobImport:
+		job, err := s.imports.GetByID(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		letter.Job = job
+	}
+	return letter, nil
+}
+
+func (s *deadLetterService) Requeue(ctx context.Context, jobType models.JobType, jobID string) (*models.DeadLetter, error) {
+	if !models.IsValidJobType(jobType) {
+		return nil, ErrUnknownJobType
+	}
+	if err := s.letters.Requeue(ctx, jobType, jobID, s.now()); err != nil {
+		return nil, err
+	}
+	return s.GetDeadLetter(ctx, jobType, jobID)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockDeadLetterRepository is a mock implementation of DeadLetterRepository
+type MockDeadLetterRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeadLetterRepository) List(ctx context.Context, jobType models.JobType, page, limit int) ([]*models.DeadLetter, int, error) {
+	args := m.Called(ctx, jobType, page, limit)
+	return args.Get(0).([]*models.DeadLetter), args.Int(1), args.Error(2)
+}
+
+func (m *MockDeadLetterRepository) Get(ctx context.Context, jobType models.JobType, jobID string) (*models.DeadLetter, error) {
+	args := m.Called(ctx, jobType, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeadLetter), args.Error(1)
+}
+
+func (m *MockDeadLetterRepository) Requeue(ctx context.Context, jobType models.JobType, jobID string, now time.Time) error {
+	args := m.Called(ctx, jobType, jobID, now)
+	return args.Error(0)
+}
+
+func TestListDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	letters := new(MockDeadLetterRepository)
+	svc := NewDeadLetterService(letters, new(MockExportRepository), new(MockImportRepository))
+
+	letters.On("List", ctx, models.JobImport, 1, 20).Return([]*models.DeadLetter{{JobType: models.JobImport, JobID: "import-1"}}, 1, nil).Once()
+
+	listed, total, err := svc.ListDeadLetters(ctx, models.JobImport, 0, 500)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, listed, 1)
+
+	_, _, err = svc.ListDeadLetters(ctx, "webhook", 1, 20)
+	assert.ErrorIs(t, err, ErrUnknownJobType)
+	letters.AssertExpectations(t)
+}
+
+func TestRequeueDeadLetter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	letters := new(MockDeadLetterRepository)
+	exports := new(MockExportRepository)
+	svc := NewDeadLetterService(letters, exports, new(MockImportRepository)).(*deadLetterService)
+	svc.now = func() time.Time { return now }
+
+	letters.On("Requeue", ctx, models.JobExport, "export-1", now).Return(nil).Once()
+	letters.On("Get", ctx, models.JobExport, "export-1").
+		Return(&models.DeadLetter{JobType: models.JobExport, JobID: "export-1", Attempts: 3, RequeuedAt: &now}, nil).Once()
+	exports.On("GetByID", ctx, "export-1").Return(&models.ExportJob{ID: "export-1", Status: models.ExportPending}, nil).Once()
+	letters.On("Requeue", ctx, models.JobExport, "export-1", now).Return(models.ErrJobNotRequeueable).Once()
+
+	letter, err := svc.Requeue(ctx, models.JobExport, "export-1")
+	require.NoError(t, err)
+	assert.Equal(t, &now, letter.RequeuedAt)
+	assert.Equal(t, models.ExportPending, letter.Job.(*models.ExportJob).Status)
+
+	// A job is requeued once
+	_, err = svc.Requeue(ctx, models.JobExport, "export-1")
+	assert.ErrorIs(t, err, models.ErrJobNotRequeueable)
+	letters.AssertExpectations(t)
+	exports.AssertExpectations(t)
+}
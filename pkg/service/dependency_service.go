@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// DependencyService manages which tasks block which
+type DependencyService interface {
+	AddBlocker(ctx context.Context, taskID, blockerID string) error
+	RemoveBlocker(ctx context.Context, taskID, blockerID string) error
+	ListBlockers(ctx context.Context, taskID string) ([]*models.Task, error)
+	ListBlocked(ctx context.Context, taskID string) ([]*models.Task, error)
+}
+
+var (
+	// ErrDependencyCycle is returned when a task would end up blocking itself
+	ErrDependencyCycle = errors.New("a task cannot be blocked by itself or by a task it blocks")
+
+	// ErrTaskBlocked is returned when completing a task that still has open blockers
+	ErrTaskBlocked = errors.New("task has open blockers")
+)
+
+type dependencyService struct {
+	deps  repository.DependencyRepository
+	tasks repository.TaskRepository
+}
+
+// NewDependencyService creates a new dependency service
+func NewDependencyService(deps repository.DependencyRepository, tasks repository.TaskRepository) DependencyService {
+	return &dependencyService{deps: deps, tasks: tasks}
+}
+
+func (s *dependencyService) AddBlocker(ctx context.Context, taskID, blockerID string) error {
+	if taskID == "" || blockerID == "" {
+		return errors.New("task id and blocker id are required")
+	}
+	if taskID == blockerID {
+		return ErrDependencyCycle
+	}
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return err
+	}
+	if _, err := s.tasks.GetByID(ctx, blockerID); err != nil {
+		return errors.New("blocking task not found")
+	}
+
+	// The task must not already block its new blocker, directly or transitively
+	blockers, err := s.deps.ListBlockerIDs(ctx, blockerID)
+	if err != nil {
+		return err
+	}
+	for _, id := range blockers {
+		if id == taskID {
+			return ErrDependencyCycle
+		}
+	}
+
+	return s.deps.Add(ctx, taskID, blockerID)
+}
+
+func (s *dependencyService) RemoveBlocker(ctx context.Context, taskID, blockerID string) error {
+	if taskID == "" || blockerID == "" {
+		return errors.New("task id and blocker id are required")
+	}
+
+	return s.deps.Remove(ctx, taskID, blockerID)
+}
+
+func (s *dependencyService) ListBlockers(ctx context.Context, taskID string) ([]*models.Task, error) {
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.deps.ListBlockers(ctx, taskID)
+}
+
+func (s *dependencyService) ListBlocked(ctx context.Context, taskID string) ([]*models.Task, error) {
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.deps.ListBlocked(ctx, taskID)
+}
+
+// blockedTaskService rejects completing tasks that still have open blockers
+type blockedTaskService struct {
+	TaskService
+	deps repository.DependencyRepository
+}
+
+// NewBlockedTaskService wraps a TaskService so a task with open blockers can only be
+// completed with TaskUpdate.Force
+func NewBlockedTaskService(next TaskService, deps repository.DependencyRepository) TaskService {
+	return &blockedTaskService{
+		TaskService: next,
+		deps:        deps,
+	}
+}
+
+func (s *blockedTaskService) UpdateTask(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error) {
+	if task.Status != nil && *task.Status == models.StatusCompleted && !task.Force {
+		blockers, err := s.deps.ListBlockers(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		var open []string
+		for _, blocker := range blockers {
+			if blocker.IsOpen() {
+				open = append(open, blocker.ID)
+			}
+		}
+		if len(open) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrTaskBlocked, strings.Join(open, ", "))
+		}
+	}
+
+	return s.TaskService.UpdateTask(ctx, id, task)
+}
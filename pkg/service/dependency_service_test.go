@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockDependencyRepository is a mock implementation of DependencyRepository
+type MockDependencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockDependencyRepository) Add(ctx context.Context, taskID, blockerID string) error {
+	args := m.Called(ctx, taskID, blockerID)
+	return args.Error(0)
+}
+
+func (m *MockDependencyRepository) Remove(ctx context.Context, taskID, blockerID string) error {
+	args := m.Called(ctx, taskID, blockerID)
+	return args.Error(0)
+}
+
+func (m *MockDependencyRepository) ListBlockers(ctx context.Context, taskID string) ([]*models.Task, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
+func (m *MockDependencyRepository) ListBlocked(ctx context.Context, taskID string) ([]*models.Task, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
+func (m *MockDependencyRepository) ListBlockerIDs(ctx context.Context, taskID string) ([]string, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// updateOnlyTaskService records UpdateTask calls; other methods are not used
+type updateOnlyTaskService struct {
+	TaskService
+	updated bool
+}
+
+func (s *updateOnlyTaskService) UpdateTask(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error) {
+	s.updated = true
+	return &models.Task{ID: id}, nil
+}
+
+func TestAddBlocker(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		taskID     string
+		blockerID  string
+		blockerIDs []string // tasks already blocking blockerID
+		wantErr    error
+	}{
+		{
+			name:       "adds blocker",
+			taskID:     "task-1",
+			blockerID:  "task-2",
+			blockerIDs: []string{"task-3"},
+		},
+		{
+			name:      "task cannot block itself",
+			taskID:    "task-1",
+			blockerID: "task-1",
+			wantErr:   ErrDependencyCycle,
+		},
+		{
+			name:       "direct cycle",
+			taskID:     "task-1",
+			blockerID:  "task-2",
+			blockerIDs: []string{"task-1"},
+			wantErr:    ErrDependencyCycle,
+		},
+		{
+			name:       "transitive cycle",
+			taskID:     "task-1",
+			blockerID:  "task-2",
+			blockerIDs: []string{"task-3", "task-4", "task-1"},
+			wantErr:    ErrDependencyCycle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := new(MockDependencyRepository)
+			tasks := new(MockTaskRepository)
+			svc := NewDependencyService(deps, tasks)
+
+			tasks.On("GetByID", ctx, mock.Anything).Return(&models.Task{}, nil).Maybe()
+			deps.On("ListBlockerIDs", ctx, tt.blockerID).Return(tt.blockerIDs, nil).Maybe()
+			deps.On("Add", ctx, tt.taskID, tt.blockerID).Return(nil).Maybe()
+
+			err := svc.AddBlocker(ctx, tt.taskID, tt.blockerID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				deps.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			deps.AssertCalled(t, "Add", ctx, tt.taskID, tt.blockerID)
+		})
+	}
+}
+
+func TestAddBlocker_TaskNotFound(t *testing.T) {
+	ctx := context.Background()
+	deps := new(MockDependencyRepository)
+	tasks := new(MockTaskRepository)
+	svc := NewDependencyService(deps, tasks)
+
+	tasks.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1"}, nil)
+	tasks.On("GetByID", ctx, "missing").Return(nil, errors.New("task not found"))
+
+	err := svc.AddBlocker(ctx, "task-1", "missing")
+
+	assert.Error(t, err)
+	deps.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBlockedTaskService_UpdateTask(t *testing.T) {
+	ctx := context.Background()
+	completed := models.StatusCompleted
+	inProgress := models.StatusInProgress
+
+	openBlocker := &models.Task{ID: "task-2", Status: models.StatusPending}
+	doneBlocker := &models.Task{ID: "task-3", Status: models.StatusCompleted}
+
+	tests := []struct {
+		name     string
+		update   *models.TaskUpdate
+		blockers []*models.Task
+		wantErr  bool
+	}{
+		{
+			name:     "completing with open blocker",
+			update:   &models.TaskUpdate{Status: &completed},
+			blockers: []*models.Task{openBlocker, doneBlocker},
+			wantErr:  true,
+		},
+		{
+			name:     "completing with completed blockers",
+			update:   &models.TaskUpdate{Status: &completed},
+			blockers: []*models.Task{doneBlocker},
+		},
+		{
+			name:     "forced completion",
+			update:   &models.TaskUpdate{Status: &completed, Force: true},
+			blockers: []*models.Task{openBlocker},
+		},
+		{
+			name:     "other status changes are not checked",
+			update:   &models.TaskUpdate{Status: &inProgress},
+			blockers: []*models.Task{openBlocker},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := new(MockDependencyRepository)
+			next := &updateOnlyTaskService{}
+			svc := NewBlockedTaskService(next, deps)
+
+			deps.On("ListBlockers", ctx, "task-1").Return(tt.blockers, nil).Maybe()
+
+			_, err := svc.UpdateTask(ctx, "task-1", tt.update)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrTaskBlocked)
+				assert.Contains(t, err.Error(), openBlocker.ID)
+				assert.False(t, next.updated)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, next.updated)
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/notify"
+	"sample/task-management-system/pkg/repository"
+)
+
+// maxPendingDeprecationClients bounds the clients counted between flushes, so requests
+// with ever-changing user agents can't grow the counts without limit
+const maxPendingDeprecationClients = 10000
+
+// DeprecationService tracks which clients still use deprecated endpoints, parameters and
+// API versions, and tells their users before the sunset dates
+type DeprecationService interface {
+	// Record counts a request of the usage's client. Requests are counted in memory and
+	// stored by Flush, so recording never slows down the request.
+	Record(usage *models.DeprecationUsage)
+	// Flush stores the requests counted since the last flush
+	Flush(ctx context.Context) error
+	// Report lists every declared deprecation with the clients that used it, after
+	// storing this replica's pending counts
+	Report(ctx context.Context) ([]*models.DeprecationReport, error)
+	// NotifyUpcoming emails and sends a deprecation.notice webhook event to the users of
+	// clients that used a deprecation whose sunset is within the notice window, once per
+	// client
+	NotifyUpcoming(ctx context.Context) error
+	// Run flushes and sends notices every interval until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// deprecationUsageKey identifies a client's pending usage of a deprecation
+type deprecationUsageKey struct {
+	deprecationID string
+	clientKind    models.DeprecationClientKind
+	client        string
+	userID        string
+}
+
+type deprecationService struct {
+	repo         repository.DeprecationRepository
+	deprecations []models.Deprecation
+	noticeWindow time.Duration
+	preferences  repository.PreferencesRepository
+	sender       notify.Sender
+	webhooks     WebhookService
+
+	mu      sync.Mutex
+	pending map[deprecationUsageKey]*models.DeprecationUsage
+	dropped int
+	now     func() time.Time
+}
+
+// NewDeprecationService creates a deprecation service for the declared deprecations.
+// Users are notified noticeWindow before a sunset date, by email when sender is set and
+// through their webhooks when webhooks is set; a zero window sends no notices.
+func NewDeprecationService(repo repository.DeprecationRepository, deprecations []models.Deprecation, noticeWindow time.Duration, preferences repository.PreferencesRepository, sender notify.Sender, webhooks WebhookService) DeprecationService {
+	return &deprecationService{
+		repo:         repo,
+		deprecations: deprecations,
+		noticeWindow: noticeWindow,
+		preferences:  preferences,
+		sender:       sender,
+		webhooks:     webhooks,
+		pending:      make(map[deprecationUsageKey]*models.DeprecationUsage),
+		now:          time.Now,
+	}
+}
+
+func (s *deprecationService) Record(usage *models.DeprecationUsage) {
+	key := deprecationUsageKey{usage.DeprecationID, usage.ClientKind, usage.Client, usage.UserID}
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counted, ok := s.pending[key]
+	if !ok {
+		if len(s.pending) >= maxPendingDeprecationClients {
+			s.dropped++
+			return
+		}
+		counted = &models.DeprecationUsage{
+			DeprecationID: usage.DeprecationID,
+			ClientKind:    usage.ClientKind,
+			Client:        usage.Client,
+			UserID:        usage.UserID,
+			FirstSeenAt:   now,
+		}
+		s.pending[key] = counted
+	}
+	if usage.OrgID != "" {
+		counted.OrgID = usage.OrgID
+	}
+	counted.Requests++
+	counted.LastSeenAt = now
+}
+
+func (s *deprecationService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	pending, dropped := s.pending, s.dropped
+	s.pending, s.dropped = make(map[deprecationUsageKey]*models.DeprecationUsage), 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		log.Printf("Dropped %d uses of deprecations because too many clients were counted", dropped)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	usage := make([]*models.DeprecationUsage, 0, len(pending))
+	for _, u := range pending {
+		usage = append(usage, u)
+	}
+	// Failed counts are not retried; the report only needs to show who still calls
+	return s.repo.RecordUsage(ctx, usage)
+}
+
+func (s *deprecationService) Report(ctx context.Context) ([]*models.DeprecationReport, error) {
+	if err := s.Flush(ctx); err != nil {
+		return nil, err
+	}
+	usage, err := s.repo.ListUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*models.DeprecationReport, 0, len(s.deprecations))
+	byID := make(map[string]*models.DeprecationReport)
+	for _, deprecation := range s.deprecations {
+		report := &models.DeprecationReport{Deprecation: deprecation, Clients: []*models.DeprecationUsage{}}
+		reports = append(reports, report)
+		byID[deprecation.ID] = report
+	}
+	// Usage of deprecations that were removed since is left out
+	for _, u := range usage {
+		if report, ok := byID[u.DeprecationID]; ok {
+			report.Requests += u.Requests
+			report.Clients = append(report.Clients, u)
+		}
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Sunset.Before(reports[j].Sunset)
+	})
+	return reports, nil
+}
+
+func (s *deprecationService) NotifyUpcoming(ctx context.Context) error {
+	if s.noticeWindow <= 0 {
+		return nil
+	}
+
+	now := s.now()
+	var upcoming []*models.Deprecation
+	for i := range s.deprecations {
+		deprecation := &s.deprecations[i]
+		if now.Before(deprecation.Sunset) && deprecation.Sunset.Sub(now) <= s.noticeWindow {
+			upcoming = append(upcoming, deprecation)
+		}
+	}
+	if len(upcoming) == 0 {
+		return nil
+	}
+
+	usage, err := s.repo.ListUsage(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, deprecation := range upcoming {
+		// One notice per user however many of their clients used the deprecation
+		byUser := make(map[string][]*models.DeprecationUsage)
+		for _, u := range usage {
+			if u.DeprecationID != deprecation.ID || u.UserID == "" || u.NotifiedAt != nil {
+				continue
+			}
+			marked, err := s.repo.MarkNotified(ctx, u, now)
+			if err != nil {
+				return err
+			}
+			if marked {
+				byUser[u.UserID] = append(byUser[u.UserID], u)
+			}
+		}
+
+		users := make([]string, 0, len(byUser))
+		for userID := range byUser {
+			users = append(users, userID)
+		}
+		sort.Strings(users)
+		for _, userID := range users {
+			// Clients are marked before the notice is sent, so a failed notice is not retried
+			if err := s.notify(ctx, userID, deprecation, byUser[userID]); err != nil {
+				log.Printf("Failed to notify user %s of deprecation %s: %v", userID, deprecation.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// notify emails the user, when they have an email address, and sends the notice to the
+// webhooks they subscribed to it
+func (s *deprecationService) notify(ctx context.Context, userID string, deprecation *models.Deprecation, clients []*models.DeprecationUsage) error {
+	var errs []error
+	if s.sender != nil {
+		errs = append(errs, s.email(ctx, userID, deprecation, clients))
+	}
+	if s.webhooks != nil {
+		_, err := s.webhooks.Notify(ctx, userID, &models.WebhookEvent{
+			ID:         uuid.New().String(),
+			Type:       models.DeprecationNoticeEvent,
+			OccurredAt: s.now(),
+			Data:       &models.WebhookDeprecationData{Deprecation: deprecation, Clients: clients},
+		})
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (s *deprecationService) email(ctx context.Context, userID string, deprecation *models.Deprecation, clients []*models.DeprecationUsage) error {
+	preferences, err := s.preferences.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if preferences.Email == "" {
+		return nil
+	}
+
+	msg, err := notify.RenderDeprecationNotice(preferences.Email, deprecation, clients)
+	if err != nil {
+		return err
+	}
+	return s.sender.Send(ctx, msg)
+}
+
+func (s *deprecationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil {
+				log.Printf("Failed to store deprecation usage: %v", err)
+			}
+			if err := s.NotifyUpcoming(ctx); err != nil {
+				log.Printf("Failed to send deprecation notices: %v", err)
+			}
+		}
+	}
+}
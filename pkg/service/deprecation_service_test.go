@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockDeprecationRepository is a mock implementation of DeprecationRepository
+type MockDeprecationRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeprecationRepository) RecordUsage(ctx context.Context, usage []*models.DeprecationUsage) error {
+	args := m.Called(ctx, usage)
+	return args.Error(0)
+}
+
+func (m *MockDeprecationRepository) ListUsage(ctx context.Context) ([]*models.DeprecationUsage, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DeprecationUsage), args.Error(1)
+}
+
+func (m *MockDeprecationRepository) MarkNotified(ctx context.Context, usage *models.DeprecationUsage, at time.Time) (bool, error) {
+	args := m.Called(ctx, usage, at)
+	return args.Bool(0), args.Error(1)
+}
+
+var testDeprecations = []models.Deprecation{
+	{ID: "v1", Kind: models.DeprecatedVersion, Version: "v1", Sunset: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	{ID: "tasks-per-page", Kind: models.DeprecatedParameter, Route: "/api/v1/tasks", Parameter: "per_page",
+		Sunset: time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), Replacement: "limit"},
+}
+
+func TestDeprecationService_RecordAndReport(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo := new(MockDeprecationRepository)
+	svc := NewDeprecationService(repo, testDeprecations, 0, nil, nil, nil).(*deprecationService)
+	svc.now = func() time.Time { return now }
+
+	svc.Record(&models.DeprecationUsage{DeprecationID: "tasks-per-page", ClientKind: models.DeprecationClientAPIKey, Client: "key-1", UserID: "user-1"})
+	now = now.Add(time.Minute)
+	svc.Record(&models.DeprecationUsage{DeprecationID: "tasks-per-page", ClientKind: models.DeprecationClientAPIKey, Client: "key-1", UserID: "user-1", OrgID: "acme"})
+
+	stored := []*models.DeprecationUsage{
+		{DeprecationID: "tasks-per-page", ClientKind: models.DeprecationClientAPIKey, Client: "key-1", UserID: "user-1", Requests: 7},
+		{DeprecationID: "tasks-per-page", ClientKind: models.DeprecationClientUserAgent, Client: "curl/8.0", Requests: 2},
+		{DeprecationID: "removed", ClientKind: models.DeprecationClientUserAgent, Client: "curl/8.0", Requests: 1},
+	}
+	// Both requests are stored as one row
+	repo.On("RecordUsage", ctx, []*models.DeprecationUsage{{
+		DeprecationID: "tasks-per-page",
+		ClientKind:    models.DeprecationClientAPIKey,
+		Client:        "key-1",
+		UserID:        "user-1",
+		OrgID:         "acme",
+		Requests:      2,
+		FirstSeenAt:   now.Add(-time.Minute),
+		LastSeenAt:    now,
+	}}).Return(nil).Once()
+	repo.On("ListUsage", ctx).Return(stored, nil).Once()
+
+	reports, err := svc.Report(ctx)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	// Soonest sunset first; deprecations nobody used are listed too
+	assert.Equal(t, "tasks-per-page", reports[0].ID)
+	assert.Equal(t, int64(9), reports[0].Requests)
+	assert.Equal(t, stored[:2], reports[0].Clients)
+	assert.Equal(t, "v1", reports[1].ID)
+	assert.Empty(t, reports[1].Clients)
+
+	// Nothing is pending after the flush
+	require.NoError(t, svc.Flush(ctx))
+	repo.AssertExpectations(t)
+}
+
+func TestDeprecationService_NotifyUpcoming(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo := new(MockDeprecationRepository)
+	preferences := new(MockPreferencesRepository)
+	hooks := new(MockWebhookRepository)
+	webhookSender := new(MockWebhookSender)
+	sender := &recordingSender{}
+	svc := NewDeprecationService(repo, testDeprecations, 30*24*time.Hour, preferences, sender, NewWebhookService(hooks, webhookSender)).(*deprecationService)
+	svc.now = func() time.Time { return now }
+
+	notified := now.Add(-24 * time.Hour)
+	usage := []*models.DeprecationUsage{
+		{DeprecationID: "tasks-per-page", ClientKind: models.DeprecationClientAPIKey, Client: "key-1", UserID: "user-1", Requests: 7},
+		{DeprecationID: "tasks-per-page", ClientKind: models.DeprecationClientUserAgent, Client: "sync/1.0", UserID: "user-1", Requests: 2},
+		// Claimed by another replica in the meantime
+		{DeprecationID: "tasks-per-page", ClientKind: models.DeprecationClientUserAgent, Client: "sync/1.0", UserID: "user-2", Requests: 1},
+		// Already notified, or nobody to notify
+		{DeprecationID: "tasks-per-page", ClientKind: models.DeprecationClientAPIKey, Client: "key-3", UserID: "user-3", NotifiedAt: &notified},
+		{DeprecationID: "tasks-per-page", ClientKind: models.DeprecationClientUserAgent, Client: "curl/8.0"},
+		// The v1 sunset is too far away
+		{DeprecationID: "v1", ClientKind: models.DeprecationClientAPIKey, Client: "key-1", UserID: "user-1"},
+	}
+	repo.On("ListUsage", ctx).Return(usage, nil).Once()
+	repo.On("MarkNotified", ctx, usage[0], now).Return(true, nil).Once()
+	repo.On("MarkNotified", ctx, usage[1], now).Return(true, nil).Once()
+	repo.On("MarkNotified", ctx, usage[2], now).Return(false, nil).Once()
+	preferences.On("Get", ctx, "user-1").Return(&models.NotificationPreferences{UserID: "user-1", Email: "dev@example.com"}, nil).Once()
+
+	hook := &models.Webhook{ID: "hook-1", CreatedBy: "user-1"}
+	hooks.On("ListForEvent", ctx, models.DeprecationNoticeEvent).
+		Return([]*models.Webhook{hook, {ID: "hook-2", CreatedBy: "someone-else"}}, nil).Once()
+	webhookSender.On("Send", ctx, hook, mock.MatchedBy(func(event *models.WebhookEvent) bool {
+		data, ok := event.Data.(*models.WebhookDeprecationData)
+		return ok && event.Type == models.DeprecationNoticeEvent && data.Deprecation.ID == "tasks-per-page" && len(data.Clients) == 2
+	})).Return(&models.WebhookDelivery{WebhookID: "hook-1", Success: true}, nil).Once()
+	hooks.On("RecordDelivery", ctx, mock.Anything).Return(nil).Once()
+
+	require.NoError(t, svc.NotifyUpcoming(ctx))
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "dev@example.com", sender.sent[0].To)
+	assert.Contains(t, sender.sent[0].Text, "API key key-1")
+	assert.Contains(t, sender.sent[0].Text, "sync/1.0")
+	repo.AssertExpectations(t)
+	preferences.AssertExpectations(t)
+	hooks.AssertExpectations(t)
+	webhookSender.AssertExpectations(t)
+
+	// Past the sunset there is nothing left to warn about
+	now = time.Date(2024, 3, 21, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, svc.NotifyUpcoming(ctx))
+	repo.AssertExpectations(t)
+}
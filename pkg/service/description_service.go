@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/textdiff"
+)
+
+// DescriptionService exposes the version history of task descriptions
+type DescriptionService interface {
+	// ListVersions returns a task's description versions, newest first, each with its
+	// diff from the version before
+	ListVersions(ctx context.Context, taskID string) ([]*models.DescriptionVersion, error)
+	// RestoreVersion makes an earlier version the current description. The restore is
+	// an edit like any other and is saved as a new version.
+	RestoreVersion(ctx context.Context, taskID string, version int, editorID string) (*models.Task, error)
+}
+
+// ErrCurrentVersion is returned when restoring the description a task already has
+var ErrCurrentVersion = errors.New("version is already the current description")
+
+type descriptionService struct {
+	versions repository.DescriptionRepository
+	tasks    repository.TaskRepository
+	updater  TaskService
+}
+
+// NewDescriptionService creates a new description history service. Restores go
+// through updater so they are checked and published like other task updates.
+func NewDescriptionService(versions repository.DescriptionRepository, tasks repository.TaskRepository, updater TaskService) DescriptionService {
+	return &descriptionService{versions: versions, tasks: tasks, updater: updater}
+}
+
+func (s *descriptionService) ListVersions(ctx context.Context, taskID string) ([]*models.DescriptionVersion, error) {
+	if _, err := s.getTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	versions, err := s.versions.ListVersions(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The oldest kept version is diffed against an empty description
+	previous := ""
+	for _, version := range versions {
+		version.Diff = textdiff.Lines(previous, version.Description)
+		previous = version.Description
+	}
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	return versions, nil
+}
+
+func (s *descriptionService) RestoreVersion(ctx context.Context, taskID string, version int, editorID string) (*models.Task, error) {
+	if version < 1 {
+		return nil, errors.New("version must be a positive number")
+	}
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.versions.GetVersion(ctx, taskID, version)
+	if err != nil {
+		return nil, err
+	}
+	if restored.Description == task.Description {
+		return nil, ErrCurrentVersion
+	}
+
+	return s.updater.UpdateTask(ctx, taskID, &models.TaskUpdate{
+		Description: &restored.Description,
+		EditorID:    editorID,
+	})
+}
+
+// getTask returns the task if the caller can see it
+func (s *descriptionService) getTask(ctx context.Context, taskID string) (*models.Task, error) {
+	task, err := s.tasks.GetByID(ctx, taskID)
+	return visibleTask(ctx, task, err)
+}
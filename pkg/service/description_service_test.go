@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/textdiff"
+)
+
+// MockDescriptionRepository is a mock implementation of DescriptionRepository
+type MockDescriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockDescriptionRepository) ListVersions(ctx context.Context, taskID string) ([]*models.DescriptionVersion, error) {
+	args := m.Called(ctx, taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DescriptionVersion), args.Error(1)
+}
+
+func (m *MockDescriptionRepository) GetVersion(ctx context.Context, taskID string, version int) (*models.DescriptionVersion, error) {
+	args := m.Called(ctx, taskID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DescriptionVersion), args.Error(1)
+}
+
+func TestListDescriptionVersions(t *testing.T) {
+	ctx := context.Background()
+	versions := new(MockDescriptionRepository)
+	tasks := new(MockTaskRepository)
+	svc := NewDescriptionService(versions, tasks, NewTaskService(tasks))
+
+	tasks.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1", Description: "a\nB"}, nil)
+	versions.On("ListVersions", ctx, "task-1").Return([]*models.DescriptionVersion{
+		{TaskID: "task-1", Version: 1, Description: "a\nb"},
+		{TaskID: "task-1", Version: 2, Description: "a\nB"},
+	}, nil)
+
+	result, err := svc.ListVersions(ctx, "task-1")
+
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, 2, result[0].Version)
+	assert.Equal(t, []textdiff.Line{
+		{Op: textdiff.Equal, Text: "a"},
+		{Op: textdiff.Delete, Text: "b"},
+		{Op: textdiff.Insert, Text: "B"},
+	}, result[0].Diff)
+	assert.Equal(t, 1, result[1].Version)
+	assert.Equal(t, []textdiff.Line{
+		{Op: textdiff.Insert, Text: "a"},
+		{Op: textdiff.Insert, Text: "b"},
+	}, result[1].Diff)
+}
+
+func TestListDescriptionVersions_HiddenDraft(t *testing.T) {
+	ctx := context.Background()
+	versions := new(MockDescriptionRepository)
+	tasks := new(MockTaskRepository)
+	svc := NewDescriptionService(versions, tasks, NewTaskService(tasks))
+
+	tasks.On("GetByID", ctx, "draft-1").
+		Return(&models.Task{ID: "draft-1", Status: models.StatusDraft, OwnerID: "alice"}, nil)
+
+	_, err := svc.ListVersions(ctx, "draft-1")
+
+	assert.Error(t, err)
+	versions.AssertNotCalled(t, "ListVersions", mock.Anything, mock.Anything)
+}
+
+func TestRestoreDescriptionVersion(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		version int
+		stored  *models.DescriptionVersion
+		lookup  error
+		wantErr error
+	}{
+		{
+			name:    "earlier version",
+			version: 1,
+			stored:  &models.DescriptionVersion{TaskID: "task-1", Version: 1, Description: "first draft"},
+		},
+		{
+			name:    "current version",
+			version: 2,
+			stored:  &models.DescriptionVersion{TaskID: "task-1", Version: 2, Description: "current"},
+			wantErr: ErrCurrentVersion,
+		},
+		{
+			name:    "unknown version",
+			version: 9,
+			lookup:  errors.New("description version not found"),
+			wantErr: errors.New("description version not found"),
+		},
+		{
+			name:    "invalid version",
+			version: 0,
+			wantErr: errors.New("version must be a positive number"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions := new(MockDescriptionRepository)
+			tasks := new(MockTaskRepository)
+			svc := NewDescriptionService(versions, tasks, NewTaskService(tasks))
+
+			tasks.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1", Description: "current"}, nil).Maybe()
+			if tt.stored != nil {
+				versions.On("GetVersion", ctx, "task-1", tt.version).Return(tt.stored, nil)
+			} else if tt.lookup != nil {
+				versions.On("GetVersion", ctx, "task-1", tt.version).Return(nil, tt.lookup)
+			}
+			tasks.On("Update", ctx, "task-1", mock.MatchedBy(func(update *models.TaskUpdate) bool {
+				return *update.Description == "first draft" && update.EditorID == "alice"
+			})).Return(&models.Task{ID: "task-1", Description: "first draft"}, nil).Maybe()
+
+			task, err := svc.RestoreVersion(ctx, "task-1", tt.version, "alice")
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+				tasks.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "first draft", task.Description)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/notify"
+	"sample/task-management-system/pkg/repository"
+)
+
+// DigestService emails users a summary of their deadlines
+type DigestService interface {
+	// SendDigests sends the digests that are due, each at the user's chosen hour in
+	// their time zone: daily ones every day and weekly ones on Mondays
+	SendDigests(ctx context.Context) error
+	// Run sends due digests every interval until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type digestService struct {
+	preferences repository.PreferencesRepository
+	tasks       repository.DigestRepository
+	sender      notify.Sender
+	signer      *notify.UnsubscribeSigner
+	baseURL     string
+	now         func() time.Time
+}
+
+// NewDigestService creates a digest service; unsubscribe links point at baseURL
+func NewDigestService(preferences repository.PreferencesRepository, tasks repository.DigestRepository, sender notify.Sender, signer *notify.UnsubscribeSigner, baseURL string) DigestService {
+	return &digestService{
+		preferences: preferences,
+		tasks:       tasks,
+		sender:      sender,
+		signer:      signer,
+		baseURL:     baseURL,
+		now:         time.Now,
+	}
+}
+
+func (s *digestService) SendDigests(ctx context.Context) error {
+	recipients, err := s.preferences.ListDigestRecipients(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	for _, recipient := range recipients {
+		// One user's failure doesn't hold up everyone else's digest
+		if err := s.send(ctx, recipient, now); err != nil {
+			log.Printf("Failed to send digest to user %s: %v", recipient.UserID, err)
+		}
+	}
+	return nil
+}
+
+// send sends the user's digest if it is due
+func (s *digestService) send(ctx context.Context, preferences *models.NotificationPreferences, now time.Time) error {
+	scheduled, ok := digestScheduledAt(preferences, now)
+	if !ok {
+		return nil
+	}
+
+	period := 24 * time.Hour
+	if preferences.Digest == models.DigestWeekly {
+		period = 7 * 24 * time.Hour
+	}
+	assignedSince := now.Add(-period)
+	if preferences.LastDigestAt != nil && preferences.LastDigestAt.After(assignedSince) {
+		assignedSince = *preferences.LastDigestAt
+	}
+
+	// Claimed before sending so that only one instance sends it; a failed send is not
+	// retried, the next digest covers the same tasks
+	claimed, err := s.preferences.ClaimDigest(ctx, preferences.UserID, scheduled, now)
+	if err != nil || !claimed {
+		return err
+	}
+
+	digest, err := s.tasks.DigestTasks(ctx, preferences.UserID, now, now.Add(period), assignedSince)
+	if err != nil {
+		return err
+	}
+	if digest.IsEmpty() {
+		return nil
+	}
+
+	token, err := s.signer.Sign(preferences.UserID)
+	if err != nil {
+		return err
+	}
+	digest.Frequency = preferences.Digest
+	digest.Location = preferences.Location()
+	digest.Locale = preferences.Locale
+	digest.UnsubscribeURL = s.baseURL + "/api/v1/users/preferences/unsubscribe?token=" + url.QueryEscape(token)
+
+	msg, err := notify.RenderDigest(preferences.Email, digest)
+	if err != nil {
+		return err
+	}
+	return s.sender.Send(ctx, msg)
+}
+
+// digestScheduledAt returns when today's digest was due in the user's time zone, and
+// false if none is due yet today
+func digestScheduledAt(preferences *models.NotificationPreferences, now time.Time) (time.Time, bool) {
+	local := now.In(preferences.Location())
+	if preferences.Digest == models.DigestWeekly && local.Weekday() != time.Monday {
+		return time.Time{}, false
+	}
+
+	scheduled := time.Date(local.Year(), local.Month(), local.Day(), preferences.DigestHour, 0, 0, 0, local.Location())
+	if local.Before(scheduled) {
+		return time.Time{}, false
+	}
+	return scheduled, true
+}
+
+func (s *digestService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SendDigests(ctx); err != nil {
+				log.Printf("Failed to send deadline digests: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/notify"
+)
+
+// MockPreferencesRepository is a mock implementation of PreferencesRepository
+type MockPreferencesRepository struct {
+	mock.Mock
+}
+
+func (m *MockPreferencesRepository) Get(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockPreferencesRepository) Save(ctx context.Context, preferences *models.NotificationPreferences) (*models.NotificationPreferences, error) {
+	args := m.Called(ctx, preferences)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockPreferencesRepository) ListDigestRecipients(ctx context.Context) ([]*models.NotificationPreferences, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockPreferencesRepository) ClaimDigest(ctx context.Context, userID string, since, at time.Time) (bool, error) {
+	args := m.Called(ctx, userID, since, at)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockDigestRepository is a mock implementation of DigestRepository
+type MockDigestRepository struct {
+	mock.Mock
+}
+
+func (m *MockDigestRepository) DigestTasks(ctx context.Context, userID string, now, dueBefore, assignedSince time.Time) (*models.Digest, error) {
+	args := m.Called(ctx, userID, now, dueBefore, assignedSince)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Digest), args.Error(1)
+}
+
+// recordingSender collects the messages it is asked to send
+type recordingSender struct {
+	sent []*notify.Message
+}
+
+func (s *recordingSender) Send(ctx context.Context, msg *notify.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestDigestScheduledAt(t *testing.T) {
+	// Monday 2024-03-04 06:30 UTC is 07:30 in Berlin and 01:30 in New York
+	now := time.Date(2024, 3, 4, 6, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		digest    models.DigestFrequency
+		timezone  string
+		hour      int
+		now       time.Time
+		wantDue   bool
+		wantLocal string
+	}{
+		{name: "daily after the hour", digest: models.DigestDaily, timezone: "Europe/Berlin", hour: 7, now: now, wantDue: true, wantLocal: "2024-03-04 07:00 CET"},
+		{name: "daily before the hour", digest: models.DigestDaily, timezone: "America/New_York", hour: 7, now: now},
+		{name: "weekly on monday", digest: models.DigestWeekly, timezone: "UTC", hour: 6, now: now, wantDue: true, wantLocal: "2024-03-04 06:00 UTC"},
+		// Still Sunday in Los Angeles
+		{name: "weekly before monday locally", digest: models.DigestWeekly, timezone: "America/Los_Angeles", hour: 0, now: now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preferences := &models.NotificationPreferences{Digest: tt.digest, Timezone: tt.timezone, DigestHour: tt.hour}
+			scheduled, due := digestScheduledAt(preferences, tt.now)
+			assert.Equal(t, tt.wantDue, due)
+			if tt.wantDue {
+				assert.Equal(t, tt.wantLocal, scheduled.Format("2006-01-02 15:04 MST"))
+			}
+		})
+	}
+}
+
+func TestSendDigests(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	lastSent := now.Add(-24 * time.Hour)
+	signer := notify.NewUnsubscribeSigner([]byte("test-secret"), "test-issuer")
+
+	prefsRepo := new(MockPreferencesRepository)
+	digestRepo := new(MockDigestRepository)
+	sender := &recordingSender{}
+	svc := NewDigestService(prefsRepo, digestRepo, sender, signer, "https://tasks.example.com").(*digestService)
+	svc.now = func() time.Time { return now }
+
+	prefsRepo.On("ListDigestRecipients", ctx).Return([]*models.NotificationPreferences{
+		{UserID: "user-1", Email: "one@example.com", Digest: models.DigestDaily, Timezone: "UTC", DigestHour: 8, LastDigestAt: &lastSent},
+		{UserID: "user-2", Email: "two@example.com", Digest: models.DigestDaily, Timezone: "UTC", DigestHour: 8},
+		{UserID: "user-3", Email: "three@example.com", Digest: models.DigestDaily, Timezone: "UTC", DigestHour: 10},
+	}, nil)
+	scheduled := time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC)
+	prefsRepo.On("ClaimDigest", ctx, "user-1", scheduled, now).Return(true, nil)
+	// Another instance already sent user-2's digest
+	prefsRepo.On("ClaimDigest", ctx, "user-2", scheduled, now).Return(false, nil)
+	digestRepo.On("DigestTasks", ctx, "user-1", now, now.Add(24*time.Hour), lastSent).Return(&models.Digest{
+		Overdue: []*models.Task{{Key: "PROJ-1", Title: "Renew certificate", DueDate: now.Add(-time.Hour)}},
+	}, nil)
+
+	require.NoError(t, svc.SendDigests(ctx))
+
+	require.Len(t, sender.sent, 1)
+	msg := sender.sent[0]
+	assert.Equal(t, "one@example.com", msg.To)
+	assert.Equal(t, "Your daily deadlines digest: 1 overdue", msg.Subject)
+	assert.Contains(t, msg.Text, "PROJ-1 Renew certificate")
+	assert.Contains(t, msg.HTML, "Renew certificate")
+	assert.Contains(t, msg.Headers["List-Unsubscribe"], "https://tasks.example.com/api/v1/users/preferences/unsubscribe?token=")
+	digestRepo.AssertNotCalled(t, "DigestTasks", ctx, "user-2", mock.Anything, mock.Anything, mock.Anything)
+	prefsRepo.AssertNotCalled(t, "ClaimDigest", ctx, "user-3", mock.Anything, mock.Anything)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+	signer := notify.NewUnsubscribeSigner([]byte("test-secret"), "test-issuer")
+	repo := new(MockPreferencesRepository)
+	svc := NewPreferencesService(repo, signer)
+
+	repo.On("Get", ctx, "user-1").Return(&models.NotificationPreferences{
+		UserID: "user-1", Email: "one@example.com", Digest: models.DigestWeekly, Timezone: "UTC",
+	}, nil)
+	repo.On("Save", ctx, mock.MatchedBy(func(p *models.NotificationPreferences) bool {
+		return p.UserID == "user-1" && p.Digest == models.DigestOff
+	})).Return(&models.NotificationPreferences{UserID: "user-1", Digest: models.DigestOff}, nil)
+
+	token, err := signer.Sign("user-1")
+	require.NoError(t, err)
+	preferences, err := svc.Unsubscribe(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, models.DigestOff, preferences.Digest)
+
+	_, err = svc.Unsubscribe(ctx, token+"x")
+	assert.ErrorIs(t, err, notify.ErrInvalidUnsubscribeToken)
+}
+
+func TestPreferencesUpdate_Apply(t *testing.T) {
+	email := "someone@example.com"
+	invalidEmail := "not an email"
+	daily := models.DigestDaily
+	zone := "Asia/Tokyo"
+	unknownZone := "Mars/Olympus"
+	hour := 24
+
+	tests := []struct {
+		name    string
+		current models.NotificationPreferences
+		update  models.PreferencesUpdate
+		wantErr bool
+	}{
+		{name: "subscribe", current: *models.DefaultNotificationPreferences("u"), update: models.PreferencesUpdate{Email: &email, Digest: &daily, Timezone: &zone}},
+		{name: "digest without email", current: *models.DefaultNotificationPreferences("u"), update: models.PreferencesUpdate{Digest: &daily}, wantErr: true},
+		{name: "invalid email", current: *models.DefaultNotificationPreferences("u"), update: models.PreferencesUpdate{Email: &invalidEmail}, wantErr: true},
+		{name: "unknown timezone", current: *models.DefaultNotificationPreferences("u"), update: models.PreferencesUpdate{Timezone: &unknownZone}, wantErr: true},
+		{name: "hour out of range", current: *models.DefaultNotificationPreferences("u"), update: models.PreferencesUpdate{DigestHour: &hour}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preferences := tt.current
+			err := tt.update.Apply(&preferences)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, models.DigestDaily, preferences.Digest)
+			assert.Equal(t, "Asia/Tokyo", preferences.Location().String())
+		})
+	}
+}
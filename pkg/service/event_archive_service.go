@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// EventArchiveService keeps every task event for a retention period and replays time
+// ranges of them, e.g. to rebuild a downstream system after an outage
+type EventArchiveService interface {
+	// Record archives an event; it is subscribed to the event bus
+	Record(ctx context.Context, event events.Event) error
+	// Replay sends the archived events of a time range to a sink again, oldest first. It
+	// stops at the first failed delivery so the sink receives events in order.
+	Replay(ctx context.Context, request *models.ReplayRequest) (*models.ReplayResult, error)
+	// Run deletes events past their retention every interval until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type eventArchiveService struct {
+	repo      repository.EventArchiveRepository
+	webhooks  WebhookService
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewEventArchiveService creates an event archive keeping events for retention and
+// replaying them to webhooks
+func NewEventArchiveService(repo repository.EventArchiveRepository, webhooks WebhookService, retention time.Duration) EventArchiveService {
+	return &eventArchiveService{repo: repo, webhooks: webhooks, retention: retention, now: time.Now}
+}
+
+func (s *eventArchiveService) Record(ctx context.Context, event events.Event) error {
+	return s.repo.Append(ctx, &models.ArchivedEvent{
+		EventID:    event.ID,
+		Type:       string(event.Type),
+		TaskID:     event.TaskID,
+		Task:       event.Task,
+		OccurredAt: event.OccurredAt,
+	})
+}
+
+func (s *eventArchiveService) Replay(ctx context.Context, request *models.ReplayRequest) (*models.ReplayResult, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	hook, err := s.webhooks.GetWebhook(ctx, request.Sink.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Webhooks only get the types they subscribe to
+	types := hook.Events
+	if len(request.Types) > 0 {
+		types = nil
+		for _, eventType := range request.Types {
+			if containsString(hook.Events, eventType) {
+				types = append(types, eventType)
+			}
+		}
+		if len(types) == 0 {
+			return nil, errors.New("the webhook subscribes to none of the requested types")
+		}
+	}
+
+	archived, err := s.repo.List(ctx, request.From, request.To, types, request.After, models.MaxReplayEvents+1)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ReplayResult{NextAfter: request.After, Complete: len(archived) <= models.MaxReplayEvents}
+	if !result.Complete {
+		archived = archived[:models.MaxReplayEvents]
+	}
+	for _, event := range archived {
+		delivery := s.webhooks.ReplayEvent(ctx, hook, event)
+		if !delivery.Success {
+			result.Complete = false
+			result.Error = delivery.Error
+			break
+		}
+		result.Replayed++
+		result.NextAfter = event.ID
+	}
+	if result.Complete {
+		result.NextAfter = 0
+	}
+	return result, nil
+}
+
+func (s *eventArchiveService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.repo.DeleteBefore(ctx, s.now().Add(-s.retention))
+			if err != nil {
+				log.Printf("Failed to delete archived events: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Deleted %d archived events past their retention", deleted)
+			}
+		}
+	}
+}
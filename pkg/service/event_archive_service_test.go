@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+)
+
+// MockEventArchiveRepository is a mock implementation of EventArchiveRepository
+type MockEventArchiveRepository struct {
+	mock.Mock
+}
+
+func (m *MockEventArchiveRepository) Append(ctx context.Context, event *models.ArchivedEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockEventArchiveRepository) List(ctx context.Context, from, to time.Time, types []string, after int64, limit int) ([]*models.ArchivedEvent, error) {
+	args := m.Called(ctx, from, to, types, after, limit)
+	return args.Get(0).([]*models.ArchivedEvent), args.Error(1)
+}
+
+func (m *MockEventArchiveRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestRecordEvent(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockEventArchiveRepository)
+	svc := NewEventArchiveService(repo, NewWebhookService(new(MockWebhookRepository), new(MockWebhookSender)), time.Hour)
+
+	occurredAt := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo.On("Append", ctx, &models.ArchivedEvent{
+		EventID: "event-1", Type: "task.deleted", TaskID: "task-1", OccurredAt: occurredAt,
+	}).Return(nil).Once()
+
+	require.NoError(t, svc.Record(ctx, events.Event{ID: "event-1", Type: events.TaskDeleted, TaskID: "task-1", OccurredAt: occurredAt}))
+	repo.AssertExpectations(t)
+}
+
+func TestReplayEvents_StopsAtFailedDelivery(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockEventArchiveRepository)
+	hooks := new(MockWebhookRepository)
+	sender := new(MockWebhookSender)
+	svc := NewEventArchiveService(repo, NewWebhookService(hooks, sender), time.Hour)
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	hook := &models.Webhook{ID: "hook-1", Events: []string{"task.created", "task.updated"}}
+	hooks.On("GetByID", ctx, "hook-1").Return(hook, nil)
+	archived := []*models.ArchivedEvent{
+		{ID: 11, EventID: "event-11", Type: "task.created", TaskID: "task-1"},
+		{ID: 12, EventID: "event-12", Type: "task.created", TaskID: "task-2"},
+		{ID: 13, EventID: "event-13", Type: "task.created", TaskID: "task-3"},
+	}
+	// Types the webhook doesn't subscribe to are left out
+	repo.On("List", ctx, from, to, []string{"task.created"}, int64(10), models.MaxReplayEvents+1).Return(archived, nil).Once()
+	sender.On("Send", ctx, hook, mock.MatchedBy(func(event *models.WebhookEvent) bool { return event.ID == "event-11" })).
+		Return(&models.WebhookDelivery{Success: true, StatusCode: 200}, nil).Once()
+	sender.On("Send", ctx, hook, mock.MatchedBy(func(event *models.WebhookEvent) bool { return event.ID == "event-12" })).
+		Return(&models.WebhookDelivery{StatusCode: 503, Error: "webhook receiver returned status 503"}, assert.AnError).Once()
+	hooks.On("RecordDelivery", ctx, mock.MatchedBy(func(delivery *models.WebhookDelivery) bool { return delivery.Redelivery })).Return(nil).Twice()
+
+	result, err := svc.Replay(ctx, &models.ReplayRequest{
+		From: from, To: to, Types: []string{"task.created", "task.deleted"}, After: 10,
+		Sink: models.ReplaySink{Type: models.ReplaySinkWebhook, WebhookID: "hook-1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &models.ReplayResult{Replayed: 1, NextAfter: 11, Error: "webhook receiver returned status 503"}, result)
+	sender.AssertExpectations(t)
+
+	_, err = svc.Replay(ctx, &models.ReplayRequest{
+		From: from, To: to, Sink: models.ReplaySink{Type: models.ReplaySinkKafka, Topic: "tasks"},
+	})
+	assert.Error(t, err)
+	_, err = svc.Replay(ctx, &models.ReplayRequest{
+		From: from, To: to, Types: []string{"task.deleted"},
+		Sink: models.ReplaySink{Type: models.ReplaySinkWebhook, WebhookID: "hook-1"},
+	})
+	assert.Error(t, err)
+}
+
+func TestReplayEvents_Complete(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockEventArchiveRepository)
+	hooks := new(MockWebhookRepository)
+	sender := new(MockWebhookSender)
+	svc := NewEventArchiveService(repo, NewWebhookService(hooks, sender), time.Hour)
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	hook := &models.Webhook{ID: "hook-1", Events: []string{"task.deleted"}}
+	hooks.On("GetByID", ctx, "hook-1").Return(hook, nil).Once()
+	repo.On("List", ctx, from, from.Add(time.Hour), []string{"task.deleted"}, int64(0), models.MaxReplayEvents+1).
+		Return([]*models.ArchivedEvent{{ID: 1, EventID: "event-1", Type: "task.deleted", TaskID: "task-1"}}, nil).Once()
+	sender.On("Send", ctx, hook, mock.Anything).Return(&models.WebhookDelivery{Success: true}, nil).Once()
+	hooks.On("RecordDelivery", ctx, mock.Anything).Return(nil).Once()
+
+	result, err := svc.Replay(ctx, &models.ReplayRequest{
+		From: from, To: from.Add(time.Hour), Sink: models.ReplaySink{Type: models.ReplaySinkWebhook, WebhookID: "hook-1"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &models.ReplayResult{Replayed: 1, Complete: true}, result)
+}
@@ -0,0 +1,464 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/storage"
+	"sample/task-management-system/pkg/tenant"
+)
+
+const (
+	// exportPageSize is the number of tasks read at a time while building an export
+	exportPageSize = 500
+	// exportLease is how long a worker may take before its job is handed to another
+	exportLease = 15 * time.Minute
+	// exportCleanupInterval is how often expired exports are deleted
+	exportCleanupInterval = time.Hour
+)
+
+// ExportService queues exports and builds them in the background
+type ExportService interface {
+	// RequestExport queues an export for the user; report exports must only be
+	// requested for admins
+	RequestExport(ctx context.Context, requestedBy string, input *models.ExportRequest) (*models.ExportJob, error)
+	// GetExport returns one of the user's exports, with its download URL once completed
+	GetExport(ctx context.Context, id, requestedBy string) (*models.ExportJob, error)
+	// Download returns a completed export of the user with its content, or without
+	// content but with a presigned DownloadURL when the file is kept in S3
+	Download(ctx context.Context, id, requestedBy string) (*models.ExportJob, []byte, error)
+	// ProcessNext builds the oldest queued export, reporting whether there was one
+	ProcessNext(ctx context.Context) (bool, error)
+	// Run builds queued exports, polling for them every interval unless they come from
+	// a queue, and deletes expired ones until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type exportService struct {
+	repo        repository.ExportRepository
+	tasks       repository.TaskRepository
+	reports     ReportService
+	preferences repository.PreferencesRepository
+	store       storage.ObjectStore // nil keeps files in the database
+	queue       JobQueue            // nil polls the database for queued exports
+	retry       models.RetryPolicy
+	now         func() time.Time
+}
+
+// NewExportService creates an export service. Files are uploaded to store and downloaded
+// through presigned URLs, or kept in the database and served by the API when store is nil.
+// Queued exports are handed to workers through queue, or found by polling when it is nil.
+// Failed exports are retried, then dead-lettered, as retry says.
+func NewExportService(repo repository.ExportRepository, tasks repository.TaskRepository, reports ReportService, preferences repository.PreferencesRepository, store storage.ObjectStore, queue JobQueue, retry models.RetryPolicy) ExportService {
+	return &exportService{
+		repo:        repo,
+		tasks:       tasks,
+		reports:     reports,
+		preferences: preferences,
+		store:       store,
+		queue:       queue,
+		retry:       retry,
+		now:         time.Now,
+	}
+}
+
+func (s *exportService) RequestExport(ctx context.Context, requestedBy string, input *models.ExportRequest) (*models.ExportJob, error) {
+	if requestedBy == "" {
+		return nil, errors.New("exports need a user")
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	if input.Kind == models.ExportReport {
+		// Fail now rather than in the background for reports that don't exist
+		if _, err := s.reports.GetReport(ctx, input.ReportID); err != nil {
+			return nil, err
+		}
+	}
+
+	job, err := s.repo.Create(ctx, &models.ExportJob{Request: *input, RequestedBy: requestedBy})
+	if err != nil || s.queue == nil {
+		return job, err
+	}
+	if err := s.queue.Publish(ctx, job.ID); err != nil {
+		// No worker would ever pick the export up
+		if err := s.repo.Fail(ctx, job.ID, "export could not be queued", s.now()); err != nil {
+			log.Printf("Failed to fail unqueued export %s: %v", job.ID, err)
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *exportService) GetExport(ctx context.Context, id, requestedBy string) (*models.ExportJob, error) {
+	job, err := s.ownExport(ctx, id, requestedBy)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.ExportCompleted {
+		return job, nil
+	}
+
+	if job.StorageKey != "" && s.store != nil {
+		url, err := s.store.PresignGet(ctx, job.StorageKey, job.FileName, DownloadURLExpiry)
+		if err != nil {
+			return nil, err
+		}
+		job.DownloadURL = url
+	} else {
+		job.DownloadURL = "/api/v1/exports/" + job.ID + "/download"
+	}
+	return job, nil
+}
+
+func (s *exportService) Download(ctx context.Context, id, requestedBy string) (*models.ExportJob, []byte, error) {
+	job, err := s.GetExport(ctx, id, requestedBy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != models.ExportCompleted {
+		return nil, nil, fmt.Errorf("export is %s", job.Status)
+	}
+	if job.StorageKey != "" {
+		return job, nil, nil
+	}
+
+	content, err := s.repo.Content(ctx, job.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, content, nil
+}
+
+func (s *exportService) ProcessNext(ctx context.Context) (bool, error) {
+	return s.processJob(ctx, "")
+}
+
+// processJob builds the queued export id, or the oldest one when id is empty, reporting
+// whether it could be claimed
+func (s *exportService) processJob(ctx context.Context, id string) (bool, error) {
+	job, err := s.repo.Claim(ctx, id, s.now(), exportLease, s.retry.MaxAttempts)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	// The job sees only the tasks of the tenant it was requested in
+	file, err := s.build(tenant.WithID(ctx, job.OrgID), job)
+	if err == nil {
+		err = s.deliver(ctx, job, file)
+	}
+	if err != nil {
+		if !retryable(err) {
+			log.Printf("Export %s failed: %v", job.ID, err)
+			return true, s.repo.Fail(ctx, job.ID, err.Error(), s.now())
+		}
+		if s.retry.Retries(job.Attempts) {
+			retryAt := s.now().Add(s.retry.Backoff(job.Attempts))
+			log.Printf("Export %s failed on attempt %d, retrying at %s: %v", job.ID, job.Attempts, retryAt.Format(time.RFC3339), err)
+			return true, s.repo.Retry(ctx, job.ID, err.Error(), retryAt)
+		}
+		log.Printf("Export %s failed on attempt %d, dead-lettering it: %v", job.ID, job.Attempts, err)
+		return true, s.repo.DeadLetter(ctx, job.ID, err.Error(), s.now())
+	}
+
+	now := s.now()
+	return true, s.repo.Complete(ctx, job.ID, file, now, now.Add(models.ExportRetention))
+}
+
+func (s *exportService) Run(ctx context.Context, interval time.Duration) {
+	if s.queue != nil {
+		go s.queue.Consume(ctx, s.consume)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Exports from the queue are built as they are handed over; retries are
+			// queued again once due
+			if s.queue == nil {
+				s.processAll(ctx)
+			} else {
+				queueRetries(ctx, s.queue, s.repo.ReleaseRetries, s.now())
+			}
+
+			if s.now().Sub(lastCleanup) >= exportCleanupInterval {
+				lastCleanup = s.now()
+				s.deleteExpired(ctx)
+			}
+		}
+	}
+}
+
+// processAll builds queued exports until there are none left
+func (s *exportService) processAll(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := s.ProcessNext(ctx)
+		if err != nil {
+			log.Printf("Failed to process export: %v", err)
+		}
+		if !processed {
+			return
+		}
+	}
+}
+
+// consume builds an export handed over by the queue. Exports another worker is still
+// building are handed back, so they are retried should that worker stop.
+func (s *exportService) consume(ctx context.Context, id string) error {
+	processed, err := s.processJob(ctx, id)
+	if err != nil || processed {
+		return err
+	}
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Status == models.ExportRunning {
+		return errJobBusy
+	}
+	return nil
+}
+
+// ownExport returns the export if it was requested by the user; others' exports are
+// reported as missing
+func (s *exportService) ownExport(ctx context.Context, id, requestedBy string) (*models.ExportJob, error) {
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.RequestedBy != requestedBy {
+		return nil, errors.New("export not found")
+	}
+	return job, nil
+}
+
+// build produces the file of a job
+func (s *exportService) build(ctx context.Context, job *models.ExportJob) (*models.ExportFile, error) {
+	request := job.Request
+	var content []byte
+	var err error
+	switch request.Kind {
+	case models.ExportTasks:
+		content, err = s.buildTasks(ctx, job)
+	case models.ExportReport:
+		content, err = s.buildReport(ctx, job)
+	case models.ExportAccount:
+		content, err = s.buildAccount(ctx, job)
+	default:
+		err = jobRejected{fmt.Errorf("unknown export kind %q", request.Kind)}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if request.Format == models.ExportJSON {
+		contentType = "application/json"
+	}
+	return &models.ExportFile{
+		FileName:    fmt.Sprintf("%s-%s.%s", request.Kind, s.now().UTC().Format("20060102"), request.Format),
+		ContentType: contentType,
+		Size:        int64(len(content)),
+		Content:     content,
+	}, nil
+}
+
+// deliver uploads the file to the object store, when there is one
+func (s *exportService) deliver(ctx context.Context, job *models.ExportJob, file *models.ExportFile) error {
+	if s.store == nil {
+		return nil
+	}
+
+	key := "exports/" + job.ID + "/" + file.FileName
+	if err := s.store.Put(ctx, key, bytes.NewReader(file.Content), file.Size, file.ContentType); err != nil {
+		return fmt.Errorf("failed to store export: %v", err)
+	}
+	file.StorageKey = key
+	file.Content = nil
+	return nil
+}
+
+func (s *exportService) buildTasks(ctx context.Context, job *models.ExportJob) ([]byte, error) {
+	filter := repository.TaskFilter{Viewer: job.RequestedBy}
+	if f := job.Request.Filter; f != nil {
+		filter.Status = f.Status
+		filter.Priority = f.Priority
+		filter.AssigneeID = f.AssigneeID
+		filter.Tags = f.Tags
+	}
+
+	tasks, err := s.collectTasks(ctx, job, filter, 0, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Request.Format == models.ExportJSON {
+		return json.Marshal(map[string]interface{}{"tasks": tasks})
+	}
+	return tasksCSV(tasks)
+}
+
+func (s *exportService) buildReport(ctx context.Context, job *models.ExportJob) ([]byte, error) {
+	result, err := s.reports.RunReport(ctx, job.Request.ReportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Request.Format == models.ExportJSON {
+		return json.Marshal(result)
+	}
+	var buf bytes.Buffer
+	if err := WriteReportCSV(&buf, result); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// buildAccount gathers what is stored about the requester: the tasks they own, including
+// drafts, the tasks assigned to them and their notification preferences
+func (s *exportService) buildAccount(ctx context.Context, job *models.ExportJob) ([]byte, error) {
+	owned, err := s.collectTasks(ctx, job, repository.TaskFilter{OwnerID: job.RequestedBy, Viewer: job.RequestedBy}, 0, 50)
+	if err != nil {
+		return nil, err
+	}
+	assigned, err := s.collectTasks(ctx, job, repository.TaskFilter{AssigneeID: job.RequestedBy, Viewer: job.RequestedBy}, 50, 100)
+	if err != nil {
+		return nil, err
+	}
+	preferences, err := s.preferences.Get(ctx, job.RequestedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"user_id":        job.RequestedBy,
+		"exported_at":    s.now(),
+		"preferences":    preferences,
+		"owned_tasks":    owned,
+		"assigned_tasks": assigned,
+	})
+}
+
+// collectTasks reads every task matching filter a page at a time, moving the job's
+// progress from progressFrom towards progressTo as pages come in
+func (s *exportService) collectTasks(ctx context.Context, job *models.ExportJob, filter repository.TaskFilter, progressFrom, progressTo int) ([]*models.Task, error) {
+	filter.Sort = repository.SortCreatedAt
+	filter.Order = "asc"
+	filter.Limit = exportPageSize
+
+	tasks := []*models.Task{}
+	for page := 1; ; page++ {
+		filter.Page = page
+		batch, total, err := s.tasks.List(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if total > models.MaxExportTasks {
+			return nil, jobRejected{fmt.Errorf("export matches %d tasks; narrow it down to at most %d", total, models.MaxExportTasks)}
+		}
+		tasks = append(tasks, batch...)
+		if len(batch) < exportPageSize || len(tasks) >= total {
+			return tasks, nil
+		}
+
+		progress := progressFrom + (progressTo-progressFrom)*len(tasks)/total
+		if err := s.repo.SetProgress(ctx, job.ID, progress); err != nil {
+			log.Printf("Failed to record progress of export %s: %v", job.ID, err)
+		}
+	}
+}
+
+// deleteExpired removes expired exports along with their files in S3
+func (s *exportService) deleteExpired(ctx context.Context) {
+	keys, err := s.repo.DeleteExpired(ctx, s.now())
+	if err != nil {
+		log.Printf("Failed to delete expired exports: %v", err)
+		return
+	}
+	for _, key := range keys {
+		if s.store == nil {
+			break
+		}
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete export file %s: %v", key, err)
+		}
+	}
+}
+
+// tasksCSV writes tasks as CSV, one row per task
+func tasksCSV(tasks []*models.Task) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := newTaskCSVWriter(&buf)
+	for _, task := range tasks {
+		writer.Write(task)
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// taskCSVWriter writes tasks as CSV rows below a header row
+type taskCSVWriter struct {
+	writer *csv.Writer
+}
+
+func newTaskCSVWriter(w io.Writer) *taskCSVWriter {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{
+		"key", "title", "description", "status", "priority", "project_key", "due_date",
+		"owner_id", "assignee_id", "tags", "estimated_minutes", "logged_minutes", "created_at", "updated_at",
+	})
+	return &taskCSVWriter{writer: writer}
+}
+
+// Write writes a task's row
+func (w *taskCSVWriter) Write(task *models.Task) error {
+	dueDate := ""
+	if !task.DueDate.IsZero() {
+		dueDate = task.DueDate.UTC().Format(time.RFC3339)
+	}
+	estimate := ""
+	if task.EstimatedMinutes != nil {
+		estimate = strconv.Itoa(*task.EstimatedMinutes)
+	}
+	return w.writer.Write([]string{
+		task.Key,
+		csvSafe(task.Title),
+		csvSafe(task.Description),
+		string(task.Status),
+		string(task.Priority),
+		task.ProjectKey,
+		dueDate,
+		task.OwnerID,
+		task.AssigneeID,
+		csvSafe(strings.Join(task.Tags, ";")),
+		estimate,
+		strconv.Itoa(task.LoggedMinutes),
+		task.CreatedAt.UTC().Format(time.RFC3339),
+		task.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// Flush writes buffered rows
+func (w *taskCSVWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// MockExportRepository is a mock implementation of ExportRepository
+type MockExportRepository struct {
+	mock.Mock
+}
+
+func (m *MockExportRepository) Create(ctx context.Context, job *models.ExportJob) (*models.ExportJob, error) {
+	args := m.Called(ctx, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ExportJob), args.Error(1)
+}
+
+func (m *MockExportRepository) GetByID(ctx context.Context, id string) (*models.ExportJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ExportJob), args.Error(1)
+}
+
+func (m *MockExportRepository) CountPending(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockExportRepository) Claim(ctx context.Context, id string, now time.Time, lease time.Duration, maxAttempts int) (*models.ExportJob, error) {
+	args := m.Called(ctx, id, now, lease, maxAttempts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ExportJob), args.Error(1)
+}
+
+func (m *MockExportRepository) SetProgress(ctx context.Context, id string, progress int) error {
+	args := m.Called(ctx, id, progress)
+	return args.Error(0)
+}
+
+func (m *MockExportRepository) Complete(ctx context.Context, id string, file *models.ExportFile, completedAt, expiresAt time.Time) error {
+	args := m.Called(ctx, id, file, completedAt, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockExportRepository) Fail(ctx context.Context, id, message string, at time.Time) error {
+	args := m.Called(ctx, id, message, at)
+	return args.Error(0)
+}
+
+func (m *MockExportRepository) Retry(ctx context.Context, id, message string, runAfter time.Time) error {
+	args := m.Called(ctx, id, message, runAfter)
+	return args.Error(0)
+}
+
+func (m *MockExportRepository) DeadLetter(ctx context.Context, id, message string, now time.Time) error {
+	args := m.Called(ctx, id, message, now)
+	return args.Error(0)
+}
+
+func (m *MockExportRepository) ReleaseRetries(ctx context.Context, now time.Time) ([]string, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockExportRepository) Content(ctx context.Context, id string) ([]byte, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockExportRepository) DeleteExpired(ctx context.Context, now time.Time) ([]string, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func newTestExportService(repo *MockExportRepository, tasks *MockTaskRepository, store *MockObjectStore, now time.Time) *exportService {
+	svc := NewExportService(repo, tasks, NewReportService(new(MockReportRepository), time.Second), new(MockPreferencesRepository), nil, nil, models.DefaultRetryPolicies[models.JobExport]).(*exportService)
+	if store != nil {
+		svc.store = store
+	}
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+// MockJobQueue is a mock implementation of JobQueue
+type MockJobQueue struct {
+	mock.Mock
+}
+
+func (m *MockJobQueue) Publish(ctx context.Context, jobID string) error {
+	args := m.Called(ctx, jobID)
+	return args.Error(0)
+}
+
+func (m *MockJobQueue) Consume(ctx context.Context, handle func(ctx context.Context, jobID string) error) {
+	m.Called(ctx, handle)
+}
+
+func exportTasks(n int) []*models.Task {
+	tasks := make([]*models.Task, n)
+	for i := range tasks {
+		tasks[i] = &models.Task{Key: fmt.Sprintf("TASK-%d", i+1), Title: "Task", Status: models.StatusPending}
+	}
+	return tasks
+}
+
+func TestProcessNext_TasksToObjectStore(t *testing.T) {
+	ctx := context.Background()
+	// Jobs run scoped to the tenant they were requested in
+	jobCtx := tenant.WithID(ctx, "org-1")
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockExportRepository)
+	tasks := new(MockTaskRepository)
+	store := new(MockObjectStore)
+	svc := newTestExportService(repo, tasks, store, now)
+
+	job := &models.ExportJob{ID: "export-1", RequestedBy: "user-1", OrgID: "org-1", Request: models.ExportRequest{
+		Kind: models.ExportTasks, Format: models.ExportCSV,
+		Filter: &models.ExportTaskFilter{Status: []models.TaskStatus{models.StatusPending}},
+	}}
+	repo.On("Claim", ctx, "", now, exportLease, models.MaxExportAttempts).Return(job, nil).Once()
+
+	listed := exportTasks(2)
+	listed[1].Title = "=HYPERLINK(\"http://evil\")"
+	tasks.On("List", jobCtx, mock.MatchedBy(func(filter repository.TaskFilter) bool {
+		return filter.Viewer == "user-1" && filter.Page == 1 && filter.Limit == exportPageSize &&
+			len(filter.Status) == 1 && filter.Status[0] == models.StatusPending
+	})).Return(listed, 2, nil).Once()
+
+	var uploaded []byte
+	store.On("Put", ctx, "exports/export-1/tasks-20240502.csv", mock.Anything, mock.Anything, "text/csv; charset=utf-8").
+		Run(func(args mock.Arguments) {
+			var buf bytes.Buffer
+			buf.ReadFrom(args.Get(2).(*bytes.Reader))
+			uploaded = buf.Bytes()
+		}).Return(nil).Once()
+	repo.On("Complete", ctx, "export-1", mock.MatchedBy(func(file *models.ExportFile) bool {
+		return file.StorageKey == "exports/export-1/tasks-20240502.csv" && file.Content == nil && file.Size > 0
+	}), now, now.Add(models.ExportRetention)).Return(nil).Once()
+
+	processed, err := svc.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	records, err := csv.NewReader(bytes.NewReader(uploaded)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "key", records[0][0])
+	assert.Equal(t, "TASK-1", records[1][0])
+	// Formulas are neutralized
+	assert.True(t, strings.HasPrefix(records[2][1], "'="))
+	repo.AssertExpectations(t)
+	tasks.AssertExpectations(t)
+	store.AssertExpectations(t)
+}
+
+func TestProcessNext_PagesAndRecordsProgress(t *testing.T) {
+	ctx := context.Background()
+	jobCtx := tenant.WithID(ctx, "")
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockExportRepository)
+	tasks := new(MockTaskRepository)
+	svc := newTestExportService(repo, tasks, nil, now)
+
+	job := &models.ExportJob{ID: "export-1", RequestedBy: "user-1", Request: models.ExportRequest{
+		Kind: models.ExportTasks, Format: models.ExportJSON,
+	}}
+	repo.On("Claim", ctx, "", now, exportLease, models.MaxExportAttempts).Return(job, nil).Once()
+	tasks.On("List", jobCtx, mock.MatchedBy(func(filter repository.TaskFilter) bool { return filter.Page == 1 })).
+		Return(exportTasks(exportPageSize), 600, nil).Once()
+	tasks.On("List", jobCtx, mock.MatchedBy(func(filter repository.TaskFilter) bool { return filter.Page == 2 })).
+		Return(exportTasks(100), 600, nil).Once()
+	repo.On("SetProgress", jobCtx, "export-1", 83).Return(nil).Once()
+	repo.On("Complete", ctx, "export-1", mock.MatchedBy(func(file *models.ExportFile) bool {
+		return file.StorageKey == "" && file.ContentType == "application/json" &&
+			file.FileName == "tasks-20240502.json" && strings.Count(string(file.Content), `"key"`) == 600
+	}), now, now.Add(models.ExportRetention)).Return(nil).Once()
+
+	processed, err := svc.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.True(t, processed)
+	repo.AssertExpectations(t)
+	tasks.AssertExpectations(t)
+}
+
+func TestProcessNext_TooManyTasksFails(t *testing.T) {
+	ctx := context.Background()
+	jobCtx := tenant.WithID(ctx, "")
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockExportRepository)
+	tasks := new(MockTaskRepository)
+	svc := newTestExportService(repo, tasks, nil, now)
+
+	job := &models.ExportJob{ID: "export-1", RequestedBy: "user-1", Request: models.ExportRequest{
+		Kind: models.ExportTasks, Format: models.ExportCSV,
+	}}
+	repo.On("Claim", ctx, "", now, exportLease, models.MaxExportAttempts).Return(job, nil).Once()
+	tasks.On("List", jobCtx, mock.Anything).Return(exportTasks(exportPageSize), models.MaxExportTasks+1, nil).Once()
+	repo.On("Fail", ctx, "export-1", mock.MatchedBy(func(message string) bool {
+		return strings.Contains(message, "narrow it down")
+	}), now).Return(nil).Once()
+
+	processed, err := svc.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.True(t, processed)
+	repo.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestProcessNext_RetriesThenDeadLetters(t *testing.T) {
+	ctx := context.Background()
+	jobCtx := tenant.WithID(ctx, "")
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockExportRepository)
+	tasks := new(MockTaskRepository)
+	svc := newTestExportService(repo, tasks, nil, now)
+
+	request := models.ExportRequest{Kind: models.ExportTasks, Format: models.ExportCSV}
+	repo.On("Claim", ctx, "", now, exportLease, models.MaxExportAttempts).
+		Return(&models.ExportJob{ID: "export-1", RequestedBy: "user-1", Request: request, Attempts: 2}, nil).Once()
+	repo.On("Claim", ctx, "", now, exportLease, models.MaxExportAttempts).
+		Return(&models.ExportJob{ID: "export-1", RequestedBy: "user-1", Request: request, Attempts: 3}, nil).Once()
+	tasks.On("List", jobCtx, mock.Anything).Return([]*models.Task(nil), 0, errors.New("connection reset")).Twice()
+	// The second attempt waits twice as long as the first
+	repo.On("Retry", ctx, "export-1", "connection reset", now.Add(2*time.Minute)).Return(nil).Once()
+	repo.On("DeadLetter", ctx, "export-1", "connection reset", now).Return(nil).Once()
+
+	for i := 0; i < 2; i++ {
+		processed, err := svc.ProcessNext(ctx)
+		require.NoError(t, err)
+		assert.True(t, processed)
+	}
+	repo.AssertNotCalled(t, "Fail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertExpectations(t)
+}
+
+func TestRun_QueuesDueRetries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockExportRepository)
+	queue := new(MockJobQueue)
+	svc := newTestExportService(repo, new(MockTaskRepository), nil, now)
+	svc.queue = queue
+
+	queue.On("Consume", mock.Anything, mock.Anything).Return()
+	repo.On("ReleaseRetries", mock.Anything, now).Return([]string{"export-1"}, nil).Once()
+	repo.On("ReleaseRetries", mock.Anything, now).Return(nil, nil)
+	repo.On("DeleteExpired", mock.Anything, now).Return(nil, nil)
+	published := make(chan string, 1)
+	queue.On("Publish", mock.Anything, "export-1").Run(func(args mock.Arguments) {
+		published <- args.String(1)
+	}).Return(nil).Once()
+
+	go svc.Run(ctx, time.Millisecond)
+	select {
+	case id := <-published:
+		assert.Equal(t, "export-1", id)
+	case <-time.After(time.Second):
+		t.Fatal("the due retry was not queued")
+	}
+}
+
+func TestProcessNext_EmptyQueue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockExportRepository)
+	svc := newTestExportService(repo, new(MockTaskRepository), nil, now)
+
+	repo.On("Claim", ctx, "", now, exportLease, models.MaxExportAttempts).Return(nil, nil).Once()
+
+	processed, err := svc.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestGetExport(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockExportRepository)
+	store := new(MockObjectStore)
+	svc := newTestExportService(repo, new(MockTaskRepository), store, now)
+
+	repo.On("GetByID", ctx, "in-db").Return(&models.ExportJob{ID: "in-db", RequestedBy: "user-1", Status: models.ExportCompleted}, nil)
+	repo.On("GetByID", ctx, "in-s3").Return(&models.ExportJob{
+		ID: "in-s3", RequestedBy: "user-1", Status: models.ExportCompleted,
+		FileName: "tasks-20240502.csv", StorageKey: "exports/in-s3/tasks-20240502.csv",
+	}, nil)
+	repo.On("GetByID", ctx, "running").Return(&models.ExportJob{ID: "running", RequestedBy: "user-1", Status: models.ExportRunning, Progress: 40}, nil)
+	store.On("PresignGet", ctx, "exports/in-s3/tasks-20240502.csv", "tasks-20240502.csv", DownloadURLExpiry).
+		Return("https://bucket.s3.amazonaws.com/signed", nil).Once()
+
+	job, err := svc.GetExport(ctx, "in-db", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/exports/in-db/download", job.DownloadURL)
+
+	job, err = svc.GetExport(ctx, "in-s3", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://bucket.s3.amazonaws.com/signed", job.DownloadURL)
+
+	job, err = svc.GetExport(ctx, "running", "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, job.DownloadURL)
+	assert.Equal(t, 40, job.Progress)
+
+	// Other users' exports are not found
+	_, err = svc.GetExport(ctx, "in-db", "user-2")
+	assert.Error(t, err)
+	_, _, err = svc.Download(ctx, "running", "user-1")
+	assert.Error(t, err)
+}
+
+func TestExportRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    models.ExportRequest
+		wantFormat models.ExportFormat
+		wantErr    bool
+	}{
+		{name: "tasks default csv", request: models.ExportRequest{Kind: models.ExportTasks}, wantFormat: models.ExportCSV},
+		{name: "account default json", request: models.ExportRequest{Kind: models.ExportAccount}, wantFormat: models.ExportJSON},
+		{name: "report", request: models.ExportRequest{Kind: models.ExportReport, ReportID: "r1", Format: models.ExportJSON}, wantFormat: models.ExportJSON},
+		{name: "report without id", request: models.ExportRequest{Kind: models.ExportReport}, wantErr: true},
+		{name: "account as csv", request: models.ExportRequest{Kind: models.ExportAccount, Format: models.ExportCSV}, wantErr: true},
+		{name: "unknown kind", request: models.ExportRequest{Kind: "everything"}, wantErr: true},
+		{name: "unknown format", request: models.ExportRequest{Kind: models.ExportTasks, Format: "xlsx"}, wantErr: true},
+		{name: "invalid status", request: models.ExportRequest{Kind: models.ExportTasks, Filter: &models.ExportTaskFilter{
+			Status: []models.TaskStatus{"done"},
+		}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFormat, tt.request.Format)
+		})
+	}
+}
+
+func TestConsumeExport_HandsBackRunningJobs(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockExportRepository)
+	svc := newTestExportService(repo, new(MockTaskRepository), nil, now)
+	svc.queue = new(MockJobQueue)
+
+	repo.On("Claim", ctx, "running", now, exportLease, models.MaxExportAttempts).Return(nil, nil).Once()
+	repo.On("GetByID", ctx, "running").Return(&models.ExportJob{ID: "running", Status: models.ExportRunning}, nil).Once()
+	repo.On("Claim", ctx, "done", now, exportLease, models.MaxExportAttempts).Return(nil, nil).Once()
+	repo.On("GetByID", ctx, "done").Return(&models.ExportJob{ID: "done", Status: models.ExportCompleted}, nil).Once()
+
+	assert.ErrorIs(t, svc.consume(ctx, "running"), errJobBusy)
+	// Jobs that already finished are dropped from the queue
+	assert.NoError(t, svc.consume(ctx, "done"))
+	repo.AssertExpectations(t)
+}
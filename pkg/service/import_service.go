@@ -0,0 +1,330 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"sample/task-management-system/pkg/importer"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+const (
+	// importChunkSize is the number of rows processed between checkpoints
+	importChunkSize = 100
+	// importLease is how long a worker may go without a checkpoint before its job is
+	// resumed by another
+	importLease = 5 * time.Minute
+)
+
+// ErrImportFinished is returned when cancelling an import that already finished
+var ErrImportFinished = errors.New("import has already finished")
+
+// ImportService queues imports of task files and runs them in the background
+type ImportService interface {
+	// StartImport checks the file's header and rows and queues it for import as the user
+	StartImport(ctx context.Context, requestedBy, fileName string, options *models.ImportOptions, content []byte) (*models.ImportJob, error)
+	// GetImport returns one of the user's imports with its progress
+	GetImport(ctx context.Context, id, requestedBy string) (*models.ImportJob, error)
+	// CancelImport stops one of the user's imports; tasks created so far are kept
+	CancelImport(ctx context.Context, id, requestedBy string) (*models.ImportJob, error)
+	// ListFailures returns the rows of one of the user's imports that could not be imported
+	ListFailures(ctx context.Context, id, requestedBy string, page, limit int) ([]*models.ImportRowResult, int, error)
+	// ProcessNext runs or resumes the oldest queued import, reporting whether there was one
+	ProcessNext(ctx context.Context) (bool, error)
+	// Run processes queued imports, polling for them every interval unless they come
+	// from a queue, until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type importService struct {
+	repo  repository.ImportRepository
+	tasks TaskService
+	cache TaskCacheInvalidator // nil leaves cached task lists to expire
+	queue JobQueue             // nil polls the database for queued imports
+	retry models.RetryPolicy
+	now   func() time.Time
+}
+
+// NewImportService creates an import service creating tasks through tasks, so imported
+// tasks are validated and moderated like any other. Cached task responses of the tenant
+// are dropped through cache after every chunk that created tasks. Queued imports are
+// handed to workers through queue, or found by polling when it is nil. Failed imports are
+// resumed from their checkpoint, then dead-lettered, as retry says.
+func NewImportService(repo repository.ImportRepository, tasks TaskService, cache TaskCacheInvalidator, queue JobQueue, retry models.RetryPolicy) ImportService {
+	return &importService{repo: repo, tasks: tasks, cache: cache, queue: queue, retry: retry, now: time.Now}
+}
+
+func (s *importService) StartImport(ctx context.Context, requestedBy, fileName string, options *models.ImportOptions, content []byte) (*models.ImportJob, error) {
+	if requestedBy == "" {
+		return nil, errors.New("imports need a user")
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	if len(content) > models.MaxImportBytes {
+		return nil, fmt.Errorf("file is larger than %d bytes", models.MaxImportBytes)
+	}
+	fileName = cleanFileName(fileName)
+	if fileName == "" {
+		fileName = "import.csv"
+	}
+
+	reader, err := importer.NewReader(bytes.NewReader(content), *options)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := reader.Count()
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, errors.New("file has no rows to import")
+	}
+	if rows > models.MaxImportRows {
+		return nil, fmt.Errorf("file has %d rows; at most %d can be imported at once", rows, models.MaxImportRows)
+	}
+
+	job, err := s.repo.Create(ctx, &models.ImportJob{
+		FileName:    fileName,
+		Options:     *options,
+		RequestedBy: requestedBy,
+		TotalRows:   rows,
+	}, content)
+	if err != nil || s.queue == nil {
+		return job, err
+	}
+	if err := s.queue.Publish(ctx, job.ID); err != nil {
+		// No worker would ever pick the import up
+		if _, err := s.repo.Cancel(ctx, job.ID, s.now()); err != nil {
+			log.Printf("Failed to cancel unqueued import %s: %v", job.ID, err)
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *importService) GetImport(ctx context.Context, id, requestedBy string) (*models.ImportJob, error) {
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.RequestedBy != requestedBy {
+		return nil, errors.New("import not found")
+	}
+	return job, nil
+}
+
+func (s *importService) CancelImport(ctx context.Context, id, requestedBy string) (*models.ImportJob, error) {
+	if _, err := s.GetImport(ctx, id, requestedBy); err != nil {
+		return nil, err
+	}
+
+	cancelled, err := s.repo.Cancel(ctx, id, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrImportFinished
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *importService) ListFailures(ctx context.Context, id, requestedBy string, page, limit int) ([]*models.ImportRowResult, int, error) {
+	if _, err := s.GetImport(ctx, id, requestedBy); err != nil {
+		return nil, 0, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 100
+	}
+	return s.repo.Failures(ctx, id, page, limit)
+}
+
+func (s *importService) ProcessNext(ctx context.Context) (bool, error) {
+	return s.processJob(ctx, "")
+}
+
+// processJob runs or resumes the queued import id, or the oldest one when id is empty,
+// reporting whether it could be claimed
+func (s *importService) processJob(ctx context.Context, id string) (bool, error) {
+	job, err := s.repo.Claim(ctx, id, s.now(), importLease, s.retry.MaxAttempts)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	// Tasks are created in the tenant the job was requested in
+	if err := s.process(tenant.WithID(ctx, job.OrgID), job); err != nil {
+		// Jobs interrupted by a shutdown are resumed by the next worker
+		if ctx.Err() != nil {
+			return true, ctx.Err()
+		}
+		if !retryable(err) {
+			log.Printf("Import %s failed: %v", job.ID, err)
+			return true, s.repo.Finish(ctx, job.ID, models.ImportFailed, err.Error(), s.now())
+		}
+		if s.retry.Retries(job.Attempts) {
+			retryAt := s.now().Add(s.retry.Backoff(job.Attempts))
+			log.Printf("Import %s failed on attempt %d, retrying at %s: %v", job.ID, job.Attempts, retryAt.Format(time.RFC3339), err)
+			return true, s.repo.Retry(ctx, job.ID, err.Error(), retryAt)
+		}
+		log.Printf("Import %s failed on attempt %d, dead-lettering it: %v", job.ID, job.Attempts, err)
+		return true, s.repo.DeadLetter(ctx, job.ID, err.Error(), s.now())
+	}
+	return true, nil
+}
+
+func (s *importService) Run(ctx context.Context, interval time.Duration) {
+	if s.queue != nil {
+		go s.queue.Consume(ctx, s.consume)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Imports from the queue are run as they are handed over; retries are
+			// queued again once due
+			if s.queue != nil {
+				queueRetries(ctx, s.queue, s.repo.ReleaseRetries, s.now())
+				continue
+			}
+			for {
+				processed, err := s.ProcessNext(ctx)
+				if err != nil {
+					log.Printf("Failed to process import: %v", err)
+				}
+				if !processed || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// consume runs an import handed over by the queue. Imports another worker is still
+// running are handed back, so they are resumed should that worker stop.
+func (s *importService) consume(ctx context.Context, id string) error {
+	processed, err := s.processJob(ctx, id)
+	if err != nil || processed {
+		return err
+	}
+	job, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Status == models.ImportRunning {
+		return errJobBusy
+	}
+	return nil
+}
+
+// process imports the job's rows after its checkpoint, a chunk at a time, and stops
+// early when the job is cancelled
+func (s *importService) process(ctx context.Context, job *models.ImportJob) error {
+	content, err := s.repo.Content(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	reader, err := importer.NewReader(bytes.NewReader(content), job.Options)
+	if err != nil {
+		return jobRejected{err}
+	}
+	// Rows after the checkpoint may have been imported just before the last worker stopped
+	done, err := s.repo.ProcessedRows(ctx, job.ID, job.ProcessedRows+1)
+	if err != nil {
+		return err
+	}
+
+	// created is set while tasks created since the last checkpoint may still be cached as
+	// missing from task lists; they are dropped at every checkpoint and however this stops
+	created := false
+	defer func() {
+		if created {
+			s.invalidateTasks(context.WithoutCancel(ctx), job.OrgID)
+		}
+	}()
+
+	processed := job.ProcessedRows
+	for {
+		task, row, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if row <= job.ProcessedRows {
+			continue
+		}
+		if done[row] {
+			processed = row
+			continue
+		}
+
+		result := &models.ImportRowResult{Row: row}
+		var rowErr *importer.RowError
+		switch {
+		case errors.As(err, &rowErr):
+			result.Error = rowErr.Err.Error()
+		case err != nil:
+			return err
+		default:
+			task.OwnerID = job.RequestedBy
+			imported, err := s.tasks.CreateTask(ctx, task)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.TaskID = imported.ID
+				created = true
+			}
+		}
+		if err := s.repo.RecordRow(ctx, job.ID, result); err != nil {
+			return err
+		}
+
+		processed = row
+		if processed%importChunkSize == 0 {
+			running, err := s.repo.Checkpoint(ctx, job.ID, processed, s.now())
+			if err != nil {
+				return err
+			}
+			if created {
+				s.invalidateTasks(ctx, job.OrgID)
+				created = false
+			}
+			if !running {
+				return nil
+			}
+		}
+	}
+
+	running, err := s.repo.Checkpoint(ctx, job.ID, processed, s.now())
+	if err != nil || !running {
+		return err
+	}
+	return s.repo.Finish(ctx, job.ID, models.ImportCompleted, "", s.now())
+}
+
+// invalidateTasks drops the tenant's cached task responses; failures are logged, leaving
+// the entries to expire
+func (s *importService) invalidateTasks(ctx context.Context, orgID string) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.InvalidateTasks(ctx, orgID); err != nil {
+		log.Printf("Failed to invalidate cached tasks of organization %q: %v", orgID, err)
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// MockImportRepository is a mock implementation of ImportRepository
+type MockImportRepository struct {
+	mock.Mock
+}
+
+func (m *MockImportRepository) Create(ctx context.Context, job *models.ImportJob, content []byte) (*models.ImportJob, error) {
+	args := m.Called(ctx, job, content)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportJob), args.Error(1)
+}
+
+func (m *MockImportRepository) GetByID(ctx context.Context, id string) (*models.ImportJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportJob), args.Error(1)
+}
+
+func (m *MockImportRepository) CountPending(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockImportRepository) Claim(ctx context.Context, id string, now time.Time, lease time.Duration, maxAttempts int) (*models.ImportJob, error) {
+	args := m.Called(ctx, id, now, lease, maxAttempts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImportJob), args.Error(1)
+}
+
+func (m *MockImportRepository) Retry(ctx context.Context, id, message string, runAfter time.Time) error {
+	args := m.Called(ctx, id, message, runAfter)
+	return args.Error(0)
+}
+
+func (m *MockImportRepository) DeadLetter(ctx context.Context, id, message string, now time.Time) error {
+	args := m.Called(ctx, id, message, now)
+	return args.Error(0)
+}
+
+func (m *MockImportRepository) ReleaseRetries(ctx context.Context, now time.Time) ([]string, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockImportRepository) Content(ctx context.Context, id string) ([]byte, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockImportRepository) ProcessedRows(ctx context.Context, id string, from int) (map[int]bool, error) {
+	args := m.Called(ctx, id, from)
+	return args.Get(0).(map[int]bool), args.Error(1)
+}
+
+func (m *MockImportRepository) RecordRow(ctx context.Context, id string, result *models.ImportRowResult) error {
+	args := m.Called(ctx, id, result)
+	return args.Error(0)
+}
+
+func (m *MockImportRepository) Checkpoint(ctx context.Context, id string, processed int, now time.Time) (bool, error) {
+	args := m.Called(ctx, id, processed, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockImportRepository) Finish(ctx context.Context, id string, status models.ImportStatus, message string, now time.Time) error {
+	args := m.Called(ctx, id, status, message, now)
+	return args.Error(0)
+}
+
+func (m *MockImportRepository) Cancel(ctx context.Context, id string, now time.Time) (bool, error) {
+	args := m.Called(ctx, id, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockImportRepository) Failures(ctx context.Context, id string, page, limit int) ([]*models.ImportRowResult, int, error) {
+	args := m.Called(ctx, id, page, limit)
+	return args.Get(0).([]*models.ImportRowResult), args.Int(1), args.Error(2)
+}
+
+// recordingInvalidator records the organizations whose cached tasks were invalidated
+type recordingInvalidator struct {
+	orgIDs []string
+}
+
+func (i *recordingInvalidator) InvalidateTasks(ctx context.Context, orgID string) error {
+	i.orgIDs = append(i.orgIDs, orgID)
+	return nil
+}
+
+func newTestImportService(repo *MockImportRepository, tasks *MockTaskRepository, now time.Time) *importService {
+	svc := NewImportService(repo, NewTaskService(tasks), nil, nil, models.DefaultRetryPolicies[models.JobImport]).(*importService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestStartImport(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockImportRepository)
+	svc := newTestImportService(repo, new(MockTaskRepository), time.Now())
+
+	content := []byte("Summary,Status\nFirst,To Do\nSecond,Done\n")
+	repo.On("Create", ctx, mock.MatchedBy(func(job *models.ImportJob) bool {
+		return job.TotalRows == 2 && job.RequestedBy == "user-1" && job.FileName == "issues.csv" &&
+			job.Options.Source == models.ImportJira && job.Options.ProjectKey == models.DefaultProjectKey
+	}), content).Return(&models.ImportJob{ID: "import-1"}, nil).Once()
+
+	job, err := svc.StartImport(ctx, "user-1", "../issues.csv", &models.ImportOptions{Source: models.ImportJira}, content)
+	require.NoError(t, err)
+	assert.Equal(t, "import-1", job.ID)
+
+	_, err = svc.StartImport(ctx, "user-1", "tasks.csv", &models.ImportOptions{Source: models.ImportCSV}, []byte("name\nx\n"))
+	assert.Error(t, err)
+	_, err = svc.StartImport(ctx, "user-1", "tasks.csv", &models.ImportOptions{Source: models.ImportCSV}, []byte("title\n"))
+	assert.Error(t, err)
+	_, err = svc.StartImport(ctx, "user-1", "tasks.csv", &models.ImportOptions{Source: "xlsx"}, content)
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestStartImport_Queued(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockImportRepository)
+	queue := new(MockJobQueue)
+	svc := newTestImportService(repo, new(MockTaskRepository), now)
+	svc.queue = queue
+
+	content := []byte("title\nFirst\n")
+	repo.On("Create", ctx, mock.Anything, content).Return(&models.ImportJob{ID: "import-1"}, nil).Once()
+	queue.On("Publish", ctx, "import-1").Return(nil).Once()
+	repo.On("Create", ctx, mock.Anything, content).Return(&models.ImportJob{ID: "import-2"}, nil).Once()
+	queue.On("Publish", ctx, "import-2").Return(errors.New("connection refused")).Once()
+	// An import that never reaches a worker is cancelled rather than left pending
+	repo.On("Cancel", ctx, "import-2", now).Return(true, nil).Once()
+
+	job, err := svc.StartImport(ctx, "user-1", "tasks.csv", &models.ImportOptions{Source: models.ImportCSV}, content)
+	require.NoError(t, err)
+	assert.Equal(t, "import-1", job.ID)
+
+	_, err = svc.StartImport(ctx, "user-1", "tasks.csv", &models.ImportOptions{Source: models.ImportCSV}, content)
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+	queue.AssertExpectations(t)
+}
+
+func TestProcessNext_ResumesFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	// Jobs run scoped to the tenant they were requested in
+	jobCtx := tenant.WithID(ctx, "org-1")
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockImportRepository)
+	tasks := new(MockTaskRepository)
+	svc := newTestImportService(repo, tasks, now)
+	invalidator := &recordingInvalidator{}
+	svc.cache = invalidator
+
+	job := &models.ImportJob{ID: "import-1", RequestedBy: "user-1", OrgID: "org-1", TotalRows: 4, ProcessedRows: 1,
+		Options: models.ImportOptions{Source: models.ImportCSV, ProjectKey: "TASK"}}
+	content := "title,due_date\nOne,2099-01-01\nTwo,2099-01-01\nThree,2099-01-01\nFour,someday\n"
+	repo.On("Claim", ctx, "", now, importLease, models.MaxImportAttempts).Return(job, nil).Once()
+	repo.On("Content", jobCtx, "import-1").Return([]byte(content), nil).Once()
+	// Row 2 was imported just before the previous worker stopped
+	repo.On("ProcessedRows", jobCtx, "import-1", 2).Return(map[int]bool{2: true}, nil).Once()
+
+	tasks.On("Create", jobCtx, mock.MatchedBy(func(task *models.TaskCreate) bool {
+		return task.Title == "Three" && task.OwnerID == "user-1"
+	})).Return(&models.Task{ID: "task-3"}, nil).Once()
+	repo.On("RecordRow", jobCtx, "import-1", &models.ImportRowResult{Row: 3, TaskID: "task-3"}).Return(nil).Once()
+	repo.On("RecordRow", jobCtx, "import-1", mock.MatchedBy(func(result *models.ImportRowResult) bool {
+		return result.Row == 4 && result.TaskID == "" && strings.Contains(result.Error, "invalid due date")
+	})).Return(nil).Once()
+	repo.On("Checkpoint", jobCtx, "import-1", 4, now).Return(true, nil).Once()
+	repo.On("Finish", jobCtx, "import-1", models.ImportCompleted, "", now).Return(nil).Once()
+
+	processed, err := svc.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.True(t, processed)
+	// Cached task lists of the tenant no longer miss the imported task
+	assert.Equal(t, []string{"org-1"}, invalidator.orgIDs)
+	repo.AssertExpectations(t)
+	tasks.AssertExpectations(t)
+}
+
+func TestProcessNext_StopsWhenCancelled(t *testing.T) {
+	ctx := context.Background()
+	jobCtx := tenant.WithID(ctx, "")
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockImportRepository)
+	tasks := new(MockTaskRepository)
+	svc := newTestImportService(repo, tasks, now)
+	invalidator := &recordingInvalidator{}
+	svc.cache = invalidator
+
+	var content strings.Builder
+	content.WriteString("title,due_date\n")
+	for i := 1; i <= importChunkSize+50; i++ {
+		fmt.Fprintf(&content, "Task %d,2099-01-01\n", i)
+	}
+	job := &models.ImportJob{ID: "import-1", RequestedBy: "user-1", TotalRows: importChunkSize + 50,
+		Options: models.ImportOptions{Source: models.ImportCSV, ProjectKey: "TASK"}}
+	repo.On("Claim", ctx, "", now, importLease, models.MaxImportAttempts).Return(job, nil).Once()
+	repo.On("Content", jobCtx, "import-1").Return([]byte(content.String()), nil).Once()
+	repo.On("ProcessedRows", jobCtx, "import-1", 1).Return(map[int]bool{}, nil).Once()
+	tasks.On("Create", jobCtx, mock.Anything).Return(&models.Task{ID: "task"}, nil).Times(importChunkSize)
+	repo.On("RecordRow", jobCtx, "import-1", mock.Anything).Return(nil).Times(importChunkSize)
+	// The job was cancelled during the first chunk
+	repo.On("Checkpoint", jobCtx, "import-1", importChunkSize, now).Return(false, nil).Once()
+
+	processed, err := svc.ProcessNext(ctx)
+	require.NoError(t, err)
+	assert.True(t, processed)
+	repo.AssertNotCalled(t, "Finish", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	// Tasks of the first chunk are dropped from the cache once, at its checkpoint
+	assert.Equal(t, []string{""}, invalidator.orgIDs)
+	repo.AssertExpectations(t)
+	tasks.AssertExpectations(t)
+}
+
+func TestCancelImport(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockImportRepository)
+	svc := newTestImportService(repo, new(MockTaskRepository), now)
+
+	repo.On("GetByID", ctx, "running").Return(&models.ImportJob{ID: "running", RequestedBy: "user-1", Status: models.ImportRunning}, nil).Once()
+	repo.On("Cancel", ctx, "running", now).Return(true, nil).Once()
+	repo.On("GetByID", ctx, "running").Return(&models.ImportJob{ID: "running", RequestedBy: "user-1", Status: models.ImportCancelled}, nil).Once()
+	repo.On("GetByID", ctx, "done").Return(&models.ImportJob{ID: "done", RequestedBy: "user-1", Status: models.ImportCompleted}, nil)
+	repo.On("Cancel", ctx, "done", now).Return(false, nil).Once()
+
+	job, err := svc.CancelImport(ctx, "running", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportCancelled, job.Status)
+
+	_, err = svc.CancelImport(ctx, "done", "user-1")
+	assert.ErrorIs(t, err, ErrImportFinished)
+
+	// Other users' imports are not found
+	_, err = svc.CancelImport(ctx, "done", "user-2")
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
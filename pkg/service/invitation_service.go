@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/notify"
+	"sample/task-management-system/pkg/repository"
+)
+
+// InvitationService invites users into organizations by email
+type InvitationService interface {
+	// Invite creates an invitation to the organization and emails its token to the invitee
+	Invite(ctx context.Context, orgID, invitedBy string, input *models.InvitationCreate) (*models.Invitation, error)
+	ListInvitations(ctx context.Context, orgID string) ([]*models.Invitation, error)
+	// RevokeInvitation deletes an invitation that was not accepted yet
+	RevokeInvitation(ctx context.Context, orgID, id string) error
+	// AcceptInvitation makes the user a member of the organization the token invites to,
+	// with the invitation's role
+	AcceptInvitation(ctx context.Context, token, userID string) (*models.OrgMembership, error)
+	ListMembers(ctx context.Context, orgID string) ([]*models.OrgMembership, error)
+}
+
+type invitationService struct {
+	repo    repository.InvitationRepository
+	sender  notify.Sender
+	baseURL string
+	now     func() time.Time
+}
+
+// NewInvitationService creates an invitation service. Invitation emails link to
+// baseURL + "/invitations/accept?token=...".
+func NewInvitationService(repo repository.InvitationRepository, sender notify.Sender, baseURL string) InvitationService {
+	return &invitationService{
+		repo:    repo,
+		sender:  sender,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		now:     time.Now,
+	}
+}
+
+func (s *invitationService) Invite(ctx context.Context, orgID, invitedBy string, input *models.InvitationCreate) (*models.Invitation, error) {
+	if orgID == "" {
+		return nil, errors.New("org id is required")
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	token, err := newInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+	invitation, err := s.repo.Create(ctx, &models.Invitation{
+		OrgID:     orgID,
+		Email:     input.Email,
+		Role:      input.Role,
+		InvitedBy: invitedBy,
+		ExpiresAt: s.now().Add(models.InvitationTTL),
+	}, hashInvitationToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	// The token is only ever sent to the invitee; an invitation that can't be sent is
+	// revoked so it can't linger unused
+	msg, err := notify.RenderInvitation(invitation, s.baseURL+"/invitations/accept?token="+url.QueryEscape(token))
+	if err == nil {
+		err = s.sender.Send(ctx, msg)
+	}
+	if err != nil {
+		if revokeErr := s.repo.Delete(ctx, orgID, invitation.ID); revokeErr != nil {
+			log.Printf("Failed to revoke unsent invitation %s: %v", invitation.ID, revokeErr)
+		}
+		return nil, errors.New("invitation could not be sent")
+	}
+	return invitation, nil
+}
+
+func (s *invitationService) ListInvitations(ctx context.Context, orgID string) ([]*models.Invitation, error) {
+	return s.repo.ListByOrg(ctx, orgID)
+}
+
+func (s *invitationService) RevokeInvitation(ctx context.Context, orgID, id string) error {
+	return s.repo.Delete(ctx, orgID, id)
+}
+
+func (s *invitationService) AcceptInvitation(ctx context.Context, token, userID string) (*models.OrgMembership, error) {
+	if token == "" {
+		return nil, errors.New("token is required")
+	}
+	return s.repo.Accept(ctx, hashInvitationToken(token), userID, s.now())
+}
+
+func (s *invitationService) ListMembers(ctx context.Context, orgID string) ([]*models.OrgMembership, error) {
+	return s.repo.ListMembers(ctx, orgID)
+}
+
+// MembershipLookup scopes user tokens to the organization each user joined last, with
+// the role they were invited with, for auth.WithUserOrgs
+func MembershipLookup(repo repository.InvitationRepository) auth.UserOrgLookup {
+	return func(ctx context.Context, userID string) (string, string, error) {
+		member, err := repo.GetMembership(ctx, userID)
+		if errors.Is(err, models.ErrMembershipNotFound) {
+			return "", "", nil
+		}
+		if err != nil {
+			return "", "", err
+		}
+		return member.OrgID, member.Role, nil
+	}
+}
+
+// newInvitationToken returns a random 256-bit invitation token
+func newInvitationToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// hashInvitationToken hashes a token for storage. Tokens are 256-bit random values, so a
+// fast hash is sufficient.
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
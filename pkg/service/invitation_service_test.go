@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/notify"
+)
+
+// MockInvitationRepository is a mock implementation of InvitationRepository
+type MockInvitationRepository struct {
+	mock.Mock
+}
+
+func (m *MockInvitationRepository) Create(ctx context.Context, invitation *models.Invitation, tokenHash string) (*models.Invitation, error) {
+	args := m.Called(ctx, invitation, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) ListByOrg(ctx context.Context, orgID string) ([]*models.Invitation, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) Delete(ctx context.Context, orgID, id string) error {
+	args := m.Called(ctx, orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockInvitationRepository) Accept(ctx context.Context, tokenHash, userID string, now time.Time) (*models.OrgMembership, error) {
+	args := m.Called(ctx, tokenHash, userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrgMembership), args.Error(1)
+}
+
+func (m *MockInvitationRepository) ListMembers(ctx context.Context, orgID string) ([]*models.OrgMembership, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.OrgMembership), args.Error(1)
+}
+
+func (m *MockInvitationRepository) GetMembership(ctx context.Context, userID string) (*models.OrgMembership, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrgMembership), args.Error(1)
+}
+
+// failingSender fails every message it is asked to send
+type failingSender struct{}
+
+func (failingSender) Send(ctx context.Context, msg *notify.Message) error {
+	return errors.New("relay unavailable")
+}
+
+func newTestInvitationService(repo *MockInvitationRepository, sender notify.Sender, now time.Time) *invitationService {
+	svc := NewInvitationService(repo, sender, "https://tasks.example.com/").(*invitationService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestInvite_EmailsTokenAndStoresItsHash(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo := new(MockInvitationRepository)
+	sender := &recordingSender{}
+	svc := newTestInvitationService(repo, sender, now)
+
+	var storedHash string
+	repo.On("Create", ctx, mock.MatchedBy(func(invitation *models.Invitation) bool {
+		return invitation.OrgID == "acme" && invitation.Email == "new@example.com" && invitation.Role == "user" &&
+			invitation.InvitedBy == "admin-1" && invitation.ExpiresAt.Equal(now.Add(models.InvitationTTL))
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		storedHash = args.String(2)
+	}).Return(&models.Invitation{ID: "inv-1", OrgID: "acme", Email: "new@example.com", Role: "user",
+		ExpiresAt: now.Add(models.InvitationTTL)}, nil).Once()
+
+	invitation, err := svc.Invite(ctx, "acme", "admin-1", &models.InvitationCreate{Email: " New@Example.com "})
+	require.NoError(t, err)
+	assert.Equal(t, "inv-1", invitation.ID)
+
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "new@example.com", sender.sent[0].To)
+	link := regexp.MustCompile(`https://tasks\.example\.com/invitations/accept\?token=\S+`).FindString(sender.sent[0].Text)
+	require.NotEmpty(t, link)
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	token := parsed.Query().Get("token")
+
+	// Only the hash of the emailed token is stored
+	assert.NotEqual(t, token, storedHash)
+	assert.Equal(t, hashInvitationToken(token), storedHash)
+	repo.AssertExpectations(t)
+}
+
+func TestInvite_RevokesUnsentInvitations(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockInvitationRepository)
+	svc := newTestInvitationService(repo, failingSender{}, time.Now())
+
+	repo.On("Create", ctx, mock.Anything, mock.Anything).
+		Return(&models.Invitation{ID: "inv-1", OrgID: "acme", Email: "new@example.com", Role: "viewer"}, nil).Once()
+	repo.On("Delete", ctx, "acme", "inv-1").Return(nil).Once()
+
+	_, err := svc.Invite(ctx, "acme", "admin-1", &models.InvitationCreate{Email: "new@example.com", Role: "viewer"})
+	assert.EqualError(t, err, "invitation could not be sent")
+	repo.AssertExpectations(t)
+}
+
+func TestInvite_Validation(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestInvitationService(new(MockInvitationRepository), &recordingSender{}, time.Now())
+
+	tests := []struct {
+		name  string
+		orgID string
+		input models.InvitationCreate
+		want  string
+	}{
+		{"no organization", "", models.InvitationCreate{Email: "new@example.com"}, "org id is required"},
+		{"invalid email", "acme", models.InvitationCreate{Email: "not-an-email"}, "a valid email is required"},
+		{"display name", "acme", models.InvitationCreate{Email: "New <new@example.com>"}, "a valid email is required"},
+		{"platform role", "acme", models.InvitationCreate{Email: "new@example.com", Role: "admin"}, "role must be viewer, user or org_admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Invite(ctx, tt.orgID, "admin-1", &tt.input)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo := new(MockInvitationRepository)
+	svc := newTestInvitationService(repo, &recordingSender{}, now)
+
+	membership := &models.OrgMembership{OrgID: "acme", UserID: "user-1", Role: "org_admin", JoinedAt: now}
+	repo.On("Accept", ctx, hashInvitationToken("token-1"), "user-1", now).Return(membership, nil).Once()
+	repo.On("Accept", ctx, hashInvitationToken("used"), "user-1", now).Return(nil, models.ErrInvitationUnavailable).Once()
+
+	got, err := svc.AcceptInvitation(ctx, "token-1", "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, membership, got)
+
+	_, err = svc.AcceptInvitation(ctx, "used", "user-1")
+	assert.ErrorIs(t, err, models.ErrInvitationUnavailable)
+
+	_, err = svc.AcceptInvitation(ctx, "", "user-1")
+	assert.EqualError(t, err, "token is required")
+	repo.AssertExpectations(t)
+}
+
+func TestAcceptInvitation_ScopesLaterTokens(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo := new(MockInvitationRepository)
+	svc := newTestInvitationService(repo, &recordingSender{}, now)
+	tokens := auth.NewTokenManager([]byte("test-secret"), "test-issuer", auth.WithUserOrgs(MembershipLookup(repo)))
+
+	// Before accepting, the user is outside any organization
+	repo.On("GetMembership", ctx, "user-1").Return(nil, models.ErrMembershipNotFound).Once()
+	pair, err := tokens.CreateTokenPair(ctx, "user-1", []string{"user"})
+	require.NoError(t, err)
+	claims, err := tokens.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Empty(t, claims.OrgID)
+
+	membership := &models.OrgMembership{OrgID: "acme", UserID: "user-1", Role: "org_admin", JoinedAt: now}
+	repo.On("Accept", ctx, hashInvitationToken("token-1"), "user-1", now).Return(membership, nil).Once()
+	repo.On("GetMembership", ctx, "user-1").Return(membership, nil).Once()
+	_, err = svc.AcceptInvitation(ctx, "token-1", "user-1")
+	require.NoError(t, err)
+
+	pair, err = tokens.CreateTokenPair(ctx, "user-1", []string{"user"})
+	require.NoError(t, err)
+	claims, err = tokens.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", claims.OrgID)
+	assert.Equal(t, []string{"user", "org_admin"}, claims.Roles)
+	repo.AssertExpectations(t)
+}
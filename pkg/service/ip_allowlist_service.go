@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// allowlistCacheTTL bounds how long other instances may keep using a stale allowlist
+const allowlistCacheTTL = 30 * time.Second
+
+// IPAllowlistService manages and enforces per-organization IP allowlists
+type IPAllowlistService interface {
+	ListEntries(ctx context.Context, orgID string) ([]*models.IPAllowlistEntry, error)
+	AddEntry(ctx context.Context, orgID, createdBy string, input *models.IPAllowlistEntryCreate) (*models.IPAllowlistEntry, error)
+	RemoveEntry(ctx context.Context, orgID, id string) error
+	// IsAllowed reports whether ip may access the organization; an empty allowlist allows everyone
+	IsAllowed(ctx context.Context, orgID string, ip net.IP) (bool, error)
+}
+
+type cachedAllowlist struct {
+	networks []*net.IPNet
+	loadedAt time.Time
+}
+
+type ipAllowlistService struct {
+	repo repository.IPAllowlistRepository
+
+	mu    sync.RWMutex
+	cache map[string]cachedAllowlist
+	now   func() time.Time
+}
+
+// NewIPAllowlistService creates a new IP allowlist service
+func NewIPAllowlistService(repo repository.IPAllowlistRepository) IPAllowlistService {
+	return &ipAllowlistService{
+		repo:  repo,
+		cache: make(map[string]cachedAllowlist),
+		now:   time.Now,
+	}
+}
+
+func (s *ipAllowlistService) ListEntries(ctx context.Context, orgID string) ([]*models.IPAllowlistEntry, error) {
+	return s.repo.ListByOrg(ctx, orgID)
+}
+
+func (s *ipAllowlistService) AddEntry(ctx context.Context, orgID, createdBy string, input *models.IPAllowlistEntryCreate) (*models.IPAllowlistEntry, error) {
+	if orgID == "" {
+		return nil, errors.New("org id is required")
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.repo.Create(ctx, &models.IPAllowlistEntry{
+		OrgID:       orgID,
+		CIDR:        input.CIDR,
+		Description: input.Description,
+		CreatedBy:   createdBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(orgID)
+	return entry, nil
+}
+
+func (s *ipAllowlistService) RemoveEntry(ctx context.Context, orgID, id string) error {
+	if err := s.repo.Delete(ctx, orgID, id); err != nil {
+		return err
+	}
+
+	s.invalidate(orgID)
+	return nil
+}
+
+func (s *ipAllowlistService) IsAllowed(ctx context.Context, orgID string, ip net.IP) (bool, error) {
+	networks, err := s.networks(ctx, orgID)
+	if err != nil {
+		return false, err
+	}
+	if len(networks) == 0 {
+		return true, nil
+	}
+	if ip == nil {
+		return false, nil
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// networks returns the parsed allowlist for an organization, loading it if the cached copy is stale
+func (s *ipAllowlistService) networks(ctx context.Context, orgID string) ([]*net.IPNet, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[orgID]
+	s.mu.RUnlock()
+	if ok && s.now().Sub(cached.loadedAt) < allowlistCacheTTL {
+		return cached.networks, nil
+	}
+
+	entries, err := s.repo.ListByOrg(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	networks := make([]*net.IPNet, 0, len(entries))
+	for _, entry := range entries {
+		_, network, err := net.ParseCIDR(entry.CIDR)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+
+	s.mu.Lock()
+	s.cache[orgID] = cachedAllowlist{networks: networks, loadedAt: s.now()}
+	s.mu.Unlock()
+
+	return networks, nil
+}
+
+func (s *ipAllowlistService) invalidate(orgID string) {
+	s.mu.Lock()
+	delete(s.cache, orgID)
+	s.mu.Unlock()
+}
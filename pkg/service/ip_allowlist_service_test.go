@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockIPAllowlistRepository is a mock implementation of IPAllowlistRepository
+type MockIPAllowlistRepository struct {
+	mock.Mock
+}
+
+func (m *MockIPAllowlistRepository) Create(ctx context.Context, entry *models.IPAllowlistEntry) (*models.IPAllowlistEntry, error) {
+	args := m.Called(ctx, entry)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IPAllowlistEntry), args.Error(1)
+}
+
+func (m *MockIPAllowlistRepository) ListByOrg(ctx context.Context, orgID string) ([]*models.IPAllowlistEntry, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.IPAllowlistEntry), args.Error(1)
+}
+
+func (m *MockIPAllowlistRepository) Delete(ctx context.Context, orgID, id string) error {
+	args := m.Called(ctx, orgID, id)
+	return args.Error(0)
+}
+
+func TestIPAllowlistIsAllowed(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		entries []*models.IPAllowlistEntry
+		listErr error
+		ip      string
+		want    bool
+		wantErr bool
+	}{
+		{name: "empty allowlist allows everyone", ip: "198.51.100.1", want: true},
+		{name: "inside range", entries: []*models.IPAllowlistEntry{{CIDR: "203.0.113.0/24"}}, ip: "203.0.113.9", want: true},
+		{name: "outside range", entries: []*models.IPAllowlistEntry{{CIDR: "203.0.113.0/24"}}, ip: "198.51.100.1", want: false},
+		{name: "ipv6 range", entries: []*models.IPAllowlistEntry{{CIDR: "2001:db8::/32"}}, ip: "2001:db8::1", want: true},
+		{name: "repository error", listErr: errors.New("db down"), ip: "203.0.113.9", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockIPAllowlistRepository)
+			if tt.listErr != nil {
+				repo.On("ListByOrg", ctx, "acme").Return(nil, tt.listErr)
+			} else {
+				repo.On("ListByOrg", ctx, "acme").Return(tt.entries, nil)
+			}
+
+			allowed, err := NewIPAllowlistService(repo).IsAllowed(ctx, "acme", net.ParseIP(tt.ip))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, allowed)
+		})
+	}
+}
+
+func TestIPAllowlistCaching(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockIPAllowlistRepository)
+	svc := NewIPAllowlistService(repo).(*ipAllowlistService)
+	now := time.Now()
+	svc.now = func() time.Time { return now }
+
+	repo.On("ListByOrg", ctx, "acme").Return([]*models.IPAllowlistEntry{{CIDR: "203.0.113.0/24"}}, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := svc.IsAllowed(ctx, "acme", net.ParseIP("203.0.113.9"))
+		require.NoError(t, err)
+	}
+	repo.AssertNumberOfCalls(t, "ListByOrg", 1)
+
+	// Stale entries are reloaded
+	now = now.Add(allowlistCacheTTL)
+	_, err := svc.IsAllowed(ctx, "acme", net.ParseIP("203.0.113.9"))
+	require.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "ListByOrg", 2)
+
+	// Changes invalidate the cache immediately
+	repo.On("Create", ctx, mock.AnythingOfType("*models.IPAllowlistEntry")).Return(&models.IPAllowlistEntry{ID: "e1"}, nil)
+	_, err = svc.AddEntry(ctx, "acme", "admin-1", &models.IPAllowlistEntryCreate{CIDR: "198.51.100.1"})
+	require.NoError(t, err)
+	_, err = svc.IsAllowed(ctx, "acme", net.ParseIP("203.0.113.9"))
+	require.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "ListByOrg", 3)
+}
+
+func TestIPAllowlistEntryValidate(t *testing.T) {
+	tests := []struct {
+		cidr    string
+		want    string
+		wantErr bool
+	}{
+		{cidr: "203.0.113.0/24", want: "203.0.113.0/24"},
+		{cidr: "203.0.113.77/24", want: "203.0.113.0/24"},
+		{cidr: "198.51.100.1", want: "198.51.100.1/32"},
+		{cidr: "2001:db8::1", want: "2001:db8::1/128"},
+		{cidr: "", wantErr: true},
+		{cidr: "not-an-ip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cidr, func(t *testing.T) {
+			input := &models.IPAllowlistEntryCreate{CIDR: tt.cidr}
+			err := input.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, input.CIDR)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// JobQueue hands queued job IDs to workers at least once, e.g. cache.JobStream.
+// Services without one poll the database for queued jobs instead.
+type JobQueue interface {
+	// Publish queues a job
+	Publish(ctx context.Context, jobID string) error
+	// Consume passes queued jobs to handle until ctx is done. Jobs that handle fails are
+	// delivered again later, and set aside once they failed too often.
+	Consume(ctx context.Context, handle func(ctx context.Context, jobID string) error)
+}
+
+// errJobBusy is returned to the queue for a job another worker is still running, so it
+// is delivered again in case that worker stops
+var errJobBusy = errors.New("job is running on another worker")
+
+// jobRejected wraps a job failure that trying again can't fix, such as a request the job
+// can't satisfy, so the job fails right away instead of being retried and dead-lettered
+type jobRejected struct {
+	error
+}
+
+func (e jobRejected) Unwrap() error {
+	return e.error
+}
+
+// retryable reports whether a failed job may succeed when it is tried again
+func retryable(err error) bool {
+	var rejected jobRejected
+	return !errors.As(err, &rejected)
+}
+
+// queueRetries hands the jobs whose retry time came, as released by release, to queue
+func queueRetries(ctx context.Context, queue JobQueue, release func(ctx context.Context, now time.Time) ([]string, error), now time.Time) {
+	ids, err := release(ctx, now)
+	if err != nil {
+		log.Printf("Failed to release jobs due for a retry: %v", err)
+		return
+	}
+	for _, id := range ids {
+		if err := queue.Publish(ctx, id); err != nil {
+			log.Printf("Failed to queue job %s for a retry: %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"sample/task-management-system/pkg/metrics"
+	"sample/task-management-system/pkg/repository"
+)
+
+// noOrgDimension is the organization business metrics report tasks without one under
+const noOrgDimension = "none"
+
+// KPIService publishes business metrics per organization through the metrics provider,
+// so product and ops dashboards need no database access
+type KPIService interface {
+	// PublishKPIs records each organization's tasks created and completed in
+	// [since, until), its overdue tasks and its average cycle time
+	PublishKPIs(ctx context.Context, since, until time.Time) error
+	// Run publishes the metrics of the past interval every interval until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type kpiService struct {
+	repo repository.KPIRepository
+	now  func() time.Time
+}
+
+// NewKPIService creates a business metrics service
+func NewKPIService(repo repository.KPIRepository) KPIService {
+	return &kpiService{repo: repo, now: time.Now}
+}
+
+func (s *kpiService) PublishKPIs(ctx context.Context, since, until time.Time) error {
+	kpis, err := s.repo.OrgKPIs(ctx, since, until)
+	if err != nil {
+		return err
+	}
+	for _, org := range kpis {
+		orgID := org.OrgID
+		if orgID == "" {
+			orgID = noOrgDimension
+		}
+		metrics.RecordOrgKPIs(orgID, org.TasksCreated, org.TasksCompleted, org.Overdue, org.AvgCycleTime)
+	}
+	return nil
+}
+
+func (s *kpiService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	since := s.now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A failed window is folded into the next one rather than lost
+			until := s.now()
+			if err := s.PublishKPIs(ctx, since, until); err != nil {
+				log.Printf("Failed to publish business metrics: %v", err)
+				continue
+			}
+			since = until
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockKPIRepository is a mock implementation of KPIRepository
+type MockKPIRepository struct {
+	mock.Mock
+}
+
+func (m *MockKPIRepository) OrgKPIs(ctx context.Context, since, until time.Time) ([]*models.OrgKPIs, error) {
+	args := m.Called(ctx, since, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.OrgKPIs), args.Error(1)
+}
+
+func TestPublishKPIs(t *testing.T) {
+	ctx := context.Background()
+	until := time.Date(2024, 3, 15, 9, 5, 0, 0, time.UTC)
+	since := until.Add(-5 * time.Minute)
+
+	repo := new(MockKPIRepository)
+	repo.On("OrgKPIs", ctx, since, until).Return([]*models.OrgKPIs{
+		{TasksCreated: 1},
+		{OrgID: "acme", TasksCreated: 3, TasksCompleted: 2, Overdue: 1, AvgCycleTime: 90 * time.Minute},
+	}, nil).Once()
+
+	assert.NoError(t, NewKPIService(repo).PublishKPIs(ctx, since, until))
+	repo.AssertExpectations(t)
+}
+
+func TestPublishKPIs_RepositoryError(t *testing.T) {
+	ctx := context.Background()
+	until := time.Date(2024, 3, 15, 9, 5, 0, 0, time.UTC)
+
+	repo := new(MockKPIRepository)
+	repo.On("OrgKPIs", ctx, until.Add(-time.Minute), until).Return(nil, errors.New("connection refused"))
+
+	assert.Error(t, NewKPIService(repo).PublishKPIs(ctx, until.Add(-time.Minute), until))
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// MergeService folds duplicate tasks into the task they duplicate
+type MergeService interface {
+	// MergeTask folds the request's source task into the target task
+	MergeTask(ctx context.Context, targetID string, request *models.TaskMergeRequest) (*models.TaskMerge, error)
+}
+
+// ErrMergeIntoSelf is returned when merging a task into itself
+var ErrMergeIntoSelf = errors.New("a task cannot be merged into itself")
+
+type mergeService struct {
+	merges    repository.MergeRepository
+	tasks     repository.TaskRepository
+	ownership auth.OwnershipChecker
+	publisher events.Publisher
+}
+
+// NewMergeService creates a new merge service. Merges bypass the event-publishing task
+// repository, so the service publishes the source's update itself.
+func NewMergeService(merges repository.MergeRepository, tasks repository.TaskRepository, teams repository.TeamRepository, publisher events.Publisher) MergeService {
+	return &mergeService{
+		merges:    merges,
+		tasks:     tasks,
+		ownership: TaskOwnership(tasks, teams),
+		publisher: publisher,
+	}
+}
+
+func (s *mergeService) MergeTask(ctx context.Context, targetID string, request *models.TaskMergeRequest) (*models.TaskMerge, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	if request.SourceID == targetID {
+		return nil, ErrMergeIntoSelf
+	}
+
+	// The tasks router checked the target; the source is emptied, so it must be the
+	// caller's to change as well
+	if err := auth.CanAccessResource(ctx, request.SourceID, s.ownership); err != nil {
+		return nil, err
+	}
+	for _, id := range []string{targetID, request.SourceID} {
+		task, err := s.tasks.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if task.Status == models.StatusDraft {
+			return nil, errors.New("drafts cannot be merged")
+		}
+	}
+
+	merge, err := s.merges.Merge(ctx, targetID, request.SourceID)
+	if err != nil {
+		return nil, err
+	}
+	s.publisher.Publish(events.Event{Type: events.TaskUpdated, TaskID: merge.Source.ID, Task: merge.Source, OrgID: merge.Source.OrgID})
+	return merge, nil
+}
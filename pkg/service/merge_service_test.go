@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+)
+
+// MockMergeRepository is a mock implementation of MergeRepository
+type MockMergeRepository struct {
+	mock.Mock
+}
+
+func (m *MockMergeRepository) Merge(ctx context.Context, targetID, sourceID string) (*models.TaskMerge, error) {
+	args := m.Called(ctx, targetID, sourceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaskMerge), args.Error(1)
+}
+
+func TestMergeTask(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: "user-1"})
+	target := &models.Task{ID: "target", OwnerID: "user-1", Status: models.StatusInProgress}
+
+	tests := []struct {
+		name     string
+		sourceID string
+		source   *models.Task
+		wantErr  error
+		wantAny  bool
+	}{
+		{name: "duplicate merged", sourceID: "source", source: &models.Task{ID: "source", OwnerID: "user-1", Status: models.StatusPending}},
+		{name: "source required", sourceID: " ", wantAny: true},
+		{name: "into itself", sourceID: "target", wantErr: ErrMergeIntoSelf},
+		{name: "someone else's source", sourceID: "source", source: &models.Task{ID: "source", OwnerID: "user-2", Status: models.StatusPending}, wantErr: auth.ErrResourceNotOwned},
+		{name: "draft source", sourceID: "source", source: &models.Task{ID: "source", OwnerID: "user-1", Status: models.StatusDraft}, wantAny: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merges := new(MockMergeRepository)
+			tasks := new(MockTaskRepository)
+			publisher := &recordingPublisher{}
+			svc := NewMergeService(merges, tasks, nil, publisher)
+
+			tasks.On("GetByID", ctx, "target").Return(target, nil).Maybe()
+			if tt.source != nil {
+				tasks.On("GetByID", ctx, "source").Return(tt.source, nil).Maybe()
+			}
+			archived := &models.Task{ID: "source", Status: models.StatusCancelled}
+			merge := &models.TaskMerge{Task: target, Source: archived, MovedAttachments: 2, MovedWatchers: 1}
+			merges.On("Merge", ctx, "target", "source").Return(merge, nil).Maybe()
+
+			result, err := svc.MergeTask(ctx, "target", &models.TaskMergeRequest{SourceID: tt.sourceID})
+			if tt.wantErr != nil || tt.wantAny {
+				assert.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				merges.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything)
+				assert.Empty(t, publisher.events)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, merge, result)
+			assert.Equal(t, []events.Event{{Type: events.TaskUpdated, TaskID: "source", Task: archived}}, publisher.events)
+		})
+	}
+}
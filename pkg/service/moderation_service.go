@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/moderation"
+	"sample/task-management-system/pkg/repository"
+)
+
+// moderatedTaskService applies content moderation to task titles and descriptions
+type moderatedTaskService struct {
+	TaskService
+	moderator *moderation.Moderator
+}
+
+// NewModeratedTaskService wraps a TaskService with content moderation
+func NewModeratedTaskService(next TaskService, moderator *moderation.Moderator) TaskService {
+	return &moderatedTaskService{
+		TaskService: next,
+		moderator:   moderator,
+	}
+}
+
+func (s *moderatedTaskService) CreateTask(ctx context.Context, task *models.TaskCreate) (*models.Task, error) {
+	result, err := s.moderator.Check(ctx, task.Title, task.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.TaskService.CreateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	s.flag(ctx, created.ID, result)
+	return created, nil
+}
+
+func (s *moderatedTaskService) UpdateTask(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error) {
+	var title, description string
+	if task.Title != nil {
+		title = *task.Title
+	}
+	if task.Description != nil {
+		description = *task.Description
+	}
+
+	result, err := s.moderator.Check(ctx, title, description)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.TaskService.UpdateTask(ctx, id, task)
+	if err != nil {
+		return nil, err
+	}
+
+	s.flag(ctx, updated.ID, result)
+	return updated, nil
+}
+
+// PublishDraft moderates a draft when it is published; saving a draft is not moderated
+// since nobody else can see it
+func (s *moderatedTaskService) PublishDraft(ctx context.Context, id string) (*models.Task, error) {
+	draft, err := s.TaskService.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.moderator.Check(ctx, draft.Title, draft.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	published, err := s.TaskService.PublishDraft(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.flag(ctx, published.ID, result)
+	return published, nil
+}
+
+func (s *moderatedTaskService) flag(ctx context.Context, id string, result *moderation.Result) {
+	if err := s.moderator.Flag(ctx, "task", id, result); err != nil {
+		log.Printf("Failed to queue task %s for moderation review: %v", id, err)
+	}
+}
+
+// ModerationService handles the admin review queue
+type ModerationService interface {
+	ListFlags(ctx context.Context, status models.FlagStatus, page, limit int) ([]*models.ModerationFlag, int, error)
+	ReviewFlag(ctx context.Context, id, reviewer string, review *models.FlagReview) (*models.ModerationFlag, error)
+}
+
+type moderationService struct {
+	flags repository.ModerationRepository
+	tasks repository.TaskRepository
+}
+
+// NewModerationService creates a new moderation service
+func NewModerationService(flags repository.ModerationRepository, tasks repository.TaskRepository) ModerationService {
+	return &moderationService{flags: flags, tasks: tasks}
+}
+
+func (s *moderationService) ListFlags(ctx context.Context, status models.FlagStatus, page, limit int) ([]*models.ModerationFlag, int, error) {
+	if status == "" {
+		status = models.FlagPending
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	return s.flags.ListFlags(ctx, status, page, limit)
+}
+
+// ReviewFlag records the decision; rejecting a flagged task deletes it
+func (s *moderationService) ReviewFlag(ctx context.Context, id, reviewer string, review *models.FlagReview) (*models.ModerationFlag, error) {
+	if id == "" {
+		return nil, errors.New("id is required")
+	}
+	if err := review.Validate(); err != nil {
+		return nil, err
+	}
+
+	flag, err := s.flags.GetFlag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if flag.Status != models.FlagPending {
+		return nil, errors.New("flag has already been reviewed")
+	}
+
+	if review.Status == models.FlagRejected && flag.ResourceType == "task" {
+		if err := s.tasks.Delete(ctx, flag.ResourceID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.flags.ReviewFlag(ctx, id, review.Status, reviewer)
+}
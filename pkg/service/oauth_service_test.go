@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// MockOAuthClientRepository is a mock implementation of OAuthClientRepository
+type MockOAuthClientRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthClientRepository) Create(ctx context.Context, client *models.OAuthClient) (*models.OAuthClient, error) {
+	args := m.Called(ctx, client)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OAuthClient), args.Error(1)
+}
+
+func (m *MockOAuthClientRepository) GetByClientID(ctx context.Context, clientID string) (*models.OAuthClient, error) {
+	args := m.Called(ctx, clientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OAuthClient), args.Error(1)
+}
+
+func (m *MockOAuthClientRepository) List(ctx context.Context) ([]*models.OAuthClient, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.OAuthClient), args.Error(1)
+}
+
+func (m *MockOAuthClientRepository) Revoke(ctx context.Context, clientID string) error {
+	args := m.Called(ctx, clientID)
+	return args.Error(0)
+}
+
+func TestIssueClientToken(t *testing.T) {
+	ctx := context.Background()
+	tokens := auth.NewTokenManager([]byte("test-secret"), "test-issuer")
+	revokedAt := time.Now()
+
+	client := &models.OAuthClient{
+		ClientID:   "client-1",
+		SecretHash: auth.HashClientSecret("s3cret"),
+		Roles:      []string{"viewer"},
+		Scopes:     []string{"tasks:read", "tasks:write"},
+	}
+	revoked := *client
+	revoked.RevokedAt = &revokedAt
+
+	tests := []struct {
+		name      string
+		secret    string
+		scope     string
+		stored    *models.OAuthClient
+		lookupErr error
+		wantScope string
+		wantErr   error
+	}{
+		{
+			name:    "requires an explicit scope",
+			secret:  "s3cret",
+			stored:  client,
+			wantErr: auth.ErrInvalidScope,
+		},
+		{
+			name:      "grants requested subset",
+			secret:    "s3cret",
+			scope:     "tasks:read",
+			stored:    client,
+			wantScope: "tasks:read",
+		},
+		{
+			name:    "rejects scope outside client grant",
+			secret:  "s3cret",
+			scope:   "webhooks:manage",
+			stored:  client,
+			wantErr: auth.ErrInvalidScope,
+		},
+		{
+			name:    "rejects wrong secret",
+			secret:  "wrong",
+			stored:  client,
+			wantErr: auth.ErrInvalidClient,
+		},
+		{
+			name:    "rejects revoked client",
+			secret:  "s3cret",
+			stored:  &revoked,
+			wantErr: auth.ErrInvalidClient,
+		},
+		{
+			name:      "rejects unknown client",
+			secret:    "s3cret",
+			lookupErr: errors.New("client not found"),
+			wantErr:   auth.ErrInvalidClient,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockOAuthClientRepository)
+			if tt.lookupErr != nil {
+				repo.On("GetByClientID", mock.Anything, "client-1").Return(nil, tt.lookupErr)
+			} else {
+				repo.On("GetByClientID", mock.Anything, "client-1").Return(tt.stored, nil)
+			}
+			service := NewOAuthService(repo, tokens)
+
+			token, err := service.IssueClientToken(ctx, "client-1", tt.secret, tt.scope)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Bearer", token.TokenType)
+			assert.Equal(t, tt.wantScope, token.Scope)
+
+			claims, err := tokens.ValidateToken(token.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, "client-1", claims.UserID)
+			assert.Equal(t, []string{"viewer"}, claims.Roles)
+			assert.Equal(t, tt.wantScope, claims.Scope)
+		})
+	}
+}
+
+func TestExchangeToken(t *testing.T) {
+	ctx := context.Background()
+	tokens := auth.NewTokenManager([]byte("test-secret"), "test-issuer")
+	pair, err := tokens.CreateTokenPair(context.Background(), "user-1", []string{"user"})
+	require.NoError(t, err)
+
+	client := &models.OAuthClient{
+		ClientID:           "slack",
+		SecretHash:         auth.HashClientSecret("s3cret"),
+		Roles:              []string{"user"},
+		Scopes:             []string{"tasks:read", "tasks:write"},
+		AllowTokenExchange: true,
+	}
+	notAllowed := *client
+	notAllowed.AllowTokenExchange = false
+
+	tests := []struct {
+		name      string
+		stored    *models.OAuthClient
+		secret    string
+		tokenType string
+		wantErr   error
+	}{
+		{"issues delegated token", client, "s3cret", auth.TokenTypeAccessToken, nil},
+		{"rejects wrong secret", client, "wrong", auth.TokenTypeAccessToken, auth.ErrInvalidClient},
+		{"rejects client without token exchange", &notAllowed, "s3cret", auth.TokenTypeAccessToken, auth.ErrUnauthorizedClient},
+		{"rejects unsupported token type", client, "s3cret", "urn:ietf:params:oauth:token-type:id_token", auth.ErrUnsupportedTokenType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockOAuthClientRepository)
+			repo.On("GetByClientID", mock.Anything, "slack").Return(tt.stored, nil)
+			service := NewOAuthService(repo, tokens)
+
+			token, err := service.ExchangeToken(ctx, "slack", tt.secret, pair.AccessToken, tt.tokenType, "")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			claims, err := tokens.ValidateToken(token.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims.UserID)
+			assert.Equal(t, "slack", claims.ActorID())
+			assert.Equal(t, "tasks:read tasks:write", claims.Scope)
+		})
+	}
+}
+
+func TestCreateClient(t *testing.T) {
+	repo := new(MockOAuthClientRepository)
+	service := NewOAuthService(repo, auth.NewTokenManager([]byte("test-secret"), "test-issuer"))
+
+	var stored *models.OAuthClient
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*models.OAuthClient")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*models.OAuthClient) }).
+		Return(&models.OAuthClient{ClientID: "generated"}, nil)
+
+	_, secret, err := service.CreateClient(context.Background(), &models.OAuthClientCreate{
+		Name:   "CI integration",
+		Roles:  []string{"user"},
+		Scopes: []string{"tasks:read"},
+	})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, secret)
+	assert.NotEqual(t, secret, stored.SecretHash)
+	assert.True(t, auth.VerifyClientSecret(secret, stored.SecretHash))
+}
+
+func TestCreateClient_RequiresKnownScopes(t *testing.T) {
+	repo := new(MockOAuthClientRepository)
+	service := NewOAuthService(repo, auth.NewTokenManager([]byte("test-secret"), "test-issuer"))
+
+	for _, scopes := range [][]string{nil, {"tasks:read", "tasks:delete"}} {
+		_, _, err := service.CreateClient(context.Background(), &models.OAuthClientCreate{
+			Name:   "CI integration",
+			Roles:  []string{"user"},
+			Scopes: scopes,
+		})
+		assert.Error(t, err, scopes)
+	}
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"sort"
+	"time"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// OIDCLoginStore keeps sign-ins in progress until the provider redirects back, e.g.
+// cache.RedisCache
+type OIDCLoginStore interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	// Take returns and removes the value at key, so a sign-in completes at most once
+	Take(ctx context.Context, key string, dest interface{}) error
+}
+
+// OIDCIdentityProvider signs users in at an OpenID Connect provider, e.g. auth.OIDCProvider
+type OIDCIdentityProvider interface {
+	Name() string
+	AuthCodeURL(login *auth.OIDCLogin) string
+	Exchange(ctx context.Context, login *auth.OIDCLogin, code string) (*auth.OIDCClaims, error)
+	Roles(claims *auth.OIDCClaims) []string
+}
+
+// OIDCService signs users in with OpenID Connect providers such as Google or Azure AD
+type OIDCService interface {
+	// Providers lists the names of the providers users can sign in with
+	Providers() []string
+	// BeginLogin starts a sign-in with the provider and returns the URL to send the user to,
+	// and the sign-in's state, which the caller binds to the user's browser
+	BeginLogin(ctx context.Context, provider string) (authURL, state string, err error)
+	// CompleteLogin completes the sign-in the provider redirected back from with the state
+	// and code, and issues tokens for the provider user's local user. boundState is the
+	// state bound to the browser the callback arrived in, and must be state, so nobody can
+	// complete their own sign-in in someone else's browser.
+	CompleteLogin(ctx context.Context, provider, state, boundState, code string) (*auth.TokenPair, error)
+}
+
+type oidcService struct {
+	providers map[string]OIDCIdentityProvider
+	logins    OIDCLoginStore
+	repo      repository.OIDCIdentityRepository
+	tokens    *auth.TokenManager
+	now       func() time.Time
+}
+
+// NewOIDCService creates an OpenID Connect sign-in service for the providers
+func NewOIDCService(providers []OIDCIdentityProvider, logins OIDCLoginStore, repo repository.OIDCIdentityRepository, tokens *auth.TokenManager) OIDCService {
+	byName := make(map[string]OIDCIdentityProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &oidcService{
+		providers: byName,
+		logins:    logins,
+		repo:      repo,
+		tokens:    tokens,
+		now:       time.Now,
+	}
+}
+
+func (s *oidcService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *oidcService) BeginLogin(ctx context.Context, provider string) (string, string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", "", models.ErrOIDCProviderNotFound
+	}
+	login, err := auth.NewOIDCLogin(provider)
+	if err != nil {
+		return "", "", err
+	}
+	if err := s.logins.Set(ctx, oidcLoginKey(login.State), login, models.OIDCLoginTTL); err != nil {
+		return "", "", err
+	}
+	return p.AuthCodeURL(login), login.State, nil
+}
+
+func (s *oidcService) CompleteLogin(ctx context.Context, provider, state, boundState, code string) (*auth.TokenPair, error) {
+	if state == "" || code == "" {
+		return nil, auth.ErrOIDCLogin
+	}
+	// Callbacks in a browser that didn't start the sign-in leave it for the one that did
+	if subtle.ConstantTimeCompare([]byte(state), []byte(boundState)) != 1 {
+		return nil, auth.ErrOIDCLogin
+	}
+	// Unknown, expired and already completed sign-ins all fail the same way
+	var login auth.OIDCLogin
+	if err := s.logins.Take(ctx, oidcLoginKey(state), &login); err != nil {
+		return nil, auth.ErrOIDCLogin
+	}
+	// The callback must come from the provider the sign-in was started with
+	p, ok := s.providers[login.Provider]
+	if !ok || login.Provider != provider {
+		return nil, auth.ErrOIDCLogin
+	}
+
+	claims, err := p.Exchange(ctx, &login, code)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := s.repo.Link(ctx, provider, claims.Subject, claims.Email, p.Roles(claims), s.now())
+	if err != nil {
+		return nil, err
+	}
+	return s.tokens.CreateTokenPair(ctx, identity.UserID, identity.Roles)
+}
+
+// oidcLoginKey is the store key of the sign-in with the state
+func oidcLoginKey(state string) string {
+	return "oidc:login:" + state
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// MockOIDCIdentityRepository is a mock implementation of OIDCIdentityRepository
+type MockOIDCIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockOIDCIdentityRepository) Link(ctx context.Context, provider, subject, email string, roles []string, now time.Time) (*models.OIDCIdentity, error) {
+	args := m.Called(ctx, provider, subject, email, roles, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OIDCIdentity), args.Error(1)
+}
+
+// memoryLoginStore keeps sign-ins in a map
+type memoryLoginStore map[string][]byte
+
+func (s memoryLoginStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	s[key] = data
+	return err
+}
+
+func (s memoryLoginStore) Take(ctx context.Context, key string, dest interface{}) error {
+	data, ok := s[key]
+	if !ok {
+		return errors.New("redis: nil")
+	}
+	delete(s, key)
+	return json.Unmarshal(data, dest)
+}
+
+// fakeOIDCProvider accepts the code "code-1" for any sign-in it started
+type fakeOIDCProvider struct {
+	name   string
+	logins []*auth.OIDCLogin
+}
+
+func (p *fakeOIDCProvider) Name() string { return p.name }
+
+func (p *fakeOIDCProvider) AuthCodeURL(login *auth.OIDCLogin) string {
+	p.logins = append(p.logins, login)
+	return "https://idp.example.com/authorize?state=" + login.State
+}
+
+func (p *fakeOIDCProvider) Exchange(ctx context.Context, login *auth.OIDCLogin, code string) (*auth.OIDCClaims, error) {
+	for _, started := range p.logins {
+		if code == "code-1" && *started == *login {
+			return &auth.OIDCClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "idp-user-1"},
+				Email:            "ada@example.com",
+			}, nil
+		}
+	}
+	return nil, auth.ErrOIDCLogin
+}
+
+func (p *fakeOIDCProvider) Roles(claims *auth.OIDCClaims) []string {
+	return []string{"user", "admin"}
+}
+
+func TestOIDCService_SignIn(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo := new(MockOIDCIdentityRepository)
+	google := &fakeOIDCProvider{name: "google"}
+	tokens := auth.NewTokenManager([]byte("secret"), "task-management")
+	svc := NewOIDCService([]OIDCIdentityProvider{google, &fakeOIDCProvider{name: "azure"}}, memoryLoginStore{}, repo, tokens).(*oidcService)
+	svc.now = func() time.Time { return now }
+
+	assert.Equal(t, []string{"azure", "google"}, svc.Providers())
+
+	authURL, state, err := svc.BeginLogin(ctx, "google")
+	require.NoError(t, err)
+	assert.Equal(t, google.logins[0].State, state)
+	assert.Equal(t, "https://idp.example.com/authorize?state="+state, authURL)
+
+	repo.On("Link", ctx, "google", "idp-user-1", "ada@example.com", []string{"user", "admin"}, now).
+		Return(&models.OIDCIdentity{Provider: "google", Subject: "idp-user-1", UserID: "user-7", Roles: []string{"user", "admin"}}, nil).Once()
+
+	// A callback claiming another provider doesn't complete the sign-in, and uses it up
+	_, err = svc.CompleteLogin(ctx, "azure", state, state, "code-1")
+	assert.ErrorIs(t, err, auth.ErrOIDCLogin)
+
+	_, state, err = svc.BeginLogin(ctx, "google")
+	require.NoError(t, err)
+
+	// A callback in a browser the sign-in wasn't started in fails, and leaves the sign-in
+	_, err = svc.CompleteLogin(ctx, "google", state, "", "code-1")
+	assert.ErrorIs(t, err, auth.ErrOIDCLogin)
+	_, err = svc.CompleteLogin(ctx, "google", state, "other-state", "code-1")
+	assert.ErrorIs(t, err, auth.ErrOIDCLogin)
+
+	pair, err := svc.CompleteLogin(ctx, "google", state, state, "code-1")
+	require.NoError(t, err)
+	claims, err := tokens.ValidateToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-7", claims.UserID)
+	assert.Equal(t, []string{"user", "admin"}, claims.Roles)
+
+	// Each sign-in completes once
+	_, err = svc.CompleteLogin(ctx, "google", state, state, "code-1")
+	assert.ErrorIs(t, err, auth.ErrOIDCLogin)
+	repo.AssertExpectations(t)
+}
+
+func TestOIDCService_UnknownProvider(t *testing.T) {
+	svc := NewOIDCService(nil, memoryLoginStore{}, new(MockOIDCIdentityRepository), auth.NewTokenManager([]byte("secret"), "task-management"))
+
+	_, _, err := svc.BeginLogin(context.Background(), "okta")
+	assert.ErrorIs(t, err, models.ErrOIDCProviderNotFound)
+	_, err = svc.CompleteLogin(context.Background(), "okta", "state", "state", "code")
+	assert.ErrorIs(t, err, auth.ErrOIDCLogin)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/metrics"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// OverdueTaskService lists and flags open tasks that passed their due date
+type OverdueTaskService interface {
+	// ListOverdue lists the open tasks past their due date that match filter, the longest
+	// overdue first unless sorted otherwise
+	ListOverdue(ctx context.Context, filter repository.TaskFilter) ([]*models.Task, int, error)
+	// DetectOverdue flags the tasks whose due date passed since the last run, publishes a
+	// TaskOverdue event for each and records the overdue counts as metrics
+	DetectOverdue(ctx context.Context) error
+	// Run detects overdue tasks every interval until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type overdueTaskService struct {
+	repo      repository.OverdueTaskRepository
+	tasks     TaskService
+	publisher events.Publisher
+	now       func() time.Time
+}
+
+// NewOverdueTaskService creates an overdue task service listing tasks through tasks, so
+// drafts and visibility are handled like any other task list
+func NewOverdueTaskService(repo repository.OverdueTaskRepository, tasks TaskService, publisher events.Publisher) OverdueTaskService {
+	return &overdueTaskService{
+		repo:      repo,
+		tasks:     tasks,
+		publisher: publisher,
+		now:       time.Now,
+	}
+}
+
+func (s *overdueTaskService) ListOverdue(ctx context.Context, filter repository.TaskFilter) ([]*models.Task, int, error) {
+	now := s.now().UTC()
+	filter.Status = []models.TaskStatus{models.StatusPending, models.StatusInProgress}
+	if filter.DueBefore == nil || filter.DueBefore.After(now) {
+		filter.DueBefore = &now
+	}
+	if filter.Sort == "" {
+		filter.Sort = repository.SortDueDate
+		filter.Order = "asc"
+	}
+	return s.tasks.ListTasks(ctx, filter)
+}
+
+func (s *overdueTaskService) DetectOverdue(ctx context.Context) error {
+	now := s.now()
+	tasks, err := s.repo.MarkOverdue(ctx, now)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		s.publisher.Publish(events.Event{Type: events.TaskOverdue, TaskID: task.ID, Task: task, OrgID: task.OrgID})
+	}
+
+	overdue, err := s.repo.CountOverdue(ctx, now)
+	if err != nil {
+		return err
+	}
+	metrics.RecordOverdueTasks(overdue, len(tasks))
+	return nil
+}
+
+func (s *overdueTaskService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.DetectOverdue(ctx); err != nil {
+				log.Printf("Failed to detect overdue tasks: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// MockOverdueTaskRepository is a mock implementation of OverdueTaskRepository
+type MockOverdueTaskRepository struct {
+	mock.Mock
+}
+
+func (m *MockOverdueTaskRepository) MarkOverdue(ctx context.Context, now time.Time) ([]*models.Task, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
+func (m *MockOverdueTaskRepository) CountOverdue(ctx context.Context, now time.Time) (int, error) {
+	args := m.Called(ctx, now)
+	return args.Int(0), args.Error(1)
+}
+
+func TestDetectOverdue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo := new(MockOverdueTaskRepository)
+	publisher := &recordingPublisher{}
+	svc := NewOverdueTaskService(repo, NewTaskService(new(MockTaskRepository)), publisher).(*overdueTaskService)
+	svc.now = func() time.Time { return now }
+
+	flagged := []*models.Task{
+		{ID: "task-1", Status: models.StatusPending, DueDate: now.Add(-time.Hour)},
+		{ID: "task-2", Status: models.StatusInProgress, DueDate: now.Add(-time.Minute)},
+	}
+	repo.On("MarkOverdue", ctx, now).Return(flagged, nil).Once()
+	repo.On("CountOverdue", ctx, now).Return(7, nil).Once()
+
+	require.NoError(t, svc.DetectOverdue(ctx))
+	require.Len(t, publisher.events, 2)
+	for i, event := range publisher.events {
+		assert.Equal(t, events.TaskOverdue, event.Type)
+		assert.Equal(t, flagged[i].ID, event.TaskID)
+		assert.Equal(t, flagged[i], event.Task)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestListOverdue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	tasks := new(MockTaskRepository)
+	svc := NewOverdueTaskService(new(MockOverdueTaskRepository), NewTaskService(tasks), &recordingPublisher{}).(*overdueTaskService)
+	svc.now = func() time.Time { return now }
+
+	overdue := []*models.Task{{ID: "task-1", Status: models.StatusPending, DueDate: now.Add(-time.Hour)}}
+	tasks.On("List", ctx, mock.MatchedBy(func(filter repository.TaskFilter) bool {
+		return assert.ObjectsAreEqual([]models.TaskStatus{models.StatusPending, models.StatusInProgress}, filter.Status) &&
+			filter.DueBefore.Equal(now) && filter.Sort == repository.SortDueDate && filter.Order == "asc" &&
+			filter.AssigneeID == "alice"
+	})).Return(overdue, 1, nil).Once()
+
+	// Statuses are always the open ones and the due bound never reaches past now
+	later := now.Add(time.Hour)
+	result, total, err := svc.ListOverdue(ctx, repository.TaskFilter{
+		Status:     []models.TaskStatus{models.StatusCompleted},
+		AssigneeID: "alice",
+		DueBefore:  &later,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, overdue, result)
+	assert.Equal(t, 1, total)
+	tasks.AssertExpectations(t)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/notify"
+	"sample/task-management-system/pkg/repository"
+)
+
+// PreferencesService manages users' notification preferences
+type PreferencesService interface {
+	GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, userID string, input *models.PreferencesUpdate) (*models.NotificationPreferences, error)
+	// Unsubscribe turns off the digest of the user an unsubscribe link was sent to
+	Unsubscribe(ctx context.Context, token string) (*models.NotificationPreferences, error)
+}
+
+type preferencesService struct {
+	repo   repository.PreferencesRepository
+	signer *notify.UnsubscribeSigner
+}
+
+// NewPreferencesService creates a new preferences service
+func NewPreferencesService(repo repository.PreferencesRepository, signer *notify.UnsubscribeSigner) PreferencesService {
+	return &preferencesService{repo: repo, signer: signer}
+}
+
+func (s *preferencesService) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	return s.repo.Get(ctx, userID)
+}
+
+func (s *preferencesService) UpdatePreferences(ctx context.Context, userID string, input *models.PreferencesUpdate) (*models.NotificationPreferences, error) {
+	preferences, err := s.repo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := input.Apply(preferences); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Save(ctx, preferences)
+}
+
+func (s *preferencesService) Unsubscribe(ctx context.Context, token string) (*models.NotificationPreferences, error) {
+	userID, err := s.signer.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	off := models.DigestOff
+	return s.UpdatePreferences(ctx, userID, &models.PreferencesUpdate{Digest: &off})
+}
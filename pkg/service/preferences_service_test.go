@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+func TestUpdatePreferences_LocaleAndPageSize(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockPreferencesRepository)
+	svc := NewPreferencesService(repo, nil)
+
+	repo.On("Get", ctx, "user-1").Return(models.DefaultNotificationPreferences("user-1"), nil)
+	saved := &models.NotificationPreferences{UserID: "user-1", Locale: "de-CH", PageSize: 25}
+	repo.On("Save", ctx, mock.MatchedBy(func(p *models.NotificationPreferences) bool {
+		// Fields the update leaves out keep their value
+		return p.Locale == "de-CH" && p.PageSize == 25 && p.Timezone == "UTC"
+	})).Return(saved, nil).Once()
+
+	locale, pageSize := "de-CH", 25
+	preferences, err := svc.UpdatePreferences(ctx, "user-1", &models.PreferencesUpdate{Locale: &locale, PageSize: &pageSize})
+	require.NoError(t, err)
+	assert.Equal(t, saved, preferences)
+	repo.AssertExpectations(t)
+}
+
+func TestUpdatePreferences_Validation(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockPreferencesRepository)
+	svc := NewPreferencesService(repo, nil)
+	repo.On("Get", ctx, "user-1").Return(models.DefaultNotificationPreferences("user-1"), nil)
+
+	locale, tooLarge, zero := "not a locale", models.MaxPageSize+1, 0
+	tests := []struct {
+		name  string
+		input models.PreferencesUpdate
+		want  string
+	}{
+		{"invalid locale", models.PreferencesUpdate{Locale: &locale}, `invalid locale "not a locale", expected a language tag such as en or de-CH`},
+		{"page size too large", models.PreferencesUpdate{PageSize: &tooLarge}, "page_size must be between 1 and 100"},
+		{"empty pages", models.PreferencesUpdate{PageSize: &zero}, "page_size must be between 1 and 100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.UpdatePreferences(ctx, "user-1", &tt.input)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+	repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// progressCacheTTL is how long daily counts are served from cache, so charts lag task
+// changes by at most this long
+const progressCacheTTL = 5 * time.Minute
+
+// ChartCache caches chart data, e.g. cache.RedisCache
+type ChartCache interface {
+	Get(ctx context.Context, key string, dest interface{}) error
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
+// ProgressService serves burnup and burndown charts of projects
+type ProgressService interface {
+	// Burnup returns the project's completed tasks and total scope per day from from to
+	// to, inclusive
+	Burnup(ctx context.Context, projectKey string, from, to time.Time) (*models.BurnupChart, error)
+	// Burndown returns the project's open tasks per day from from to to, inclusive
+	Burndown(ctx context.Context, projectKey string, from, to time.Time) (*models.BurndownChart, error)
+	// Record keeps the status history the charts are computed from; subscribe it to
+	// task events
+	Record(ctx context.Context, event events.Event) error
+}
+
+type progressService struct {
+	repo  repository.ProgressRepository
+	cache ChartCache
+}
+
+// NewProgressService creates a new progress service
+func NewProgressService(repo repository.ProgressRepository, cache ChartCache) ProgressService {
+	return &progressService{repo: repo, cache: cache}
+}
+
+func (s *progressService) Burnup(ctx context.Context, projectKey string, from, to time.Time) (*models.BurnupChart, error) {
+	projectKey, counts, err := s.dailyCounts(ctx, projectKey, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := &models.BurnupChart{
+		ProjectKey: projectKey,
+		From:       from.Format(models.ChartDateLayout),
+		To:         to.Format(models.ChartDateLayout),
+		Points:     make([]*models.BurnupPoint, 0, len(counts)),
+	}
+	for _, count := range counts {
+		chart.Points = append(chart.Points, &models.BurnupPoint{
+			Date:      count.Day.Format(models.ChartDateLayout),
+			Completed: count.Completed,
+			Scope:     count.Open + count.Completed,
+		})
+	}
+	return chart, nil
+}
+
+func (s *progressService) Burndown(ctx context.Context, projectKey string, from, to time.Time) (*models.BurndownChart, error) {
+	projectKey, counts, err := s.dailyCounts(ctx, projectKey, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := &models.BurndownChart{
+		ProjectKey: projectKey,
+		From:       from.Format(models.ChartDateLayout),
+		To:         to.Format(models.ChartDateLayout),
+		Points:     make([]*models.BurndownPoint, 0, len(counts)),
+	}
+	for _, count := range counts {
+		chart.Points = append(chart.Points, &models.BurndownPoint{
+			Date:      count.Day.Format(models.ChartDateLayout),
+			Remaining: count.Open,
+		})
+	}
+	return chart, nil
+}
+
+// dailyCounts returns the normalized project key and a count for every day in the range,
+// from the cache when possible
+func (s *progressService) dailyCounts(ctx context.Context, projectKey string, from, to time.Time) (string, []*models.ProgressCount, error) {
+	projectKey, err := models.NormalizeProjectKey(projectKey)
+	if err != nil {
+		return "", nil, err
+	}
+	from = truncateToDay(from)
+	to = truncateToDay(to)
+	if to.Before(from) {
+		return "", nil, errors.New("from must not be after to")
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > models.MaxChartDays {
+		return "", nil, fmt.Errorf("charts span at most %d days", models.MaxChartDays)
+	}
+
+	key := fmt.Sprintf("progress:%s:%s:%s:%s", progressCacheTenant(ctx), projectKey,
+		from.Format(models.ChartDateLayout), to.Format(models.ChartDateLayout))
+	var cached []*models.ProgressCount
+	if err := s.cache.Get(ctx, key, &cached); err == nil {
+		return projectKey, cached, nil
+	}
+
+	stored, err := s.repo.DailyCounts(ctx, projectKey, from, to)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Days without any tasks yet are left out by the repository
+	var counts []*models.ProgressCount
+	byDay := make(map[string]*models.ProgressCount, len(stored))
+	for _, count := range stored {
+		byDay[count.Day.Format(models.ChartDateLayout)] = count
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		count, ok := byDay[day.Format(models.ChartDateLayout)]
+		if !ok {
+			count = &models.ProgressCount{}
+		}
+		count.Day = day
+		counts = append(counts, count)
+	}
+
+	if err := s.cache.Set(ctx, key, counts, progressCacheTTL); err != nil {
+		log.Printf("Failed to cache progress of project %s: %v", projectKey, err)
+	}
+	return projectKey, counts, nil
+}
+
+func (s *progressService) Record(ctx context.Context, event events.Event) error {
+	switch event.Type {
+	case events.TaskDeleted:
+		return s.repo.RecordDeleted(ctx, event.TaskID, event.OccurredAt)
+	case events.TaskCreated, events.TaskUpdated:
+		if event.Task == nil || event.Task.Status == models.StatusDraft {
+			return nil
+		}
+		return s.repo.RecordStatus(ctx, event.Task, event.OccurredAt)
+	default:
+		return nil
+	}
+}
+
+// progressCacheTenant identifies the tenant of ctx in cache keys, since each tenant sees
+// only its own history; unscoped contexts see every tenant's
+func progressCacheTenant(ctx context.Context) string {
+	if orgID, ok := tenant.FromContext(ctx); ok {
+		return models.TaskCacheTenant(orgID)
+	}
+	return "all"
+}
+
+// truncateToDay returns midnight UTC of the day t falls on in UTC
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
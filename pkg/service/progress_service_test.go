@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// MockProgressRepository is a mock implementation of ProgressRepository
+type MockProgressRepository struct {
+	mock.Mock
+}
+
+func (m *MockProgressRepository) RecordStatus(ctx context.Context, task *models.Task, at time.Time) error {
+	args := m.Called(ctx, task, at)
+	return args.Error(0)
+}
+
+func (m *MockProgressRepository) RecordDeleted(ctx context.Context, taskID string, at time.Time) error {
+	args := m.Called(ctx, taskID, at)
+	return args.Error(0)
+}
+
+func (m *MockProgressRepository) DailyCounts(ctx context.Context, projectKey string, from, to time.Time) ([]*models.ProgressCount, error) {
+	args := m.Called(ctx, projectKey, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ProgressCount), args.Error(1)
+}
+
+// MockChartCache is a mock implementation of ChartCache
+type MockChartCache struct {
+	mock.Mock
+}
+
+func (m *MockChartCache) Get(ctx context.Context, key string, dest interface{}) error {
+	args := m.Called(ctx, key, dest)
+	return args.Error(0)
+}
+
+func (m *MockChartCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	args := m.Called(ctx, key, value, expiration)
+	return args.Error(0)
+}
+
+func TestBurnupAndBurndown(t *testing.T) {
+	ctx := tenant.WithID(context.Background(), "acme")
+	repo := new(MockProgressRepository)
+	cache := new(MockChartCache)
+	svc := NewProgressService(repo, cache)
+
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	wednesday := monday.AddDate(0, 0, 2)
+	key := "progress:org-acme:PROJ:2024-03-04:2024-03-06"
+
+	cache.On("Get", ctx, key, mock.Anything).Return(errors.New("redis: nil")).Once()
+	// The project's first task was created on Tuesday
+	repo.On("DailyCounts", ctx, "PROJ", monday, wednesday).Return([]*models.ProgressCount{
+		{Day: monday.AddDate(0, 0, 1), Open: 3},
+		{Day: wednesday, Open: 2, Completed: 2},
+	}, nil).Once()
+	cache.On("Set", ctx, key, mock.Anything, progressCacheTTL).Return(nil).Once()
+
+	burnup, err := svc.Burnup(ctx, "proj", monday.Add(15*time.Hour), wednesday)
+	require.NoError(t, err)
+	assert.Equal(t, "PROJ", burnup.ProjectKey)
+	assert.Equal(t, []*models.BurnupPoint{
+		{Date: "2024-03-04", Completed: 0, Scope: 0},
+		{Date: "2024-03-05", Completed: 0, Scope: 3},
+		{Date: "2024-03-06", Completed: 2, Scope: 4},
+	}, burnup.Points)
+
+	// The second chart of the same range is served from the cache
+	cache.On("Get", ctx, key, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(2).(*[]*models.ProgressCount) = []*models.ProgressCount{{Day: monday, Open: 1}}
+	}).Once()
+
+	burndown, err := svc.Burndown(ctx, "PROJ", monday, wednesday)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.BurndownPoint{{Date: "2024-03-04", Remaining: 1}}, burndown.Points)
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func TestBurnup_InvalidRange(t *testing.T) {
+	svc := NewProgressService(new(MockProgressRepository), new(MockChartCache))
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	_, err := svc.Burnup(context.Background(), "PROJ", monday, monday.AddDate(0, 0, -1))
+	assert.Error(t, err)
+	_, err = svc.Burnup(context.Background(), "PROJ", monday, monday.AddDate(0, 0, models.MaxChartDays))
+	assert.Error(t, err)
+}
+
+func TestProgressRecord(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockProgressRepository)
+	svc := NewProgressService(repo, new(MockChartCache))
+	at := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	task := &models.Task{ID: "task-1", ProjectKey: "PROJ", Status: models.StatusCompleted}
+	repo.On("RecordStatus", ctx, task, at).Return(nil).Once()
+	repo.On("RecordDeleted", ctx, "task-1", at).Return(nil).Once()
+
+	require.NoError(t, svc.Record(ctx, events.Event{Type: events.TaskUpdated, TaskID: "task-1", Task: task, OccurredAt: at}))
+	require.NoError(t, svc.Record(ctx, events.Event{Type: events.TaskDeleted, TaskID: "task-1", OccurredAt: at}))
+	// Drafts and stale flags don't change the charts
+	draft := &models.Task{ID: "task-2", ProjectKey: "PROJ", Status: models.StatusDraft}
+	require.NoError(t, svc.Record(ctx, events.Event{Type: events.TaskCreated, TaskID: "task-2", Task: draft, OccurredAt: at}))
+	require.NoError(t, svc.Record(ctx, events.Event{Type: events.TaskStale, TaskID: "task-1", Task: task, OccurredAt: at}))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/prefetch"
+	"sample/task-management-system/pkg/repository"
+)
+
+// RecentViewStore keeps each user's latest task views, e.g. cache.RecentViews
+type RecentViewStore interface {
+	Size() int
+	Record(ctx context.Context, userID, taskID string, at time.Time) error
+	List(ctx context.Context, userID string, limit int) ([]models.TaskView, error)
+	Seed(ctx context.Context, userID string, views []models.TaskView) error
+	PopDirty(ctx context.Context, count int) ([]string, error)
+	MarkDirty(ctx context.Context, userIDs ...string) error
+}
+
+// RecentTaskService tracks the tasks each user viewed last
+type RecentTaskService interface {
+	RecordView(ctx context.Context, userID, taskID string) error
+	// RecentTasks returns up to limit of the user's recently viewed tasks, most recent first
+	RecentTasks(ctx context.Context, userID string, limit int) ([]*models.RecentTask, error)
+	// Flush copies changed views from the store to the database
+	Flush(ctx context.Context) error
+	// Run flushes every interval until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+const (
+	// MaxRecentTasks is the number of recently viewed tasks kept per user
+	MaxRecentTasks = 50
+	// flushBatchSize is the number of users flushed per store round trip
+	flushBatchSize = 100
+)
+
+type recentTaskService struct {
+	store RecentViewStore
+	views repository.ViewRepository
+	tasks repository.TaskRepository
+	now   func() time.Time
+}
+
+// NewRecentTaskService creates a new recent task service
+func NewRecentTaskService(store RecentViewStore, views repository.ViewRepository, tasks repository.TaskRepository) RecentTaskService {
+	return &recentTaskService{store: store, views: views, tasks: tasks, now: time.Now}
+}
+
+func (s *recentTaskService) RecordView(ctx context.Context, userID, taskID string) error {
+	return s.store.Record(ctx, userID, taskID, s.now())
+}
+
+func (s *recentTaskService) RecentTasks(ctx context.Context, userID string, limit int) ([]*models.RecentTask, error) {
+	if limit < 1 || limit > s.store.Size() {
+		limit = s.store.Size()
+	}
+
+	views, err := s.store.List(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	// An empty store may have lost its data, e.g. after a Redis flush
+	if len(views) == 0 {
+		if views, err = s.views.ListViews(ctx, userID, s.store.Size()); err != nil {
+			return nil, err
+		}
+		if err := s.store.Seed(ctx, userID, views); err != nil {
+			log.Printf("Failed to restore recent views of user %s: %v", userID, err)
+		}
+		if len(views) > limit {
+			views = views[:limit]
+		}
+	}
+	if len(views) == 0 {
+		return []*models.RecentTask{}, nil
+	}
+
+	ids := make([]string, len(views))
+	for i, view := range views {
+		ids[i] = view.TaskID
+	}
+	tasks, _, err := s.tasks.List(ctx, repository.TaskFilter{IDs: ids, Page: 1, Limit: len(ids)})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	// Deleted tasks drop out of the list
+	recent := make([]*models.RecentTask, 0, len(views))
+	for _, view := range views {
+		if task, ok := byID[view.TaskID]; ok {
+			recent = append(recent, &models.RecentTask{Task: task, ViewedAt: view.ViewedAt})
+		}
+	}
+	return recent, nil
+}
+
+func (s *recentTaskService) Flush(ctx context.Context) error {
+	for {
+		users, err := s.store.PopDirty(ctx, flushBatchSize)
+		if err != nil || len(users) == 0 {
+			return err
+		}
+
+		for i, userID := range users {
+			views, err := s.store.List(ctx, userID, s.store.Size())
+			if err == nil {
+				err = s.views.SaveViews(ctx, userID, views, s.store.Size())
+			}
+			if err != nil {
+				// Retry this and the remaining users on the next flush
+				if markErr := s.store.MarkDirty(ctx, users[i:]...); markErr != nil {
+					log.Printf("Failed to requeue recent views for flushing: %v", markErr)
+				}
+				return err
+			}
+		}
+	}
+}
+
+func (s *recentTaskService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil {
+				log.Printf("Failed to flush recent task views: %v", err)
+			}
+		}
+	}
+}
+
+// viewRecordingTaskService records a view whenever a user reads a single task
+type viewRecordingTaskService struct {
+	TaskService
+	recent RecentTaskService
+}
+
+// NewViewRecordingTaskService wraps a TaskService so single-task reads by an
+// authenticated user are recorded as views
+func NewViewRecordingTaskService(next TaskService, recent RecentTaskService) TaskService {
+	return &viewRecordingTaskService{
+		TaskService: next,
+		recent:      recent,
+	}
+}
+
+func (s *viewRecordingTaskService) GetTask(ctx context.Context, id string) (*models.Task, error) {
+	task, err := s.TaskService.GetTask(ctx, id)
+	s.record(ctx, task, err)
+	return task, err
+}
+
+func (s *viewRecordingTaskService) GetTaskByKey(ctx context.Context, key string) (*models.Task, error) {
+	task, err := s.TaskService.GetTaskByKey(ctx, key)
+	s.record(ctx, task, err)
+	return task, err
+}
+
+func (s *viewRecordingTaskService) GetTaskBySlug(ctx context.Context, projectKey, slug string) (*models.Task, error) {
+	task, err := s.TaskService.GetTaskBySlug(ctx, projectKey, slug)
+	s.record(ctx, task, err)
+	return task, err
+}
+
+// record notes the view; failing to record never fails the read. Reads made to warm the
+// cache aren't views.
+func (s *viewRecordingTaskService) record(ctx context.Context, task *models.Task, err error) {
+	if err != nil || prefetch.FromContext(ctx) {
+		return
+	}
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return
+	}
+	if err := s.recent.RecordView(ctx, user.ID, task.ID); err != nil {
+		log.Printf("Failed to record view of task %s: %v", task.ID, err)
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/prefetch"
+	"sample/task-management-system/pkg/repository"
+)
+
+// MockRecentViewStore is a mock implementation of RecentViewStore
+type MockRecentViewStore struct {
+	mock.Mock
+}
+
+func (m *MockRecentViewStore) Size() int {
+	return 50
+}
+
+func (m *MockRecentViewStore) Record(ctx context.Context, userID, taskID string, at time.Time) error {
+	args := m.Called(ctx, userID, taskID, at)
+	return args.Error(0)
+}
+
+func (m *MockRecentViewStore) List(ctx context.Context, userID string, limit int) ([]models.TaskView, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TaskView), args.Error(1)
+}
+
+func (m *MockRecentViewStore) Seed(ctx context.Context, userID string, views []models.TaskView) error {
+	args := m.Called(ctx, userID, views)
+	return args.Error(0)
+}
+
+func (m *MockRecentViewStore) PopDirty(ctx context.Context, count int) ([]string, error) {
+	args := m.Called(ctx, count)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRecentViewStore) MarkDirty(ctx context.Context, userIDs ...string) error {
+	args := m.Called(ctx, userIDs)
+	return args.Error(0)
+}
+
+// MockViewRepository is a mock implementation of ViewRepository
+type MockViewRepository struct {
+	mock.Mock
+}
+
+func (m *MockViewRepository) SaveViews(ctx context.Context, userID string, views []models.TaskView, keep int) error {
+	args := m.Called(ctx, userID, views, keep)
+	return args.Error(0)
+}
+
+func (m *MockViewRepository) ListViews(ctx context.Context, userID string, limit int) ([]models.TaskView, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TaskView), args.Error(1)
+}
+
+func TestRecentTasks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	cached := []models.TaskView{
+		{TaskID: "task-2", ViewedAt: now},
+		{TaskID: "task-deleted", ViewedAt: now.Add(-time.Minute)},
+		{TaskID: "task-1", ViewedAt: now.Add(-2 * time.Minute)},
+	}
+	saved := []models.TaskView{
+		{TaskID: "task-1", ViewedAt: now.Add(-time.Hour)},
+	}
+
+	tests := []struct {
+		name      string
+		cached    []models.TaskView
+		saved     []models.TaskView
+		wantTasks []string
+	}{
+		{
+			name:      "ordered by view and skips deleted tasks",
+			cached:    cached,
+			wantTasks: []string{"task-2", "task-1"},
+		},
+		{
+			name:      "restores views from the database",
+			cached:    []models.TaskView{},
+			saved:     saved,
+			wantTasks: []string{"task-1"},
+		},
+		{
+			name:      "no views",
+			cached:    []models.TaskView{},
+			saved:     []models.TaskView{},
+			wantTasks: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := new(MockRecentViewStore)
+			views := new(MockViewRepository)
+			tasks := new(MockTaskRepository)
+			svc := NewRecentTaskService(store, views, tasks)
+
+			store.On("List", ctx, "user-1", 10).Return(tt.cached, nil)
+			views.On("ListViews", ctx, "user-1", 50).Return(tt.saved, nil).Maybe()
+			store.On("Seed", ctx, "user-1", tt.saved).Return(nil).Maybe()
+			tasks.On("List", ctx, mock.MatchedBy(func(f repository.TaskFilter) bool {
+				return len(f.IDs) > 0
+			})).Return([]*models.Task{{ID: "task-1"}, {ID: "task-2"}}, 2, nil).Maybe()
+
+			recent, err := svc.RecentTasks(ctx, "user-1", 10)
+
+			require.NoError(t, err)
+			ids := make([]string, len(recent))
+			for i, r := range recent {
+				ids[i] = r.Task.ID
+			}
+			assert.Equal(t, tt.wantTasks, ids)
+		})
+	}
+}
+
+func TestRecentTaskService_Flush(t *testing.T) {
+	ctx := context.Background()
+	views := []models.TaskView{{TaskID: "task-1", ViewedAt: time.Now()}}
+
+	t.Run("saves dirty users", func(t *testing.T) {
+		store := new(MockRecentViewStore)
+		repo := new(MockViewRepository)
+		svc := NewRecentTaskService(store, repo, new(MockTaskRepository))
+
+		store.On("PopDirty", ctx, flushBatchSize).Return([]string{"alice", "bob"}, nil).Once()
+		store.On("PopDirty", ctx, flushBatchSize).Return([]string{}, nil).Once()
+		store.On("List", ctx, mock.Anything, 50).Return(views, nil)
+		repo.On("SaveViews", ctx, mock.Anything, views, 50).Return(nil)
+
+		require.NoError(t, svc.Flush(ctx))
+		repo.AssertNumberOfCalls(t, "SaveViews", 2)
+	})
+
+	t.Run("requeues unsaved users on error", func(t *testing.T) {
+		store := new(MockRecentViewStore)
+		repo := new(MockViewRepository)
+		svc := NewRecentTaskService(store, repo, new(MockTaskRepository))
+
+		store.On("PopDirty", ctx, flushBatchSize).Return([]string{"alice", "bob"}, nil).Once()
+		store.On("List", ctx, mock.Anything, 50).Return(views, nil)
+		repo.On("SaveViews", ctx, "alice", views, 50).Return(errors.New("db down"))
+		store.On("MarkDirty", ctx, []string{"alice", "bob"}).Return(nil)
+
+		assert.Error(t, svc.Flush(ctx))
+		store.AssertExpectations(t)
+	})
+}
+
+func TestViewRecordingTaskService_GetTask(t *testing.T) {
+	userCtx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: "user-1"})
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		recordErr  error
+		wantRecord bool
+	}{
+		{name: "records view of the caller", ctx: userCtx, wantRecord: true},
+		{name: "record failure does not fail the read", ctx: userCtx, recordErr: errors.New("redis down"), wantRecord: true},
+		{name: "anonymous reads are not recorded", ctx: context.Background()},
+		{name: "prefetches are not recorded", ctx: prefetch.With(userCtx)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTaskRepository)
+			store := new(MockRecentViewStore)
+			recent := NewRecentTaskService(store, new(MockViewRepository), repo)
+			svc := NewViewRecordingTaskService(NewTaskService(repo), recent)
+
+			repo.On("GetByID", tt.ctx, "task-1").Return(&models.Task{ID: "task-1"}, nil)
+			store.On("Record", tt.ctx, "user-1", "task-1", mock.Anything).Return(tt.recordErr).Maybe()
+
+			task, err := svc.GetTask(tt.ctx, "task-1")
+
+			require.NoError(t, err)
+			assert.Equal(t, "task-1", task.ID)
+			if tt.wantRecord {
+				store.AssertCalled(t, "Record", tt.ctx, "user-1", "task-1", mock.Anything)
+			} else {
+				store.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// RelationService manages typed links between tasks, such as duplicates
+type RelationService interface {
+	AddRelation(ctx context.Context, taskID string, relation *models.TaskRelationCreate) error
+	// RemoveRelation removes a relation as listed for the task, so the inverse types
+	// duplicated_by and causes may be used as well
+	RemoveRelation(ctx context.Context, taskID string, relationType models.RelationType, relatedID string) error
+	ListRelations(ctx context.Context, taskID string) ([]*models.TaskRelation, error)
+}
+
+var (
+	// ErrRelationToSelf is returned when relating a task to itself
+	ErrRelationToSelf = errors.New("a task cannot be related to itself")
+
+	// ErrRelationConflict is returned when the related task already holds the same
+	// relation towards the task, e.g. both being a duplicate of the other
+	ErrRelationConflict = errors.New("the related task already has this relation to the task")
+)
+
+type relationService struct {
+	relations repository.RelationRepository
+	tasks     repository.TaskRepository
+}
+
+// NewRelationService creates a new task relation service
+func NewRelationService(relations repository.RelationRepository, tasks repository.TaskRepository) RelationService {
+	return &relationService{relations: relations, tasks: tasks}
+}
+
+func (s *relationService) AddRelation(ctx context.Context, taskID string, relation *models.TaskRelationCreate) error {
+	if err := relation.Validate(); err != nil {
+		return err
+	}
+	if taskID == relation.TaskID {
+		return ErrRelationToSelf
+	}
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return err
+	}
+	related, err := s.tasks.GetByID(ctx, relation.TaskID)
+	if err != nil || related.Status == models.StatusDraft {
+		return errors.New("related task not found")
+	}
+
+	if relation.Type != models.RelationRelatesTo {
+		existing, err := s.relations.List(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		for _, other := range existing {
+			if other.TaskID == relation.TaskID && other.Type == relation.Type.Inverse() {
+				return ErrRelationConflict
+			}
+		}
+	}
+
+	from, to, relationType := storedRelation(taskID, relation.TaskID, relation.Type)
+	return s.relations.Add(ctx, from, to, relationType)
+}
+
+func (s *relationService) RemoveRelation(ctx context.Context, taskID string, relationType models.RelationType, relatedID string) error {
+	if taskID == "" || relatedID == "" {
+		return errors.New("task id and related task id are required")
+	}
+	if !models.IsValidRelationType(relationType) && !models.IsValidRelationType(relationType.Inverse()) {
+		return errors.New("relation not found")
+	}
+
+	from, to, relationType := storedRelation(taskID, relatedID, relationType)
+	return s.relations.Remove(ctx, from, to, relationType)
+}
+
+func (s *relationService) ListRelations(ctx context.Context, taskID string) ([]*models.TaskRelation, error) {
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.relations.List(ctx, taskID)
+}
+
+// storedRelation is how a relation from taskID to relatedID is stored: inverse types are
+// stored from the related task, and relates_to from the task with the smaller ID
+func storedRelation(taskID, relatedID string, relationType models.RelationType) (string, string, models.RelationType) {
+	switch {
+	case !models.IsValidRelationType(relationType):
+		return relatedID, taskID, relationType.Inverse()
+	case relationType == models.RelationRelatesTo && relatedID < taskID:
+		return relatedID, taskID, relationType
+	}
+	return taskID, relatedID, relationType
+}
+
+// relatedTaskService sets Task.Relations on single tasks
+type relatedTaskService struct {
+	TaskService
+	relations repository.RelationRepository
+}
+
+// NewRelatedTaskService wraps a TaskService so tasks fetched one at a time include
+// their relations
+func NewRelatedTaskService(next TaskService, relations repository.RelationRepository) TaskService {
+	return &relatedTaskService{
+		TaskService: next,
+		relations:   relations,
+	}
+}
+
+func (s *relatedTaskService) GetTask(ctx context.Context, id string) (*models.Task, error) {
+	task, err := s.TaskService.GetTask(ctx, id)
+	return s.relate(ctx, task, err)
+}
+
+func (s *relatedTaskService) GetTaskByKey(ctx context.Context, key string) (*models.Task, error) {
+	task, err := s.TaskService.GetTaskByKey(ctx, key)
+	return s.relate(ctx, task, err)
+}
+
+func (s *relatedTaskService) GetTaskBySlug(ctx context.Context, projectKey, slug string) (*models.Task, error) {
+	task, err := s.TaskService.GetTaskBySlug(ctx, projectKey, slug)
+	return s.relate(ctx, task, err)
+}
+
+// relate sets the relations of the result of a single-task call
+func (s *relatedTaskService) relate(ctx context.Context, task *models.Task, err error) (*models.Task, error) {
+	if err != nil {
+		return nil, err
+	}
+	relations, err := s.relations.List(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	task.Relations = relations
+	return task, nil
+}
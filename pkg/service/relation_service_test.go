@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockRelationRepository is a mock implementation of RelationRepository
+type MockRelationRepository struct {
+	mock.Mock
+}
+
+func (m *MockRelationRepository) Add(ctx context.Context, taskID, relatedID string, relationType models.RelationType) error {
+	args := m.Called(ctx, taskID, relatedID, relationType)
+	return args.Error(0)
+}
+
+func (m *MockRelationRepository) Remove(ctx context.Context, taskID, relatedID string, relationType models.RelationType) error {
+	args := m.Called(ctx, taskID, relatedID, relationType)
+	return args.Error(0)
+}
+
+func (m *MockRelationRepository) List(ctx context.Context, taskID string) ([]*models.TaskRelation, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]*models.TaskRelation), args.Error(1)
+}
+
+// getOnlyTaskService returns a task with the requested ID; other methods are not used
+type getOnlyTaskService struct {
+	TaskService
+}
+
+func (s *getOnlyTaskService) GetTask(ctx context.Context, id string) (*models.Task, error) {
+	return &models.Task{ID: id}, nil
+}
+
+func TestAddRelation(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		taskID    string
+		relation  models.TaskRelationCreate
+		existing  []*models.TaskRelation // relations task-b already has
+		related   *models.Task
+		wantErr   error
+		wantAdded []interface{} // stored task ID, related ID and type
+	}{
+		{
+			name:      "duplicate of",
+			taskID:    "task-b",
+			relation:  models.TaskRelationCreate{Type: models.RelationDuplicateOf, TaskID: "task-a"},
+			existing:  []*models.TaskRelation{{Type: models.RelationCausedBy, TaskID: "task-a"}},
+			wantAdded: []interface{}{"task-b", "task-a", models.RelationDuplicateOf},
+		},
+		{
+			name:      "relates to is stored from the smaller ID",
+			taskID:    "task-b",
+			relation:  models.TaskRelationCreate{Type: models.RelationRelatesTo, TaskID: "task-a"},
+			wantAdded: []interface{}{"task-a", "task-b", models.RelationRelatesTo},
+		},
+		{
+			name:     "each a duplicate of the other",
+			taskID:   "task-b",
+			relation: models.TaskRelationCreate{Type: models.RelationDuplicateOf, TaskID: "task-a"},
+			existing: []*models.TaskRelation{{Type: models.RelationDuplicatedBy, TaskID: "task-a"}},
+			wantErr:  ErrRelationConflict,
+		},
+		{
+			name:     "task cannot relate to itself",
+			taskID:   "task-b",
+			relation: models.TaskRelationCreate{Type: models.RelationCausedBy, TaskID: "task-b"},
+			wantErr:  ErrRelationToSelf,
+		},
+		{
+			name:     "inverse types cannot be added",
+			taskID:   "task-b",
+			relation: models.TaskRelationCreate{Type: models.RelationDuplicatedBy, TaskID: "task-a"},
+		},
+		{
+			name:     "related draft",
+			taskID:   "task-b",
+			relation: models.TaskRelationCreate{Type: models.RelationRelatesTo, TaskID: "task-a"},
+			related:  &models.Task{ID: "task-a", Status: models.StatusDraft},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relations := new(MockRelationRepository)
+			tasks := new(MockTaskRepository)
+			svc := NewRelationService(relations, tasks)
+
+			related := tt.related
+			if related == nil {
+				related = &models.Task{ID: tt.relation.TaskID, Status: models.StatusPending}
+			}
+			tasks.On("GetByID", ctx, tt.taskID).Return(&models.Task{ID: tt.taskID}, nil).Maybe()
+			tasks.On("GetByID", ctx, tt.relation.TaskID).Return(related, nil).Maybe()
+			relations.On("List", ctx, tt.taskID).Return(tt.existing, nil).Maybe()
+			relations.On("Add", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			err := svc.AddRelation(ctx, tt.taskID, &tt.relation)
+			if tt.wantAdded == nil {
+				assert.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				relations.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			relations.AssertCalled(t, "Add", append([]interface{}{ctx}, tt.wantAdded...)...)
+		})
+	}
+}
+
+func TestRemoveRelation_InverseType(t *testing.T) {
+	ctx := context.Background()
+	relations := new(MockRelationRepository)
+	svc := NewRelationService(relations, new(MockTaskRepository))
+
+	relations.On("Remove", ctx, "task-b", "task-a", models.RelationDuplicateOf).Return(nil).Once()
+
+	assert.NoError(t, svc.RemoveRelation(ctx, "task-a", models.RelationDuplicatedBy, "task-b"))
+	assert.Error(t, svc.RemoveRelation(ctx, "task-a", "blocks", "task-b"))
+	relations.AssertExpectations(t)
+}
+
+func TestRelatedTaskService_GetTask(t *testing.T) {
+	ctx := context.Background()
+	relations := new(MockRelationRepository)
+	svc := NewRelatedTaskService(&getOnlyTaskService{}, relations)
+
+	related := []*models.TaskRelation{{Type: models.RelationCauses, TaskID: "task-2", TaskKey: "TASK-2"}}
+	relations.On("List", ctx, "task-1").Return(related, nil).Once()
+
+	task, err := svc.GetTask(ctx, "task-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, related, task.Relations)
+}
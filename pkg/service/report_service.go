@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// ErrReportTimeout is returned when a report takes longer than the service's timeout
+var ErrReportTimeout = errors.New("report took too long to run; narrow its range or dimensions")
+
+// ReportService manages the saved reports admins define and runs them
+type ReportService interface {
+	CreateReport(ctx context.Context, createdBy string, input *models.ReportInput) (*models.Report, error)
+	GetReport(ctx context.Context, id string) (*models.Report, error)
+	ListReports(ctx context.Context) ([]*models.Report, error)
+	UpdateReport(ctx context.Context, id string, input *models.ReportInput) (*models.Report, error)
+	DeleteReport(ctx context.Context, id string) error
+	// RunReport computes a saved report. Relative ranges are resolved against the
+	// current time, and the query is abandoned with ErrReportTimeout when it runs long.
+	RunReport(ctx context.Context, id string) (*models.ReportResult, error)
+}
+
+type reportService struct {
+	repo    repository.ReportRepository
+	timeout time.Duration
+	now     func() time.Time
+}
+
+// NewReportService creates a report service that gives each run up to timeout
+func NewReportService(repo repository.ReportRepository, timeout time.Duration) ReportService {
+	return &reportService{repo: repo, timeout: timeout, now: time.Now}
+}
+
+func (s *reportService) CreateReport(ctx context.Context, createdBy string, input *models.ReportInput) (*models.Report, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, &models.Report{
+		Name:       input.Name,
+		Definition: input.Definition,
+		CreatedBy:  createdBy,
+	})
+}
+
+func (s *reportService) GetReport(ctx context.Context, id string) (*models.Report, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *reportService) ListReports(ctx context.Context) ([]*models.Report, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *reportService) UpdateReport(ctx context.Context, id string, input *models.ReportInput) (*models.Report, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, input)
+}
+
+func (s *reportService) DeleteReport(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *reportService) RunReport(ctx context.Context, id string) (*models.ReportResult, error) {
+	report, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	definition := report.Definition
+	from, to := definition.Range(now)
+
+	runCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	rows, truncated, err := s.repo.Run(runCtx, &definition, from, to)
+	if err != nil {
+		// The caller going away is not the report's fault
+		if ctx.Err() == nil && runCtx.Err() == context.DeadlineExceeded {
+			return nil, ErrReportTimeout
+		}
+		return nil, err
+	}
+
+	return &models.ReportResult{
+		ReportID:    report.ID,
+		Name:        report.Name,
+		Columns:     definition.Columns(),
+		Rows:        rows,
+		From:        from,
+		To:          to,
+		Truncated:   truncated,
+		GeneratedAt: now,
+	}, nil
+}
+
+// WriteReportCSV writes a report's columns and rows as CSV
+func WriteReportCSV(w io.Writer, result *models.ReportResult) error {
+	writer := csv.NewWriter(w)
+	writer.Write(result.Columns)
+	for _, row := range result.Rows {
+		record := make([]string, 0, len(row.Dimensions)+len(row.Measures))
+		for _, value := range row.Dimensions {
+			record = append(record, csvSafe(value))
+		}
+		for _, value := range row.Measures {
+			record = append(record, strconv.FormatInt(value, 10))
+		}
+		writer.Write(record)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafe keeps spreadsheets from evaluating user-provided values such as tags as formulas
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockReportRepository is a mock implementation of ReportRepository
+type MockReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportRepository) Create(ctx context.Context, report *models.Report) (*models.Report, error) {
+	args := m.Called(ctx, report)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Report), args.Error(1)
+}
+
+func (m *MockReportRepository) GetByID(ctx context.Context, id string) (*models.Report, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Report), args.Error(1)
+}
+
+func (m *MockReportRepository) List(ctx context.Context) ([]*models.Report, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.Report), args.Error(1)
+}
+
+func (m *MockReportRepository) Update(ctx context.Context, id string, input *models.ReportInput) (*models.Report, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Report), args.Error(1)
+}
+
+func (m *MockReportRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockReportRepository) Run(ctx context.Context, definition *models.ReportDefinition, from, to *time.Time) ([]models.ReportRow, bool, error) {
+	args := m.Called(ctx, definition, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]models.ReportRow), args.Bool(1), args.Error(2)
+}
+
+func TestCreateReport_DefaultsAndValidation(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockReportRepository)
+	svc := NewReportService(repo, time.Second)
+
+	repo.On("Create", ctx, mock.MatchedBy(func(report *models.Report) bool {
+		return report.Name == "By status" && report.CreatedBy == "admin-1" &&
+			report.Definition.DateField == models.DateFieldCreatedAt &&
+			report.Definition.RowLimit == models.DefaultReportRowLimit
+	})).Return(&models.Report{ID: "report-1"}, nil).Once()
+
+	report, err := svc.CreateReport(ctx, "admin-1", &models.ReportInput{
+		Name: " By status ",
+		Definition: models.ReportDefinition{
+			Dimensions: []models.ReportDimension{models.DimensionStatus},
+			Measures:   []models.ReportMeasure{models.MeasureCount},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "report-1", report.ID)
+
+	_, err = svc.CreateReport(ctx, "admin-1", &models.ReportInput{
+		Name: "Bad",
+		Definition: models.ReportDefinition{
+			Dimensions: []models.ReportDimension{"owner"},
+			Measures:   []models.ReportMeasure{models.MeasureCount},
+		},
+	})
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestRunReport_ResolvesRelativeRange(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockReportRepository)
+	svc := NewReportService(repo, time.Second).(*reportService)
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	definition := models.ReportDefinition{
+		Dimensions: []models.ReportDimension{models.DimensionAssignee, models.DimensionTag},
+		Measures:   []models.ReportMeasure{models.MeasureCount, models.MeasureTotalEstimate},
+		DateField:  models.DateFieldCreatedAt,
+		LastDays:   30,
+		RowLimit:   2,
+	}
+	repo.On("GetByID", ctx, "report-1").Return(&models.Report{ID: "report-1", Name: "Tags", Definition: definition}, nil)
+	rows := []models.ReportRow{
+		{Dimensions: []string{"alice", "backend"}, Measures: []int64{3, 180}},
+		{Dimensions: []string{"bob", ""}, Measures: []int64{1, 0}},
+	}
+	repo.On("Run", mock.Anything, mock.Anything, mock.MatchedBy(func(from *time.Time) bool {
+		return from != nil && from.Equal(now.AddDate(0, 0, -30))
+	}), mock.MatchedBy(func(to *time.Time) bool {
+		return to != nil && to.Equal(now)
+	})).Return(rows, true, nil).Once()
+
+	result, err := svc.RunReport(ctx, "report-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"assignee", "tag", "count", "total_estimate"}, result.Columns)
+	assert.Equal(t, rows, result.Rows)
+	assert.True(t, result.Truncated)
+	assert.Equal(t, now, result.GeneratedAt)
+	repo.AssertExpectations(t)
+}
+
+func TestRunReport_Timeout(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockReportRepository)
+	svc := NewReportService(repo, 10*time.Millisecond)
+
+	repo.On("GetByID", ctx, "report-1").Return(&models.Report{ID: "report-1", Definition: models.ReportDefinition{
+		Dimensions: []models.ReportDimension{models.DimensionProject},
+		Measures:   []models.ReportMeasure{models.MeasureCount},
+	}}, nil)
+	repo.On("Run", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(nil, false, context.DeadlineExceeded).Once()
+
+	_, err := svc.RunReport(ctx, "report-1")
+	assert.ErrorIs(t, err, ErrReportTimeout)
+}
+
+func TestReportDefinition_Validate(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	dimensions := []models.ReportDimension{models.DimensionStatus}
+	measures := []models.ReportMeasure{models.MeasureCount}
+
+	tests := []struct {
+		name       string
+		definition models.ReportDefinition
+		wantErr    bool
+	}{
+		{name: "valid fixed range", definition: models.ReportDefinition{Dimensions: dimensions, Measures: measures, From: &from, To: &to}},
+		{name: "no dimensions", definition: models.ReportDefinition{Measures: measures}, wantErr: true},
+		{name: "duplicate dimension", definition: models.ReportDefinition{Dimensions: []models.ReportDimension{"tag", "tag"}, Measures: measures}, wantErr: true},
+		{name: "unknown measure", definition: models.ReportDefinition{Dimensions: dimensions, Measures: []models.ReportMeasure{"average"}}, wantErr: true},
+		{name: "unknown date field", definition: models.ReportDefinition{Dimensions: dimensions, Measures: measures, DateField: "deleted_at"}, wantErr: true},
+		{name: "inverted range", definition: models.ReportDefinition{Dimensions: dimensions, Measures: measures, From: &to, To: &from}, wantErr: true},
+		{name: "relative and fixed range", definition: models.ReportDefinition{Dimensions: dimensions, Measures: measures, LastDays: 7, From: &from}, wantErr: true},
+		{name: "row limit too high", definition: models.ReportDefinition{Dimensions: dimensions, Measures: measures, RowLimit: models.MaxReportRowLimit + 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.definition.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// roleAdminPath is where the admin role is edited. The admin role must still be allowed
+// to PUT it, so an edit locking admins out of role management can't be saved.
+const roleAdminPath = "/api/v1/admin/roles/admin"
+
+// RoleInvalidator reloads cached roles after they change, e.g. auth.RoleCache
+type RoleInvalidator interface {
+	Invalidate(ctx context.Context) error
+}
+
+// RoleService manages the role definitions permission checks use. Stored roles replace the
+// built-in role of the same name, or add a role.
+type RoleService interface {
+	// ListRoles lists the roles in effect by name
+	ListRoles(ctx context.Context) ([]*models.RoleDefinition, error)
+	GetRole(ctx context.Context, name string) (*models.RoleDefinition, error)
+	// SaveRole creates a role or replaces its permissions, and reloads this instance's
+	// roles; other instances pick the change up on their next reload
+	SaveRole(ctx context.Context, name, updatedBy string, input *models.RoleInput) (*models.RoleDefinition, error)
+	// DeleteRole deletes a stored role; a built-in role gets its compiled permissions back
+	DeleteRole(ctx context.Context, name string) error
+}
+
+type roleService struct {
+	repo    repository.RoleRepository
+	builtin map[string]auth.Role
+	cache   RoleInvalidator
+}
+
+// NewRoleService creates a role service over the built-in roles, reloading cache after
+// every change when set
+func NewRoleService(repo repository.RoleRepository, builtin map[string]auth.Role, cache RoleInvalidator) RoleService {
+	return &roleService{repo: repo, builtin: builtin, cache: cache}
+}
+
+// NewRoleSource returns an auth.RoleSource loading the built-in roles replaced and
+// extended by the stored ones, for an auth.RoleCache
+func NewRoleSource(repo repository.RoleRepository, builtin map[string]auth.Role) auth.RoleSource {
+	return func(ctx context.Context) (map[string]auth.Role, error) {
+		definitions, err := effectiveRoles(ctx, repo, builtin)
+		if err != nil {
+			return nil, err
+		}
+		roles := make(map[string]auth.Role, len(definitions))
+		for _, definition := range definitions {
+			roles[definition.Name] = auth.Role{Name: definition.Name, Permissions: definition.Permissions}
+		}
+		return roles, nil
+	}
+}
+
+// effectiveRoles merges the stored roles over the built-in ones, by name
+func effectiveRoles(ctx context.Context, repo repository.RoleRepository, builtin map[string]auth.Role) ([]*models.RoleDefinition, error) {
+	stored, err := repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*models.RoleDefinition, len(builtin)+len(stored))
+	for name, role := range builtin {
+		byName[name] = &models.RoleDefinition{Name: name, Permissions: role.Permissions, Builtin: true}
+	}
+	for _, role := range stored {
+		byName[role.Name] = role
+	}
+
+	roles := make([]*models.RoleDefinition, 0, len(byName))
+	for _, role := range byName {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+	return roles, nil
+}
+
+func (s *roleService) ListRoles(ctx context.Context) ([]*models.RoleDefinition, error) {
+	return effectiveRoles(ctx, s.repo, s.builtin)
+}
+
+func (s *roleService) GetRole(ctx context.Context, name string) (*models.RoleDefinition, error) {
+	roles, err := s.ListRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.Name == name {
+			return role, nil
+		}
+	}
+	return nil, models.ErrRoleNotFound
+}
+
+func (s *roleService) SaveRole(ctx context.Context, name, updatedBy string, input *models.RoleInput) (*models.RoleDefinition, error) {
+	if err := models.ValidateRoleName(name); err != nil {
+		return nil, err
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	if name == "admin" && !(auth.Role{Name: name, Permissions: input.Permissions}).Allows(http.MethodPut, roleAdminPath) {
+		return nil, fmt.Errorf("the admin role must keep PUT on %s", roleAdminPath)
+	}
+
+	role, err := s.repo.Save(ctx, &models.RoleDefinition{
+		Name:        name,
+		Permissions: input.Permissions,
+		UpdatedBy:   updatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.reload(ctx)
+	return role, nil
+}
+
+func (s *roleService) DeleteRole(ctx context.Context, name string) error {
+	if err := s.repo.Delete(ctx, name); err != nil {
+		return err
+	}
+	s.reload(ctx)
+	return nil
+}
+
+// reload refreshes the cached roles; the change is saved either way, so a failure only
+// delays it until the next background reload
+func (s *roleService) reload(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Invalidate(ctx); err != nil {
+		log.Printf("Failed to reload roles after a change: %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// MockRoleRepository is a mock implementation of RoleRepository
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) List(ctx context.Context) ([]*models.RoleDefinition, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.RoleDefinition), args.Error(1)
+}
+
+func (m *MockRoleRepository) Save(ctx context.Context, role *models.RoleDefinition) (*models.RoleDefinition, error) {
+	args := m.Called(ctx, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RoleDefinition), args.Error(1)
+}
+
+func (m *MockRoleRepository) Delete(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+// countingInvalidator counts the reloads it is asked for
+type countingInvalidator struct {
+	reloads int
+}
+
+func (c *countingInvalidator) Invalidate(ctx context.Context) error {
+	c.reloads++
+	return nil
+}
+
+var testBuiltinRoles = map[string]auth.Role{
+	"admin":  {Name: "admin", Permissions: map[string][]string{"/api/v1/admin/roles/{id}": {"PUT"}}},
+	"viewer": {Name: "viewer", Permissions: map[string][]string{"/api/v1/tasks": {"GET"}}},
+}
+
+func TestRoleSource_StoredRolesReplaceBuiltinOnes(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockRoleRepository)
+	repo.On("List", ctx).Return([]*models.RoleDefinition{
+		{Name: "viewer", Permissions: map[string][]string{"/api/v1/tasks": {"GET"}, "/api/v1/tasks/{id}": {"GET"}}},
+		{Name: "triager", Permissions: map[string][]string{"/api/v1/tasks/{id}": {"GET", "PUT"}}},
+	}, nil)
+
+	roles, err := NewRoleSource(repo, testBuiltinRoles)(ctx)
+	require.NoError(t, err)
+	assert.Len(t, roles, 3)
+	assert.Equal(t, testBuiltinRoles["admin"], roles["admin"])
+	assert.Contains(t, roles["viewer"].Permissions, "/api/v1/tasks/{id}")
+	assert.Equal(t, []string{"GET", "PUT"}, roles["triager"].Permissions["/api/v1/tasks/{id}"])
+
+	list, err := NewRoleService(repo, testBuiltinRoles, nil).ListRoles(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, "admin", list[0].Name)
+	assert.True(t, list[0].Builtin)
+	assert.Equal(t, "triager", list[1].Name)
+	assert.False(t, list[2].Builtin)
+}
+
+func TestSaveRole(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockRoleRepository)
+	cache := &countingInvalidator{}
+	svc := NewRoleService(repo, testBuiltinRoles, cache)
+
+	permissions := map[string][]string{"/api/v1/tasks": {"GET"}, "/api/v1/tasks/{id}": {"GET", "PUT"}}
+	saved := &models.RoleDefinition{Name: "triager", Permissions: permissions, UpdatedBy: "admin-1"}
+	repo.On("Save", ctx, saved).Return(saved, nil).Once()
+
+	role, err := svc.SaveRole(ctx, "triager", "admin-1", &models.RoleInput{Permissions: permissions})
+	require.NoError(t, err)
+	assert.Equal(t, saved, role)
+	assert.Equal(t, 1, cache.reloads)
+
+	tests := []struct {
+		name        string
+		role        string
+		permissions map[string][]string
+	}{
+		{"invalid name", "Triager!", permissions},
+		{"missing permissions", "triager", nil},
+		{"regular expression", "triager", map[string][]string{"/api/v1/.*": {"GET"}}},
+		{"unknown method", "triager", map[string][]string{"/api/v1/tasks": {"TRACE"}}},
+		{"no methods", "triager", map[string][]string{"/api/v1/tasks": {}}},
+		{"admin locked out", "admin", map[string][]string{"/api/v1/tasks": {"GET"}}},
+		{"admin locked out of its own role", "admin", map[string][]string{"/api/v1/admin/roles/{id}": {"GET"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.SaveRole(ctx, tt.role, "admin-1", &models.RoleInput{Permissions: tt.permissions})
+			assert.Error(t, err)
+		})
+	}
+	repo.AssertExpectations(t)
+	assert.Equal(t, 1, cache.reloads)
+}
+
+func TestSaveRole_AdminKeepsRoleManagement(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockRoleRepository)
+	svc := NewRoleService(repo, testBuiltinRoles, nil)
+
+	// Any permission that still lets admins PUT the admin role keeps them in
+	permissions := map[string][]string{"/api/v1/admin/roles/admin": {"GET", "PUT"}}
+	saved := &models.RoleDefinition{Name: "admin", Permissions: permissions, UpdatedBy: "admin-1"}
+	repo.On("Save", ctx, saved).Return(saved, nil).Once()
+
+	_, err := svc.SaveRole(ctx, "admin", "admin-1", &models.RoleInput{Permissions: permissions})
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestSaveRole_AcceptsBuiltinRoles(t *testing.T) {
+	// Every built-in role can be saved back unchanged as the starting point of an edit
+	for name, role := range auth.DefaultRoles {
+		input := &models.RoleInput{Permissions: role.Permissions}
+		assert.NoError(t, models.ValidateRoleName(name), name)
+		assert.NoError(t, input.Validate(), name)
+	}
+	assert.True(t, auth.DefaultRoles["admin"].Allows(http.MethodPut, roleAdminPath))
+}
+
+func TestDeleteRole(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockRoleRepository)
+	cache := &countingInvalidator{}
+	svc := NewRoleService(repo, testBuiltinRoles, cache)
+
+	repo.On("Delete", ctx, "viewer").Return(nil).Once()
+	repo.On("Delete", ctx, "missing").Return(models.ErrRoleNotFound).Once()
+	repo.On("List", ctx).Return([]*models.RoleDefinition{}, nil).Once()
+
+	require.NoError(t, svc.DeleteRole(ctx, "viewer"))
+	assert.ErrorIs(t, svc.DeleteRole(ctx, "missing"), models.ErrRoleNotFound)
+	assert.Equal(t, 1, cache.reloads)
+
+	// The built-in role is back
+	role, err := svc.GetRole(ctx, "viewer")
+	require.NoError(t, err)
+	assert.True(t, role.Builtin)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// MaxRelatedTasks is the most related tasks returned for a task
+const MaxRelatedTasks = 50
+
+// MaxSearchResults is the most tasks returned per search page
+const MaxSearchResults = 100
+
+var (
+	// ErrSearchQueryRequired is returned for searches without text
+	ErrSearchQueryRequired = errors.New("search query is required")
+	// ErrInvalidSearchLanguage is returned for searches in an unsupported language
+	ErrInvalidSearchLanguage = fmt.Errorf("language must be one of %v", models.SearchLanguages)
+)
+
+// SearchService handles task search business logic
+type SearchService interface {
+	// SearchTasks matches text against each task in its project's search language, or in
+	// language when set
+	SearchTasks(ctx context.Context, text string, status models.TaskStatus, language models.SearchLanguage, page, limit int) (*repository.TaskSearchResult, error)
+	RelatedTasks(ctx context.Context, id string, limit int) ([]*models.Task, error)
+}
+
+type searchService struct {
+	repo     repository.TaskRepository
+	searcher repository.TaskSearcher
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(repo repository.TaskRepository, searcher repository.TaskSearcher) SearchService {
+	return &searchService{repo: repo, searcher: searcher}
+}
+
+func (s *searchService) SearchTasks(ctx context.Context, text string, status models.TaskStatus, language models.SearchLanguage, page, limit int) (*repository.TaskSearchResult, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, ErrSearchQueryRequired
+	}
+	if language != "" && !language.IsValid() {
+		return nil, ErrInvalidSearchLanguage
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > MaxSearchResults {
+		limit = MaxSearchResults
+	}
+
+	return s.searcher.Search(ctx, repository.TaskSearchQuery{
+		Text:       text,
+		Status:     status,
+		Language:   language,
+		ProjectKey: projectRestriction(ctx),
+		Page:       page,
+		Limit:      limit,
+	})
+}
+
+func (s *searchService) RelatedTasks(ctx context.Context, id string, limit int) ([]*models.Task, error) {
+	if id == "" {
+		return nil, errors.New("id is required")
+	}
+	if limit < 1 {
+		limit = 5
+	}
+	if limit > MaxRelatedTasks {
+		limit = MaxRelatedTasks
+	}
+
+	// Make sure the source task exists so callers get a not found error
+	source, err := s.repo.GetByID(ctx, id)
+	if _, err := visibleTask(ctx, source, err); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.searcher.Related(ctx, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	// Callers limited to one project only see related tasks in it
+	visible := []*models.Task{}
+	for _, task := range tasks {
+		if canSee(ctx, task) {
+			visible = append(visible, task)
+		}
+	}
+
+	return visible, nil
+}
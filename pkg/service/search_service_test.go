@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// MockTaskSearcher is a mock implementation of TaskSearcher
+type MockTaskSearcher struct {
+	mock.Mock
+}
+
+func (m *MockTaskSearcher) Search(ctx context.Context, query repository.TaskSearchQuery) (*repository.TaskSearchResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.TaskSearchResult), args.Error(1)
+}
+
+func (m *MockTaskSearcher) Related(ctx context.Context, id string, limit int) ([]*models.Task, error) {
+	args := m.Called(ctx, id, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
+func TestSearchTasks(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		text     string
+		language models.SearchLanguage
+		page     int
+		limit    int
+		mock     func(*MockTaskSearcher)
+		wantErr  error
+	}{
+		{
+			name:  "applies default pagination",
+			text:  "  docs  ",
+			page:  0,
+			limit: 0,
+			mock: func(m *MockTaskSearcher) {
+				m.On("Search", mock.Anything, repository.TaskSearchQuery{Text: "docs", Page: 1, Limit: 10}).
+					Return(&repository.TaskSearchResult{Total: 1}, nil)
+			},
+		},
+		{
+			name:  "caps the page size",
+			text:  "docs",
+			page:  2,
+			limit: 10000,
+			mock: func(m *MockTaskSearcher) {
+				m.On("Search", mock.Anything, repository.TaskSearchQuery{Text: "docs", Page: 2, Limit: MaxSearchResults}).
+					Return(&repository.TaskSearchResult{Total: 1}, nil)
+			},
+		},
+		{
+			name:     "search language",
+			text:     "aufgaben",
+			language: models.SearchLanguageGerman,
+			mock: func(m *MockTaskSearcher) {
+				m.On("Search", mock.Anything, repository.TaskSearchQuery{Text: "aufgaben", Language: models.SearchLanguageGerman, Page: 1, Limit: 10}).
+					Return(&repository.TaskSearchResult{Total: 1}, nil)
+			},
+		},
+		{
+			name:     "unsupported language",
+			text:     "docs",
+			language: "klingon",
+			mock:     func(m *MockTaskSearcher) {},
+			wantErr:  ErrInvalidSearchLanguage,
+		},
+		{
+			name:    "empty query",
+			text:    "   ",
+			mock:    func(m *MockTaskSearcher) {},
+			wantErr: ErrSearchQueryRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := new(MockTaskSearcher)
+			tt.mock(searcher)
+			service := NewSearchService(new(MockTaskRepository), searcher)
+
+			_, err := service.SearchTasks(ctx, tt.text, "", tt.language, tt.page, tt.limit)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			searcher.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRelatedTasks(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		id      string
+		limit   int
+		ctx     context.Context // defaults to ctx
+		mock    func(*MockTaskRepository, *MockTaskSearcher)
+		want    []*models.Task
+		wantErr bool
+	}{
+		{
+			name: "returns related tasks",
+			id:   "1",
+			mock: func(repo *MockTaskRepository, searcher *MockTaskSearcher) {
+				repo.On("GetByID", mock.Anything, "1").Return(&models.Task{ID: "1"}, nil)
+				searcher.On("Related", mock.Anything, "1", 5).Return([]*models.Task{{ID: "2"}}, nil)
+			},
+			want: []*models.Task{{ID: "2"}},
+		},
+		{
+			name: "no related tasks",
+			id:   "1",
+			mock: func(repo *MockTaskRepository, searcher *MockTaskSearcher) {
+				repo.On("GetByID", mock.Anything, "1").Return(&models.Task{ID: "1"}, nil)
+				searcher.On("Related", mock.Anything, "1", 5).Return(nil, nil)
+			},
+			want: []*models.Task{},
+		},
+		{
+			name: "task not found",
+			id:   "missing",
+			mock: func(repo *MockTaskRepository, searcher *MockTaskSearcher) {
+				repo.On("GetByID", mock.Anything, "missing").Return(nil, models.ErrTaskNotFound)
+			},
+			wantErr: true,
+		},
+		{
+			name: "guests only see their project",
+			id:   "1",
+			ctx:  context.WithValue(ctx, "claims", &auth.Claims{UserID: auth.GuestUserID, ProjectID: "roadmap"}),
+			mock: func(repo *MockTaskRepository, searcher *MockTaskSearcher) {
+				repo.On("GetByID", mock.Anything, "1").Return(&models.Task{ID: "1", ProjectKey: "ROADMAP"}, nil)
+				searcher.On("Related", mock.Anything, "1", 5).Return([]*models.Task{{ID: "2", ProjectKey: "ROADMAP"}, {ID: "3", ProjectKey: "OTHER"}}, nil)
+			},
+			want: []*models.Task{{ID: "2", ProjectKey: "ROADMAP"}},
+		},
+		{
+			name:  "limit is capped",
+			id:    "1",
+			limit: 1000,
+			mock: func(repo *MockTaskRepository, searcher *MockTaskSearcher) {
+				repo.On("GetByID", mock.Anything, "1").Return(&models.Task{ID: "1"}, nil)
+				searcher.On("Related", mock.Anything, "1", MaxRelatedTasks).Return([]*models.Task{{ID: "2"}}, nil)
+			},
+			want: []*models.Task{{ID: "2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTaskRepository)
+			searcher := new(MockTaskSearcher)
+			tt.mock(repo, searcher)
+			service := NewSearchService(repo, searcher)
+			callCtx := tt.ctx
+			if callCtx == nil {
+				callCtx = ctx
+			}
+
+			got, err := service.RelatedTasks(callCtx, tt.id, tt.limit)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			repo.AssertExpectations(t)
+			searcher.AssertExpectations(t)
+		})
+	}
+}
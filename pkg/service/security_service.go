@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/security"
+)
+
+// SecurityService exposes security alerts raised for users
+type SecurityService interface {
+	ListSecurityEvents(ctx context.Context, userID string, page, limit int) ([]*models.AuditEvent, int, error)
+}
+
+type securityService struct {
+	repo repository.AuditRepository
+}
+
+// NewSecurityService creates a new security service
+func NewSecurityService(repo repository.AuditRepository) SecurityService {
+	return &securityService{repo: repo}
+}
+
+func (s *securityService) ListSecurityEvents(ctx context.Context, userID string, page, limit int) ([]*models.AuditEvent, int, error) {
+	if userID == "" {
+		return nil, 0, errors.New("user id is required")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	return s.repo.List(ctx, repository.AuditFilter{
+		SubjectID:    userID,
+		ActionPrefix: security.ActionPrefix,
+		Page:         page,
+		Limit:        limit,
+	})
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/share"
+)
+
+// ShareService manages signed, expiring links to individual tasks
+type ShareService interface {
+	CreateShare(ctx context.Context, taskID, createdBy string, input *models.TaskShareCreate) (*models.TaskShare, string, error)
+	ListShares(ctx context.Context, taskID string) ([]*models.TaskShare, error)
+	RevokeShare(ctx context.Context, taskID, shareID string) error
+	ResolveShare(ctx context.Context, token string) (*models.TaskShare, error)
+	GetSharedTask(ctx context.Context, sh *models.TaskShare) (*models.Task, error)
+}
+
+type shareService struct {
+	shares repository.ShareRepository
+	tasks  repository.TaskRepository
+	signer *share.Signer
+}
+
+// NewShareService creates a new share service
+func NewShareService(shares repository.ShareRepository, tasks repository.TaskRepository, signer *share.Signer) ShareService {
+	return &shareService{shares: shares, tasks: tasks, signer: signer}
+}
+
+// CreateShare creates a share link for an existing task and returns it with its signed token
+func (s *shareService) CreateShare(ctx context.Context, taskID, createdBy string, input *models.TaskShareCreate) (*models.TaskShare, string, error) {
+	if err := input.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, "", err
+	}
+
+	created, err := s.shares.Create(ctx, &models.TaskShare{
+		TaskID:     taskID,
+		Permission: input.Permission,
+		CreatedBy:  createdBy,
+		ExpiresAt:  time.Now().Add(time.Duration(input.ExpiresIn) * time.Second),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := s.signer.Sign(created)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return created, token, nil
+}
+
+func (s *shareService) ListShares(ctx context.Context, taskID string) ([]*models.TaskShare, error) {
+	return s.shares.ListByTask(ctx, taskID)
+}
+
+func (s *shareService) RevokeShare(ctx context.Context, taskID, shareID string) error {
+	if shareID == "" {
+		return errors.New("share id is required")
+	}
+
+	return s.shares.Revoke(ctx, taskID, shareID)
+}
+
+// ResolveShare verifies a share token and checks the share hasn't been revoked
+func (s *shareService) ResolveShare(ctx context.Context, token string) (*models.TaskShare, error) {
+	claims, err := s.signer.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.shares.GetByID(ctx, claims.ID)
+	if err != nil || stored.TaskID != claims.Subject {
+		return nil, share.ErrInvalidShareToken
+	}
+	if !stored.IsActive(time.Now()) {
+		return nil, share.ErrShareExpired
+	}
+
+	return stored, nil
+}
+
+func (s *shareService) GetSharedTask(ctx context.Context, sh *models.TaskShare) (*models.Task, error) {
+	return s.tasks.GetByID(ctx, sh.TaskID)
+}
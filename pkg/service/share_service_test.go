@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/share"
+)
+
+// MockShareRepository is a mock implementation of ShareRepository
+type MockShareRepository struct {
+	mock.Mock
+}
+
+func (m *MockShareRepository) Create(ctx context.Context, s *models.TaskShare) (*models.TaskShare, error) {
+	args := m.Called(ctx, s)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	if fn, ok := args.Get(0).(func(context.Context, *models.TaskShare) *models.TaskShare); ok {
+		return fn(ctx, s), args.Error(1)
+	}
+	return args.Get(0).(*models.TaskShare), args.Error(1)
+}
+
+func (m *MockShareRepository) GetByID(ctx context.Context, id string) (*models.TaskShare, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaskShare), args.Error(1)
+}
+
+func (m *MockShareRepository) ListByTask(ctx context.Context, taskID string) ([]*models.TaskShare, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]*models.TaskShare), args.Error(1)
+}
+
+func (m *MockShareRepository) Revoke(ctx context.Context, taskID, id string) error {
+	args := m.Called(ctx, taskID, id)
+	return args.Error(0)
+}
+
+func TestCreateShare(t *testing.T) {
+	ctx := context.Background()
+	signer := share.NewSigner([]byte("test-secret"), "test-issuer")
+
+	tests := []struct {
+		name    string
+		input   *models.TaskShareCreate
+		taskErr error
+		wantErr bool
+	}{
+		{
+			name:  "defaults to read for 7 days",
+			input: &models.TaskShareCreate{},
+		},
+		{
+			name:  "comment permission",
+			input: &models.TaskShareCreate{Permission: models.SharePermissionComment, ExpiresIn: 3600},
+		},
+		{
+			name:    "invalid permission",
+			input:   &models.TaskShareCreate{Permission: "write"},
+			wantErr: true,
+		},
+		{
+			name:    "expiry too long",
+			input:   &models.TaskShareCreate{ExpiresIn: int64((31 * 24 * time.Hour).Seconds())},
+			wantErr: true,
+		},
+		{
+			name:    "task not found",
+			input:   &models.TaskShareCreate{},
+			taskErr: errors.New("task not found"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := new(MockShareRepository)
+			tasks := new(MockTaskRepository)
+			svc := NewShareService(shares, tasks, signer)
+
+			if tt.taskErr != nil {
+				tasks.On("GetByID", ctx, "task-1").Return(nil, tt.taskErr)
+			} else {
+				tasks.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1"}, nil)
+			}
+			shares.On("Create", ctx, mock.AnythingOfType("*models.TaskShare")).Return(
+				func(ctx context.Context, s *models.TaskShare) *models.TaskShare {
+					created := *s
+					created.ID = "share-1"
+					created.CreatedAt = time.Now()
+					return &created
+				}, nil).Maybe()
+
+			created, token, err := svc.CreateShare(ctx, "task-1", "user-1", tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				shares.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "task-1", created.TaskID)
+			assert.Equal(t, "user-1", created.CreatedBy)
+			assert.Equal(t, tt.input.Permission, created.Permission)
+			assert.WithinDuration(t, time.Now().Add(time.Duration(tt.input.ExpiresIn)*time.Second), created.ExpiresAt, time.Minute)
+
+			claims, err := signer.Verify(token)
+			require.NoError(t, err)
+			assert.Equal(t, "share-1", claims.ID)
+		})
+	}
+}
+
+func TestResolveShare(t *testing.T) {
+	ctx := context.Background()
+	signer := share.NewSigner([]byte("test-secret"), "test-issuer")
+	revokedAt := time.Now()
+
+	active := &models.TaskShare{
+		ID:         "share-1",
+		TaskID:     "task-1",
+		Permission: models.SharePermissionRead,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	revoked := *active
+	revoked.RevokedAt = &revokedAt
+	otherTask := *active
+	otherTask.TaskID = "task-2"
+
+	token, err := signer.Sign(active)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		token     string
+		stored    *models.TaskShare
+		lookupErr error
+		wantErr   error
+	}{
+		{name: "active share", token: token, stored: active},
+		{name: "revoked share", token: token, stored: &revoked, wantErr: share.ErrShareExpired},
+		{name: "share deleted", token: token, lookupErr: errors.New("share not found"), wantErr: share.ErrInvalidShareToken},
+		{name: "task mismatch", token: token, stored: &otherTask, wantErr: share.ErrInvalidShareToken},
+		{name: "bad signature", token: token + "x", wantErr: share.ErrInvalidShareToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := new(MockShareRepository)
+			svc := NewShareService(shares, new(MockTaskRepository), signer)
+			if tt.stored != nil || tt.lookupErr != nil {
+				shares.On("GetByID", ctx, "share-1").Return(tt.stored, tt.lookupErr)
+			}
+
+			resolved, err := svc.ResolveShare(ctx, tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, active, resolved)
+		})
+	}
+}
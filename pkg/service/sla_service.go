@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"sample/task-management-system/pkg/metrics"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// SLAService manages task SLA policies and reports breaches
+type SLAService interface {
+	// SetPolicy replaces the task's SLA policy
+	SetPolicy(ctx context.Context, taskID string, policy *models.SLAPolicy) (*models.Task, error)
+	// RemovePolicy drops the task's SLA policy
+	RemovePolicy(ctx context.Context, taskID string) (*models.Task, error)
+	// DetectBreaches flags the SLA targets missed since the last run and records them as
+	// metrics
+	DetectBreaches(ctx context.Context) error
+	// Run detects SLA breaches every interval until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type slaService struct {
+	repo repository.SLARepository
+	now  func() time.Time
+}
+
+// NewSLAService creates a new SLA service
+func NewSLAService(repo repository.SLARepository) SLAService {
+	return &slaService{repo: repo, now: time.Now}
+}
+
+func (s *slaService) SetPolicy(ctx context.Context, taskID string, policy *models.SLAPolicy) (*models.Task, error) {
+	respond, resolve, err := policy.Parse()
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.repo.SetPolicy(ctx, taskID, respond, resolve)
+	if err != nil {
+		return nil, err
+	}
+	evaluateSLA(task, s.now())
+	return task, nil
+}
+
+func (s *slaService) RemovePolicy(ctx context.Context, taskID string) (*models.Task, error) {
+	return s.repo.SetPolicy(ctx, taskID, nil, nil)
+}
+
+func (s *slaService) DetectBreaches(ctx context.Context) error {
+	now := s.now()
+	for _, kind := range []models.SLAKind{models.SLARespond, models.SLAResolve} {
+		tasks, err := s.repo.MarkBreached(ctx, kind, now)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			log.Printf("Task %s breached its %s SLA", task.ID, kind)
+		}
+		metrics.RecordSLABreaches(string(kind), len(tasks))
+	}
+	return nil
+}
+
+func (s *slaService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.DetectBreaches(ctx); err != nil {
+				log.Printf("Failed to detect SLA breaches: %v", err)
+			}
+		}
+	}
+}
+
+// evaluateSLA sets the due times and remaining time of the task's SLA targets as of now
+func evaluateSLA(task *models.Task, now time.Time) {
+	if task != nil && task.SLA != nil {
+		task.SLA.Evaluate(task.CreatedAt, now)
+	}
+}
+
+// slaTaskService evaluates the SLA of the tasks it returns
+type slaTaskService struct {
+	TaskService
+	now func() time.Time
+}
+
+// NewSLATaskService wraps a TaskService so the tasks it returns carry their SLA's due
+// times and remaining time
+func NewSLATaskService(next TaskService) TaskService {
+	return &slaTaskService{TaskService: next, now: time.Now}
+}
+
+// evaluated evaluates the SLA of a single returned task
+func (s *slaTaskService) evaluated(task *models.Task, err error) (*models.Task, error) {
+	if err == nil {
+		evaluateSLA(task, s.now())
+	}
+	return task, err
+}
+
+func (s *slaTaskService) CreateTask(ctx context.Context, task *models.TaskCreate) (*models.Task, error) {
+	return s.evaluated(s.TaskService.CreateTask(ctx, task))
+}
+
+func (s *slaTaskService) GetTask(ctx context.Context, id string) (*models.Task, error) {
+	return s.evaluated(s.TaskService.GetTask(ctx, id))
+}
+
+func (s *slaTaskService) GetTaskByKey(ctx context.Context, key string) (*models.Task, error) {
+	return s.evaluated(s.TaskService.GetTaskByKey(ctx, key))
+}
+
+func (s *slaTaskService) GetTaskBySlug(ctx context.Context, projectKey, slug string) (*models.Task, error) {
+	return s.evaluated(s.TaskService.GetTaskBySlug(ctx, projectKey, slug))
+}
+
+func (s *slaTaskService) UpdateTask(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error) {
+	return s.evaluated(s.TaskService.UpdateTask(ctx, id, task))
+}
+
+func (s *slaTaskService) PublishDraft(ctx context.Context, id string) (*models.Task, error) {
+	return s.evaluated(s.TaskService.PublishDraft(ctx, id))
+}
+
+func (s *slaTaskService) ListTasks(ctx context.Context, filter repository.TaskFilter) ([]*models.Task, int, error) {
+	tasks, total, err := s.TaskService.ListTasks(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	now := s.now()
+	for _, task := range tasks {
+		evaluateSLA(task, now)
+	}
+	return tasks, total, nil
+}
+
+func (s *slaTaskService) ListSubtasks(ctx context.Context, id string, recursive bool) ([]*models.Task, error) {
+	tasks, err := s.TaskService.ListSubtasks(ctx, id, recursive)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	for _, task := range tasks {
+		evaluateSLA(task, now)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockSLARepository is a mock implementation of SLARepository
+type MockSLARepository struct {
+	mock.Mock
+}
+
+func (m *MockSLARepository) SetPolicy(ctx context.Context, taskID string, respondWithin, resolveWithin *time.Duration) (*models.Task, error) {
+	args := m.Called(ctx, taskID, respondWithin, resolveWithin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockSLARepository) MarkBreached(ctx context.Context, kind models.SLAKind, now time.Time) ([]*models.Task, error) {
+	args := m.Called(ctx, kind, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
+// fixedTaskService returns the same task for every GetTask
+type fixedTaskService struct {
+	TaskService
+	task *models.Task
+}
+
+func (s *fixedTaskService) GetTask(ctx context.Context, id string) (*models.Task, error) {
+	return s.task, nil
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func TestSetSLAPolicy(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	now := created.Add(3 * time.Hour)
+
+	tests := []struct {
+		name        string
+		policy      models.SLAPolicy
+		wantRespond *time.Duration
+		wantResolve *time.Duration
+		wantErr     bool
+	}{
+		{name: "both targets", policy: models.SLAPolicy{RespondWithin: "4h", ResolveWithin: "72h"}, wantRespond: durationPtr(4 * time.Hour), wantResolve: durationPtr(72 * time.Hour)},
+		{name: "resolve only", policy: models.SLAPolicy{ResolveWithin: "2h"}, wantResolve: durationPtr(2 * time.Hour)},
+		{name: "neither", policy: models.SLAPolicy{}, wantErr: true},
+		{name: "invalid duration", policy: models.SLAPolicy{RespondWithin: "soon"}, wantErr: true},
+		{name: "too short", policy: models.SLAPolicy{RespondWithin: "10ms"}, wantErr: true},
+		{name: "too long", policy: models.SLAPolicy{ResolveWithin: "9000h"}, wantErr: true},
+		{name: "resolve before respond", policy: models.SLAPolicy{RespondWithin: "4h", ResolveWithin: "1h"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockSLARepository)
+			svc := &slaService{repo: repo, now: func() time.Time { return now }}
+
+			if !tt.wantErr {
+				repo.On("SetPolicy", ctx, "task-1", tt.wantRespond, tt.wantResolve).Return(&models.Task{
+					ID:        "task-1",
+					CreatedAt: created,
+					SLA:       models.NewTaskSLA(tt.wantRespond, tt.wantResolve),
+				}, nil).Once()
+			}
+
+			task, err := svc.SetPolicy(ctx, "task-1", &tt.policy)
+			if tt.wantErr {
+				assert.Error(t, err)
+				repo.AssertNotCalled(t, "SetPolicy", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			repo.AssertExpectations(t)
+			if tt.wantResolve != nil {
+				assert.Equal(t, created.Add(*tt.wantResolve), task.SLA.Resolve.Due)
+				assert.Equal(t, int64((*tt.wantResolve-3*time.Hour)/time.Second), task.SLA.Resolve.RemainingSeconds)
+			}
+		})
+	}
+}
+
+func TestSLATaskService_Evaluates(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	respondedAt := created.Add(5 * time.Hour)
+	now := created.Add(6 * time.Hour)
+
+	sla := models.NewTaskSLA(durationPtr(4*time.Hour), durationPtr(8*time.Hour))
+	sla.RespondedAt = &respondedAt
+	next := &fixedTaskService{task: &models.Task{ID: "task-1", CreatedAt: created, SLA: sla}}
+	svc := &slaTaskService{TaskService: next, now: func() time.Time { return now }}
+
+	task, err := svc.GetTask(ctx, "task-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &models.SLATarget{Due: created.Add(4 * time.Hour), RemainingSeconds: -3600, Breached: true}, task.SLA.Respond,
+		"a target met late stays breached by the time it was late")
+	assert.Equal(t, &models.SLATarget{Due: created.Add(8 * time.Hour), RemainingSeconds: 7200}, task.SLA.Resolve)
+	assert.Equal(t, "4h0m0s", task.SLA.Policy.RespondWithin)
+}
+
+func TestDetectBreaches(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	repo := new(MockSLARepository)
+	svc := &slaService{repo: repo, now: func() time.Time { return now }}
+
+	repo.On("MarkBreached", ctx, models.SLARespond, now).Return([]*models.Task{{ID: "task-1"}}, nil).Once()
+	repo.On("MarkBreached", ctx, models.SLAResolve, now).Return([]*models.Task{}, nil).Once()
+
+	assert.NoError(t, svc.DetectBreaches(ctx))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// SnoozeService defers open tasks, hiding them from default lists for a while
+type SnoozeService interface {
+	Snooze(ctx context.Context, taskID string, snooze *models.TaskSnooze) (*models.Task, error)
+	// Wake ends a task's snooze early
+	Wake(ctx context.Context, taskID string) (*models.Task, error)
+}
+
+type snoozeService struct {
+	repo repository.SnoozeRepository
+	now  func() time.Time
+}
+
+// NewSnoozeService creates a new snooze service
+func NewSnoozeService(repo repository.SnoozeRepository) SnoozeService {
+	return &snoozeService{repo: repo, now: time.Now}
+}
+
+func (s *snoozeService) Snooze(ctx context.Context, taskID string, snooze *models.TaskSnooze) (*models.Task, error) {
+	until, err := snooze.WakeAt(s.now())
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Snooze(ctx, taskID, &until)
+}
+
+func (s *snoozeService) Wake(ctx context.Context, taskID string) (*models.Task, error) {
+	return s.repo.Snooze(ctx, taskID, nil)
+}
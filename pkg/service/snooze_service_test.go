@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockSnoozeRepository is a mock implementation of SnoozeRepository
+type MockSnoozeRepository struct {
+	mock.Mock
+}
+
+func (m *MockSnoozeRepository) Snooze(ctx context.Context, id string, until *time.Time) (*models.Task, error) {
+	args := m.Called(ctx, id, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func TestSnooze(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	until := now.Add(48 * time.Hour)
+	past := now.Add(-time.Minute)
+	tooLate := now.Add(models.MaxSnooze + time.Hour)
+
+	tests := []struct {
+		name     string
+		snooze   models.TaskSnooze
+		wantWake time.Time
+		wantErr  bool
+	}{
+		{name: "for a duration", snooze: models.TaskSnooze{Duration: "4h"}, wantWake: now.Add(4 * time.Hour)},
+		{name: "until a time", snooze: models.TaskSnooze{Until: &until}, wantWake: until},
+		{name: "neither", snooze: models.TaskSnooze{}, wantErr: true},
+		{name: "both", snooze: models.TaskSnooze{Duration: "4h", Until: &until}, wantErr: true},
+		{name: "invalid duration", snooze: models.TaskSnooze{Duration: "tomorrow"}, wantErr: true},
+		{name: "negative duration", snooze: models.TaskSnooze{Duration: "-1h"}, wantErr: true},
+		{name: "in the past", snooze: models.TaskSnooze{Until: &past}, wantErr: true},
+		{name: "too long", snooze: models.TaskSnooze{Until: &tooLate}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockSnoozeRepository)
+			svc := &snoozeService{repo: repo, now: func() time.Time { return now }}
+
+			repo.On("Snooze", ctx, "task-1", mock.Anything).Return(&models.Task{ID: "task-1"}, nil).Maybe()
+
+			_, err := svc.Snooze(ctx, "task-1", &tt.snooze)
+			if tt.wantErr {
+				assert.Error(t, err)
+				repo.AssertNotCalled(t, "Snooze", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			repo.AssertCalled(t, "Snooze", ctx, "task-1", &tt.wantWake)
+		})
+	}
+}
+
+func TestWake(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockSnoozeRepository)
+	svc := NewSnoozeService(repo)
+
+	repo.On("Snooze", ctx, "task-1", (*time.Time)(nil)).Return(&models.Task{ID: "task-1"}, nil).Once()
+
+	task, err := svc.Wake(ctx, "task-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "task-1", task.ID)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/notify"
+	"sample/task-management-system/pkg/repository"
+)
+
+// StaleTaskService flags open tasks that went without updates for too long
+type StaleTaskService interface {
+	GetProjectSettings(ctx context.Context, projectKey string) (*models.ProjectSettings, error)
+	UpdateProjectSettings(ctx context.Context, projectKey, updatedBy string, input *models.ProjectSettingsUpdate) (*models.ProjectSettings, error)
+	// DetectStale flags the tasks that went stale since the last run, publishes a
+	// TaskStale event for each and emails their assignees, or owners when unassigned
+	DetectStale(ctx context.Context) error
+	// Run detects stale tasks every interval until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type staleTaskService struct {
+	repo        repository.StaleTaskRepository
+	preferences repository.PreferencesRepository
+	publisher   events.Publisher
+	sender      notify.Sender
+	defaultDays int
+	now         func() time.Time
+}
+
+// NewStaleTaskService creates a stale task service flagging tasks after defaultDays
+// without updates in projects that don't configure their own threshold
+func NewStaleTaskService(repo repository.StaleTaskRepository, preferences repository.PreferencesRepository, publisher events.Publisher, sender notify.Sender, defaultDays int) StaleTaskService {
+	return &staleTaskService{
+		repo:        repo,
+		preferences: preferences,
+		publisher:   publisher,
+		sender:      sender,
+		defaultDays: defaultDays,
+		now:         time.Now,
+	}
+}
+
+func (s *staleTaskService) GetProjectSettings(ctx context.Context, projectKey string) (*models.ProjectSettings, error) {
+	projectKey, err := models.NormalizeProjectKey(projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.repo.GetProjectSettings(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	s.applyDefaults(settings)
+	return settings, nil
+}
+
+func (s *staleTaskService) UpdateProjectSettings(ctx context.Context, projectKey, updatedBy string, input *models.ProjectSettingsUpdate) (*models.ProjectSettings, error) {
+	projectKey, err := models.NormalizeProjectKey(projectKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	searchLanguage := input.SearchLanguage
+	if searchLanguage == "" {
+		searchLanguage = models.DefaultSearchLanguage
+	}
+
+	// Changing the search language reindexes the project's tasks
+	settings, err := s.repo.SaveProjectSettings(ctx, &models.ProjectSettings{
+		ProjectKey:     projectKey,
+		StaleAfterDays: input.StaleAfterDays,
+		SearchLanguage: searchLanguage,
+		UpdatedBy:      updatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.applyDefaults(settings)
+	return settings, nil
+}
+
+func (s *staleTaskService) applyDefaults(settings *models.ProjectSettings) {
+	settings.EffectiveStaleAfterDays = s.defaultDays
+	if settings.StaleAfterDays != nil {
+		settings.EffectiveStaleAfterDays = *settings.StaleAfterDays
+	}
+	if settings.SearchLanguage == "" {
+		settings.SearchLanguage = models.DefaultSearchLanguage
+	}
+}
+
+func (s *staleTaskService) DetectStale(ctx context.Context) error {
+	tasks, err := s.repo.MarkStale(ctx, s.now(), s.defaultDays)
+	if err != nil {
+		return err
+	}
+
+	// One email per recipient however many of their tasks went stale
+	byRecipient := make(map[string][]*models.Task)
+	for _, task := range tasks {
+		s.publisher.Publish(events.Event{Type: events.TaskStale, TaskID: task.ID, Task: task, OrgID: task.OrgID})
+
+		recipient := task.AssigneeID
+		if recipient == "" {
+			recipient = task.OwnerID
+		}
+		if recipient != "" {
+			byRecipient[recipient] = append(byRecipient[recipient], task)
+		}
+	}
+
+	recipients := make([]string, 0, len(byRecipient))
+	for recipient := range byRecipient {
+		recipients = append(recipients, recipient)
+	}
+	sort.Strings(recipients)
+	for _, recipient := range recipients {
+		// Tasks are flagged once, so a failed alert is not retried
+		if err := s.alert(ctx, recipient, byRecipient[recipient]); err != nil {
+			log.Printf("Failed to send stale task alert to user %s: %v", recipient, err)
+		}
+	}
+	return nil
+}
+
+// alert emails the user about their stale tasks, unless they have no email address or
+// turned the alerts off
+func (s *staleTaskService) alert(ctx context.Context, userID string, tasks []*models.Task) error {
+	preferences, err := s.preferences.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if preferences.Email == "" || !preferences.StaleAlerts {
+		return nil
+	}
+
+	msg, err := notify.RenderStaleAlert(preferences.Email, tasks, preferences.Location())
+	if err != nil {
+		return err
+	}
+	return s.sender.Send(ctx, msg)
+}
+
+func (s *staleTaskService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.DetectStale(ctx); err != nil {
+				log.Printf("Failed to detect stale tasks: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+)
+
+// MockStaleTaskRepository is a mock implementation of StaleTaskRepository
+type MockStaleTaskRepository struct {
+	mock.Mock
+}
+
+func (m *MockStaleTaskRepository) GetProjectSettings(ctx context.Context, projectKey string) (*models.ProjectSettings, error) {
+	args := m.Called(ctx, projectKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProjectSettings), args.Error(1)
+}
+
+func (m *MockStaleTaskRepository) SaveProjectSettings(ctx context.Context, settings *models.ProjectSettings) (*models.ProjectSettings, error) {
+	args := m.Called(ctx, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProjectSettings), args.Error(1)
+}
+
+func (m *MockStaleTaskRepository) MarkStale(ctx context.Context, now time.Time, defaultDays int) ([]*models.Task, error) {
+	args := m.Called(ctx, now, defaultDays)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
+// recordingPublisher collects the events it is asked to publish
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(event events.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestDetectStale(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo := new(MockStaleTaskRepository)
+	preferences := new(MockPreferencesRepository)
+	publisher := &recordingPublisher{}
+	sender := &recordingSender{}
+	svc := NewStaleTaskService(repo, preferences, publisher, sender, 14).(*staleTaskService)
+	svc.now = func() time.Time { return now }
+
+	repo.On("MarkStale", ctx, now, 14).Return([]*models.Task{
+		{ID: "1", Key: "PROJ-1", AssigneeID: "alice", OwnerID: "bob"},
+		{ID: "2", Key: "PROJ-2", OwnerID: "bob"},
+		{ID: "3", Key: "PROJ-3", AssigneeID: "alice"},
+		{ID: "4", Key: "PROJ-4", AssigneeID: "carol"},
+	}, nil)
+
+	alice := models.DefaultNotificationPreferences("alice")
+	alice.Email = "alice@example.com"
+	bob := models.DefaultNotificationPreferences("bob")
+	bob.Email = "bob@example.com"
+	bob.StaleAlerts = false
+	preferences.On("Get", ctx, "alice").Return(alice, nil)
+	preferences.On("Get", ctx, "bob").Return(bob, nil)
+	// carol has no email address
+	preferences.On("Get", ctx, "carol").Return(models.DefaultNotificationPreferences("carol"), nil)
+
+	require.NoError(t, svc.DetectStale(ctx))
+
+	require.Len(t, publisher.events, 4)
+	assert.Equal(t, events.TaskStale, publisher.events[0].Type)
+	assert.Equal(t, "1", publisher.events[0].TaskID)
+
+	// alice gets one email for both her tasks; bob turned the alerts off
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "alice@example.com", sender.sent[0].To)
+	assert.Equal(t, "2 stale tasks need your attention", sender.sent[0].Subject)
+	preferences.AssertExpectations(t)
+}
+
+func TestUpdateProjectSettings(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockStaleTaskRepository)
+	svc := NewStaleTaskService(repo, new(MockPreferencesRepository), &recordingPublisher{}, &recordingSender{}, 14)
+
+	seven := 7
+	repo.On("SaveProjectSettings", ctx, &models.ProjectSettings{ProjectKey: "PROJ", StaleAfterDays: &seven,
+		SearchLanguage: models.SearchLanguageEnglish, UpdatedBy: "admin-1"}).
+		Return(&models.ProjectSettings{ProjectKey: "PROJ", StaleAfterDays: &seven, SearchLanguage: models.SearchLanguageEnglish, UpdatedBy: "admin-1"}, nil).Once()
+	repo.On("SaveProjectSettings", ctx, &models.ProjectSettings{ProjectKey: "DOCS", SearchLanguage: models.SearchLanguageGerman, UpdatedBy: "admin-1"}).
+		Return(&models.ProjectSettings{ProjectKey: "DOCS", SearchLanguage: models.SearchLanguageGerman, UpdatedBy: "admin-1"}, nil).Once()
+	repo.On("GetProjectSettings", ctx, "WEB").Return(&models.ProjectSettings{ProjectKey: "WEB"}, nil)
+
+	settings, err := svc.UpdateProjectSettings(ctx, "proj", "admin-1", &models.ProjectSettingsUpdate{StaleAfterDays: &seven})
+	require.NoError(t, err)
+	assert.Equal(t, 7, settings.EffectiveStaleAfterDays)
+
+	// Projects without their own threshold use the default
+	settings, err = svc.GetProjectSettings(ctx, "web")
+	require.NoError(t, err)
+	assert.Nil(t, settings.StaleAfterDays)
+	assert.Equal(t, 14, settings.EffectiveStaleAfterDays)
+	assert.Equal(t, models.DefaultSearchLanguage, settings.SearchLanguage)
+
+	settings, err = svc.UpdateProjectSettings(ctx, "docs", "admin-1", &models.ProjectSettingsUpdate{SearchLanguage: models.SearchLanguageGerman})
+	require.NoError(t, err)
+	assert.Equal(t, models.SearchLanguageGerman, settings.SearchLanguage)
+
+	_, err = svc.UpdateProjectSettings(ctx, "DOCS", "admin-1", &models.ProjectSettingsUpdate{SearchLanguage: "klingon"})
+	assert.Error(t, err)
+
+	tooLong := models.MaxStaleAfterDays + 1
+	_, err = svc.UpdateProjectSettings(ctx, "PROJ", "admin-1", &models.ProjectSettingsUpdate{StaleAfterDays: &tooLong})
+	assert.Error(t, err)
+	repo.AssertNumberOfCalls(t, "SaveProjectSettings", 2)
+}
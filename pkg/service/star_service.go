@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// StarService manages the tasks users star for quick access
+type StarService interface {
+	StarTask(ctx context.Context, taskID, userID string) error
+	UnstarTask(ctx context.Context, taskID, userID string) error
+}
+
+type starService struct {
+	stars repository.StarRepository
+	tasks repository.TaskRepository
+}
+
+// NewStarService creates a new star service
+func NewStarService(stars repository.StarRepository, tasks repository.TaskRepository) StarService {
+	return &starService{stars: stars, tasks: tasks}
+}
+
+func (s *starService) StarTask(ctx context.Context, taskID, userID string) error {
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return err
+	}
+
+	return s.stars.Star(ctx, taskID, userID)
+}
+
+func (s *starService) UnstarTask(ctx context.Context, taskID, userID string) error {
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return err
+	}
+
+	return s.stars.Unstar(ctx, taskID, userID)
+}
+
+// starredTaskService sets Task.Starred for the user in the request context
+type starredTaskService struct {
+	TaskService
+	stars repository.StarRepository
+}
+
+// NewStarredTaskService wraps a TaskService so returned tasks report whether the
+// requesting user starred them
+func NewStarredTaskService(next TaskService, stars repository.StarRepository) TaskService {
+	return &starredTaskService{
+		TaskService: next,
+		stars:       stars,
+	}
+}
+
+func (s *starredTaskService) GetTask(ctx context.Context, id string) (*models.Task, error) {
+	task, err := s.TaskService.GetTask(ctx, id)
+	return s.markTask(ctx, task, err)
+}
+
+func (s *starredTaskService) GetTaskByKey(ctx context.Context, key string) (*models.Task, error) {
+	task, err := s.TaskService.GetTaskByKey(ctx, key)
+	return s.markTask(ctx, task, err)
+}
+
+func (s *starredTaskService) GetTaskBySlug(ctx context.Context, projectKey, slug string) (*models.Task, error) {
+	task, err := s.TaskService.GetTaskBySlug(ctx, projectKey, slug)
+	return s.markTask(ctx, task, err)
+}
+
+func (s *starredTaskService) UpdateTask(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error) {
+	updated, err := s.TaskService.UpdateTask(ctx, id, task)
+	return s.markTask(ctx, updated, err)
+}
+
+func (s *starredTaskService) ListTasks(ctx context.Context, filter repository.TaskFilter) ([]*models.Task, int, error) {
+	tasks, total, err := s.TaskService.ListTasks(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.mark(ctx, tasks...); err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
+
+func (s *starredTaskService) ListSubtasks(ctx context.Context, id string, recursive bool) ([]*models.Task, error) {
+	tasks, err := s.TaskService.ListSubtasks(ctx, id, recursive)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mark(ctx, tasks...); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// markTask marks the result of a single-task call
+func (s *starredTaskService) markTask(ctx context.Context, task *models.Task, err error) (*models.Task, error) {
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mark(ctx, task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// mark sets Starred on tasks; without an authenticated user nothing is starred
+func (s *starredTaskService) mark(ctx context.Context, tasks ...*models.Task) error {
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil || len(tasks) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	starred, err := s.stars.StarredIDs(ctx, user.ID, ids)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		task.Starred = starred[task.ID]
+	}
+	return nil
+}
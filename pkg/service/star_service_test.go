@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// MockStarRepository is a mock implementation of StarRepository
+type MockStarRepository struct {
+	mock.Mock
+}
+
+func (m *MockStarRepository) Star(ctx context.Context, taskID, userID string) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+func (m *MockStarRepository) Unstar(ctx context.Context, taskID, userID string) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+func (m *MockStarRepository) StarredIDs(ctx context.Context, userID string, taskIDs []string) (map[string]bool, error) {
+	args := m.Called(ctx, userID, taskIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func TestStarTask(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		taskErr error
+	}{
+		{name: "stars existing task"},
+		{name: "task not found", taskErr: errors.New("task not found")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stars := new(MockStarRepository)
+			tasks := new(MockTaskRepository)
+			svc := NewStarService(stars, tasks)
+
+			if tt.taskErr != nil {
+				tasks.On("GetByID", ctx, "task-1").Return(nil, tt.taskErr)
+			} else {
+				tasks.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1"}, nil)
+			}
+			stars.On("Star", ctx, "task-1", "user-1").Return(nil).Maybe()
+
+			err := svc.StarTask(ctx, "task-1", "user-1")
+			if tt.taskErr != nil {
+				assert.Error(t, err)
+				stars.AssertNotCalled(t, "Star", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			stars.AssertExpectations(t)
+		})
+	}
+}
+
+func TestStarredTaskService_ListTasks(t *testing.T) {
+	userCtx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: "user-1"})
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantStarred []bool
+	}{
+		{
+			name:        "marks tasks starred by the caller",
+			ctx:         userCtx,
+			wantStarred: []bool{false, true},
+		},
+		{
+			name:        "nothing is starred without a user",
+			ctx:         context.Background(),
+			wantStarred: []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTaskRepository)
+			stars := new(MockStarRepository)
+			svc := NewStarredTaskService(NewTaskService(repo), stars)
+
+			repo.On("List", tt.ctx, mock.Anything).
+				Return([]*models.Task{{ID: "task-1"}, {ID: "task-2"}}, 2, nil)
+			stars.On("StarredIDs", tt.ctx, "user-1", []string{"task-1", "task-2"}).
+				Return(map[string]bool{"task-2": true}, nil).Maybe()
+
+			tasks, total, err := svc.ListTasks(tt.ctx, repository.TaskFilter{Page: 1, Limit: 10})
+
+			require.NoError(t, err)
+			assert.Equal(t, 2, total)
+			for i, task := range tasks {
+				assert.Equal(t, tt.wantStarred[i], task.Starred, task.ID)
+			}
+		})
+	}
+}
+
+func TestStarredTaskService_GetTask(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: "user-1"})
+	repo := new(MockTaskRepository)
+	stars := new(MockStarRepository)
+	svc := NewStarredTaskService(NewTaskService(repo), stars)
+
+	repo.On("GetByID", ctx, "task-1").Return(&models.Task{ID: "task-1"}, nil)
+	stars.On("StarredIDs", ctx, "user-1", []string{"task-1"}).Return(map[string]bool{"task-1": true}, nil)
+
+	task, err := svc.GetTask(ctx, "task-1")
+
+	require.NoError(t, err)
+	assert.True(t, task.Starred)
+}
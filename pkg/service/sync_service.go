@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// SyncService lets offline clients fetch task changes incrementally
+type SyncService interface {
+	// Sync returns up to limit task changes after the since cursor, with the cursor to
+	// pass next time. An empty cursor returns every task.
+	Sync(ctx context.Context, since string, limit int) (*models.SyncPage, error)
+	// Push applies edits clients made offline, resolving conflicts with tasks changed on
+	// the server since by the push's policy or the service's default one
+	Push(ctx context.Context, push *models.SyncPush) ([]*models.SyncEditResult, error)
+}
+
+type syncService struct {
+	repo      repository.SyncRepository
+	tasks     TaskService
+	ownership auth.OwnershipChecker
+	policy    models.ConflictPolicy
+}
+
+// NewSyncService creates a new sync service writing edits through tasks, which only
+// users allowed by ownership may make
+func NewSyncService(repo repository.SyncRepository, tasks TaskService, ownership auth.OwnershipChecker, policy models.ConflictPolicy) SyncService {
+	return &syncService{repo: repo, tasks: tasks, ownership: ownership, policy: policy}
+}
+
+func (s *syncService) Sync(ctx context.Context, since string, limit int) (*models.SyncPage, error) {
+	after, err := models.ParseSyncCursor(since)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 {
+		limit = models.DefaultSyncLimit
+	}
+	if limit > models.MaxSyncLimit {
+		limit = models.MaxSyncLimit
+	}
+	viewer := ""
+	if user, err := auth.GetUserFromContext(ctx); err == nil {
+		viewer = user.ID
+	}
+
+	// One extra change tells whether more are left
+	changes, bound, err := s.repo.Changes(ctx, after, viewer, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.SyncPage{Changes: changes}
+	next := models.SyncCursor{Seq: bound}
+	if len(changes) > limit {
+		page.Changes, page.HasMore = changes[:limit], true
+		last := changes[limit-1]
+		next = models.SyncCursor{Seq: last.Seq, TaskID: last.TaskID}
+	}
+	// Every change below the bound was read, so the next sync starts there
+	if next.Seq < after.Seq {
+		next = after
+	}
+	if page.Changes == nil {
+		page.Changes = []*models.SyncChange{}
+	}
+	page.Cursor = next.String()
+	return page, nil
+}
+
+func (s *syncService) Push(ctx context.Context, push *models.SyncPush) ([]*models.SyncEditResult, error) {
+	if err := push.Validate(); err != nil {
+		return nil, err
+	}
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policy := push.Policy
+	if policy == "" {
+		policy = s.policy
+	}
+
+	results := make([]*models.SyncEditResult, len(push.Edits))
+	for i, edit := range push.Edits {
+		results[i] = s.apply(ctx, user, policy, edit)
+	}
+	return results, nil
+}
+
+// apply applies one offline edit by the policy
+func (s *syncService) apply(ctx context.Context, user auth.User, policy models.ConflictPolicy, edit *models.SyncEdit) *models.SyncEditResult {
+	result := &models.SyncEditResult{TaskID: edit.TaskID}
+	fail := func(err error) *models.SyncEditResult {
+		result.Status, result.Error = models.SyncFailed, err.Error()
+		return result
+	}
+
+	current, err := s.tasks.GetTask(ctx, edit.TaskID)
+	if err != nil {
+		if edit.Op == models.SyncEditDelete {
+			// Deleted on both sides
+			result.Status = models.SyncApplied
+			return result
+		}
+		return fail(errors.New("task not found"))
+	}
+	if err := auth.CanAccessResource(ctx, edit.TaskID, s.ownership); err != nil {
+		return fail(errors.New("forbidden"))
+	}
+	result.Task = current
+
+	// Without changes on the server since the client's copy there is nothing to resolve
+	changed := current.UpdatedAt.After(edit.BaseUpdatedAt)
+	clientLast := policy == models.ConflictLastWriteWins && edit.ClientUpdatedAt.After(current.UpdatedAt)
+
+	if edit.Op == models.SyncEditDelete {
+		if changed && !clientLast {
+			result.Status, result.Error = models.SyncRejected, "task changed on the server since base_updated_at"
+			return result
+		}
+		if err := s.tasks.DeleteTask(ctx, edit.TaskID); err != nil {
+			return fail(err)
+		}
+		result.Status, result.Task = models.SyncApplied, nil
+		return result
+	}
+
+	client := syncValues(&edit.Changes)
+	base := syncValues(&edit.Base)
+	server := taskSyncValues(current)
+	var apply []string
+	for _, field := range syncFieldNames {
+		value, edited := client[field]
+		if !edited || sameSyncValue(value, server[field]) {
+			continue
+		}
+		if baseValue, ok := base[field]; !changed || (ok && sameSyncValue(baseValue, server[field])) {
+			apply = append(apply, field)
+			continue
+		}
+		result.Conflicts = append(result.Conflicts, &models.SyncConflict{
+			Field:       field,
+			ServerValue: server[field],
+			ClientValue: value,
+			Resolution:  "server",
+		})
+	}
+
+	result.Status = models.SyncApplied
+	if len(result.Conflicts) > 0 {
+		switch {
+		case clientLast:
+			for _, conflict := range result.Conflicts {
+				conflict.Resolution = "client"
+				apply = append(apply, conflict.Field)
+			}
+		case policy == models.ConflictFieldMerge && len(apply) > 0:
+			result.Status = models.SyncMerged
+		default:
+			result.Status = models.SyncRejected
+			return result
+		}
+	}
+	if len(apply) == 0 {
+		return result
+	}
+
+	update := syncUpdate(&edit.Changes, apply)
+	update.EditorID = user.ID
+	updated, err := s.tasks.UpdateTask(ctx, edit.TaskID, update)
+	if err != nil {
+		return fail(err)
+	}
+	result.Task = updated
+	return result
+}
+
+// syncFieldNames are the fields of models.SyncFields, in the order conflicts are reported
+var syncFieldNames = []string{"title", "description", "status", "priority", "due_date", "assignee_id", "tags"}
+
+// syncValues returns the fields set in fields by name
+func syncValues(fields *models.SyncFields) map[string]interface{} {
+	values := make(map[string]interface{})
+	if fields.Title != nil {
+		values["title"] = *fields.Title
+	}
+	if fields.Description != nil {
+		values["description"] = *fields.Description
+	}
+	if fields.Status != nil {
+		values["status"] = *fields.Status
+	}
+	if fields.Priority != nil {
+		values["priority"] = *fields.Priority
+	}
+	if fields.DueDate != nil {
+		values["due_date"] = *fields.DueDate
+	}
+	if fields.AssigneeID != nil {
+		values["assignee_id"] = *fields.AssigneeID
+	}
+	if fields.Tags != nil {
+		values["tags"] = *fields.Tags
+	}
+	return values
+}
+
+// taskSyncValues returns a task's values of the syncable fields by name
+func taskSyncValues(task *models.Task) map[string]interface{} {
+	return syncValues(&models.SyncFields{
+		Title:       &task.Title,
+		Description: &task.Description,
+		Status:      &task.Status,
+		Priority:    &task.Priority,
+		DueDate:     &task.DueDate,
+		AssigneeID:  &task.AssigneeID,
+		Tags:        &task.Tags,
+	})
+}
+
+// sameSyncValue compares field values, times by instant and tags in any order
+func sameSyncValue(a, b interface{}) bool {
+	switch a := a.(type) {
+	case time.Time:
+		b, ok := b.(time.Time)
+		return ok && a.Equal(b)
+	case []string:
+		b, ok := b.([]string)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		sortedA := append([]string(nil), a...)
+		sortedB := append([]string(nil), b...)
+		sort.Strings(sortedA)
+		sort.Strings(sortedB)
+		return reflect.DeepEqual(sortedA, sortedB)
+	}
+	return a == b
+}
+
+// syncUpdate builds the update applying the named fields of changes
+func syncUpdate(changes *models.SyncFields, fields []string) *models.TaskUpdate {
+	update := &models.TaskUpdate{}
+	for _, field := range fields {
+		switch field {
+		case "title":
+			update.Title = changes.Title
+		case "description":
+			update.Description = changes.Description
+		case "status":
+			update.Status = changes.Status
+		case "priority":
+			update.Priority = changes.Priority
+		case "due_date":
+			update.DueDate = changes.DueDate
+		case "assignee_id":
+			update.AssigneeID = changes.AssigneeID
+		case "tags":
+			update.Tags = changes.Tags
+		}
+	}
+	return update
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+)
+
+// MockSyncRepository is a mock implementation of SyncRepository
+type MockSyncRepository struct {
+	mock.Mock
+}
+
+func (m *MockSyncRepository) Changes(ctx context.Context, after models.SyncCursor, viewer string, limit int) ([]*models.SyncChange, int64, error) {
+	args := m.Called(ctx, after, viewer, limit)
+	return args.Get(0).([]*models.SyncChange), args.Get(1).(int64), args.Error(2)
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: "user-1"})
+	repo := new(MockSyncRepository)
+	svc := NewSyncService(repo, nil, nil, models.ConflictFieldMerge)
+
+	changes := []*models.SyncChange{
+		{Op: models.SyncUpsert, TaskID: "task-1", Task: &models.Task{ID: "task-1"}, Seq: 10},
+		{Op: models.SyncDelete, TaskID: "task-2", Seq: 12},
+		{Op: models.SyncUpsert, TaskID: "task-3", Task: &models.Task{ID: "task-3"}, Seq: 12},
+	}
+
+	// A first sync asks for one more change than the limit to tell whether more are left
+	repo.On("Changes", ctx, models.SyncCursor{}, "user-1", 3).Return(changes, int64(20), nil).Once()
+	page, err := svc.Sync(ctx, "", 2)
+	require.NoError(t, err)
+	assert.Equal(t, changes[:2], page.Changes)
+	assert.True(t, page.HasMore)
+	assert.Equal(t, models.SyncCursor{Seq: 12, TaskID: "task-2"}.String(), page.Cursor)
+
+	// Once caught up, the next sync starts at the bound
+	repo.On("Changes", ctx, models.SyncCursor{Seq: 12, TaskID: "task-2"}, "user-1", 3).Return(changes[2:], int64(20), nil).Once()
+	page, err = svc.Sync(ctx, page.Cursor, 2)
+	require.NoError(t, err)
+	assert.Equal(t, changes[2:], page.Changes)
+	assert.False(t, page.HasMore)
+	assert.Equal(t, models.SyncCursor{Seq: 20}.String(), page.Cursor)
+
+	_, err = svc.Sync(ctx, "not a cursor", 0)
+	assert.ErrorIs(t, err, models.ErrInvalidSyncCursor)
+	repo.AssertExpectations(t)
+}
+
+func TestSync_DefaultLimitAndEmptyPage(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockSyncRepository)
+	svc := NewSyncService(repo, nil, nil, models.ConflictFieldMerge)
+
+	after := models.SyncCursor{Seq: 30}
+	repo.On("Changes", ctx, after, "", models.DefaultSyncLimit+1).Return([]*models.SyncChange(nil), int64(30), nil).Once()
+
+	page, err := svc.Sync(ctx, after.String(), 0)
+	require.NoError(t, err)
+	assert.Empty(t, page.Changes)
+	assert.NotNil(t, page.Changes)
+	assert.Equal(t, after.String(), page.Cursor)
+}
+
+// offlineTaskService serves one task and records the updates and deletes made to it
+type offlineTaskService struct {
+	TaskService
+	task    *models.Task
+	updates []*models.TaskUpdate
+	deleted bool
+}
+
+func (s *offlineTaskService) GetTask(ctx context.Context, id string) (*models.Task, error) {
+	if s.task == nil || s.deleted {
+		return nil, errors.New("task not found")
+	}
+	return s.task, nil
+}
+
+func (s *offlineTaskService) UpdateTask(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error) {
+	s.updates = append(s.updates, task)
+	return s.task, nil
+}
+
+func (s *offlineTaskService) DeleteTask(ctx context.Context, id string) error {
+	s.deleted = true
+	return nil
+}
+
+func TestPush(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: "user-1"})
+	synced := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	serverEdit := synced.Add(time.Hour)
+	str := func(value string) *string { return &value }
+	priority := func(value models.TaskPriority) *models.TaskPriority { return &value }
+
+	// The server renamed the task after the client synced it
+	serverTask := func() *models.Task {
+		return &models.Task{ID: "task-1", OwnerID: "user-1", Title: "Server title", Priority: models.PriorityLow, UpdatedAt: serverEdit}
+	}
+	edit := func(clientEdit time.Time) *models.SyncEdit {
+		return &models.SyncEdit{
+			TaskID:          "task-1",
+			BaseUpdatedAt:   synced,
+			ClientUpdatedAt: clientEdit,
+			Changes:         models.SyncFields{Title: str("Client title"), Priority: priority(models.PriorityHigh)},
+			Base:            models.SyncFields{Title: str("Original title"), Priority: priority(models.PriorityLow)},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		policy        models.ConflictPolicy
+		edit          *models.SyncEdit
+		task          *models.Task
+		wantStatus    string
+		wantUpdate    *models.TaskUpdate
+		wantConflicts []*models.SyncConflict
+	}{
+		{
+			name:       "unchanged on the server",
+			policy:     models.ConflictServerWins,
+			edit:       edit(synced),
+			task:       &models.Task{ID: "task-1", OwnerID: "user-1", UpdatedAt: synced},
+			wantStatus: models.SyncApplied,
+			wantUpdate: &models.TaskUpdate{Title: str("Client title"), Priority: priority(models.PriorityHigh), EditorID: "user-1"},
+		},
+		{
+			name:       "field merge applies fields the server left alone",
+			policy:     models.ConflictFieldMerge,
+			edit:       edit(synced),
+			task:       serverTask(),
+			wantStatus: models.SyncMerged,
+			wantUpdate: &models.TaskUpdate{Priority: priority(models.PriorityHigh), EditorID: "user-1"},
+			wantConflicts: []*models.SyncConflict{
+				{Field: "title", ServerValue: "Server title", ClientValue: "Client title", Resolution: "server"},
+			},
+		},
+		{
+			name:       "server wins drops the edit",
+			policy:     models.ConflictServerWins,
+			edit:       edit(serverEdit.Add(time.Minute)),
+			task:       serverTask(),
+			wantStatus: models.SyncRejected,
+			wantConflicts: []*models.SyncConflict{
+				{Field: "title", ServerValue: "Server title", ClientValue: "Client title", Resolution: "server"},
+			},
+		},
+		{
+			name:       "last write wins with a later client edit",
+			policy:     models.ConflictLastWriteWins,
+			edit:       edit(serverEdit.Add(time.Minute)),
+			task:       serverTask(),
+			wantStatus: models.SyncApplied,
+			wantUpdate: &models.TaskUpdate{Title: str("Client title"), Priority: priority(models.PriorityHigh), EditorID: "user-1"},
+			wantConflicts: []*models.SyncConflict{
+				{Field: "title", ServerValue: "Server title", ClientValue: "Client title", Resolution: "client"},
+			},
+		},
+		{
+			name:       "last write wins with an earlier client edit",
+			policy:     models.ConflictLastWriteWins,
+			edit:       edit(synced.Add(time.Minute)),
+			task:       serverTask(),
+			wantStatus: models.SyncRejected,
+			wantConflicts: []*models.SyncConflict{
+				{Field: "title", ServerValue: "Server title", ClientValue: "Client title", Resolution: "server"},
+			},
+		},
+		{
+			name:       "someone else's task",
+			policy:     models.ConflictLastWriteWins,
+			edit:       edit(synced),
+			task:       &models.Task{ID: "task-1", OwnerID: "user-2", UpdatedAt: synced},
+			wantStatus: models.SyncFailed,
+		},
+		{
+			name:       "delete of a task changed on the server",
+			policy:     models.ConflictFieldMerge,
+			edit:       &models.SyncEdit{TaskID: "task-1", Op: models.SyncEditDelete, BaseUpdatedAt: synced},
+			task:       serverTask(),
+			wantStatus: models.SyncRejected,
+		},
+		{
+			name:       "delete of a task deleted on the server",
+			policy:     models.ConflictFieldMerge,
+			edit:       &models.SyncEdit{TaskID: "task-1", Op: models.SyncEditDelete, BaseUpdatedAt: synced},
+			wantStatus: models.SyncApplied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := &offlineTaskService{task: tt.task}
+			svc := NewSyncService(new(MockSyncRepository), tasks, TaskOwnership(taskRepoWith(tt.task), nil), models.ConflictFieldMerge)
+
+			results, err := svc.Push(ctx, &models.SyncPush{Policy: tt.policy, Edits: []*models.SyncEdit{tt.edit}})
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+
+			assert.Equal(t, tt.wantStatus, results[0].Status, results[0].Error)
+			assert.Equal(t, tt.wantConflicts, results[0].Conflicts)
+			if tt.wantUpdate == nil {
+				assert.Empty(t, tasks.updates)
+				return
+			}
+			assert.Equal(t, []*models.TaskUpdate{tt.wantUpdate}, tasks.updates)
+		})
+	}
+}
+
+// taskRepoWith is a task repository serving task, for ownership checks
+func taskRepoWith(task *models.Task) *MockTaskRepository {
+	repo := new(MockTaskRepository)
+	if task != nil {
+		repo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	}
+	return repo
+}
+
+func TestPush_Validation(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: "user-1"})
+	svc := NewSyncService(new(MockSyncRepository), &offlineTaskService{}, nil, models.ConflictFieldMerge)
+
+	for _, push := range []*models.SyncPush{
+		{},
+		{Policy: "client_wins", Edits: []*models.SyncEdit{{TaskID: "task-1", BaseUpdatedAt: time.Now()}}},
+		{Edits: []*models.SyncEdit{{TaskID: "task-1"}}},
+		{Edits: []*models.SyncEdit{{TaskID: "task-1", Op: "move", BaseUpdatedAt: time.Now()}}},
+	} {
+		_, err := svc.Push(ctx, push)
+		assert.Error(t, err)
+	}
+
+	_, err := svc.Push(context.Background(), &models.SyncPush{Edits: []*models.SyncEdit{{TaskID: "task-1", BaseUpdatedAt: time.Now()}}})
+	assert.ErrorIs(t, err, auth.ErrUserNotFound)
+}
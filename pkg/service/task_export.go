@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// errExportPaused stops a streamed export whose pacing limits were reached
+var errExportPaused = errors.New("export paused")
+
+// pacedWriter tracks how long a streamed export has been running and how long it spent
+// waiting for the client to accept its writes
+type pacedWriter struct {
+	w       io.Writer
+	pacing  models.ExportPacing
+	now     func() time.Time
+	start   time.Time
+	stalled time.Duration
+}
+
+func newPacedWriter(w io.Writer, pacing models.ExportPacing, now func() time.Time) *pacedWriter {
+	return &pacedWriter{w: w, pacing: pacing, now: now, start: now()}
+}
+
+func (p *pacedWriter) Write(b []byte) (int, error) {
+	start := p.now()
+	n, err := p.w.Write(b)
+	p.stalled += p.now().Sub(start)
+	return n, err
+}
+
+// exhausted reports whether the export has used up its time or its stall allowance
+func (p *pacedWriter) exhausted() bool {
+	if p.pacing.MaxDuration > 0 && p.now().Sub(p.start) >= p.pacing.MaxDuration {
+		return true
+	}
+	return p.pacing.MaxStall > 0 && p.stalled >= p.pacing.MaxStall
+}
+
+// taskStreamEncoder writes the tasks of a streamed export in one format. Writes are
+// buffered, so a stream failing early leaves the destination untouched.
+type taskStreamEncoder interface {
+	Encode(task *models.Task) error
+	// Close ends the export, with the cursor to resume from when it was cut short
+	Close(next *models.ExportCursor) error
+}
+
+func newTaskStreamEncoder(format models.ExportFormat, w io.Writer) taskStreamEncoder {
+	switch format {
+	case models.ExportJSON:
+		return &taskJSONEncoder{w: bufio.NewWriter(w)}
+	case models.ExportNDJSON:
+		return &taskNDJSONEncoder{w: bufio.NewWriter(w)}
+	default:
+		return &taskCSVEncoder{newTaskCSVWriter(w)}
+	}
+}
+
+// taskCSVEncoder writes CSV rows; CSV has no room for a cursor, which the API sends in a
+// trailer instead
+type taskCSVEncoder struct {
+	*taskCSVWriter
+}
+
+func (e *taskCSVEncoder) Encode(task *models.Task) error {
+	return e.Write(task)
+}
+
+func (e *taskCSVEncoder) Close(next *models.ExportCursor) error {
+	return e.Flush()
+}
+
+// taskJSONEncoder writes a JSON object with a "tasks" array and, when cut short, a
+// "next_cursor"
+type taskJSONEncoder struct {
+	w       *bufio.Writer
+	started bool
+}
+
+func (e *taskJSONEncoder) Encode(task *models.Task) error {
+	prefix := ","
+	if !e.started {
+		prefix = `{"tasks":[`
+		e.started = true
+	}
+	if _, err := e.w.WriteString(prefix); err != nil {
+		return err
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *taskJSONEncoder) Close(next *models.ExportCursor) error {
+	if !e.started {
+		if _, err := e.w.WriteString(`{"tasks":[`); err != nil {
+			return err
+		}
+	}
+	if _, err := e.w.WriteString("]"); err != nil {
+		return err
+	}
+	if next != nil {
+		if _, err := e.w.WriteString(`,"next_cursor":"` + next.String() + `"`); err != nil {
+			return err
+		}
+	}
+	if _, err := e.w.WriteString("}\n"); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// taskNDJSONEncoder writes a task per line and, when cut short, a last
+// {"next_cursor": ...} line
+type taskNDJSONEncoder struct {
+	w *bufio.Writer
+}
+
+func (e *taskNDJSONEncoder) Encode(task *models.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(data, '\n'))
+	return err
+}
+
+func (e *taskNDJSONEncoder) Close(next *models.ExportCursor) error {
+	if next != nil {
+		if _, err := e.w.WriteString(`{"next_cursor":"` + next.String() + `"}` + "\n"); err != nil {
+			return err
+		}
+	}
+	return e.w.Flush()
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"sample/task-management-system/pkg/importer"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/moderation"
+	"sample/task-management-system/pkg/repository"
+)
+
+// taskImportBatchSize is the number of tasks created per transaction
+const taskImportBatchSize = 100
+
+// TaskImportService creates tasks from small CSV files while the caller waits
+type TaskImportService interface {
+	// ImportTasks creates a task owned by ownerID for every valid row of a CSV file and
+	// reports the outcome of each row
+	ImportTasks(ctx context.Context, ownerID string, options *models.ImportOptions, content []byte) (*models.TaskImportResult, error)
+}
+
+type taskImportService struct {
+	repo      repository.TaskRepository
+	moderator *moderation.Moderator
+}
+
+// NewTaskImportService creates a task import service. Rows are validated and moderated
+// like tasks created through the API, then created in batches.
+func NewTaskImportService(repo repository.TaskRepository, moderator *moderation.Moderator) TaskImportService {
+	return &taskImportService{repo: repo, moderator: moderator}
+}
+
+// importedRow is a valid row waiting to be created
+type importedRow struct {
+	row        int
+	task       *models.TaskCreate
+	moderation *moderation.Result
+}
+
+func (s *taskImportService) ImportTasks(ctx context.Context, ownerID string, options *models.ImportOptions, content []byte) (*models.TaskImportResult, error) {
+	if ownerID == "" {
+		return nil, errors.New("imports need a user")
+	}
+	options.Source = models.ImportCSV
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	if len(content) > models.MaxTaskImportBytes {
+		return nil, fmt.Errorf("file is larger than %d bytes; use an import job for larger files", models.MaxTaskImportBytes)
+	}
+
+	reader, err := importer.NewReader(bytes.NewReader(content), *options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every row is checked before any is created, so an oversized file creates nothing
+	result := &models.TaskImportResult{Rows: []models.ImportRowResult{}}
+	var pending []importedRow
+	for {
+		task, row, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if row > models.MaxTaskImportRows {
+			return nil, fmt.Errorf("file has more than %d rows; use an import job for larger files", models.MaxTaskImportRows)
+		}
+
+		result.Rows = append(result.Rows, models.ImportRowResult{Row: row})
+		var rowErr *importer.RowError
+		switch {
+		case errors.As(err, &rowErr):
+			err = rowErr.Err
+		case err != nil:
+			return nil, err
+		default:
+			var checked *moderation.Result
+			checked, err = s.check(ctx, ownerID, task)
+			if err == nil {
+				pending = append(pending, importedRow{row: row, task: task, moderation: checked})
+			}
+		}
+		if err != nil {
+			result.Rows[row-1].Error = err.Error()
+		}
+	}
+	if len(result.Rows) == 0 {
+		return nil, errors.New("file has no rows to import")
+	}
+
+	for start := 0; start < len(pending); start += taskImportBatchSize {
+		end := start + taskImportBatchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		if err := s.create(ctx, pending[start:end], result); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, row := range result.Rows {
+		if row.Error != "" {
+			result.Failed++
+		} else {
+			result.Created++
+		}
+	}
+	return result, nil
+}
+
+// check validates and moderates a row's task as it would be on its own
+func (s *taskImportService) check(ctx context.Context, ownerID string, task *models.TaskCreate) (*moderation.Result, error) {
+	task.OwnerID = ownerID
+	if err := task.Validate(); err != nil {
+		return nil, err
+	}
+	return s.moderator.Check(ctx, task.Title, task.Description)
+}
+
+// create creates a batch of rows in one transaction. Should the batch fail, its rows are
+// created one at a time so only the rows at fault are reported as failed.
+func (s *taskImportService) create(ctx context.Context, batch []importedRow, result *models.TaskImportResult) error {
+	tasks := make([]*models.TaskCreate, len(batch))
+	for i, row := range batch {
+		tasks[i] = row.task
+	}
+
+	created, err := s.repo.CreateBatch(ctx, tasks)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err == nil {
+		for i, row := range batch {
+			s.record(ctx, row, created[i], result)
+		}
+		return nil
+	}
+
+	log.Printf("Failed to create import batch, creating its %d tasks one at a time: %v", len(batch), err)
+	for _, row := range batch {
+		task, err := s.repo.Create(ctx, row.task)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			result.Rows[row.row-1].Error = err.Error()
+			continue
+		}
+		s.record(ctx, row, task, result)
+	}
+	return nil
+}
+
+// record reports a row's task as created and queues it for review if moderation flagged it
+func (s *taskImportService) record(ctx context.Context, row importedRow, task *models.Task, result *models.TaskImportResult) {
+	result.Rows[row.row-1].TaskID = task.ID
+	if err := s.moderator.Flag(ctx, "task", task.ID, row.moderation); err != nil {
+		log.Printf("Failed to queue task %s for moderation review: %v", task.ID, err)
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/moderation"
+)
+
+func newTestTaskImportService(tasks *MockTaskRepository) TaskImportService {
+	moderator := moderation.NewModerator(moderation.NewHeuristicChecker([]string{"casino"}, 0), moderation.ModeBlock, nil)
+	return NewTaskImportService(tasks, moderator)
+}
+
+func TestImportTasks_ReportsEveryRow(t *testing.T) {
+	ctx := context.Background()
+	tasks := new(MockTaskRepository)
+	svc := newTestTaskImportService(tasks)
+
+	content := []byte("Name,Due,priority\n" +
+		"Write docs,2099-01-15,high\n" +
+		",2099-01-15,\n" +
+		"Visit the casino,2099-01-15,\n" +
+		"Tidy up,2099-02-01,urgent\n" +
+		"Release,2099-03-01,\n")
+	tasks.On("CreateBatch", ctx, mock.MatchedBy(func(batch []*models.TaskCreate) bool {
+		return len(batch) == 2 && batch[0].Title == "Write docs" && batch[1].Title == "Release" &&
+			batch[0].OwnerID == "user-1" && batch[0].Priority == models.PriorityHigh && batch[1].ProjectKey == "OPS"
+	})).Return([]*models.Task{{ID: "task-1"}, {ID: "task-5"}}, nil).Once()
+
+	result, err := svc.ImportTasks(ctx, "user-1", &models.ImportOptions{
+		ProjectKey: "ops",
+		Columns:    map[string]string{"Name": "title", "Due": "due_date"},
+	}, content)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 3, result.Failed)
+	require.Len(t, result.Rows, 5)
+	assert.Equal(t, models.ImportRowResult{Row: 1, TaskID: "task-1"}, result.Rows[0])
+	assert.Equal(t, "title is required", result.Rows[1].Error)
+	assert.Contains(t, result.Rows[2].Error, "rejected by moderation")
+	assert.Equal(t, "invalid priority", result.Rows[3].Error)
+	assert.Equal(t, models.ImportRowResult{Row: 5, TaskID: "task-5"}, result.Rows[4])
+	tasks.AssertExpectations(t)
+}
+
+func TestImportTasks_FailedBatchCreatedOneByOne(t *testing.T) {
+	ctx := context.Background()
+	tasks := new(MockTaskRepository)
+	svc := newTestTaskImportService(tasks)
+
+	content := []byte("title,due_date,assignee_id\nFirst,2099-01-15,\nSecond,2099-01-15,missing-user\n")
+	tasks.On("CreateBatch", ctx, mock.Anything).Return(nil, errors.New("violates foreign key constraint")).Once()
+	tasks.On("Create", ctx, mock.MatchedBy(func(task *models.TaskCreate) bool { return task.Title == "First" })).
+		Return(&models.Task{ID: "task-1"}, nil).Once()
+	tasks.On("Create", ctx, mock.MatchedBy(func(task *models.TaskCreate) bool { return task.Title == "Second" })).
+		Return(nil, errors.New("violates foreign key constraint")).Once()
+
+	result, err := svc.ImportTasks(ctx, "user-1", &models.ImportOptions{}, content)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, "task-1", result.Rows[0].TaskID)
+	assert.Contains(t, result.Rows[1].Error, "foreign key")
+	tasks.AssertExpectations(t)
+}
+
+func TestImportTasks_RejectsLargeFiles(t *testing.T) {
+	ctx := context.Background()
+	tasks := new(MockTaskRepository)
+	svc := newTestTaskImportService(tasks)
+
+	var content strings.Builder
+	content.WriteString("title,due_date\n")
+	for i := 0; i <= models.MaxTaskImportRows; i++ {
+		fmt.Fprintf(&content, "Task %d,2099-01-15\n", i)
+	}
+
+	_, err := svc.ImportTasks(ctx, "user-1", &models.ImportOptions{}, []byte(content.String()))
+	assert.ErrorContains(t, err, "import job")
+	_, err = svc.ImportTasks(ctx, "user-1", &models.ImportOptions{}, []byte("title,due_date\n"))
+	assert.Error(t, err)
+	tasks.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
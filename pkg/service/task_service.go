@@ -3,7 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"io"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
+
+	"sample/task-management-system/pkg/auth"
 	"sample/task-management-system/pkg/models"
 	"sample/task-management-system/pkg/repository"
 )
@@ -12,18 +18,42 @@ import (
 type TaskService interface {
 	CreateTask(ctx context.Context, task *models.TaskCreate) (*models.Task, error)
 	GetTask(ctx context.Context, id string) (*models.Task, error)
+	GetTaskByKey(ctx context.Context, key string) (*models.Task, error)
+	GetTaskBySlug(ctx context.Context, projectKey, slug string) (*models.Task, error)
 	UpdateTask(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error)
 	DeleteTask(ctx context.Context, id string) error
-	ListTasks(ctx context.Context, status models.TaskStatus, page, limit int) ([]*models.Task, int, error)
+	ListTasks(ctx context.Context, filter repository.TaskFilter) ([]*models.Task, int, error)
+	// ExportTasks writes every task matching filter to w as CSV, as NDJSON or as a JSON
+	// object with a "tasks" array, reading them from the repository as it goes. An export
+	// that reaches the pacing limits stops early and returns the cursor to resume it from,
+	// which also ends JSON and NDJSON output; a complete export returns no cursor.
+	ExportTasks(ctx context.Context, filter repository.TaskFilter, format models.ExportFormat, w io.Writer, pacing models.ExportPacing) (*models.ExportCursor, error)
+	ListSubtasks(ctx context.Context, id string, recursive bool) ([]*models.Task, error)
+	ListTags(ctx context.Context) ([]models.TagCount, error)
+	// SaveDraft creates or replaces the caller's draft with the given ID
+	SaveDraft(ctx context.Context, id string, draft *models.TaskDraft) (*models.Task, error)
+	// PublishDraft validates the caller's draft as a new task and makes it visible
+	PublishDraft(ctx context.Context, id string) (*models.Task, error)
 }
 
+// ErrTaskCycle is returned when re-parenting a task would nest it under itself
+var ErrTaskCycle = errors.New("a task cannot be nested under itself or its subtasks")
+
+// ErrNotDraft is returned when saving or publishing a draft whose ID belongs to a
+// published task
+var ErrNotDraft = errors.New("task is not a draft")
+
+// errTaskNotFound hides other users' drafts
+var errTaskNotFound = errors.New("task not found")
+
 type taskService struct {
 	repo repository.TaskRepository
+	now  func() time.Time
 }
 
 // NewTaskService creates a new task service
 func NewTaskService(repo repository.TaskRepository) TaskService {
-	return &taskService{repo: repo}
+	return &taskService{repo: repo, now: time.Now}
 }
 
 func (s *taskService) CreateTask(ctx context.Context, task *models.TaskCreate) (*models.Task, error) {
@@ -31,6 +61,12 @@ func (s *taskService) CreateTask(ctx context.Context, task *models.TaskCreate) (
 		return nil, err
 	}
 
+	if task.ParentID != "" {
+		if _, err := s.repo.GetByID(ctx, task.ParentID); err != nil {
+			return nil, errors.New("parent task not found")
+		}
+	}
+
 	return s.repo.Create(ctx, task)
 }
 
@@ -39,7 +75,31 @@ func (s *taskService) GetTask(ctx context.Context, id string) (*models.Task, err
 		return nil, errors.New("id is required")
 	}
 
-	return s.repo.GetByID(ctx, id)
+	task, err := s.repo.GetByID(ctx, id)
+	return visibleTask(ctx, task, err)
+}
+
+func (s *taskService) GetTaskByKey(ctx context.Context, key string) (*models.Task, error) {
+	projectKey, number, err := models.ParseTaskKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.repo.GetByKey(ctx, projectKey, number)
+	return visibleTask(ctx, task, err)
+}
+
+func (s *taskService) GetTaskBySlug(ctx context.Context, projectKey, slug string) (*models.Task, error) {
+	projectKey, err := models.NormalizeProjectKey(projectKey)
+	if err != nil {
+		return nil, err
+	}
+	if slug == "" {
+		return nil, errors.New("slug is required")
+	}
+
+	task, err := s.repo.GetBySlug(ctx, projectKey, strings.ToLower(slug))
+	return visibleTask(ctx, task, err)
 }
 
 func (s *taskService) UpdateTask(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error) {
@@ -51,7 +111,45 @@ func (s *taskService) UpdateTask(ctx context.Context, id string, task *models.Ta
 		return nil, err
 	}
 
-	return s.repo.Update(ctx, id, task)
+	if task.ParentID != nil && *task.ParentID != "" {
+		if err := s.checkParent(ctx, id, *task.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.Update(ctx, id, task)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.CascadeStatus {
+		if _, err := s.repo.UpdateSubtaskStatus(ctx, id, *task.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	return updated, nil
+}
+
+// checkParent verifies parentID exists and isn't the task itself or one of its subtasks
+func (s *taskService) checkParent(ctx context.Context, id, parentID string) error {
+	if parentID == id {
+		return ErrTaskCycle
+	}
+	if _, err := s.repo.GetByID(ctx, parentID); err != nil {
+		return errors.New("parent task not found")
+	}
+
+	ancestors, err := s.repo.ListAncestorIDs(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	for _, ancestorID := range ancestors {
+		if ancestorID == id {
+			return ErrTaskCycle
+		}
+	}
+	return nil
 }
 
 func (s *taskService) DeleteTask(ctx context.Context, id string) error {
@@ -62,18 +160,22 @@ func (s *taskService) DeleteTask(ctx context.Context, id string) error {
 	return s.repo.Delete(ctx, id)
 }
 
-func (s *taskService) ListTasks(ctx context.Context, status models.TaskStatus, page, limit int) ([]*models.Task, int, error) {
-	if page < 1 {
-		page = 1
+func (s *taskService) ListTasks(ctx context.Context, filter repository.TaskFilter) ([]*models.Task, int, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, 0, err
 	}
-	if limit < 1 {
-		limit = 10
+	if filter.Page < 1 {
+		filter.Page = 1
 	}
-
-	filter := repository.TaskFilter{
-		Status: status,
-		Page:   page,
-		Limit:  limit,
+	if filter.Limit < 1 {
+		filter.Limit = models.DefaultPageSize
+	}
+	filter.Viewer = ""
+	if user, err := auth.GetUserFromContext(ctx); err == nil {
+		filter.Viewer = user.ID
+	}
+	if projectKey := projectRestriction(ctx); projectKey != "" {
+		filter.ProjectKey = projectKey
 	}
 
 	tasks, total, err := s.repo.List(ctx, filter)
@@ -82,4 +184,199 @@ func (s *taskService) ListTasks(ctx context.Context, status models.TaskStatus, p
 	}
 
 	return tasks, total, nil
-} 
\ No newline at end of file
+}
+
+func (s *taskService) ExportTasks(ctx context.Context, filter repository.TaskFilter, format models.ExportFormat, w io.Writer, pacing models.ExportPacing) (*models.ExportCursor, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if format != models.ExportCSV && format != models.ExportJSON && format != models.ExportNDJSON {
+		return nil, errors.New("format must be csv, json or ndjson")
+	}
+	filter.Viewer = ""
+	if user, err := auth.GetUserFromContext(ctx); err == nil {
+		filter.Viewer = user.ID
+	}
+	if projectKey := projectRestriction(ctx); projectKey != "" {
+		filter.ProjectKey = projectKey
+	}
+
+	paced := newPacedWriter(w, pacing, s.now)
+	encoder := newTaskStreamEncoder(format, paced)
+	delivered := 0
+	err := s.repo.Stream(ctx, filter, func(task *models.Task) error {
+		// Every call delivers at least one task, so resuming always makes progress
+		if delivered > 0 && paced.exhausted() {
+			return errExportPaused
+		}
+		if err := encoder.Encode(task); err != nil {
+			return err
+		}
+		delivered++
+		return nil
+	})
+
+	// The cursor is closed by now, so a slow client only holds on to its own connection
+	var next *models.ExportCursor
+	if errors.Is(err, errExportPaused) {
+		next = &models.ExportCursor{Offset: filter.Offset + delivered}
+	} else if err != nil {
+		return nil, err
+	}
+	return next, encoder.Close(next)
+}
+
+func (s *taskService) ListSubtasks(ctx context.Context, id string, recursive bool) ([]*models.Task, error) {
+	if id == "" {
+		return nil, errors.New("id is required")
+	}
+
+	if _, err := s.GetTask(ctx, id); err != nil {
+		return nil, err
+	}
+
+	subtasks, err := s.repo.ListSubtasks(ctx, id, recursive)
+	if err != nil {
+		return nil, err
+	}
+	visible := subtasks[:0]
+	for _, task := range subtasks {
+		if canSee(ctx, task) {
+			visible = append(visible, task)
+		}
+	}
+	return visible, nil
+}
+
+func (s *taskService) ListTags(ctx context.Context) ([]models.TagCount, error) {
+	return s.repo.ListTags(ctx)
+}
+
+func (s *taskService) SaveDraft(ctx context.Context, id string, draft *models.TaskDraft) (*models.Task, error) {
+	// Clients pick the ID of a new draft so autosaves can be retried safely
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("draft id must be a UUID")
+	}
+	if draft.OwnerID == "" {
+		return nil, errors.New("drafts need an owner")
+	}
+	if err := draft.Validate(); err != nil {
+		return nil, err
+	}
+
+	// A lookup error means there is no task yet and the draft is created
+	if existing, err := s.repo.GetByID(ctx, id); err == nil {
+		if existing.OwnerID != draft.OwnerID {
+			return nil, errTaskNotFound
+		}
+		if existing.Status != models.StatusDraft {
+			return nil, ErrNotDraft
+		}
+		if existing.ProjectKey != draft.ProjectKey {
+			return nil, errors.New("the project of a draft cannot be changed")
+		}
+	}
+
+	return s.repo.SaveDraft(ctx, id, draft)
+}
+
+func (s *taskService) PublishDraft(ctx context.Context, id string) (*models.Task, error) {
+	task, err := s.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.Status != models.StatusDraft {
+		return nil, ErrNotDraft
+	}
+
+	// Publishing runs the same checks as creating a task
+	create := &models.TaskCreate{
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    task.Priority,
+		DueDate:     task.DueDate,
+		AssigneeID:  task.AssigneeID,
+		ParentID:    task.ParentID,
+		Tags:        task.Tags,
+		Metadata:    task.Metadata,
+		ProjectKey:  task.ProjectKey,
+	}
+	if err := create.Validate(); err != nil {
+		return nil, err
+	}
+	if create.ParentID != "" {
+		if _, err := s.GetTask(ctx, create.ParentID); err != nil {
+			return nil, errors.New("parent task not found")
+		}
+	}
+
+	return s.repo.PublishDraft(ctx, id, create.Status)
+}
+
+// canSee reports whether the user in ctx may see a task: drafts are visible only to
+// their owner
+func canSee(ctx context.Context, task *models.Task) bool {
+	if projectKey := projectRestriction(ctx); projectKey != "" && task.ProjectKey != projectKey {
+		return false
+	}
+	if task.Status != models.StatusDraft {
+		return true
+	}
+	user, err := auth.GetUserFromContext(ctx)
+	return err == nil && user.ID == task.OwnerID
+}
+
+// projectRestriction returns the only project the caller may see, such as a guest link's,
+// or "" if they may see every project
+func projectRestriction(ctx context.Context) string {
+	user, err := auth.GetUserFromContext(ctx)
+	if err != nil || user.ProjectID == "" {
+		return ""
+	}
+	return strings.ToUpper(user.ProjectID)
+}
+
+// visibleTask passes on the result of a single-task lookup, hiding other users' drafts
+func visibleTask(ctx context.Context, task *models.Task, err error) (*models.Task, error) {
+	if err != nil {
+		return nil, err
+	}
+	if !canSee(ctx, task) {
+		return nil, errTaskNotFound
+	}
+	return task, nil
+}
+
+// TaskInTenant returns a tenant lookup finding tasks by ID; the repository only finds the
+// tasks of the tenant ctx is scoped to
+func TaskInTenant(repo repository.TaskRepository) auth.TenantLookup {
+	return func(ctx context.Context, taskID string) error {
+		_, err := repo.GetByID(ctx, taskID)
+		return err
+	}
+}
+
+// TaskOwnership returns an ownership check granting access to a task's owner and assignee,
+// and to members of the team the task is assigned to. A nil teams grants no team access.
+// Tasks created before ownership was tracked have neither and stay accessible to everyone.
+func TaskOwnership(repo repository.TaskRepository, teams repository.TeamRepository) auth.OwnershipChecker {
+	return func(ctx context.Context, userID, taskID string) (bool, error) {
+		task, err := repo.GetByID(ctx, taskID)
+		if err != nil {
+			return false, err
+		}
+		if task.Status == models.StatusDraft {
+			return task.OwnerID == userID, nil
+		}
+		if task.OwnerID == "" && task.AssigneeID == "" {
+			return true, nil
+		}
+		if task.OwnerID == userID || task.AssigneeID == userID {
+			return true, nil
+		}
+		if task.TeamID == "" || teams == nil {
+			return false, nil
+		}
+		return teams.IsMember(ctx, task.TeamID, userID)
+	}
+}
@@ -1,14 +1,20 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"sample/task-management-system/pkg/auth"
 	"sample/task-management-system/pkg/models"
 	"sample/task-management-system/pkg/repository"
 )
@@ -26,6 +32,14 @@ func (m *MockTaskRepository) Create(ctx context.Context, task *models.TaskCreate
 	return args.Get(0).(*models.Task), args.Error(1)
 }
 
+func (m *MockTaskRepository) CreateBatch(ctx context.Context, tasks []*models.TaskCreate) ([]*models.Task, error) {
+	args := m.Called(ctx, tasks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
 func (m *MockTaskRepository) GetByID(ctx context.Context, id string) (*models.Task, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -34,6 +48,22 @@ func (m *MockTaskRepository) GetByID(ctx context.Context, id string) (*models.Ta
 	return args.Get(0).(*models.Task), args.Error(1)
 }
 
+func (m *MockTaskRepository) GetByKey(ctx context.Context, projectKey string, number int64) (*models.Task, error) {
+	args := m.Called(ctx, projectKey, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetBySlug(ctx context.Context, projectKey, slug string) (*models.Task, error) {
+	args := m.Called(ctx, projectKey, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
 func (m *MockTaskRepository) Update(ctx context.Context, id string, task *models.TaskUpdate) (*models.Task, error) {
 	args := m.Called(ctx, id, task)
 	if args.Get(0) == nil {
@@ -42,6 +72,22 @@ func (m *MockTaskRepository) Update(ctx context.Context, id string, task *models
 	return args.Get(0).(*models.Task), args.Error(1)
 }
 
+func (m *MockTaskRepository) SaveDraft(ctx context.Context, id string, draft *models.TaskDraft) (*models.Task, error) {
+	args := m.Called(ctx, id, draft)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) PublishDraft(ctx context.Context, id string, status models.TaskStatus) (*models.Task, error) {
+	args := m.Called(ctx, id, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
 func (m *MockTaskRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -52,6 +98,36 @@ func (m *MockTaskRepository) List(ctx context.Context, filter repository.TaskFil
 	return args.Get(0).([]*models.Task), args.Int(1), args.Error(2)
 }
 
+func (m *MockTaskRepository) Stream(ctx context.Context, filter repository.TaskFilter, fn func(task *models.Task) error) error {
+	args := m.Called(ctx, filter)
+	for _, task := range args.Get(0).([]*models.Task) {
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTaskRepository) ListSubtasks(ctx context.Context, parentID string, recursive bool) ([]*models.Task, error) {
+	args := m.Called(ctx, parentID, recursive)
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) ListAncestorIDs(ctx context.Context, id string) ([]string, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTaskRepository) UpdateSubtaskStatus(ctx context.Context, parentID string, status models.TaskStatus) ([]*models.Task, error) {
+	args := m.Called(ctx, parentID, status)
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) ListTags(ctx context.Context) ([]models.TagCount, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.TagCount), args.Error(1)
+}
+
 func TestCreateTask(t *testing.T) {
 	mockRepo := new(MockTaskRepository)
 	service := NewTaskService(mockRepo)
@@ -190,6 +266,80 @@ func TestGetTask(t *testing.T) {
 	}
 }
 
+func TestGetTaskByKey(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		key        string
+		projectKey string
+		number     int64
+		wantErr    bool
+	}{
+		{name: "uppercase key", key: "PROJ-123", projectKey: "PROJ", number: 123},
+		{name: "lowercase key", key: "web2-7", projectKey: "WEB2", number: 7},
+		{name: "missing number", key: "PROJ", wantErr: true},
+		{name: "zero number", key: "PROJ-0", wantErr: true},
+		{name: "invalid project key", key: "2PROJ-1", wantErr: true},
+		{name: "not a number", key: "PROJ-abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			service := NewTaskService(mockRepo)
+			mockRepo.On("GetByKey", ctx, tt.projectKey, tt.number).
+				Return(&models.Task{ID: "test-id", ProjectKey: tt.projectKey, Number: tt.number}, nil).Maybe()
+
+			got, err := service.GetTaskByKey(ctx, tt.key)
+			if tt.wantErr {
+				assert.Error(t, err)
+				mockRepo.AssertNotCalled(t, "GetByKey", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "test-id", got.ID)
+		})
+	}
+}
+
+func TestGetTaskBySlug(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		projectKey string
+		slug       string
+		wantKey    string
+		wantSlug   string
+		wantErr    bool
+	}{
+		{name: "normalizes case", projectKey: "proj", slug: "Fix-Login", wantKey: "PROJ", wantSlug: "fix-login"},
+		{name: "invalid project key", projectKey: "p", slug: "fix-login", wantErr: true},
+		{name: "missing slug", projectKey: "PROJ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			service := NewTaskService(mockRepo)
+			mockRepo.On("GetBySlug", ctx, tt.wantKey, tt.wantSlug).
+				Return(&models.Task{ID: "test-id", Slug: tt.wantSlug}, nil).Maybe()
+
+			got, err := service.GetTaskBySlug(ctx, tt.projectKey, tt.slug)
+			if tt.wantErr {
+				assert.Error(t, err)
+				mockRepo.AssertNotCalled(t, "GetBySlug", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, "test-id", got.ID)
+		})
+	}
+}
+
 func TestUpdateTask(t *testing.T) {
 	mockRepo := new(MockTaskRepository)
 	service := NewTaskService(mockRepo)
@@ -257,6 +407,90 @@ func TestUpdateTask(t *testing.T) {
 	}
 }
 
+func TestUpdateTask_Parent(t *testing.T) {
+	ctx := context.Background()
+	parent := func(id string) *string { return &id }
+
+	tests := []struct {
+		name      string
+		parentID  *string
+		ancestors []string
+		wantErr   error
+	}{
+		{"nest under unrelated task", parent("other"), []string{"root"}, nil},
+		{"detach from parent", parent(""), nil, nil},
+		{"nest under itself", parent("task-1"), nil, ErrTaskCycle},
+		{"nest under own subtask", parent("child"), []string{"task-1", "root"}, ErrTaskCycle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			mockRepo.On("GetByID", mock.Anything, *tt.parentID).Return(&models.Task{ID: *tt.parentID}, nil)
+			mockRepo.On("ListAncestorIDs", mock.Anything, *tt.parentID).Return(tt.ancestors, nil)
+			mockRepo.On("Update", mock.Anything, "task-1", mock.Anything).Return(&models.Task{ID: "task-1", ParentID: *tt.parentID}, nil)
+			service := NewTaskService(mockRepo)
+
+			_, err := service.UpdateTask(ctx, "task-1", &models.TaskUpdate{ParentID: tt.parentID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUpdateTask_CascadeStatus(t *testing.T) {
+	ctx := context.Background()
+	completed := models.StatusCompleted
+
+	t.Run("cascades to subtasks when requested", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("Update", mock.Anything, "task-1", mock.Anything).Return(&models.Task{ID: "task-1", Status: completed}, nil)
+		mockRepo.On("UpdateSubtaskStatus", mock.Anything, "task-1", completed).Return([]*models.Task{{ID: "child"}}, nil)
+		service := NewTaskService(mockRepo)
+
+		_, err := service.UpdateTask(ctx, "task-1", &models.TaskUpdate{Status: &completed, CascadeStatus: true})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("leaves subtasks alone by default", func(t *testing.T) {
+		mockRepo := new(MockTaskRepository)
+		mockRepo.On("Update", mock.Anything, "task-1", mock.Anything).Return(&models.Task{ID: "task-1", Status: completed}, nil)
+		service := NewTaskService(mockRepo)
+
+		_, err := service.UpdateTask(ctx, "task-1", &models.TaskUpdate{Status: &completed})
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "UpdateSubtaskStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("requires a status", func(t *testing.T) {
+		service := NewTaskService(new(MockTaskRepository))
+
+		_, err := service.UpdateTask(ctx, "task-1", &models.TaskUpdate{CascadeStatus: true})
+		assert.Error(t, err)
+	})
+}
+
+func TestListSubtasks(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("GetByID", mock.Anything, "task-1").Return(&models.Task{ID: "task-1"}, nil)
+	mockRepo.On("GetByID", mock.Anything, "missing").Return(nil, errors.New("task not found"))
+	mockRepo.On("ListSubtasks", mock.Anything, "task-1", true).Return([]*models.Task{{ID: "child", ParentID: "task-1"}, {ID: "grandchild", ParentID: "child"}}, nil)
+	service := NewTaskService(mockRepo)
+
+	tasks, err := service.ListSubtasks(ctx, "task-1", true)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	_, err = service.ListSubtasks(ctx, "missing", false)
+	assert.Error(t, err)
+}
+
 func TestDeleteTask(t *testing.T) {
 	mockRepo := new(MockTaskRepository)
 	service := NewTaskService(mockRepo)
@@ -341,7 +575,7 @@ func TestListTasks(t *testing.T) {
 					},
 				}
 				mockRepo.On("List", mock.Anything, repository.TaskFilter{
-					Status: models.StatusPending,
+					Status: []models.TaskStatus{models.StatusPending},
 					Page:   1,
 					Limit:  10,
 				}).Return(tasks, 2, nil)
@@ -380,7 +614,7 @@ func TestListTasks(t *testing.T) {
 					},
 				}
 				mockRepo.On("List", mock.Anything, repository.TaskFilter{
-					Status: models.StatusPending,
+					Status: []models.TaskStatus{models.StatusPending},
 					Page:   1,
 					Limit:  10,
 				}).Return(tasks, 2, nil)
@@ -405,7 +639,7 @@ func TestListTasks(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mock()
-			tasks, total, err := service.ListTasks(ctx, tt.status, tt.page, tt.limit)
+			tasks, total, err := service.ListTasks(ctx, repository.TaskFilter{Status: []models.TaskStatus{tt.status}, Page: tt.page, Limit: tt.limit})
 			
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -425,4 +659,497 @@ func TestListTasks(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+} 
+func TestTaskOwnership(t *testing.T) {
+	tests := []struct {
+		name      string
+		task      *models.Task
+		repoErr   error
+		userID    string
+		want      bool
+		wantError bool
+	}{
+		{"owner", &models.Task{ID: "1", OwnerID: "alice"}, nil, "alice", true, false},
+		{"assignee", &models.Task{ID: "1", OwnerID: "alice", AssigneeID: "bob"}, nil, "bob", true, false},
+		{"other user", &models.Task{ID: "1", OwnerID: "alice", AssigneeID: "bob"}, nil, "carol", false, false},
+		{"legacy task without owner", &models.Task{ID: "1"}, nil, "carol", true, false},
+		{"team member", &models.Task{ID: "1", OwnerID: "alice", TeamID: "team-1"}, nil, "dave", true, false},
+		{"not a team member", &models.Task{ID: "1", OwnerID: "alice", TeamID: "team-1"}, nil, "carol", false, false},
+		{"team member on a draft", &models.Task{ID: "1", OwnerID: "alice", TeamID: "team-1", Status: models.StatusDraft}, nil, "dave", false, false},
+		{"lookup error", nil, errors.New("not found"), "alice", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTaskRepository)
+			repo.On("GetByID", mock.Anything, "1").Return(tt.task, tt.repoErr)
+			teams := new(MockTeamRepository)
+			teams.On("IsMember", mock.Anything, "team-1", "dave").Return(true, nil)
+			teams.On("IsMember", mock.Anything, "team-1", "carol").Return(false, nil)
+
+			allowed, err := TaskOwnership(repo, teams)(context.Background(), tt.userID, "1")
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, allowed)
+		})
+	}
+}
+
+func TestCreateTask_Tags(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no tags", tags: nil, want: []string{}},
+		{name: "normalized", tags: []string{" Bug", "frontend", "bug", ""}, want: []string{"bug", "frontend"}},
+		{name: "namespaced", tags: []string{"team:core", "v1.2"}, want: []string{"team:core", "v1.2"}},
+		{name: "comma not allowed", tags: []string{"a,b"}, wantErr: true},
+		{name: "space not allowed", tags: []string{"two words"}, wantErr: true},
+		{name: "too long", tags: []string{strings.Repeat("x", models.MaxTagLength+1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			service := NewTaskService(mockRepo)
+			mockRepo.On("Create", ctx, mock.AnythingOfType("*models.TaskCreate")).
+				Return(&models.Task{ID: "test-id"}, nil).Maybe()
+
+			input := &models.TaskCreate{Title: "Tagged", DueDate: time.Now().Add(time.Hour), Tags: tt.tags}
+			_, err := service.CreateTask(ctx, input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, input.Tags)
+		})
+	}
+}
+
+func TestCreateTask_TooManyTags(t *testing.T) {
+	tags := make([]string, models.MaxTags+1)
+	for i := range tags {
+		tags[i] = "tag" + strconv.Itoa(i)
+	}
+
+	input := &models.TaskCreate{Title: "Tagged", DueDate: time.Now().Add(time.Hour), Tags: tags}
+	_, err := NewTaskService(new(MockTaskRepository)).CreateTask(context.Background(), input)
+	assert.Error(t, err)
+}
+
+func TestCreateTask_Priority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority models.TaskPriority
+		want     models.TaskPriority
+		wantErr  bool
+	}{
+		{name: "defaults to medium", want: models.PriorityMedium},
+		{name: "keeps valid priority", priority: models.PriorityCritical, want: models.PriorityCritical},
+		{name: "rejects unknown priority", priority: "urgent", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			input := &models.TaskCreate{Title: "Prioritized", DueDate: time.Now().Add(time.Hour), Priority: tt.priority}
+			if !tt.wantErr {
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.TaskCreate) bool {
+					return task.Priority == tt.want
+				})).Return(&models.Task{ID: "1", Priority: tt.want}, nil)
+			}
+
+			task, err := NewTaskService(mockRepo).CreateTask(context.Background(), input)
+			if tt.wantErr {
+				assert.EqualError(t, err, "invalid priority")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, task.Priority)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateTask_ProjectKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		projectKey string
+		want       string
+		wantErr    bool
+	}{
+		{name: "defaults to TASK", want: models.DefaultProjectKey},
+		{name: "uppercases key", projectKey: "web2", want: "WEB2"},
+		{name: "rejects leading digit", projectKey: "2WEB", wantErr: true},
+		{name: "rejects separator", projectKey: "MY-PROJ", wantErr: true},
+		{name: "rejects long key", projectKey: "ABCDEFGHIJK", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			input := &models.TaskCreate{Title: "Numbered", DueDate: time.Now().Add(time.Hour), ProjectKey: tt.projectKey}
+			if !tt.wantErr {
+				mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.TaskCreate) bool {
+					return task.ProjectKey == tt.want
+				})).Return(&models.Task{ID: "1", ProjectKey: tt.want, Number: 1}, nil)
+			}
+
+			_, err := NewTaskService(mockRepo).CreateTask(context.Background(), input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreateTask_Metadata(t *testing.T) {
+	tooMany := make(map[string]interface{}, models.MaxMetadataKeys+1)
+	for i := 0; i <= models.MaxMetadataKeys; i++ {
+		tooMany["key"+strconv.Itoa(i)] = i
+	}
+
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		wantErr  bool
+	}{
+		{name: "no metadata"},
+		{name: "nested values", metadata: map[string]interface{}{"customer": "acme", "seats": 5, "contact": map[string]interface{}{"email": "ops@acme.test"}}},
+		{name: "too many keys", metadata: tooMany, wantErr: true},
+		{name: "value too large", metadata: map[string]interface{}{"notes": strings.Repeat("x", models.MaxMetadataValueSize)}, wantErr: true},
+		{name: "dotted key", metadata: map[string]interface{}{"customer.name": "acme"}, wantErr: true},
+		{name: "empty key", metadata: map[string]interface{}{"": "acme"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.TaskCreate")).
+				Return(&models.Task{ID: "1", Metadata: tt.metadata}, nil).Maybe()
+
+			input := &models.TaskCreate{Title: "Custom", DueDate: time.Now().Add(time.Hour), Metadata: tt.metadata}
+			_, err := NewTaskService(mockRepo).CreateTask(context.Background(), input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSaveDraft(t *testing.T) {
+	ctx := context.Background()
+	id := "3f0c2a8e-6c1b-4d5e-9a7f-1b2c3d4e5f60"
+
+	tests := []struct {
+		name     string
+		id       string
+		existing *models.Task
+		wantErr  error
+		wantSave bool
+	}{
+		{name: "creates new draft", id: id, wantSave: true},
+		{name: "replaces own draft", id: id, existing: &models.Task{ID: id, Status: models.StatusDraft, OwnerID: "alice", ProjectKey: models.DefaultProjectKey}, wantSave: true},
+		{name: "published task", id: id, existing: &models.Task{ID: id, Status: models.StatusPending, OwnerID: "alice"}, wantErr: ErrNotDraft},
+		{name: "another user's draft", id: id, existing: &models.Task{ID: id, Status: models.StatusDraft, OwnerID: "bob"}, wantErr: errTaskNotFound},
+		{name: "id must be a UUID", id: "draft-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			if tt.existing != nil {
+				mockRepo.On("GetByID", ctx, tt.id).Return(tt.existing, nil)
+			} else {
+				mockRepo.On("GetByID", ctx, tt.id).Return(nil, errors.New("task not found")).Maybe()
+			}
+			mockRepo.On("SaveDraft", ctx, tt.id, mock.AnythingOfType("*models.TaskDraft")).
+				Return(&models.Task{ID: tt.id, Status: models.StatusDraft}, nil).Maybe()
+
+			// No title or due date yet
+			draft := &models.TaskDraft{Description: "half-written", OwnerID: "alice"}
+			task, err := NewTaskService(mockRepo).SaveDraft(ctx, tt.id, draft)
+			if !tt.wantSave {
+				assert.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				mockRepo.AssertNotCalled(t, "SaveDraft", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, models.StatusDraft, task.Status)
+		})
+	}
+}
+
+func TestPublishDraft(t *testing.T) {
+	aliceCtx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: "alice"})
+	bobCtx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: "bob"})
+	complete := &models.Task{ID: "draft-1", Title: "Ready", Status: models.StatusDraft, Priority: models.PriorityHigh, DueDate: time.Now().Add(time.Hour), OwnerID: "alice", ProjectKey: models.DefaultProjectKey}
+	noDueDate := &models.Task{ID: "draft-1", Title: "Ready", Status: models.StatusDraft, Priority: models.PriorityHigh, OwnerID: "alice", ProjectKey: models.DefaultProjectKey}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		task        *models.Task
+		wantPublish bool
+		wantErr     error
+	}{
+		{name: "publishes complete draft", ctx: aliceCtx, task: complete, wantPublish: true},
+		{name: "runs full validation", ctx: aliceCtx, task: noDueDate},
+		{name: "hidden from other users", ctx: bobCtx, task: complete, wantErr: errTaskNotFound},
+		{name: "already published", ctx: aliceCtx, task: &models.Task{ID: "draft-1", Status: models.StatusPending, OwnerID: "alice"}, wantErr: ErrNotDraft},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			mockRepo.On("GetByID", tt.ctx, "draft-1").Return(tt.task, nil)
+			mockRepo.On("PublishDraft", tt.ctx, "draft-1", models.StatusPending).
+				Return(&models.Task{ID: "draft-1", Status: models.StatusPending}, nil).Maybe()
+
+			task, err := NewTaskService(mockRepo).PublishDraft(tt.ctx, "draft-1")
+			if !tt.wantPublish {
+				assert.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+				mockRepo.AssertNotCalled(t, "PublishDraft", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, models.StatusPending, task.Status)
+		})
+	}
+}
+
+func TestListTasks_DraftsOnlyForOwner(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: "alice"})
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("List", ctx, mock.MatchedBy(func(f repository.TaskFilter) bool {
+		return f.Viewer == "alice"
+	})).Return([]*models.Task{}, 0, nil)
+
+	// A viewer in the request is ignored
+	_, _, err := NewTaskService(mockRepo).ListTasks(ctx, repository.TaskFilter{Viewer: "bob"})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListTasks_Sort(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		sort    string
+		order   string
+		wantErr bool
+	}{
+		{name: "title", sort: repository.SortTitle},
+		{name: "updated_at ascending", sort: repository.SortUpdatedAt, order: "asc"},
+		{name: "unknown field", sort: "title; DROP TABLE tasks", wantErr: true},
+		{name: "unknown order", sort: repository.SortDueDate, order: "sideways", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTaskRepository)
+			mockRepo.On("List", ctx, mock.MatchedBy(func(f repository.TaskFilter) bool {
+				return f.Sort == tt.sort && f.Order == tt.order
+			})).Return([]*models.Task{}, 0, nil)
+
+			_, _, err := NewTaskService(mockRepo).ListTasks(ctx, repository.TaskFilter{Sort: tt.sort, Order: tt.order})
+			if tt.wantErr {
+				assert.Error(t, err)
+				mockRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListTasks_DueRange(t *testing.T) {
+	ctx := context.Background()
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	nextMonday := monday.AddDate(0, 0, 7)
+
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("List", ctx, mock.MatchedBy(func(f repository.TaskFilter) bool {
+		return f.DueAfter.Equal(monday) && f.DueBefore.Equal(nextMonday)
+	})).Return([]*models.Task{}, 0, nil)
+	service := NewTaskService(mockRepo)
+
+	_, _, err := service.ListTasks(ctx, repository.TaskFilter{DueAfter: &monday, DueBefore: &nextMonday})
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// An empty range is rejected
+	_, _, err = service.ListTasks(ctx, repository.TaskFilter{DueAfter: &nextMonday, DueBefore: &monday})
+	assert.Error(t, err)
+	mockRepo.AssertNumberOfCalls(t, "List", 1)
+}
+
+func TestListTasks_MultipleStatuses(t *testing.T) {
+	ctx := context.Background()
+	open := []models.TaskStatus{models.StatusPending, models.StatusInProgress}
+
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("List", ctx, repository.TaskFilter{Status: open, Page: 1, Limit: 10}).Return([]*models.Task{}, 0, nil)
+	service := NewTaskService(mockRepo)
+
+	_, _, err := service.ListTasks(ctx, repository.TaskFilter{Status: open})
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+
+	// Every listed status must be known
+	_, _, err = service.ListTasks(ctx, repository.TaskFilter{Status: []models.TaskStatus{models.StatusPending, "started"}})
+	assert.Error(t, err)
+	mockRepo.AssertNumberOfCalls(t, "List", 1)
+}
+
+func TestExportTasks(t *testing.T) {
+	ctx := context.Background()
+	filter := repository.TaskFilter{Priority: models.PriorityHigh}
+	tasks := []*models.Task{
+		{ID: "task-1", Key: "OPS-1", Title: "=SUM(A1)", Status: models.StatusPending, Priority: models.PriorityHigh},
+		{ID: "task-2", Key: "OPS-2", Title: "Release", Status: models.StatusCompleted, Priority: models.PriorityHigh, Tags: []string{"a", "b"}},
+	}
+
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("Stream", ctx, filter).Return(tasks, nil)
+	service := NewTaskService(mockRepo)
+
+	var csvOut bytes.Buffer
+	next, err := service.ExportTasks(ctx, filter, models.ExportCSV, &csvOut, models.ExportPacing{})
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "key,title,"))
+	assert.True(t, strings.HasPrefix(lines[1], "OPS-1,'=SUM(A1),"), "formulas are escaped")
+	assert.Contains(t, lines[2], "a;b")
+
+	var jsonOut bytes.Buffer
+	_, err = service.ExportTasks(ctx, filter, models.ExportJSON, &jsonOut, models.ExportPacing{})
+	require.NoError(t, err)
+	var decoded struct {
+		Tasks []models.Task `json:"tasks"`
+	}
+	require.NoError(t, json.Unmarshal(jsonOut.Bytes(), &decoded))
+	require.Len(t, decoded.Tasks, 2)
+	assert.Equal(t, "task-2", decoded.Tasks[1].ID)
+
+	var ndjsonOut bytes.Buffer
+	_, err = service.ExportTasks(ctx, filter, models.ExportNDJSON, &ndjsonOut, models.ExportPacing{})
+	require.NoError(t, err)
+	lines = strings.Split(strings.TrimSpace(ndjsonOut.String()), "\n")
+	require.Len(t, lines, 2)
+	var task models.Task
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &task))
+	assert.Equal(t, "task-2", task.ID)
+
+	_, err = service.ExportTasks(ctx, filter, "xlsx", &jsonOut, models.ExportPacing{})
+	assert.Error(t, err)
+}
+
+func TestExportTasks_Paced(t *testing.T) {
+	ctx := context.Background()
+	filter := repository.TaskFilter{Offset: 10}
+	tasks := []*models.Task{{ID: "task-11"}, {ID: "task-12"}, {ID: "task-13"}}
+
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("Stream", ctx, filter).Return(tasks, nil)
+	service := NewTaskService(mockRepo)
+
+	// The clock moves on a minute whenever it is read, so the export runs out of time
+	// before its third task
+	var now time.Time
+	service.(*taskService).now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	pacing := models.ExportPacing{MaxDuration: 90 * time.Second}
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		next, err := service.ExportTasks(ctx, filter, models.ExportJSON, &out, pacing)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, 12, next.Offset, "two tasks were delivered after the ten skipped")
+
+		var decoded struct {
+			Tasks      []models.Task `json:"tasks"`
+			NextCursor string        `json:"next_cursor"`
+		}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+		assert.Len(t, decoded.Tasks, 2)
+		cursor, err := models.ParseExportCursor(decoded.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, *next, cursor)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		var out bytes.Buffer
+		next, err := service.ExportTasks(ctx, filter, models.ExportNDJSON, &out, pacing)
+		require.NoError(t, err)
+		require.NotNil(t, next)
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.Equal(t, `{"next_cursor":"`+next.String()+`"}`, lines[2])
+	})
+}
+
+func TestExportTasks_FailureWritesNothing(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("Stream", ctx, repository.TaskFilter{}).Return([]*models.Task{{ID: "task-1"}}, errors.New("connection reset"))
+	service := NewTaskService(mockRepo)
+
+	for _, format := range []models.ExportFormat{models.ExportCSV, models.ExportJSON} {
+		var out bytes.Buffer
+		_, err := service.ExportTasks(ctx, repository.TaskFilter{}, format, &out, models.ExportPacing{})
+		assert.Error(t, err)
+		assert.Zero(t, out.Len(), format)
+	}
+}
+
+func TestGuestProjectRestriction(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "claims", &auth.Claims{UserID: auth.GuestUserID, ProjectID: "roadmap"})
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("GetByID", ctx, "1").Return(&models.Task{ID: "1", ProjectKey: "ROADMAP"}, nil)
+	mockRepo.On("GetByID", ctx, "2").Return(&models.Task{ID: "2", ProjectKey: "OTHER"}, nil)
+	mockRepo.On("List", ctx, mock.MatchedBy(func(f repository.TaskFilter) bool {
+		return f.ProjectKey == "ROADMAP"
+	})).Return([]*models.Task{}, 0, nil)
+	svc := NewTaskService(mockRepo)
+
+	_, err := svc.GetTask(ctx, "1")
+	assert.NoError(t, err)
+	_, err = svc.GetTask(ctx, "2")
+	assert.ErrorIs(t, err, errTaskNotFound)
+	// A project filter in the request can't widen the restriction
+	_, _, err = svc.ListTasks(ctx, repository.TaskFilter{ProjectKey: "OTHER"})
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// TeamService manages teams, their members and the tasks assigned to them
+type TeamService interface {
+	CreateTeam(ctx context.Context, createdBy string, input *models.TeamInput) (*models.Team, error)
+	GetTeam(ctx context.Context, id string) (*models.Team, error)
+	ListTeams(ctx context.Context) ([]*models.Team, error)
+	UpdateTeam(ctx context.Context, id string, input *models.TeamInput) (*models.Team, error)
+	DeleteTeam(ctx context.Context, id string) error
+	// AddMember adds a user to a team and returns the team with its members
+	AddMember(ctx context.Context, teamID, userID, addedBy string) (*models.Team, error)
+	// RemoveMember removes a user from a team and returns the team with its members
+	RemoveMember(ctx context.Context, teamID, userID string) (*models.Team, error)
+	AssignTask(ctx context.Context, taskID string, assignment *models.TaskTeamAssignment) (*models.Task, error)
+	UnassignTask(ctx context.Context, taskID string) (*models.Task, error)
+}
+
+type teamService struct {
+	repo repository.TeamRepository
+}
+
+// NewTeamService creates a new team service
+func NewTeamService(repo repository.TeamRepository) TeamService {
+	return &teamService{repo: repo}
+}
+
+func (s *teamService) CreateTeam(ctx context.Context, createdBy string, input *models.TeamInput) (*models.Team, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, &models.Team{
+		Name:        input.Name,
+		Description: input.Description,
+		CreatedBy:   createdBy,
+	})
+}
+
+func (s *teamService) GetTeam(ctx context.Context, id string) (*models.Team, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *teamService) ListTeams(ctx context.Context) ([]*models.Team, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *teamService) UpdateTeam(ctx context.Context, id string, input *models.TeamInput) (*models.Team, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, input)
+}
+
+func (s *teamService) DeleteTeam(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *teamService) AddMember(ctx context.Context, teamID, userID, addedBy string) (*models.Team, error) {
+	if userID == "" {
+		return nil, errors.New("user_id is required")
+	}
+	if err := s.repo.AddMember(ctx, teamID, userID, addedBy); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, teamID)
+}
+
+func (s *teamService) RemoveMember(ctx context.Context, teamID, userID string) (*models.Team, error) {
+	if err := s.repo.RemoveMember(ctx, teamID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, teamID)
+}
+
+func (s *teamService) AssignTask(ctx context.Context, taskID string, assignment *models.TaskTeamAssignment) (*models.Task, error) {
+	if err := assignment.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.AssignTask(ctx, taskID, assignment.TeamID)
+}
+
+func (s *teamService) UnassignTask(ctx context.Context, taskID string) (*models.Task, error) {
+	return s.repo.AssignTask(ctx, taskID, "")
+}
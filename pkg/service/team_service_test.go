@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockTeamRepository is a mock implementation of TeamRepository
+type MockTeamRepository struct {
+	mock.Mock
+}
+
+func (m *MockTeamRepository) Create(ctx context.Context, team *models.Team) (*models.Team, error) {
+	args := m.Called(ctx, team)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) GetByID(ctx context.Context, id string) (*models.Team, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) List(ctx context.Context) ([]*models.Team, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) Update(ctx context.Context, id string, input *models.TeamInput) (*models.Team, error) {
+	args := m.Called(ctx, id, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Team), args.Error(1)
+}
+
+func (m *MockTeamRepository) Delete(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockTeamRepository) AddMember(ctx context.Context, teamID, userID, addedBy string) error {
+	return m.Called(ctx, teamID, userID, addedBy).Error(0)
+}
+
+func (m *MockTeamRepository) RemoveMember(ctx context.Context, teamID, userID string) error {
+	return m.Called(ctx, teamID, userID).Error(0)
+}
+
+func (m *MockTeamRepository) IsMember(ctx context.Context, teamID, userID string) (bool, error) {
+	args := m.Called(ctx, teamID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTeamRepository) AssignTask(ctx context.Context, taskID, teamID string) (*models.Task, error) {
+	args := m.Called(ctx, taskID, teamID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func TestCreateTeam(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		input    models.TeamInput
+		wantName string
+		wantErr  bool
+	}{
+		{name: "valid", input: models.TeamInput{Name: "  Platform ", Description: "Infra"}, wantName: "Platform"},
+		{name: "missing name", input: models.TeamInput{Name: "  "}, wantErr: true},
+		{name: "name too long", input: models.TeamInput{Name: string(make([]byte, models.MaxTeamNameLength+1))}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTeamRepository)
+			repo.On("Create", ctx, mock.Anything).Return(&models.Team{ID: "team-1", Name: tt.wantName}, nil).Maybe()
+
+			team, err := NewTeamService(repo).CreateTeam(ctx, "admin-1", &tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "team-1", team.ID)
+			repo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(team *models.Team) bool {
+				return team.Name == tt.wantName && team.CreatedBy == "admin-1"
+			}))
+		})
+	}
+}
+
+func TestTeamMembers(t *testing.T) {
+	ctx := context.Background()
+	team := &models.Team{ID: "team-1", Members: []*models.TeamMember{{UserID: "bob", AddedBy: "admin-1"}}}
+
+	t.Run("add returns the team with its members", func(t *testing.T) {
+		repo := new(MockTeamRepository)
+		repo.On("AddMember", ctx, "team-1", "bob", "admin-1").Return(nil)
+		repo.On("GetByID", ctx, "team-1").Return(team, nil)
+
+		got, err := NewTeamService(repo).AddMember(ctx, "team-1", "bob", "admin-1")
+		assert.NoError(t, err)
+		assert.Equal(t, team, got)
+	})
+
+	t.Run("add to a missing team", func(t *testing.T) {
+		repo := new(MockTeamRepository)
+		repo.On("AddMember", ctx, "team-2", "bob", "admin-1").Return(models.ErrTeamNotFound)
+
+		_, err := NewTeamService(repo).AddMember(ctx, "team-2", "bob", "admin-1")
+		assert.ErrorIs(t, err, models.ErrTeamNotFound)
+		repo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		repo := new(MockTeamRepository)
+		repo.On("RemoveMember", ctx, "team-1", "bob").Return(nil)
+		repo.On("GetByID", ctx, "team-1").Return(&models.Team{ID: "team-1"}, nil)
+
+		got, err := NewTeamService(repo).RemoveMember(ctx, "team-1", "bob")
+		assert.NoError(t, err)
+		assert.Empty(t, got.Members)
+	})
+}
+
+func TestAssignTaskToTeam(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("assign", func(t *testing.T) {
+		repo := new(MockTeamRepository)
+		repo.On("AssignTask", ctx, "task-1", "team-1").Return(&models.Task{ID: "task-1", TeamID: "team-1"}, nil)
+
+		task, err := NewTeamService(repo).AssignTask(ctx, "task-1", &models.TaskTeamAssignment{TeamID: "team-1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "team-1", task.TeamID)
+	})
+
+	t.Run("assign without a team", func(t *testing.T) {
+		repo := new(MockTeamRepository)
+
+		_, err := NewTeamService(repo).AssignTask(ctx, "task-1", &models.TaskTeamAssignment{})
+		assert.Error(t, err)
+		repo.AssertNotCalled(t, "AssignTask", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unassign", func(t *testing.T) {
+		repo := new(MockTeamRepository)
+		repo.On("AssignTask", ctx, "task-1", "").Return(&models.Task{ID: "task-1"}, nil)
+
+		task, err := NewTeamService(repo).UnassignTask(ctx, "task-1")
+		assert.NoError(t, err)
+		assert.Empty(t, task.TeamID)
+	})
+}
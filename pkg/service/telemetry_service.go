@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+const (
+	// telemetrySettingsCacheTTL bounds how long other instances may keep recording
+	// events of an organization that opted out
+	telemetrySettingsCacheTTL = 30 * time.Second
+	// telemetryInsertBatchSize is the number of events stored per transaction
+	telemetryInsertBatchSize = 500
+)
+
+// TelemetryService collects usage analytics events from first-party clients
+type TelemetryService interface {
+	// Ingest validates a batch and queues the sampled events for storage, returning how
+	// many were queued. Events of organizations that opted out are discarded.
+	Ingest(ctx context.Context, userID, orgID string, batch *models.TelemetryBatch) (int, error)
+	GetSettings(ctx context.Context, orgID string) (*models.TelemetrySettings, error)
+	UpdateSettings(ctx context.Context, orgID, updatedBy string, input *models.TelemetrySettingsUpdate) (*models.TelemetrySettings, error)
+	// Flush stores the queued events
+	Flush(ctx context.Context) error
+	// Run flushes every interval until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type cachedTelemetrySettings struct {
+	optedOut bool
+	loadedAt time.Time
+}
+
+type telemetryService struct {
+	repo       repository.TelemetryRepository
+	sampleRate float64
+	queue      chan *models.TelemetryEvent
+	dropped    uint64
+
+	mu     sync.RWMutex
+	cache  map[string]cachedTelemetrySettings
+	now    func() time.Time
+	random func() float64
+}
+
+// NewTelemetryService creates a telemetry service keeping sampleRate of the events and
+// buffering up to bufferSize of them. Like metrics, events are dropped rather than
+// slowing down clients when the buffer is full.
+func NewTelemetryService(repo repository.TelemetryRepository, sampleRate float64, bufferSize int) TelemetryService {
+	return &telemetryService{
+		repo:       repo,
+		sampleRate: sampleRate,
+		queue:      make(chan *models.TelemetryEvent, bufferSize),
+		cache:      make(map[string]cachedTelemetrySettings),
+		now:        time.Now,
+		random:     rand.Float64,
+	}
+}
+
+func (s *telemetryService) Ingest(ctx context.Context, userID, orgID string, batch *models.TelemetryBatch) (int, error) {
+	if userID == "" {
+		return 0, errors.New("telemetry events need a user")
+	}
+	now := s.now()
+	if err := batch.Validate(now); err != nil {
+		return 0, err
+	}
+
+	if orgID != "" {
+		optedOut, err := s.optedOut(ctx, orgID)
+		if err != nil {
+			return 0, err
+		}
+		if optedOut {
+			return 0, nil
+		}
+	}
+
+	queued := 0
+	for _, input := range batch.Events {
+		if s.sampleRate < 1 && s.random() >= s.sampleRate {
+			continue
+		}
+
+		event := &models.TelemetryEvent{
+			Name:       input.Name,
+			Client:     batch.Client,
+			UserID:     userID,
+			OrgID:      orgID,
+			Properties: input.Properties,
+			SampleRate: s.sampleRate,
+			OccurredAt: *input.OccurredAt,
+			ReceivedAt: now,
+		}
+		select {
+		case s.queue <- event:
+			queued++
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+	return queued, nil
+}
+
+func (s *telemetryService) GetSettings(ctx context.Context, orgID string) (*models.TelemetrySettings, error) {
+	return s.repo.GetSettings(ctx, orgID)
+}
+
+func (s *telemetryService) UpdateSettings(ctx context.Context, orgID, updatedBy string, input *models.TelemetrySettingsUpdate) (*models.TelemetrySettings, error) {
+	if orgID == "" {
+		return nil, errors.New("org id is required")
+	}
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.repo.SaveSettings(ctx, &models.TelemetrySettings{
+		OrgID:     orgID,
+		OptedOut:  *input.OptedOut,
+		UpdatedBy: updatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	delete(s.cache, orgID)
+	s.mu.Unlock()
+	return settings, nil
+}
+
+func (s *telemetryService) Flush(ctx context.Context) error {
+	if dropped := atomic.SwapUint64(&s.dropped, 0); dropped > 0 {
+		log.Printf("Dropped %d telemetry events because the buffer was full", dropped)
+	}
+
+	for {
+		events := s.drain(telemetryInsertBatchSize)
+		if len(events) == 0 {
+			return nil
+		}
+		// Failed batches are not retried; analytics tolerate the gap
+		if err := s.repo.InsertEvents(ctx, events); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *telemetryService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil {
+				log.Printf("Failed to store telemetry events: %v", err)
+			}
+		}
+	}
+}
+
+// drain takes up to limit queued events without waiting for more
+func (s *telemetryService) drain(limit int) []*models.TelemetryEvent {
+	var events []*models.TelemetryEvent
+	for len(events) < limit {
+		select {
+		case event := <-s.queue:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+	return events
+}
+
+// optedOut reports whether the organization opted out, loading its settings if the
+// cached copy is stale
+func (s *telemetryService) optedOut(ctx context.Context, orgID string) (bool, error) {
+	s.mu.RLock()
+	cached, ok := s.cache[orgID]
+	s.mu.RUnlock()
+	if ok && s.now().Sub(cached.loadedAt) < telemetrySettingsCacheTTL {
+		return cached.optedOut, nil
+	}
+
+	settings, err := s.repo.GetSettings(ctx, orgID)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	s.cache[orgID] = cachedTelemetrySettings{optedOut: settings.OptedOut, loadedAt: s.now()}
+	s.mu.Unlock()
+
+	return settings.OptedOut, nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockTelemetryRepository is a mock implementation of TelemetryRepository
+type MockTelemetryRepository struct {
+	mock.Mock
+}
+
+func (m *MockTelemetryRepository) InsertEvents(ctx context.Context, events []*models.TelemetryEvent) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+func (m *MockTelemetryRepository) GetSettings(ctx context.Context, orgID string) (*models.TelemetrySettings, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TelemetrySettings), args.Error(1)
+}
+
+func (m *MockTelemetryRepository) SaveSettings(ctx context.Context, settings *models.TelemetrySettings) (*models.TelemetrySettings, error) {
+	args := m.Called(ctx, settings)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TelemetrySettings), args.Error(1)
+}
+
+func telemetryBatch(names ...string) *models.TelemetryBatch {
+	batch := &models.TelemetryBatch{Client: "web"}
+	for _, name := range names {
+		batch.Events = append(batch.Events, models.TelemetryEventInput{Name: name})
+	}
+	return batch
+}
+
+func TestIngest_QueuesAndFlushes(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockTelemetryRepository)
+	svc := NewTelemetryService(repo, 1, 10)
+
+	repo.On("GetSettings", ctx, "acme").Return(&models.TelemetrySettings{OrgID: "acme"}, nil).Once()
+	repo.On("InsertEvents", ctx, mock.MatchedBy(func(events []*models.TelemetryEvent) bool {
+		return len(events) == 2 && events[0].Name == "board.opened" && events[0].OrgID == "acme" &&
+			events[0].UserID == "user-1" && events[0].SampleRate == 1
+	})).Return(nil).Once()
+
+	accepted, err := svc.Ingest(ctx, "user-1", "acme", telemetryBatch("board.opened", "task.created"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, accepted)
+
+	require.NoError(t, svc.Flush(ctx))
+	// Nothing left to store
+	require.NoError(t, svc.Flush(ctx))
+	repo.AssertExpectations(t)
+}
+
+func TestIngest_OptedOut(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockTelemetryRepository)
+	svc := NewTelemetryService(repo, 1, 10)
+
+	repo.On("GetSettings", ctx, "acme").Return(&models.TelemetrySettings{OrgID: "acme", OptedOut: true}, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		accepted, err := svc.Ingest(ctx, "user-1", "acme", telemetryBatch("board.opened"))
+		require.NoError(t, err)
+		assert.Zero(t, accepted)
+	}
+
+	// The opt-out is cached
+	repo.AssertNumberOfCalls(t, "GetSettings", 1)
+	require.NoError(t, svc.Flush(ctx))
+	repo.AssertNotCalled(t, "InsertEvents", mock.Anything, mock.Anything)
+}
+
+func TestIngest_SamplesAndDropsWhenFull(t *testing.T) {
+	ctx := context.Background()
+	svc := NewTelemetryService(new(MockTelemetryRepository), 0.5, 1).(*telemetryService)
+	draws := []float64{0.7, 0.2, 0.1}
+	svc.random = func() float64 {
+		draw := draws[0]
+		draws = draws[1:]
+		return draw
+	}
+
+	// The first event is sampled out and the third doesn't fit the buffer
+	accepted, err := svc.Ingest(ctx, "user-1", "", telemetryBatch("a", "b", "c"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, accepted)
+
+	events := svc.drain(10)
+	require.Len(t, events, 1)
+	assert.Equal(t, "b", events[0].Name)
+	assert.Equal(t, 0.5, events[0].SampleRate)
+}
+
+func TestTelemetryBatch_Validate(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-25 * time.Hour)
+
+	tests := []struct {
+		name    string
+		batch   models.TelemetryBatch
+		wantErr bool
+	}{
+		{name: "valid", batch: models.TelemetryBatch{Client: "ios", Events: []models.TelemetryEventInput{
+			{Name: "task.board.opened", Properties: map[string]interface{}{"view": "kanban", "columns": 4.0}},
+		}}},
+		{name: "unknown client", batch: models.TelemetryBatch{Client: "bot", Events: []models.TelemetryEventInput{{Name: "a"}}}, wantErr: true},
+		{name: "empty batch", batch: models.TelemetryBatch{Client: "web"}, wantErr: true},
+		{name: "invalid name", batch: models.TelemetryBatch{Client: "web", Events: []models.TelemetryEventInput{{Name: "Board Opened"}}}, wantErr: true},
+		{name: "too old", batch: models.TelemetryBatch{Client: "web", Events: []models.TelemetryEventInput{{Name: "a", OccurredAt: &old}}}, wantErr: true},
+		{name: "nested property", batch: models.TelemetryBatch{Client: "web", Events: []models.TelemetryEventInput{
+			{Name: "a", Properties: map[string]interface{}{"filter": map[string]interface{}{"status": "done"}}},
+		}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.batch.Validate(now)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, now, *tt.batch.Events[0].OccurredAt)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+
+	"sample/task-management-system/pkg/auth"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// TemplateService handles task template business logic
+type TemplateService interface {
+	CreateTemplate(ctx context.Context, ownerID string, input *models.TaskTemplateInput) (*models.TaskTemplate, error)
+	GetTemplate(ctx context.Context, id string) (*models.TaskTemplate, error)
+	ListTemplates(ctx context.Context) ([]*models.TaskTemplate, error)
+	UpdateTemplate(ctx context.Context, id string, input *models.TaskTemplateInput) (*models.TaskTemplate, error)
+	DeleteTemplate(ctx context.Context, id string) error
+	// Instantiate creates a task from a template, owned by ownerID
+	Instantiate(ctx context.Context, id, ownerID string, input *models.TaskTemplateInstantiate) (*models.Task, error)
+}
+
+type templateService struct {
+	repo  repository.TemplateRepository
+	tasks TaskService
+}
+
+// NewTemplateService creates a new template service. Tasks are created through tasks
+// so they are validated and moderated like any other task.
+func NewTemplateService(repo repository.TemplateRepository, tasks TaskService) TemplateService {
+	return &templateService{repo: repo, tasks: tasks}
+}
+
+func (s *templateService) CreateTemplate(ctx context.Context, ownerID string, input *models.TaskTemplateInput) (*models.TaskTemplate, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Create(ctx, &models.TaskTemplate{
+		Title:       input.Title,
+		Description: input.Description,
+		Status:      input.Status,
+		Checklist:   input.Checklist,
+		OwnerID:     ownerID,
+	})
+}
+
+func (s *templateService) GetTemplate(ctx context.Context, id string) (*models.TaskTemplate, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *templateService) ListTemplates(ctx context.Context) ([]*models.TaskTemplate, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *templateService) UpdateTemplate(ctx context.Context, id string, input *models.TaskTemplateInput) (*models.TaskTemplate, error) {
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Update(ctx, id, input)
+}
+
+func (s *templateService) DeleteTemplate(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *templateService) Instantiate(ctx context.Context, id, ownerID string, input *models.TaskTemplateInstantiate) (*models.Task, error) {
+	template, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	task := template.NewTask(input)
+	task.OwnerID = ownerID
+	return s.tasks.CreateTask(ctx, task)
+}
+
+// TemplateInTenant returns a tenant lookup finding templates by ID; the repository only
+// finds the templates of the tenant ctx is scoped to
+func TemplateInTenant(repo repository.TemplateRepository) auth.TenantLookup {
+	return func(ctx context.Context, templateID string) error {
+		_, err := repo.GetByID(ctx, templateID)
+		return err
+	}
+}
+
+// TemplateOwnership allows only a template's owner to modify it
+func TemplateOwnership(repo repository.TemplateRepository) auth.OwnershipChecker {
+	return func(ctx context.Context, userID, templateID string) (bool, error) {
+		template, err := repo.GetByID(ctx, templateID)
+		if err != nil {
+			return false, err
+		}
+		return template.OwnerID == userID, nil
+	}
+}
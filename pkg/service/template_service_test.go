@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockTemplateRepository is a mock implementation of TemplateRepository
+type MockTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockTemplateRepository) Create(ctx context.Context, template *models.TaskTemplate) (*models.TaskTemplate, error) {
+	args := m.Called(ctx, template)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaskTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) GetByID(ctx context.Context, id string) (*models.TaskTemplate, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaskTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) List(ctx context.Context) ([]*models.TaskTemplate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.TaskTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) Update(ctx context.Context, id string, template *models.TaskTemplateInput) (*models.TaskTemplate, error) {
+	args := m.Called(ctx, id, template)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TaskTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCreateTemplate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		input         *models.TaskTemplateInput
+		wantStatus    models.TaskStatus
+		wantChecklist []string
+		wantErr       bool
+	}{
+		{
+			name:          "defaults status and drops blank items",
+			input:         &models.TaskTemplateInput{Title: " Release ", Checklist: []string{"Tag", " ", " Publish notes "}},
+			wantStatus:    models.StatusPending,
+			wantChecklist: []string{"Tag", "Publish notes"},
+		},
+		{
+			name:          "keeps status",
+			input:         &models.TaskTemplateInput{Title: "Release", Status: models.StatusInProgress},
+			wantStatus:    models.StatusInProgress,
+			wantChecklist: []string{},
+		},
+		{
+			name:    "missing title",
+			input:   &models.TaskTemplateInput{Title: "  "},
+			wantErr: true,
+		},
+		{
+			name:    "invalid status",
+			input:   &models.TaskTemplateInput{Title: "Release", Status: "done"},
+			wantErr: true,
+		},
+		{
+			name:    "too many items",
+			input:   &models.TaskTemplateInput{Title: "Release", Checklist: tooManyChecklistItems()},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTemplateRepository)
+			svc := NewTemplateService(repo, NewTaskService(new(MockTaskRepository)))
+
+			repo.On("Create", ctx, mock.AnythingOfType("*models.TaskTemplate")).
+				Return(&models.TaskTemplate{ID: "template-1"}, nil).Maybe()
+
+			_, err := svc.CreateTemplate(ctx, "user-1", tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			created := repo.Calls[0].Arguments.Get(1).(*models.TaskTemplate)
+			assert.Equal(t, "Release", created.Title)
+			assert.Equal(t, tt.wantStatus, created.Status)
+			assert.Equal(t, tt.wantChecklist, created.Checklist)
+			assert.Equal(t, "user-1", created.OwnerID)
+		})
+	}
+}
+
+func tooManyChecklistItems() []string {
+	items := make([]string, models.MaxChecklistItems+1)
+	for i := range items {
+		items[i] = "item"
+	}
+	return items
+}
+
+func TestInstantiateTemplate(t *testing.T) {
+	ctx := context.Background()
+	due := time.Now().Add(24 * time.Hour)
+
+	template := &models.TaskTemplate{
+		ID:          "template-1",
+		Title:       "Release",
+		Description: "Ship the release.",
+		Status:      models.StatusInProgress,
+		Checklist:   []string{"Tag", "Publish notes"},
+		OwnerID:     "user-1",
+	}
+
+	repo := new(MockTemplateRepository)
+	tasks := new(MockTaskRepository)
+	svc := NewTemplateService(repo, NewTaskService(tasks))
+
+	repo.On("GetByID", ctx, "template-1").Return(template, nil)
+	tasks.On("Create", ctx, mock.AnythingOfType("*models.TaskCreate")).
+		Return(&models.Task{ID: "task-1"}, nil)
+
+	task, err := svc.Instantiate(ctx, "template-1", "user-2", &models.TaskTemplateInstantiate{
+		DueDate:    due,
+		AssigneeID: "user-3",
+		ProjectKey: "ops",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "task-1", task.ID)
+
+	created := tasks.Calls[0].Arguments.Get(1).(*models.TaskCreate)
+	assert.Equal(t, "Release", created.Title)
+	assert.Equal(t, "Ship the release.\n\n- [ ] Tag\n- [ ] Publish notes", created.Description)
+	assert.Equal(t, models.StatusInProgress, created.Status)
+	assert.Equal(t, models.PriorityMedium, created.Priority)
+	assert.Equal(t, "user-2", created.OwnerID)
+	assert.Equal(t, "user-3", created.AssigneeID)
+	assert.Equal(t, "OPS", created.ProjectKey)
+	assert.True(t, due.Equal(created.DueDate))
+}
+
+func TestInstantiateTemplate_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		templateErr error
+		input       *models.TaskTemplateInstantiate
+	}{
+		{
+			name:        "template not found",
+			templateErr: errors.New("template not found"),
+			input:       &models.TaskTemplateInstantiate{DueDate: time.Now().Add(time.Hour)},
+		},
+		{
+			name:  "missing due date",
+			input: &models.TaskTemplateInstantiate{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTemplateRepository)
+			tasks := new(MockTaskRepository)
+			svc := NewTemplateService(repo, NewTaskService(tasks))
+
+			if tt.templateErr != nil {
+				repo.On("GetByID", ctx, "template-1").Return(nil, tt.templateErr)
+			} else {
+				repo.On("GetByID", ctx, "template-1").Return(&models.TaskTemplate{Title: "Release", Status: models.StatusPending}, nil)
+			}
+
+			_, err := svc.Instantiate(ctx, "template-1", "user-1", tt.input)
+
+			assert.Error(t, err)
+			tasks.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTemplateOwnership(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockTemplateRepository)
+	repo.On("GetByID", ctx, "template-1").Return(&models.TaskTemplate{ID: "template-1", OwnerID: "user-1"}, nil)
+
+	check := TemplateOwnership(repo)
+
+	allowed, err := check(ctx, "user-1", "template-1")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = check(ctx, "user-2", "template-1")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+// TimeTrackingService records the time spent on tasks
+type TimeTrackingService interface {
+	// GetTime returns the task's estimate, logged time and time entries
+	GetTime(ctx context.Context, taskID string) (*models.TimeSummary, error)
+	LogTime(ctx context.Context, taskID string, entry *models.TimeEntryLog) (*models.TimeEntry, error)
+	// StartTimer starts the user's timer; a user runs at most one timer per task
+	StartTimer(ctx context.Context, taskID string, timer *models.TimerStart) (*models.TimeEntry, error)
+	StopTimer(ctx context.Context, taskID, userID string) (*models.TimeEntry, error)
+	DeleteEntry(ctx context.Context, taskID, entryID string) error
+}
+
+// ErrTimerRunning is returned when starting a timer while the user's timer on the task runs
+var ErrTimerRunning = errors.New("a timer is already running on this task")
+
+type timeTrackingService struct {
+	entries repository.TimeEntryRepository
+	tasks   repository.TaskRepository
+}
+
+// NewTimeTrackingService creates a new time tracking service
+func NewTimeTrackingService(entries repository.TimeEntryRepository, tasks repository.TaskRepository) TimeTrackingService {
+	return &timeTrackingService{entries: entries, tasks: tasks}
+}
+
+func (s *timeTrackingService) GetTime(ctx context.Context, taskID string) (*models.TimeSummary, error) {
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.entries.List(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewTimeSummary(task, entries), nil
+}
+
+func (s *timeTrackingService) LogTime(ctx context.Context, taskID string, entry *models.TimeEntryLog) (*models.TimeEntry, error) {
+	if entry.UserID == "" {
+		return nil, errors.New("time entries need a user")
+	}
+	if err := entry.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.getTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.entries.Log(ctx, taskID, entry)
+}
+
+func (s *timeTrackingService) StartTimer(ctx context.Context, taskID string, timer *models.TimerStart) (*models.TimeEntry, error) {
+	if timer.UserID == "" {
+		return nil, errors.New("timers need a user")
+	}
+	if err := timer.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.getTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	// The repository's unique index still guards against concurrent starts
+	entries, err := s.entries.List(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsRunning() && entry.UserID == timer.UserID {
+			return nil, ErrTimerRunning
+		}
+	}
+
+	return s.entries.Start(ctx, taskID, timer)
+}
+
+func (s *timeTrackingService) StopTimer(ctx context.Context, taskID, userID string) (*models.TimeEntry, error) {
+	if _, err := s.getTask(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	return s.entries.Stop(ctx, taskID, userID)
+}
+
+func (s *timeTrackingService) DeleteEntry(ctx context.Context, taskID, entryID string) error {
+	if _, err := s.getTask(ctx, taskID); err != nil {
+		return err
+	}
+
+	return s.entries.Delete(ctx, taskID, entryID)
+}
+
+// getTask returns the task if the caller can see it
+func (s *timeTrackingService) getTask(ctx context.Context, taskID string) (*models.Task, error) {
+	task, err := s.tasks.GetByID(ctx, taskID)
+	return visibleTask(ctx, task, err)
+}
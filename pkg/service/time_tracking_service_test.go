@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockTimeEntryRepository is a mock implementation of TimeEntryRepository
+type MockTimeEntryRepository struct {
+	mock.Mock
+}
+
+func (m *MockTimeEntryRepository) List(ctx context.Context, taskID string) ([]*models.TimeEntry, error) {
+	args := m.Called(ctx, taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) Log(ctx context.Context, taskID string, log *models.TimeEntryLog) (*models.TimeEntry, error) {
+	args := m.Called(ctx, taskID, log)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) Start(ctx context.Context, taskID string, timer *models.TimerStart) (*models.TimeEntry, error) {
+	args := m.Called(ctx, taskID, timer)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) Stop(ctx context.Context, taskID, userID string) (*models.TimeEntry, error) {
+	args := m.Called(ctx, taskID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTimeEntryRepository) Delete(ctx context.Context, taskID, entryID string) error {
+	args := m.Called(ctx, taskID, entryID)
+	return args.Error(0)
+}
+
+func TestGetTime(t *testing.T) {
+	ctx := context.Background()
+	estimate := 90
+	task := &models.Task{ID: "task-1", Status: models.StatusPending, EstimatedMinutes: &estimate, LoggedMinutes: 120}
+	entries := []*models.TimeEntry{{ID: "entry-1", TaskID: "task-1", Minutes: 120}}
+
+	taskRepo := new(MockTaskRepository)
+	entryRepo := new(MockTimeEntryRepository)
+	taskRepo.On("GetByID", ctx, "task-1").Return(task, nil)
+	entryRepo.On("List", ctx, "task-1").Return(entries, nil)
+
+	summary, err := NewTimeTrackingService(entryRepo, taskRepo).GetTime(ctx, "task-1")
+	require.NoError(t, err)
+	assert.Equal(t, 90, *summary.EstimatedMinutes)
+	assert.Equal(t, 120, summary.LoggedMinutes)
+	assert.Equal(t, 0, *summary.RemainingMinutes)
+	assert.Len(t, summary.Entries, 1)
+}
+
+func TestLogTime(t *testing.T) {
+	ctx := context.Background()
+	task := &models.Task{ID: "task-1", Status: models.StatusPending}
+
+	t.Run("defaults the start", func(t *testing.T) {
+		taskRepo := new(MockTaskRepository)
+		entryRepo := new(MockTimeEntryRepository)
+		taskRepo.On("GetByID", ctx, "task-1").Return(task, nil)
+		entryRepo.On("Log", ctx, "task-1", mock.MatchedBy(func(log *models.TimeEntryLog) bool {
+			return log.StartedAt != nil && time.Since(*log.StartedAt) >= 30*time.Minute
+		})).Return(&models.TimeEntry{ID: "entry-1", Minutes: 30}, nil)
+
+		entry, err := NewTimeTrackingService(entryRepo, taskRepo).LogTime(ctx, "task-1",
+			&models.TimeEntryLog{Minutes: 30, UserID: "user-1"})
+		require.NoError(t, err)
+		assert.Equal(t, 30, entry.Minutes)
+	})
+
+	t.Run("rejects more than a day", func(t *testing.T) {
+		entryRepo := new(MockTimeEntryRepository)
+		_, err := NewTimeTrackingService(entryRepo, new(MockTaskRepository)).LogTime(ctx, "task-1",
+			&models.TimeEntryLog{Minutes: models.MaxTimeEntryMinutes + 1, UserID: "user-1"})
+		assert.Error(t, err)
+		entryRepo.AssertNotCalled(t, "Log", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestStartTimer(t *testing.T) {
+	ctx := context.Background()
+	task := &models.Task{ID: "task-1", Status: models.StatusPending}
+	ended := time.Now()
+
+	tests := []struct {
+		name    string
+		entries []*models.TimeEntry
+		wantErr error
+	}{
+		{name: "no timers", entries: []*models.TimeEntry{}},
+		{name: "another user's timer", entries: []*models.TimeEntry{{UserID: "user-2"}}},
+		{name: "stopped timer", entries: []*models.TimeEntry{{UserID: "user-1", EndedAt: &ended}}},
+		{name: "running timer", entries: []*models.TimeEntry{{UserID: "user-1"}}, wantErr: ErrTimerRunning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := new(MockTaskRepository)
+			entryRepo := new(MockTimeEntryRepository)
+			taskRepo.On("GetByID", ctx, "task-1").Return(task, nil)
+			entryRepo.On("List", ctx, "task-1").Return(tt.entries, nil)
+			entryRepo.On("Start", ctx, "task-1", mock.AnythingOfType("*models.TimerStart")).
+				Return(&models.TimeEntry{ID: "entry-1", UserID: "user-1"}, nil)
+
+			_, err := NewTimeTrackingService(entryRepo, taskRepo).StartTimer(ctx, "task-1",
+				&models.TimerStart{UserID: "user-1"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				entryRepo.AssertNotCalled(t, "Start", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
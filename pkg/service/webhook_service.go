@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+	"sample/task-management-system/pkg/tenant"
+	"sample/task-management-system/pkg/webhook"
+)
+
+// webhookEvents are the event types webhooks can subscribe to
+var webhookEvents = map[string]bool{
+	string(events.TaskCreated): true,
+	string(events.TaskUpdated): true,
+	string(events.TaskDeleted): true,
+	string(events.TaskStale):   true,
+	string(events.TaskOverdue): true,
+	// Sent to the webhooks of integrators still using a deprecation, see DeprecationService
+	models.DeprecationNoticeEvent: true,
+}
+
+// maxWebhookDeliveries is how many deliveries run at once, so slow receivers can't pile
+// up connections
+const maxWebhookDeliveries = 8
+
+// WebhookService manages webhook subscriptions and delivers task events to them
+type WebhookService interface {
+	// CreateWebhook subscribes a URL to events. The signing secret is returned once.
+	CreateWebhook(ctx context.Context, createdBy string, input *models.WebhookCreate) (*models.Webhook, string, error)
+	ListWebhooks(ctx context.Context) ([]*models.Webhook, error)
+	GetWebhook(ctx context.Context, id string) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	// SendTest sends a signed sample event to a webhook and returns the delivery log
+	SendTest(ctx context.Context, id string) (*models.WebhookDelivery, error)
+	// ListDeliveries lists the logged delivery attempts of a webhook, newest first
+	ListDeliveries(ctx context.Context, id string, page, limit int) ([]*models.WebhookDelivery, int, error)
+	// Redeliver sends the event of a logged delivery to its webhook again
+	Redeliver(ctx context.Context, id, deliveryID string) (*models.WebhookDelivery, error)
+	// ReplayEvent sends an archived event to hook again and returns the delivery log
+	ReplayEvent(ctx context.Context, hook *models.Webhook, event *models.ArchivedEvent) *models.WebhookDelivery
+	// Dispatch delivers a task event to its subscribers in the background; it is
+	// subscribed to the event bus
+	Dispatch(ctx context.Context, event events.Event) error
+	// Notify delivers an event meant for one user to the webhooks they subscribed to its
+	// type, returning how many received it
+	Notify(ctx context.Context, userID string, event *models.WebhookEvent) (int, error)
+	// Run deletes delivery logs past their retention every interval until ctx is done
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type webhookService struct {
+	repo   repository.WebhookRepository
+	sender webhook.Sender
+	slots  chan struct{}
+	now    func() time.Time
+}
+
+// NewWebhookService creates a webhook service delivering events through sender
+func NewWebhookService(repo repository.WebhookRepository, sender webhook.Sender) WebhookService {
+	return &webhookService{
+		repo:   repo,
+		sender: sender,
+		slots:  make(chan struct{}, maxWebhookDeliveries),
+		now:    time.Now,
+	}
+}
+
+func (s *webhookService) CreateWebhook(ctx context.Context, createdBy string, input *models.WebhookCreate) (*models.Webhook, string, error) {
+	if err := input.Validate(); err != nil {
+		return nil, "", err
+	}
+	for _, eventType := range input.Events {
+		if !webhookEvents[eventType] {
+			return nil, "", fmt.Errorf("unknown event %q", eventType)
+		}
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	hook, err := s.repo.Create(ctx, &models.Webhook{
+		URL:       input.URL,
+		Events:    input.Events,
+		Secret:    secret,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return hook, secret, nil
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context) ([]*models.Webhook, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, id string) (*models.Webhook, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *webhookService) SendTest(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	hook, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	return s.deliver(ctx, hook, &models.WebhookEvent{
+		ID:         uuid.New().String(),
+		Type:       models.WebhookTestEvent,
+		OccurredAt: now,
+		Data: &models.WebhookTaskData{
+			TaskID: "00000000-0000-0000-0000-000000000000",
+			Task: &models.Task{
+				ID:          "00000000-0000-0000-0000-000000000000",
+				Key:         models.DefaultProjectKey + "-1",
+				Title:       "Sample task",
+				Description: "Sent to check the webhook receives and verifies deliveries",
+				Status:      models.StatusPending,
+				Priority:    models.PriorityMedium,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			},
+		},
+	}, false), nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, id string, page, limit int) ([]*models.WebhookDelivery, int, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.repo.ListDeliveries(ctx, id, page, limit)
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, id, deliveryID string) (*models.WebhookDelivery, error) {
+	hook, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.GetDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	// The event keeps its ID, so receivers that handled it already can drop it
+	return s.deliver(ctx, hook, delivery.Event, true), nil
+}
+
+func (s *webhookService) Dispatch(ctx context.Context, event events.Event) error {
+	if !webhookEvents[string(event.Type)] {
+		return nil
+	}
+	// Only the task's tenant subscribes to its events
+	ctx = tenant.WithID(ctx, event.OrgID)
+	hooks, err := s.repo.ListForEvent(ctx, string(event.Type))
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+
+	payload := &models.WebhookEvent{
+		ID:         event.ID,
+		Type:       string(event.Type),
+		OccurredAt: event.OccurredAt,
+		Data:       &models.WebhookTaskData{TaskID: event.TaskID, Task: event.Task},
+	}
+	// Deliveries run in the background so a slow receiver doesn't hold up other subscribers
+	// of the event bus
+	for _, hook := range hooks {
+		s.slots <- struct{}{}
+		go func(hook *models.Webhook) {
+			defer func() { <-s.slots }()
+			s.deliver(ctx, hook, payload, false)
+		}(hook)
+	}
+	return nil
+}
+
+func (s *webhookService) Notify(ctx context.Context, userID string, event *models.WebhookEvent) (int, error) {
+	hooks, err := s.repo.ListForEvent(ctx, event.Type)
+	if err != nil {
+		return 0, err
+	}
+
+	received := 0
+	for _, hook := range hooks {
+		if hook.CreatedBy != userID {
+			continue
+		}
+		if s.deliver(ctx, hook, event, false).Success {
+			received++
+		}
+	}
+	return received, nil
+}
+
+func (s *webhookService) ReplayEvent(ctx context.Context, hook *models.Webhook, event *models.ArchivedEvent) *models.WebhookDelivery {
+	return s.deliver(ctx, hook, &models.WebhookEvent{
+		ID:         event.EventID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       &models.WebhookTaskData{TaskID: event.TaskID, Task: event.Task},
+	}, true)
+}
+
+// deliver sends event to hook and logs the attempt. A failed delivery is reported in the
+// returned log rather than as an error.
+func (s *webhookService) deliver(ctx context.Context, hook *models.Webhook, event *models.WebhookEvent, redelivery bool) *models.WebhookDelivery {
+	delivery, err := s.sender.Send(ctx, hook, event)
+	if err != nil {
+		log.Printf("Webhook %s failed to receive %s %s: %v", hook.ID, event.Type, event.ID, err)
+	}
+	delivery.Redelivery = redelivery
+	if err := s.repo.RecordDelivery(ctx, delivery); err != nil {
+		log.Printf("Failed to log delivery of %s to webhook %s: %v", event.ID, hook.ID, err)
+	}
+	return delivery
+}
+
+func (s *webhookService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.repo.DeleteDeliveriesBefore(ctx, s.now().Add(-models.WebhookDeliveryRetention))
+			if err != nil {
+				log.Printf("Failed to delete old webhook deliveries: %v", err)
+			} else if deleted > 0 {
+				log.Printf("Deleted %d webhook deliveries past their retention", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/events"
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/tenant"
+)
+
+// MockWebhookRepository is a mock implementation of WebhookRepository
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, hook *models.Webhook) (*models.Webhook, error) {
+	args := m.Called(ctx, hook)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetByID(ctx context.Context, id string) (*models.Webhook, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) List(ctx context.Context) ([]*models.Webhook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListForEvent(ctx context.Context, eventType string) ([]*models.Webhook, error) {
+	args := m.Called(ctx, eventType)
+	return args.Get(0).([]*models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) RecordDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, webhookID string, page, limit int) ([]*models.WebhookDelivery, int, error) {
+	args := m.Called(ctx, webhookID, page, limit)
+	return args.Get(0).([]*models.WebhookDelivery), args.Int(1), args.Error(2)
+}
+
+func (m *MockWebhookRepository) GetDelivery(ctx context.Context, webhookID, id string) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockWebhookSender is a mock implementation of webhook.Sender
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, hook *models.Webhook, event *models.WebhookEvent) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, hook, event)
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func TestCreateWebhook(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockWebhookRepository)
+	svc := NewWebhookService(repo, new(MockWebhookSender))
+
+	var stored *models.Webhook
+	repo.On("Create", ctx, mock.MatchedBy(func(hook *models.Webhook) bool {
+		stored = hook
+		return hook.URL == "https://example.com/hooks" && hook.CreatedBy == "admin-1"
+	})).Return(&models.Webhook{ID: "hook-1"}, nil).Once()
+
+	hook, secret, err := svc.CreateWebhook(ctx, "admin-1", &models.WebhookCreate{
+		URL: "https://example.com/hooks", Events: []string{"task.created", "task.deleted"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hook-1", hook.ID)
+	assert.NotEmpty(t, secret)
+	assert.Equal(t, secret, stored.Secret)
+
+	for _, input := range []*models.WebhookCreate{
+		{URL: "ftp://example.com", Events: []string{"task.created"}},
+		{URL: "/hooks", Events: []string{"task.created"}},
+		{URL: "https://example.com/hooks"},
+		{URL: "https://example.com/hooks", Events: []string{"task.archived"}},
+	} {
+		_, _, err := svc.CreateWebhook(ctx, "admin-1", input)
+		assert.Error(t, err, input)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestSendTestWebhook(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockWebhookRepository)
+	sender := new(MockWebhookSender)
+	svc := NewWebhookService(repo, sender)
+
+	hook := &models.Webhook{ID: "hook-1", URL: "https://example.com/hooks", Secret: "whsec_test"}
+	repo.On("GetByID", ctx, "hook-1").Return(hook, nil).Once()
+	sender.On("Send", ctx, hook, mock.MatchedBy(func(event *models.WebhookEvent) bool {
+		return event.Type == models.WebhookTestEvent && event.ID != ""
+	})).Return(&models.WebhookDelivery{WebhookID: "hook-1", Success: true, StatusCode: 200}, nil).Once()
+	repo.On("RecordDelivery", ctx, mock.MatchedBy(func(delivery *models.WebhookDelivery) bool {
+		return delivery.StatusCode == 200 && !delivery.Redelivery
+	})).Return(nil).Once()
+
+	delivery, err := svc.SendTest(ctx, "hook-1")
+	require.NoError(t, err)
+	assert.Equal(t, 200, delivery.StatusCode)
+	sender.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
+func TestRedeliverWebhook(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockWebhookRepository)
+	sender := new(MockWebhookSender)
+	svc := NewWebhookService(repo, sender)
+
+	hook := &models.Webhook{ID: "hook-1", URL: "https://example.com/hooks", Secret: "whsec_test"}
+	event := &models.WebhookEvent{ID: "event-1", Type: "task.created"}
+	repo.On("GetByID", ctx, "hook-1").Return(hook, nil).Once()
+	repo.On("GetDelivery", ctx, "hook-1", "delivery-1").
+		Return(&models.WebhookDelivery{ID: "delivery-1", EventID: "event-1", StatusCode: 500, Event: event}, nil).Once()
+	// The same event is sent again, and the attempt logged as a redelivery
+	sender.On("Send", ctx, hook, event).
+		Return(&models.WebhookDelivery{WebhookID: "hook-1", EventID: "event-1", StatusCode: 503, Error: "webhook receiver returned status 503"}, assert.AnError).Once()
+	repo.On("RecordDelivery", ctx, mock.MatchedBy(func(delivery *models.WebhookDelivery) bool {
+		return delivery.EventID == "event-1" && delivery.Redelivery
+	})).Return(nil).Once()
+
+	delivery, err := svc.Redeliver(ctx, "hook-1", "delivery-1")
+	require.NoError(t, err, "failed deliveries are reported in the log")
+	assert.Equal(t, 503, delivery.StatusCode)
+	assert.True(t, delivery.Redelivery)
+	repo.AssertExpectations(t)
+	sender.AssertExpectations(t)
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockWebhookRepository)
+	svc := NewWebhookService(repo, new(MockWebhookSender))
+
+	repo.On("GetByID", ctx, "hook-1").Return(&models.Webhook{ID: "hook-1"}, nil).Once()
+	repo.On("ListDeliveries", ctx, "hook-1", 1, 20).Return([]*models.WebhookDelivery{{ID: "delivery-1"}}, 1, nil).Once()
+	repo.On("GetByID", ctx, "missing").Return(nil, errors.New("webhook not found")).Once()
+
+	deliveries, total, err := svc.ListDeliveries(ctx, "hook-1", 0, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, deliveries, 1)
+
+	_, _, err = svc.ListDeliveries(ctx, "missing", 1, 20)
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestDispatchWebhooks(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockWebhookRepository)
+	sender := new(MockWebhookSender)
+	svc := NewWebhookService(repo, sender)
+	// Only the subscriptions of the task's tenant are listed
+	scoped := tenant.WithID(ctx, "acme")
+
+	hooks := []*models.Webhook{{ID: "hook-1"}, {ID: "hook-2"}}
+	repo.On("ListForEvent", scoped, "task.updated").Return(hooks, nil).Once()
+	delivered := make(chan string, 2)
+	recorded := make(chan struct{}, 2)
+	isUpdate := mock.MatchedBy(func(event *models.WebhookEvent) bool {
+		data := event.Data.(*models.WebhookTaskData)
+		return event.Type == "task.updated" && data.TaskID == "task-1" && data.Task.Title == "Ship it"
+	})
+	for _, hook := range hooks {
+		sender.On("Send", scoped, hook, isUpdate).Run(func(args mock.Arguments) {
+			delivered <- args.Get(1).(*models.Webhook).ID
+		}).Return(&models.WebhookDelivery{WebhookID: hook.ID, StatusCode: 500}, assert.AnError).Once()
+	}
+	repo.On("RecordDelivery", scoped, mock.Anything).Run(func(args mock.Arguments) {
+		recorded <- struct{}{}
+	}).Return(nil).Twice()
+
+	require.NoError(t, svc.Dispatch(ctx, events.Event{
+		Type: events.TaskUpdated, TaskID: "task-1", Task: &models.Task{ID: "task-1", Title: "Ship it"}, OrgID: "acme", OccurredAt: time.Now(),
+	}))
+	var ids []string
+	for i := 0; i < 2; i++ {
+		select {
+		case id := <-delivered:
+			ids = append(ids, id)
+			<-recorded
+		case <-time.After(time.Second):
+			t.Fatal("the event was not delivered to every subscriber")
+		}
+	}
+	assert.ElementsMatch(t, []string{"hook-1", "hook-2"}, ids)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sample/task-management-system/pkg/models"
+	"sample/task-management-system/pkg/repository"
+)
+
+const (
+	// workloadTolerance is the load difference, in minutes, considered balanced
+	workloadTolerance = 60
+	// maxWorkloadSuggestions bounds the reassignments proposed at once
+	maxWorkloadSuggestions = 50
+)
+
+// WorkloadService summarizes how open work is spread across a project's assignees
+type WorkloadService interface {
+	// GetWorkload returns the open work per assignee of a project. With suggest, it also
+	// proposes reassignments that even out the load; candidates are assignees without
+	// open tasks who may receive some.
+	GetWorkload(ctx context.Context, projectKey string, suggest bool, candidates []string) (*models.Workload, error)
+}
+
+type workloadService struct {
+	repo repository.WorkloadRepository
+}
+
+// NewWorkloadService creates a new workload service
+func NewWorkloadService(repo repository.WorkloadRepository) WorkloadService {
+	return &workloadService{repo: repo}
+}
+
+func (s *workloadService) GetWorkload(ctx context.Context, projectKey string, suggest bool, candidates []string) (*models.Workload, error) {
+	projectKey, err := models.NormalizeProjectKey(projectKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) > models.MaxWorkloadCandidates {
+		return nil, fmt.Errorf("at most %d candidates may be given", models.MaxWorkloadCandidates)
+	}
+
+	aggregates, err := s.repo.AssigneeWorkloads(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	workload := &models.Workload{
+		ProjectKey: projectKey,
+		Assignees:  []*models.AssigneeWorkload{},
+		Unassigned: &models.AssigneeWorkload{},
+	}
+	byAssignee := make(map[string]*models.AssigneeWorkload)
+	for _, aggregate := range aggregates {
+		aggregate.ComputeLoad()
+		if aggregate.AssigneeID == "" {
+			workload.Unassigned = aggregate
+			continue
+		}
+		workload.Assignees = append(workload.Assignees, aggregate)
+		byAssignee[aggregate.AssigneeID] = aggregate
+	}
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" || byAssignee[candidate] != nil {
+			continue
+		}
+		idle := &models.AssigneeWorkload{AssigneeID: candidate}
+		workload.Assignees = append(workload.Assignees, idle)
+		byAssignee[candidate] = idle
+	}
+	sortWorkloads(workload.Assignees)
+
+	if suggest {
+		tasks, err := s.repo.ReassignableTasks(ctx, projectKey)
+		if err != nil {
+			return nil, err
+		}
+		workload.Suggestions = suggestReassignments(workload.Assignees, tasks)
+	}
+
+	return workload, nil
+}
+
+// sortWorkloads orders workloads from the most to the least loaded
+func sortWorkloads(workloads []*models.AssigneeWorkload) {
+	sort.SliceStable(workloads, func(i, j int) bool {
+		if workloads[i].LoadMinutes != workloads[j].LoadMinutes {
+			return workloads[i].LoadMinutes > workloads[j].LoadMinutes
+		}
+		return workloads[i].AssigneeID < workloads[j].AssigneeID
+	})
+}
+
+// taskLoad returns the remaining work of a task, as counted in AssigneeWorkload.LoadMinutes
+func taskLoad(task *models.Task) int {
+	if task.EstimatedMinutes == nil {
+		return models.UnestimatedTaskMinutes
+	}
+	if remaining := *task.EstimatedMinutes - task.LoggedMinutes; remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// suggestReassignments greedily moves tasks from the most to the least loaded assignee.
+// Each move picks the task that best halves the gap between the two, and only moves
+// that narrow it are made, so the suggestions always end up more balanced. Each task
+// is moved at most once.
+func suggestReassignments(workloads []*models.AssigneeWorkload, tasks []*models.Task) []*models.Reassignment {
+	suggestions := []*models.Reassignment{}
+	if len(workloads) < 2 {
+		return suggestions
+	}
+
+	loads := make(map[string]int, len(workloads))
+	for _, workload := range workloads {
+		loads[workload.AssigneeID] = workload.LoadMinutes
+	}
+	movable := make(map[string][]*models.Task)
+	for _, task := range tasks {
+		if _, ok := loads[task.AssigneeID]; ok && taskLoad(task) > 0 {
+			movable[task.AssigneeID] = append(movable[task.AssigneeID], task)
+		}
+	}
+
+	// Assignees ordered by current load, ties broken by ID to keep suggestions stable
+	order := make([]string, 0, len(workloads))
+	for _, workload := range workloads {
+		order = append(order, workload.AssigneeID)
+	}
+	byLoad := func() {
+		sort.SliceStable(order, func(i, j int) bool {
+			if loads[order[i]] != loads[order[j]] {
+				return loads[order[i]] > loads[order[j]]
+			}
+			return order[i] < order[j]
+		})
+	}
+
+	for len(suggestions) < maxWorkloadSuggestions {
+		byLoad()
+		receiver := order[len(order)-1]
+
+		moved := false
+		for _, donor := range order[:len(order)-1] {
+			gap := loads[donor] - loads[receiver]
+			if gap <= workloadTolerance {
+				break
+			}
+
+			best := -1
+			for i, task := range movable[donor] {
+				load := taskLoad(task)
+				if load >= gap {
+					continue
+				}
+				if best == -1 || abs(gap-2*load) < abs(gap-2*taskLoad(movable[donor][best])) {
+					best = i
+				}
+			}
+			if best == -1 {
+				continue
+			}
+
+			task := movable[donor][best]
+			movable[donor] = append(movable[donor][:best], movable[donor][best+1:]...)
+			load := taskLoad(task)
+			loads[donor] -= load
+			loads[receiver] += load
+			suggestions = append(suggestions, &models.Reassignment{
+				TaskID:      task.ID,
+				TaskKey:     task.Key,
+				Title:       task.Title,
+				From:        donor,
+				To:          receiver,
+				LoadMinutes: load,
+			})
+			moved = true
+			break
+		}
+		if !moved {
+			return suggestions
+		}
+	}
+	return suggestions
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// MockWorkloadRepository is a mock implementation of WorkloadRepository
+type MockWorkloadRepository struct {
+	mock.Mock
+}
+
+func (m *MockWorkloadRepository) AssigneeWorkloads(ctx context.Context, projectKey string) ([]*models.AssigneeWorkload, error) {
+	args := m.Called(ctx, projectKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AssigneeWorkload), args.Error(1)
+}
+
+func (m *MockWorkloadRepository) ReassignableTasks(ctx context.Context, projectKey string) ([]*models.Task, error) {
+	args := m.Called(ctx, projectKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Task), args.Error(1)
+}
+
+func estimatedTask(id, assigneeID string, minutes int) *models.Task {
+	return &models.Task{ID: id, Key: "PROJ-" + id, AssigneeID: assigneeID, EstimatedMinutes: &minutes}
+}
+
+func TestGetWorkload_Summary(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockWorkloadRepository)
+	svc := NewWorkloadService(repo)
+
+	repo.On("AssigneeWorkloads", ctx, "PROJ").Return([]*models.AssigneeWorkload{
+		{AssigneeID: "", OpenTasks: 2, UnestimatedTasks: 2},
+		{AssigneeID: "alice", OpenTasks: 1, EstimatedMinutes: 30},
+		{AssigneeID: "bob", OpenTasks: 3, EstimatedMinutes: 120, UnestimatedTasks: 1},
+	}, nil)
+
+	workload, err := svc.GetWorkload(ctx, "proj", false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "PROJ", workload.ProjectKey)
+	require.Len(t, workload.Assignees, 2)
+	assert.Equal(t, "bob", workload.Assignees[0].AssigneeID)
+	assert.Equal(t, 180, workload.Assignees[0].LoadMinutes)
+	assert.Equal(t, 30, workload.Assignees[1].LoadMinutes)
+	assert.Equal(t, 120, workload.Unassigned.LoadMinutes)
+	assert.Nil(t, workload.Suggestions)
+	repo.AssertNotCalled(t, "ReassignableTasks", mock.Anything, mock.Anything)
+}
+
+func TestGetWorkload_Suggestions(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockWorkloadRepository)
+	svc := NewWorkloadService(repo)
+
+	repo.On("AssigneeWorkloads", ctx, "PROJ").Return([]*models.AssigneeWorkload{
+		{AssigneeID: "alice", OpenTasks: 4, EstimatedMinutes: 600},
+		{AssigneeID: "bob", OpenTasks: 1, EstimatedMinutes: 120},
+	}, nil)
+	// alice's in-progress work isn't reassignable, only her pending tasks
+	repo.On("ReassignableTasks", ctx, "PROJ").Return([]*models.Task{
+		estimatedTask("1", "alice", 300),
+		estimatedTask("2", "alice", 90),
+		estimatedTask("3", "alice", 60),
+		estimatedTask("4", "bob", 120),
+	}, nil)
+
+	workload, err := svc.GetWorkload(ctx, "PROJ", true, []string{"carol", "bob", " "})
+	require.NoError(t, err)
+	require.Len(t, workload.Assignees, 3)
+	assert.Equal(t, "carol", workload.Assignees[2].AssigneeID)
+
+	// 600/120/0: alice's 300 halves the gap to carol, then her 90 evens her out with bob.
+	// Tasks already moved to carol stay put, so the remaining gap of 90 is accepted.
+	require.Len(t, workload.Suggestions, 2)
+	assert.Equal(t, &models.Reassignment{TaskID: "1", TaskKey: "PROJ-1", From: "alice", To: "carol", LoadMinutes: 300}, workload.Suggestions[0])
+	assert.Equal(t, &models.Reassignment{TaskID: "2", TaskKey: "PROJ-2", From: "alice", To: "bob", LoadMinutes: 90}, workload.Suggestions[1])
+}
+
+func TestGetWorkload_InvalidProject(t *testing.T) {
+	svc := NewWorkloadService(new(MockWorkloadRepository))
+
+	_, err := svc.GetWorkload(context.Background(), "-", false, nil)
+	assert.Error(t, err)
+}
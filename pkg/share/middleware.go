@@ -0,0 +1,55 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"sample/task-management-system/pkg/models"
+)
+
+type contextKey string
+
+const shareContextKey contextKey = "share"
+
+// Resolver looks up the active share behind a share token
+type Resolver interface {
+	ResolveShare(ctx context.Context, token string) (*models.TaskShare, error)
+}
+
+// Middleware validates the share token in the "token" route variable and stores the
+// share in the request context. Requests are rejected unless the share grants permission.
+func Middleware(resolver Resolver, permission models.SharePermission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Share URLs are bearer credentials; keep them out of shared caches
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Set("Referrer-Policy", "no-referrer")
+
+			share, err := resolver.ResolveShare(r.Context(), mux.Vars(r)["token"])
+			switch {
+			case errors.Is(err, ErrShareExpired):
+				http.Error(w, err.Error(), http.StatusGone)
+				return
+			case err != nil:
+				http.Error(w, ErrInvalidShareToken.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			if !share.Allows(permission) {
+				http.Error(w, ErrShareForbidden.Error(), http.StatusForbidden)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), shareContextKey, share)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// FromContext returns the share validated by Middleware
+func FromContext(ctx context.Context) (*models.TaskShare, bool) {
+	share, ok := ctx.Value(shareContextKey).(*models.TaskShare)
+	return share, ok
+}
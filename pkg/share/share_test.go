@@ -0,0 +1,115 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+func TestSigner(t *testing.T) {
+	signer := NewSigner([]byte("test-secret"), "test-issuer")
+	shared := &models.TaskShare{
+		ID:         "share-1",
+		TaskID:     "task-1",
+		Permission: models.SharePermissionRead,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		token, err := signer.Sign(shared)
+		require.NoError(t, err)
+
+		claims, err := signer.Verify(token)
+		require.NoError(t, err)
+		assert.Equal(t, "share-1", claims.ID)
+		assert.Equal(t, "task-1", claims.Subject)
+		assert.Equal(t, models.SharePermissionRead, claims.Permission)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired := *shared
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		token, err := signer.Sign(&expired)
+		require.NoError(t, err)
+
+		_, err = signer.Verify(token)
+		assert.ErrorIs(t, err, ErrShareExpired)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		token, err := NewSigner([]byte("other-secret"), "test-issuer").Sign(shared)
+		require.NoError(t, err)
+
+		_, err = signer.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalidShareToken)
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		_, err := signer.Verify("not-a-token")
+		assert.ErrorIs(t, err, ErrInvalidShareToken)
+	})
+}
+
+type resolverFunc func(ctx context.Context, token string) (*models.TaskShare, error)
+
+func (f resolverFunc) ResolveShare(ctx context.Context, token string) (*models.TaskShare, error) {
+	return f(ctx, token)
+}
+
+func TestMiddleware(t *testing.T) {
+	shares := map[string]*models.TaskShare{
+		"read":    {ID: "s1", TaskID: "task-1", Permission: models.SharePermissionRead},
+		"comment": {ID: "s2", TaskID: "task-1", Permission: models.SharePermissionComment},
+	}
+	resolver := resolverFunc(func(ctx context.Context, token string) (*models.TaskShare, error) {
+		if token == "revoked" {
+			return nil, ErrShareExpired
+		}
+		if s, ok := shares[token]; ok {
+			return s, nil
+		}
+		return nil, errors.New("share not found")
+	})
+
+	tests := []struct {
+		name       string
+		permission models.SharePermission
+		token      string
+		want       int
+	}{
+		{"read share can read", models.SharePermissionRead, "read", http.StatusOK},
+		{"comment share can read", models.SharePermissionRead, "comment", http.StatusOK},
+		{"read share cannot comment", models.SharePermissionComment, "read", http.StatusForbidden},
+		{"comment share can comment", models.SharePermissionComment, "comment", http.StatusOK},
+		{"revoked or expired", models.SharePermissionRead, "revoked", http.StatusGone},
+		{"unknown token", models.SharePermissionRead, "unknown", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.Use(Middleware(resolver, tt.permission))
+			router.HandleFunc("/shared/{token}", func(w http.ResponseWriter, r *http.Request) {
+				s, ok := FromContext(r.Context())
+				require.True(t, ok)
+				assert.Equal(t, shares[tt.token], s)
+				w.WriteHeader(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/shared/"+tt.token, nil))
+			assert.Equal(t, tt.want, rec.Code)
+			assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		})
+	}
+}
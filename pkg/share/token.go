@@ -0,0 +1,74 @@
+package share
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"sample/task-management-system/pkg/models"
+)
+
+// Audience is the "aud" claim of share tokens; it keeps them from being accepted as access tokens
+const Audience = "task-share"
+
+// Common errors
+var (
+	ErrInvalidShareToken = errors.New("invalid share token")
+	ErrShareExpired      = errors.New("share link has expired or been revoked")
+	ErrShareForbidden    = errors.New("share link does not allow this action")
+)
+
+// Claims are the claims carried by a share token
+type Claims struct {
+	jwt.RegisteredClaims
+	Permission models.SharePermission `json:"perm"`
+}
+
+// Signer issues and verifies share tokens
+type Signer struct {
+	secret []byte
+	issuer string
+}
+
+// NewSigner creates a share token signer
+func NewSigner(secret []byte, issuer string) *Signer {
+	return &Signer{secret: secret, issuer: issuer}
+}
+
+// Sign creates a token for the share; the token expires with the share
+func (s *Signer) Sign(share *models.TaskShare) (string, error) {
+	claims := &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        share.ID,
+			Subject:   share.TaskID,
+			Issuer:    s.issuer,
+			Audience:  jwt.ClaimStrings{Audience},
+			IssuedAt:  jwt.NewNumericDate(share.CreatedAt),
+			ExpiresAt: jwt.NewNumericDate(share.ExpiresAt),
+		},
+		Permission: share.Permission,
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
+
+// Verify checks a share token's signature and expiry and returns its claims
+func (s *Signer) Verify(token string) (*Claims, error) {
+	claims := &Claims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(Audience),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+	)
+
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrShareExpired
+	}
+	if err != nil || !parsed.Valid {
+		return nil, ErrInvalidShareToken
+	}
+
+	return claims, nil
+}
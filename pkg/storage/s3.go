@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// unsignedPayload lets uploads stream without hashing the body first; requests go over TLS
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+type s3Store struct {
+	bucket   string
+	region   string
+	endpoint string // path-style endpoint for S3-compatible stores; empty uses AWS
+	creds    aws.CredentialsProvider
+	client   *http.Client
+	signer   *v4.Signer
+}
+
+// NewS3Store creates an object store for an S3 bucket, signing requests with the
+// credentials of cfg. endpoint may point at an S3-compatible store such as MinIO,
+// which is then addressed path-style.
+func NewS3Store(cfg aws.Config, bucket, endpoint string) ObjectStore {
+	return &s3Store{
+		bucket:   bucket,
+		region:   cfg.Region,
+		endpoint: strings.TrimRight(endpoint, "/"),
+		creds:    cfg.Credentials,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		signer: v4.NewSigner(func(o *v4.SignerOptions) {
+			// S3 expects object keys escaped once, not twice
+			o.DisableURIPathEscaping = true
+		}),
+	}
+}
+
+// objectURL returns the URL of the object under key
+func (s *s3Store) objectURL(key string) string {
+	escaped := (&url.URL{Path: key}).EscapedPath()
+	if s.endpoint != "" {
+		return s.endpoint + "/" + s.bucket + "/" + escaped
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, escaped)
+}
+
+func (s *s3Store) do(ctx context.Context, req *http.Request) error {
+	creds, err := s.creds.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %v", err)
+	}
+
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	if err := s.signer.SignHTTP(ctx, creds, req, unsignedPayload, "s3", s.region, time.Now()); err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, msg)
+	}
+	return nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Length", strconv.FormatInt(size, 10))
+	req.Header.Set("Content-Type", contentType)
+
+	return s.do(ctx, req)
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.do(ctx, req)
+}
+
+func (s *s3Store) PresignGet(ctx context.Context, key, fileName string, expires time.Duration) (string, error) {
+	creds, err := s.creds.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve AWS credentials: %v", err)
+	}
+
+	u, err := url.Parse(s.objectURL(key))
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": fileName})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+	query.Set("response-content-disposition", disposition)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	signed, _, err := s.signer.PresignHTTP(ctx, creds, req, unsignedPayload, "s3", s.region, time.Now())
+	return signed, err
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3Store_ObjectURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		key      string
+		want     string
+	}{
+		{
+			name: "virtual-hosted AWS bucket",
+			key:  "tasks/task-1/attachments/att-1",
+			want: "https://attachments.s3.us-west-2.amazonaws.com/tasks/task-1/attachments/att-1",
+		},
+		{
+			name:     "path-style compatible endpoint",
+			endpoint: "http://localhost:9000/",
+			key:      "tasks/task-1/attachments/att-1",
+			want:     "http://localhost:9000/attachments/tasks/task-1/attachments/att-1",
+		},
+		{
+			name: "escapes key",
+			key:  "tasks/a b",
+			want: "https://attachments.s3.us-west-2.amazonaws.com/tasks/a%20b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewS3Store(aws.Config{Region: "us-west-2"}, "attachments", tt.endpoint).(*s3Store)
+			assert.Equal(t, tt.want, store.objectURL(tt.key))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ObjectStore stores file content by key
+type ObjectStore interface {
+	// Put uploads size bytes from body under key
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+
+	// Delete removes the object under key; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+
+	// PresignGet returns a URL that downloads the object as fileName until it expires
+	PresignGet(ctx context.Context, key, fileName string, expires time.Duration) (string, error)
+}
@@ -0,0 +1,29 @@
+// Package tenant carries the organization a request acts for, so repositories can confine
+// every query to it.
+package tenant
+
+import "context"
+
+type contextKey struct{}
+
+// WithID scopes ctx to the tenant with the ID. The empty ID is the default tenant of
+// users that belong to no organization, and holds the tasks created before tenancy.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant ctx is scoped to. ok is false for unscoped contexts,
+// which only background jobs and token-addressed routes such as share links use.
+func FromContext(ctx context.Context) (id string, ok bool) {
+	id, ok = ctx.Value(contextKey{}).(string)
+	return id, ok
+}
+
+// Arg returns the query argument for a tenant predicate: the tenant's ID, or nil for
+// unscoped contexts
+func Arg(ctx context.Context) interface{} {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+	return nil
+}
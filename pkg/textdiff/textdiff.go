@@ -0,0 +1,110 @@
+// Package textdiff computes line-based differences between two texts.
+package textdiff
+
+import "strings"
+
+// Op says what happened to a line
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Line is one line of a diff
+type Line struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxCells bounds the work of a diff. Texts whose differing parts exceed it are
+// reported as a whole replacement instead of a minimal diff.
+const maxCells = 1 << 20
+
+// Lines returns the lines to delete from and insert into before to get after, in
+// order, along with the unchanged lines between them
+func Lines(before, after string) []Line {
+	a, b := split(before), split(after)
+
+	// Unchanged lines at either end need no comparison
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]Line, 0, len(a)+len(b))
+	for _, text := range a[:prefix] {
+		diff = append(diff, Line{Op: Equal, Text: text})
+	}
+	diff = append(diff, middle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		diff = append(diff, Line{Op: Equal, Text: text})
+	}
+	return diff
+}
+
+// middle diffs the lines between the common prefix and suffix using their longest
+// common subsequence
+func middle(a, b []string) []Line {
+	if len(a)*len(b) > maxCells {
+		return replace(a, b)
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []Line
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, Line{Op: Equal, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, Line{Op: Delete, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, Line{Op: Insert, Text: b[j]})
+			j++
+		}
+	}
+	return append(diff, replace(a[i:], b[j:])...)
+}
+
+// replace deletes every line of a and inserts every line of b
+func replace(a, b []string) []Line {
+	diff := make([]Line, 0, len(a)+len(b))
+	for _, text := range a {
+		diff = append(diff, Line{Op: Delete, Text: text})
+	}
+	for _, text := range b {
+		diff = append(diff, Line{Op: Insert, Text: text})
+	}
+	return diff
+}
+
+// split breaks text into lines; empty text has none
+func split(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
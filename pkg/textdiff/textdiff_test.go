@@ -0,0 +1,66 @@
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []Line
+	}{
+		{
+			name:   "no change",
+			before: "a\nb",
+			after:  "a\nb",
+			want:   []Line{{Equal, "a"}, {Equal, "b"}},
+		},
+		{
+			name:   "from empty",
+			before: "",
+			after:  "a\nb",
+			want:   []Line{{Insert, "a"}, {Insert, "b"}},
+		},
+		{
+			name:   "to empty",
+			before: "a",
+			after:  "",
+			want:   []Line{{Delete, "a"}},
+		},
+		{
+			name:   "changed line in the middle",
+			before: "a\nb\nc",
+			after:  "a\nB\nc",
+			want:   []Line{{Equal, "a"}, {Delete, "b"}, {Insert, "B"}, {Equal, "c"}},
+		},
+		{
+			name:   "moved line",
+			before: "a\nb\nc\nd",
+			after:  "b\nc\na\nd",
+			want:   []Line{{Delete, "a"}, {Equal, "b"}, {Equal, "c"}, {Insert, "a"}, {Equal, "d"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Lines(tt.before, tt.after))
+		})
+	}
+}
+
+func TestLines_LargeTextsAreReplaced(t *testing.T) {
+	before := strings.Repeat("old\n", 2000) + "end"
+	after := strings.Repeat("new\n", 2000) + "end"
+
+	diff := Lines(before, after)
+
+	assert.Len(t, diff, 4001)
+	assert.Equal(t, Line{Delete, "old"}, diff[0])
+	assert.Equal(t, Line{Insert, "new"}, diff[2000])
+	assert.Equal(t, Line{Equal, "end"}, diff[4000])
+}
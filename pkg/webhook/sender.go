@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+
+	"sample/task-management-system/pkg/models"
+)
+
+// Sender delivers webhook events to subscriptions
+type Sender interface {
+	// Send posts event to hook, signed with its secret, and returns the log of the attempt.
+	// The log is returned even when the delivery fails; responses outside 2xx fail it.
+	Send(ctx context.Context, hook *models.Webhook, event *models.WebhookEvent) (*models.WebhookDelivery, error)
+}
+
+// ErrForbiddenAddress is returned for deliveries to receivers that resolve to a private,
+// loopback or link-local address
+var ErrForbiddenAddress = errors.New("webhook receivers must have a public address")
+
+// nonPublicPrefixes are the ranges that are neither private, loopback nor link-local but
+// still don't reach the public internet
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("198.18.0.0/15"), // benchmarking
+}
+
+// HTTPSender posts events as JSON
+type HTTPSender struct {
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewHTTPSender creates a sender giving up on receivers after timeout. Webhook URLs are
+// chosen by users, so it only connects to public addresses: the address is checked as it
+// is dialed, after DNS resolution, so names resolving to internal services such as the
+// cloud metadata endpoint are refused too.
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialPublicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would connect to the receiver on our behalf, unchecked
+	transport.Proxy = nil
+
+	return &HTTPSender{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// A redirect would post the event somewhere the subscription did not name
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+func (s *HTTPSender) Send(ctx context.Context, hook *models.Webhook, event *models.WebhookEvent) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{
+		WebhookID:   hook.ID,
+		EventID:     event.ID,
+		EventType:   event.Type,
+		DeliveredAt: s.now(),
+		Event:       event,
+	}
+	err := s.post(ctx, hook, event, delivery)
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	delivery.Success = err == nil
+	return delivery, err
+}
+
+// post sends the request, recording the receiver's answer and how long it took on delivery
+func (s *HTTPSender) post(ctx context.Context, hook *models.Webhook, event *models.WebhookEvent, delivery *models.WebhookDelivery) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-management-system-webhooks/1")
+	req.Header.Set(EventHeader, event.Type)
+	req.Header.Set(DeliveryHeader, event.ID)
+	// Signed as it is sent, so redeliveries carry a fresh timestamp
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, delivery.DeliveredAt, body))
+
+	started := time.Now()
+	resp, err := s.httpClient.Do(req)
+	delivery.DurationMs = time.Since(started).Milliseconds()
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	delivery.StatusCode = resp.StatusCode
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, models.MaxWebhookResponseBytes))
+	// Kept as text, which can't hold NUL bytes or invalid UTF-8
+	delivery.Response = strings.ReplaceAll(strings.ToValidUTF8(string(snippet), "�"), "\x00", "")
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook receiver returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// dialPublicOnly refuses connections to addresses webhooks may not reach
+func dialPublicOnly(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddr(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+	return nil
+}
+
+// isPublicAddr reports whether ip is a global unicast address outside private networks,
+// so not loopback, link-local (169.254.0.0/16, fe80::/10), multicast or unspecified
+func isPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
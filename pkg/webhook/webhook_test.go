@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/models"
+)
+
+func TestVerify(t *testing.T) {
+	sentAt := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	body := []byte(`{"type":"task.created"}`)
+	header := Sign("whsec_test", sentAt, body)
+
+	assert.NoError(t, Verify("whsec_test", header, body, sentAt.Add(time.Minute), DefaultTolerance))
+	// A signature from a rotated-out secret alongside the current one still verifies
+	assert.NoError(t, Verify("whsec_test", header+",v1=deadbeef", body, sentAt, DefaultTolerance))
+
+	assert.ErrorIs(t, Verify("whsec_other", header, body, sentAt, DefaultTolerance), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_test", header, []byte(`{"type":"task.deleted"}`), sentAt, DefaultTolerance), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_test", "v1=abc", body, sentAt, DefaultTolerance), ErrInvalidSignature)
+	// Replayed after the tolerance
+	assert.ErrorIs(t, Verify("whsec_test", header, body, sentAt.Add(DefaultTolerance+time.Second), DefaultTolerance), ErrSignatureExpired)
+	assert.ErrorIs(t, Verify("whsec_test", header, body, sentAt.Add(-DefaultTolerance-time.Second), DefaultTolerance), ErrSignatureExpired)
+}
+
+func TestHTTPSender_SignsDeliveries(t *testing.T) {
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("queued\x00\xff"))
+	}))
+	defer server.Close()
+
+	sender := newLoopbackSender()
+	sender.now = func() time.Time { return now }
+	hook := &models.Webhook{ID: "hook-1", URL: server.URL, Secret: "whsec_test"}
+	delivery, err := sender.Send(context.Background(), hook, &models.WebhookEvent{ID: "event-1", Type: "task.created", OccurredAt: now})
+	require.NoError(t, err)
+	assert.True(t, delivery.Success)
+	assert.Equal(t, http.StatusAccepted, delivery.StatusCode)
+	assert.Equal(t, "queued\uFFFD", delivery.Response, "kept as valid text")
+	assert.Equal(t, "hook-1", delivery.WebhookID)
+	assert.Equal(t, "event-1", delivery.EventID)
+	assert.Equal(t, now, delivery.DeliveredAt)
+
+	assert.Equal(t, "task.created", received.Header.Get(EventHeader))
+	assert.Equal(t, "event-1", received.Header.Get(DeliveryHeader))
+	assert.NoError(t, Verify("whsec_test", received.Header.Get(SignatureHeader), body, now, DefaultTolerance))
+}
+
+func TestHTTPSender_FailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer server.Close()
+
+	delivery, err := newLoopbackSender().Send(context.Background(),
+		&models.Webhook{URL: server.URL, Secret: "whsec_test"}, &models.WebhookEvent{ID: "event-1"})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusFound, delivery.StatusCode, "redirects are not followed")
+	assert.False(t, delivery.Success)
+	assert.Equal(t, err.Error(), delivery.Error)
+}
+
+func TestHTTPSender_RefusesNonPublicAddresses(t *testing.T) {
+	received := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer server.Close()
+
+	delivery, err := NewHTTPSender(time.Second).Send(context.Background(),
+		&models.Webhook{URL: server.URL, Secret: "whsec_test"}, &models.WebhookEvent{ID: "event-1"})
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.False(t, delivery.Success)
+	assert.False(t, received)
+
+	for address, public := range map[string]bool{
+		"93.184.216.34:443":       true,
+		"[2606:4700::1111]:443":   true,
+		"127.0.0.1:80":            false,
+		"10.1.2.3:80":             false,
+		"172.16.0.1:80":           false,
+		"192.168.1.1:80":          false,
+		"169.254.169.254:80":      false,
+		"100.64.0.1:80":           false,
+		"0.0.0.0:80":              false,
+		"[::1]:80":                false,
+		"[fe80::1]:80":            false,
+		"[fd00::1]:80":            false,
+		"[::ffff:127.0.0.1]:80":   false,
+		"[::ffff:169.254.1.1]:80": false,
+	} {
+		err := dialPublicOnly("tcp", address, nil)
+		if public {
+			assert.NoError(t, err, address)
+		} else {
+			assert.ErrorIs(t, err, ErrForbiddenAddress, address)
+		}
+	}
+}
+
+// newLoopbackSender returns a sender that may reach the loopback test servers
+func newLoopbackSender() *HTTPSender {
+	sender := NewHTTPSender(time.Second)
+	sender.httpClient.Transport = http.DefaultTransport
+	return sender
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	require.NoError(t, err)
+	second, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(first, "whsec_"))
+	assert.NotEqual(t, first, second)
+}
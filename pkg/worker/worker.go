@@ -0,0 +1,212 @@
+// Package worker supervises the background loops of a process, such as job workers and
+// schedulers: it restarts loops that stop, samples job queue depths and reports both for
+// health checks and metrics.
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"sample/task-management-system/pkg/health"
+	"sample/task-management-system/pkg/metrics"
+)
+
+// restartDelay is how long a loop that stopped is left down before it is started again
+const restartDelay = 5 * time.Second
+
+// Loop is a background loop, running until ctx is done
+type Loop func(ctx context.Context)
+
+// DepthFunc counts the jobs waiting in a queue
+type DepthFunc func(ctx context.Context) (int, error)
+
+// LoopStatus is the state of one loop
+type LoopStatus struct {
+	Name     string `json:"name"`
+	Running  bool   `json:"running"`
+	Restarts int    `json:"restarts"`
+	// LastFailure is why the loop last stopped, if it ever did
+	LastFailure string `json:"last_failure,omitempty"`
+}
+
+// Status is the state of a worker's loops and queues
+type Status struct {
+	Status    health.Status  `json:"status"`
+	Timestamp time.Time      `json:"timestamp"`
+	Loops     []LoopStatus   `json:"loops"`
+	Queues    map[string]int `json:"queues"` // jobs waiting, as last sampled
+}
+
+// Worker runs a process's background loops
+type Worker struct {
+	mu       sync.Mutex
+	loops    map[string]Loop
+	status   map[string]*LoopStatus
+	queues   map[string]DepthFunc
+	depths   map[string]int
+	interval time.Duration
+}
+
+// New creates a worker sampling queue depths every interval
+func New(interval time.Duration) *Worker {
+	return &Worker{
+		loops:    make(map[string]Loop),
+		status:   make(map[string]*LoopStatus),
+		queues:   make(map[string]DepthFunc),
+		depths:   make(map[string]int),
+		interval: interval,
+	}
+}
+
+// Add registers a loop; loops are started by Run
+func (w *Worker) Add(name string, loop Loop) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.loops[name] = loop
+	w.status[name] = &LoopStatus{Name: name}
+}
+
+// AddQueue registers a job queue whose depth is sampled and recorded as a metric
+func (w *Worker) AddQueue(name string, depth DepthFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.queues[name] = depth
+}
+
+// Run starts every loop and samples queue depths until ctx is done, then waits for the
+// loops to return. A loop that returns or panics before then is started again.
+func (w *Worker) Run(ctx context.Context) {
+	w.mu.Lock()
+	loops := make(map[string]Loop, len(w.loops))
+	for name, loop := range w.loops {
+		loops[name] = loop
+	}
+	w.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for name, loop := range loops {
+		wg.Add(1)
+		go func(name string, loop Loop) {
+			defer wg.Done()
+			w.supervise(ctx, name, loop)
+		}(name, loop)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	w.sample(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+			w.sample(ctx)
+		}
+	}
+}
+
+// supervise runs a loop until ctx is done, restarting it whenever it stops early
+func (w *Worker) supervise(ctx context.Context, name string, loop Loop) {
+	for {
+		w.update(name, func(status *LoopStatus) { status.Running = true })
+		err := runLoop(ctx, loop)
+		w.update(name, func(status *LoopStatus) { status.Running = false })
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("Worker loop %s stopped, restarting in %s: %v", name, restartDelay, err)
+		w.update(name, func(status *LoopStatus) {
+			status.Restarts++
+			status.LastFailure = err.Error()
+		})
+		metrics.RecordWorkerRestart(name)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(restartDelay):
+		}
+	}
+}
+
+// runLoop runs a loop, turning a panic into an error
+func runLoop(ctx context.Context, loop Loop) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v\n%s", recovered, debug.Stack())
+		}
+	}()
+	loop(ctx)
+	return errors.New("loop returned")
+}
+
+// update changes a loop's status
+func (w *Worker) update(name string, change func(status *LoopStatus)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	change(w.status[name])
+}
+
+// sample records the depth of every queue
+func (w *Worker) sample(ctx context.Context) {
+	w.mu.Lock()
+	queues := make(map[string]DepthFunc, len(w.queues))
+	for name, depth := range w.queues {
+		queues[name] = depth
+	}
+	w.mu.Unlock()
+
+	for name, depthFunc := range queues {
+		depth, err := depthFunc(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to count jobs in queue %s: %v", name, err)
+			}
+			continue
+		}
+		w.mu.Lock()
+		w.depths[name] = depth
+		w.mu.Unlock()
+		metrics.RecordQueueDepth(name, depth)
+	}
+}
+
+// Status reports the worker's loops, by name, and queue depths. The worker is down when
+// any loop is not running.
+func (w *Worker) Status() Status {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := Status{Status: health.StatusUp, Timestamp: time.Now(), Loops: []LoopStatus{}, Queues: make(map[string]int)}
+	for _, loop := range w.status {
+		status.Loops = append(status.Loops, *loop)
+		if !loop.Running {
+			status.Status = health.StatusDown
+		}
+	}
+	sort.Slice(status.Loops, func(i, j int) bool { return status.Loops[i].Name < status.Loops[j].Name })
+	for name, depth := range w.depths {
+		status.Queues[name] = depth
+	}
+	return status
+}
+
+// ServeHTTP serves the worker's status as a health check, with 503 while it is down
+func (w *Worker) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	status := w.Status()
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Cache-Control", "no-store")
+	if status.Status != health.StatusUp {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(rw).Encode(status)
+}
@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sample/task-management-system/pkg/health"
+)
+
+func TestRun_ReportsLoopsAndQueues(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := New(time.Hour)
+	w.Add("exports", func(ctx context.Context) { <-ctx.Done() })
+	w.AddQueue("exports", func(ctx context.Context) (int, error) { return 3, nil })
+
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		return w.Status().Status == health.StatusUp && w.Status().Queues["exports"] == 3
+	}, time.Second, 10*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	w.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"queues":{"exports":3}`)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+}
+
+func TestRun_RestartsLoopsThatPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs atomic.Int32
+	w := New(time.Hour)
+	w.Add("digests", func(ctx context.Context) {
+		if runs.Add(1) == 1 {
+			panic("boom")
+		}
+		<-ctx.Done()
+	})
+	go w.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		status := w.Status()
+		return len(status.Loops) == 1 && status.Loops[0].Restarts == 1
+	}, time.Second, 10*time.Millisecond)
+
+	status := w.Status()
+	assert.Equal(t, health.StatusDown, status.Status, "the loop waits before it is restarted")
+	assert.Contains(t, status.Loops[0].LastFailure, "panic: boom")
+
+	rec := httptest.NewRecorder()
+	w.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
@@ -2,7 +2,9 @@
 
 # Run migrations
 export PGPASSWORD=$DB_PASSWORD
-psql -h $DB_HOST -U $DB_USER -d $DB_NAME -f /app/internal/database/migrations/001_create_tasks_table.sql
+for migration in /app/internal/database/migrations/*.sql; do
+    psql -h $DB_HOST -U $DB_USER -d $DB_NAME -f "$migration"
+done
 
 # Start the application
 exec ./main 
\ No newline at end of file